@@ -0,0 +1,19 @@
+package bootstrap
+
+import "dbfartifactapi/pkg/metrics"
+
+// cacheEntries reports the size of each cache populated by LoadData.
+var cacheEntries = metrics.NewGaugeFunc("dbfartifactapi_bootstrap_cache_entries",
+	"Entries held in each bootstrap reference data cache.", "cache", func() map[string]float64 {
+		return map[string]float64{
+			"db_actor":             float64(len(DBActorAll)),
+			"db_type":              float64(len(DBTypeAll)),
+			"db_object":            float64(len(DBObjectAllMap)),
+			"db_policy_default":    float64(len(DBPolicyDefaultsAllMap)),
+			"db_group_list_policy": float64(len(DBGroupListPoliciesAllMap)),
+		}
+	})
+
+func init() {
+	metrics.MustRegister(cacheEntries)
+}
@@ -0,0 +1,66 @@
+// Command fakeagent runs the dbfsqlexecute agent simulator as a standalone agent gateway.
+//
+// Point the API at it with:
+//
+//	AGENT_TRANSPORT=http AGENT_GATEWAY_URL=http://localhost:9090 ./dbfartifactapi
+//
+// Use the same -query-dir as the API's DBFWEB_TEMP_DIR so download jobs can read query files.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"dbfartifactapi/pkg/fakeagent"
+	"dbfartifactapi/pkg/logger"
+)
+
+func main() {
+	addr := flag.String("addr", ":9090", "listen address for the agent gateway API")
+	token := flag.String("token", "", "bearer token required from clients (empty disables auth)")
+	queryDir := flag.String("query-dir", "/etc/saids/idsconfig/tmp/dbfweb", "directory the API writes query files to (DBFWEB_TEMP_DIR)")
+	resultsDir := flag.String("results-dir", "", "directory for job results files (default: <tmp>/fakeagent)")
+	jobDelay := flag.Duration("job-delay", 2*time.Second, "time background jobs stay running before they execute")
+	logPath := flag.String("log", "", "log file path (empty logs startup messages only)")
+	flag.Parse()
+
+	if *logPath != "" {
+		logger.Init(*logPath)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fa, err := fakeagent.New(ctx, fakeagent.Options{
+		QueryDir:   *queryDir,
+		ResultsDir: *resultsDir,
+		JobDelay:   *jobDelay,
+		Token:      *token,
+	})
+	if err != nil {
+		log.Fatalf("Fake agent error: %v", err)
+	}
+
+	server := &http.Server{Addr: *addr, Handler: fa.Handler()}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("Fake agent gateway listening on %s (embedded MySQL on port %d)", *addr, fa.Port())
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Fatalf("Fake agent server error: %v", err)
+	}
+
+	if err := fa.Close(); err != nil {
+		log.Printf("Fake agent close error: %v", err)
+	}
+}
@@ -7,8 +7,6 @@ import (
 	"strconv"
 	"strings"
 	"time"
-
-	"github.com/joho/godotenv"
 )
 
 // AppConfig holds application configuration loaded from environment variables and .env file.
@@ -48,6 +46,14 @@ type AppConfig struct {
 	AgentMaxRetries       int           // Maximum retry attempts for agent commands
 	AgentRetryBaseDelay   time.Duration // Base delay between agent command retries
 
+	// Agent transport config - "exec" forks dbfAgentAPI per call, "http" talks to an agent gateway
+	AgentTransport    string
+	AgentGatewayURL   string // Base URL of agent gateway (http transport only)
+	AgentGatewayToken string // Bearer token sent to agent gateway (optional)
+
+	// Agent concurrency config - commands running at once on one agent (Endpoint.ClientID), 0 for unlimited
+	AgentMaxConcurrentPerClient int
+
 	// Database and User Exclusion Lists - configurable system objects to skip during sync
 	SystemDatabases []string // System databases that should not be managed
 	SystemUsers     []string // System database users that should not be managed
@@ -62,15 +68,96 @@ type AppConfig struct {
 	// Performance-critical: MySQL privilege query logging generates large files during bulk analysis
 	// Enable only in development for debugging MySQL privilege template execution issues
 	EnableMySQLPrivilegeQueryLogging bool
+
+	// Job persistence config - stores job state in the jobs table so in-flight jobs survive restarts
+	JobPersistenceEnabled bool
+
+	// Job monitor config - how often running jobs are polled on their agent
+	JobMonitorInterval time.Duration
+
+	// Job event WebSocket config - browser origins allowed to open /api/jobs/:job-id/ws
+	JobEventsAllowedOrigins []string // Entries "scheme://host[:port]"
+	JobEventsAllowNoOrigin  bool     // Accept clients sending no Origin header (non-browser tools)
+
+	// Privilege snapshot config - keeps raw grant rows and derived policies of each discovery run for drift reports
+	PrivilegeSnapshotEnabled bool
+
+	// Policy revision config - records every policy change per connection so it can be diffed and rolled back
+	PolicyRevisionsEnabled bool
+
+	// Change approval config - group changes touching HIGH/CRITICAL policies or many actors wait for approval by other users
+	ApprovalEnabled           bool
+	ApprovalActorThreshold    int // Changes adding or removing more actors than this need approval (0 disables the actor rule)
+	ApprovalsRequired         int // Approvals for HIGH-risk policies and large actor changes
+	ApprovalsRequiredCritical int // Approvals for CRITICAL-risk policies
+
+	// Validity enforcement config - grants and revokes group memberships as their valid_from/valid_until pass
+	ValidityEnforcementEnabled  bool
+	ValidityEnforcementInterval time.Duration // Time between passes over the memberships
+
+	// API authentication config - static API keys and/or JWT bearer tokens verified against a local JWKS
+	AuthEnabled      bool
+	AuthAPIKeys      []string // Entries "name:role:key", role one of viewer, operator, policy-admin, super-admin
+	AuthJWKSFile     string   // Path to JWKS document of the OIDC provider (empty disables JWT auth)
+	AuthJWTIssuer    string   // Required iss claim (optional)
+	AuthJWTAudience  string   // Required aud claim (optional)
+	AuthJWTRoleClaim string   // Claim holding role names, dotted paths allowed (e.g. realm_access.roles)
+
+	// Agent callback auth config - agents report job completion with a shared secret instead of an API key
+	AgentCallbackSecrets []string // Entries "client_id:secret", one per agent (Endpoint.ClientID)
+
+	// Audit trail config - hash-chained record of every mutating API call and agent command
+	AuditEnabled bool
+
+	// Credential encryption config - envelope encryption of cntmgt and dbactormgt passwords at rest
+	CredentialKeys            []string // Entries "id:base64key" with 32-byte AES keys
+	CredentialActiveKey       string   // Key ID new values are encrypted with (optional with a single key)
+	CredentialKeyFile         string   // JSON keyfile {"active": id, "keys": {id: base64key}}, used instead of CredentialKeys
+	CredentialRewrapOnStartup bool     // Encrypt plain text and re-wrap values under retired keys on startup
+
+	// Secret provider config - connections with secret_ref resolve their password at agent-call time
+	SecretFileDir   string // Base directory for file: references (empty disables the file provider)
+	SecretEnvPrefix string // Prefix prepended to env: references
+	VaultAddr       string // Vault-compatible API address for vault: references (empty disables the provider)
+	VaultToken      string // Token sent as X-Vault-Token
+
+	// Metrics config - Prometheus text exposition served outside the authenticated /api group
+	MetricsEnabled bool
+	MetricsPath    string
+
+	// Tracing config - OpenTelemetry spans exported over OTLP/HTTP
+	TracingEnabled      bool
+	TracingOTLPEndpoint string  // Collector host:port
+	TracingOTLPInsecure bool    // Plain HTTP to the collector
+	TracingServiceName  string  // service.name resource attribute
+	TracingSampleRatio  float64 // Fraction of new traces recorded (0-1)
+
+	// Health check config - dependency probes behind /readyz and /api/diagnostics
+	HealthCheckTimeout time.Duration // Timeout of each dependency probe
+
+	// Shutdown config - how long a stop signal waits for HTTP requests and job completion callbacks
+	ShutdownTimeout time.Duration
+
+	// Idempotency config - POST requests with an Idempotency-Key header replay their first successful response
+	IdempotencyEnabled     bool
+	IdempotencyTTL         time.Duration // How long a stored response is replayed
+	IdempotencyLockTimeout time.Duration // After this an unfinished request no longer blocks its key
+
+	// Rate limit config - token buckets per API client across /api and per client on selected routes
+	RateLimitEnabled     bool
+	RateLimitClientRPS   float64  // Sustained requests per second per client (0 disables the client limit)
+	RateLimitClientBurst int      // Requests a client may send at once
+	RateLimitRoutes      []string // Per-route rules "[METHOD ]/route=rate:burst"
 }
 
 // Cfg is the global application configuration instance.
+// Fields applied by Reload must be read through Current or the accessor functions below.
 var Cfg AppConfig
 
 // LoadConfig loads and validates application configuration from .env file and environment variables.
 func LoadConfig() error {
 	// Nếu có file .env thì load
-	err := godotenv.Load()
+	err := loadDotEnv()
 	if err != nil {
 		// Use standard log here since logger is not initialized yet
 		log.Printf("[WARN] .env file not found or cannot be loaded: %v", err)
@@ -78,78 +165,185 @@ func LoadConfig() error {
 		log.Printf("[INFO] .env file loaded successfully")
 	}
 
-	Cfg.DBHost = getEnv("DB_HOST", "127.0.0.1")
-	Cfg.DBUser = getEnv("DB_USER", "root")
-	Cfg.DBPass = getEnv("DB_PASS", "")
-	Cfg.DBName = getEnv("DB_NAME", "test_db")
+	loadFromEnv(&Cfg)
+
+	log.Printf("[INFO] Config loaded - DB: %s@%s:%d/%s, LogLevel: %s",
+		Cfg.DBUser, Cfg.DBHost, Cfg.DBPort, Cfg.DBName, Cfg.LogLevel)
+	log.Printf("[INFO] VeloArtifact config - ExecTimeout: %v, DownloadTimeout: %v, MaxRetries: %d, BaseDelay: %v",
+		Cfg.VeloExecutionTimeout, Cfg.VeloDownloadTimeout, Cfg.VeloMaxRetries, Cfg.VeloRetryBaseDelay)
+	log.Printf("[INFO] Agent API config - Transport: %s, Path: %s, Gateway: %s, ExecTimeout: %v, MaxRetries: %d, BaseDelay: %v, MaxConcurrentPerClient: %d",
+		Cfg.AgentTransport, Cfg.AgentAPIPath, Cfg.AgentGatewayURL, Cfg.AgentExecutionTimeout, Cfg.AgentMaxRetries, Cfg.AgentRetryBaseDelay,
+		Cfg.AgentMaxConcurrentPerClient)
+	log.Printf("[INFO] System exclusion lists - Databases: %v, Users: %v",
+		Cfg.SystemDatabases, Cfg.SystemUsers)
+	log.Printf("[INFO] Job config - Persistence: %v, MonitorInterval: %v, EventOrigins: %v, EventsNoOrigin: %v",
+		Cfg.JobPersistenceEnabled, Cfg.JobMonitorInterval, Cfg.JobEventsAllowedOrigins, Cfg.JobEventsAllowNoOrigin)
+	log.Printf("[INFO] Auth config - Enabled: %v, APIKeys: %d, JWKS: %s, Issuer: %s, Audience: %s, AgentSecrets: %d",
+		Cfg.AuthEnabled, len(Cfg.AuthAPIKeys), Cfg.AuthJWKSFile, Cfg.AuthJWTIssuer, Cfg.AuthJWTAudience, len(Cfg.AgentCallbackSecrets))
+	log.Printf("[INFO] Credential config - Keys: %d, ActiveKey: %s, KeyFile: %s, SecretDir: %s, Vault: %s",
+		len(Cfg.CredentialKeys), Cfg.CredentialActiveKey, Cfg.CredentialKeyFile, Cfg.SecretFileDir, Cfg.VaultAddr)
+	log.Printf("[INFO] Metrics config - Enabled: %v, Path: %s", Cfg.MetricsEnabled, Cfg.MetricsPath)
+	log.Printf("[INFO] Tracing config - Enabled: %v, Endpoint: %s, Insecure: %v, SampleRatio: %g",
+		Cfg.TracingEnabled, Cfg.TracingOTLPEndpoint, Cfg.TracingOTLPInsecure, Cfg.TracingSampleRatio)
+	log.Printf("[INFO] Health check config - Timeout: %v", Cfg.HealthCheckTimeout)
+	log.Printf("[INFO] Shutdown config - Timeout: %v", Cfg.ShutdownTimeout)
+	log.Printf("[INFO] Validity enforcement config - Enabled: %v, Interval: %v",
+		Cfg.ValidityEnforcementEnabled, Cfg.ValidityEnforcementInterval)
+	log.Printf("[INFO] Idempotency config - Enabled: %v, TTL: %v, LockTimeout: %v",
+		Cfg.IdempotencyEnabled, Cfg.IdempotencyTTL, Cfg.IdempotencyLockTimeout)
+	log.Printf("[INFO] Rate limit config - Enabled: %v, ClientRPS: %g, ClientBurst: %d, Routes: %d",
+		Cfg.RateLimitEnabled, Cfg.RateLimitClientRPS, Cfg.RateLimitClientBurst, len(Cfg.RateLimitRoutes))
+
+	return nil
+}
+
+// loadFromEnv reads every setting from the environment into c, using defaults for unset variables.
+func loadFromEnv(c *AppConfig) {
+	c.DBHost = getEnv("DB_HOST", "127.0.0.1")
+	c.DBUser = getEnv("DB_USER", "root")
+	c.DBPass = getEnv("DB_PASS", "")
+	c.DBName = getEnv("DB_NAME", "test_db")
 
 	portStr := getEnv("DB_PORT", "3306")
 	portInt, _ := strconv.Atoi(portStr)
-	Cfg.DBPort = portInt
+	c.DBPort = portInt
 
-	Cfg.VeloClientPath = "/usr/local/bin/veloapiclient"
-	Cfg.AgentAPIPath = getEnv("AGENT_API_PATH", "/usr/local/bin/dbfAgentAPI")
+	c.VeloClientPath = "/usr/local/bin/veloapiclient"
+	c.AgentAPIPath = getEnv("AGENT_API_PATH", "/usr/local/bin/dbfAgentAPI")
 
 	// Load logging config
-	Cfg.LogLevel = getEnv("LOG_LEVEL", "DEBUG")
-	Cfg.LogFile = getEnv("LOG_FILE", "/var/log/dbf/dbfartifactapi.log")
+	c.LogLevel = getEnv("LOG_LEVEL", "DEBUG")
+	c.LogFile = getEnv("LOG_FILE", "/var/log/dbf/dbfartifactapi.log")
 
-	Cfg.LogMaxSize = getEnvInt("LOG_MAX_SIZE", 10)
-	Cfg.LogMaxBackups = getEnvInt("LOG_MAX_BACKUPS", 3)
-	Cfg.LogMaxAge = getEnvInt("LOG_MAX_AGE", 28)
-	Cfg.LogCompress = getEnvBool("LOG_COMPRESS", true)
+	c.LogMaxSize = getEnvInt("LOG_MAX_SIZE", 10)
+	c.LogMaxBackups = getEnvInt("LOG_MAX_BACKUPS", 3)
+	c.LogMaxAge = getEnvInt("LOG_MAX_AGE", 28)
+	c.LogCompress = getEnvBool("LOG_COMPRESS", true)
 
 	// Load DBF Web config
-	Cfg.DBFWebTempDir = getEnv("DBFWEB_TEMP_DIR", "/etc/saids/idsconfig/tmp/dbfweb")
-	Cfg.VeloResultsDir = getEnv("VELO_RESULTS_DIR", "/var/log/edr/evident")
-	Cfg.NotificationFileDir = getEnv("NOTIFICATION_FILE_DIR", "/etc/saids/idsconfig/tmp/dbfresults")
-	Cfg.DownloadFileDir = getEnv("DOWNLOAD_FILE_DIR", "/etc/saids/idsconfig/tmp/downloads")
+	c.DBFWebTempDir = getEnv("DBFWEB_TEMP_DIR", "/etc/saids/idsconfig/tmp/dbfweb")
+	c.VeloResultsDir = getEnv("VELO_RESULTS_DIR", "/var/log/edr/evident")
+	c.NotificationFileDir = getEnv("NOTIFICATION_FILE_DIR", "/etc/saids/idsconfig/tmp/dbfresults")
+	c.DownloadFileDir = getEnv("DOWNLOAD_FILE_DIR", "/etc/saids/idsconfig/tmp/downloads")
 
 	// Load VeloArtifact timeout and retry config
-	Cfg.VeloExecutionTimeout = time.Duration(getEnvInt("VELO_EXECUTION_TIMEOUT", 120)) * time.Second // Default: 120 seconds
-	Cfg.VeloDownloadTimeout = time.Duration(getEnvInt("VELO_DOWNLOAD_TIMEOUT", 60)) * time.Second    // Default: 60 seconds
-	Cfg.VeloMaxRetries = getEnvInt("VELO_MAX_RETRIES", 5)                                            // Default: 5 retries
-	Cfg.VeloRetryBaseDelay = time.Duration(getEnvInt("VELO_RETRY_BASE_delay", 2)) * time.Second      // Default: 2 seconds
-	Cfg.VeloDownloadRetries = getEnvInt("VELO_DOWNLOAD_RETRIES", 5)                                  // Default: 5 retries
+	c.VeloExecutionTimeout = time.Duration(getEnvInt("VELO_EXECUTION_TIMEOUT", 120)) * time.Second // Default: 120 seconds
+	c.VeloDownloadTimeout = time.Duration(getEnvInt("VELO_DOWNLOAD_TIMEOUT", 60)) * time.Second    // Default: 60 seconds
+	c.VeloMaxRetries = getEnvInt("VELO_MAX_RETRIES", 5)                                            // Default: 5 retries
+	c.VeloRetryBaseDelay = time.Duration(getEnvInt("VELO_RETRY_BASE_delay", 2)) * time.Second      // Default: 2 seconds
+	c.VeloDownloadRetries = getEnvInt("VELO_DOWNLOAD_RETRIES", 5)                                  // Default: 5 retries
 
 	// Load Agent API timeout and retry config
-	Cfg.AgentExecutionTimeout = time.Duration(getEnvInt("AGENT_EXECUTION_TIMEOUT", 120)) * time.Second // Default: 120 seconds
-	Cfg.AgentMaxRetries = getEnvInt("AGENT_MAX_RETRIES", 5)                                            // Default: 5 retries
-	Cfg.AgentRetryBaseDelay = time.Duration(getEnvInt("AGENT_RETRY_BASE_DELAY", 2)) * time.Second      // Default: 2 seconds
+	c.AgentExecutionTimeout = time.Duration(getEnvInt("AGENT_EXECUTION_TIMEOUT", 120)) * time.Second // Default: 120 seconds
+	c.AgentMaxRetries = getEnvInt("AGENT_MAX_RETRIES", 5)                                            // Default: 5 retries
+	c.AgentRetryBaseDelay = time.Duration(getEnvInt("AGENT_RETRY_BASE_DELAY", 2)) * time.Second      // Default: 2 seconds
+
+	// Load agent transport config
+	c.AgentTransport = strings.ToLower(getEnv("AGENT_TRANSPORT", "exec"))
+	c.AgentGatewayURL = getEnv("AGENT_GATEWAY_URL", "")
+	c.AgentGatewayToken = getEnv("AGENT_GATEWAY_TOKEN", "")
+	c.AgentMaxConcurrentPerClient = getEnvInt("AGENT_MAX_CONCURRENT_PER_CLIENT", 4)
 
 	// Load system exclusion lists with defaults
-	Cfg.SystemDatabases = getEnvStringSlice("SYSTEM_DATABASES", []string{
+	c.SystemDatabases = getEnvStringSlice("SYSTEM_DATABASES", []string{
 		"information_schema",
 		"mysql",
 		"performance_schema",
 		"sys",
 	})
-	Cfg.SystemUsers = getEnvStringSlice("SYSTEM_USERS", []string{
+	c.SystemUsers = getEnvStringSlice("SYSTEM_USERS", []string{
 		"mysql.infoschema",
 		"mysql.session",
 		"mysql.sys",
 	})
 
 	// Load policy compliance tool path
-	Cfg.DBFCheckPolicyCompliancePath = getEnv("DBF_CHECK_POLICY_COMPLIANCE_PATH", "/etc/v2/dbf/dbfcheckpolicycompliance/dbfcheckpolicycompliance")
+	c.DBFCheckPolicyCompliancePath = getEnv("DBF_CHECK_POLICY_COMPLIANCE_PATH", "/etc/v2/dbf/dbfcheckpolicycompliance/dbfcheckpolicycompliance")
 
 	// Load concurrency config (0 = auto-detect based on CPU cores)
-	Cfg.PrivilegeLoadConcurrency = getEnvInt("PRIVILEGE_LOAD_CONCURRENCY", 0)
-	Cfg.PrivilegeQueryConcurrency = getEnvInt("PRIVILEGE_QUERY_CONCURRENCY", 0)
+	c.PrivilegeLoadConcurrency = getEnvInt("PRIVILEGE_LOAD_CONCURRENCY", 0)
+	c.PrivilegeQueryConcurrency = getEnvInt("PRIVILEGE_QUERY_CONCURRENCY", 0)
 
 	// Load MySQL privilege query logging config (default: false for production)
-	Cfg.EnableMySQLPrivilegeQueryLogging = getEnvBool("ENABLE_MYSQL_PRIVILEGE_QUERY_LOGGING", false)
-
-	log.Printf("[INFO] Config loaded - DB: %s@%s:%d/%s, LogLevel: %s",
-		Cfg.DBUser, Cfg.DBHost, Cfg.DBPort, Cfg.DBName, Cfg.LogLevel)
-	log.Printf("[INFO] VeloArtifact config - ExecTimeout: %v, DownloadTimeout: %v, MaxRetries: %d, BaseDelay: %v",
-		Cfg.VeloExecutionTimeout, Cfg.VeloDownloadTimeout, Cfg.VeloMaxRetries, Cfg.VeloRetryBaseDelay)
-	log.Printf("[INFO] Agent API config - Path: %s, ExecTimeout: %v, MaxRetries: %d, BaseDelay: %v",
-		Cfg.AgentAPIPath, Cfg.AgentExecutionTimeout, Cfg.AgentMaxRetries, Cfg.AgentRetryBaseDelay)
-	log.Printf("[INFO] System exclusion lists - Databases: %v, Users: %v",
-		Cfg.SystemDatabases, Cfg.SystemUsers)
-
-	return nil
+	c.EnableMySQLPrivilegeQueryLogging = getEnvBool("ENABLE_MYSQL_PRIVILEGE_QUERY_LOGGING", false)
+
+	// Load job persistence config (default: true so running jobs are restored on startup)
+	c.JobPersistenceEnabled = getEnvBool("JOB_PERSISTENCE_ENABLED", true)
+	c.JobMonitorInterval = time.Duration(getEnvInt("JOB_MONITOR_INTERVAL", 10)) * time.Second // Default: 10 seconds
+
+	// Load job event WebSocket config (default: no origin allowed, the WebSocket endpoint rejects every browser)
+	c.JobEventsAllowedOrigins = getEnvStringSlice("JOB_EVENTS_ALLOWED_ORIGINS", nil)
+	c.JobEventsAllowNoOrigin = getEnvBool("JOB_EVENTS_ALLOW_NO_ORIGIN", false)
+
+	// Load privilege snapshot config (default: true so drift between discovery runs can be reported)
+	c.PrivilegeSnapshotEnabled = getEnvBool("PRIVILEGE_SNAPSHOT_ENABLED", true)
+
+	// Load policy revision config (default: true so policy changes can be rolled back)
+	c.PolicyRevisionsEnabled = getEnvBool("POLICY_REVISIONS_ENABLED", true)
+
+	// Load change approval config (default: disabled, approvals need authenticated callers to tell users apart)
+	c.ApprovalEnabled = getEnvBool("APPROVAL_ENABLED", false)
+	c.ApprovalActorThreshold = getEnvInt("APPROVAL_ACTOR_THRESHOLD", 20)
+	c.ApprovalsRequired = getEnvInt("APPROVALS_REQUIRED", 1)
+	c.ApprovalsRequiredCritical = getEnvInt("APPROVALS_REQUIRED_CRITICAL", 2)
+
+	// Load validity enforcement config (default: disabled, it sends agent commands without a caller)
+	c.ValidityEnforcementEnabled = getEnvBool("VALIDITY_ENFORCEMENT_ENABLED", false)
+	c.ValidityEnforcementInterval = time.Duration(getEnvInt("VALIDITY_ENFORCEMENT_INTERVAL", 60)) * time.Second // Default: 60 seconds
+
+	// Load API authentication config (default: enabled, requests without valid credentials are rejected)
+	c.AuthEnabled = getEnvBool("AUTH_ENABLED", true)
+	c.AuthAPIKeys = getEnvStringSlice("AUTH_API_KEYS", nil)
+	c.AuthJWKSFile = getEnv("AUTH_JWKS_FILE", "")
+	c.AuthJWTIssuer = getEnv("AUTH_JWT_ISSUER", "")
+	c.AuthJWTAudience = getEnv("AUTH_JWT_AUDIENCE", "")
+	c.AuthJWTRoleClaim = getEnv("AUTH_JWT_ROLE_CLAIM", "roles")
+
+	// Load agent callback auth config (default: none, job notifications are rejected while AUTH_ENABLED is set)
+	c.AgentCallbackSecrets = getEnvStringSlice("AGENT_CALLBACK_SECRETS", nil)
+
+	// Load audit trail config (default: true so every change is attributable)
+	c.AuditEnabled = getEnvBool("AUDIT_ENABLED", true)
+
+	// Load credential encryption config (no keys: passwords stay in plain text, as before)
+	c.CredentialKeys = getEnvStringSlice("CREDENTIAL_KEYS", nil)
+	c.CredentialActiveKey = getEnv("CREDENTIAL_ACTIVE_KEY", "")
+	c.CredentialKeyFile = getEnv("CREDENTIAL_KEY_FILE", "")
+	c.CredentialRewrapOnStartup = getEnvBool("CREDENTIAL_REWRAP_ON_STARTUP", false) // Opt-in: DBF Web reads the same columns
+
+	// Load secret provider config
+	c.SecretFileDir = getEnv("SECRET_FILE_DIR", "")
+	c.SecretEnvPrefix = getEnv("SECRET_ENV_PREFIX", "DBF_SECRET_")
+	c.VaultAddr = getEnv("VAULT_ADDR", "")
+	c.VaultToken = getEnv("VAULT_TOKEN", "")
+
+	// Load metrics config
+	c.MetricsEnabled = getEnvBool("METRICS_ENABLED", true)
+	c.MetricsPath = getEnv("METRICS_PATH", "/metrics")
+
+	// Load tracing config (default: disabled, a local collector on the OTLP/HTTP port when enabled)
+	c.TracingEnabled = getEnvBool("TRACING_ENABLED", false)
+	c.TracingOTLPEndpoint = getEnv("TRACING_OTLP_ENDPOINT", "localhost:4318")
+	c.TracingOTLPInsecure = getEnvBool("TRACING_OTLP_INSECURE", true)
+	c.TracingServiceName = getEnv("TRACING_SERVICE_NAME", "dbfartifactapi")
+	c.TracingSampleRatio = getEnvFloat("TRACING_SAMPLE_RATIO", 1.0)
+
+	// Load health check config
+	c.HealthCheckTimeout = time.Duration(getEnvInt("HEALTH_CHECK_TIMEOUT", 3)) * time.Second // Default: 3 seconds
+
+	// Load graceful shutdown config
+	c.ShutdownTimeout = time.Duration(getEnvInt("SHUTDOWN_TIMEOUT", 30)) * time.Second // Default: 30 seconds
+
+	// Load idempotency config
+	c.IdempotencyEnabled = getEnvBool("IDEMPOTENCY_ENABLED", true)
+	c.IdempotencyTTL = time.Duration(getEnvInt("IDEMPOTENCY_TTL", 86400)) * time.Second                // Default: 24 hours
+	c.IdempotencyLockTimeout = time.Duration(getEnvInt("IDEMPOTENCY_LOCK_TIMEOUT", 600)) * time.Second // Default: 10 minutes
+
+	// Load rate limit config
+	c.RateLimitEnabled = getEnvBool("RATE_LIMIT_ENABLED", true)
+	c.RateLimitClientRPS = getEnvFloat("RATE_LIMIT_CLIENT_RPS", 20)
+	c.RateLimitClientBurst = getEnvInt("RATE_LIMIT_CLIENT_BURST", 40)
+	c.RateLimitRoutes = getEnvStringSlice("RATE_LIMIT_ROUTES", nil)
 }
 
 func getEnv(key, defaultVal string) string {
@@ -168,6 +362,15 @@ func getEnvInt(key string, defaultVal int) int {
 	return defaultVal
 }
 
+func getEnvFloat(key string, defaultVal float64) float64 {
+	if val := os.Getenv(key); val != "" {
+		if floatVal, err := strconv.ParseFloat(val, 64); err == nil {
+			return floatVal
+		}
+	}
+	return defaultVal
+}
+
 func getEnvBool(key string, defaultVal bool) bool {
 	if val := os.Getenv(key); val != "" {
 		if boolVal, err := strconv.ParseBool(val); err == nil {
@@ -196,6 +399,8 @@ func getEnvStringSlice(key string, defaultVal []string) []string {
 
 // IsSystemDatabase checks if a database name is in the system exclusion list
 func IsSystemDatabase(dbName string) bool {
+	cfgMu.RLock()
+	defer cfgMu.RUnlock()
 	for _, sysDB := range Cfg.SystemDatabases {
 		if dbName == sysDB {
 			return true
@@ -206,6 +411,8 @@ func IsSystemDatabase(dbName string) bool {
 
 // IsSystemUser checks if a database user is in the system exclusion list
 func IsSystemUser(dbUser string) bool {
+	cfgMu.RLock()
+	defer cfgMu.RUnlock()
 	for _, sysUser := range Cfg.SystemUsers {
 		if dbUser == sysUser {
 			return true
@@ -218,8 +425,8 @@ func IsSystemUser(dbUser string) bool {
 // Auto-detects based on CPU cores if config value is 0, ensuring minimum of 2 and maximum of 20.
 // Conservative limit prevents memory exhaustion when loading large privilege datasets.
 func GetPrivilegeLoadConcurrency() int {
-	if Cfg.PrivilegeLoadConcurrency > 0 {
-		return Cfg.PrivilegeLoadConcurrency
+	if n := Current().PrivilegeLoadConcurrency; n > 0 {
+		return n
 	}
 
 	// Auto-detect: use half of CPU cores to leave resources for other operations
@@ -239,8 +446,8 @@ func GetPrivilegeLoadConcurrency() int {
 // Auto-detects based on CPU cores if config value is 0, ensuring minimum of 4 and maximum of 50.
 // Higher than load concurrency because queries are lighter weight than data loading.
 func GetPrivilegeQueryConcurrency() int {
-	if Cfg.PrivilegeQueryConcurrency > 0 {
-		return Cfg.PrivilegeQueryConcurrency
+	if n := Current().PrivilegeQueryConcurrency; n > 0 {
+		return n
 	}
 
 	// Auto-detect: use full CPU cores since queries are CPU-bound operations
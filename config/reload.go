@@ -0,0 +1,198 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+
+	"dbfartifactapi/pkg/logger"
+
+	"github.com/joho/godotenv"
+)
+
+// reloadableFields are the settings Reload applies to the running process. Everything else, such as the
+// database connection, authentication, credential keys, agent transport and the HTTP middleware chain,
+// is read once at startup and needs a restart to change.
+var reloadableFields = map[string]bool{
+	"LogLevel":                         true,
+	"SystemDatabases":                  true,
+	"SystemUsers":                      true,
+	"AgentExecutionTimeout":            true,
+	"AgentMaxRetries":                  true,
+	"AgentMaxConcurrentPerClient":      true,
+	"PrivilegeLoadConcurrency":         true,
+	"PrivilegeQueryConcurrency":        true,
+	"EnableMySQLPrivilegeQueryLogging": true,
+	"JobMonitorInterval":               true,
+	"ApprovalActorThreshold":           true,
+	"ApprovalsRequired":                true,
+	"ApprovalsRequiredCritical":        true,
+}
+
+var (
+	// cfgMu guards the reloadable fields of Cfg
+	cfgMu sync.RWMutex
+	// reloadMu serializes reloads
+	reloadMu sync.Mutex
+	// dotEnvKeys are the variables last set from .env rather than the process environment
+	dotEnvKeys = make(map[string]bool)
+
+	subscribersMu sync.Mutex
+	subscribers   []func(prev, next AppConfig)
+)
+
+// ReloadResult lists the settings whose value changed in a reload, by AppConfig field name.
+type ReloadResult struct {
+	Applied         []string // Now in effect
+	RestartRequired []string // Kept at their current value until the next restart
+}
+
+// Current returns a copy of the configuration. Fields applied by Reload are consistent with each other,
+// e.g. AgentExecutionTimeout and AgentMaxRetries always come from the same reload.
+func Current() AppConfig {
+	cfgMu.RLock()
+	defer cfgMu.RUnlock()
+	return Cfg
+}
+
+// OnReload registers fn to be called after each reload that changed a reloadable setting.
+// fn receives the configuration before and after the reload and must not block.
+func OnReload(fn func(prev, next AppConfig)) {
+	subscribersMu.Lock()
+	defer subscribersMu.Unlock()
+	subscribers = append(subscribers, fn)
+}
+
+// Reload reads .env and the environment again, validates the result and applies the reloadable settings
+// at once. Nothing is applied when validation fails. Variables set in the process environment keep
+// precedence over .env, as on startup.
+func Reload() (*ReloadResult, error) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	if err := loadDotEnv(); err != nil {
+		logger.Warnf("Config reload: .env cannot be read, using the current environment: %v", err)
+	}
+	var next AppConfig
+	loadFromEnv(&next)
+	if err := next.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	cfgMu.Lock()
+	prev := Cfg
+	result := applyReloadable(&Cfg, &next)
+	applied := Cfg
+	cfgMu.Unlock()
+
+	if len(result.RestartRequired) > 0 {
+		logger.Warnf("Config reload: changes to %s take effect after a restart", strings.Join(result.RestartRequired, ", "))
+	}
+	if len(result.Applied) == 0 {
+		logger.Infof("Config reload: no reloadable setting changed")
+		return result, nil
+	}
+	logger.Infof("Config reload: applied %s", strings.Join(result.Applied, ", "))
+
+	subscribersMu.Lock()
+	fns := append([]func(prev, next AppConfig){}, subscribers...)
+	subscribersMu.Unlock()
+	for _, fn := range fns {
+		fn(prev, applied)
+	}
+	return result, nil
+}
+
+// applyReloadable copies the reloadable fields of src that differ into dst and reports every changed field.
+// Caller must hold cfgMu.
+func applyReloadable(dst, src *AppConfig) *ReloadResult {
+	result := &ReloadResult{Applied: []string{}, RestartRequired: []string{}}
+	dstVal := reflect.ValueOf(dst).Elem()
+	srcVal := reflect.ValueOf(src).Elem()
+	for i := 0; i < dstVal.NumField(); i++ {
+		name := dstVal.Type().Field(i).Name
+		if reflect.DeepEqual(dstVal.Field(i).Interface(), srcVal.Field(i).Interface()) {
+			continue
+		}
+		if !reloadableFields[name] {
+			result.RestartRequired = append(result.RestartRequired, name)
+			continue
+		}
+		dstVal.Field(i).Set(srcVal.Field(i))
+		result.Applied = append(result.Applied, name)
+	}
+	return result
+}
+
+// Validate reports settings that are out of range. LoadConfig falls back to defaults for unparsable values;
+// Validate rejects values that parse but cannot work.
+func (c *AppConfig) Validate() error {
+	var problems []string
+	if _, ok := logger.LookupLogLevel(c.LogLevel); !ok {
+		problems = append(problems, fmt.Sprintf("LOG_LEVEL %q is not one of DEBUG, INFO, WARN, ERROR, FATAL", c.LogLevel))
+	}
+	if c.DBPort < 1 || c.DBPort > 65535 {
+		problems = append(problems, fmt.Sprintf("DB_PORT %d is out of range", c.DBPort))
+	}
+	if c.AgentTransport != "exec" && c.AgentTransport != "http" {
+		problems = append(problems, fmt.Sprintf("AGENT_TRANSPORT %q must be exec or http", c.AgentTransport))
+	}
+	if c.AgentExecutionTimeout <= 0 {
+		problems = append(problems, "AGENT_EXECUTION_TIMEOUT must be positive")
+	}
+	if c.AgentMaxRetries < 1 {
+		problems = append(problems, "AGENT_MAX_RETRIES must be at least 1")
+	}
+	if c.AgentMaxConcurrentPerClient < 0 {
+		problems = append(problems, "AGENT_MAX_CONCURRENT_PER_CLIENT must not be negative")
+	}
+	if c.PrivilegeLoadConcurrency < 0 || c.PrivilegeQueryConcurrency < 0 {
+		problems = append(problems, "PRIVILEGE_LOAD_CONCURRENCY and PRIVILEGE_QUERY_CONCURRENCY must not be negative")
+	}
+	if c.JobMonitorInterval <= 0 {
+		problems = append(problems, "JOB_MONITOR_INTERVAL must be positive")
+	}
+	if c.ValidityEnforcementInterval <= 0 {
+		problems = append(problems, "VALIDITY_ENFORCEMENT_INTERVAL must be positive")
+	}
+	if c.ApprovalActorThreshold < 0 {
+		problems = append(problems, "APPROVAL_ACTOR_THRESHOLD must not be negative")
+	}
+	if c.ApprovalsRequired < 1 || c.ApprovalsRequiredCritical < 1 {
+		problems = append(problems, "APPROVALS_REQUIRED and APPROVALS_REQUIRED_CRITICAL must be at least 1")
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// loadDotEnv sets the variables of .env that the process environment does not define. Variables taken
+// from .env are remembered, so a later call picks up their edits and removals.
+func loadDotEnv() error {
+	values, err := godotenv.Read()
+	if err != nil {
+		return err
+	}
+	for key := range dotEnvKeys {
+		if _, exists := values[key]; !exists {
+			os.Unsetenv(key)
+			delete(dotEnvKeys, key)
+		}
+	}
+	for key, value := range values {
+		if _, set := os.LookupEnv(key); set && !dotEnvKeys[key] {
+			continue
+		}
+		if err := os.Setenv(key, value); err != nil {
+			log.Printf("[WARN] Cannot set %s from .env: %v", key, err)
+			continue
+		}
+		dotEnvKeys[key] = true
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// setupReloadTest loads the config from a .env in a temp dir and restores the globals afterwards
+func setupReloadTest(t *testing.T, dotEnv string) string {
+	t.Helper()
+	original, originalSubscribers := Cfg, subscribers
+	t.Cleanup(func() {
+		for key := range dotEnvKeys {
+			os.Unsetenv(key)
+		}
+		dotEnvKeys = make(map[string]bool)
+		Cfg, subscribers = original, originalSubscribers
+	})
+
+	dir := t.TempDir()
+	t.Chdir(dir)
+	path := filepath.Join(dir, ".env")
+	if err := os.WriteFile(path, []byte(dotEnv), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	return path
+}
+
+// TestReload_AppliesReloadableSettings tests that reloadable settings change and startup-only ones are reported
+func TestReload_AppliesReloadableSettings(t *testing.T) {
+	t.Setenv("AGENT_EXECUTION_TIMEOUT", "60")
+	path := setupReloadTest(t, "AGENT_MAX_RETRIES=5\nSYSTEM_USERS=root\nDB_HOST=db1\n")
+
+	var events []AppConfig
+	OnReload(func(prev, next AppConfig) { events = append(events, prev, next) })
+
+	// The process environment keeps precedence over .env
+	dotEnv := "AGENT_MAX_RETRIES=2\nSYSTEM_USERS=root,admin\nDB_HOST=db2\nAGENT_EXECUTION_TIMEOUT=5\n"
+	if err := os.WriteFile(path, []byte(dotEnv), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	result, err := Reload()
+	if err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+
+	if want := []string{"AgentMaxRetries", "SystemUsers"}; !reflect.DeepEqual(result.Applied, want) {
+		t.Errorf("Applied = %v, want %v", result.Applied, want)
+	}
+	if want := []string{"DBHost"}; !reflect.DeepEqual(result.RestartRequired, want) {
+		t.Errorf("RestartRequired = %v, want %v", result.RestartRequired, want)
+	}
+	current := Current()
+	if current.AgentMaxRetries != 2 || !IsSystemUser("admin") || current.DBHost != "db1" || current.AgentExecutionTimeout != time.Minute {
+		t.Errorf("config after reload = retries %d, users %v, host %s, timeout %v",
+			current.AgentMaxRetries, current.SystemUsers, current.DBHost, current.AgentExecutionTimeout)
+	}
+	if len(events) != 2 || events[0].AgentMaxRetries != 5 || events[1].AgentMaxRetries != 2 {
+		t.Errorf("expected one change event from 5 to 2 retries, got %d configs", len(events))
+	}
+
+	// Removing a variable from .env falls back to its default
+	if err := os.WriteFile(path, []byte(dotEnv[len("AGENT_MAX_RETRIES=2\n"):]), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if got := Current().AgentMaxRetries; got != 5 {
+		t.Errorf("AgentMaxRetries after removal = %d, want default 5", got)
+	}
+}
+
+// TestReload_RejectsInvalidConfig tests that nothing is applied when the new config does not validate
+func TestReload_RejectsInvalidConfig(t *testing.T) {
+	path := setupReloadTest(t, "AGENT_MAX_RETRIES=5\nLOG_LEVEL=INFO\n")
+
+	OnReload(func(prev, next AppConfig) { t.Error("unexpected change event") })
+	if err := os.WriteFile(path, []byte("AGENT_MAX_RETRIES=0\nLOG_LEVEL=VERBOSE\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Reload(); err == nil {
+		t.Fatal("expected validation error")
+	}
+	if current := Current(); current.AgentMaxRetries != 5 || current.LogLevel != "INFO" {
+		t.Errorf("config changed by rejected reload: retries %d, log level %s", current.AgentMaxRetries, current.LogLevel)
+	}
+}
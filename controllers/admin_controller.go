@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	"dbfartifactapi/pkg/auth"
+	"dbfartifactapi/services/admin"
+	"dbfartifactapi/services/dto"
+	"dbfartifactapi/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+var adminSrv admin.AdminService
+
+// SetAdminService initializes the runtime administration service instance.
+// Used for dependency injection in tests to provide mock implementations.
+func SetAdminService(s admin.AdminService) {
+	adminSrv = s
+}
+
+// GetLogLevel returns the current log level
+// @Summary Get log level
+// @Description Returns the log level in effect and the LOG_LEVEL of the loaded config. They differ after a change through PUT /api/admin/log-level.
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} dto.LogLevelResponse "Current log level"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/admin/log-level [get]
+func getLogLevel(c *gin.Context) {
+	utils.JSONResponse(c, http.StatusOK, adminSrv.GetLogLevel())
+}
+
+// SetLogLevel changes the log level at runtime
+// @Summary Set log level
+// @Description Changes the log level without a restart. The level holds until the next restart or a config reload that changes LOG_LEVEL.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param request body dto.LogLevelRequest true "New log level"
+// @Success 200 {object} dto.LogLevelResponse "Log level changed"
+// @Failure 400 {object} StandardErrorResponse "Unknown log level"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/admin/log-level [put]
+func setLogLevel(c *gin.Context) {
+	var req dto.LogLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, fmt.Errorf("invalid request body: %v", err))
+		return
+	}
+
+	result, err := adminSrv.SetLogLevel(req.Level)
+	if err != nil {
+		utils.ErrorResponse(c, err)
+		return
+	}
+	utils.JSONResponse(c, http.StatusOK, result)
+}
+
+// ReloadConfig re-reads the configuration
+// @Summary Reload configuration
+// @Description Re-reads .env and the environment, validates the result and applies reloadable settings such as SYSTEM_DATABASES, SYSTEM_USERS, LOG_LEVEL, agent timeouts, retries and concurrency, privilege query concurrency and JOB_MONITOR_INTERVAL. Changed settings that are only read at startup are listed under restart_required. Same as sending SIGHUP to the process.
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} dto.ConfigReloadResponse "Changed settings"
+// @Failure 400 {object} StandardErrorResponse "Invalid configuration, nothing was applied"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/admin/config/reload [post]
+func reloadConfig(c *gin.Context) {
+	result, err := adminSrv.ReloadConfig()
+	if err != nil {
+		utils.ErrorResponse(c, err)
+		return
+	}
+	utils.JSONResponse(c, http.StatusOK, result)
+}
+
+// RegisterAdminRoutes registers runtime administration endpoints.
+func RegisterAdminRoutes(rg *gin.RouterGroup) {
+	adminGroup := rg.Group("/admin")
+	{
+		adminGroup.GET("/log-level", auth.RequireRole(auth.RoleOperator), getLogLevel)
+		adminGroup.PUT("/log-level", auth.RequireRole(auth.RoleSuperAdmin), setLogLevel)
+		adminGroup.POST("/config/reload", auth.RequireRole(auth.RoleSuperAdmin), reloadConfig)
+	}
+}
@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	"dbfartifactapi/pkg/auth"
+	"dbfartifactapi/pkg/logger"
+	"dbfartifactapi/services/audit"
+	"dbfartifactapi/services/dto"
+	"dbfartifactapi/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+var auditSrv audit.AuditService
+
+// SetAuditService initializes the audit trail service instance.
+// Used for dependency injection in tests to provide mock implementations.
+func SetAuditService(s audit.AuditService) {
+	auditSrv = s
+}
+
+// ListAuditEvents returns audit trail events matching the given filters
+// @Summary List audit events
+// @Description Returns the audit trail of mutating API calls and agent commands, newest first. Credentials in commands and entity states are redacted.
+// @Tags Audit
+// @Produce json
+// @Param actor query string false "Caller subject (API key name or JWT sub)"
+// @Param entity_type query string false "Entity type, e.g. dbpolicy, group, dbactormgt"
+// @Param entity_id query string false "Entity ID"
+// @Param action query string false "Action, e.g. create, update, delete, request"
+// @Param outcome query string false "Outcome: success or failure"
+// @Param from query string false "Earliest event time (RFC3339)"
+// @Param to query string false "Latest event time (RFC3339)"
+// @Param page query int false "Page number (default: 1)"
+// @Param page_size query int false "Events per page (default: 50, max: 500)"
+// @Success 200 {object} dto.AuditEventListResponse "Page of audit events"
+// @Failure 400 {object} StandardErrorResponse "Invalid filter"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/audit [get]
+func listAuditEvents(c *gin.Context) {
+	var query dto.AuditEventQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.ErrorResponse(c, fmt.Errorf("invalid audit filter: %v", err))
+		return
+	}
+
+	result, err := auditSrv.ListEvents(c.Request.Context(), query)
+	if err != nil {
+		utils.ErrorResponse(c, err)
+		return
+	}
+	utils.JSONResponse(c, http.StatusOK, result)
+}
+
+// VerifyAuditChain recomputes the audit trail hash chain
+// @Summary Verify audit trail integrity
+// @Description Recomputes the hash of every audit event and checks that each links to its predecessor and the chain ends at the recorded head. Reports the first event found edited or missing.
+// @Tags Audit
+// @Produce json
+// @Success 200 {object} dto.AuditVerifyResponse "Verification result"
+// @Failure 500 {object} StandardErrorResponse "Audit trail could not be read"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/audit/verify [get]
+func verifyAuditChain(c *gin.Context) {
+	result, err := auditSrv.VerifyChain(c.Request.Context())
+	if err != nil {
+		logger.Errorf("Audit chain verification error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	utils.JSONResponse(c, http.StatusOK, result)
+}
+
+// RegisterAuditRoutes registers HTTP endpoints for reading the audit trail.
+func RegisterAuditRoutes(rg *gin.RouterGroup) {
+	a := rg.Group("/audit", auth.RequireRole(auth.RolePolicyAdmin))
+	{
+		a.GET("", listAuditEvents)
+		a.GET("/verify", verifyAuditChain)
+	}
+}
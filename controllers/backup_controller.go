@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"dbfartifactapi/models"
+	"dbfartifactapi/pkg/auth"
 	"dbfartifactapi/pkg/logger"
 	"dbfartifactapi/services/fileops"
 	"dbfartifactapi/utils"
@@ -28,6 +29,8 @@ func SetBackupService(srv fileops.BackupService) {
 // @Success 200 {object} BackupJobStartResponse "Background jobs started successfully with job IDs"
 // @Failure 400 {object} BackupValidationErrorResponse "Invalid request body or validation error"
 // @Failure 500 {object} BackupErrorResponse "Internal server error during backup execution"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/backup [post]
 func executeBackup(c *gin.Context) {
 	var req models.BackupRequest
@@ -63,7 +66,7 @@ func executeBackup(c *gin.Context) {
 
 // RegisterBackupRoutes registers HTTP endpoints for database backup operations.
 func RegisterBackupRoutes(rg *gin.RouterGroup) {
-	backup := rg.Group("/backup")
+	backup := rg.Group("/backup", auth.RequireRole(auth.RoleSuperAdmin))
 	{
 		backup.POST("", executeBackup)
 	}
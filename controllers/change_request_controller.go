@@ -0,0 +1,174 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"dbfartifactapi/pkg/auth"
+	"dbfartifactapi/pkg/logger"
+	"dbfartifactapi/services/approval"
+	"dbfartifactapi/services/dto"
+	"dbfartifactapi/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+var changeRequestSrv approval.ChangeRequestService
+
+// SetChangeRequestService initializes the change request service instance.
+// Group changes are applied without approval while it is not set.
+func SetChangeRequestService(s approval.ChangeRequestService) {
+	changeRequestSrv = s
+}
+
+// ListChangeRequests returns change requests, optionally filtered by status
+// @Summary List change requests
+// @Description Returns group changes held back for approval, newest first, without their request and preview.
+// @Tags Change Requests
+// @Produce json
+// @Param status query string false "Status: pending, approved, rejected, applied or failed"
+// @Param page query int false "Page number (default: 1)"
+// @Param page_size query int false "Change requests per page (default: 50, max: 500)"
+// @Success 200 {object} dto.ChangeRequestListResponse "Page of change requests"
+// @Failure 400 {object} StandardErrorResponse "Invalid filter"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/queries/change-requests [get]
+func listChangeRequests(c *gin.Context) {
+	var query dto.ChangeRequestQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.ErrorResponse(c, fmt.Errorf("invalid change request filter: %v", err))
+		return
+	}
+
+	result, err := changeRequestSrv.List(c.Request.Context(), query)
+	if err != nil {
+		utils.ErrorResponse(c, err)
+		return
+	}
+	utils.JSONResponse(c, http.StatusOK, result)
+}
+
+// GetChangeRequest returns one change request
+// @Summary Get change request
+// @Description Returns a change request with the request it holds back, the dry-run preview of that request including the SQL it would run, the approvals and rejections so far and, once applied, the result.
+// @Tags Change Requests
+// @Produce json
+// @Param id path int true "Change request ID"
+// @Success 200 {object} dto.ChangeRequestDetailResponse "Change request"
+// @Failure 400 {object} StandardErrorResponse "Invalid ID or change request not found"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/queries/change-requests/{id} [get]
+func getChangeRequest(c *gin.Context) {
+	id, ok := parseChangeRequestID(c)
+	if !ok {
+		return
+	}
+
+	result, err := changeRequestSrv.Get(c.Request.Context(), id)
+	if err != nil {
+		utils.ErrorResponse(c, err)
+		return
+	}
+	utils.JSONResponse(c, http.StatusOK, result)
+}
+
+// ApproveChangeRequest approves a pending change request
+// @Summary Approve change request
+// @Description Records the caller's approval of a pending change request. The requester cannot approve their own change request and each user decides once. The approval that reaches the required count applies the change through the normal group service path; the response then has status applied with the result, or failed with the error.
+// @Tags Change Requests
+// @Accept json
+// @Produce json
+// @Param id path int true "Change request ID"
+// @Param decision body dto.ChangeRequestDecisionRequest false "Optional comment"
+// @Success 200 {object} dto.ChangeRequestDetailResponse "Change request after the approval"
+// @Failure 400 {object} StandardErrorResponse "Change request not pending, own change request or already decided"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/queries/change-requests/{id}/approve [post]
+func approveChangeRequest(c *gin.Context) {
+	id, comment, ok := parseChangeRequestDecision(c)
+	if !ok {
+		return
+	}
+
+	result, err := changeRequestSrv.Approve(c.Request.Context(), id, comment)
+	if err != nil {
+		logger.Errorf("Failed to approve change request %d: %v", id, err)
+		utils.ErrorResponse(c, err)
+		return
+	}
+	utils.JSONResponse(c, http.StatusOK, result)
+}
+
+// RejectChangeRequest rejects a pending change request
+// @Summary Reject change request
+// @Description Rejects a pending change request, which is then never applied. The requester may reject their own change request to withdraw it.
+// @Tags Change Requests
+// @Accept json
+// @Produce json
+// @Param id path int true "Change request ID"
+// @Param decision body dto.ChangeRequestDecisionRequest false "Optional comment"
+// @Success 200 {object} dto.ChangeRequestDetailResponse "Rejected change request"
+// @Failure 400 {object} StandardErrorResponse "Change request not pending or already decided"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/queries/change-requests/{id}/reject [post]
+func rejectChangeRequest(c *gin.Context) {
+	id, comment, ok := parseChangeRequestDecision(c)
+	if !ok {
+		return
+	}
+
+	result, err := changeRequestSrv.Reject(c.Request.Context(), id, comment)
+	if err != nil {
+		logger.Errorf("Failed to reject change request %d: %v", id, err)
+		utils.ErrorResponse(c, err)
+		return
+	}
+	utils.JSONResponse(c, http.StatusOK, result)
+}
+
+// parseChangeRequestID reads the id path parameter, writing the error response when invalid.
+func parseChangeRequestID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		utils.ErrorResponse(c, fmt.Errorf("invalid change request ID"))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// parseChangeRequestDecision reads the id path parameter and the optional decision body,
+// writing the error response when invalid.
+func parseChangeRequestDecision(c *gin.Context) (uint, string, bool) {
+	id, ok := parseChangeRequestID(c)
+	if !ok {
+		return 0, "", false
+	}
+	var request dto.ChangeRequestDecisionRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			utils.ErrorResponse(c, err)
+			return 0, "", false
+		}
+	}
+	if err := utils.ValidateStruct(&request); err != nil {
+		utils.ErrorResponse(c, err)
+		return 0, "", false
+	}
+	return id, request.Comment, true
+}
+
+// RegisterChangeRequestRoutes registers HTTP endpoints for reviewing change requests.
+func RegisterChangeRequestRoutes(rg *gin.RouterGroup) {
+	cr := rg.Group("/change-requests")
+	{
+		cr.GET("", listChangeRequests)
+		cr.GET("/:id", getChangeRequest)
+		cr.POST("/:id/approve", auth.RequireRole(auth.RolePolicyAdmin), approveChangeRequest)
+		cr.POST("/:id/reject", auth.RequireRole(auth.RolePolicyAdmin), rejectChangeRequest)
+	}
+}
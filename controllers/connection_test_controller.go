@@ -33,6 +33,8 @@ func NewConnectionTestController() *ConnectionTestController {
 // @Failure 400 {object} map[string]interface{} "Bad request"
 // @Failure 404 {object} map[string]interface{} "Connection not found"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/connection/test/{id} [post]
 func (ctrl *ConnectionTestController) TestConnection(c *gin.Context) {
 	idParam := c.Param("id")
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"dbfartifactapi/models"
+	"dbfartifactapi/pkg/auth"
 	"dbfartifactapi/pkg/logger"
 	"dbfartifactapi/repository"
 	"dbfartifactapi/services/entity"
@@ -34,6 +35,8 @@ func SetDBActorMgtService(s entity.DBActorMgtService) {
 // @Success 200 {object} BulkCreateResponse "Actors created successfully with count"
 // @Failure 400 {object} StandardErrorResponse "Invalid connection management ID"
 // @Failure 500 {object} ActorCreationErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/dbactormgt/all [post]
 func postDBActorMgtAll(c *gin.Context) {
 	var params struct {
@@ -71,6 +74,8 @@ func postDBActorMgtAll(c *gin.Context) {
 // @Success 201 {object} DBActorMgtCreateResponse "Actor created successfully"
 // @Failure 400 {object} StandardErrorResponse "Invalid request body, validation error, or invalid IP address"
 // @Failure 500 {object} ActorCreationErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/dbactormgt [post]
 func createDBActorMgt(c *gin.Context) {
 	var req dto.DBActorMgtCreate
@@ -116,7 +121,7 @@ func createDBActorMgt(c *gin.Context) {
 		Status:      req.Status,
 	}
 
-	logger.Debugf("Creating new database user: %+v", data)
+	logger.Debugf("Creating new database user: cntmgt=%d, dbuser=%s, ip_address=%s", data.CntID, data.DBUser, data.IPAddress)
 	newObj, err := dbActorMgtSrv.Create(c.Request.Context(), data)
 	if err != nil {
 		logger.Errorf("Failed to create database user: %v", err)
@@ -142,6 +147,8 @@ func createDBActorMgt(c *gin.Context) {
 // @Failure 400 {object} StandardErrorResponse "Invalid ID or request body"
 // @Failure 404 {object} StandardErrorResponse "Actor not found"
 // @Failure 500 {object} InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/dbactormgt/{id} [put]
 func updateDBActorMgt(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -190,6 +197,8 @@ func updateDBActorMgt(c *gin.Context) {
 // @Failure 400 {object} StandardErrorResponse "Invalid actor management ID"
 // @Failure 404 {object} StandardErrorResponse "Actor not found"
 // @Failure 500 {object} InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/dbactormgt/{id} [delete]
 func deleteDBActorMgt(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -210,13 +219,43 @@ func deleteDBActorMgt(c *gin.Context) {
 	})
 }
 
+// ExplainDBActorMgtPrivileges explains which grants give a database actor its policies
+// @Summary Explain effective privileges of a database actor
+// @Description Re-evaluates the actor against the latest privilege snapshot of its connection without writing policies. For each allowed policy default it returns the pass that matched (super, action_wide, object_specific), the final SQL, the result value, and the grant rows and role chain that produced it.
+// @Tags DB Actor Management
+// @Produce json
+// @Param id path int true "Actor Management ID"
+// @Success 200 {object} dto.PrivilegeExplainResponse "Allowed policy defaults with their grant/role path"
+// @Failure 400 {object} StandardErrorResponse "Invalid actor ID, actor not found or no privilege snapshot"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/queries/dbactormgt/{id}/privileges/explain [get]
+func explainDBActorMgtPrivileges(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, err)
+		return
+	}
+
+	logger.Debugf("Explaining privileges of database actor with ID: %d", id)
+	explanation, err := dbPolicySrv.ExplainActorPrivileges(c.Request.Context(), utils.MustIntToUint(id))
+	if err != nil {
+		logger.Errorf("Failed to explain privileges of database actor with ID %d: %v", id, err)
+		utils.ErrorResponse(c, err)
+		return
+	}
+	logger.Infof("Explained %d allowed policies for database actor with ID: %d", len(explanation.Policies), id)
+	utils.JSONResponse(c, http.StatusOK, explanation)
+}
+
 // RegisterDBActorMgtRoutes registers HTTP endpoints for database actor management operations.
 func RegisterDBActorMgtRoutes(rg *gin.RouterGroup) {
 	dbactormgt := rg.Group("/dbactormgt")
 	{
-		dbactormgt.POST("/all", postDBActorMgtAll)
-		dbactormgt.POST("", createDBActorMgt)
-		dbactormgt.PUT("/:id", updateDBActorMgt)
-		dbactormgt.DELETE("/:id", deleteDBActorMgt)
+		dbactormgt.POST("/all", auth.RequireRole(auth.RolePolicyAdmin), postDBActorMgtAll)
+		dbactormgt.POST("", auth.RequireRole(auth.RolePolicyAdmin), createDBActorMgt)
+		dbactormgt.PUT("/:id", auth.RequireRole(auth.RolePolicyAdmin), updateDBActorMgt)
+		dbactormgt.DELETE("/:id", auth.RequireRole(auth.RolePolicyAdmin), deleteDBActorMgt)
+		dbactormgt.GET("/:id/privileges/explain", explainDBActorMgtPrivileges)
 	}
 }
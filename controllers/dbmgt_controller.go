@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"dbfartifactapi/models"
+	"dbfartifactapi/pkg/auth"
 	"dbfartifactapi/pkg/logger"
 	"dbfartifactapi/services/entity"
 	"dbfartifactapi/utils"
@@ -31,6 +32,8 @@ func SetDBMgtService(s entity.DBMgtService) {
 // @Success 200 {object} BulkCreateResponse "Databases created successfully with count"
 // @Failure 400 {object} StandardErrorResponse "Invalid connection management ID"
 // @Failure 500 {object} DatabaseCreationErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/dbmgt/all [post]
 func postDBMgtAll(c *gin.Context) {
 	var params struct {
@@ -68,6 +71,8 @@ func postDBMgtAll(c *gin.Context) {
 // @Success 201 {object} DBMgtCreateResponse "Database created successfully"
 // @Failure 400 {object} StandardErrorResponse "Invalid request body or validation error"
 // @Failure 500 {object} DatabaseCreationErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/dbmgt [post]
 func createDBMgt(c *gin.Context) {
 	var data models.DBMgt
@@ -126,6 +131,8 @@ func createDBMgt(c *gin.Context) {
 // @Failure 400 {object} StandardErrorResponse "Invalid database management ID"
 // @Failure 404 {object} StandardErrorResponse "Database not found"
 // @Failure 500 {object} InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/dbmgt/{id} [delete]
 func deleteDBMgt(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -151,7 +158,7 @@ func RegisterDBMgtRoutes(rg *gin.RouterGroup) {
 	dbmgt := rg.Group("/dbmgt")
 	{
 		dbmgt.POST("/all", postDBMgtAll)
-		dbmgt.POST("", createDBMgt)
-		dbmgt.DELETE("/:id", deleteDBMgt)
+		dbmgt.POST("", auth.RequireRole(auth.RolePolicyAdmin), createDBMgt)
+		dbmgt.DELETE("/:id", auth.RequireRole(auth.RolePolicyAdmin), deleteDBMgt)
 	}
 }
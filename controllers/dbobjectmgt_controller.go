@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"dbfartifactapi/models"
+	"dbfartifactapi/pkg/auth"
 	"dbfartifactapi/pkg/logger"
 	"dbfartifactapi/services/entity"
 	"dbfartifactapi/utils"
@@ -30,6 +31,8 @@ func SetDBObjectMgtService(srv entity.DBObjectMgtService) {
 // @Success 200 {object} DBObjectMgtListResponse "List of database objects"
 // @Failure 400 {object} StandardErrorResponse "Invalid database management ID"
 // @Failure 500 {object} DatabaseConnectionErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/dbobjectmgt/dbmgt/{id} [get]
 func getDBObjectMgtByDbMgtId(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -60,6 +63,8 @@ func getDBObjectMgtByDbMgtId(c *gin.Context) {
 // @Success 200 {object} DBObjectMgtListResponse "List of database objects"
 // @Failure 400 {object} StandardErrorResponse "Invalid connection management ID"
 // @Failure 500 {object} DatabaseConnectionErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/dbobjectmgt/cntmgt/{id} [get]
 func getDBObjectMgtByCntMgtId(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -90,6 +95,8 @@ func getDBObjectMgtByCntMgtId(c *gin.Context) {
 // @Success 201 {object} DBObjectMgtCreateResponse "Object created successfully"
 // @Failure 400 {object} StandardErrorResponse "Invalid request body or validation error"
 // @Failure 500 {object} ObjectCreationErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/dbobjectmgt [post]
 func createDBObjectMgt(c *gin.Context) {
 	var data models.DBObjectMgt
@@ -128,6 +135,8 @@ func createDBObjectMgt(c *gin.Context) {
 // @Failure 400 {object} StandardErrorResponse "Invalid ID or request body"
 // @Failure 404 {object} StandardErrorResponse "Object not found"
 // @Failure 500 {object} InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/dbobjectmgt/{id} [put]
 func updateDBObjectMgt(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -168,6 +177,8 @@ func updateDBObjectMgt(c *gin.Context) {
 // @Failure 400 {object} StandardErrorResponse "Invalid object management ID"
 // @Failure 404 {object} StandardErrorResponse "Object not found"
 // @Failure 500 {object} InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/dbobjectmgt/{id} [delete]
 func deleteDBObjectMgt(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -196,6 +207,6 @@ func RegisterDBObjectMgtRoutes(rg *gin.RouterGroup) {
 		dbobjectmgt.GET("/cntmgt/:id", getDBObjectMgtByCntMgtId)
 		dbobjectmgt.POST("", createDBObjectMgt)
 		dbobjectmgt.PUT("/:id", updateDBObjectMgt)
-		dbobjectmgt.DELETE("/:id", deleteDBObjectMgt)
+		dbobjectmgt.DELETE("/:id", auth.RequireRole(auth.RolePolicyAdmin), deleteDBObjectMgt)
 	}
 }
@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"dbfartifactapi/models"
+	"dbfartifactapi/pkg/auth"
 	"dbfartifactapi/pkg/logger"
 	"dbfartifactapi/services/dto"
 	"dbfartifactapi/services/policy"
@@ -31,6 +32,8 @@ func SetDBPolicyService(srv policy.DBPolicyService) {
 // @Success 200 {object} JobStartResponse "Background job started message with job ID"
 // @Failure 400 {object} StandardErrorResponse "Invalid connection management ID"
 // @Failure 500 {object} JobProcessingErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/dbpolicy/cntmgt/{cntmgt} [get]
 func getDBPolicyByCntMgt(c *gin.Context) {
 	cntmgt, err := strconv.Atoi(c.Param("cntmgt"))
@@ -51,18 +54,69 @@ func getDBPolicyByCntMgt(c *gin.Context) {
 	})
 }
 
+// GetPrivilegeDrift reports privilege changes between two discovery runs of a connection management
+// @Summary Get privilege drift between discovery runs
+// @Description Compares two stored privilege snapshots of a connection: grants added or revoked, new or removed actors, and policy defaults newly allowed or no longer allowed. Defaults to the latest snapshot compared with the one before it.
+// @Tags DB Policy
+// @Produce json
+// @Param cntmgt path int true "Connection Management ID"
+// @Param from query int false "Baseline snapshot version (default: version before 'to')"
+// @Param to query int false "Target snapshot version (default: latest)"
+// @Success 200 {object} dto.PrivilegeDriftResponse "Privilege drift between the two snapshots"
+// @Failure 400 {object} StandardErrorResponse "Invalid ID, invalid versions or snapshot not found"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/queries/dbpolicy/cntmgt/{cntmgt}/drift [get]
+func getPrivilegeDrift(c *gin.Context) {
+	cntmgt, err := strconv.Atoi(c.Param("cntmgt"))
+	if err != nil {
+		utils.ErrorResponse(c, fmt.Errorf("invalid cntmgt"))
+		return
+	}
+
+	fromVersion, toVersion := 0, 0
+	if from := c.Query("from"); from != "" {
+		if fromVersion, err = strconv.Atoi(from); err != nil {
+			utils.ErrorResponse(c, fmt.Errorf("invalid from version"))
+			return
+		}
+	}
+	if to := c.Query("to"); to != "" {
+		if toVersion, err = strconv.Atoi(to); err != nil {
+			utils.ErrorResponse(c, fmt.Errorf("invalid to version"))
+			return
+		}
+	}
+
+	drift, err := dbPolicySrv.GetPrivilegeDrift(c.Request.Context(), uint(cntmgt), fromVersion, toVersion)
+	if err != nil {
+		logger.Errorf("Failed to get privilege drift for cntmgt %d: %v", cntmgt, err)
+		utils.ErrorResponse(c, err)
+		return
+	}
+	utils.JSONResponse(c, http.StatusOK, drift)
+}
+
 // CreateDBPolicy creates a new database policy
 // @Summary Create database policy
-// @Description Creates a new database policy with specified parameters
+// @Description Creates a new database policy with specified parameters. With dry_run=true, returns the policy and the rendered allow SQL with its target endpoint instead, without contacting the agent or storing the policy.
 // @Tags DB Policy
 // @Accept json
 // @Produce json
 // @Param policy body DBPolicyCreateRequest true "Database Policy object"
+// @Param dry_run query bool false "Preview the change without applying it"
 // @Success 201 {object} DBPolicyCreateResponse "Policy created successfully with ID"
+// @Success 200 {object} dto.PolicyDryRunResponse "Dry run preview"
 // @Failure 400 {object} StandardErrorResponse "Invalid request body or validation error"
 // @Failure 500 {object} PolicyCreationErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/dbpolicy [post]
 func createDBPolicy(c *gin.Context) {
+	dryRun, ok := dryRunRequested(c)
+	if !ok {
+		return
+	}
 	var data models.DBPolicy
 	if err := c.ShouldBindJSON(&data); err != nil {
 		utils.ErrorResponse(c, err)
@@ -73,6 +127,17 @@ func createDBPolicy(c *gin.Context) {
 		return
 	}
 
+	if dryRun {
+		preview, err := dbPolicySrv.PreviewCreate(c.Request.Context(), data)
+		if err != nil {
+			logger.Errorf("Failed to preview policy creation: %v", err)
+			utils.ErrorResponse(c, err)
+			return
+		}
+		utils.JSONResponse(c, http.StatusOK, preview)
+		return
+	}
+
 	logger.Debugf("Creating new policy: %+v", data)
 	newObj, err := dbPolicySrv.Create(c.Request.Context(), data)
 	if err != nil {
@@ -89,22 +154,29 @@ func createDBPolicy(c *gin.Context) {
 
 // UpdateDBPolicy updates an existing database policy
 // @Summary Update database policy
-// @Description Updates an existing database policy by ID
+// @Description Updates an existing database policy by ID. With dry_run=true, returns a PolicyDryRunResponse with the policy before and after and the rendered deny and allow SQL with their target endpoints instead, without contacting the agent or saving the policy.
 // @Tags DB Policy
 // @Accept json
 // @Produce json
 // @Param id path int true "Policy ID"
 // @Param policy body DBPolicyCreateRequest true "Updated Database Policy object"
+// @Param dry_run query bool false "Preview the change without applying it"
 // @Success 200 {object} DBPolicyUpdateResponse "Policy updated successfully"
 // @Failure 400 {object} StandardErrorResponse "Invalid ID or request body"
 // @Failure 404 {object} StandardErrorResponse "Policy not found"
 // @Failure 500 {object} InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/dbpolicy/{id} [put]
 func updateDBPolicy(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		utils.ErrorResponse(c, fmt.Errorf("invalid id"))
 	}
+	dryRun, ok := dryRunRequested(c)
+	if !ok {
+		return
+	}
 	var data models.DBPolicy
 	if err := c.ShouldBindJSON(&data); err != nil {
 		utils.ErrorResponse(c, err)
@@ -115,6 +187,17 @@ func updateDBPolicy(c *gin.Context) {
 		return
 	}
 
+	if dryRun {
+		preview, err := dbPolicySrv.PreviewUpdate(c.Request.Context(), utils.MustIntToUint(id), data)
+		if err != nil {
+			logger.Errorf("Failed to preview update of policy with ID %d: %v", id, err)
+			utils.ErrorResponse(c, err)
+			return
+		}
+		utils.JSONResponse(c, http.StatusOK, preview)
+		return
+	}
+
 	logger.Debugf("Updating policy with ID: %d", id)
 	_, err = dbPolicySrv.Update(c.Request.Context(), utils.MustIntToUint(id), data)
 	if err != nil {
@@ -139,6 +222,8 @@ func updateDBPolicy(c *gin.Context) {
 // @Failure 400 {object} StandardErrorResponse "Invalid ID"
 // @Failure 404 {object} StandardErrorResponse "Policy not found"
 // @Failure 500 {object} InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/dbpolicy/{id} [delete]
 func deleteDBPolicy(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -184,6 +269,8 @@ type BulkDeleteDBPolicyResponse struct {
 // @Success 200 {object} BulkDeleteDBPolicyResponse "Bulk delete completed with success/failure details"
 // @Failure 400 {object} StandardErrorResponse "Invalid request body or validation error"
 // @Failure 500 {object} InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/dbpolicy/bulkdelete [post]
 func bulkDeleteDBPolicies(c *gin.Context) {
 	var req BulkDeleteDBPolicyRequest
@@ -223,17 +310,24 @@ func bulkDeleteDBPolicies(c *gin.Context) {
 
 // BulkUpdatePoliciesByActor performs bulk policy update for a specific database actor
 // @Summary Bulk update database policies
-// @Description Compares existing policies with desired state (Cartesian product of policy_defaults × objects) and executes changes via VeloArtifact background job. Only updates database after successful remote execution to ensure atomic consistency.
+// @Description Compares existing policies with desired state (Cartesian product of policy_defaults × objects) and executes changes via VeloArtifact background job. Only updates database after successful remote execution to ensure atomic consistency. With dry_run=true, returns a BulkPolicyDryRunResponse with the computed diff and the rendered revoke and grant SQL with the target endpoint instead, without starting the job.
 // @Tags DB Policy
 // @Accept json
 // @Produce json
 // @Param request body BulkPolicyUpdateRequest true "Bulk policy update request with actor, policy defaults, and objects"
+// @Param dry_run query bool false "Preview the change without starting the job"
 // @Success 200 {object} BulkPolicyUpdateJobResponse "Background job started with job ID and change summary"
 // @Failure 400 {object} StandardErrorResponse "Invalid request body or validation error"
 // @Failure 404 {object} NotFoundResponse "Referenced entity (cntmgt, dbmgt, actor, policy, object) not found"
 // @Failure 500 {object} InternalServerErrorResponse "Internal server error during job creation or VeloArtifact execution"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/dbpolicy/bulkupdate [post]
 func bulkUpdatePoliciesByActor(c *gin.Context) {
+	dryRun, ok := dryRunRequested(c)
+	if !ok {
+		return
+	}
 	var req dto.BulkPolicyUpdateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		logger.Errorf("Failed to bind bulk policy update request: %v", err)
@@ -250,6 +344,17 @@ func bulkUpdatePoliciesByActor(c *gin.Context) {
 	logger.Infof("Bulk policy update request: cntmgt_id=%d, dbmgt_id=%d, actor_id=%d, policy_defaults=%v, objects=%v",
 		req.CntMgtID, req.DBMgtID, req.DBActorMgtID, req.NewPolicyDefaults, req.NewObjectMgts)
 
+	if dryRun {
+		preview, err := dbPolicySrv.PreviewBulkUpdate(c.Request.Context(), req)
+		if err != nil {
+			logger.Errorf("Failed to preview bulk policy update: %v", err)
+			utils.ErrorResponse(c, err)
+			return
+		}
+		utils.JSONResponse(c, http.StatusOK, preview)
+		return
+	}
+
 	jobMessage, err := dbPolicySrv.BulkUpdatePoliciesByActor(c.Request.Context(), req)
 	if err != nil {
 		logger.Errorf("Failed to start bulk policy update job: %v", err)
@@ -264,15 +369,204 @@ func bulkUpdatePoliciesByActor(c *gin.Context) {
 	})
 }
 
+// GetPolicyConflicts reports conflicting policies of a connection management
+// @Summary Get policy conflicts
+// @Description Reports, per actor, policies that contradict, are shadowed by or repeat another policy of the same policy default: disabled policies overlapping an enabled one or a group grant, enabled policies inside the scope of a wider policy or group grant, and exact repeats. Group grants are the policy defaults of the groups each actor currently belongs to. removable_policy_ids lists disabled repeats of another disabled policy, whose deletion runs no SQL on the database. Enabled rows are never listed: deleting one runs its deny SQL, which also revokes what the covering policy grants.
+// @Tags DB Policy
+// @Produce json
+// @Param cntmgt path int true "Connection Management ID"
+// @Success 200 {object} dto.PolicyConflictReport "Policy conflicts per actor"
+// @Failure 400 {object} StandardErrorResponse "Invalid ID or connection management not found"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/queries/dbpolicy/cntmgt/{cntmgt}/conflicts [get]
+func getPolicyConflicts(c *gin.Context) {
+	cntmgt, err := strconv.Atoi(c.Param("cntmgt"))
+	if err != nil {
+		utils.ErrorResponse(c, fmt.Errorf("invalid cntmgt"))
+		return
+	}
+
+	report, err := dbPolicySrv.AnalyzeConflicts(c.Request.Context(), uint(cntmgt))
+	if err != nil {
+		logger.Errorf("Failed to analyze policy conflicts for cntmgt %d: %v", cntmgt, err)
+		utils.ErrorResponse(c, err)
+		return
+	}
+	utils.JSONResponse(c, http.StatusOK, report)
+}
+
+// ListPolicyRevisions lists the policy revisions of a connection management
+// @Summary List policy revisions
+// @Description Lists the revisions of the policy set of a connection, newest first. Every policy create, update, delete and completed bulk update or rollback adds a revision with its author and the number of policies added and removed. The first change of a connection is preceded by a baseline revision with the policies before it.
+// @Tags DB Policy
+// @Produce json
+// @Param cntmgt path int true "Connection Management ID"
+// @Param page query int false "Page number (default: 1)"
+// @Param page_size query int false "Revisions per page (default: 50, max: 500)"
+// @Success 200 {object} dto.PolicyRevisionListResponse "One page of policy revisions"
+// @Failure 400 {object} StandardErrorResponse "Invalid connection management ID or query"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/queries/dbpolicy/cntmgt/{cntmgt}/revisions [get]
+func listPolicyRevisions(c *gin.Context) {
+	cntmgt, err := strconv.Atoi(c.Param("cntmgt"))
+	if err != nil {
+		utils.ErrorResponse(c, fmt.Errorf("invalid cntmgt"))
+		return
+	}
+	var query dto.PolicyRevisionQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.ErrorResponse(c, fmt.Errorf("invalid query: %v", err))
+		return
+	}
+
+	revisions, err := dbPolicySrv.ListRevisions(c.Request.Context(), uint(cntmgt), query)
+	if err != nil {
+		logger.Errorf("Failed to list policy revisions for cntmgt %d: %v", cntmgt, err)
+		utils.ErrorResponse(c, err)
+		return
+	}
+	utils.JSONResponse(c, http.StatusOK, revisions)
+}
+
+// GetPolicyRevision returns one policy revision of a connection management
+// @Summary Get policy revision
+// @Description Returns one policy revision with its changeset (policies added and removed; an update shows as the old policy removed and the new one added) and the whole policy set of the connection after it.
+// @Tags DB Policy
+// @Produce json
+// @Param cntmgt path int true "Connection Management ID"
+// @Param rev path int true "Revision number"
+// @Success 200 {object} dto.PolicyRevisionDetailResponse "Policy revision"
+// @Failure 400 {object} StandardErrorResponse "Invalid ID or revision not found"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/queries/dbpolicy/cntmgt/{cntmgt}/revisions/{rev} [get]
+func getPolicyRevision(c *gin.Context) {
+	cntmgt, rev, ok := parseRevisionParams(c)
+	if !ok {
+		return
+	}
+
+	result, err := dbPolicySrv.GetRevision(c.Request.Context(), uint(cntmgt), rev)
+	if err != nil {
+		logger.Errorf("Failed to get policy revision %d for cntmgt %d: %v", rev, cntmgt, err)
+		utils.ErrorResponse(c, err)
+		return
+	}
+	utils.JSONResponse(c, http.StatusOK, result)
+}
+
+// DiffPolicyRevisions compares a policy revision with another one
+// @Summary Diff policy revisions
+// @Description Compares the policy set after a revision with the set after another revision of the same connection: policies added, removed, and kept with a different status or description. Defaults to the revision right before it.
+// @Tags DB Policy
+// @Produce json
+// @Param cntmgt path int true "Connection Management ID"
+// @Param rev path int true "Revision number"
+// @Param from query int false "Revision to compare with (default: revision before 'rev')"
+// @Success 200 {object} dto.PolicyRevisionDiffResponse "Policy changes between the two revisions"
+// @Failure 400 {object} StandardErrorResponse "Invalid ID, invalid revisions or revision not found"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/queries/dbpolicy/cntmgt/{cntmgt}/revisions/{rev}/diff [get]
+func diffPolicyRevisions(c *gin.Context) {
+	cntmgt, rev, ok := parseRevisionParams(c)
+	if !ok {
+		return
+	}
+	fromRev := 0
+	if from := c.Query("from"); from != "" {
+		var err error
+		if fromRev, err = strconv.Atoi(from); err != nil {
+			utils.ErrorResponse(c, fmt.Errorf("invalid from revision"))
+			return
+		}
+	}
+
+	diff, err := dbPolicySrv.DiffRevisions(c.Request.Context(), uint(cntmgt), fromRev, rev)
+	if err != nil {
+		logger.Errorf("Failed to diff policy revisions for cntmgt %d: %v", cntmgt, err)
+		utils.ErrorResponse(c, err)
+		return
+	}
+	utils.JSONResponse(c, http.StatusOK, diff)
+}
+
+// RollbackPolicyRevision rolls the policies of a connection management back to a revision
+// @Summary Roll back policies to a revision
+// @Description Compares the current policies of the connection with the policy set after the revision and starts one bulk policy update job per database and actor to grant and revoke the difference through the policies' allow/deny SQL. Each job changes the policy rows only after all its commands succeeded, records a rollback revision and rebuilds the rule files with exportDBFPolicy. Disabled policies are left as they are; connection-wide and wildcard-object policies cannot be applied in bulk and are returned as skipped.
+// @Tags DB Policy
+// @Produce json
+// @Param cntmgt path int true "Connection Management ID"
+// @Param rev path int true "Revision to roll back to"
+// @Success 200 {object} dto.PolicyRollbackResponse "Rollback jobs started"
+// @Failure 400 {object} StandardErrorResponse "Invalid ID, revision not found or job start failed"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/queries/dbpolicy/cntmgt/{cntmgt}/revisions/{rev}/rollback [post]
+func rollbackPolicyRevision(c *gin.Context) {
+	cntmgt, rev, ok := parseRevisionParams(c)
+	if !ok {
+		return
+	}
+
+	logger.Infof("Policy rollback request: cntmgt_id=%d, revision=%d", cntmgt, rev)
+	result, err := dbPolicySrv.RollbackToRevision(c.Request.Context(), uint(cntmgt), rev)
+	if err != nil {
+		logger.Errorf("Failed to roll back cntmgt %d to policy revision %d: %v", cntmgt, rev, err)
+		utils.ErrorResponse(c, err)
+		return
+	}
+	logger.Infof("Policy rollback for cntmgt %d: %s", cntmgt, result.Message)
+	utils.JSONResponse(c, http.StatusOK, result)
+}
+
+// parseRevisionParams reads the cntmgt and rev path parameters, writing the error response when invalid.
+func parseRevisionParams(c *gin.Context) (int, int, bool) {
+	cntmgt, err := strconv.Atoi(c.Param("cntmgt"))
+	if err != nil {
+		utils.ErrorResponse(c, fmt.Errorf("invalid cntmgt"))
+		return 0, 0, false
+	}
+	rev, err := strconv.Atoi(c.Param("rev"))
+	if err != nil {
+		utils.ErrorResponse(c, fmt.Errorf("invalid revision"))
+		return 0, 0, false
+	}
+	return cntmgt, rev, true
+}
+
+// dryRunRequested reports whether the request asks for a preview with ?dry_run=true.
+// It writes a bad request response and returns ok=false when dry_run is not a boolean.
+func dryRunRequested(c *gin.Context) (dryRun bool, ok bool) {
+	value := c.Query("dry_run")
+	if value == "" {
+		return false, true
+	}
+	dryRun, err := strconv.ParseBool(value)
+	if err != nil {
+		utils.ErrorResponse(c, fmt.Errorf("invalid dry_run %q: must be true or false", value))
+		return false, false
+	}
+	return dryRun, true
+}
+
 // RegisterDBPolicyRoutes registers HTTP endpoints for database policy operations.
 func RegisterDBPolicyRoutes(rg *gin.RouterGroup) {
 	dbpolicy := rg.Group("/dbpolicy")
 	{
 		dbpolicy.GET("/cntmgt/:cntmgt", getDBPolicyByCntMgt)
-		dbpolicy.POST("", createDBPolicy)
-		dbpolicy.POST("/bulkupdate", bulkUpdatePoliciesByActor)
-		dbpolicy.POST("/bulkdelete", bulkDeleteDBPolicies)
-		dbpolicy.PUT("/:id", updateDBPolicy)
-		dbpolicy.DELETE("/:id", deleteDBPolicy)
+		dbpolicy.GET("/cntmgt/:cntmgt/drift", getPrivilegeDrift)
+		dbpolicy.GET("/cntmgt/:cntmgt/conflicts", getPolicyConflicts)
+		dbpolicy.GET("/cntmgt/:cntmgt/revisions", listPolicyRevisions)
+		dbpolicy.GET("/cntmgt/:cntmgt/revisions/:rev", getPolicyRevision)
+		dbpolicy.GET("/cntmgt/:cntmgt/revisions/:rev/diff", diffPolicyRevisions)
+		dbpolicy.POST("/cntmgt/:cntmgt/revisions/:rev/rollback", auth.RequireRole(auth.RolePolicyAdmin), rollbackPolicyRevision)
+		dbpolicy.POST("", auth.RequireRole(auth.RolePolicyAdmin), createDBPolicy)
+		dbpolicy.POST("/bulkupdate", auth.RequireRole(auth.RolePolicyAdmin), bulkUpdatePoliciesByActor)
+		dbpolicy.POST("/bulkdelete", auth.RequireRole(auth.RolePolicyAdmin), bulkDeleteDBPolicies)
+		dbpolicy.PUT("/:id", auth.RequireRole(auth.RolePolicyAdmin), updateDBPolicy)
+		dbpolicy.DELETE("/:id", auth.RequireRole(auth.RolePolicyAdmin), deleteDBPolicy)
 	}
 }
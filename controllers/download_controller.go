@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"dbfartifactapi/models"
+	"dbfartifactapi/pkg/auth"
 	"dbfartifactapi/pkg/logger"
 	"dbfartifactapi/services/fileops"
 	"dbfartifactapi/utils"
@@ -47,6 +48,8 @@ type DownloadErrorResponse struct {
 // @Success 200 {object} DownloadJobStartResponse "Download job started successfully with job ID"
 // @Failure 400 {object} DownloadValidationErrorResponse "Invalid request body or validation error"
 // @Failure 500 {object} DownloadErrorResponse "Internal server error during download execution"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/download [post]
 func executeDownload(c *gin.Context) {
 	var req models.DownloadRequest
@@ -82,7 +85,7 @@ func executeDownload(c *gin.Context) {
 
 // RegisterDownloadRoutes registers HTTP endpoints for file download operations.
 func RegisterDownloadRoutes(rg *gin.RouterGroup) {
-	download := rg.Group("/download")
+	download := rg.Group("/download", auth.RequireRole(auth.RoleSuperAdmin))
 	{
 		download.POST("", executeDownload)
 	}
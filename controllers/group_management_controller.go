@@ -1,13 +1,16 @@
 package controllers
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
 
 	"dbfartifactapi/models"
+	"dbfartifactapi/pkg/auth"
 	"dbfartifactapi/pkg/logger"
+	"dbfartifactapi/services/dto"
 	"dbfartifactapi/services/group"
 	"dbfartifactapi/utils"
 
@@ -34,6 +37,8 @@ func SetGroupManagementService(srv group.GroupManagementService) {
 // @Failure 400 {object} ValidationErrorResponse "Invalid request body or validation error"
 // @Failure 409 {object} GroupCodeConflictResponse "Group code already exists"
 // @Failure 500 {object} GroupCreationErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/groups [post]
 func createGroup(c *gin.Context) {
 	var group models.DBGroupMgt
@@ -71,6 +76,8 @@ func createGroup(c *gin.Context) {
 // @Failure 404 {object} GroupNotFoundResponse "Group not found"
 // @Failure 409 {object} GroupCodeConflictResponse "Group code already exists"
 // @Failure 500 {object} GroupCreationErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/groups/{id} [put]
 func updateGroup(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -113,6 +120,8 @@ func updateGroup(c *gin.Context) {
 // @Failure 404 {object} GroupNotFoundResponse "Group not found"
 // @Failure 409 {object} GroupHasChildrenResponse "Group has child groups"
 // @Failure 500 {object} InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/groups/{id} [delete]
 func deleteGroup(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -144,6 +153,8 @@ func deleteGroup(c *gin.Context) {
 // @Failure 400 {object} InvalidGroupIDResponse "Invalid group ID"
 // @Failure 404 {object} GroupNotFoundResponse "Group not found"
 // @Failure 500 {object} InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/groups/{id} [get]
 func getGroupByID(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -172,6 +183,8 @@ func getGroupByID(c *gin.Context) {
 // @Success 200 {array} GroupCreateResponse "List of groups"
 // @Failure 400 {object} ValidationErrorResponse "Invalid database_type_id parameter"
 // @Failure 500 {object} InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/groups [get]
 func getAllGroups(c *gin.Context) {
 	databaseTypeIDStr := c.Query("database_type_id")
@@ -203,17 +216,21 @@ func getAllGroups(c *gin.Context) {
 
 // AssignPoliciesToGroup assigns policies to a group
 // @Summary Assign policies to group
-// @Description Assigns multiple policies to a database group
+// @Description Assigns multiple policies to a database group. With dry_run=true, returns a GroupDryRunResult with the policies that would be assigned without storing them.
 // @Tags Group Management
 // @Accept json
 // @Produce json
 // @Param id path int true "Group ID"
 // @Param policies body PolicyAssignmentRequest true "Policy IDs to assign"
+// @Param dry_run query bool false "Preview the change without applying it"
 // @Success 200 {object} GroupAssignmentResponse "Policies assigned successfully"
+// @Success 202 {object} dto.ChangeRequestDetailResponse "Change held back as a pending change request"
 // @Failure 400 {object} InvalidGroupIDResponse "Invalid group ID"
 // @Failure 400 {object} EmptyListValidationResponse "Empty policy list"
 // @Failure 404 {object} GroupNotFoundResponse "Group not found"
 // @Failure 500 {object} PolicyAssignmentErrorResponse "Policy assignment failed"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/groups/{id}/policies [post]
 func assignPoliciesToGroup(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -221,6 +238,10 @@ func assignPoliciesToGroup(c *gin.Context) {
 		utils.ErrorResponse(c, fmt.Errorf("invalid group ID"))
 		return
 	}
+	dryRun, ok := dryRunRequested(c)
+	if !ok {
+		return
+	}
 
 	var request struct {
 		PolicyIDs []uint `json:"policy_ids" validate:"required,min=1"`
@@ -236,6 +257,20 @@ func assignPoliciesToGroup(c *gin.Context) {
 		return
 	}
 
+	if dryRun {
+		preview, err := groupMgtSrv.PreviewGroupChange(c.Request.Context(), uint(id), group.ChangeAssignPolicies, &group.GroupAssignmentsUpdateRequest{PolicyIDs: request.PolicyIDs})
+		if err != nil {
+			logger.Errorf("Failed to preview policy assignment for group %d: %v", id, err)
+			utils.ErrorResponse(c, err)
+			return
+		}
+		utils.JSONResponse(c, http.StatusOK, preview)
+		return
+	}
+	if approvalRequired(c, uint(id), group.ChangeAssignPolicies, &group.GroupAssignmentsUpdateRequest{PolicyIDs: request.PolicyIDs}) {
+		return
+	}
+
 	if err := groupMgtSrv.AssignPoliciesToGroup(c.Request.Context(), uint(id), request.PolicyIDs); err != nil {
 		logger.Errorf("Failed to assign policies to group %d: %v", id, err)
 		utils.ErrorResponse(c, err)
@@ -250,17 +285,21 @@ func assignPoliciesToGroup(c *gin.Context) {
 
 // RemovePoliciesFromGroup removes policies from a group
 // @Summary Remove policies from group
-// @Description Removes multiple policies from a database group
+// @Description Removes multiple policies from a database group. With dry_run=true, returns a GroupDryRunResult with the policies that would be removed without removing them.
 // @Tags Group Management
 // @Accept json
 // @Produce json
 // @Param id path int true "Group ID"
 // @Param policies body PolicyAssignmentRequest true "Policy IDs to remove"
+// @Param dry_run query bool false "Preview the change without applying it"
 // @Success 200 {object} GroupAssignmentResponse "Policies removed successfully"
+// @Success 202 {object} dto.ChangeRequestDetailResponse "Change held back as a pending change request"
 // @Failure 400 {object} InvalidGroupIDResponse "Invalid group ID"
 // @Failure 400 {object} EmptyListValidationResponse "Empty policy list"
 // @Failure 404 {object} GroupNotFoundResponse "Group not found"
 // @Failure 500 {object} PolicyAssignmentErrorResponse "Policy removal failed"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/groups/{id}/policies [delete]
 func removePoliciesFromGroup(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -268,6 +307,10 @@ func removePoliciesFromGroup(c *gin.Context) {
 		utils.ErrorResponse(c, fmt.Errorf("invalid group ID"))
 		return
 	}
+	dryRun, ok := dryRunRequested(c)
+	if !ok {
+		return
+	}
 
 	var request struct {
 		PolicyIDs []uint `json:"policy_ids" validate:"required,min=1"`
@@ -283,6 +326,20 @@ func removePoliciesFromGroup(c *gin.Context) {
 		return
 	}
 
+	if dryRun {
+		preview, err := groupMgtSrv.PreviewGroupChange(c.Request.Context(), uint(id), group.ChangeRemovePolicies, &group.GroupAssignmentsUpdateRequest{PolicyIDs: request.PolicyIDs})
+		if err != nil {
+			logger.Errorf("Failed to preview policy removal for group %d: %v", id, err)
+			utils.ErrorResponse(c, err)
+			return
+		}
+		utils.JSONResponse(c, http.StatusOK, preview)
+		return
+	}
+	if approvalRequired(c, uint(id), group.ChangeRemovePolicies, &group.GroupAssignmentsUpdateRequest{PolicyIDs: request.PolicyIDs}) {
+		return
+	}
+
 	if err := groupMgtSrv.RemovePoliciesFromGroup(c.Request.Context(), uint(id), request.PolicyIDs); err != nil {
 		logger.Errorf("Failed to remove policies from group %d: %v", id, err)
 		utils.ErrorResponse(c, err)
@@ -306,6 +363,8 @@ func removePoliciesFromGroup(c *gin.Context) {
 // @Failure 400 {object} InvalidGroupIDResponse "Invalid group ID"
 // @Failure 404 {object} GroupNotFoundResponse "Group not found"
 // @Failure 500 {object} InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/groups/{id}/policies [get]
 func getGroupPolicies(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -335,6 +394,8 @@ func getGroupPolicies(c *gin.Context) {
 // @Failure 400 {object} InvalidPolicyIDResponse "Invalid policy ID"
 // @Failure 404 {object} PolicyNotFoundResponse "Policy not found"
 // @Failure 500 {object} InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/policies/{policy_id}/groups [get]
 func getPolicyGroups(c *gin.Context) {
 	policyID, err := strconv.ParseUint(c.Param("policy_id"), 10, 32)
@@ -357,17 +418,21 @@ func getPolicyGroups(c *gin.Context) {
 
 // AssignActorsToGroup assigns actors to a group
 // @Summary Assign actors to group
-// @Description Assigns multiple actors to a database group. Sends VeloArtifact allow commands to clients before updating database. Supports partial success - if some VeloArtifact operations fail, the operation continues with successful ones. Returns detailed execution status including success/failure information for each VeloArtifact job.
+// @Description Assigns multiple actors to a database group. Sends VeloArtifact allow commands to clients before updating database. Supports partial success - if some VeloArtifact operations fail, the operation continues with successful ones. Returns detailed execution status including success/failure information for each VeloArtifact job. With dry_run=true, returns a GroupDryRunResult with the actors that would be assigned and the rendered allow SQL per connection with its target endpoint instead, without contacting the agents or storing the assignment.
 // @Tags Group Management
 // @Accept json
 // @Produce json
 // @Param id path int true "Group ID"
 // @Param actors body ActorAssignmentRequest true "Actor IDs to assign"
+// @Param dry_run query bool false "Preview the change without applying it"
 // @Success 200 {object} ActorAssignmentResult "Actors assigned with detailed execution status"
+// @Success 202 {object} dto.ChangeRequestDetailResponse "Change held back as a pending change request"
 // @Failure 400 {object} InvalidGroupIDResponse "Invalid group ID"
 // @Failure 400 {object} EmptyListValidationResponse "Empty actor list"
 // @Failure 404 {object} GroupNotFoundResponse "Group not found"
 // @Failure 500 {object} ActorAssignmentErrorResponse "All VeloArtifact operations failed or actor assignment failed"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/groups/{id}/actors [post]
 func assignActorsToGroup(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -375,6 +440,10 @@ func assignActorsToGroup(c *gin.Context) {
 		utils.ErrorResponse(c, fmt.Errorf("invalid group ID"))
 		return
 	}
+	dryRun, ok := dryRunRequested(c)
+	if !ok {
+		return
+	}
 
 	var request struct {
 		ActorIDs []uint `json:"actor_ids" validate:"required,min=1"`
@@ -390,6 +459,20 @@ func assignActorsToGroup(c *gin.Context) {
 		return
 	}
 
+	if dryRun {
+		preview, err := groupMgtSrv.PreviewGroupChange(c.Request.Context(), uint(id), group.ChangeAssignActors, &group.GroupAssignmentsUpdateRequest{ActorIDs: request.ActorIDs})
+		if err != nil {
+			logger.Errorf("Failed to preview actor assignment for group %d: %v", id, err)
+			utils.ErrorResponse(c, err)
+			return
+		}
+		utils.JSONResponse(c, http.StatusOK, preview)
+		return
+	}
+	if approvalRequired(c, uint(id), group.ChangeAssignActors, &group.GroupAssignmentsUpdateRequest{ActorIDs: request.ActorIDs}) {
+		return
+	}
+
 	result, err := groupMgtSrv.AssignActorsToGroup(c.Request.Context(), uint(id), request.ActorIDs)
 	if err != nil {
 		logger.Errorf("Failed to assign actors to group %d: %v", id, err)
@@ -404,17 +487,21 @@ func assignActorsToGroup(c *gin.Context) {
 
 // RemoveActorsFromGroup removes actors from a group
 // @Summary Remove actors from group
-// @Description Removes multiple actors from a database group. Sends VeloArtifact deny commands to clients before updating database. Supports partial success - if some VeloArtifact operations fail, the operation continues with successful ones. Returns detailed execution status including success/failure information for each VeloArtifact job.
+// @Description Removes multiple actors from a database group. Sends VeloArtifact deny commands to clients before updating database. Supports partial success - if some VeloArtifact operations fail, the operation continues with successful ones. Returns detailed execution status including success/failure information for each VeloArtifact job. With dry_run=true, returns a GroupDryRunResult with the actors that would be removed and the rendered deny SQL per connection with its target endpoint instead, without contacting the agents or removing the assignment.
 // @Tags Group Management
 // @Accept json
 // @Produce json
 // @Param id path int true "Group ID"
 // @Param actors body ActorAssignmentRequest true "Actor IDs to remove"
+// @Param dry_run query bool false "Preview the change without applying it"
 // @Success 200 {object} ActorRemovalResult "Actors removed with detailed execution status"
+// @Success 202 {object} dto.ChangeRequestDetailResponse "Change held back as a pending change request"
 // @Failure 400 {object} InvalidGroupIDResponse "Invalid group ID"
 // @Failure 400 {object} EmptyListValidationResponse "Empty actor list"
 // @Failure 404 {object} GroupNotFoundResponse "Group not found"
 // @Failure 500 {object} ActorAssignmentErrorResponse "All VeloArtifact operations failed or actor removal failed"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/groups/{id}/actors [delete]
 func removeActorsFromGroup(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -422,6 +509,10 @@ func removeActorsFromGroup(c *gin.Context) {
 		utils.ErrorResponse(c, fmt.Errorf("invalid group ID"))
 		return
 	}
+	dryRun, ok := dryRunRequested(c)
+	if !ok {
+		return
+	}
 
 	var request struct {
 		ActorIDs []uint `json:"actor_ids" validate:"required,min=1"`
@@ -437,6 +528,20 @@ func removeActorsFromGroup(c *gin.Context) {
 		return
 	}
 
+	if dryRun {
+		preview, err := groupMgtSrv.PreviewGroupChange(c.Request.Context(), uint(id), group.ChangeRemoveActors, &group.GroupAssignmentsUpdateRequest{ActorIDs: request.ActorIDs})
+		if err != nil {
+			logger.Errorf("Failed to preview actor removal for group %d: %v", id, err)
+			utils.ErrorResponse(c, err)
+			return
+		}
+		utils.JSONResponse(c, http.StatusOK, preview)
+		return
+	}
+	if approvalRequired(c, uint(id), group.ChangeRemoveActors, &group.GroupAssignmentsUpdateRequest{ActorIDs: request.ActorIDs}) {
+		return
+	}
+
 	result, err := groupMgtSrv.RemoveActorsFromGroup(c.Request.Context(), uint(id), request.ActorIDs)
 	if err != nil {
 		logger.Errorf("Failed to remove actors from group %d: %v", id, err)
@@ -460,6 +565,8 @@ func removeActorsFromGroup(c *gin.Context) {
 // @Failure 400 {object} InvalidGroupIDResponse "Invalid group ID"
 // @Failure 404 {object} GroupNotFoundResponse "Group not found"
 // @Failure 500 {object} InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/groups/{id}/actors [get]
 func getGroupActors(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -489,6 +596,8 @@ func getGroupActors(c *gin.Context) {
 // @Failure 400 {object} InvalidActorIDResponse "Invalid actor ID"
 // @Failure 404 {object} ActorNotFoundResponse "Actor not found"
 // @Failure 500 {object} InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/actors/{actor_id}/groups [get]
 func getActorGroups(c *gin.Context) {
 	actorID, err := strconv.ParseUint(c.Param("actor_id"), 10, 32)
@@ -518,6 +627,8 @@ func getActorGroups(c *gin.Context) {
 // @Failure 400 {object} InvalidGroupIDResponse "Invalid group ID"
 // @Failure 404 {object} GroupNotFoundResponse "Group not found"
 // @Failure 500 {object} InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/groups/{id}/details [get]
 func getGroupDetails(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -538,16 +649,20 @@ func getGroupDetails(c *gin.Context) {
 
 // UpdateGroupAssignments performs optimized bulk update of group assignments
 // @Summary Update group assignments (optimized)
-// @Description Updates both policies and actors for a group with minimal VeloArtifact executions. This is the optimized alternative to individual assignment operations.
+// @Description Updates both policies and actors for a group with minimal VeloArtifact executions. This is the optimized alternative to individual assignment operations. With dry_run=true, returns a GroupDryRunResult with the computed diff and the rendered allow/deny SQL per connection with its target endpoint instead, without contacting the agents or storing the assignments.
 // @Tags Group Management
 // @Accept json
 // @Produce json
 // @Param id path int true "Group ID"
 // @Param request body GroupAssignmentsUpdateRequest true "New policy and actor assignments"
+// @Param dry_run query bool false "Preview the change without applying it"
 // @Success 200 {object} GroupAssignmentsUpdateResult "Group assignments updated successfully with optimization details"
+// @Success 202 {object} dto.ChangeRequestDetailResponse "Change held back as a pending change request"
 // @Failure 400 {object} InvalidGroupIDResponse "Invalid group ID or request"
 // @Failure 404 {object} GroupNotFoundResponse "Group not found"
 // @Failure 500 {object} InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/groups/{id}/assignments [put]
 func updateGroupAssignments(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -555,6 +670,10 @@ func updateGroupAssignments(c *gin.Context) {
 		utils.ErrorResponse(c, fmt.Errorf("invalid group ID"))
 		return
 	}
+	dryRun, ok := dryRunRequested(c)
+	if !ok {
+		return
+	}
 
 	var request group.GroupAssignmentsUpdateRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
@@ -562,6 +681,20 @@ func updateGroupAssignments(c *gin.Context) {
 		return
 	}
 
+	if dryRun {
+		preview, err := groupMgtSrv.PreviewGroupChange(c.Request.Context(), uint(id), group.ChangeUpdateAssignments, &request)
+		if err != nil {
+			logger.Errorf("Failed to preview assignments update for group %d: %v", id, err)
+			utils.ErrorResponse(c, err)
+			return
+		}
+		utils.JSONResponse(c, http.StatusOK, preview)
+		return
+	}
+	if approvalRequired(c, uint(id), group.ChangeUpdateAssignments, &request) {
+		return
+	}
+
 	result, err := groupMgtSrv.UpdateGroupAssignments(c.Request.Context(), uint(id), &request)
 	if err != nil {
 		logger.Errorf("Failed to update group assignments for %d: %v", id, err)
@@ -579,18 +712,21 @@ func updateGroupAssignments(c *gin.Context) {
 
 // BulkAssignPolicyToGroups assigns a policy to multiple groups
 // @Summary Bulk assign policy to groups
-// @Description Assigns a single policy to multiple database groups. Returns partial success if some groups fail.
+// @Description Assigns a single policy to multiple database groups. Returns partial success if some groups fail. Groups where the assignment needs approval get a pending change request each, listed in change_requests.
 // @Tags Group Management
 // @Accept json
 // @Produce json
 // @Param policy_id path int true "Policy ID"
 // @Param groups body GroupAssignmentRequest true "Group IDs to assign policy to"
 // @Success 200 {object} GroupBulkAssignmentResponse "Policy assigned to all groups successfully"
+// @Success 202 {object} GroupBulkAssignmentResponse "Policy assigned to the other groups, some held back for approval"
 // @Success 206 {object} GroupBulkAssignmentResponse "Policy assigned to some groups (partial success)"
 // @Failure 400 {object} InvalidPolicyIDResponse "Invalid policy ID"
 // @Failure 400 {object} EmptyListValidationResponse "Empty group list"
 // @Failure 404 {object} PolicyNotFoundResponse "Policy not found"
 // @Failure 500 {object} PolicyAssignmentErrorResponse "Policy assignment failed"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/policies/{policy_id}/assign-groups [post]
 func bulkAssignPolicyToGroups(c *gin.Context) {
 	policyID, err := strconv.ParseUint(c.Param("policy_id"), 10, 32)
@@ -615,8 +751,18 @@ func bulkAssignPolicyToGroups(c *gin.Context) {
 
 	successCount := 0
 	var errors []string
+	var changeRequests []uint
 
 	for _, groupID := range request.GroupIDs {
+		pending, err := gateGroupChange(c.Request.Context(), groupID, group.ChangeAssignPolicies, &group.GroupAssignmentsUpdateRequest{PolicyIDs: []uint{uint(policyID)}})
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("Group %d: %v", groupID, err))
+			continue
+		}
+		if pending != nil {
+			changeRequests = append(changeRequests, pending.ID)
+			continue
+		}
 		if err := groupMgtSrv.AssignPoliciesToGroup(c.Request.Context(), groupID, []uint{uint(policyID)}); err != nil {
 			errors = append(errors, fmt.Sprintf("Group %d: %v", groupID, err))
 		} else {
@@ -627,10 +773,19 @@ func bulkAssignPolicyToGroups(c *gin.Context) {
 	if len(errors) > 0 {
 		logger.Errorf("Bulk assign policy %d partially failed: %s", policyID, strings.Join(errors, "; "))
 		utils.JSONResponse(c, http.StatusPartialContent, gin.H{
-			"message":       fmt.Sprintf("Policy assigned to %d/%d groups", successCount, len(request.GroupIDs)),
-			"success_count": successCount,
-			"total_count":   len(request.GroupIDs),
-			"errors":        errors,
+			"message":         fmt.Sprintf("Policy assigned to %d/%d groups", successCount, len(request.GroupIDs)),
+			"success_count":   successCount,
+			"total_count":     len(request.GroupIDs),
+			"errors":          errors,
+			"change_requests": changeRequests,
+		})
+	} else if len(changeRequests) > 0 {
+		logger.Infof("Assigned policy %d to %d groups, %d held back for approval", policyID, successCount, len(changeRequests))
+		utils.JSONResponse(c, http.StatusAccepted, gin.H{
+			"message":         fmt.Sprintf("Policy assigned to %d/%d groups, %d awaiting approval", successCount, len(request.GroupIDs), len(changeRequests)),
+			"success_count":   successCount,
+			"total_count":     len(request.GroupIDs),
+			"change_requests": changeRequests,
 		})
 	} else {
 		logger.Infof("Successfully assigned policy %d to %d groups", policyID, successCount)
@@ -641,27 +796,85 @@ func bulkAssignPolicyToGroups(c *gin.Context) {
 	}
 }
 
+// ListMembershipTransitions returns the outcomes of time-bound membership enforcement
+// @Summary List membership transitions
+// @Description Returns the grants and revocations the validity enforcement scheduler made when memberships in dbactor_groups and dbpolicy_groups reached their valid_from or valid_until, most recently attempted first. Failed transitions are retried on every pass; attempts counts the passes so far. Returns an error while VALIDITY_ENFORCEMENT_ENABLED is false and the table has never been created.
+// @Tags Group Management
+// @Produce json
+// @Param status query string false "Status: applied, partial or failed"
+// @Param page query int false "Page number (default: 1)"
+// @Param page_size query int false "Transitions per page (default: 50, max: 500)"
+// @Success 200 {object} dto.MembershipTransitionListResponse "Page of membership transitions"
+// @Failure 400 {object} StandardErrorResponse "Invalid filter"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/queries/membership-transitions [get]
+func listMembershipTransitions(c *gin.Context) {
+	var query dto.MembershipTransitionQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.ErrorResponse(c, fmt.Errorf("invalid membership transition filter: %v", err))
+		return
+	}
+
+	result, err := groupMgtSrv.ListMembershipTransitions(c.Request.Context(), query)
+	if err != nil {
+		utils.ErrorResponse(c, err)
+		return
+	}
+	utils.JSONResponse(c, http.StatusOK, result)
+}
+
+// approvalRequired holds a group change back as a pending change request when it needs approval
+// and writes the 202 response. Returns true when the handler must stop, including when the
+// approval check failed and the error response was written.
+func approvalRequired(c *gin.Context, groupID uint, change string, request *group.GroupAssignmentsUpdateRequest) bool {
+	pending, err := gateGroupChange(c.Request.Context(), groupID, change, request)
+	if err != nil {
+		logger.Errorf("Failed to check approval of %s for group %d: %v", change, groupID, err)
+		utils.ErrorResponse(c, err)
+		return true
+	}
+	if pending == nil {
+		return false
+	}
+	utils.JSONResponse(c, http.StatusAccepted, pending)
+	return true
+}
+
+// gateGroupChange returns the pending change request a group change was stored as, or nil
+// when the change can be applied right away.
+func gateGroupChange(ctx context.Context, groupID uint, change string, request *group.GroupAssignmentsUpdateRequest) (*dto.ChangeRequestDetailResponse, error) {
+	if changeRequestSrv == nil || !changeRequestSrv.Enabled() {
+		return nil, nil
+	}
+	described, err := groupMgtSrv.DescribeChange(ctx, groupID, change, request)
+	if err != nil {
+		return nil, err
+	}
+	return changeRequestSrv.Gate(ctx, *described)
+}
+
 // RegisterGroupManagementRoutes registers HTTP endpoints for group management operations.
 func RegisterGroupManagementRoutes(rg *gin.RouterGroup) {
 	// Group CRUD operations
 	groups := rg.Group("/groups")
 	{
-		groups.POST("", createGroup)
+		groups.POST("", auth.RequireRole(auth.RolePolicyAdmin), createGroup)
 		groups.GET("", getAllGroups)
 		groups.GET("/:id", getGroupByID)
-		groups.PUT("/:id", updateGroup)
-		groups.DELETE("/:id", deleteGroup)
+		groups.PUT("/:id", auth.RequireRole(auth.RolePolicyAdmin), updateGroup)
+		groups.DELETE("/:id", auth.RequireRole(auth.RolePolicyAdmin), deleteGroup)
 		groups.GET("/:id/details", getGroupDetails)
-		groups.PUT("/:id/assignments", updateGroupAssignments)
+		groups.PUT("/:id/assignments", auth.RequireRole(auth.RolePolicyAdmin), updateGroupAssignments)
 
 		// Policy assignments for groups
-		groups.POST("/:id/policies", assignPoliciesToGroup)
-		groups.DELETE("/:id/policies", removePoliciesFromGroup)
+		groups.POST("/:id/policies", auth.RequireRole(auth.RolePolicyAdmin), assignPoliciesToGroup)
+		groups.DELETE("/:id/policies", auth.RequireRole(auth.RolePolicyAdmin), removePoliciesFromGroup)
 		groups.GET("/:id/policies", getGroupPolicies)
 
 		// Actor assignments for groups
-		groups.POST("/:id/actors", assignActorsToGroup)
-		groups.DELETE("/:id/actors", removeActorsFromGroup)
+		groups.POST("/:id/actors", auth.RequireRole(auth.RolePolicyAdmin), assignActorsToGroup)
+		groups.DELETE("/:id/actors", auth.RequireRole(auth.RolePolicyAdmin), removeActorsFromGroup)
 		groups.GET("/:id/actors", getGroupActors)
 	}
 
@@ -669,7 +882,7 @@ func RegisterGroupManagementRoutes(rg *gin.RouterGroup) {
 	policies := rg.Group("/policies")
 	{
 		policies.GET("/:policy_id/groups", getPolicyGroups)
-		policies.POST("/:policy_id/assign-groups", bulkAssignPolicyToGroups)
+		policies.POST("/:policy_id/assign-groups", auth.RequireRole(auth.RolePolicyAdmin), bulkAssignPolicyToGroups)
 	}
 
 	// Actor-centric routes
@@ -677,4 +890,7 @@ func RegisterGroupManagementRoutes(rg *gin.RouterGroup) {
 	{
 		actors.GET("/:actor_id/groups", getActorGroups)
 	}
+
+	// Outcomes of granting and revoking time-bound memberships
+	rg.GET("/membership-transitions", listMembershipTransitions)
 }
@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"net/http"
+
+	"dbfartifactapi/pkg/auth"
+	"dbfartifactapi/services/dto"
+	"dbfartifactapi/services/health"
+	"dbfartifactapi/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+var healthSrv health.HealthService
+
+// SetHealthService initializes the dependency health service instance.
+// Used for dependency injection in tests to provide mock implementations.
+func SetHealthService(s health.HealthService) {
+	healthSrv = s
+}
+
+// Liveness reports that the process is serving requests
+// @Summary Liveness probe
+// @Description Returns 200 while the process is serving HTTP requests. Does not check dependencies, so a failing database does not get the pod restarted.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} dto.LivenessResponse "Process is alive"
+// @Router /healthz [get]
+func liveness(c *gin.Context) {
+	utils.JSONResponse(c, http.StatusOK, dto.LivenessResponse{Status: dto.HealthStatusOK})
+}
+
+// Readiness reports whether the critical dependencies are available
+// @Summary Readiness probe
+// @Description Checks the config database, bootstrap caches, agent API, exportDBFPolicy binary and working directories. Returns 503 when a critical check fails; failures of non-critical checks report status degraded with 200.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} dto.ReadinessResponse "Ready to serve traffic"
+// @Failure 503 {object} dto.ReadinessResponse "A critical dependency is unavailable"
+// @Router /readyz [get]
+func readiness(c *gin.Context) {
+	result := healthSrv.Readiness(c.Request.Context())
+	status := http.StatusOK
+	if result.Status == dto.HealthStatusUnavailable {
+		status = http.StatusServiceUnavailable
+	}
+	utils.JSONResponse(c, status, result)
+}
+
+// Diagnostics returns every dependency check with its latency and error
+// @Summary Dependency diagnostics
+// @Description Runs all dependency checks and reports each with its target, latency, detail and error. Always returns 200; the overall status is ok, degraded or unavailable.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} dto.DiagnosticsResponse "Dependency check results"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/diagnostics [get]
+func diagnostics(c *gin.Context) {
+	utils.JSONResponse(c, http.StatusOK, healthSrv.Diagnostics(c.Request.Context()))
+}
+
+// RegisterHealthRoutes registers the unauthenticated liveness and readiness probes.
+func RegisterHealthRoutes(router *gin.Engine) {
+	router.GET("/healthz", liveness)
+	router.GET("/readyz", readiness)
+}
+
+// RegisterDiagnosticsRoutes registers the detailed dependency report.
+func RegisterDiagnosticsRoutes(rg *gin.RouterGroup) {
+	rg.GET("/diagnostics", auth.RequireRole(auth.RoleOperator), diagnostics)
+}
@@ -0,0 +1,218 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"dbfartifactapi/pkg/logger"
+	"dbfartifactapi/services/job"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+// jobEventsKeepAliveInterval keeps idle SSE connections open through proxies
+const jobEventsKeepAliveInterval = 15 * time.Second
+
+var (
+	// jobEventsOrigins are the normalized Origin values allowed to open job event WebSockets
+	jobEventsOrigins = map[string]bool{}
+	// jobEventsAllowNoOrigin accepts WebSocket clients that send no Origin header
+	jobEventsAllowNoOrigin bool
+)
+
+// SetJobEventsOrigins sets the browser origins ("scheme://host[:port]") allowed to open job event
+// WebSockets, and whether clients sending no Origin header are accepted. Every origin is rejected
+// until it is called.
+func SetJobEventsOrigins(origins []string, allowNoOrigin bool) {
+	allowed := make(map[string]bool, len(origins))
+	for _, origin := range origins {
+		if parsed, err := url.Parse(strings.TrimSpace(origin)); err == nil && parsed.Host != "" {
+			allowed[normalizeOrigin(parsed)] = true
+		} else {
+			logger.Warnf("Ignoring invalid job events origin %q", origin)
+		}
+	}
+	jobEventsOrigins = allowed
+	jobEventsAllowNoOrigin = allowNoOrigin
+}
+
+// checkJobEventsOrigin is the WebSocket handshake check. Browsers always send Origin, so checking it
+// against the allow-list stops other sites from opening a stream with the user's credentials.
+func checkJobEventsOrigin(config *websocket.Config, req *http.Request) error {
+	origin, err := websocket.Origin(config, req)
+	if err != nil {
+		return err
+	}
+	if origin == nil {
+		if jobEventsAllowNoOrigin {
+			return nil
+		}
+		return fmt.Errorf("missing Origin header")
+	}
+	if !jobEventsOrigins[normalizeOrigin(origin)] {
+		return fmt.Errorf("origin %s is not allowed", origin)
+	}
+	config.Origin = origin
+	return nil
+}
+
+func normalizeOrigin(origin *url.URL) string {
+	return strings.ToLower(origin.Scheme + "://" + origin.Host)
+}
+
+// StreamJobEvents streams live job state transitions as Server-Sent Events
+// @Summary Stream job events (SSE)
+// @Description Stream job status, results and sub-step progress events as Server-Sent Events. The first event is a snapshot of current job state; the stream ends after a completed, failed, cancelled or interrupted event.
+// @Tags job-status
+// @Produce text/event-stream
+// @Param job_id path string true "Job ID"
+// @Success 200 {object} job.JobEvent
+// @Failure 400 {object} JobStatusErrorResponse
+// @Failure 404 {object} JobNotFoundErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/jobs/{job_id}/events [get]
+func (jsc *JobStatusController) StreamJobEvents(c *gin.Context) {
+	jobID := c.Param("job_id")
+	if jobID == "" {
+		logger.Warnf("Empty job_id provided for job event stream")
+		c.JSON(http.StatusBadRequest, JobStatusResponse{
+			Success: false,
+			Message: "Job ID is required",
+		})
+		return
+	}
+
+	events, unsubscribe, err := jsc.jobMonitor.SubscribeJobEvents(jobID)
+	if err != nil {
+		if errors.Is(err, job.ErrJobNotFound) {
+			c.JSON(http.StatusNotFound, JobStatusResponse{
+				Success: false,
+				Message: "Job not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, JobStatusResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+	defer unsubscribe()
+
+	logger.Debugf("SSE subscriber attached to job %s", jobID)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	keepAlive := time.NewTicker(jobEventsKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case <-keepAlive.C:
+			if _, err := io.WriteString(w, ": keep-alive\n\n"); err != nil {
+				return false
+			}
+			return true
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			c.SSEvent(event.Type, event)
+			return !event.IsTerminal()
+		}
+	})
+
+	logger.Debugf("SSE subscriber detached from job %s", jobID)
+}
+
+// StreamJobEventsWebSocket streams live job state transitions over a WebSocket
+// @Summary Stream job events (WebSocket)
+// @Description WebSocket variant of the job event stream. Each message is a JSON job event; the server closes the connection after a completed, failed, cancelled or interrupted event. The Origin header must be in JOB_EVENTS_ALLOWED_ORIGINS; otherwise the handshake is answered with 403.
+// @Tags job-status
+// @Param job_id path string true "Job ID"
+// @Success 101 {object} job.JobEvent
+// @Failure 400 {object} JobStatusErrorResponse
+// @Failure 403 {string} string "Origin not allowed"
+// @Failure 404 {object} JobNotFoundErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/jobs/{job_id}/ws [get]
+func (jsc *JobStatusController) StreamJobEventsWebSocket(c *gin.Context) {
+	jobID := c.Param("job_id")
+	if jobID == "" {
+		logger.Warnf("Empty job_id provided for job event websocket")
+		c.JSON(http.StatusBadRequest, JobStatusResponse{
+			Success: false,
+			Message: "Job ID is required",
+		})
+		return
+	}
+
+	// Subscribe before upgrading so unknown jobs get a plain 404
+	events, unsubscribe, err := jsc.jobMonitor.SubscribeJobEvents(jobID)
+	if err != nil {
+		if errors.Is(err, job.ErrJobNotFound) {
+			c.JSON(http.StatusNotFound, JobStatusResponse{
+				Success: false,
+				Message: "Job not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, JobStatusResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+	defer unsubscribe()
+
+	// A failed origin check answers 403 Forbidden before the upgrade
+	server := websocket.Server{
+		Handshake: checkJobEventsOrigin,
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+			logger.Debugf("WebSocket subscriber attached to job %s", jobID)
+
+			// Client messages are ignored; reading detects disconnects
+			closed := make(chan struct{})
+			go func() {
+				defer close(closed)
+				var discard string
+				for websocket.Message.Receive(ws, &discard) == nil {
+				}
+			}()
+
+			for {
+				select {
+				case <-closed:
+					logger.Debugf("WebSocket subscriber disconnected from job %s", jobID)
+					return
+				case event, ok := <-events:
+					if !ok {
+						return
+					}
+					if err := websocket.JSON.Send(ws, event); err != nil {
+						logger.Debugf("Failed to send event to WebSocket subscriber of job %s: %v", jobID, err)
+						return
+					}
+					if event.IsTerminal() {
+						return
+					}
+				}
+			}
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -56,6 +57,8 @@ type PaginationMetadata struct {
 // @Success 200 {object} JobStatusSingleResponse
 // @Failure 400 {object} JobStatusErrorResponse
 // @Failure 404 {object} JobNotFoundErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/jobs/{job_id}/status [get]
 func (jsc *JobStatusController) GetJobStatus(c *gin.Context) {
 	jobID := c.Param("job_id")
@@ -95,6 +98,8 @@ func (jsc *JobStatusController) GetJobStatus(c *gin.Context) {
 // @Param page query int false "Page number (1-indexed, optional)"
 // @Param page_size query int false "Number of items per page (optional, default: 10)"
 // @Success 200 {object} JobStatusListResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/jobs/status [get]
 func (jsc *JobStatusController) GetAllJobs(c *gin.Context) {
 	pageStr := c.Query("page")
@@ -159,6 +164,8 @@ func (jsc *JobStatusController) GetAllJobs(c *gin.Context) {
 // @Param dbmgt_id path int true "Database Management ID"
 // @Success 200 {object} JobStatusListResponse
 // @Failure 400 {object} JobStatusErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/jobs/dbmgt/{dbmgt_id}/status [get]
 func (jsc *JobStatusController) GetJobsByDBMgt(c *gin.Context) {
 	dbmgtIDStr := c.Param("dbmgt_id")
@@ -200,7 +207,7 @@ type JobNotificationRequest struct {
 
 // NotifyJobCompletion allows external services to notify about job completion
 // @Summary Notify job completion
-// @Description Allow external services to report job completion with file data
+// @Description Called by the agent running the job to report completion with file data. Authenticated with the agent's X-Agent-Secret, not user credentials.
 // @Tags job-status
 // @Accept json
 // @Produce json
@@ -208,7 +215,9 @@ type JobNotificationRequest struct {
 // @Param notification body JobNotificationRequest true "Job completion notification data"
 // @Success 200 {object} JobStatusResponse
 // @Failure 400 {object} JobStatusErrorResponse
+// @Failure 401 {object} JobStatusErrorResponse
 // @Failure 404 {object} JobNotFoundErrorResponse
+// @Security AgentSecret
 // @Router /api/jobs/{job_id}/notify [post]
 func (jsc *JobStatusController) NotifyJobCompletion(c *gin.Context) {
 	jobID := c.Param("job_id")
@@ -274,6 +283,8 @@ func (jsc *JobStatusController) NotifyJobCompletion(c *gin.Context) {
 // @Success 200 {object} JobDeleteResponse
 // @Failure 400 {object} JobStatusErrorResponse
 // @Failure 404 {object} JobNotFoundErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/jobs/{job_id} [delete]
 func (jsc *JobStatusController) DeleteJob(c *gin.Context) {
 	jobID := c.Param("job_id")
@@ -306,6 +317,65 @@ func (jsc *JobStatusController) DeleteJob(c *gin.Context) {
 	})
 }
 
+// CancelJob cancels a running job
+// @Summary Cancel job
+// @Description Cancel a running job: the agent is asked to stop execution and any server-side processing is rolled back. Jobs that change the database on the agent (bulk policy updates) answer 409 once the agent has run them or when the agent does not confirm the cancel, so changes the agent applied are always recorded.
+// @Tags job-status
+// @Accept json
+// @Produce json
+// @Param job_id path string true "Job ID"
+// @Success 200 {object} JobCancelResponse
+// @Failure 400 {object} JobStatusErrorResponse
+// @Failure 404 {object} JobNotFoundErrorResponse
+// @Failure 409 {object} JobNotCancellableErrorResponse
+// @Failure 500 {object} JobStatusErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/jobs/{job_id}/cancel [post]
+func (jsc *JobStatusController) CancelJob(c *gin.Context) {
+	jobID := c.Param("job_id")
+	if jobID == "" {
+		logger.Warnf("Empty job_id provided for job cancellation")
+		c.JSON(http.StatusBadRequest, JobStatusResponse{
+			Success: false,
+			Message: "Job ID is required",
+		})
+		return
+	}
+
+	cancelledJob, err := jsc.jobMonitor.CancelJob(jobID)
+	if err != nil {
+		switch {
+		case errors.Is(err, job.ErrJobNotFound):
+			logger.Warnf("Attempted to cancel non-existent job: %s", jobID)
+			c.JSON(http.StatusNotFound, JobStatusResponse{
+				Success: false,
+				Message: "Job not found",
+			})
+		case errors.Is(err, job.ErrJobNotCancellable):
+			logger.Warnf("Job %s cannot be cancelled: %v", jobID, err)
+			c.JSON(http.StatusConflict, JobStatusResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+		default:
+			logger.Errorf("Failed to cancel job %s: %v", jobID, err)
+			c.JSON(http.StatusInternalServerError, JobStatusResponse{
+				Success: false,
+				Message: fmt.Sprintf("Failed to cancel job: %v", err),
+			})
+		}
+		return
+	}
+
+	logger.Infof("Job %s cancelled", jobID)
+	c.JSON(http.StatusOK, JobStatusResponse{
+		Success: true,
+		Message: "Job cancelled successfully",
+		Data:    cancelledJob,
+	})
+}
+
 // RegisterJobStatusRoutes registers all job status routes
 func RegisterJobStatusRoutes(router *gin.RouterGroup) {
 	controller := NewJobStatusController()
@@ -315,7 +385,17 @@ func RegisterJobStatusRoutes(router *gin.RouterGroup) {
 		jobRoutes.GET("/:job_id/status", controller.GetJobStatus)
 		jobRoutes.GET("/status", controller.GetAllJobs)
 		jobRoutes.GET("/dbmgt/:dbmgt_id/status", controller.GetJobsByDBMgt)
-		jobRoutes.POST("/:job_id/notify", controller.NotifyJobCompletion)
+		jobRoutes.POST("/:job_id/cancel", controller.CancelJob)
+		jobRoutes.GET("/:job_id/events", controller.StreamJobEvents)
+		jobRoutes.GET("/:job_id/ws", controller.StreamJobEventsWebSocket)
 		jobRoutes.DELETE("/:job_id", controller.DeleteJob)
 	}
 }
+
+// RegisterAgentCallbackRoutes registers the routes agents call back on. router must carry agent
+// authentication instead of the user authentication chain.
+func RegisterAgentCallbackRoutes(router *gin.RouterGroup) {
+	controller := NewJobStatusController()
+
+	router.POST("/jobs/:job_id/notify", controller.NotifyJobCompletion)
+}
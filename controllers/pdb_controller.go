@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"dbfartifactapi/pkg/auth"
 	"dbfartifactapi/pkg/logger"
 	"dbfartifactapi/services/pdb"
 	"dbfartifactapi/utils"
@@ -30,6 +31,8 @@ func SetPDBService(s pdb.PDBService) {
 // @Success 200 {object} BulkCreateResponse "PDBs synchronized successfully with count"
 // @Failure 400 {object} StandardErrorResponse "Invalid connection management ID"
 // @Failure 500 {object} DatabaseCreationErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/pdb/all [post]
 func postPDBAll(c *gin.Context) {
 	var params struct {
@@ -67,6 +70,8 @@ func postPDBAll(c *gin.Context) {
 // @Success 201 {object} PDBCreateResponse "PDB created successfully"
 // @Failure 400 {object} StandardErrorResponse "Invalid request body or validation error"
 // @Failure 500 {object} DatabaseCreationErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/pdb [post]
 func createPDB(c *gin.Context) {
 	var req pdb.PDBCreateRequest
@@ -105,6 +110,8 @@ func createPDB(c *gin.Context) {
 // @Failure 400 {object} StandardErrorResponse "Invalid ID or request body"
 // @Failure 404 {object} StandardErrorResponse "PDB not found"
 // @Failure 500 {object} InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/pdb/{id} [put]
 func updatePDB(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -147,6 +154,8 @@ func updatePDB(c *gin.Context) {
 // @Failure 400 {object} StandardErrorResponse "Invalid PDB connection management ID"
 // @Failure 404 {object} StandardErrorResponse "PDB not found"
 // @Failure 500 {object} InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/pdb/{id} [delete]
 func deletePDB(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -181,6 +190,6 @@ func RegisterPDBRoutes(rg *gin.RouterGroup) {
 		pdb.POST("/all", postPDBAll)
 		pdb.POST("", createPDB)
 		pdb.PUT("/:id", updatePDB)
-		pdb.DELETE("/:id", deletePDB)
+		pdb.DELETE("/:id", auth.RequireRole(auth.RoleSuperAdmin), deletePDB)
 	}
 }
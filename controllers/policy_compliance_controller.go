@@ -28,6 +28,8 @@ func SetPolicyComplianceService(srv compliance.PolicyComplianceService) {
 // @Success 200 {object} map[string]string "Policy compliance check started"
 // @Failure 400 {object} map[string]string "Invalid connection management ID"
 // @Failure 500 {object} map[string]string "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/policy-compliance/start/{id} [post]
 func startPolicyComplianceCheck(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"dbfartifactapi/pkg/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestRouteRoles_DDLNeedsPolicyAdmin tests that routes sending CREATE/ALTER statements to the target database
+// reject operators before reaching the handler
+func TestRouteRoles_DDLNeedsPolicyAdmin(t *testing.T) {
+	keys, err := auth.ParseAPIKeys([]string{"ops:operator:op-key"})
+	if err != nil {
+		t.Fatalf("ParseAPIKeys: %v", err)
+	}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	api := router.Group("/api/queries", auth.Middleware(auth.NewAPIKeyAuthenticator(keys)))
+	RegisterDBActorMgtRoutes(api)
+	RegisterDBMgtRoutes(api)
+
+	routes := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodPost, "/api/queries/dbactormgt"},
+		{http.MethodPost, "/api/queries/dbactormgt/all"},
+		{http.MethodPut, "/api/queries/dbactormgt/1"},
+		{http.MethodPost, "/api/queries/dbmgt"},
+	}
+	for _, route := range routes {
+		req := httptest.NewRequest(route.method, route.path, nil)
+		req.Header.Set("X-API-Key", "op-key")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusForbidden {
+			t.Errorf("%s %s as operator: status = %d, want %d", route.method, route.path, w.Code, http.StatusForbidden)
+		}
+	}
+}
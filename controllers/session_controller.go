@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"dbfartifactapi/pkg/auth"
 	"dbfartifactapi/pkg/logger"
 	"dbfartifactapi/services/session"
 	"dbfartifactapi/utils"
@@ -35,6 +36,8 @@ type KillSessionRequest struct {
 // @Success 200 {object} JobStartResponse "Kill session command executed successfully for session ID on connection ID"
 // @Failure 400 {object} StandardErrorResponse "Invalid request parameters"
 // @Failure 500 {object} JobProcessingErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/session/kill/{cntid} [post]
 func killSession(c *gin.Context) {
 	cntid, err := strconv.Atoi(c.Param("cntid"))
@@ -68,7 +71,7 @@ func killSession(c *gin.Context) {
 
 // RegisterSessionRoutes registers HTTP endpoints for session management operations.
 func RegisterSessionRoutes(rg *gin.RouterGroup) {
-	session := rg.Group("/session")
+	session := rg.Group("/session", auth.RequireRole(auth.RoleSuperAdmin))
 	{
 		session.POST("/kill/:cntid", killSession)
 	}
@@ -168,6 +168,19 @@ type JobDeleteResponse struct {
 	Message string `json:"message" example:"Job removed from monitoring successfully"`
 }
 
+// JobCancelResponse represents the response for cancelling a job
+type JobCancelResponse struct {
+	Success bool          `json:"success" example:"true"`
+	Message string        `json:"message" example:"Job cancelled successfully"`
+	Data    JobStatusData `json:"data"`
+}
+
+// JobNotCancellableErrorResponse represents errors for jobs that already finished
+type JobNotCancellableErrorResponse struct {
+	Success bool   `json:"success" example:"false"`
+	Message string `json:"message" example:"Job cannot be cancelled in status completed"`
+}
+
 // Error response models for different HTTP status codes
 // Based on actual code: utils.ErrorResponse() returns {"error": "message"}
 
@@ -331,10 +344,11 @@ type GroupAssignmentResponse struct {
 
 // GroupBulkAssignmentResponse represents the response for bulk assignment operations
 type GroupBulkAssignmentResponse struct {
-	Message      string   `json:"message" example:"Policy assigned to 2/3 groups"`
-	SuccessCount int      `json:"success_count" example:"2"`
-	TotalCount   int      `json:"total_count,omitempty" example:"3"`
-	Errors       []string `json:"errors,omitempty" example:"[\"Group 3: group not found\"]"`
+	Message        string   `json:"message" example:"Policy assigned to 2/3 groups"`
+	SuccessCount   int      `json:"success_count" example:"2"`
+	TotalCount     int      `json:"total_count,omitempty" example:"3"`
+	Errors         []string `json:"errors,omitempty" example:"[\"Group 3: group not found\"]"`
+	ChangeRequests []uint   `json:"change_requests,omitempty" example:"12"`
 }
 
 // GroupDetailsResponse represents comprehensive group information
@@ -370,7 +384,6 @@ type ActorResponse struct {
 	IPAddress   string  `json:"ip_address" example:"192.168.1.100"`
 	DBClient    *string `json:"db_client,omitempty" example:"mysql_client"`
 	OSUser      *string `json:"osuser,omitempty" example:"root"`
-	Password    *string `json:"password,omitempty" example:"password123"`
 	Description *string `json:"description,omitempty" example:"Test database user"`
 	Status      *string `json:"status,omitempty" example:"active"`
 }
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"dbfartifactapi/models"
+	"dbfartifactapi/pkg/auth"
 	"dbfartifactapi/pkg/logger"
 	"dbfartifactapi/services/fileops"
 	"dbfartifactapi/utils"
@@ -28,6 +29,8 @@ func SetUploadService(srv fileops.UploadService) {
 // @Success 200 {object} UploadJobStartResponse "Upload job started successfully with job ID"
 // @Failure 400 {object} UploadValidationErrorResponse "Invalid request body or validation error"
 // @Failure 500 {object} UploadErrorResponse "Internal server error during upload execution"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/queries/upload [post]
 func executeUpload(c *gin.Context) {
 	var req models.UploadRequest
@@ -63,7 +66,7 @@ func executeUpload(c *gin.Context) {
 
 // RegisterUploadRoutes registers HTTP endpoints for file upload operations.
 func RegisterUploadRoutes(rg *gin.RouterGroup) {
-	upload := rg.Group("/upload")
+	upload := rg.Group("/upload", auth.RequireRole(auth.RoleSuperAdmin))
 	{
 		upload.POST("", executeUpload)
 	}
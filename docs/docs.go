@@ -15,8 +15,276 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/api/admin/config/reload": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Re-reads .env and the environment, validates the result and applies reloadable settings such as SYSTEM_DATABASES, SYSTEM_USERS, LOG_LEVEL, agent timeouts, retries and concurrency, privilege query concurrency and JOB_MONITOR_INTERVAL. Changed settings that are only read at startup are listed under restart_required. Same as sending SIGHUP to the process.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Reload configuration",
+                "responses": {
+                    "200": {
+                        "description": "Changed settings",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ConfigReloadResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid configuration, nothing was applied",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.StandardErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/log-level": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the log level in effect and the LOG_LEVEL of the loaded config. They differ after a change through PUT /api/admin/log-level.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get log level",
+                "responses": {
+                    "200": {
+                        "description": "Current log level",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LogLevelResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Changes the log level without a restart. The level holds until the next restart or a config reload that changes LOG_LEVEL.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Set log level",
+                "parameters": [
+                    {
+                        "description": "New log level",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.LogLevelRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Log level changed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LogLevelResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Unknown log level",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.StandardErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/audit": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the audit trail of mutating API calls and agent commands, newest first. Credentials in commands and entity states are redacted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Audit"
+                ],
+                "summary": "List audit events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Caller subject (API key name or JWT sub)",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Entity type, e.g. dbpolicy, group, dbactormgt",
+                        "name": "entity_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Entity ID",
+                        "name": "entity_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Action, e.g. create, update, delete, request",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Outcome: success or failure",
+                        "name": "outcome",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Earliest event time (RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Latest event time (RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Events per page (default: 50, max: 500)",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Page of audit events",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AuditEventListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.StandardErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/audit/verify": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Recomputes the hash of every audit event and checks that each links to its predecessor and the chain ends at the recorded head. Reports the first event found edited or missing.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Audit"
+                ],
+                "summary": "Verify audit trail integrity",
+                "responses": {
+                    "200": {
+                        "description": "Verification result",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AuditVerifyResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Audit trail could not be read",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.StandardErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/diagnostics": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Runs all dependency checks and reports each with its target, latency, detail and error. Always returns 200; the overall status is ok, degraded or unavailable.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Dependency diagnostics",
+                "responses": {
+                    "200": {
+                        "description": "Dependency check results",
+                        "schema": {
+                            "$ref": "#/definitions/dto.DiagnosticsResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/api/jobs/dbmgt/{dbmgt_id}/status": {
             "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "description": "Get all jobs associated with a specific database management ID",
                 "consumes": [
                     "application/json"
@@ -55,6 +323,14 @@ const docTemplate = `{
         },
         "/api/jobs/status": {
             "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "description": "Get the current status of all background jobs. Supports optional pagination via query parameters 'page' and 'page_size'",
                 "consumes": [
                     "application/json"
@@ -92,6 +368,14 @@ const docTemplate = `{
         },
         "/api/jobs/{job_id}": {
             "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "description": "Remove a job from the monitoring system (typically for completed or failed jobs)",
                 "consumes": [
                     "application/json"
@@ -134,9 +418,17 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/jobs/{job_id}/notify": {
+        "/api/jobs/{job_id}/cancel": {
             "post": {
-                "description": "Allow external services to report job completion with file data",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cancel a running job: the agent is asked to stop execution and any server-side processing is rolled back. Jobs that change the database on the agent (bulk policy updates) answer 409 once the agent has run them or when the agent does not confirm the cancel, so changes the agent applied are always recorded.",
                 "consumes": [
                     "application/json"
                 ],
@@ -146,7 +438,7 @@ const docTemplate = `{
                 "tags": [
                     "job-status"
                 ],
-                "summary": "Notify job completion",
+                "summary": "Cancel job",
                 "parameters": [
                     {
                         "type": "string",
@@ -154,22 +446,13 @@ const docTemplate = `{
                         "name": "job_id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Job completion notification data",
-                        "name": "notification",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.JobNotificationRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.JobStatusResponse"
+                            "$ref": "#/definitions/controllers.JobCancelResponse"
                         }
                     },
                     "400": {
@@ -183,23 +466,40 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/controllers.JobNotFoundErrorResponse"
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.JobNotCancellableErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.JobStatusErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/api/jobs/{job_id}/status": {
+        "/api/jobs/{job_id}/events": {
             "get": {
-                "description": "Get the current status of a background job",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Stream job status, results and sub-step progress events as Server-Sent Events. The first event is a snapshot of current job state; the stream ends after a completed, failed, cancelled or interrupted event.",
                 "produces": [
-                    "application/json"
+                    "text/event-stream"
                 ],
                 "tags": [
                     "job-status"
                 ],
-                "summary": "Get job status by ID",
+                "summary": "Stream job events (SSE)",
                 "parameters": [
                     {
                         "type": "string",
@@ -213,7 +513,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.JobStatusSingleResponse"
+                            "$ref": "#/definitions/job.JobEvent"
                         }
                     },
                     "400": {
@@ -231,9 +531,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/queries/actors/{actor_id}/groups": {
-            "get": {
-                "description": "Retrieves all groups assigned to a specific actor",
+        "/api/jobs/{job_id}/notify": {
+            "post": {
+                "security": [
+                    {
+                        "AgentSecret": []
+                    }
+                ],
+                "description": "Called by the agent running the job to report completion with file data. Authenticated with the agent's X-Agent-Secret, not user credentials.",
                 "consumes": [
                     "application/json"
                 ],
@@ -241,52 +546,66 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Group Management"
+                    "job-status"
                 ],
-                "summary": "Get actor groups",
+                "summary": "Notify job completion",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Actor ID",
-                        "name": "actor_id",
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "job_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Job completion notification data",
+                        "name": "notification",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.JobNotificationRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of groups",
+                        "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/controllers.GroupCreateResponse"
-                            }
+                            "$ref": "#/definitions/controllers.JobStatusResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid actor ID",
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/controllers.InvalidActorIDResponse"
+                            "$ref": "#/definitions/controllers.JobStatusErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Actor not found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/controllers.ActorNotFoundResponse"
+                            "$ref": "#/definitions/controllers.JobStatusErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/controllers.InternalServerErrorResponse"
+                            "$ref": "#/definitions/controllers.JobNotFoundErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/queries/backup": {
-            "post": {
-                "description": "Starts background jobs to execute backup steps (dump, binlog check, etc.) via VeloArtifact. Each step is executed as a separate background job. For dump type with fileName, supports both OS commands (os_execute) and SQL commands (execute). For other types like check_binlog, all steps are SQL commands.",
+        "/api/jobs/{job_id}/status": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the current status of a background job",
                 "consumes": [
                     "application/json"
                 ],
@@ -294,99 +613,103 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Backup"
+                    "job-status"
                 ],
-                "summary": "Execute backup operation",
+                "summary": "Get job status by ID",
                 "parameters": [
                     {
-                        "description": "Backup request with hex-encoded command containing steps",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.BackupRequest"
-                        }
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "job_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Background jobs started successfully with job IDs",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.BackupJobStartResponse"
+                            "$ref": "#/definitions/controllers.JobStatusSingleResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body or validation error",
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/controllers.BackupValidationErrorResponse"
+                            "$ref": "#/definitions/controllers.JobStatusErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error during backup execution",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/controllers.BackupErrorResponse"
+                            "$ref": "#/definitions/controllers.JobNotFoundErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/queries/connection/test/{id}": {
-            "post": {
-                "description": "Tests connection to database and updates status in the system",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
+        "/api/jobs/{job_id}/ws": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "WebSocket variant of the job event stream. Each message is a JSON job event; the server closes the connection after a completed, failed, cancelled or interrupted event. The Origin header must be in JOB_EVENTS_ALLOWED_ORIGINS; otherwise the handshake is answered with 403.",
                 "tags": [
-                    "Connection"
+                    "job-status"
                 ],
-                "summary": "Test database connection",
+                "summary": "Stream job events (WebSocket)",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Connection Management ID",
-                        "name": "id",
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "job_id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Connection test successful",
+                    "101": {
+                        "description": "Switching Protocols",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/job.JobEvent"
                         }
                     },
                     "400": {
-                        "description": "Bad request",
+                        "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/controllers.JobStatusErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Connection not found",
+                    "403": {
+                        "description": "Origin not allowed",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/controllers.JobNotFoundErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/queries/dbactormgt": {
-            "post": {
-                "description": "Creates a new database actor management entry with specified parameters",
+        "/api/queries/actors/{actor_id}/groups": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves all groups assigned to a specific actor",
                 "consumes": [
                     "application/json"
                 ],
@@ -394,45 +717,60 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "DB Actor Management"
+                    "Group Management"
                 ],
-                "summary": "Create database actor management",
+                "summary": "Get actor groups",
                 "parameters": [
                     {
-                        "description": "Database Actor Management object",
-                        "name": "actor",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.DBActorMgtCreateRequest"
-                        }
+                        "type": "integer",
+                        "description": "Actor ID",
+                        "name": "actor_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Actor created successfully",
+                    "200": {
+                        "description": "List of groups",
                         "schema": {
-                            "$ref": "#/definitions/controllers.DBActorMgtCreateResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/controllers.GroupCreateResponse"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid request body, validation error, or invalid IP address",
+                        "description": "Invalid actor ID",
                         "schema": {
-                            "$ref": "#/definitions/controllers.StandardErrorResponse"
+                            "$ref": "#/definitions/controllers.InvalidActorIDResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Actor not found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ActorNotFoundResponse"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/controllers.ActorCreationErrorResponse"
+                            "$ref": "#/definitions/controllers.InternalServerErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/queries/dbactormgt/all": {
+        "/api/queries/backup": {
             "post": {
-                "description": "Creates all database actors for the specified connection management ID",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Starts background jobs to execute backup steps (dump, binlog check, etc.) via VeloArtifact. Each step is executed as a separate background job. For dump type with fileName, supports both OS commands (os_execute) and SQL commands (execute). For other types like check_binlog, all steps are SQL commands.",
                 "consumes": [
                     "application/json"
                 ],
@@ -440,116 +778,118 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "DB Actor Management"
+                    "Backup"
                 ],
-                "summary": "Create all database actors for connection management",
+                "summary": "Execute backup operation",
                 "parameters": [
                     {
-                        "description": "Connection Management ID parameters",
-                        "name": "params",
+                        "description": "Backup request with hex-encoded command containing steps",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.ConnectionMgtRequest"
+                            "$ref": "#/definitions/models.BackupRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Actors created successfully with count",
+                        "description": "Background jobs started successfully with job IDs",
                         "schema": {
-                            "$ref": "#/definitions/controllers.BulkCreateResponse"
+                            "$ref": "#/definitions/controllers.BackupJobStartResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid connection management ID",
+                        "description": "Invalid request body or validation error",
                         "schema": {
-                            "$ref": "#/definitions/controllers.StandardErrorResponse"
+                            "$ref": "#/definitions/controllers.BackupValidationErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal server error during backup execution",
                         "schema": {
-                            "$ref": "#/definitions/controllers.ActorCreationErrorResponse"
+                            "$ref": "#/definitions/controllers.BackupErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/queries/dbactormgt/{id}": {
-            "put": {
-                "description": "Updates an existing database actor management entry by ID",
-                "consumes": [
-                    "application/json"
+        "/api/queries/change-requests": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Returns group changes held back for approval, newest first, without their request and preview.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "DB Actor Management"
+                    "Change Requests"
                 ],
-                "summary": "Update database actor management",
+                "summary": "List change requests",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Status: pending, approved, rejected, applied or failed",
+                        "name": "status",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "Actor Management ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
                     },
                     {
-                        "description": "Updated Database Actor Management object",
-                        "name": "actor",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.DBActorMgtUpdateRequest"
-                        }
+                        "type": "integer",
+                        "description": "Change requests per page (default: 50, max: 500)",
+                        "name": "page_size",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Actor updated successfully",
+                        "description": "Page of change requests",
                         "schema": {
-                            "$ref": "#/definitions/controllers.DBActorMgtUpdateResponse"
+                            "$ref": "#/definitions/dto.ChangeRequestListResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID or request body",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.StandardErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Actor not found",
+                        "description": "Invalid filter",
                         "schema": {
                             "$ref": "#/definitions/controllers.StandardErrorResponse"
                         }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.InternalServerErrorResponse"
-                        }
                     }
                 }
-            },
-            "delete": {
-                "description": "Deletes an existing database actor management entry by ID",
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/api/queries/change-requests/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Returns a change request with the request it holds back, the dry-run preview of that request including the SQL it would run, the approvals and rejections so far and, once applied, the result.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "DB Actor Management"
+                    "Change Requests"
                 ],
-                "summary": "Delete database actor management",
+                "summary": "Get change request",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Actor Management ID",
+                        "description": "Change request ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -557,35 +897,31 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Actor deleted successfully",
+                        "description": "Change request",
                         "schema": {
-                            "$ref": "#/definitions/controllers.DBActorMgtDeleteResponse"
+                            "$ref": "#/definitions/dto.ChangeRequestDetailResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid actor management ID",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.StandardErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Actor not found",
+                        "description": "Invalid ID or change request not found",
                         "schema": {
                             "$ref": "#/definitions/controllers.StandardErrorResponse"
                         }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.InternalServerErrorResponse"
-                        }
                     }
                 }
             }
         },
-        "/api/queries/dbmgt": {
+        "/api/queries/change-requests/{id}/approve": {
             "post": {
-                "description": "Creates a new database management entry with specified parameters",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Records the caller's approval of a pending change request. The requester cannot approve their own change request and each user decides once. The approval that reaches the required count applies the change through the normal group service path; the response then has status applied with the result, or failed with the error.",
                 "consumes": [
                     "application/json"
                 ],
@@ -593,45 +929,53 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "DB Management"
+                    "Change Requests"
                 ],
-                "summary": "Create database management",
+                "summary": "Approve change request",
                 "parameters": [
                     {
-                        "description": "Database Management object",
-                        "name": "database",
+                        "type": "integer",
+                        "description": "Change request ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Optional comment",
+                        "name": "decision",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.DBMgtCreateRequest"
+                            "$ref": "#/definitions/dto.ChangeRequestDecisionRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Database created successfully",
+                    "200": {
+                        "description": "Change request after the approval",
                         "schema": {
-                            "$ref": "#/definitions/controllers.DBMgtCreateResponse"
+                            "$ref": "#/definitions/dto.ChangeRequestDetailResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body or validation error",
+                        "description": "Change request not pending, own change request or already decided",
                         "schema": {
                             "$ref": "#/definitions/controllers.StandardErrorResponse"
                         }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.DatabaseCreationErrorResponse"
-                        }
                     }
                 }
             }
         },
-        "/api/queries/dbmgt/all": {
+        "/api/queries/change-requests/{id}/reject": {
             "post": {
-                "description": "Creates all databases for the specified connection management ID",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Rejects a pending change request, which is then never applied. The requester may reject their own change request to withdraw it.",
                 "consumes": [
                     "application/json"
                 ],
@@ -639,45 +983,53 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "DB Management"
+                    "Change Requests"
                 ],
-                "summary": "Create all databases for connection management",
+                "summary": "Reject change request",
                 "parameters": [
                     {
-                        "description": "Connection Management ID parameters",
-                        "name": "params",
+                        "type": "integer",
+                        "description": "Change request ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Optional comment",
+                        "name": "decision",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.ConnectionMgtRequest"
+                            "$ref": "#/definitions/dto.ChangeRequestDecisionRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Databases created successfully with count",
+                        "description": "Rejected change request",
                         "schema": {
-                            "$ref": "#/definitions/controllers.BulkCreateResponse"
+                            "$ref": "#/definitions/dto.ChangeRequestDetailResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid connection management ID",
+                        "description": "Change request not pending or already decided",
                         "schema": {
                             "$ref": "#/definitions/controllers.StandardErrorResponse"
                         }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.DatabaseCreationErrorResponse"
-                        }
                     }
                 }
             }
         },
-        "/api/queries/dbmgt/{id}": {
-            "delete": {
-                "description": "Deletes an existing database management entry by ID",
+        "/api/queries/connection/test/{id}": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Tests connection to database and updates status in the system",
                 "consumes": [
                     "application/json"
                 ],
@@ -685,13 +1037,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "DB Management"
+                    "Connection"
                 ],
-                "summary": "Delete database management",
+                "summary": "Test database connection",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Database Management ID",
+                        "description": "Connection Management ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -699,35 +1051,47 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Database deleted successfully",
+                        "description": "Connection test successful",
                         "schema": {
-                            "$ref": "#/definitions/controllers.DBMgtDeleteResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Invalid database management ID",
+                        "description": "Bad request",
                         "schema": {
-                            "$ref": "#/definitions/controllers.StandardErrorResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "404": {
-                        "description": "Database not found",
+                        "description": "Connection not found",
                         "schema": {
-                            "$ref": "#/definitions/controllers.StandardErrorResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/controllers.InternalServerErrorResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     }
                 }
             }
         },
-        "/api/queries/dbobjectmgt": {
+        "/api/queries/dbactormgt": {
             "post": {
-                "description": "Creates a new database object management entry with specified parameters",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a new database actor management entry with specified parameters",
                 "consumes": [
                     "application/json"
                 ],
@@ -735,29 +1099,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "DB Object Management"
+                    "DB Actor Management"
                 ],
-                "summary": "Create database object management",
+                "summary": "Create database actor management",
                 "parameters": [
                     {
-                        "description": "Database Object Management object",
-                        "name": "object",
+                        "description": "Database Actor Management object",
+                        "name": "actor",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.DBObjectMgtCreateRequest"
+                            "$ref": "#/definitions/controllers.DBActorMgtCreateRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Object created successfully",
+                        "description": "Actor created successfully",
                         "schema": {
-                            "$ref": "#/definitions/controllers.DBObjectMgtCreateResponse"
+                            "$ref": "#/definitions/controllers.DBActorMgtCreateResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body or validation error",
+                        "description": "Invalid request body, validation error, or invalid IP address",
                         "schema": {
                             "$ref": "#/definitions/controllers.StandardErrorResponse"
                         }
@@ -765,59 +1129,23 @@ const docTemplate = `{
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/controllers.ObjectCreationErrorResponse"
+                            "$ref": "#/definitions/controllers.ActorCreationErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/queries/dbobjectmgt/cntmgt/{id}": {
-            "get": {
-                "description": "Retrieves all database objects associated with the specified connection management ID",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "DB Object Management"
-                ],
-                "summary": "Get database objects by connection management ID",
-                "parameters": [
+        "/api/queries/dbactormgt/all": {
+            "post": {
+                "security": [
                     {
-                        "type": "integer",
-                        "description": "Connection Management ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "List of database objects",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.DBObjectMgtListResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid connection management ID",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.StandardErrorResponse"
-                        }
+                        "ApiKeyAuth": []
                     },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.DatabaseConnectionErrorResponse"
-                        }
+                    {
+                        "BearerAuth": []
                     }
-                }
-            }
-        },
-        "/api/queries/dbobjectmgt/dbmgt/{id}": {
-            "get": {
-                "description": "Retrieves all database objects associated with the specified database management ID",
+                ],
+                "description": "Creates all database actors for the specified connection management ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -825,27 +1153,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "DB Object Management"
+                    "DB Actor Management"
                 ],
-                "summary": "Get database objects by database management ID",
+                "summary": "Create all database actors for connection management",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Database Management ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Connection Management ID parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ConnectionMgtRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of database objects",
+                        "description": "Actors created successfully with count",
                         "schema": {
-                            "$ref": "#/definitions/controllers.DBObjectMgtListResponse"
+                            "$ref": "#/definitions/controllers.BulkCreateResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid database management ID",
+                        "description": "Invalid connection management ID",
                         "schema": {
                             "$ref": "#/definitions/controllers.StandardErrorResponse"
                         }
@@ -853,15 +1183,23 @@ const docTemplate = `{
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/controllers.DatabaseConnectionErrorResponse"
+                            "$ref": "#/definitions/controllers.ActorCreationErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/queries/dbobjectmgt/{id}": {
+        "/api/queries/dbactormgt/{id}": {
             "put": {
-                "description": "Updates an existing database object management entry by ID",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates an existing database actor management entry by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -869,32 +1207,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "DB Object Management"
+                    "DB Actor Management"
                 ],
-                "summary": "Update database object management",
+                "summary": "Update database actor management",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Object Management ID",
+                        "description": "Actor Management ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated Database Object Management object",
-                        "name": "object",
+                        "description": "Updated Database Actor Management object",
+                        "name": "actor",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.DBObjectMgtCreateRequest"
+                            "$ref": "#/definitions/controllers.DBActorMgtUpdateRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Object updated successfully",
+                        "description": "Actor updated successfully",
                         "schema": {
-                            "$ref": "#/definitions/controllers.DBObjectMgtUpdateResponse"
+                            "$ref": "#/definitions/controllers.DBActorMgtUpdateResponse"
                         }
                     },
                     "400": {
@@ -904,7 +1242,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Object not found",
+                        "description": "Actor not found",
                         "schema": {
                             "$ref": "#/definitions/controllers.StandardErrorResponse"
                         }
@@ -918,7 +1256,15 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Deletes an existing database object management entry by ID",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes an existing database actor management entry by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -926,13 +1272,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "DB Object Management"
+                    "DB Actor Management"
                 ],
-                "summary": "Delete database object management",
+                "summary": "Delete database actor management",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Object Management ID",
+                        "description": "Actor Management ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -940,19 +1286,19 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Object deleted successfully",
+                        "description": "Actor deleted successfully",
                         "schema": {
-                            "$ref": "#/definitions/controllers.DBObjectMgtDeleteResponse"
+                            "$ref": "#/definitions/controllers.DBActorMgtDeleteResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid object management ID",
+                        "description": "Invalid actor management ID",
                         "schema": {
                             "$ref": "#/definitions/controllers.StandardErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Object not found",
+                        "description": "Actor not found",
                         "schema": {
                             "$ref": "#/definitions/controllers.StandardErrorResponse"
                         }
@@ -966,9 +1312,60 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/queries/dbpolicy": {
-            "post": {
-                "description": "Creates a new database policy with specified parameters",
+        "/api/queries/dbactormgt/{id}/privileges/explain": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Re-evaluates the actor against the latest privilege snapshot of its connection without writing policies. For each allowed policy default it returns the pass that matched (super, action_wide, object_specific), the final SQL, the result value, and the grant rows and role chain that produced it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "DB Actor Management"
+                ],
+                "summary": "Explain effective privileges of a database actor",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Actor Management ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Allowed policy defaults with their grant/role path",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PrivilegeExplainResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid actor ID, actor not found or no privilege snapshot",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.StandardErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/queries/dbmgt": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a new database management entry with specified parameters",
                 "consumes": [
                     "application/json"
                 ],
@@ -976,25 +1373,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "DB Policy"
+                    "DB Management"
                 ],
-                "summary": "Create database policy",
+                "summary": "Create database management",
                 "parameters": [
                     {
-                        "description": "Database Policy object",
-                        "name": "policy",
+                        "description": "Database Management object",
+                        "name": "database",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.DBPolicyCreateRequest"
+                            "$ref": "#/definitions/controllers.DBMgtCreateRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Policy created successfully with ID",
+                        "description": "Database created successfully",
                         "schema": {
-                            "$ref": "#/definitions/controllers.DBPolicyCreateResponse"
+                            "$ref": "#/definitions/controllers.DBMgtCreateResponse"
                         }
                     },
                     "400": {
@@ -1006,15 +1403,23 @@ const docTemplate = `{
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/controllers.PolicyCreationErrorResponse"
+                            "$ref": "#/definitions/controllers.DatabaseCreationErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/queries/dbpolicy/bulkdelete": {
+        "/api/queries/dbmgt/all": {
             "post": {
-                "description": "Deletes multiple database policies by IDs. Revokes permissions via SqlUpdateDeny for enabled policies before removing records. Returns count of successfully deleted policies and list of failed IDs if any.",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates all databases for the specified connection management ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1022,29 +1427,87 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "DB Policy"
+                    "DB Management"
                 ],
-                "summary": "Bulk delete database policies",
+                "summary": "Create all databases for connection management",
                 "parameters": [
                     {
-                        "description": "Policy IDs to delete",
-                        "name": "request",
+                        "description": "Connection Management ID parameters",
+                        "name": "params",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.BulkDeleteDBPolicyRequest"
+                            "$ref": "#/definitions/controllers.ConnectionMgtRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Bulk delete completed with success/failure details",
+                        "description": "Databases created successfully with count",
                         "schema": {
-                            "$ref": "#/definitions/controllers.BulkDeleteDBPolicyResponse"
+                            "$ref": "#/definitions/controllers.BulkCreateResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body or validation error",
+                        "description": "Invalid connection management ID",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.StandardErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.DatabaseCreationErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/queries/dbmgt/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes an existing database management entry by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "DB Management"
+                ],
+                "summary": "Delete database management",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Database Management ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Database deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.DBMgtDeleteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid database management ID",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.StandardErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Database not found",
                         "schema": {
                             "$ref": "#/definitions/controllers.StandardErrorResponse"
                         }
@@ -1058,9 +1521,17 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/queries/dbpolicy/bulkupdate": {
+        "/api/queries/dbobjectmgt": {
             "post": {
-                "description": "Compares existing policies with desired state (Cartesian product of policy_defaults × objects) and executes changes via VeloArtifact background job. Only updates database after successful remote execution to ensure atomic consistency.",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a new database object management entry with specified parameters",
                 "consumes": [
                     "application/json"
                 ],
@@ -1068,25 +1539,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "DB Policy"
+                    "DB Object Management"
                 ],
-                "summary": "Bulk update database policies",
+                "summary": "Create database object management",
                 "parameters": [
                     {
-                        "description": "Bulk policy update request with actor, policy defaults, and objects",
-                        "name": "request",
+                        "description": "Database Object Management object",
+                        "name": "object",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.BulkPolicyUpdateRequest"
+                            "$ref": "#/definitions/controllers.DBObjectMgtCreateRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Background job started with job ID and change summary",
+                    "201": {
+                        "description": "Object created successfully",
                         "schema": {
-                            "$ref": "#/definitions/controllers.BulkPolicyUpdateJobResponse"
+                            "$ref": "#/definitions/controllers.DBObjectMgtCreateResponse"
                         }
                     },
                     "400": {
@@ -1095,24 +1566,26 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.StandardErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Referenced entity (cntmgt, dbmgt, actor, policy, object) not found",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.NotFoundResponse"
-                        }
-                    },
                     "500": {
-                        "description": "Internal server error during job creation or VeloArtifact execution",
+                        "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/controllers.InternalServerErrorResponse"
+                            "$ref": "#/definitions/controllers.ObjectCreationErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/queries/dbpolicy/cntmgt/{cntmgt}": {
+        "/api/queries/dbobjectmgt/cntmgt/{id}": {
             "get": {
-                "description": "Starts background job to generate database policies for all databases under specified connection management ID",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves all database objects associated with the specified connection management ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1120,23 +1593,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "DB Policy"
+                    "DB Object Management"
                 ],
-                "summary": "Generate policies for connection management",
+                "summary": "Get database objects by connection management ID",
                 "parameters": [
                     {
                         "type": "integer",
                         "description": "Connection Management ID",
-                        "name": "cntmgt",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Background job started message with job ID",
+                        "description": "List of database objects",
                         "schema": {
-                            "$ref": "#/definitions/controllers.JobStartResponse"
+                            "$ref": "#/definitions/controllers.DBObjectMgtListResponse"
                         }
                     },
                     "400": {
@@ -1148,15 +1621,23 @@ const docTemplate = `{
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/controllers.JobProcessingErrorResponse"
+                            "$ref": "#/definitions/controllers.DatabaseConnectionErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/queries/dbpolicy/dbmgt/{dbmgt}": {
+        "/api/queries/dbobjectmgt/dbmgt/{id}": {
             "get": {
-                "description": "Starts background job to generate database policies for specified database management ID",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves all database objects associated with the specified database management ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1164,23 +1645,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "DB Policy"
+                    "DB Object Management"
                 ],
-                "summary": "Generate policies for database management",
+                "summary": "Get database objects by database management ID",
                 "parameters": [
                     {
                         "type": "integer",
                         "description": "Database Management ID",
-                        "name": "dbmgt",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Background job started message with job ID",
+                        "description": "List of database objects",
                         "schema": {
-                            "$ref": "#/definitions/controllers.JobStartResponse"
+                            "$ref": "#/definitions/controllers.DBObjectMgtListResponse"
                         }
                     },
                     "400": {
@@ -1192,15 +1673,23 @@ const docTemplate = `{
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/controllers.JobProcessingErrorResponse"
+                            "$ref": "#/definitions/controllers.DatabaseConnectionErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/queries/dbpolicy/{id}": {
+        "/api/queries/dbobjectmgt/{id}": {
             "put": {
-                "description": "Updates an existing database policy by ID",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates an existing database object management entry by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1208,32 +1697,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "DB Policy"
+                    "DB Object Management"
                 ],
-                "summary": "Update database policy",
+                "summary": "Update database object management",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Policy ID",
+                        "description": "Object Management ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated Database Policy object",
-                        "name": "policy",
+                        "description": "Updated Database Object Management object",
+                        "name": "object",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.DBPolicyCreateRequest"
+                            "$ref": "#/definitions/controllers.DBObjectMgtCreateRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Policy updated successfully",
+                        "description": "Object updated successfully",
                         "schema": {
-                            "$ref": "#/definitions/controllers.DBPolicyUpdateResponse"
+                            "$ref": "#/definitions/controllers.DBObjectMgtUpdateResponse"
                         }
                     },
                     "400": {
@@ -1243,7 +1732,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Policy not found",
+                        "description": "Object not found",
                         "schema": {
                             "$ref": "#/definitions/controllers.StandardErrorResponse"
                         }
@@ -1257,7 +1746,15 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Deletes an existing database policy by ID. Revokes permissions via SqlUpdateDeny before removing policy record.",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes an existing database object management entry by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1265,13 +1762,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "DB Policy"
+                    "DB Object Management"
                 ],
-                "summary": "Delete database policy",
+                "summary": "Delete database object management",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Policy ID",
+                        "description": "Object Management ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1279,19 +1776,19 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Policy deleted successfully",
+                        "description": "Object deleted successfully",
                         "schema": {
-                            "$ref": "#/definitions/controllers.DBPolicyDeleteResponse"
+                            "$ref": "#/definitions/controllers.DBObjectMgtDeleteResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID",
+                        "description": "Invalid object management ID",
                         "schema": {
                             "$ref": "#/definitions/controllers.StandardErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Policy not found",
+                        "description": "Object not found",
                         "schema": {
                             "$ref": "#/definitions/controllers.StandardErrorResponse"
                         }
@@ -1305,9 +1802,17 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/queries/download": {
+        "/api/queries/dbpolicy": {
             "post": {
-                "description": "Starts background job to download file from server to agent. If source_path is a directory, it will be compressed to tar.gz before sending. The agent will verify MD5 hash and extract compressed files automatically.",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a new database policy with specified parameters. With dry_run=true, returns the policy and the rendered allow SQL with its target endpoint instead, without contacting the agent or storing the policy.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1315,45 +1820,65 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Download"
+                    "DB Policy"
                 ],
-                "summary": "Execute file download operation",
+                "summary": "Create database policy",
                 "parameters": [
                     {
-                        "description": "Download request with connection ID, source path, and save path",
-                        "name": "request",
+                        "description": "Database Policy object",
+                        "name": "policy",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.DownloadRequest"
+                            "$ref": "#/definitions/controllers.DBPolicyCreateRequest"
                         }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Preview the change without applying it",
+                        "name": "dry_run",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Download job started successfully with job ID",
+                        "description": "Dry run preview",
                         "schema": {
-                            "$ref": "#/definitions/controllers.DownloadJobStartResponse"
+                            "$ref": "#/definitions/dto.PolicyDryRunResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Policy created successfully with ID",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.DBPolicyCreateResponse"
                         }
                     },
                     "400": {
                         "description": "Invalid request body or validation error",
                         "schema": {
-                            "$ref": "#/definitions/controllers.DownloadValidationErrorResponse"
+                            "$ref": "#/definitions/controllers.StandardErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error during download execution",
+                        "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/controllers.DownloadErrorResponse"
+                            "$ref": "#/definitions/controllers.PolicyCreationErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/queries/groups": {
-            "get": {
-                "description": "Retrieves all active database groups. Optional filter by database type ID.",
+        "/api/queries/dbpolicy/bulkdelete": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes multiple database policies by IDs. Revokes permissions via SqlUpdateDeny for enabled policies before removing records. Returns count of successfully deleted policies and list of failed IDs if any.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1361,31 +1886,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Group Management"
+                    "DB Policy"
                 ],
-                "summary": "Get all database groups",
+                "summary": "Bulk delete database policies",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Filter by database type ID",
-                        "name": "database_type_id",
-                        "in": "query"
+                        "description": "Policy IDs to delete",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.BulkDeleteDBPolicyRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of groups",
+                        "description": "Bulk delete completed with success/failure details",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/controllers.GroupCreateResponse"
-                            }
+                            "$ref": "#/definitions/controllers.BulkDeleteDBPolicyResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid database_type_id parameter",
+                        "description": "Invalid request body or validation error",
                         "schema": {
-                            "$ref": "#/definitions/controllers.ValidationErrorResponse"
+                            "$ref": "#/definitions/controllers.StandardErrorResponse"
                         }
                     },
                     "500": {
@@ -1395,9 +1920,19 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/queries/dbpolicy/bulkupdate": {
             "post": {
-                "description": "Creates a new database group with specified parameters. Group code must be unique.",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Compares existing policies with desired state (Cartesian product of policy_defaults × objects) and executes changes via VeloArtifact background job. Only updates database after successful remote execution to ensure atomic consistency. With dry_run=true, returns a BulkPolicyDryRunResponse with the computed diff and the rendered revoke and grant SQL with the target endpoint instead, without starting the job.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1405,51 +1940,65 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Group Management"
+                    "DB Policy"
                 ],
-                "summary": "Create database group",
+                "summary": "Bulk update database policies",
                 "parameters": [
                     {
-                        "description": "Database Group object",
-                        "name": "group",
+                        "description": "Bulk policy update request with actor, policy defaults, and objects",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.GroupCreateRequest"
+                            "$ref": "#/definitions/controllers.BulkPolicyUpdateRequest"
                         }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Preview the change without starting the job",
+                        "name": "dry_run",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Group created successfully",
+                    "200": {
+                        "description": "Background job started with job ID and change summary",
                         "schema": {
-                            "$ref": "#/definitions/controllers.GroupCreateResponse"
+                            "$ref": "#/definitions/controllers.BulkPolicyUpdateJobResponse"
                         }
                     },
                     "400": {
                         "description": "Invalid request body or validation error",
                         "schema": {
-                            "$ref": "#/definitions/controllers.ValidationErrorResponse"
+                            "$ref": "#/definitions/controllers.StandardErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Group code already exists",
+                    "404": {
+                        "description": "Referenced entity (cntmgt, dbmgt, actor, policy, object) not found",
                         "schema": {
-                            "$ref": "#/definitions/controllers.GroupCodeConflictResponse"
+                            "$ref": "#/definitions/controllers.NotFoundResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal server error during job creation or VeloArtifact execution",
                         "schema": {
-                            "$ref": "#/definitions/controllers.GroupCreationErrorResponse"
+                            "$ref": "#/definitions/controllers.InternalServerErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/queries/groups/{id}": {
+        "/api/queries/dbpolicy/cntmgt/{cntmgt}": {
             "get": {
-                "description": "Retrieves a database group with specified ID",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Starts background job to generate database policies for all databases under specified connection management ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1457,47 +2006,43 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Group Management"
+                    "DB Policy"
                 ],
-                "summary": "Get database group by ID",
+                "summary": "Generate policies for connection management",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Group ID",
-                        "name": "id",
+                        "description": "Connection Management ID",
+                        "name": "cntmgt",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Group details",
+                        "description": "Background job started message with job ID",
                         "schema": {
-                            "$ref": "#/definitions/controllers.GroupCreateResponse"
+                            "$ref": "#/definitions/controllers.JobStartResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid group ID",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.InvalidGroupIDResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Group not found",
+                        "description": "Invalid connection management ID",
                         "schema": {
-                            "$ref": "#/definitions/controllers.GroupNotFoundResponse"
+                            "$ref": "#/definitions/controllers.StandardErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/controllers.InternalServerErrorResponse"
+                            "$ref": "#/definitions/controllers.JobProcessingErrorResponse"
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Updates an existing database group with specified ID. Code must remain unique.",
+            }
+        },
+        "/api/queries/dbpolicy/dbmgt/{dbmgt}": {
+            "get": {
+                "description": "Starts background job to generate database policies for specified database management ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1505,285 +2050,360 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Group Management"
+                    "DB Policy"
                 ],
-                "summary": "Update database group",
+                "summary": "Generate policies for database management",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Group ID",
-                        "name": "id",
+                        "description": "Database Management ID",
+                        "name": "dbmgt",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Database Group object",
-                        "name": "group",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.GroupCreateRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Group updated successfully",
+                        "description": "Background job started message with job ID",
                         "schema": {
-                            "$ref": "#/definitions/controllers.GroupCreateResponse"
+                            "$ref": "#/definitions/controllers.JobStartResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid group ID or request body",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.InvalidGroupIDResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Group not found",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.GroupNotFoundResponse"
-                        }
-                    },
-                    "409": {
-                        "description": "Group code already exists",
+                        "description": "Invalid database management ID",
                         "schema": {
-                            "$ref": "#/definitions/controllers.GroupCodeConflictResponse"
+                            "$ref": "#/definitions/controllers.StandardErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/controllers.GroupCreationErrorResponse"
+                            "$ref": "#/definitions/controllers.JobProcessingErrorResponse"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Deletes a database group and all its associations. Cannot delete groups with child groups.",
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/api/queries/dbpolicy/cntmgt/{cntmgt}/conflicts": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Reports, per actor, policies that contradict, are shadowed by or repeat another policy of the same policy default: disabled policies overlapping an enabled one or a group grant, enabled policies inside the scope of a wider policy or group grant, and exact repeats. Group grants are the policy defaults of the groups each actor currently belongs to. removable_policy_ids lists disabled repeats of another disabled policy, whose deletion runs no SQL on the database. Enabled rows are never listed: deleting one runs its deny SQL, which also revokes what the covering policy grants.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Group Management"
+                    "DB Policy"
                 ],
-                "summary": "Delete database group",
+                "summary": "Get policy conflicts",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Group ID",
-                        "name": "id",
+                        "description": "Connection Management ID",
+                        "name": "cntmgt",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Group deleted successfully",
+                        "description": "Policy conflicts per actor",
                         "schema": {
-                            "$ref": "#/definitions/controllers.SuccessResponse"
+                            "$ref": "#/definitions/dto.PolicyConflictReport"
                         }
                     },
                     "400": {
-                        "description": "Invalid group ID",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.InvalidGroupIDResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Group not found",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.GroupNotFoundResponse"
-                        }
-                    },
-                    "409": {
-                        "description": "Group has child groups",
+                        "description": "Invalid ID or connection management not found",
                         "schema": {
-                            "$ref": "#/definitions/controllers.GroupHasChildrenResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.InternalServerErrorResponse"
+                            "$ref": "#/definitions/controllers.StandardErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/queries/groups/{id}/actors": {
+        "/api/queries/dbpolicy/cntmgt/{cntmgt}/drift": {
             "get": {
-                "description": "Retrieves all active actors assigned to a database group",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Compares two stored privilege snapshots of a connection: grants added or revoked, new or removed actors, and policy defaults newly allowed or no longer allowed. Defaults to the latest snapshot compared with the one before it.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Group Management"
+                    "DB Policy"
                 ],
-                "summary": "Get group actors",
+                "summary": "Get privilege drift between discovery runs",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Group ID",
-                        "name": "id",
+                        "description": "Connection Management ID",
+                        "name": "cntmgt",
                         "in": "path",
                         "required": true
-                    }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Baseline snapshot version (default: version before 'to')",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Target snapshot version (default: latest)",
+                        "name": "to",
+                        "in": "query"
+                    }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of actors",
+                        "description": "Privilege drift between the two snapshots",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/controllers.ActorResponse"
-                            }
+                            "$ref": "#/definitions/dto.PrivilegeDriftResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid group ID",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.InvalidGroupIDResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Group not found",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.GroupNotFoundResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
+                        "description": "Invalid ID, invalid versions or snapshot not found",
                         "schema": {
-                            "$ref": "#/definitions/controllers.InternalServerErrorResponse"
+                            "$ref": "#/definitions/controllers.StandardErrorResponse"
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Assigns multiple actors to a database group. Sends VeloArtifact allow commands to clients before updating database. Supports partial success - if some VeloArtifact operations fail, the operation continues with successful ones. Returns detailed execution status including success/failure information for each VeloArtifact job.",
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/api/queries/dbpolicy/cntmgt/{cntmgt}/revisions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Lists the revisions of the policy set of a connection, newest first. Every policy create, update, delete and completed bulk update or rollback adds a revision with its author and the number of policies added and removed. The first change of a connection is preceded by a baseline revision with the policies before it.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Group Management"
+                    "DB Policy"
                 ],
-                "summary": "Assign actors to group",
+                "summary": "List policy revisions",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Group ID",
-                        "name": "id",
+                        "description": "Connection Management ID",
+                        "name": "cntmgt",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Actor IDs to assign",
-                        "name": "actors",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.ActorAssignmentRequest"
-                        }
+                        "type": "integer",
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revisions per page (default: 50, max: 500)",
+                        "name": "page_size",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Actors assigned with detailed execution status",
+                        "description": "One page of policy revisions",
                         "schema": {
-                            "$ref": "#/definitions/controllers.ActorAssignmentResult"
+                            "$ref": "#/definitions/dto.PolicyRevisionListResponse"
                         }
                     },
                     "400": {
-                        "description": "Empty actor list",
+                        "description": "Invalid connection management ID or query",
                         "schema": {
-                            "$ref": "#/definitions/controllers.EmptyListValidationResponse"
+                            "$ref": "#/definitions/controllers.StandardErrorResponse"
                         }
+                    }
+                }
+            }
+        },
+        "/api/queries/dbpolicy/cntmgt/{cntmgt}/revisions/{rev}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
                     },
-                    "404": {
-                        "description": "Group not found",
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns one policy revision with its changeset (policies added and removed; an update shows as the old policy removed and the new one added) and the whole policy set of the connection after it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "DB Policy"
+                ],
+                "summary": "Get policy revision",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Connection Management ID",
+                        "name": "cntmgt",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision number",
+                        "name": "rev",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Policy revision",
                         "schema": {
-                            "$ref": "#/definitions/controllers.GroupNotFoundResponse"
+                            "$ref": "#/definitions/dto.PolicyRevisionDetailResponse"
                         }
                     },
-                    "500": {
-                        "description": "All VeloArtifact operations failed or actor assignment failed",
+                    "400": {
+                        "description": "Invalid ID or revision not found",
                         "schema": {
-                            "$ref": "#/definitions/controllers.ActorAssignmentErrorResponse"
+                            "$ref": "#/definitions/controllers.StandardErrorResponse"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Removes multiple actors from a database group. Sends VeloArtifact deny commands to clients before updating database. Supports partial success - if some VeloArtifact operations fail, the operation continues with successful ones. Returns detailed execution status including success/failure information for each VeloArtifact job.",
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/api/queries/dbpolicy/cntmgt/{cntmgt}/revisions/{rev}/diff": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Compares the policy set after a revision with the set after another revision of the same connection: policies added, removed, and kept with a different status or description. Defaults to the revision right before it.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Group Management"
+                    "DB Policy"
                 ],
-                "summary": "Remove actors from group",
+                "summary": "Diff policy revisions",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Group ID",
-                        "name": "id",
+                        "description": "Connection Management ID",
+                        "name": "cntmgt",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Actor IDs to remove",
-                        "name": "actors",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.ActorAssignmentRequest"
-                        }
+                        "type": "integer",
+                        "description": "Revision number",
+                        "name": "rev",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision to compare with (default: revision before 'rev')",
+                        "name": "from",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Actors removed with detailed execution status",
+                        "description": "Policy changes between the two revisions",
                         "schema": {
-                            "$ref": "#/definitions/controllers.ActorRemovalResult"
+                            "$ref": "#/definitions/dto.PolicyRevisionDiffResponse"
                         }
                     },
                     "400": {
-                        "description": "Empty actor list",
+                        "description": "Invalid ID, invalid revisions or revision not found",
                         "schema": {
-                            "$ref": "#/definitions/controllers.EmptyListValidationResponse"
+                            "$ref": "#/definitions/controllers.StandardErrorResponse"
                         }
+                    }
+                }
+            }
+        },
+        "/api/queries/dbpolicy/cntmgt/{cntmgt}/revisions/{rev}/rollback": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
                     },
-                    "404": {
-                        "description": "Group not found",
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Compares the current policies of the connection with the policy set after the revision and starts one bulk policy update job per database and actor to grant and revoke the difference through the policies' allow/deny SQL. Each job changes the policy rows only after all its commands succeeded, records a rollback revision and rebuilds the rule files with exportDBFPolicy. Disabled policies are left as they are; connection-wide and wildcard-object policies cannot be applied in bulk and are returned as skipped.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "DB Policy"
+                ],
+                "summary": "Roll back policies to a revision",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Connection Management ID",
+                        "name": "cntmgt",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision to roll back to",
+                        "name": "rev",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rollback jobs started",
                         "schema": {
-                            "$ref": "#/definitions/controllers.GroupNotFoundResponse"
+                            "$ref": "#/definitions/dto.PolicyRollbackResponse"
                         }
                     },
-                    "500": {
-                        "description": "All VeloArtifact operations failed or actor removal failed",
+                    "400": {
+                        "description": "Invalid ID, revision not found or job start failed",
                         "schema": {
-                            "$ref": "#/definitions/controllers.ActorAssignmentErrorResponse"
+                            "$ref": "#/definitions/controllers.StandardErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/queries/groups/{id}/assignments": {
+        "/api/queries/dbpolicy/{id}": {
             "put": {
-                "description": "Updates both policies and actors for a group with minimal VeloArtifact executions. This is the optimized alternative to individual assignment operations.",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates an existing database policy by ID. With dry_run=true, returns a PolicyDryRunResponse with the policy before and after and the rendered deny and allow SQL with their target endpoints instead, without contacting the agent or saving the policy.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1791,44 +2411,50 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Group Management"
+                    "DB Policy"
                 ],
-                "summary": "Update group assignments (optimized)",
+                "summary": "Update database policy",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Group ID",
+                        "description": "Policy ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "New policy and actor assignments",
-                        "name": "request",
+                        "description": "Updated Database Policy object",
+                        "name": "policy",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.GroupAssignmentsUpdateRequest"
+                            "$ref": "#/definitions/controllers.DBPolicyCreateRequest"
                         }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Preview the change without applying it",
+                        "name": "dry_run",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Group assignments updated successfully with optimization details",
+                        "description": "Policy updated successfully",
                         "schema": {
-                            "$ref": "#/definitions/controllers.GroupAssignmentsUpdateResult"
+                            "$ref": "#/definitions/controllers.DBPolicyUpdateResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid group ID or request",
+                        "description": "Invalid ID or request body",
                         "schema": {
-                            "$ref": "#/definitions/controllers.InvalidGroupIDResponse"
+                            "$ref": "#/definitions/controllers.StandardErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Group not found",
+                        "description": "Policy not found",
                         "schema": {
-                            "$ref": "#/definitions/controllers.GroupNotFoundResponse"
+                            "$ref": "#/definitions/controllers.StandardErrorResponse"
                         }
                     },
                     "500": {
@@ -1838,11 +2464,17 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/queries/groups/{id}/details": {
-            "get": {
-                "description": "Retrieves group details along with assigned policies and actors",
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes an existing database policy by ID. Revokes permissions via SqlUpdateDeny before removing policy record.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1850,13 +2482,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Group Management"
+                    "DB Policy"
                 ],
-                "summary": "Get comprehensive group details",
+                "summary": "Delete database policy",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Group ID",
+                        "description": "Policy ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1864,21 +2496,21 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Comprehensive group information",
+                        "description": "Policy deleted successfully",
                         "schema": {
-                            "$ref": "#/definitions/controllers.GroupDetailsResponse"
+                            "$ref": "#/definitions/controllers.DBPolicyDeleteResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid group ID",
+                        "description": "Invalid ID",
                         "schema": {
-                            "$ref": "#/definitions/controllers.InvalidGroupIDResponse"
+                            "$ref": "#/definitions/controllers.StandardErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Group not found",
+                        "description": "Policy not found",
                         "schema": {
-                            "$ref": "#/definitions/controllers.GroupNotFoundResponse"
+                            "$ref": "#/definitions/controllers.StandardErrorResponse"
                         }
                     },
                     "500": {
@@ -1890,60 +2522,17 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/queries/groups/{id}/policies": {
-            "get": {
-                "description": "Retrieves all active policies assigned to a database group",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Group Management"
-                ],
-                "summary": "Get group policies",
-                "parameters": [
+        "/api/queries/download": {
+            "post": {
+                "security": [
                     {
-                        "type": "integer",
-                        "description": "Group ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "List of policies",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/controllers.GroupListPolicyResponse"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid group ID",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.InvalidGroupIDResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Group not found",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.GroupNotFoundResponse"
-                        }
+                        "ApiKeyAuth": []
                     },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.InternalServerErrorResponse"
-                        }
+                    {
+                        "BearerAuth": []
                     }
-                }
-            },
-            "post": {
-                "description": "Assigns multiple policies to a database group",
+                ],
+                "description": "Starts background job to download file from server to agent. If source_path is a directory, it will be compressed to tar.gz before sending. The agent will verify MD5 hash and extract compressed files automatically.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1951,56 +2540,53 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Group Management"
+                    "Download"
                 ],
-                "summary": "Assign policies to group",
+                "summary": "Execute file download operation",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Group ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Policy IDs to assign",
-                        "name": "policies",
+                        "description": "Download request with connection ID, source path, and save path",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.PolicyAssignmentRequest"
+                            "$ref": "#/definitions/models.DownloadRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Policies assigned successfully",
+                        "description": "Download job started successfully with job ID",
                         "schema": {
-                            "$ref": "#/definitions/controllers.GroupAssignmentResponse"
+                            "$ref": "#/definitions/controllers.DownloadJobStartResponse"
                         }
                     },
                     "400": {
-                        "description": "Empty policy list",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.EmptyListValidationResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Group not found",
+                        "description": "Invalid request body or validation error",
                         "schema": {
-                            "$ref": "#/definitions/controllers.GroupNotFoundResponse"
+                            "$ref": "#/definitions/controllers.DownloadValidationErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Policy assignment failed",
+                        "description": "Internal server error during download execution",
                         "schema": {
-                            "$ref": "#/definitions/controllers.PolicyAssignmentErrorResponse"
+                            "$ref": "#/definitions/controllers.DownloadErrorResponse"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Removes multiple policies from a database group",
+            }
+        },
+        "/api/queries/groups": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves all active database groups. Optional filter by database type ID.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2010,56 +2596,49 @@ const docTemplate = `{
                 "tags": [
                     "Group Management"
                 ],
-                "summary": "Remove policies from group",
+                "summary": "Get all database groups",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Group ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Policy IDs to remove",
-                        "name": "policies",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.PolicyAssignmentRequest"
-                        }
+                        "description": "Filter by database type ID",
+                        "name": "database_type_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Policies removed successfully",
+                        "description": "List of groups",
                         "schema": {
-                            "$ref": "#/definitions/controllers.GroupAssignmentResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/controllers.GroupCreateResponse"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Empty policy list",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.EmptyListValidationResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Group not found",
+                        "description": "Invalid database_type_id parameter",
                         "schema": {
-                            "$ref": "#/definitions/controllers.GroupNotFoundResponse"
+                            "$ref": "#/definitions/controllers.ValidationErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Policy removal failed",
+                        "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/controllers.PolicyAssignmentErrorResponse"
+                            "$ref": "#/definitions/controllers.InternalServerErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/api/queries/pdb": {
+            },
             "post": {
-                "description": "Creates a new Oracle Pluggable Database on the remote server and registers it locally",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a new database group with specified parameters. Group code must be unique.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2067,45 +2646,59 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "PDB Management"
+                    "Group Management"
                 ],
-                "summary": "Create PDB",
+                "summary": "Create database group",
                 "parameters": [
                     {
-                        "description": "PDB creation parameters",
-                        "name": "pdb",
+                        "description": "Database Group object",
+                        "name": "group",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.PDBCreateRequest"
+                            "$ref": "#/definitions/controllers.GroupCreateRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "PDB created successfully",
+                        "description": "Group created successfully",
                         "schema": {
-                            "$ref": "#/definitions/controllers.PDBCreateResponse"
+                            "$ref": "#/definitions/controllers.GroupCreateResponse"
                         }
                     },
                     "400": {
                         "description": "Invalid request body or validation error",
                         "schema": {
-                            "$ref": "#/definitions/controllers.StandardErrorResponse"
+                            "$ref": "#/definitions/controllers.ValidationErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Group code already exists",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.GroupCodeConflictResponse"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/controllers.DatabaseCreationErrorResponse"
+                            "$ref": "#/definitions/controllers.GroupCreationErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/queries/pdb/all": {
-            "post": {
-                "description": "Queries DBA_PDBS on the remote Oracle server and synchronizes local PDB records",
+        "/api/queries/groups/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves a database group with specified ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -2113,45 +2706,55 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "PDB Management"
+                    "Group Management"
                 ],
-                "summary": "Synchronize all PDBs for CDB connection",
+                "summary": "Get database group by ID",
                 "parameters": [
                     {
-                        "description": "CDB Connection Management ID",
-                        "name": "params",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.PDBGetAllRequest"
-                        }
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "PDBs synchronized successfully with count",
+                        "description": "Group details",
                         "schema": {
-                            "$ref": "#/definitions/controllers.BulkCreateResponse"
+                            "$ref": "#/definitions/controllers.GroupCreateResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid connection management ID",
+                        "description": "Invalid group ID",
                         "schema": {
-                            "$ref": "#/definitions/controllers.StandardErrorResponse"
+                            "$ref": "#/definitions/controllers.InvalidGroupIDResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Group not found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.GroupNotFoundResponse"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/controllers.DatabaseCreationErrorResponse"
+                            "$ref": "#/definitions/controllers.InternalServerErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/api/queries/pdb/{id}": {
+            },
             "put": {
-                "description": "Executes ALTER PLUGGABLE DATABASE on the remote Oracle server",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates an existing database group with specified ID. Code must remain unique.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2159,100 +2762,112 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "PDB Management"
+                    "Group Management"
                 ],
-                "summary": "Alter PDB",
+                "summary": "Update database group",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "PDB Connection Management ID",
+                        "description": "Group ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "PDB alter parameters",
-                        "name": "pdb",
+                        "description": "Database Group object",
+                        "name": "group",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.PDBUpdateRequest"
+                            "$ref": "#/definitions/controllers.GroupCreateRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "PDB altered successfully",
+                        "description": "Group updated successfully",
                         "schema": {
-                            "$ref": "#/definitions/controllers.PDBUpdateResponse"
+                            "$ref": "#/definitions/controllers.GroupCreateResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID or request body",
+                        "description": "Invalid group ID or request body",
                         "schema": {
-                            "$ref": "#/definitions/controllers.StandardErrorResponse"
+                            "$ref": "#/definitions/controllers.InvalidGroupIDResponse"
                         }
                     },
                     "404": {
-                        "description": "PDB not found",
+                        "description": "Group not found",
                         "schema": {
-                            "$ref": "#/definitions/controllers.StandardErrorResponse"
+                            "$ref": "#/definitions/controllers.GroupNotFoundResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Group code already exists",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.GroupCodeConflictResponse"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/controllers.InternalServerErrorResponse"
+                            "$ref": "#/definitions/controllers.GroupCreationErrorResponse"
                         }
                     }
                 }
             },
             "delete": {
-                "description": "Drops a Pluggable Database on the remote Oracle server and removes local record",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a database group and all its associations. Cannot delete groups with child groups.",
+                "consumes": [
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "PDB Management"
+                    "Group Management"
                 ],
-                "summary": "Drop PDB",
+                "summary": "Delete database group",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "PDB Connection Management ID",
+                        "description": "Group ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Optional DROP parameters",
-                        "name": "params",
-                        "in": "body",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.PDBDeleteRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "PDB dropped successfully",
+                        "description": "Group deleted successfully",
                         "schema": {
-                            "$ref": "#/definitions/controllers.PDBDeleteResponse"
+                            "$ref": "#/definitions/controllers.SuccessResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid PDB connection management ID",
+                        "description": "Invalid group ID",
                         "schema": {
-                            "$ref": "#/definitions/controllers.StandardErrorResponse"
+                            "$ref": "#/definitions/controllers.InvalidGroupIDResponse"
                         }
                     },
                     "404": {
-                        "description": "PDB not found",
+                        "description": "Group not found",
                         "schema": {
-                            "$ref": "#/definitions/controllers.StandardErrorResponse"
+                            "$ref": "#/definitions/controllers.GroupNotFoundResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Group has child groups",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.GroupHasChildrenResponse"
                         }
                     },
                     "500": {
@@ -2264,9 +2879,17 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/queries/policies/{policy_id}/assign-groups": {
-            "post": {
-                "description": "Assigns a single policy to multiple database groups. Returns partial success if some groups fail.",
+        "/api/queries/groups/{id}/actors": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves all active actors assigned to a database group",
                 "consumes": [
                     "application/json"
                 ],
@@ -2276,62 +2899,56 @@ const docTemplate = `{
                 "tags": [
                     "Group Management"
                 ],
-                "summary": "Bulk assign policy to groups",
+                "summary": "Get group actors",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Policy ID",
-                        "name": "policy_id",
+                        "description": "Group ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Group IDs to assign policy to",
-                        "name": "groups",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.GroupAssignmentRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Policy assigned to all groups successfully",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.GroupBulkAssignmentResponse"
-                        }
-                    },
-                    "206": {
-                        "description": "Policy assigned to some groups (partial success)",
+                        "description": "List of actors",
                         "schema": {
-                            "$ref": "#/definitions/controllers.GroupBulkAssignmentResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/controllers.ActorResponse"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Empty group list",
+                        "description": "Invalid group ID",
                         "schema": {
-                            "$ref": "#/definitions/controllers.EmptyListValidationResponse"
+                            "$ref": "#/definitions/controllers.InvalidGroupIDResponse"
                         }
                     },
                     "404": {
-                        "description": "Policy not found",
+                        "description": "Group not found",
                         "schema": {
-                            "$ref": "#/definitions/controllers.PolicyNotFoundResponse"
+                            "$ref": "#/definitions/controllers.GroupNotFoundResponse"
                         }
                     },
                     "500": {
-                        "description": "Policy assignment failed",
+                        "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/controllers.PolicyAssignmentErrorResponse"
+                            "$ref": "#/definitions/controllers.InternalServerErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/api/queries/policies/{policy_id}/groups": {
-            "get": {
-                "description": "Retrieves all groups assigned to a specific policy",
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Assigns multiple actors to a database group. Sends VeloArtifact allow commands to clients before updating database. Supports partial success - if some VeloArtifact operations fail, the operation continues with successful ones. Returns detailed execution status including success/failure information for each VeloArtifact job. With dry_run=true, returns a GroupDryRunResult with the actors that would be assigned and the rendered allow SQL per connection with its target endpoint instead, without contacting the agents or storing the assignment.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2341,50 +2958,74 @@ const docTemplate = `{
                 "tags": [
                     "Group Management"
                 ],
-                "summary": "Get policy groups",
+                "summary": "Assign actors to group",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Policy ID",
-                        "name": "policy_id",
+                        "description": "Group ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Actor IDs to assign",
+                        "name": "actors",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ActorAssignmentRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Preview the change without applying it",
+                        "name": "dry_run",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of groups",
+                        "description": "Actors assigned with detailed execution status",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/controllers.GroupCreateResponse"
-                            }
+                            "$ref": "#/definitions/controllers.ActorAssignmentResult"
+                        }
+                    },
+                    "202": {
+                        "description": "Change held back as a pending change request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ChangeRequestDetailResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid policy ID",
+                        "description": "Empty actor list",
                         "schema": {
-                            "$ref": "#/definitions/controllers.InvalidPolicyIDResponse"
+                            "$ref": "#/definitions/controllers.EmptyListValidationResponse"
                         }
                     },
                     "404": {
-                        "description": "Policy not found",
+                        "description": "Group not found",
                         "schema": {
-                            "$ref": "#/definitions/controllers.PolicyNotFoundResponse"
+                            "$ref": "#/definitions/controllers.GroupNotFoundResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "All VeloArtifact operations failed or actor assignment failed",
                         "schema": {
-                            "$ref": "#/definitions/controllers.InternalServerErrorResponse"
+                            "$ref": "#/definitions/controllers.ActorAssignmentErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/api/queries/policy-compliance/start/{id}": {
-            "post": {
-                "description": "Initiates policy compliance check for the specified connection management ID",
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes multiple actors from a database group. Sends VeloArtifact deny commands to clients before updating database. Supports partial success - if some VeloArtifact operations fail, the operation continues with successful ones. Returns detailed execution status including success/failure information for each VeloArtifact job. With dry_run=true, returns a GroupDryRunResult with the actors that would be removed and the rendered deny SQL per connection with its target endpoint instead, without contacting the agents or removing the assignment.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2392,52 +3033,78 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Policy Compliance"
+                    "Group Management"
                 ],
-                "summary": "Start policy compliance check",
+                "summary": "Remove actors from group",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Connection Management ID",
+                        "description": "Group ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Actor IDs to remove",
+                        "name": "actors",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ActorAssignmentRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Preview the change without applying it",
+                        "name": "dry_run",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Policy compliance check started",
+                        "description": "Actors removed with detailed execution status",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/controllers.ActorRemovalResult"
+                        }
+                    },
+                    "202": {
+                        "description": "Change held back as a pending change request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ChangeRequestDetailResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid connection management ID",
+                        "description": "Empty actor list",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/controllers.EmptyListValidationResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Group not found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.GroupNotFoundResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "All VeloArtifact operations failed or actor removal failed",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/controllers.ActorAssignmentErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/queries/session/kill/{cntid}": {
-            "post": {
-                "description": "Kills a specific database session using connection management ID and session ID",
+        "/api/queries/groups/{id}/assignments": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates both policies and actors for a group with minimal VeloArtifact executions. This is the optimized alternative to individual assignment operations. With dry_run=true, returns a GroupDryRunResult with the computed diff and the rendered allow/deny SQL per connection with its target endpoint instead, without contacting the agents or storing the assignments.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2445,854 +3112,2390 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Session Management"
+                    "Group Management"
                 ],
-                "summary": "Kill database session",
+                "summary": "Update group assignments (optimized)",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Connection Management ID",
-                        "name": "cntid",
+                        "description": "Group ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Session ID to kill",
-                        "name": "killSessionRequest",
+                        "description": "New policy and actor assignments",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.KillSessionRequest"
+                            "$ref": "#/definitions/controllers.GroupAssignmentsUpdateRequest"
                         }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Preview the change without applying it",
+                        "name": "dry_run",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Kill session command executed successfully for session ID on connection ID",
+                        "description": "Group assignments updated successfully with optimization details",
                         "schema": {
-                            "$ref": "#/definitions/controllers.JobStartResponse"
+                            "$ref": "#/definitions/controllers.GroupAssignmentsUpdateResult"
+                        }
+                    },
+                    "202": {
+                        "description": "Change held back as a pending change request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ChangeRequestDetailResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request parameters",
+                        "description": "Invalid group ID or request",
                         "schema": {
-                            "$ref": "#/definitions/controllers.StandardErrorResponse"
+                            "$ref": "#/definitions/controllers.InvalidGroupIDResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Group not found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.GroupNotFoundResponse"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/controllers.JobProcessingErrorResponse"
+                            "$ref": "#/definitions/controllers.InternalServerErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/queries/upload": {
-            "post": {
-                "description": "Starts background job to upload file from client to server via VeloArtifact. The upload operation uses the source job ID to locate the file on the client, then uploads it to the specified path on the server.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
+        "/api/queries/groups/{id}/details": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves group details along with assigned policies and actors",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
                 ],
                 "tags": [
-                    "Upload"
+                    "Group Management"
                 ],
-                "summary": "Execute file upload operation",
+                "summary": "Get comprehensive group details",
                 "parameters": [
                     {
-                        "description": "Upload request with connection ID, source job ID, file name, and file path",
-                        "name": "request",
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Comprehensive group information",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.GroupDetailsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid group ID",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.InvalidGroupIDResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Group not found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.GroupNotFoundResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.InternalServerErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/queries/groups/{id}/policies": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves all active policies assigned to a database group",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Group Management"
+                ],
+                "summary": "Get group policies",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of policies",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/controllers.GroupListPolicyResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid group ID",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.InvalidGroupIDResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Group not found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.GroupNotFoundResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.InternalServerErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Assigns multiple policies to a database group. With dry_run=true, returns a GroupDryRunResult with the policies that would be assigned without storing them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Group Management"
+                ],
+                "summary": "Assign policies to group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Policy IDs to assign",
+                        "name": "policies",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UploadRequest"
+                            "$ref": "#/definitions/controllers.PolicyAssignmentRequest"
                         }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Preview the change without applying it",
+                        "name": "dry_run",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Upload job started successfully with job ID",
+                        "description": "Policies assigned successfully",
                         "schema": {
-                            "$ref": "#/definitions/controllers.UploadJobStartResponse"
+                            "$ref": "#/definitions/controllers.GroupAssignmentResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Change held back as a pending change request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ChangeRequestDetailResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body or validation error",
+                        "description": "Empty policy list",
                         "schema": {
-                            "$ref": "#/definitions/controllers.UploadValidationErrorResponse"
+                            "$ref": "#/definitions/controllers.EmptyListValidationResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Group not found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.GroupNotFoundResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error during upload execution",
+                        "description": "Policy assignment failed",
                         "schema": {
-                            "$ref": "#/definitions/controllers.UploadErrorResponse"
+                            "$ref": "#/definitions/controllers.PolicyAssignmentErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes multiple policies from a database group. With dry_run=true, returns a GroupDryRunResult with the policies that would be removed without removing them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Group Management"
+                ],
+                "summary": "Remove policies from group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Policy IDs to remove",
+                        "name": "policies",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.PolicyAssignmentRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Preview the change without applying it",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Policies removed successfully",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.GroupAssignmentResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Change held back as a pending change request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ChangeRequestDetailResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Empty policy list",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.EmptyListValidationResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Group not found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.GroupNotFoundResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Policy removal failed",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.PolicyAssignmentErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "controllers.ActorAssignmentErrorResponse": {
+        },
+        "/api/queries/membership-transitions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the grants and revocations the validity enforcement scheduler made when memberships in dbactor_groups and dbpolicy_groups reached their valid_from or valid_until, most recently attempted first. Failed transitions are retried on every pass; attempts counts the passes so far. Returns an error while VALIDITY_ENFORCEMENT_ENABLED is false and the table has never been created.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Group Management"
+                ],
+                "summary": "List membership transitions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Status: applied, partial or failed",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Transitions per page (default: 50, max: 500)",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Page of membership transitions",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MembershipTransitionListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.StandardErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/queries/pdb": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a new Oracle Pluggable Database on the remote server and registers it locally",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PDB Management"
+                ],
+                "summary": "Create PDB",
+                "parameters": [
+                    {
+                        "description": "PDB creation parameters",
+                        "name": "pdb",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.PDBCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "PDB created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.PDBCreateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or validation error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.StandardErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.DatabaseCreationErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/queries/pdb/all": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Queries DBA_PDBS on the remote Oracle server and synchronizes local PDB records",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PDB Management"
+                ],
+                "summary": "Synchronize all PDBs for CDB connection",
+                "parameters": [
+                    {
+                        "description": "CDB Connection Management ID",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.PDBGetAllRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "PDBs synchronized successfully with count",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.BulkCreateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid connection management ID",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.StandardErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.DatabaseCreationErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/queries/pdb/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Executes ALTER PLUGGABLE DATABASE on the remote Oracle server",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PDB Management"
+                ],
+                "summary": "Alter PDB",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "PDB Connection Management ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "PDB alter parameters",
+                        "name": "pdb",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.PDBUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "PDB altered successfully",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.PDBUpdateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or request body",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.StandardErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "PDB not found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.StandardErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.InternalServerErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Drops a Pluggable Database on the remote Oracle server and removes local record",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PDB Management"
+                ],
+                "summary": "Drop PDB",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "PDB Connection Management ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Optional DROP parameters",
+                        "name": "params",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.PDBDeleteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "PDB dropped successfully",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.PDBDeleteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid PDB connection management ID",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.StandardErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "PDB not found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.StandardErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.InternalServerErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/queries/policies/{policy_id}/assign-groups": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Assigns a single policy to multiple database groups. Returns partial success if some groups fail. Groups where the assignment needs approval get a pending change request each, listed in change_requests.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Group Management"
+                ],
+                "summary": "Bulk assign policy to groups",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Policy ID",
+                        "name": "policy_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Group IDs to assign policy to",
+                        "name": "groups",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.GroupAssignmentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Policy assigned to all groups successfully",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.GroupBulkAssignmentResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Policy assigned to the other groups, some held back for approval",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.GroupBulkAssignmentResponse"
+                        }
+                    },
+                    "206": {
+                        "description": "Policy assigned to some groups (partial success)",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.GroupBulkAssignmentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Empty group list",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.EmptyListValidationResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Policy not found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.PolicyNotFoundResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Policy assignment failed",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.PolicyAssignmentErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/queries/policies/{policy_id}/groups": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves all groups assigned to a specific policy",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Group Management"
+                ],
+                "summary": "Get policy groups",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Policy ID",
+                        "name": "policy_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of groups",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/controllers.GroupCreateResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid policy ID",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.InvalidPolicyIDResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Policy not found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.PolicyNotFoundResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.InternalServerErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/queries/policy-compliance/start/{id}": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Initiates policy compliance check for the specified connection management ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Policy Compliance"
+                ],
+                "summary": "Start policy compliance check",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Connection Management ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Policy compliance check started",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid connection management ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/queries/session/kill/{cntid}": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Kills a specific database session using connection management ID and session ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Session Management"
+                ],
+                "summary": "Kill database session",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Connection Management ID",
+                        "name": "cntid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Session ID to kill",
+                        "name": "killSessionRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.KillSessionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Kill session command executed successfully for session ID on connection ID",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.JobStartResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.StandardErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.JobProcessingErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/queries/upload": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Starts background job to upload file from client to server via VeloArtifact. The upload operation uses the source job ID to locate the file on the client, then uploads it to the specified path on the server.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Upload"
+                ],
+                "summary": "Execute file upload operation",
+                "parameters": [
+                    {
+                        "description": "Upload request with connection ID, source job ID, file name, and file path",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UploadRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Upload job started successfully with job ID",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.UploadJobStartResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or validation error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.UploadValidationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error during upload execution",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.UploadErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "description": "Returns 200 while the process is serving HTTP requests. Does not check dependencies, so a failing database does not get the pod restarted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "Process is alive",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LivenessResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Checks the config database, bootstrap caches, agent API, exportDBFPolicy binary and working directories. Returns 503 when a critical check fails; failures of non-critical checks report status degraded with 200.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "Ready to serve traffic",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReadinessResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "A critical dependency is unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReadinessResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "controllers.ActorAssignmentErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "failed to assign actors to group"
+                }
+            }
+        },
+        "controllers.ActorAssignmentRequest": {
+            "type": "object",
+            "properties": {
+                "actor_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        521,
+                        522,
+                        523
+                    ]
+                }
+            }
+        },
+        "controllers.ActorAssignmentResult": {
+            "type": "object",
+            "properties": {
+                "actors_assigned": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        10,
+                        20,
+                        30
+                    ]
+                },
+                "already_assigned": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        5
+                    ]
+                },
+                "partial_success": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "total_executions": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "velo_jobs_failed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.VeloExecutionError"
+                    }
+                },
+                "velo_jobs_succeeded": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "batch_conn_1_group_5_allow_15_commands_1729800000"
+                    ]
+                }
+            }
+        },
+        "controllers.ActorCreationErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "failed to create actor"
+                }
+            }
+        },
+        "controllers.ActorNotFoundResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "record not found"
+                }
+            }
+        },
+        "controllers.ActorRemovalResult": {
+            "type": "object",
+            "properties": {
+                "actors_removed": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        10,
+                        20
+                    ]
+                },
+                "not_assigned": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        99
+                    ]
+                },
+                "partial_success": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "total_executions": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "velo_jobs_failed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.VeloExecutionError"
+                    }
+                },
+                "velo_jobs_succeeded": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "batch_conn_1_group_5_deny_10_commands_1729800001"
+                    ]
+                }
+            }
+        },
+        "controllers.ActorResponse": {
+            "type": "object",
+            "properties": {
+                "cntid": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "db_client": {
+                    "type": "string",
+                    "example": "mysql_client"
+                },
+                "dbuser": {
+                    "type": "string",
+                    "example": "testuser"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Test database user"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 521
+                },
+                "ip_address": {
+                    "type": "string",
+                    "example": "192.168.1.100"
+                },
+                "osuser": {
+                    "type": "string",
+                    "example": "root"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "active"
+                }
+            }
+        },
+        "controllers.BackupErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "failed to execute backup"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "connection management with id=123 not found"
+                }
+            }
+        },
+        "controllers.BackupJobStartResponse": {
+            "type": "object",
+            "properties": {
+                "job_id": {
+                    "type": "string",
+                    "example": "backup_123"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Backup job started successfully with 3 steps"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                }
+            }
+        },
+        "controllers.BackupValidationErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "validation failed"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "invalid job ID: must be greater than 0"
+                }
+            }
+        },
+        "controllers.BulkCreateResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Inserted 5 new records"
+                }
+            }
+        },
+        "controllers.BulkDeleteDBPolicyRequest": {
+            "type": "object",
+            "required": [
+                "policy_ids"
+            ],
+            "properties": {
+                "policy_ids": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "controllers.BulkDeleteDBPolicyResponse": {
+            "type": "object",
+            "properties": {
+                "deleted_count": {
+                    "type": "integer"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "failed_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "controllers.BulkPolicyUpdateJobResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Bulk policy update background job started: F.C4LG5UMHSAPEI0J. Adding 6 policies, removing 3 policies."
+                },
+                "status": {
+                    "type": "string",
+                    "example": "job_started"
+                }
+            }
+        },
+        "controllers.BulkPolicyUpdateRequest": {
+            "type": "object",
+            "required": [
+                "cntmgt_id",
+                "dbactormgt_id",
+                "dbmgt_id",
+                "new_object_mgts",
+                "new_policy_defaults"
+            ],
+            "properties": {
+                "cntmgt_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "dbactormgt_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "dbmgt_id": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "new_object_mgts": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        100,
+                        101,
+                        102
+                    ]
+                },
+                "new_policy_defaults": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        10,
+                        11,
+                        12
+                    ]
+                }
+            }
+        },
+        "controllers.ConnectionMgtRequest": {
+            "type": "object",
+            "properties": {
+                "cntmgt": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "controllers.DBActorMgtCreateRequest": {
+            "type": "object",
+            "properties": {
+                "cntmgt": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "db_client": {
+                    "type": "string",
+                    "example": "mysql_client"
+                },
+                "dbuser": {
+                    "type": "string",
+                    "example": "testuser"
+                },
+                "dbuserpaswd": {
+                    "type": "string",
+                    "example": "password123"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Actor description"
+                },
+                "ip_address": {
+                    "type": "string",
+                    "example": "192.168.1.100"
+                },
+                "osuser": {
+                    "type": "string",
+                    "example": "root"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "active"
+                }
+            }
+        },
+        "controllers.DBActorMgtCreateResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 123
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Database Actor was created successfully"
+                }
+            }
+        },
+        "controllers.DBActorMgtDeleteResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Database Actor was deleted"
+                }
+            }
+        },
+        "controllers.DBActorMgtUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "dbuser": {
+                    "type": "string",
+                    "example": "updated_user"
+                },
+                "dbuserpaswd": {
+                    "type": "string",
+                    "example": "new_password123"
+                },
+                "ip_address": {
+                    "type": "string",
+                    "example": "192.168.1.101"
+                }
+            }
+        },
+        "controllers.DBActorMgtUpdateResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Database Actor was updated"
+                }
+            }
+        },
+        "controllers.DBMgtCreateRequest": {
+            "type": "object",
+            "properties": {
+                "cntmgt": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "dbname": {
+                    "type": "string",
+                    "example": "test_database"
+                },
+                "dbtype": {
+                    "type": "string",
+                    "example": "mysql"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "active"
+                }
+            }
+        },
+        "controllers.DBMgtCreateResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 123
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Database was created successfully"
+                }
+            }
+        },
+        "controllers.DBMgtDeleteResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Database was deleted successfully"
+                }
+            }
+        },
+        "controllers.DBObjectMgtCreateRequest": {
+            "type": "object",
+            "properties": {
+                "dbmgt": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Table description"
+                },
+                "objectName": {
+                    "type": "string",
+                    "example": "table1"
+                },
+                "objectTypeId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "sqlParam": {
+                    "type": "string",
+                    "example": "param1=value1"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "active"
+                }
+            }
+        },
+        "controllers.DBObjectMgtCreateResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 123
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Object was created successfully"
+                }
+            }
+        },
+        "controllers.DBObjectMgtDeleteResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Object was deleted"
+                }
+            }
+        },
+        "controllers.DBObjectMgtItem": {
+            "type": "object",
+            "properties": {
+                "dbmgt": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Table description"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "objectName": {
+                    "type": "string",
+                    "example": "table1"
+                },
+                "objectTypeId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "sqlParam": {
+                    "type": "string",
+                    "example": "param1=value1"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "active"
+                }
+            }
+        },
+        "controllers.DBObjectMgtListResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.DBObjectMgtItem"
+                    }
+                }
+            }
+        },
+        "controllers.DBObjectMgtUpdateResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Object was updated"
+                }
+            }
+        },
+        "controllers.DBPolicyCreateRequest": {
+            "type": "object",
+            "properties": {
+                "cntmgt": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "dbactormgt": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "dbmgt": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "dbobjectmgt": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "dbpolicydefault": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Policy description"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "active"
+                }
+            }
+        },
+        "controllers.DBPolicyCreateResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 123
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Policy created"
+                }
+            }
+        },
+        "controllers.DBPolicyDeleteResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Policy deleted"
+                }
+            }
+        },
+        "controllers.DBPolicyUpdateResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Policy updated"
+                }
+            }
+        },
+        "controllers.DatabaseConnectionErrorResponse": {
             "type": "object",
             "properties": {
                 "error": {
                     "type": "string",
-                    "example": "failed to assign actors to group"
+                    "example": "database connection failed"
                 }
             }
         },
-        "controllers.ActorAssignmentRequest": {
+        "controllers.DatabaseCreationErrorResponse": {
             "type": "object",
             "properties": {
-                "actor_ids": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    },
-                    "example": [
-                        521,
-                        522,
-                        523
-                    ]
+                "error": {
+                    "type": "string",
+                    "example": "failed to create database"
                 }
             }
         },
-        "controllers.ActorAssignmentResult": {
+        "controllers.DownloadErrorResponse": {
             "type": "object",
             "properties": {
-                "actors_assigned": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    },
-                    "example": [
-                        10,
-                        20,
-                        30
-                    ]
-                },
-                "already_assigned": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    },
-                    "example": [
-                        5
-                    ]
-                },
-                "partial_success": {
-                    "type": "boolean",
-                    "example": false
+                "error": {
+                    "type": "string",
+                    "example": "internal server error"
                 },
-                "success": {
-                    "type": "boolean",
-                    "example": true
+                "message": {
+                    "type": "string",
+                    "example": "failed to execute filedownload"
+                }
+            }
+        },
+        "controllers.DownloadJobStartResponse": {
+            "type": "object",
+            "properties": {
+                "job_id": {
+                    "type": "string",
+                    "example": "filedownload_config_1234567890"
                 },
-                "total_executions": {
-                    "type": "integer",
-                    "example": 1
+                "message": {
+                    "type": "string",
+                    "example": "Download job started successfully for file: config.json"
                 },
-                "velo_jobs_failed": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.VeloExecutionError"
-                    }
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                }
+            }
+        },
+        "controllers.DownloadValidationErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "validation error"
                 },
-                "velo_jobs_succeeded": {
+                "message": {
+                    "type": "string",
+                    "example": "source_path is required"
+                }
+            }
+        },
+        "controllers.EmptyListValidationResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "policy IDs list cannot be empty"
+                }
+            }
+        },
+        "controllers.GroupAssignmentRequest": {
+            "type": "object",
+            "properties": {
+                "group_ids": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "type": "integer"
                     },
                     "example": [
-                        "batch_conn_1_group_5_allow_15_commands_1729800000"
+                        1,
+                        2,
+                        4
                     ]
                 }
             }
         },
-        "controllers.ActorCreationErrorResponse": {
+        "controllers.GroupAssignmentResponse": {
             "type": "object",
             "properties": {
-                "error": {
+                "message": {
                     "type": "string",
-                    "example": "failed to create actor"
+                    "example": "Successfully assigned 3 policies to group"
                 }
             }
         },
-        "controllers.ActorNotFoundResponse": {
+        "controllers.GroupAssignmentsUpdateRequest": {
             "type": "object",
+            "required": [
+                "actor_ids",
+                "policy_ids"
+            ],
             "properties": {
-                "error": {
-                    "type": "string",
-                    "example": "record not found"
+                "actor_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        521,
+                        522,
+                        523
+                    ]
+                },
+                "policy_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2,
+                        3
+                    ]
                 }
             }
         },
-        "controllers.ActorRemovalResult": {
+        "controllers.GroupAssignmentsUpdateResult": {
             "type": "object",
             "properties": {
-                "actors_removed": {
+                "actors_added": {
                     "type": "array",
                     "items": {
                         "type": "integer"
                     },
                     "example": [
-                        10,
-                        20
+                        521,
+                        523
                     ]
                 },
-                "not_assigned": {
+                "actors_removed": {
                     "type": "array",
                     "items": {
                         "type": "integer"
                     },
                     "example": [
-                        99
+                        522
                     ]
                 },
-                "partial_success": {
-                    "type": "boolean",
-                    "example": false
+                "policies_added": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        3
+                    ]
                 },
-                "success": {
-                    "type": "boolean",
-                    "example": true
+                "policies_removed": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        2
+                    ]
                 },
                 "total_executions": {
                     "type": "integer",
-                    "example": 1
-                },
-                "velo_jobs_failed": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.VeloExecutionError"
-                    }
+                    "example": 2
                 },
-                "velo_jobs_succeeded": {
+                "velo_jobs_created": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     },
                     "example": [
-                        "batch_conn_1_group_5_deny_10_commands_1729800001"
+                        "job_1_allow_1735566780",
+                        "job_1_deny_1735566781"
                     ]
                 }
             }
         },
-        "controllers.ActorResponse": {
+        "controllers.GroupBulkAssignmentResponse": {
             "type": "object",
             "properties": {
-                "cntid": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "db_client": {
-                    "type": "string",
-                    "example": "mysql_client"
+                "change_requests": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        12
+                    ]
                 },
-                "dbuser": {
-                    "type": "string",
-                    "example": "testuser"
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "[\"Group 3: group not found\"]"
+                    ]
                 },
-                "description": {
+                "message": {
                     "type": "string",
-                    "example": "Test database user"
+                    "example": "Policy assigned to 2/3 groups"
                 },
-                "id": {
+                "success_count": {
                     "type": "integer",
-                    "example": 521
-                },
-                "ip_address": {
-                    "type": "string",
-                    "example": "192.168.1.100"
-                },
-                "osuser": {
-                    "type": "string",
-                    "example": "root"
-                },
-                "password": {
-                    "type": "string",
-                    "example": "password123"
+                    "example": 2
                 },
-                "status": {
-                    "type": "string",
-                    "example": "active"
+                "total_count": {
+                    "type": "integer",
+                    "example": 3
                 }
             }
         },
-        "controllers.BackupErrorResponse": {
+        "controllers.GroupCodeConflictResponse": {
             "type": "object",
             "properties": {
                 "error": {
                     "type": "string",
-                    "example": "failed to execute backup"
-                },
-                "message": {
-                    "type": "string",
-                    "example": "connection management with id=123 not found"
+                    "example": "group with code 'MYSQL_DEV' already exists"
                 }
             }
         },
-        "controllers.BackupJobStartResponse": {
+        "controllers.GroupCreateRequest": {
             "type": "object",
             "properties": {
-                "job_id": {
+                "code": {
                     "type": "string",
-                    "example": "backup_123"
+                    "example": "MYSQL_DEV"
                 },
-                "message": {
+                "database_type_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "description": {
                     "type": "string",
-                    "example": "Backup job started successfully with 3 steps"
+                    "example": "MySQL development team group"
                 },
-                "status": {
+                "group_type": {
                     "type": "string",
-                    "example": "success"
-                }
-            }
-        },
-        "controllers.BackupValidationErrorResponse": {
-            "type": "object",
-            "properties": {
-                "error": {
+                    "example": "CUSTOM"
+                },
+                "is_active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "is_template": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "metadata": {
                     "type": "string",
-                    "example": "validation failed"
+                    "example": "{\"department\": \"IT\"}"
                 },
-                "message": {
+                "name": {
                     "type": "string",
-                    "example": "invalid job ID: must be greater than 0"
+                    "example": "MySQL Developers"
+                },
+                "parent_group_id": {
+                    "type": "integer",
+                    "example": 1
                 }
             }
         },
-        "controllers.BulkCreateResponse": {
+        "controllers.GroupCreateResponse": {
             "type": "object",
             "properties": {
-                "message": {
+                "code": {
                     "type": "string",
-                    "example": "Inserted 5 new records"
-                }
-            }
-        },
-        "controllers.BulkDeleteDBPolicyRequest": {
-            "type": "object",
-            "required": [
-                "policy_ids"
-            ],
-            "properties": {
-                "policy_ids": {
-                    "type": "array",
-                    "minItems": 1,
-                    "items": {
-                        "type": "integer"
-                    }
-                }
-            }
-        },
-        "controllers.BulkDeleteDBPolicyResponse": {
-            "type": "object",
-            "properties": {
-                "deleted_count": {
-                    "type": "integer"
+                    "example": "MYSQL_DEV"
                 },
-                "errors": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
                 },
-                "failed_ids": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
+                "database_type_id": {
+                    "type": "integer",
+                    "example": 1
                 },
-                "message": {
-                    "type": "string"
+                "description": {
+                    "type": "string",
+                    "example": "MySQL development team group"
                 },
-                "success": {
-                    "type": "boolean"
+                "group_type": {
+                    "type": "string",
+                    "example": "CUSTOM"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 123
+                },
+                "is_active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "is_template": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "metadata": {
+                    "type": "string",
+                    "example": "{\"department\": \"IT\"}"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "MySQL Developers"
+                },
+                "parent_group_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
                 }
             }
         },
-        "controllers.BulkPolicyUpdateJobResponse": {
+        "controllers.GroupCreationErrorResponse": {
             "type": "object",
             "properties": {
-                "message": {
-                    "type": "string",
-                    "example": "Bulk policy update background job started: F.C4LG5UMHSAPEI0J. Adding 6 policies, removing 3 policies."
-                },
-                "status": {
+                "error": {
                     "type": "string",
-                    "example": "job_started"
+                    "example": "failed to create group"
                 }
             }
         },
-        "controllers.BulkPolicyUpdateRequest": {
+        "controllers.GroupDetailsResponse": {
             "type": "object",
-            "required": [
-                "cntmgt_id",
-                "dbactormgt_id",
-                "dbmgt_id",
-                "new_object_mgts",
-                "new_policy_defaults"
-            ],
             "properties": {
-                "cntmgt_id": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "dbactormgt_id": {
-                    "type": "integer",
-                    "example": 3
-                },
-                "dbmgt_id": {
-                    "type": "integer",
-                    "example": 5
-                },
-                "new_object_mgts": {
+                "actors": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
-                    },
-                    "example": [
-                        100,
-                        101,
-                        102
-                    ]
+                        "$ref": "#/definitions/controllers.ActorResponse"
+                    }
                 },
-                "new_policy_defaults": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    },
-                    "example": [
-                        10,
-                        11,
-                        12
-                    ]
+                "group": {
+                    "$ref": "#/definitions/controllers.GroupCreateResponse"
+                },
+                "policies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.GroupListPolicyResponse"
+                    }
                 }
             }
         },
-        "controllers.ConnectionMgtRequest": {
+        "controllers.GroupHasChildrenResponse": {
             "type": "object",
             "properties": {
-                "cntmgt": {
-                    "type": "integer",
-                    "example": 1
+                "error": {
+                    "type": "string",
+                    "example": "cannot delete group with child groups. Found 2 child groups"
                 }
             }
         },
-        "controllers.DBActorMgtCreateRequest": {
+        "controllers.GroupListPolicyResponse": {
             "type": "object",
             "properties": {
-                "cntmgt": {
+                "category_id": {
                     "type": "integer",
                     "example": 1
                 },
-                "db_client": {
+                "code": {
                     "type": "string",
-                    "example": "mysql_client"
+                    "example": "SELECT_PERM"
                 },
-                "dbuser": {
+                "created_at": {
                     "type": "string",
-                    "example": "testuser"
+                    "example": "2024-01-01T00:00:00Z"
                 },
-                "dbuserpaswd": {
+                "database_type_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "dbpolicydefault_id": {
                     "type": "string",
-                    "example": "password123"
+                    "example": "1,2,3"
                 },
                 "description": {
                     "type": "string",
-                    "example": "Actor description"
+                    "example": "Allow SELECT operations"
                 },
-                "ip_address": {
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "is_active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "is_ddl": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "is_dml": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "is_system": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "metadata": {
                     "type": "string",
-                    "example": "192.168.1.100"
+                    "example": "{\"category\": \"read\"}"
                 },
-                "osuser": {
+                "name": {
                     "type": "string",
-                    "example": "root"
+                    "example": "SELECT Permission"
                 },
-                "status": {
+                "risk_level": {
                     "type": "string",
-                    "example": "active"
+                    "example": "LOW"
                 }
             }
         },
-        "controllers.DBActorMgtCreateResponse": {
+        "controllers.GroupNotFoundResponse": {
             "type": "object",
             "properties": {
-                "id": {
-                    "type": "integer",
-                    "example": 123
-                },
-                "message": {
+                "error": {
                     "type": "string",
-                    "example": "Database Actor was created successfully"
+                    "example": "group with id=1 not found"
                 }
             }
         },
-        "controllers.DBActorMgtDeleteResponse": {
+        "controllers.InternalServerErrorResponse": {
             "type": "object",
             "properties": {
-                "message": {
+                "error": {
                     "type": "string",
-                    "example": "Database Actor was deleted"
+                    "example": "database connection failed"
                 }
             }
         },
-        "controllers.DBActorMgtUpdateRequest": {
+        "controllers.InvalidActorIDResponse": {
             "type": "object",
             "properties": {
-                "dbuser": {
-                    "type": "string",
-                    "example": "updated_user"
-                },
-                "dbuserpaswd": {
-                    "type": "string",
-                    "example": "new_password123"
-                },
-                "ip_address": {
+                "error": {
                     "type": "string",
-                    "example": "192.168.1.101"
+                    "example": "invalid actor ID"
                 }
             }
         },
-        "controllers.DBActorMgtUpdateResponse": {
+        "controllers.InvalidGroupIDResponse": {
             "type": "object",
             "properties": {
-                "message": {
+                "error": {
                     "type": "string",
-                    "example": "Database Actor was updated"
+                    "example": "invalid group ID"
                 }
             }
         },
-        "controllers.DBMgtCreateRequest": {
+        "controllers.InvalidPolicyIDResponse": {
             "type": "object",
             "properties": {
-                "cntmgt": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "dbname": {
-                    "type": "string",
-                    "example": "test_database"
-                },
-                "dbtype": {
-                    "type": "string",
-                    "example": "mysql"
-                },
-                "status": {
+                "error": {
                     "type": "string",
-                    "example": "active"
+                    "example": "invalid policy ID"
                 }
             }
         },
-        "controllers.DBMgtCreateResponse": {
+        "controllers.JobCancelResponse": {
             "type": "object",
             "properties": {
-                "id": {
-                    "type": "integer",
-                    "example": 123
+                "data": {
+                    "$ref": "#/definitions/controllers.JobStatusData"
                 },
                 "message": {
                     "type": "string",
-                    "example": "Database was created successfully"
+                    "example": "Job cancelled successfully"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
                 }
             }
         },
-        "controllers.DBMgtDeleteResponse": {
+        "controllers.JobDeleteResponse": {
             "type": "object",
             "properties": {
                 "message": {
                     "type": "string",
-                    "example": "Database was deleted successfully"
-                }
-            }
-        },
-        "controllers.DBObjectMgtCreateRequest": {
-            "type": "object",
-            "properties": {
-                "dbmgt": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "description": {
-                    "type": "string",
-                    "example": "Table description"
-                },
-                "objectName": {
-                    "type": "string",
-                    "example": "table1"
-                },
-                "objectTypeId": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "sqlParam": {
-                    "type": "string",
-                    "example": "param1=value1"
+                    "example": "Job removed from monitoring successfully"
                 },
-                "status": {
-                    "type": "string",
-                    "example": "active"
+                "success": {
+                    "type": "boolean",
+                    "example": true
                 }
             }
         },
-        "controllers.DBObjectMgtCreateResponse": {
+        "controllers.JobNotCancellableErrorResponse": {
             "type": "object",
             "properties": {
-                "id": {
-                    "type": "integer",
-                    "example": 123
-                },
                 "message": {
                     "type": "string",
-                    "example": "Object was created successfully"
+                    "example": "Job cannot be cancelled in status completed"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": false
                 }
             }
         },
-        "controllers.DBObjectMgtDeleteResponse": {
+        "controllers.JobNotFoundErrorResponse": {
             "type": "object",
             "properties": {
                 "message": {
                     "type": "string",
-                    "example": "Object was deleted"
+                    "example": "Job not found"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": false
                 }
             }
         },
-        "controllers.DBObjectMgtItem": {
+        "controllers.JobNotificationRequest": {
             "type": "object",
+            "required": [
+                "fileName",
+                "md5Hash"
+            ],
             "properties": {
-                "dbmgt": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "description": {
-                    "type": "string",
-                    "example": "Table description"
-                },
-                "id": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "objectName": {
-                    "type": "string",
-                    "example": "table1"
-                },
-                "objectTypeId": {
-                    "type": "integer",
-                    "example": 1
+                "fileName": {
+                    "type": "string"
                 },
-                "sqlParam": {
-                    "type": "string",
-                    "example": "param1=value1"
+                "md5Hash": {
+                    "type": "string"
                 },
-                "status": {
-                    "type": "string",
-                    "example": "active"
+                "success": {
+                    "type": "boolean"
                 }
             }
         },
-        "controllers.DBObjectMgtListResponse": {
+        "controllers.JobProcessingErrorResponse": {
             "type": "object",
             "properties": {
-                "message": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.DBObjectMgtItem"
-                    }
+                "error": {
+                    "type": "string",
+                    "example": "failed to start background job"
                 }
             }
         },
-        "controllers.DBObjectMgtUpdateResponse": {
+        "controllers.JobStartResponse": {
             "type": "object",
             "properties": {
                 "message": {
                     "type": "string",
-                    "example": "Object was updated"
+                    "example": "Background job started: job_12345"
                 }
             }
         },
-        "controllers.DBPolicyCreateRequest": {
-            "type": "object",
-            "properties": {
-                "cntmgt": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "dbactormgt": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "dbmgt": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "dbobjectmgt": {
-                    "type": "integer",
-                    "example": 1
+        "controllers.JobStatusData": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
                 },
-                "dbpolicydefault": {
+                "dbmgt_id": {
                     "type": "integer",
                     "example": 1
                 },
-                "description": {
+                "id": {
                     "type": "string",
-                    "example": "Policy description"
+                    "example": "job_12345"
                 },
                 "status": {
                     "type": "string",
-                    "example": "active"
+                    "example": "completed"
                 }
             }
         },
-        "controllers.DBPolicyCreateResponse": {
+        "controllers.JobStatusErrorResponse": {
             "type": "object",
             "properties": {
-                "id": {
-                    "type": "integer",
-                    "example": 123
-                },
                 "message": {
                     "type": "string",
-                    "example": "Policy created"
+                    "example": "Job ID is required"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": false
                 }
             }
         },
-        "controllers.DBPolicyDeleteResponse": {
+        "controllers.JobStatusListResponse": {
             "type": "object",
             "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.JobStatusData"
+                    }
+                },
                 "message": {
                     "type": "string",
-                    "example": "Policy deleted"
+                    "example": "All jobs status retrieved successfully"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
                 }
             }
         },
-        "controllers.DBPolicyUpdateResponse": {
+        "controllers.JobStatusResponse": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "controllers.JobStatusSingleResponse": {
             "type": "object",
             "properties": {
+                "data": {
+                    "$ref": "#/definitions/controllers.JobStatusData"
+                },
                 "message": {
                     "type": "string",
-                    "example": "Policy updated"
+                    "example": "Job status retrieved successfully"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
                 }
             }
         },
-        "controllers.DatabaseConnectionErrorResponse": {
+        "controllers.KillSessionRequest": {
+            "type": "object",
+            "required": [
+                "session_id"
+            ],
+            "properties": {
+                "session_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.NotFoundResponse": {
             "type": "object",
             "properties": {
                 "error": {
                     "type": "string",
-                    "example": "database connection failed"
+                    "example": "record not found"
                 }
             }
         },
-        "controllers.DatabaseCreationErrorResponse": {
+        "controllers.ObjectCreationErrorResponse": {
             "type": "object",
             "properties": {
                 "error": {
                     "type": "string",
-                    "example": "failed to create database"
+                    "example": "failed to create object"
                 }
             }
         },
-        "controllers.DownloadErrorResponse": {
+        "controllers.PDBCreateRequest": {
             "type": "object",
             "properties": {
-                "error": {
+                "cntmgt": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "pdbname": {
                     "type": "string",
-                    "example": "internal server error"
+                    "example": "PDB3"
                 },
-                "message": {
+                "sql_param": {
                     "type": "string",
-                    "example": "failed to execute filedownload"
+                    "example": ""
                 }
             }
         },
-        "controllers.DownloadJobStartResponse": {
+        "controllers.PDBCreateResponse": {
             "type": "object",
             "properties": {
-                "job_id": {
-                    "type": "string",
-                    "example": "filedownload_config_1234567890"
+                "id": {
+                    "type": "integer",
+                    "example": 123
                 },
                 "message": {
                     "type": "string",
-                    "example": "Download job started successfully for file: config.json"
-                },
-                "status": {
-                    "type": "string",
-                    "example": "success"
+                    "example": "PDB was created successfully"
                 }
             }
         },
-        "controllers.DownloadValidationErrorResponse": {
+        "controllers.PDBDeleteRequest": {
             "type": "object",
             "properties": {
-                "error": {
+                "sql_param": {
                     "type": "string",
-                    "example": "validation error"
-                },
+                    "example": ""
+                }
+            }
+        },
+        "controllers.PDBDeleteResponse": {
+            "type": "object",
+            "properties": {
                 "message": {
                     "type": "string",
-                    "example": "source_path is required"
+                    "example": "PDB was dropped successfully"
                 }
             }
         },
-        "controllers.EmptyListValidationResponse": {
+        "controllers.PDBGetAllRequest": {
             "type": "object",
             "properties": {
-                "error": {
-                    "type": "string",
-                    "example": "policy IDs list cannot be empty"
+                "cntmgt": {
+                    "type": "integer",
+                    "example": 1
                 }
             }
         },
-        "controllers.GroupAssignmentRequest": {
+        "controllers.PDBUpdateRequest": {
             "type": "object",
             "properties": {
-                "group_ids": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    },
-                    "example": [
-                        1,
-                        2,
-                        4
-                    ]
+                "sql_param": {
+                    "type": "string",
+                    "example": "4f50454e"
                 }
             }
         },
-        "controllers.GroupAssignmentResponse": {
+        "controllers.PDBUpdateResponse": {
             "type": "object",
             "properties": {
                 "message": {
                     "type": "string",
-                    "example": "Successfully assigned 3 policies to group"
+                    "example": "PDB was altered successfully"
                 }
             }
         },
-        "controllers.GroupAssignmentsUpdateRequest": {
+        "controllers.PolicyAssignmentErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "failed to assign policies to group"
+                }
+            }
+        },
+        "controllers.PolicyAssignmentRequest": {
             "type": "object",
-            "required": [
-                "actor_ids",
-                "policy_ids"
-            ],
             "properties": {
-                "actor_ids": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    },
-                    "example": [
-                        521,
-                        522,
-                        523
-                    ]
-                },
                 "policy_ids": {
                     "type": "array",
                     "items": {
@@ -3306,710 +5509,1011 @@ const docTemplate = `{
                 }
             }
         },
-        "controllers.GroupAssignmentsUpdateResult": {
+        "controllers.PolicyCreationErrorResponse": {
             "type": "object",
             "properties": {
-                "actors_added": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    },
-                    "example": [
-                        521,
-                        523
-                    ]
-                },
-                "actors_removed": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    },
-                    "example": [
-                        522
-                    ]
-                },
-                "policies_added": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    },
-                    "example": [
-                        1,
-                        3
-                    ]
-                },
-                "policies_removed": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    },
-                    "example": [
-                        2
-                    ]
-                },
-                "total_executions": {
-                    "type": "integer",
-                    "example": 2
-                },
-                "velo_jobs_created": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "job_1_allow_1735566780",
-                        "job_1_deny_1735566781"
-                    ]
+                "error": {
+                    "type": "string",
+                    "example": "failed to create policy"
                 }
             }
         },
-        "controllers.GroupBulkAssignmentResponse": {
+        "controllers.PolicyNotFoundResponse": {
             "type": "object",
             "properties": {
-                "errors": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "[\"Group 3: group not found\"]"
-                    ]
-                },
-                "message": {
+                "error": {
                     "type": "string",
-                    "example": "Policy assigned to 2/3 groups"
-                },
-                "success_count": {
-                    "type": "integer",
-                    "example": 2
-                },
-                "total_count": {
-                    "type": "integer",
-                    "example": 3
+                    "example": "record not found"
                 }
             }
         },
-        "controllers.GroupCodeConflictResponse": {
+        "controllers.StandardErrorResponse": {
             "type": "object",
             "properties": {
                 "error": {
                     "type": "string",
-                    "example": "group with code 'MYSQL_DEV' already exists"
+                    "example": "Invalid request body"
                 }
             }
         },
-        "controllers.GroupCreateRequest": {
+        "controllers.SuccessResponse": {
             "type": "object",
             "properties": {
-                "code": {
-                    "type": "string",
-                    "example": "MYSQL_DEV"
-                },
-                "database_type_id": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "description": {
-                    "type": "string",
-                    "example": "MySQL development team group"
-                },
-                "group_type": {
+                "message": {
                     "type": "string",
-                    "example": "CUSTOM"
-                },
-                "is_active": {
-                    "type": "boolean",
-                    "example": true
-                },
-                "is_template": {
-                    "type": "boolean",
-                    "example": false
-                },
-                "metadata": {
+                    "example": "Group deleted successfully"
+                }
+            }
+        },
+        "controllers.UploadErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
                     "type": "string",
-                    "example": "{\"department\": \"IT\"}"
+                    "example": "failed to execute upload"
                 },
-                "name": {
+                "message": {
                     "type": "string",
-                    "example": "MySQL Developers"
-                },
-                "parent_group_id": {
-                    "type": "integer",
-                    "example": 1
+                    "example": "connection management with id=123 not found"
                 }
             }
         },
-        "controllers.GroupCreateResponse": {
+        "controllers.UploadJobStartResponse": {
             "type": "object",
             "properties": {
-                "code": {
-                    "type": "string",
-                    "example": "MYSQL_DEV"
-                },
-                "created_at": {
+                "job_id": {
                     "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
-                },
-                "database_type_id": {
-                    "type": "integer",
-                    "example": 1
+                    "example": "F.C39AKJDLAKEU8VH"
                 },
-                "description": {
+                "message": {
                     "type": "string",
-                    "example": "MySQL development team group"
+                    "example": "Upload job started successfully for file: backup_20240101.sql"
                 },
-                "group_type": {
+                "status": {
                     "type": "string",
-                    "example": "CUSTOM"
-                },
-                "id": {
-                    "type": "integer",
-                    "example": 123
-                },
-                "is_active": {
-                    "type": "boolean",
-                    "example": true
-                },
-                "is_template": {
-                    "type": "boolean",
-                    "example": false
-                },
-                "metadata": {
+                    "example": "success"
+                }
+            }
+        },
+        "controllers.UploadValidationErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
                     "type": "string",
-                    "example": "{\"department\": \"IT\"}"
+                    "example": "validation failed"
                 },
-                "name": {
+                "message": {
                     "type": "string",
-                    "example": "MySQL Developers"
-                },
-                "parent_group_id": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "updated_at": {
+                    "example": "invalid connection ID: must be greater than 0"
+                }
+            }
+        },
+        "controllers.ValidationErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
                     "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
+                    "example": "Validation failed: dbuser is required"
                 }
             }
         },
-        "controllers.GroupCreationErrorResponse": {
+        "controllers.VeloExecutionError": {
             "type": "object",
             "properties": {
+                "connection_id": {
+                    "type": "integer",
+                    "example": 2
+                },
                 "error": {
                     "type": "string",
-                    "example": "failed to create group"
+                    "example": "connection timeout: failed to connect to client"
+                },
+                "operation": {
+                    "type": "string",
+                    "example": "allow"
                 }
             }
         },
-        "controllers.GroupDetailsResponse": {
+        "dto.ActorPolicyConflicts": {
             "type": "object",
             "properties": {
-                "actors": {
+                "conflicts": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/controllers.ActorResponse"
+                        "$ref": "#/definitions/dto.PolicyConflict"
                     }
                 },
-                "group": {
-                    "$ref": "#/definitions/controllers.GroupCreateResponse"
+                "dbactormgt_id": {
+                    "type": "integer"
                 },
-                "policies": {
+                "dbuser": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AuditEventListResponse": {
+            "type": "object",
+            "properties": {
+                "events": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/controllers.GroupListPolicyResponse"
+                        "$ref": "#/definitions/models.AuditEvent"
                     }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
                 }
             }
         },
-        "controllers.GroupHasChildrenResponse": {
+        "dto.AuditVerifyResponse": {
             "type": "object",
             "properties": {
-                "error": {
+                "broken_at_id": {
+                    "type": "integer"
+                },
+                "checked": {
+                    "type": "integer"
+                },
+                "head_event_id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "valid": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.ChangeRequestDecisionRequest": {
+            "type": "object",
+            "properties": {
+                "comment": {
                     "type": "string",
-                    "example": "cannot delete group with child groups. Found 2 child groups"
+                    "maxLength": 1024
                 }
             }
         },
-        "controllers.GroupListPolicyResponse": {
+        "dto.ChangeRequestDetailResponse": {
             "type": "object",
             "properties": {
-                "category_id": {
-                    "type": "integer",
-                    "example": 1
+                "applied_at": {
+                    "type": "string"
                 },
-                "code": {
-                    "type": "string",
-                    "example": "SELECT_PERM"
+                "approval_count": {
+                    "type": "integer"
+                },
+                "approvals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ChangeRequestApproval"
+                    }
                 },
                 "created_at": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
+                    "type": "string"
                 },
-                "database_type_id": {
-                    "type": "integer",
-                    "example": 1
+                "decided_at": {
+                    "type": "string"
                 },
-                "dbpolicydefault_id": {
-                    "type": "string",
-                    "example": "1,2,3"
+                "decided_by": {
+                    "type": "string"
                 },
-                "description": {
-                    "type": "string",
-                    "example": "Allow SELECT operations"
+                "error": {
+                    "type": "string"
+                },
+                "group_id": {
+                    "type": "integer"
                 },
                 "id": {
-                    "type": "integer",
-                    "example": 1
+                    "type": "integer"
                 },
-                "is_active": {
-                    "type": "boolean",
-                    "example": true
+                "operation": {
+                    "type": "string"
                 },
-                "is_ddl": {
-                    "type": "boolean",
-                    "example": false
+                "preview": {
+                    "type": "object"
                 },
-                "is_dml": {
-                    "type": "boolean",
-                    "example": true
+                "reason": {
+                    "type": "string"
                 },
-                "is_system": {
-                    "type": "boolean",
-                    "example": false
+                "request": {
+                    "type": "object"
                 },
-                "metadata": {
-                    "type": "string",
-                    "example": "{\"category\": \"read\"}"
+                "requested_by": {
+                    "type": "string"
                 },
-                "name": {
-                    "type": "string",
-                    "example": "SELECT Permission"
+                "required_approvals": {
+                    "type": "integer"
+                },
+                "result": {
+                    "type": "object"
                 },
                 "risk_level": {
-                    "type": "string",
-                    "example": "LOW"
-                }
-            }
-        },
-        "controllers.GroupNotFoundResponse": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "type": "string",
-                    "example": "group with id=1 not found"
-                }
-            }
-        },
-        "controllers.InternalServerErrorResponse": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "type": "string",
-                    "example": "database connection failed"
-                }
-            }
-        },
-        "controllers.InvalidActorIDResponse": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "type": "string",
-                    "example": "invalid actor ID"
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "controllers.InvalidGroupIDResponse": {
+        "dto.ChangeRequestListResponse": {
             "type": "object",
             "properties": {
-                "error": {
-                    "type": "string",
-                    "example": "invalid group ID"
+                "change_requests": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ChangeRequest"
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
                 }
             }
         },
-        "controllers.InvalidPolicyIDResponse": {
+        "dto.ConfigReloadResponse": {
             "type": "object",
             "properties": {
-                "error": {
-                    "type": "string",
-                    "example": "invalid policy ID"
+                "applied": {
+                    "description": "Now in effect",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "restart_required": {
+                    "description": "Changed but only read at startup",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "controllers.JobDeleteResponse": {
+        "dto.DependencyCheck": {
             "type": "object",
             "properties": {
-                "message": {
-                    "type": "string",
-                    "example": "Job removed from monitoring successfully"
+                "critical": {
+                    "type": "boolean"
                 },
-                "success": {
-                    "type": "boolean",
-                    "example": true
+                "detail": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "latency_ms": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "target": {
+                    "type": "string"
                 }
             }
         },
-        "controllers.JobNotFoundErrorResponse": {
+        "dto.DiagnosticsResponse": {
             "type": "object",
             "properties": {
-                "message": {
-                    "type": "string",
-                    "example": "Job not found"
+                "checked_at": {
+                    "type": "string"
                 },
-                "success": {
-                    "type": "boolean",
-                    "example": false
+                "checks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.DependencyCheck"
+                    }
+                },
+                "status": {
+                    "type": "string"
                 }
             }
         },
-        "controllers.JobNotificationRequest": {
+        "dto.DryRunCommand": {
             "type": "object",
-            "required": [
-                "fileName",
-                "md5Hash"
-            ],
             "properties": {
-                "fileName": {
+                "cnt_id": {
+                    "type": "integer"
+                },
+                "cnt_type": {
                     "type": "string"
                 },
-                "md5Hash": {
+                "database": {
                     "type": "string"
                 },
-                "success": {
-                    "type": "boolean"
+                "endpoint": {
+                    "$ref": "#/definitions/models.Endpoint"
+                },
+                "operation": {
+                    "type": "string"
+                },
+                "statements": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "controllers.JobProcessingErrorResponse": {
+        "dto.LivenessResponse": {
             "type": "object",
             "properties": {
-                "error": {
-                    "type": "string",
-                    "example": "failed to start background job"
+                "status": {
+                    "type": "string"
                 }
             }
         },
-        "controllers.JobStartResponse": {
+        "dto.LogLevelRequest": {
             "type": "object",
+            "required": [
+                "level"
+            ],
             "properties": {
-                "message": {
+                "level": {
+                    "description": "DEBUG, INFO, WARN, ERROR or FATAL",
                     "type": "string",
-                    "example": "Background job started: job_12345"
+                    "example": "DEBUG"
                 }
             }
         },
-        "controllers.JobStatusData": {
+        "dto.LogLevelResponse": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string",
-                    "example": "2023-01-01T00:00:00Z"
-                },
-                "dbmgt_id": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "id": {
+                "configured_level": {
+                    "description": "LOG_LEVEL of the loaded config",
                     "type": "string",
-                    "example": "job_12345"
+                    "example": "INFO"
                 },
-                "status": {
+                "level": {
                     "type": "string",
-                    "example": "completed"
+                    "example": "INFO"
                 }
             }
         },
-        "controllers.JobStatusErrorResponse": {
+        "dto.MembershipTransitionListResponse": {
             "type": "object",
             "properties": {
-                "message": {
-                    "type": "string",
-                    "example": "Job ID is required"
+                "page": {
+                    "type": "integer"
                 },
-                "success": {
-                    "type": "boolean",
-                    "example": false
-                }
-            }
-        },
-        "controllers.JobStatusListResponse": {
-            "type": "object",
-            "properties": {
-                "data": {
+                "page_size": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
+                },
+                "transitions": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/controllers.JobStatusData"
+                        "$ref": "#/definitions/models.MembershipTransition"
                     }
-                },
-                "message": {
-                    "type": "string",
-                    "example": "All jobs status retrieved successfully"
-                },
-                "success": {
-                    "type": "boolean",
-                    "example": true
                 }
             }
         },
-        "controllers.JobStatusResponse": {
+        "dto.PolicyConflict": {
             "type": "object",
             "properties": {
-                "data": {},
-                "message": {
+                "against": {
+                    "$ref": "#/definitions/models.DBPolicy"
+                },
+                "dbpolicydefault": {
+                    "type": "integer"
+                },
+                "detail": {
                     "type": "string"
                 },
-                "success": {
+                "group_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "policy": {
+                    "$ref": "#/definitions/models.DBPolicy"
+                },
+                "removable": {
                     "type": "boolean"
+                },
+                "type": {
+                    "type": "string"
                 }
             }
         },
-        "controllers.JobStatusSingleResponse": {
+        "dto.PolicyConflictReport": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/controllers.JobStatusData"
+                "actor_count": {
+                    "type": "integer"
                 },
-                "message": {
-                    "type": "string",
-                    "example": "Job status retrieved successfully"
+                "actors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ActorPolicyConflicts"
+                    }
                 },
-                "success": {
-                    "type": "boolean",
-                    "example": true
+                "cnt_id": {
+                    "type": "integer"
+                },
+                "contradictory": {
+                    "type": "integer"
+                },
+                "policy_count": {
+                    "type": "integer"
+                },
+                "redundant": {
+                    "type": "integer"
+                },
+                "removable_policy_ids": {
+                    "description": "Disabled repeats, whose deletion runs no SQL on the database",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "shadowed": {
+                    "type": "integer"
                 }
             }
         },
-        "controllers.KillSessionRequest": {
+        "dto.PolicyDryRunResponse": {
             "type": "object",
-            "required": [
-                "session_id"
-            ],
             "properties": {
-                "session_id": {
-                    "type": "string"
+                "added": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DBPolicy"
+                    }
+                },
+                "commands": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.DryRunCommand"
+                    }
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "removed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DBPolicy"
+                    }
                 }
             }
         },
-        "controllers.NotFoundResponse": {
+        "dto.PolicyRevisionChange": {
             "type": "object",
             "properties": {
-                "error": {
-                    "type": "string",
-                    "example": "record not found"
+                "from": {
+                    "$ref": "#/definitions/models.DBPolicy"
+                },
+                "to": {
+                    "$ref": "#/definitions/models.DBPolicy"
                 }
             }
         },
-        "controllers.ObjectCreationErrorResponse": {
+        "dto.PolicyRevisionChanges": {
             "type": "object",
             "properties": {
-                "error": {
-                    "type": "string",
-                    "example": "failed to create object"
+                "added": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DBPolicy"
+                    }
+                },
+                "removed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DBPolicy"
+                    }
                 }
             }
         },
-        "controllers.PDBCreateRequest": {
+        "dto.PolicyRevisionDetailResponse": {
             "type": "object",
             "properties": {
-                "cntmgt": {
-                    "type": "integer",
-                    "example": 1
+                "action": {
+                    "type": "string"
                 },
-                "pdbname": {
-                    "type": "string",
-                    "example": "PDB3"
+                "added_count": {
+                    "type": "integer"
+                },
+                "author": {
+                    "type": "string"
+                },
+                "changes": {
+                    "$ref": "#/definitions/dto.PolicyRevisionChanges"
+                },
+                "cnt_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "job_id": {
+                    "type": "string"
+                },
+                "policies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DBPolicy"
+                    }
+                },
+                "policy_count": {
+                    "type": "integer"
+                },
+                "removed_count": {
+                    "type": "integer"
                 },
-                "sql_param": {
-                    "type": "string",
-                    "example": ""
-                }
-            }
-        },
-        "controllers.PDBCreateResponse": {
-            "type": "object",
-            "properties": {
-                "id": {
-                    "type": "integer",
-                    "example": 123
+                "revision": {
+                    "type": "integer"
                 },
-                "message": {
-                    "type": "string",
-                    "example": "PDB was created successfully"
-                }
-            }
-        },
-        "controllers.PDBDeleteRequest": {
-            "type": "object",
-            "properties": {
-                "sql_param": {
-                    "type": "string",
-                    "example": ""
+                "rolled_back_to": {
+                    "type": "integer"
                 }
             }
         },
-        "controllers.PDBDeleteResponse": {
+        "dto.PolicyRevisionDiffResponse": {
             "type": "object",
             "properties": {
-                "message": {
-                    "type": "string",
-                    "example": "PDB was dropped successfully"
+                "added": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DBPolicy"
+                    }
+                },
+                "changed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PolicyRevisionChange"
+                    }
+                },
+                "cnt_id": {
+                    "type": "integer"
+                },
+                "from_created_at": {
+                    "type": "string"
+                },
+                "from_revision": {
+                    "type": "integer"
+                },
+                "removed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DBPolicy"
+                    }
+                },
+                "to_created_at": {
+                    "type": "string"
+                },
+                "to_revision": {
+                    "type": "integer"
                 }
             }
         },
-        "controllers.PDBGetAllRequest": {
+        "dto.PolicyRevisionListResponse": {
             "type": "object",
             "properties": {
-                "cntmgt": {
-                    "type": "integer",
-                    "example": 1
+                "cnt_id": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "revisions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PolicyRevision"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
                 }
             }
         },
-        "controllers.PDBUpdateRequest": {
+        "dto.PolicyRollbackJob": {
             "type": "object",
             "properties": {
-                "sql_param": {
-                    "type": "string",
-                    "example": "4f50454e"
+                "dbactormgt_id": {
+                    "type": "integer"
+                },
+                "dbmgt_id": {
+                    "type": "integer"
+                },
+                "job_id": {
+                    "type": "string"
+                },
+                "policies_added": {
+                    "type": "integer"
+                },
+                "policies_removed": {
+                    "type": "integer"
                 }
             }
         },
-        "controllers.PDBUpdateResponse": {
+        "dto.PolicyRollbackResponse": {
             "type": "object",
             "properties": {
+                "cnt_id": {
+                    "type": "integer"
+                },
+                "jobs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PolicyRollbackJob"
+                    }
+                },
                 "message": {
-                    "type": "string",
-                    "example": "PDB was altered successfully"
+                    "type": "string"
+                },
+                "skipped": {
+                    "description": "Connection-wide or wildcard-object policies the bulk path cannot apply",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DBPolicy"
+                    }
+                },
+                "target_revision": {
+                    "type": "integer"
                 }
             }
         },
-        "controllers.PolicyAssignmentErrorResponse": {
+        "dto.PrivilegeDriftActor": {
             "type": "object",
             "properties": {
-                "error": {
-                    "type": "string",
-                    "example": "failed to assign policies to group"
+                "actor_id": {
+                    "type": "integer"
+                },
+                "dbuser": {
+                    "type": "string"
+                },
+                "ip_address": {
+                    "type": "string"
                 }
             }
         },
-        "controllers.PolicyAssignmentRequest": {
+        "dto.PrivilegeDriftResponse": {
             "type": "object",
             "properties": {
-                "policy_ids": {
+                "actors_added": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
-                    },
-                    "example": [
-                        1,
-                        2,
-                        3
-                    ]
+                        "$ref": "#/definitions/dto.PrivilegeDriftActor"
+                    }
+                },
+                "actors_removed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PrivilegeDriftActor"
+                    }
+                },
+                "cnt_id": {
+                    "type": "integer"
+                },
+                "from_created_at": {
+                    "type": "string"
+                },
+                "from_version": {
+                    "type": "integer"
+                },
+                "grants_added": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PrivilegeGrantChange"
+                    }
+                },
+                "grants_revoked": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PrivilegeGrantChange"
+                    }
+                },
+                "incomplete_tables": {
+                    "description": "Tables not compared because a query failed in either run",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "policies_allowed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PrivilegePolicyDrift"
+                    }
+                },
+                "policies_revoked": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PrivilegePolicyDrift"
+                    }
+                },
+                "to_created_at": {
+                    "type": "string"
+                },
+                "to_version": {
+                    "type": "integer"
                 }
             }
         },
-        "controllers.PolicyCreationErrorResponse": {
+        "dto.PrivilegeExplainEntry": {
             "type": "object",
             "properties": {
-                "error": {
-                    "type": "string",
-                    "example": "failed to create policy"
+                "action_id": {
+                    "type": "integer"
+                },
+                "dbmgt_id": {
+                    "type": "integer"
+                },
+                "final_sql": {
+                    "type": "string"
+                },
+                "grants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PrivilegeGrantPath"
+                    }
+                },
+                "object_id": {
+                    "type": "integer"
+                },
+                "pass": {
+                    "description": "super, action_wide or object_specific",
+                    "type": "string"
+                },
+                "policy_default_id": {
+                    "type": "integer"
+                },
+                "result_value": {
+                    "type": "string"
                 }
             }
         },
-        "controllers.PolicyNotFoundResponse": {
+        "dto.PrivilegeExplainResponse": {
             "type": "object",
             "properties": {
-                "error": {
-                    "type": "string",
-                    "example": "record not found"
+                "actor_id": {
+                    "type": "integer"
+                },
+                "cnt_id": {
+                    "type": "integer"
+                },
+                "db_type": {
+                    "type": "string"
+                },
+                "dbuser": {
+                    "type": "string"
+                },
+                "policies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PrivilegeExplainEntry"
+                    }
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PrivilegeRolePath"
+                    }
+                },
+                "snapshot_created_at": {
+                    "type": "string"
+                },
+                "snapshot_version": {
+                    "type": "integer"
+                },
+                "super_privilege": {
+                    "description": "Pass 1 matched, so action-wide and object-specific passes were skipped",
+                    "type": "boolean"
                 }
             }
         },
-        "controllers.StandardErrorResponse": {
+        "dto.PrivilegeGrantChange": {
             "type": "object",
             "properties": {
-                "error": {
-                    "type": "string",
-                    "example": "Invalid request body"
+                "row": {
+                    "type": "array",
+                    "items": {}
+                },
+                "table": {
+                    "type": "string"
                 }
             }
         },
-        "controllers.SuccessResponse": {
+        "dto.PrivilegeGrantPath": {
             "type": "object",
             "properties": {
-                "message": {
-                    "type": "string",
-                    "example": "Group deleted successfully"
+                "grantee": {
+                    "type": "string"
+                },
+                "row": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "table": {
+                    "type": "string"
+                },
+                "via": {
+                    "description": "Actor first; more than one element means the grant was inherited through roles",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "controllers.UploadErrorResponse": {
+        "dto.PrivilegePolicyDrift": {
             "type": "object",
             "properties": {
-                "error": {
-                    "type": "string",
-                    "example": "failed to execute upload"
+                "actor_id": {
+                    "type": "integer"
                 },
-                "message": {
-                    "type": "string",
-                    "example": "connection management with id=123 not found"
+                "dbuser": {
+                    "type": "string"
+                },
+                "policy_default_id": {
+                    "type": "integer"
                 }
             }
         },
-        "controllers.UploadJobStartResponse": {
+        "dto.PrivilegeRolePath": {
             "type": "object",
             "properties": {
-                "job_id": {
-                    "type": "string",
-                    "example": "F.C39AKJDLAKEU8VH"
-                },
-                "message": {
-                    "type": "string",
-                    "example": "Upload job started successfully for file: backup_20240101.sql"
+                "path": {
+                    "description": "Actor first, then each role granted on the way to Role",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "status": {
-                    "type": "string",
-                    "example": "success"
+                "role": {
+                    "type": "string"
                 }
             }
         },
-        "controllers.UploadValidationErrorResponse": {
+        "dto.ReadinessResponse": {
             "type": "object",
             "properties": {
-                "error": {
-                    "type": "string",
-                    "example": "validation failed"
+                "failed": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "message": {
-                    "type": "string",
-                    "example": "invalid connection ID: must be greater than 0"
+                "status": {
+                    "type": "string"
                 }
             }
         },
-        "controllers.ValidationErrorResponse": {
+        "job.JobEvent": {
             "type": "object",
             "properties": {
+                "completed": {
+                    "type": "integer"
+                },
                 "error": {
-                    "type": "string",
-                    "example": "Validation failed: dbuser is required"
+                    "type": "string"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "job_id": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "progress": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "total_queries": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
                 }
             }
         },
-        "controllers.VeloExecutionError": {
+        "models.AuditEvent": {
             "type": "object",
             "properties": {
-                "connection_id": {
-                    "type": "integer",
-                    "example": 2
+                "action": {
+                    "type": "string"
+                },
+                "actor": {
+                    "type": "string"
+                },
+                "actor_role": {
+                    "type": "string"
+                },
+                "after": {
+                    "type": "string"
+                },
+                "auth_method": {
+                    "type": "string"
+                },
+                "before": {
+                    "type": "string"
+                },
+                "client_ip": {
+                    "type": "string"
+                },
+                "command": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "entity_id": {
+                    "type": "string"
+                },
+                "entity_type": {
+                    "type": "string"
                 },
                 "error": {
-                    "type": "string",
-                    "example": "connection timeout: failed to connect to client"
+                    "type": "string"
                 },
-                "operation": {
-                    "type": "string",
-                    "example": "allow"
+                "hash": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "outcome": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "prev_hash": {
+                    "type": "string"
+                },
+                "route": {
+                    "type": "string"
                 }
             }
         },
@@ -4048,6 +6552,108 @@ const docTemplate = `{
                 }
             }
         },
+        "models.ChangeRequest": {
+            "type": "object",
+            "properties": {
+                "applied_at": {
+                    "type": "string"
+                },
+                "approval_count": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "decided_at": {
+                    "type": "string"
+                },
+                "decided_by": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "operation": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "requested_by": {
+                    "type": "string"
+                },
+                "required_approvals": {
+                    "type": "integer"
+                },
+                "risk_level": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ChangeRequestApproval": {
+            "type": "object",
+            "properties": {
+                "approver": {
+                    "type": "string"
+                },
+                "change_request_id": {
+                    "type": "integer"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "decision": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DBPolicy": {
+            "type": "object",
+            "properties": {
+                "cntmgt": {
+                    "type": "integer"
+                },
+                "dbactormgt": {
+                    "type": "integer"
+                },
+                "dbmgt": {
+                    "type": "integer"
+                },
+                "dbobjectmgt": {
+                    "type": "integer"
+                },
+                "dbpolicydefault": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
         "models.DownloadRequest": {
             "type": "object",
             "required": [
@@ -4070,6 +6676,100 @@ const docTemplate = `{
                 }
             }
         },
+        "models.Endpoint": {
+            "type": "object",
+            "properties": {
+                "client_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "os_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MembershipTransition": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "executions": {
+                    "type": "integer"
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "membership_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subject_id": {
+                    "description": "Actor ID or dbgroup_listpolicies ID",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PolicyRevision": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "added_count": {
+                    "type": "integer"
+                },
+                "author": {
+                    "type": "string"
+                },
+                "cnt_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "job_id": {
+                    "type": "string"
+                },
+                "policy_count": {
+                    "type": "integer"
+                },
+                "removed_count": {
+                    "type": "integer"
+                },
+                "revision": {
+                    "type": "integer"
+                },
+                "rolled_back_to": {
+                    "type": "integer"
+                }
+            }
+        },
         "models.UploadRequest": {
             "type": "object",
             "required": [
@@ -4097,6 +6797,23 @@ const docTemplate = `{
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "AgentSecret": {
+            "type": "apiKey",
+            "name": "X-Agent-Secret",
+            "in": "header"
+        },
+        "ApiKeyAuth": {
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        },
+        "BearerAuth": {
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
     }
 }`
 
@@ -1,24 +1,48 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
+	"time"
 
 	"dbfartifactapi/bootstrap"
 	"dbfartifactapi/config"
 	"dbfartifactapi/controllers"
 	_ "dbfartifactapi/docs"
+	"dbfartifactapi/pkg/auth"
 	"dbfartifactapi/pkg/logger"
+	"dbfartifactapi/pkg/metrics"
+	"dbfartifactapi/pkg/ratelimit"
+	"dbfartifactapi/pkg/secrets"
+	"dbfartifactapi/pkg/tracing"
+	"dbfartifactapi/repository"
+	"dbfartifactapi/services/admin"
+	"dbfartifactapi/services/agent"
+	"dbfartifactapi/services/approval"
+	"dbfartifactapi/services/audit"
 	"dbfartifactapi/services/compliance"
+	"dbfartifactapi/services/credential"
 	"dbfartifactapi/services/entity"
 	"dbfartifactapi/services/fileops"
 	"dbfartifactapi/services/group"
+	"dbfartifactapi/services/health"
+	"dbfartifactapi/services/idempotency"
 	"dbfartifactapi/services/job"
 	"dbfartifactapi/services/pdb"
 	"dbfartifactapi/services/policy"
+	privmssql "dbfartifactapi/services/privilege/mssql"
+	privmysql "dbfartifactapi/services/privilege/mysql"
+	privoracle "dbfartifactapi/services/privilege/oracle"
+	privpostgres "dbfartifactapi/services/privilege/postgres"
 	"dbfartifactapi/services/session"
+	"dbfartifactapi/services/validity"
 	"dbfartifactapi/utils"
 
 	"github.com/gin-gonic/gin"
@@ -32,6 +56,105 @@ import (
 
 // @BasePath  /api
 
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+
+// @securityDefinitions.apikey AgentSecret
+// @in header
+// @name X-Agent-Secret
+
+// newAuthMiddleware builds the authentication middleware from config.
+// With auth enabled at least one credential source (API keys or JWKS) must be configured.
+func newAuthMiddleware() (gin.HandlerFunc, error) {
+	if !config.Cfg.AuthEnabled {
+		logger.Warnf("API authentication is disabled (AUTH_ENABLED=false), every caller is treated as super-admin")
+		return auth.Anonymous(), nil
+	}
+
+	var authenticators []auth.Authenticator
+	if len(config.Cfg.AuthAPIKeys) > 0 {
+		keys, err := auth.ParseAPIKeys(config.Cfg.AuthAPIKeys)
+		if err != nil {
+			return nil, fmt.Errorf("AUTH_API_KEYS: %w", err)
+		}
+		authenticators = append(authenticators, auth.NewAPIKeyAuthenticator(keys))
+	}
+	if config.Cfg.AuthJWKSFile != "" {
+		keys, err := auth.LoadJWKSFile(config.Cfg.AuthJWKSFile)
+		if err != nil {
+			return nil, fmt.Errorf("AUTH_JWKS_FILE: %w", err)
+		}
+		authenticators = append(authenticators, auth.NewJWTAuthenticator(keys, auth.JWTOptions{
+			Issuer:    config.Cfg.AuthJWTIssuer,
+			Audience:  config.Cfg.AuthJWTAudience,
+			RoleClaim: config.Cfg.AuthJWTRoleClaim,
+		}))
+	}
+	if len(authenticators) == 0 {
+		return nil, fmt.Errorf("authentication is enabled but neither AUTH_API_KEYS nor AUTH_JWKS_FILE is set (set AUTH_ENABLED=false to run without authentication)")
+	}
+	return auth.Middleware(authenticators...), nil
+}
+
+// newAgentAuthMiddleware builds the authentication middleware for agent callbacks from config.
+// A callback must carry the secret of the agent running the job named in its path.
+func newAgentAuthMiddleware() (gin.HandlerFunc, error) {
+	if !config.Cfg.AuthEnabled {
+		return auth.Anonymous(), nil
+	}
+	secrets, err := auth.ParseAgentSecrets(config.Cfg.AgentCallbackSecrets)
+	if err != nil {
+		return nil, fmt.Errorf("AGENT_CALLBACK_SECRETS: %w", err)
+	}
+	if len(config.Cfg.AgentCallbackSecrets) == 0 {
+		logger.Warnf("AGENT_CALLBACK_SECRETS is empty, agent job notifications are rejected and jobs complete by polling only")
+	}
+	jobMonitor := job.GetJobMonitorService()
+	return auth.AgentMiddleware(secrets, func(c *gin.Context) (string, bool) {
+		jobInfo, exists := jobMonitor.GetJob(c.Param("job_id"))
+		if !exists {
+			return "", false
+		}
+		return jobInfo.ClientID, true
+	}), nil
+}
+
+// newRateLimitMiddleware builds the per-client and per-route rate limit middleware from config.
+// Returns a pass-through handler when RATE_LIMIT_ENABLED is false.
+func newRateLimitMiddleware() (gin.HandlerFunc, error) {
+	if !config.Cfg.RateLimitEnabled {
+		return func(c *gin.Context) { c.Next() }, nil
+	}
+	routes, err := ratelimit.ParseRules(config.Cfg.RateLimitRoutes)
+	if err != nil {
+		return nil, fmt.Errorf("RATE_LIMIT_ROUTES: %w", err)
+	}
+	return ratelimit.Middleware(ratelimit.Options{
+		ClientRate:  config.Cfg.RateLimitClientRPS,
+		ClientBurst: config.Cfg.RateLimitClientBurst,
+		Routes:      routes,
+	}), nil
+}
+
+// setupTracing installs the OTLP trace exporter when TRACING_ENABLED is set.
+// Returns a shutdown function flushing pending spans; a no-op when tracing is disabled.
+func setupTracing() (func(context.Context) error, error) {
+	if !config.Cfg.TracingEnabled {
+		return func(context.Context) error { return nil }, nil
+	}
+	return tracing.Setup(context.Background(), tracing.Options{
+		ServiceName: config.Cfg.TracingServiceName,
+		Endpoint:    config.Cfg.TracingOTLPEndpoint,
+		Insecure:    config.Cfg.TracingOTLPInsecure,
+		SampleRatio: config.Cfg.TracingSampleRatio,
+	})
+}
+
 func main() {
 	// logger.Init("/var/log/dbf/dbfartifactapi.log")
 	// 1) Load config
@@ -39,6 +162,11 @@ func main() {
 		log.Fatalf("LoadConfig error: %v", err)
 	}
 
+	shutdownTracing, err := setupTracing()
+	if err != nil {
+		log.Fatalf("Tracing config error: %v", err)
+	}
+
 	// 2) Connect DB (GORM)
 	if err := config.ConnectDB(); err != nil {
 		log.Fatalf("ConnectDB error: %v", err)
@@ -51,17 +179,40 @@ func main() {
 		log.Fatalf("Load data error: %v", err)
 	}
 
+	// Agent transport must be selected before services are constructed with it
+	agentExecutor, err := agent.NewAgentExecutorFromConfig()
+	if err != nil {
+		log.Fatalf("Agent executor error: %v", err)
+	}
+	agent.SetDefaultExecutor(agentExecutor)
+	job.GetJobMonitorService().SetAgentExecutor(agentExecutor)
+
+	// Completion callbacks fetch job results with the same executor; registered before persisted jobs are restored
+	policy.RegisterJobKinds(agentExecutor)
+	entity.RegisterJobKinds(agentExecutor)
+	compliance.RegisterJobKinds(agentExecutor)
+	privmysql.RegisterJobKinds(agentExecutor)
+	privpostgres.RegisterJobKinds(agentExecutor)
+	privmssql.RegisterJobKinds(agentExecutor)
+	privoracle.RegisterJobKinds(agentExecutor)
+
 	controllers.SetDBActorMgtService(entity.NewDBActorMgtService())
 	controllers.SetDBMgtService(entity.NewDBMgtService())
 	controllers.SetDBObjectMgtService(entity.NewDBObjectMgtService())
 	controllers.SetDBPolicyService(policy.NewDBPolicyService())
 	controllers.SetSessionService(session.NewSessionService())
 	controllers.SetPolicyComplianceService(compliance.NewPolicyComplianceService())
-	controllers.SetGroupManagementService(group.NewGroupManagementService())
+	groupMgtSrv := group.NewGroupManagementService()
+	controllers.SetGroupManagementService(groupMgtSrv)
+	controllers.SetChangeRequestService(approval.NewChangeRequestService(group.ChangeRequestExecutor(groupMgtSrv)))
 	controllers.SetBackupService(fileops.NewBackupService())
 	controllers.SetUploadService(fileops.NewUploadService())
 	controllers.SetDownloadService(fileops.NewDownloadService())
 	controllers.SetPDBService(pdb.NewPDBService())
+	controllers.SetAuditService(audit.NewAuditService())
+	controllers.SetHealthService(health.NewHealthService())
+	controllers.SetAdminService(admin.NewAdminService())
+	controllers.SetJobEventsOrigins(config.Cfg.JobEventsAllowedOrigins, config.Cfg.JobEventsAllowNoOrigin)
 
 	// 3) Init structured logger with config
 	logLevel := logger.ParseLogLevel(config.Cfg.LogLevel)
@@ -74,12 +225,112 @@ func main() {
 		config.Cfg.LogCompress,
 	)
 	logger.Infof("Starting DBF Artifact API with log level: %s", config.Cfg.LogLevel)
+	config.OnReload(func(prev, next config.AppConfig) {
+		if prev.LogLevel != next.LogLevel {
+			logger.SetLevel(logger.ParseLogLevel(next.LogLevel))
+			logger.Warnf("Log level changed from %s to %s by config reload", prev.LogLevel, next.LogLevel)
+		}
+	})
+
+	// Restore in-flight jobs so polling and agent notifications resume after restart
+	if config.Cfg.JobPersistenceEnabled {
+		if err := job.GetJobMonitorService().EnablePersistence(repository.NewJobRepository()); err != nil {
+			logger.Errorf("Job persistence unavailable, jobs will be tracked in memory only: %v", err)
+		}
+	}
+
+	// Privilege snapshots back the drift endpoint; discovery still works if the table is unavailable
+	if config.Cfg.PrivilegeSnapshotEnabled {
+		if err := repository.NewPrivilegeSnapshotRepository().Migrate(); err != nil {
+			logger.Errorf("Privilege snapshot table unavailable, drift reports will be empty: %v", err)
+		}
+	}
+
+	// Policy revisions are written in the transaction of each policy change, so the table must exist before serving
+	if config.Cfg.PolicyRevisionsEnabled {
+		if err := repository.NewPolicyRevisionRepository().Migrate(); err != nil {
+			log.Fatalf("Policy revision migration error: %v", err)
+		}
+	}
+
+	// Pending change requests are stored before a gated group change returns, so the tables must exist before serving
+	if config.Cfg.ApprovalEnabled {
+		if err := repository.NewChangeRequestRepository().Migrate(); err != nil {
+			log.Fatalf("Change request migration error: %v", err)
+		}
+		if !config.Cfg.AuthEnabled {
+			logger.Warnf("Change approvals are enabled without authentication, every caller is the same user and no change request can be approved")
+		}
+	}
+
+	// Membership transitions are recorded by every validity enforcement pass, so the table must exist before the first one
+	var validityScheduler *validity.Scheduler
+	if config.Cfg.ValidityEnforcementEnabled {
+		if err := repository.NewMembershipTransitionRepository().Migrate(); err != nil {
+			log.Fatalf("Membership transition migration error: %v", err)
+		}
+		validityScheduler = validity.NewScheduler(groupMgtSrv, repository.NewAdvisoryLockRepository(), config.Cfg.ValidityEnforcementInterval)
+	}
+
+	// Passwords are only decrypted or resolved from their secret reference when an agent command is built
+	credentialStore, err := credential.NewStoreFromConfig()
+	if err != nil {
+		log.Fatalf("Credential config error: %v", err)
+	}
+	secrets.SetDefault(credentialStore)
+	// cntmgt and dbactormgt belong to DBF Web, so their columns are only checked here, never altered
+	if err := repository.NewCredentialRepository().CheckSchema(credentialStore.EncryptionEnabled()); err != nil {
+		log.Fatalf("Credential column schema error: %v", err)
+	}
+	if !credentialStore.EncryptionEnabled() {
+		logger.Warnf("No credential key configured (CREDENTIAL_KEYS or CREDENTIAL_KEY_FILE), passwords are stored in plain text")
+	} else if config.Cfg.CredentialRewrapOnStartup {
+		// Rewriting stored passwords breaks every other reader of these columns that cannot decrypt them
+		count, err := credential.RewrapStored(repository.NewCredentialRepository(), credentialStore)
+		if err != nil {
+			logger.Errorf("Failed to re-encrypt stored passwords after %d rows: %v", count, err)
+		} else {
+			logger.Infof("Re-encrypted %d stored passwords under the active credential key", count)
+		}
+	}
+
+	// Audit events are written in the transaction of each change, so the table must exist before serving
+	if config.Cfg.AuditEnabled {
+		if err := repository.NewAuditEventRepository().Migrate(); err != nil {
+			log.Fatalf("Audit trail migration error: %v", err)
+		}
+	}
+
+	// Idempotency keys are claimed in this table, so duplicate retries are caught across instances
+	if config.Cfg.IdempotencyEnabled {
+		if err := repository.NewIdempotencyKeyRepository().Migrate(); err != nil {
+			log.Fatalf("Idempotency key migration error: %v", err)
+		}
+	}
+
+	authMiddleware, err := newAuthMiddleware()
+	if err != nil {
+		log.Fatalf("Auth config error: %v", err)
+	}
+	rateLimitMiddleware, err := newRateLimitMiddleware()
+	if err != nil {
+		log.Fatalf("Rate limit config error: %v", err)
+	}
+	agentAuthMiddleware, err := newAgentAuthMiddleware()
+	if err != nil {
+		log.Fatalf("Agent auth config error: %v", err)
+	}
 
 	// 4) Setup Gin
 	router := gin.Default()
 	router.Use(utils.LoggerMiddleware())
+	router.Use(tracing.Middleware())
+	if config.Cfg.MetricsEnabled {
+		router.Use(metrics.Middleware())
+	}
 
-	v1 := router.Group("/api")
+	// Rate limiting runs before audit so rejected requests don't flood the audit trail
+	v1 := router.Group("/api", authMiddleware, rateLimitMiddleware, audit.Middleware(), idempotency.Middleware())
 	{
 		queries := v1.Group("/queries")
 		{
@@ -91,6 +342,7 @@ func main() {
 			controllers.RegisterPolicyComplianceRoutes(queries)
 			controllers.RegisterConnectionTestRoutes(queries)
 			controllers.RegisterGroupManagementRoutes(queries)
+			controllers.RegisterChangeRequestRoutes(queries)
 			controllers.RegisterBackupRoutes(queries)
 			controllers.RegisterUploadRoutes(queries)
 			controllers.RegisterDownloadRoutes(queries)
@@ -99,33 +351,109 @@ func main() {
 
 		// Job status monitoring routes
 		controllers.RegisterJobStatusRoutes(v1)
+
+		controllers.RegisterAuditRoutes(v1)
+
+		controllers.RegisterDiagnosticsRoutes(v1)
+
+		controllers.RegisterAdminRoutes(v1)
 	}
 
+	// Agent callbacks authenticate with a per-agent secret and bypass user rate limits and idempotency keys
+	agentAPI := router.Group("/api", agentAuthMiddleware, audit.Middleware())
+	controllers.RegisterAgentCallbackRoutes(agentAPI)
+
 	// 5) Swagger route
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// Metrics are scraped without API credentials, like the Swagger UI
+	if config.Cfg.MetricsEnabled {
+		router.GET(config.Cfg.MetricsPath, gin.WrapH(metrics.Handler()))
+	}
+
+	// Liveness and readiness probes are called by Kubernetes and load balancers without credentials
+	controllers.RegisterHealthRoutes(router)
+
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8081"
+	}
+	srv := &http.Server{
+		Addr:    "0.0.0.0:" + port,
+		Handler: router,
+	}
+
 	// 6) Setup graceful shutdown
 	// Setup signal handling for graceful shutdown
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
+	// SIGHUP reloads the config, like POST /api/admin/config/reload
+	reloadChan := make(chan os.Signal, 1)
+	signal.Notify(reloadChan, syscall.SIGHUP)
 	go func() {
-		<-sigChan
-		logger.Infof("Received shutdown signal, stopping job monitor service...")
-
-		// Stop job monitor service
-		jobMonitor := job.GetJobMonitorService()
-		jobMonitor.Stop()
+		for range reloadChan {
+			logger.Infof("Received SIGHUP, reloading config")
+			if _, err := config.Reload(); err != nil {
+				logger.Errorf("Config reload failed, keeping current config: %v", err)
+			}
+		}
+	}()
 
-		logger.Infof("Application shutdown complete")
-		os.Exit(0)
+	shutdownDone := make(chan struct{})
+	go func() {
+		<-sigChan
+		logger.Infof("Received shutdown signal, draining requests and jobs for up to %v", config.Cfg.ShutdownTimeout)
+		gracefulShutdown(srv, job.GetJobMonitorService(), validityScheduler, shutdownTracing)
+		close(shutdownDone)
 	}()
 
 	// 7) Run
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8081"
+	if validityScheduler != nil {
+		validityScheduler.Start()
 	}
 	logger.Infof("Starting server at port %s", port)
-	router.Run("0.0.0.0:" + port)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalf("Server error: %v", err)
+	}
+	<-shutdownDone
+	logger.Infof("Application shutdown complete")
+}
+
+// gracefulShutdown stops accepting connections, then waits up to ShutdownTimeout for in-flight HTTP requests,
+// job completion callbacks and the running validity enforcement pass. They drain in parallel: open job event
+// streams only end once the job monitor closes them. Jobs unfinished at the deadline are flushed to the job
+// store or marked interrupted.
+func gracefulShutdown(srv *http.Server, jobMonitor *job.JobMonitorService, validityScheduler *validity.Scheduler, shutdownTracing func(context.Context) error) {
+	ctx, cancel := context.WithTimeout(context.Background(), config.Cfg.ShutdownTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		if err := srv.Shutdown(ctx); err != nil {
+			logger.Warnf("HTTP requests still in progress at shutdown deadline: %v", err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		if err := jobMonitor.Shutdown(ctx); err != nil {
+			logger.Warnf("Job monitor shutdown incomplete: %v", err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		if err := validityScheduler.Shutdown(ctx); err != nil {
+			logger.Warnf("Validity enforcement shutdown incomplete: %v", err)
+		}
+	}()
+	wg.Wait()
+
+	// Traces get their own short deadline so spans of the drained requests are exported even after a timeout
+	traceCtx, traceCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer traceCancel()
+	if err := shutdownTracing(traceCtx); err != nil {
+		logger.Warnf("Failed to flush traces: %v", err)
+	}
 }
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// AuditEvent is one append-only record of a mutating API call or agent command.
+// Hash covers every other column plus PrevHash, chaining each event to the one before it
+// so edited or deleted rows break verification.
+type AuditEvent struct {
+	ID         uint      `gorm:"primaryKey;column:id" json:"id"`
+	CreatedAt  time.Time `gorm:"column:created_at;index" json:"created_at"`
+	Actor      string    `gorm:"column:actor;size:191;index" json:"actor"`
+	ActorRole  string    `gorm:"column:actor_role;size:32" json:"actor_role"`
+	AuthMethod string    `gorm:"column:auth_method;size:16" json:"auth_method"`
+	ClientIP   string    `gorm:"column:client_ip;size:64" json:"client_ip"`
+	Method     string    `gorm:"column:method;size:16" json:"method"`
+	Route      string    `gorm:"column:route;size:255" json:"route"`
+	Path       string    `gorm:"column:path;size:1024" json:"path"`
+	EntityType string    `gorm:"column:entity_type;size:64;index:idx_audit_events_entity" json:"entity_type"`
+	EntityID   string    `gorm:"column:entity_id;size:191;index:idx_audit_events_entity" json:"entity_id"`
+	Action     string    `gorm:"column:action;size:64" json:"action"`
+	Before     string    `gorm:"column:before_state;type:longtext" json:"before,omitempty"`
+	After      string    `gorm:"column:after_state;type:longtext" json:"after,omitempty"`
+	Command    string    `gorm:"column:command;type:longtext" json:"command,omitempty"`
+	Outcome    string    `gorm:"column:outcome;size:16;index" json:"outcome"`
+	Error      string    `gorm:"column:error;type:text" json:"error,omitempty"`
+	PrevHash   string    `gorm:"column:prev_hash;size:64" json:"prev_hash"`
+	Hash       string    `gorm:"column:hash;size:64;uniqueIndex" json:"hash"`
+}
+
+// TableName returns the database table name for AuditEvent model.
+func (AuditEvent) TableName() string {
+	return "audit_events"
+}
+
+// AuditChainHead holds the hash of the newest audit event in its single row.
+// Appends lock the row, so concurrent transactions extend the chain one at a time.
+type AuditChainHead struct {
+	ID          uint   `gorm:"primaryKey;column:id" json:"id"`
+	LastEventID uint   `gorm:"column:last_event_id" json:"last_event_id"`
+	LastHash    string `gorm:"column:last_hash;size:64" json:"last_hash"`
+}
+
+// TableName returns the database table name for AuditChainHead model.
+func (AuditChainHead) TableName() string {
+	return "audit_chain_head"
+}
@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// Change request statuses.
+const (
+	ChangeRequestPending  = "pending"
+	ChangeRequestApproved = "approved"
+	ChangeRequestRejected = "rejected"
+	ChangeRequestApplied  = "applied"
+	ChangeRequestFailed   = "failed"
+)
+
+// ChangeRequest is a group change held back until other users approve it.
+// Payload holds the original request and Preview its dry-run result with the rendered SQL,
+// both JSON-encoded; Result holds what the change returned once applied.
+type ChangeRequest struct {
+	ID                uint       `gorm:"primaryKey;column:id" json:"id"`
+	Operation         string     `gorm:"column:operation;size:64" json:"operation"`
+	GroupID           uint       `gorm:"column:group_id;index" json:"group_id"`
+	Status            string     `gorm:"column:status;size:32;index" json:"status"`
+	RiskLevel         string     `gorm:"column:risk_level;size:16" json:"risk_level,omitempty"`
+	Reason            string     `gorm:"column:reason;size:512" json:"reason"`
+	RequiredApprovals int        `gorm:"column:required_approvals" json:"required_approvals"`
+	ApprovalCount     int        `gorm:"column:approval_count" json:"approval_count"`
+	RequestedBy       string     `gorm:"column:requested_by;size:191" json:"requested_by"`
+	DecidedBy         string     `gorm:"column:decided_by;size:191" json:"decided_by,omitempty"`
+	Payload           string     `gorm:"column:payload;type:longtext" json:"-"`
+	Preview           string     `gorm:"column:preview;type:longtext" json:"-"`
+	Result            string     `gorm:"column:result;type:longtext" json:"-"`
+	Error             string     `gorm:"column:error;type:text" json:"error,omitempty"`
+	CreatedAt         time.Time  `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt         time.Time  `gorm:"column:updated_at" json:"updated_at"`
+	DecidedAt         *time.Time `gorm:"column:decided_at" json:"decided_at,omitempty"`
+	AppliedAt         *time.Time `gorm:"column:applied_at" json:"applied_at,omitempty"`
+}
+
+// TableName returns the database table name for ChangeRequest model.
+func (ChangeRequest) TableName() string {
+	return "change_requests"
+}
+
+// Change request decisions.
+const (
+	DecisionApprove = "approve"
+	DecisionReject  = "reject"
+)
+
+// ChangeRequestApproval is one user's decision on a change request.
+type ChangeRequestApproval struct {
+	ID              uint      `gorm:"primaryKey;column:id" json:"id"`
+	ChangeRequestID uint      `gorm:"column:change_request_id;uniqueIndex:idx_change_request_approvals_approver" json:"change_request_id"`
+	Approver        string    `gorm:"column:approver;size:191;uniqueIndex:idx_change_request_approvals_approver" json:"approver"`
+	Decision        string    `gorm:"column:decision;size:16" json:"decision"`
+	Comment         string    `gorm:"column:comment;size:1024" json:"comment,omitempty"`
+	CreatedAt       time.Time `gorm:"column:created_at" json:"created_at"`
+}
+
+// TableName returns the database table name for ChangeRequestApproval model.
+func (ChangeRequestApproval) TableName() string {
+	return "change_request_approvals"
+}
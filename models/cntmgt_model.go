@@ -2,6 +2,7 @@ package models
 
 // CntMgt represents a database server connection configuration.
 // Stores credentials and connection details for remote database servers.
+// Password is never serialized to JSON; it is decrypted or resolved from SecretRef only for agent calls.
 // Agent field references Endpoint ID for VeloArtifact command execution.
 // ParentConnectionID links Oracle PDB connections to their parent CDB container.
 type CntMgt struct {
@@ -12,7 +13,8 @@ type CntMgt struct {
 	Port               int    `gorm:"column:port" json:"port"`                                 // Database server port
 	ConfigFilePath     string `gorm:"column:config_file_path" json:"config_file_path"`         // Path to database config file
 	Username           string `gorm:"column:username" json:"username"`                         // Database authentication username
-	Password           string `gorm:"column:password" json:"password"`                         // Database authentication password (consider encryption)
+	Password           string `gorm:"column:password;size:1024" json:"-"`                      // Database authentication password, envelope-encrypted when a credential key is configured
+	SecretRef          string `gorm:"column:secret_ref;size:512" json:"secret_ref"`            // Secret provider reference (file:, env:, vault:) used instead of Password when set
 	UserIP             string `gorm:"column:user_ip" json:"user_ip"`                           // Allowed user IP for connections
 	Agent              int    `gorm:"column:agent" json:"agent"`                               // Foreign key to Endpoint for VeloArtifact execution
 	Status             string `gorm:"column:status;default:disabled" json:"status"`            // enabled/disabled for policy operations
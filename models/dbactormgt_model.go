@@ -9,7 +9,7 @@ type DBActorMgt struct {
 	IPAddress   string `gorm:"column:ip_address" json:"ip_address" validate:"required"`
 	DBClient    string `gorm:"column:db_client" json:"db_client"`
 	OSUser      string `gorm:"column:osuser" json:"osuser"`
-	Password    string `gorm:"column:password" json:"dbuserpaswd"`
+	Password    string `gorm:"column:password;size:1024" json:"-"` // Envelope-encrypted when a credential key is configured, never serialized
 	Description string `gorm:"column:description" json:"description"`
 	Status      string `gorm:"column:status" json:"status"`
 }
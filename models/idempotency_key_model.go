@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Idempotency key states
+const (
+	IdempotencyStatusInProgress = "in_progress"
+	IdempotencyStatusCompleted  = "completed"
+)
+
+// IdempotencyKey records a POST request sent with an Idempotency-Key header and, once it succeeded,
+// the response replayed to retries of the same request.
+type IdempotencyKey struct {
+	KeyHash        string    `gorm:"primaryKey;column:key_hash;size:64" json:"key_hash"` // SHA-256 of caller and key
+	Actor          string    `gorm:"column:actor;size:191" json:"actor"`
+	Method         string    `gorm:"column:method;size:16" json:"method"`
+	Path           string    `gorm:"column:path;size:512" json:"path"`
+	Fingerprint    string    `gorm:"column:fingerprint;size:64" json:"fingerprint"` // SHA-256 of method, path, query and body
+	Status         string    `gorm:"column:status;size:16" json:"status"`
+	ResponseStatus int       `gorm:"column:response_status" json:"response_status"`
+	ContentType    string    `gorm:"column:content_type;size:128" json:"content_type"`
+	ResponseBody   string    `gorm:"column:response_body;type:longtext" json:"response_body"`
+	LockedUntil    time.Time `gorm:"column:locked_until" json:"locked_until"` // In-progress records are abandoned after this
+	ExpiresAt      time.Time `gorm:"column:expires_at;index" json:"expires_at"`
+	CreatedAt      time.Time `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt      time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+// TableName returns the database table name for IdempotencyKey model.
+func (IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// Job represents the persisted state of a background job tracked by the job monitor.
+// ContextData holds JSON-encoded context payloads keyed by the same keys used in
+// JobInfo.ContextData so completion handlers can be resumed after a restart.
+// CallbackCommitted is only set by the completion callback's own transaction, never by state saves.
+type Job struct {
+	JobID                    string     `gorm:"primaryKey;column:job_id;size:191" json:"job_id"`
+	Kind                     string     `gorm:"column:kind;size:64;index" json:"kind"`
+	DBMgtID                  uint       `gorm:"column:dbmgt_id;index" json:"dbmgt_id"`
+	ClientID                 string     `gorm:"column:client_id;size:191" json:"client_id"`
+	OsType                   string     `gorm:"column:os_type;size:32" json:"os_type"`
+	Status                   string     `gorm:"column:status;size:32;index" json:"status"`
+	Progress                 int        `gorm:"column:progress" json:"progress"`
+	Message                  string     `gorm:"column:message;type:text" json:"message"`
+	Completed                int        `gorm:"column:completed" json:"completed"`
+	Failed                   int        `gorm:"column:failed" json:"failed"`
+	TotalQueries             int        `gorm:"column:total_queries" json:"total_queries"`
+	Error                    string     `gorm:"column:error;type:text" json:"error"`
+	Results                  string     `gorm:"column:results;type:longtext" json:"results"`
+	ContextData              string     `gorm:"column:context_data;type:longtext" json:"context_data"`
+	ProcessedViaNotification bool       `gorm:"column:processed_via_notification" json:"processed_via_notification"`
+	TraceParent              string     `gorm:"column:trace_parent;size:64" json:"trace_parent,omitempty"`
+	CallbackCommitted        bool       `gorm:"column:callback_committed" json:"callback_committed"`
+	StartTime                time.Time  `gorm:"column:start_time" json:"start_time"`
+	EndTime                  *time.Time `gorm:"column:end_time" json:"end_time,omitempty"`
+	CreatedAt                time.Time  `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt                time.Time  `gorm:"column:updated_at" json:"updated_at"`
+}
+
+// TableName returns the database table name for Job model.
+func (Job) TableName() string {
+	return "jobs"
+}
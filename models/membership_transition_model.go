@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Membership kinds a transition applies to.
+const (
+	MembershipKindActor  = "actor"  // dbactor_groups row
+	MembershipKindPolicy = "policy" // dbpolicy_groups row
+)
+
+// Membership transitions and their outcomes.
+const (
+	TransitionActivate = "activate"
+	TransitionExpire   = "expire"
+
+	TransitionApplied = "applied"
+	TransitionPartial = "partial"
+	TransitionFailed  = "failed"
+)
+
+// MembershipTransition is the outcome of granting or revoking a group membership when its
+// valid_from or valid_until passes. Failed transitions are retried on the next pass and update
+// the same row, so Attempts counts the passes it took.
+type MembershipTransition struct {
+	ID           uint      `gorm:"primaryKey;column:id" json:"id"`
+	Kind         string    `gorm:"column:kind;size:16;uniqueIndex:idx_membership_transitions_membership" json:"kind"`
+	MembershipID uint      `gorm:"column:membership_id;uniqueIndex:idx_membership_transitions_membership" json:"membership_id"`
+	Action       string    `gorm:"column:action;size:16;uniqueIndex:idx_membership_transitions_membership" json:"action"`
+	GroupID      uint      `gorm:"column:group_id;index" json:"group_id"`
+	SubjectID    uint      `gorm:"column:subject_id" json:"subject_id"` // Actor ID or dbgroup_listpolicies ID
+	Status       string    `gorm:"column:status;size:16;index" json:"status"`
+	Attempts     int       `gorm:"column:attempts" json:"attempts"`
+	Executions   int       `gorm:"column:executions" json:"executions"`
+	Error        string    `gorm:"column:error;type:text" json:"error,omitempty"`
+	CreatedAt    time.Time `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+// TableName returns the database table name for MembershipTransition model.
+func (MembershipTransition) TableName() string {
+	return "membership_transitions"
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// PolicyRevision records one change to the policy set of a connection.
+// Changes holds the policies the change added and removed and Policies the whole
+// policy set of the connection afterwards, both JSON-encoded, so any two revisions
+// can be diffed and the connection rolled back to an earlier one.
+type PolicyRevision struct {
+	ID           uint      `gorm:"primaryKey;column:id" json:"id"`
+	CntMgtID     uint      `gorm:"column:cnt_id;uniqueIndex:idx_policy_revisions_cnt_revision" json:"cnt_id"`
+	Revision     int       `gorm:"column:revision;uniqueIndex:idx_policy_revisions_cnt_revision" json:"revision"`
+	Action       string    `gorm:"column:action;size:64" json:"action"`
+	Author       string    `gorm:"column:author;size:191" json:"author"`
+	JobID        string    `gorm:"column:job_id;size:191" json:"job_id,omitempty"`
+	RolledBackTo int       `gorm:"column:rolled_back_to" json:"rolled_back_to,omitempty"`
+	AddedCount   int       `gorm:"column:added_count" json:"added_count"`
+	RemovedCount int       `gorm:"column:removed_count" json:"removed_count"`
+	PolicyCount  int       `gorm:"column:policy_count" json:"policy_count"`
+	Changes      string    `gorm:"column:changes;type:longtext" json:"-"`
+	Policies     string    `gorm:"column:policies;type:longtext" json:"-"`
+	CreatedAt    time.Time `gorm:"column:created_at" json:"created_at"`
+}
+
+// TableName returns the database table name for PolicyRevision model.
+func (PolicyRevision) TableName() string {
+	return "policy_revisions"
+}
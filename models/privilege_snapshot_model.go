@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// PrivilegeSnapshot stores the outcome of one privilege discovery run for a connection.
+// GrantRows holds the raw dbfAgentAPI privilege rows and ActorPolicies the derived
+// actor -> allowed policy default set, both JSON-encoded, so consecutive runs can be diffed.
+type PrivilegeSnapshot struct {
+	ID            uint      `gorm:"primaryKey;column:id" json:"id"`
+	CntMgtID      uint      `gorm:"column:cnt_id;uniqueIndex:idx_privilege_snapshots_cnt_version" json:"cnt_id"`
+	Version       int       `gorm:"column:version;uniqueIndex:idx_privilege_snapshots_cnt_version" json:"version"`
+	JobID         string    `gorm:"column:job_id;size:191" json:"job_id"`
+	DBType        string    `gorm:"column:db_type;size:32" json:"db_type"`
+	GrantRows     string    `gorm:"column:grant_rows;type:longtext" json:"-"`
+	Actors        string    `gorm:"column:actors;type:longtext" json:"-"`
+	ActorPolicies string    `gorm:"column:actor_policies;type:longtext" json:"-"`
+	CreatedAt     time.Time `gorm:"column:created_at" json:"created_at"`
+}
+
+// TableName returns the database table name for PrivilegeSnapshot model.
+func (PrivilegeSnapshot) TableName() string {
+	return "privilege_snapshots"
+}
@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"dbfartifactapi/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AgentSecretHeader carries the shared secret an agent presents on its callbacks.
+const AgentSecretHeader = "X-Agent-Secret"
+
+// MethodAgentSecret is recorded on principals authenticated by an agent shared secret.
+const MethodAgentSecret = "agent_secret"
+
+// AgentSecrets holds one shared secret per agent, keyed by Endpoint.ClientID.
+// Secrets are kept as SHA-256 digests and compared in constant time.
+type AgentSecrets struct {
+	digests map[string][sha256.Size]byte
+}
+
+// ParseAgentSecrets parses "client_id:secret" entries. The secret may itself contain colons.
+func ParseAgentSecrets(entries []string) (*AgentSecrets, error) {
+	digests := make(map[string][sha256.Size]byte, len(entries))
+	for _, entry := range entries {
+		clientID, secret, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok || clientID == "" || secret == "" {
+			return nil, fmt.Errorf("invalid agent secret entry, expected client_id:secret")
+		}
+		if _, dup := digests[clientID]; dup {
+			return nil, fmt.Errorf("duplicate agent secret for client %s", clientID)
+		}
+		digests[clientID] = sha256.Sum256([]byte(secret))
+	}
+	return &AgentSecrets{digests: digests}, nil
+}
+
+// verify reports whether secret is the one configured for clientID.
+func (s *AgentSecrets) verify(clientID, secret string) bool {
+	want, ok := s.digests[clientID]
+	if !ok || secret == "" {
+		return false
+	}
+	got := sha256.Sum256([]byte(secret))
+	return subtle.ConstantTimeCompare(got[:], want[:]) == 1
+}
+
+// AgentMiddleware authenticates agent callbacks. clientIDOf resolves the agent the request is about
+// (e.g. the agent running the job in the path); the request must carry that agent's secret, so one
+// agent cannot report on another agent's jobs. Unknown targets are rejected like bad secrets so the
+// response does not reveal which jobs exist.
+func AgentMiddleware(secrets *AgentSecrets, clientIDOf func(c *gin.Context) (string, bool)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clientID, ok := clientIDOf(c)
+		if !ok || !secrets.verify(clientID, c.GetHeader(AgentSecretHeader)) {
+			logger.Warnf("Agent authentication failed for %s %s from %s", c.Request.Method, c.Request.URL.Path, c.ClientIP())
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid agent credentials"})
+			return
+		}
+		c.Set(principalKey, &Principal{Subject: "agent:" + clientID, Role: RoleOperator, Method: MethodAgentSecret})
+		c.Next()
+	}
+}
@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIKeyHeader is the header carrying a static API key. "Authorization: ApiKey <key>" is accepted as well.
+const APIKeyHeader = "X-API-Key"
+
+// APIKey is a static key bound to a caller name and role.
+type APIKey struct {
+	Name string
+	Role Role
+	Key  string
+}
+
+// ParseAPIKeys parses "name:role:key" entries. The key may itself contain colons.
+func ParseAPIKeys(entries []string) ([]APIKey, error) {
+	keys := make([]APIKey, 0, len(entries))
+	for _, entry := range entries {
+		parts := strings.SplitN(strings.TrimSpace(entry), ":", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+			return nil, fmt.Errorf("invalid API key entry, expected name:role:key")
+		}
+		role, err := ParseRole(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("API key %s: %w", parts[0], err)
+		}
+		keys = append(keys, APIKey{Name: parts[0], Role: role, Key: parts[2]})
+	}
+	return keys, nil
+}
+
+// apiKeyAuthenticator checks requests against a fixed set of API keys.
+// Keys are kept as SHA-256 digests and compared in constant time.
+type apiKeyAuthenticator struct {
+	keys []hashedAPIKey
+}
+
+type hashedAPIKey struct {
+	name   string
+	role   Role
+	digest [sha256.Size]byte
+}
+
+// NewAPIKeyAuthenticator creates an Authenticator for static API keys.
+func NewAPIKeyAuthenticator(keys []APIKey) Authenticator {
+	hashed := make([]hashedAPIKey, 0, len(keys))
+	for _, k := range keys {
+		hashed = append(hashed, hashedAPIKey{name: k.Name, role: k.Role, digest: sha256.Sum256([]byte(k.Key))})
+	}
+	return &apiKeyAuthenticator{keys: hashed}
+}
+
+// Authenticate implements Authenticator.
+func (a *apiKeyAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	key := r.Header.Get(APIKeyHeader)
+	if key == "" {
+		if scheme, value, ok := strings.Cut(r.Header.Get("Authorization"), " "); ok && strings.EqualFold(scheme, "ApiKey") {
+			key = strings.TrimSpace(value)
+		}
+	}
+	if key == "" {
+		return nil, ErrNoCredentials
+	}
+
+	digest := sha256.Sum256([]byte(key))
+	for _, k := range a.keys {
+		if subtle.ConstantTimeCompare(digest[:], k.digest[:]) == 1 {
+			return &Principal{Subject: k.name, Role: k.role, Method: MethodAPIKey}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: unknown API key", ErrInvalidCredentials)
+}
@@ -0,0 +1,188 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": kid})
+	payload, _ := json.Marshal(claims)
+	input := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(input))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func newTestRouter(authenticators ...Authenticator) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	api := router.Group("/api", Middleware(authenticators...))
+	api.GET("/items", func(c *gin.Context) { c.JSON(http.StatusOK, PrincipalFrom(c)) })
+	api.POST("/items", func(c *gin.Context) { c.Status(http.StatusOK) })
+	api.POST("/kill", RequireRole(RoleSuperAdmin), func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func serve(router *gin.Engine, method, path string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// TestMiddleware_APIKeyRoles tests that API keys authenticate and roles gate methods and elevated routes
+func TestMiddleware_APIKeyRoles(t *testing.T) {
+	keys, err := ParseAPIKeys([]string{"dashboard:viewer:view-key", "ops:operator:op:key", "root:super_admin:root-key"})
+	if err != nil {
+		t.Fatalf("ParseAPIKeys: %v", err)
+	}
+	router := newTestRouter(NewAPIKeyAuthenticator(keys))
+
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		header http.Header
+		want   int
+	}{
+		{"no credentials", http.MethodGet, "/api/items", nil, http.StatusUnauthorized},
+		{"unknown key", http.MethodGet, "/api/items", http.Header{"X-Api-Key": {"nope"}}, http.StatusUnauthorized},
+		{"viewer reads", http.MethodGet, "/api/items", http.Header{"X-Api-Key": {"view-key"}}, http.StatusOK},
+		{"viewer cannot mutate", http.MethodPost, "/api/items", http.Header{"X-Api-Key": {"view-key"}}, http.StatusForbidden},
+		{"operator mutates", http.MethodPost, "/api/items", http.Header{"Authorization": {"ApiKey op:key"}}, http.StatusOK},
+		{"operator cannot kill", http.MethodPost, "/api/kill", http.Header{"X-Api-Key": {"op:key"}}, http.StatusForbidden},
+		{"super-admin kills", http.MethodPost, "/api/kill", http.Header{"X-Api-Key": {"root-key"}}, http.StatusOK},
+	}
+	for _, tc := range cases {
+		if w := serve(router, tc.method, tc.path, tc.header); w.Code != tc.want {
+			t.Errorf("%s: status = %d, want %d", tc.name, w.Code, tc.want)
+		}
+	}
+
+	if _, err := ParseAPIKeys([]string{"bad:admin:key"}); err == nil {
+		t.Error("ParseAPIKeys accepted an unknown role")
+	}
+}
+
+// TestMiddleware_JWT tests bearer tokens validated against a local JWKS
+func TestMiddleware_JWT(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	jwks, _ := json.Marshal(map[string]interface{}{"keys": []map[string]string{{
+		"kty": "RSA", "kid": "k1", "use": "sig", "alg": "RS256",
+		"n": base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}})
+	keys, err := ParseJWKS(jwks)
+	if err != nil {
+		t.Fatalf("ParseJWKS: %v", err)
+	}
+	router := newTestRouter(NewJWTAuthenticator(keys, JWTOptions{
+		Issuer: "https://idp.example", Audience: "dbfartifactapi", RoleClaim: "realm_access.roles",
+	}))
+
+	valid := map[string]interface{}{
+		"iss": "https://idp.example", "aud": []string{"dbfartifactapi"}, "sub": "alice",
+		"exp": time.Now().Add(time.Hour).Unix(), "realm_access": map[string]interface{}{"roles": []string{"offline", "operator"}},
+	}
+	bearer := func(claims map[string]interface{}, kid string) http.Header {
+		return http.Header{"Authorization": {"Bearer " + signRS256(t, key, kid, claims)}}
+	}
+	with := func(k string, v interface{}) map[string]interface{} {
+		claims := make(map[string]interface{}, len(valid))
+		for ck, cv := range valid {
+			claims[ck] = cv
+		}
+		claims[k] = v
+		return claims
+	}
+
+	w := serve(router, http.MethodPost, "/api/items", bearer(valid, "k1"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("valid token: status = %d, want 200 (%s)", w.Code, w.Body.String())
+	}
+	w = serve(router, http.MethodGet, "/api/items", bearer(valid, "k1"))
+	var principal Principal
+	if err := json.Unmarshal(w.Body.Bytes(), &principal); err != nil || principal.Subject != "alice" || principal.Role != RoleOperator {
+		t.Errorf("principal = %+v, want alice as operator", principal)
+	}
+
+	rejected := map[string]http.Header{
+		"expired":        bearer(with("exp", time.Now().Add(-time.Hour).Unix()), "k1"),
+		"wrong issuer":   bearer(with("iss", "https://other.example"), "k1"),
+		"wrong audience": bearer(with("aud", "other"), "k1"),
+		"unknown kid":    bearer(valid, "k2"),
+		"no role":        bearer(with("realm_access", map[string]interface{}{"roles": []string{"offline"}}), "k1"),
+		"tampered":       {"Authorization": {"Bearer " + signRS256(t, key, "k1", valid) + "x"}},
+	}
+	for name, header := range rejected {
+		if w := serve(router, http.MethodGet, "/api/items", header); w.Code != http.StatusUnauthorized {
+			t.Errorf("%s: status = %d, want 401", name, w.Code)
+		}
+	}
+}
+
+// TestAgentMiddleware tests that agent callbacks need the secret of the agent running the target job
+func TestAgentMiddleware(t *testing.T) {
+	secrets, err := ParseAgentSecrets([]string{"agent-a:secret:a", "agent-b:secret-b"})
+	if err != nil {
+		t.Fatalf("ParseAgentSecrets: %v", err)
+	}
+	jobAgents := map[string]string{"job-a": "agent-a", "job-b": "agent-b"}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/jobs/:job_id/notify", AgentMiddleware(secrets, func(c *gin.Context) (string, bool) {
+		clientID, ok := jobAgents[c.Param("job_id")]
+		return clientID, ok
+	}), func(c *gin.Context) { c.JSON(http.StatusOK, PrincipalFrom(c)) })
+
+	cases := []struct {
+		name   string
+		path   string
+		header http.Header
+		want   int
+	}{
+		{"no secret", "/api/jobs/job-a/notify", nil, http.StatusUnauthorized},
+		{"operator API key", "/api/jobs/job-a/notify", http.Header{"X-Api-Key": {"op-key"}}, http.StatusUnauthorized},
+		{"other agent's secret", "/api/jobs/job-a/notify", http.Header{"X-Agent-Secret": {"secret-b"}}, http.StatusUnauthorized},
+		{"unknown job", "/api/jobs/job-x/notify", http.Header{"X-Agent-Secret": {"secret:a"}}, http.StatusUnauthorized},
+		{"own secret", "/api/jobs/job-a/notify", http.Header{"X-Agent-Secret": {"secret:a"}}, http.StatusOK},
+	}
+	for _, tc := range cases {
+		if w := serve(router, http.MethodPost, tc.path, tc.header); w.Code != tc.want {
+			t.Errorf("%s: status = %d, want %d", tc.name, w.Code, tc.want)
+		}
+	}
+
+	w := serve(router, http.MethodPost, "/api/jobs/job-b/notify", http.Header{"X-Agent-Secret": {"secret-b"}})
+	var principal Principal
+	if err := json.Unmarshal(w.Body.Bytes(), &principal); err != nil || principal.Subject != "agent:agent-b" || principal.Method != MethodAgentSecret {
+		t.Errorf("principal = %+v, want agent:agent-b via agent secret", principal)
+	}
+
+	if _, err := ParseAgentSecrets([]string{"agent-a:"}); err == nil {
+		t.Error("ParseAgentSecrets accepted an empty secret")
+	}
+}
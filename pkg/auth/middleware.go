@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+
+	"dbfartifactapi/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Authentication methods recorded on a Principal.
+const (
+	MethodAPIKey = "api_key"
+	MethodJWT    = "jwt"
+	MethodNone   = "none"
+)
+
+// principalKey is the gin context key holding the authenticated *Principal.
+const principalKey = "auth.principal"
+
+var (
+	// ErrNoCredentials means the request carries no credentials for this authenticator.
+	ErrNoCredentials = errors.New("no credentials")
+	// ErrInvalidCredentials means credentials were presented but rejected.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	Subject string `json:"subject"`
+	Role    Role   `json:"role"`
+	Method  string `json:"method"`
+}
+
+// Authenticator resolves the caller of a request.
+// Returns ErrNoCredentials when the request carries no credentials it understands,
+// so the next authenticator can be tried.
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+// Middleware authenticates every request with the first authenticator that recognises its credentials.
+// Safe methods need at least RoleViewer and mutations RoleOperator; routes needing more use RequireRole.
+func Middleware(authenticators ...Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var principal *Principal
+		for _, a := range authenticators {
+			p, err := a.Authenticate(c.Request)
+			if errors.Is(err, ErrNoCredentials) {
+				continue
+			}
+			if err != nil {
+				logger.Warnf("Authentication failed for %s %s from %s: %v", c.Request.Method, c.Request.URL.Path, c.ClientIP(), err)
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
+				return
+			}
+			principal = p
+			break
+		}
+		if principal == nil {
+			c.Header("WWW-Authenticate", `Bearer realm="dbfartifactapi"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+			return
+		}
+
+		c.Set(principalKey, principal)
+		if !principal.Role.Allows(defaultRole(c.Request.Method)) {
+			forbid(c, principal, defaultRole(c.Request.Method))
+			return
+		}
+		c.Next()
+	}
+}
+
+// Anonymous marks every request as made by an unauthenticated super-admin.
+// Used when authentication is disabled so RequireRole checks keep passing.
+func Anonymous() gin.HandlerFunc {
+	principal := &Principal{Subject: "anonymous", Role: RoleSuperAdmin, Method: MethodNone}
+	return func(c *gin.Context) {
+		c.Set(principalKey, principal)
+		c.Next()
+	}
+}
+
+// RequireRole rejects requests whose principal does not hold at least role.
+func RequireRole(role Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal := PrincipalFrom(c)
+		if principal == nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+			return
+		}
+		if !principal.Role.Allows(role) {
+			forbid(c, principal, role)
+			return
+		}
+		c.Next()
+	}
+}
+
+// PrincipalFrom returns the authenticated caller of the request, or nil when none was set.
+func PrincipalFrom(c *gin.Context) *Principal {
+	if v, ok := c.Get(principalKey); ok {
+		if p, ok := v.(*Principal); ok {
+			return p
+		}
+	}
+	return nil
+}
+
+// defaultRole is the role every route needs for method before any route-specific requirement.
+func defaultRole(method string) Role {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return RoleViewer
+	default:
+		return RoleOperator
+	}
+}
+
+func forbid(c *gin.Context, principal *Principal, required Role) {
+	logger.Warnf("Access denied for %s (role %s) on %s %s: requires %s",
+		principal.Subject, principal.Role, c.Request.Method, c.Request.URL.Path, required)
+	c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "insufficient role: requires " + string(required)})
+}
@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Role is an API caller role. Roles are ordered: each role includes every permission of the roles below it.
+type Role string
+
+const (
+	RoleViewer      Role = "viewer"       // Read-only access to every GET endpoint
+	RoleOperator    Role = "operator"     // Sync, discovery and job control
+	RolePolicyAdmin Role = "policy-admin" // Policy and group assignment changes, bulk policy delete
+	RoleSuperAdmin  Role = "super-admin"  // Session kill, agent file operations, PDB drop
+)
+
+// roleRank orders roles from least to most privileged.
+var roleRank = map[Role]int{
+	RoleViewer:      1,
+	RoleOperator:    2,
+	RolePolicyAdmin: 3,
+	RoleSuperAdmin:  4,
+}
+
+// ParseRole converts a role name to a Role, accepting underscores and any letter case.
+func ParseRole(name string) (Role, error) {
+	role := Role(strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), "_", "-"))
+	if _, ok := roleRank[role]; !ok {
+		return "", fmt.Errorf("unknown role: %q", name)
+	}
+	return role, nil
+}
+
+// Allows reports whether r grants at least the permissions of required.
+func (r Role) Allows(required Role) bool {
+	rank, ok := roleRank[r]
+	return ok && rank >= roleRank[required]
+}
+
+// highestRole returns the most privileged known role among names, ignoring unknown names.
+func highestRole(names []string) (Role, bool) {
+	var best Role
+	for _, name := range names {
+		role, err := ParseRole(name)
+		if err != nil {
+			continue
+		}
+		if best == "" || roleRank[role] > roleRank[best] {
+			best = role
+		}
+	}
+	return best, best != ""
+}
@@ -0,0 +1,337 @@
+package fakeagent
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"dbfartifactapi/services/dto"
+	"dbfartifactapi/services/privilege/mysql"
+	"dbfartifactapi/utils"
+)
+
+// executeResponse is the stdout of a foreground execute action
+type executeResponse struct {
+	Message  string          `json:"message"`
+	Results  [][]interface{} `json:"results"`
+	RowCount int             `json:"row_count"`
+	Success  bool            `json:"success"`
+}
+
+// jobStartResponse is the stdout of actions started with --background
+type jobStartResponse struct {
+	JobID          string `json:"job_id"`
+	FileName       string `json:"filename,omitempty"`
+	Message        string `json:"message"`
+	MonitorCommand string `json:"monitor_command"`
+	PID            int    `json:"pid"`
+	ResultsCommand string `json:"results_command,omitempty"`
+	Success        bool   `json:"success"`
+	DatabaseName   string `json:"database_name,omitempty"`
+	DBType         string `json:"db_type,omitempty"`
+}
+
+// queryResult is one entry of a download job results file
+type queryResult struct {
+	QueryKey    string          `json:"query_key"`
+	Query       string          `json:"query"`
+	Status      string          `json:"status"`
+	Result      [][]interface{} `json:"result"`
+	ExecuteTime string          `json:"execute_time"`
+	DurationMs  int             `json:"duration_ms"`
+}
+
+// complianceResult is one entry of a policycompliance job results file
+type complianceResult struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Value       string `json:"value"`
+	Message     string `json:"message"`
+	TimeCheck   string `json:"time_check"`
+}
+
+// decodeQueryParam decodes the hex-encoded DBQueryParam JSON payload
+func decodeQueryParam(hexJSON string) (*dto.DBQueryParam, error) {
+	raw, err := hex.DecodeString(hexJSON)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex payload: %w", err)
+	}
+
+	var param dto.DBQueryParam
+	if err := json.Unmarshal(raw, &param); err != nil {
+		return nil, fmt.Errorf("invalid payload JSON: %w", err)
+	}
+
+	if param.DBType != "" && !strings.EqualFold(param.DBType, "mysql") {
+		return nil, fmt.Errorf("fake agent only supports mysql, got db_type=%s", param.DBType)
+	}
+	return &param, nil
+}
+
+// query runs SQL against the embedded database and converts values to their JSON representation
+func (a *Agent) query(database, query string) ([][]interface{}, error) {
+	if database == "" {
+		database = "mysql"
+	}
+
+	a.dbMu.Lock()
+	_, rows, err := a.session.QueryInDatabase(mysql.RewriteQueryForPrivilegeSession(query), database)
+	a.dbMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		for i, val := range row {
+			row[i] = jsonValue(val)
+		}
+	}
+	return rows, nil
+}
+
+// jsonValue renders engine values the way dbfsqlexecute prints them: strings for everything except NULL
+func jsonValue(val interface{}) interface{} {
+	switch v := val.(type) {
+	case nil:
+		return nil
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.Format("2006-01-02 15:04:05")
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func (a *Agent) execute(hexJSON string) (string, error) {
+	param, err := decodeQueryParam(hexJSON)
+	if err != nil {
+		return "", err
+	}
+
+	query, ok := param.Query.(string)
+	if !ok || strings.TrimSpace(query) == "" {
+		return "", fmt.Errorf("execute requires a non-empty query string")
+	}
+
+	rows, err := a.query(param.Database, query)
+	if err != nil {
+		output, _ := marshalOutput(executeResponse{
+			Message: fmt.Sprintf("Query failed: %v", err),
+			Results: [][]interface{}{},
+		})
+		return output, fmt.Errorf("query failed: %w", err)
+	}
+
+	return marshalOutput(executeResponse{
+		Message:  fmt.Sprintf("Query executed successfully, %d rows returned", len(rows)),
+		Results:  rows,
+		RowCount: len(rows),
+		Success:  true,
+	})
+}
+
+func (a *Agent) startDownload(hexJSON string) (string, error) {
+	param, err := decodeQueryParam(hexJSON)
+	if err != nil {
+		return "", err
+	}
+
+	fileName, ok := param.Query.(string)
+	if !ok || fileName == "" {
+		return "", fmt.Errorf("download requires query file name")
+	}
+	if a.opts.QueryDir == "" {
+		return "", fmt.Errorf("download requires fake agent query dir")
+	}
+
+	data, err := os.ReadFile(filepath.Join(a.opts.QueryDir, filepath.Base(fileName)))
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch query file %s: %w", fileName, err)
+	}
+
+	var listQuery map[string][]string
+	if err := json.Unmarshal(data, &listQuery); err != nil {
+		return "", fmt.Errorf("invalid query file %s: %w", fileName, err)
+	}
+
+	// Sorted keys keep results file order stable between runs
+	keys := make([]string, 0, len(listQuery))
+	total := 0
+	for key, queries := range listQuery {
+		keys = append(keys, key)
+		total += len(queries)
+	}
+	sort.Strings(keys)
+
+	j := a.startJob("download", total, func(j *fakeJob) (interface{}, error) {
+		results := make([]queryResult, 0, total)
+		for _, key := range keys {
+			for _, q := range listQuery[key] {
+				start := time.Now()
+				result := queryResult{
+					QueryKey:    key,
+					Query:       q,
+					Status:      "success",
+					Result:      [][]interface{}{},
+					ExecuteTime: start.Format(time.RFC3339),
+				}
+
+				rows, err := a.query(param.Database, q)
+				if err != nil {
+					result.Status = "error"
+					j.recordQuery(false)
+				} else {
+					result.Result = rows
+					j.recordQuery(true)
+				}
+				result.DurationMs = int(time.Since(start).Milliseconds())
+				results = append(results, result)
+			}
+		}
+		return results, nil
+	})
+
+	return marshalOutput(jobStartResponse{
+		JobID:          j.id,
+		Message:        fmt.Sprintf("Background job started with %d queries", total),
+		MonitorCommand: "dbfsqlexecute checkstatus " + j.id,
+		PID:            j.pid,
+		ResultsCommand: "dbfsqlexecute getresults " + j.id,
+		Success:        true,
+	})
+}
+
+func (a *Agent) startPolicyCompliance(hexJSON string) (string, error) {
+	param, err := decodeQueryParam(hexJSON)
+	if err != nil {
+		return "", err
+	}
+
+	checks := complianceChecks()
+	j := a.startJob("policycompliance", len(checks), func(j *fakeJob) (interface{}, error) {
+		results := make([]complianceResult, 0, len(checks))
+		for _, check := range checks {
+			result := complianceResult{
+				Name:        check.name,
+				Description: check.description,
+				Message:     "OK",
+				TimeCheck:   time.Now().Format("2006-01-02 15:04:05"),
+			}
+
+			rows, err := a.query(param.Database, check.query)
+			if err != nil {
+				result.Message = fmt.Sprintf("check failed: %v", err)
+				j.recordQuery(false)
+			} else {
+				if len(rows) > 0 && len(rows[0]) > 0 && rows[0][0] != nil {
+					result.Value = rows[0][0].(string)
+				}
+				j.recordQuery(true)
+			}
+			results = append(results, result)
+		}
+		return results, nil
+	})
+
+	return marshalOutput(jobStartResponse{
+		JobID:          j.id,
+		Message:        fmt.Sprintf("Policy compliance job started with %d checks", len(checks)),
+		MonitorCommand: "dbfsqlexecute checkstatus " + j.id,
+		PID:            j.pid,
+		ResultsCommand: "dbfsqlexecute getresults " + j.id,
+		Success:        true,
+		DatabaseName:   param.Database,
+		DBType:         "mysql",
+	})
+}
+
+func (a *Agent) startOSExecute(hexJSON, option string) (string, error) {
+	raw, err := hex.DecodeString(hexJSON)
+	if err != nil {
+		return "", fmt.Errorf("invalid hex payload: %w", err)
+	}
+
+	var param dto.DBQueryParam
+	if err := json.Unmarshal(raw, &param); err != nil {
+		return "", fmt.Errorf("invalid payload JSON: %w", err)
+	}
+
+	// commandExec is hex-encoded a second time, see utils.CreateAgentOSExecuteJSON
+	command, err := hex.DecodeString(param.CommandExec)
+	if err != nil || len(command) == 0 {
+		return "", fmt.Errorf("invalid commandExec: must be hex-encoded command")
+	}
+
+	run := func(j *fakeJob) (interface{}, error) {
+		output, err := a.opts.OSCommand(string(command))
+		j.recordQuery(err == nil)
+		result := map[string]interface{}{
+			"command":   string(command),
+			"exit_code": 0,
+			"output":    output,
+		}
+		if err != nil {
+			result["exit_code"] = 1
+			result["error"] = err.Error()
+		}
+		j.setResults([]interface{}{result})
+		return nil, err
+	}
+
+	if !strings.Contains(option, "--background") {
+		j := &fakeJob{}
+		if _, err := run(j); err != nil {
+			return "", err
+		}
+		return marshalOutput(j.results[0])
+	}
+
+	j := a.startJob("os_execute", 1, run)
+	return marshalOutput(jobStartResponse{
+		JobID:          j.id,
+		FileName:       param.FileName,
+		Message:        "OS command started in background",
+		MonitorCommand: "dbfsqlexecute checkstatus " + j.id,
+		PID:            j.pid,
+		Success:        true,
+	})
+}
+
+func (a *Agent) startFileDownload(hexJSON string) (string, error) {
+	raw, err := hex.DecodeString(hexJSON)
+	if err != nil {
+		return "", fmt.Errorf("invalid hex payload: %w", err)
+	}
+
+	var param utils.DownloadParam
+	if err := json.Unmarshal(raw, &param); err != nil {
+		return "", fmt.Errorf("invalid payload JSON: %w", err)
+	}
+	if param.FileName == "" || param.MD5Hash == "" {
+		return "", fmt.Errorf("filedownload requires fileName and md5Hash")
+	}
+
+	// File transfer is simulated: the job only reports where the file would have been saved
+	j := a.startJob("filedownload", 1, func(j *fakeJob) (interface{}, error) {
+		j.recordQuery(true)
+		j.setDoneMessage(fmt.Sprintf("File %s saved to %s (md5 %s)", param.FileName, param.SavePath, param.MD5Hash))
+		return nil, nil
+	})
+
+	return marshalOutput(jobStartResponse{
+		JobID:          j.id,
+		FileName:       param.FileName,
+		Message:        "File download started in background",
+		MonitorCommand: "dbfsqlexecute checkstatus " + j.id,
+		PID:            j.pid,
+		Success:        true,
+	})
+}
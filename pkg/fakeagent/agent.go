@@ -0,0 +1,253 @@
+// Package fakeagent simulates a dbfsqlexecute agent for end-to-end testing without real endpoints.
+//
+// The simulator speaks the agent gateway protocol used by the http agent transport, so the API
+// can be pointed at it with AGENT_TRANSPORT=http and AGENT_GATEWAY_URL=<fake agent address>.
+// SQL runs against an embedded go-mysql-server seeded with mysql.user/db/tables_priv fixtures,
+// and responses use the same JSON shapes the services parse (execute results, background job
+// responses, StatusResponse, getresults, QueryResult files).
+//
+// In-process usage from Go tests:
+//
+//	fa, _ := fakeagent.New(ctx, fakeagent.Options{QueryDir: config.Cfg.DBFWebTempDir})
+//	defer fa.Close()
+//	srv := httptest.NewServer(fa.Handler())
+//	defer srv.Close()
+//	agent.SetDefaultExecutor(agent.NewHTTPAgentExecutor(srv.URL, ""))
+package fakeagent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"dbfartifactapi/pkg/logger"
+	"dbfartifactapi/services/agent"
+	"dbfartifactapi/services/privilege"
+	"dbfartifactapi/services/privilege/mysql"
+)
+
+// Options configures the fake agent
+type Options struct {
+	// QueryDir is where the API writes query files (DBFWEB_TEMP_DIR). Download jobs read <QueryDir>/<query>.
+	QueryDir string
+	// ResultsDir is where job result files are written. Only files under it are served by the files endpoint.
+	ResultsDir string
+	// JobDelay keeps background jobs in running state before they execute, so monitors can observe progress.
+	JobDelay time.Duration
+	// Token is the bearer token required on gateway requests. Empty disables authorization.
+	Token string
+	// Fixtures are extra SQL statements applied after the built-in fixtures.
+	Fixtures []string
+	// OSCommand simulates os_execute commands. Default reports success with empty output; commands are never run.
+	OSCommand func(command string) (string, error)
+}
+
+// Agent is an in-memory dbfsqlexecute simulator
+type Agent struct {
+	opts    Options
+	session *privilege.PrivilegeSession
+
+	// dbMu serializes queries so fixture state changes are deterministic
+	dbMu sync.Mutex
+
+	jobsMu sync.Mutex
+	jobs   map[string]*fakeJob
+	jobSeq int
+
+	closed chan struct{}
+	wg     sync.WaitGroup
+}
+
+// New starts the embedded database, applies fixtures and returns a ready fake agent.
+func New(ctx context.Context, opts Options) (*Agent, error) {
+	if opts.ResultsDir == "" {
+		opts.ResultsDir = filepath.Join(os.TempDir(), "fakeagent")
+	}
+	if opts.OSCommand == nil {
+		opts.OSCommand = func(string) (string, error) { return "", nil }
+	}
+
+	resultsDir, err := filepath.Abs(opts.ResultsDir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid results dir %s: %w", opts.ResultsDir, err)
+	}
+	opts.ResultsDir = resultsDir
+	if err := os.MkdirAll(opts.ResultsDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create results dir %s: %w", opts.ResultsDir, err)
+	}
+
+	session, err := mysql.NewPrivilegeSession(ctx, "fakeagent")
+	if err != nil {
+		return nil, fmt.Errorf("failed to start embedded database: %w", err)
+	}
+
+	a := &Agent{
+		opts:    opts,
+		session: session,
+		jobs:    make(map[string]*fakeJob),
+		closed:  make(chan struct{}),
+	}
+
+	statements := append(append([]string{}, fixtureStatements()...), opts.Fixtures...)
+	for _, stmt := range statements {
+		if _, _, err := session.QueryInDatabase(stmt, "mysql"); err != nil {
+			session.Close()
+			return nil, fmt.Errorf("failed to apply fixture %q: %w", stmt, err)
+		}
+	}
+
+	logger.Infof("Fake agent ready: embedded MySQL on port %d, %d fixture statements, results in %s",
+		session.Port, len(statements), opts.ResultsDir)
+	return a, nil
+}
+
+// Port returns the TCP port of the embedded MySQL server, useful for inspecting fixture state with a MySQL client.
+func (a *Agent) Port() int {
+	return a.session.Port
+}
+
+// Close stops background jobs and the embedded database.
+func (a *Agent) Close() error {
+	close(a.closed)
+	a.wg.Wait()
+	return a.session.Close()
+}
+
+// Handler returns the agent gateway HTTP API:
+//
+//	POST /api/v1/agents/{agent_id}/commands   body {"command": "..."}  -> AgentAPIResponse
+//	GET  /api/v1/agents/{agent_id}/files?path=<remote_path>           -> raw file content
+func (a *Agent) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/agents/{agent_id}/commands", a.handleCommand)
+	mux.HandleFunc("GET /api/v1/agents/{agent_id}/files", a.handleFile)
+	return mux
+}
+
+func (a *Agent) authorized(w http.ResponseWriter, r *http.Request) bool {
+	if a.opts.Token == "" || r.Header.Get("Authorization") == "Bearer "+a.opts.Token {
+		return true
+	}
+	http.Error(w, "invalid or missing bearer token", http.StatusUnauthorized)
+	return false
+}
+
+func (a *Agent) handleCommand(w http.ResponseWriter, r *http.Request) {
+	if !a.authorized(w, r) {
+		return
+	}
+
+	var req agent.GatewayCommandRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Command == "" {
+		http.Error(w, "request body must be {\"command\": \"...\"}", http.StatusBadRequest)
+		return
+	}
+
+	resp := a.Execute(r.PathValue("agent_id"), req.Command)
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (a *Agent) handleFile(w http.ResponseWriter, r *http.Request) {
+	if !a.authorized(w, r) {
+		return
+	}
+
+	remotePath := filepath.Clean(r.URL.Query().Get("path"))
+	if !strings.HasPrefix(remotePath, a.opts.ResultsDir+string(filepath.Separator)) {
+		http.Error(w, fmt.Sprintf("path %s is outside agent results dir", remotePath), http.StatusForbidden)
+		return
+	}
+	if _, err := os.Stat(remotePath); err != nil {
+		http.Error(w, fmt.Sprintf("file not found: %s", remotePath), http.StatusNotFound)
+		return
+	}
+
+	logger.Debugf("Fake agent %s serving file %s", r.PathValue("agent_id"), remotePath)
+	http.ServeFile(w, r, remotePath)
+}
+
+// Execute runs one dbfsqlexecute command line and returns the dbfAgentAPI --json response.
+// Command format: <dbfsqlexecute path> <action> [value] [option]
+func (a *Agent) Execute(agentID, command string) *agent.AgentAPIResponse {
+	resp := &agent.AgentAPIResponse{
+		ClientID:   agentID,
+		Command:    command,
+		ExecutedAt: time.Now().Format(time.RFC3339),
+	}
+
+	output, err := a.dispatch(strings.Fields(command))
+	resp.Output = output
+	if err != nil {
+		logger.Debugf("Fake agent %s command failed: %v", agentID, err)
+		resp.Status = "error"
+		resp.ExitCode = 1
+		resp.Message = err.Error()
+		resp.ErrorOutput = err.Error()
+		return resp
+	}
+
+	resp.Status = "success"
+	return resp
+}
+
+func (a *Agent) dispatch(fields []string) (string, error) {
+	if len(fields) < 2 || !strings.HasSuffix(fields[0], "dbfsqlexecute") {
+		return "", fmt.Errorf("unsupported command: expected dbfsqlexecute <action> [value] [option]")
+	}
+
+	action := fields[1]
+	value := ""
+	if len(fields) > 2 {
+		value = fields[2]
+	}
+	option := strings.Join(fields[3:], " ")
+
+	switch action {
+	case "execute":
+		return a.execute(value)
+	case "download":
+		return a.startDownload(value)
+	case "policycompliance":
+		return a.startPolicyCompliance(value)
+	case "os_execute":
+		return a.startOSExecute(value, option)
+	case "filedownload":
+		return a.startFileDownload(value)
+	case "checkstatus":
+		return a.checkStatus(value)
+	case "getresults":
+		return a.getResults(value)
+	case "cancel":
+		return a.cancel(value)
+	case "listjobs":
+		return a.listJobs()
+	case "cleanup":
+		return a.cleanup(value)
+	case "info":
+		return marshalOutput(map[string]interface{}{
+			"success": true,
+			"message": "dbfsqlexecute fake agent",
+			"version": "fake",
+		})
+	default:
+		return "", fmt.Errorf("invalid action: %s", action)
+	}
+}
+
+// marshalOutput encodes action output without HTML escaping, matching dbfsqlexecute stdout
+func marshalOutput(v interface{}) (string, error) {
+	var sb strings.Builder
+	encoder := json.NewEncoder(&sb)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return "", fmt.Errorf("failed to encode output: %w", err)
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
@@ -0,0 +1,185 @@
+package fakeagent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"dbfartifactapi/config"
+	"dbfartifactapi/services/agent"
+	"dbfartifactapi/services/dto"
+	"dbfartifactapi/services/job"
+	"dbfartifactapi/services/privilege"
+	"dbfartifactapi/utils"
+)
+
+func setupFakeAgent(t *testing.T) (*Agent, agent.AgentExecutor, string) {
+	t.Helper()
+	original := config.Cfg
+	t.Cleanup(func() { config.Cfg = original })
+	config.Cfg.AgentMaxRetries = 1
+	config.Cfg.AgentExecutionTimeout = 5 * time.Second
+	config.Cfg.VeloResultsDir = t.TempDir()
+
+	queryDir := t.TempDir()
+	fa, err := New(context.Background(), Options{QueryDir: queryDir, ResultsDir: t.TempDir(), Token: "secret"})
+	if err != nil {
+		t.Fatalf("Failed to start fake agent: %v", err)
+	}
+	t.Cleanup(func() { fa.Close() })
+
+	server := httptest.NewServer(fa.Handler())
+	t.Cleanup(server.Close)
+
+	return fa, agent.NewHTTPAgentExecutor(server.URL, "secret"), queryDir
+}
+
+// TestFakeAgent_Execute tests foreground SQL execution against fixtures
+func TestFakeAgent_Execute(t *testing.T) {
+	_, executor, _ := setupFakeAgent(t)
+
+	hexJSON, err := utils.CreateAgentCommandJSON(&dto.DBQueryParam{
+		DBType: "mysql",
+		Query:  "SELECT CONCAT(User, '@', Host) FROM mysql.user WHERE Super_priv = 'Y' ORDER BY User",
+	})
+	if err != nil {
+		t.Fatalf("Failed to build payload: %v", err)
+	}
+
+	stdout, err := executor.ExecuteSql("L.0050", "linux", "execute", hexJSON, "", true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var resp struct {
+		Results  [][]string `json:"results"`
+		RowCount int        `json:"row_count"`
+		Success  bool       `json:"success"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &resp); err != nil {
+		t.Fatalf("Failed to parse execute output: %v", err)
+	}
+	if !resp.Success || resp.RowCount != 2 || resp.Results[0][0] != "dba@%" || resp.Results[1][0] != "root@localhost" {
+		t.Errorf("Unexpected execute output: %s", stdout)
+	}
+}
+
+// TestFakeAgent_DownloadJob tests background query file execution through checkstatus, getresults and file download
+func TestFakeAgent_DownloadJob(t *testing.T) {
+	_, executor, queryDir := setupFakeAgent(t)
+
+	queries := map[string][]string{
+		"information_schema.TABLE_PRIVILEGES": {"SELECT GRANTEE, TABLE_NAME FROM information_schema.TABLE_PRIVILEGES"},
+		"broken":                              {"SELECT * FROM missing_table"},
+	}
+	data, _ := json.Marshal(queries)
+	if err := os.WriteFile(filepath.Join(queryDir, "queries.json"), data, 0644); err != nil {
+		t.Fatalf("Failed to write query file: %v", err)
+	}
+
+	hexJSON, _ := utils.CreateAgentCommandJSON(&dto.DBQueryParam{DBType: "mysql", Query: "queries.json"})
+	stdout, err := executor.ExecuteSql("L.0050", "linux", "download", hexJSON, "--background", true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var started struct {
+		JobID   string `json:"job_id"`
+		Success bool   `json:"success"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &started); err != nil || !started.Success {
+		t.Fatalf("Unexpected job start output: %s", stdout)
+	}
+
+	var status job.StatusResponse
+	deadline := time.Now().Add(5 * time.Second)
+	for status.Status != "completed" && time.Now().Before(deadline) {
+		out, err := executor.ExecuteSimpleCommand("L.0050", "linux", "checkstatus", started.JobID, "", true)
+		if err != nil {
+			t.Fatalf("checkstatus failed: %v", err)
+		}
+		_ = json.Unmarshal([]byte(out), &status)
+		time.Sleep(20 * time.Millisecond)
+	}
+	if status.Status != "completed" || status.TotalQueries != 2 || status.Completed != 1 || status.Failed != 1 {
+		t.Fatalf("Unexpected final status: %+v", status)
+	}
+
+	out, err := executor.ExecuteSimpleCommand("L.0050", "linux", "getresults", started.JobID, "", true)
+	if err != nil {
+		t.Fatalf("getresults failed: %v", err)
+	}
+	var results struct {
+		FilePath string `json:"file_path"`
+		Success  bool   `json:"success"`
+	}
+	if err := json.Unmarshal([]byte(out), &results); err != nil || !results.Success {
+		t.Fatalf("Unexpected getresults output: %s", out)
+	}
+
+	download, err := executor.DownloadFile("L.0050", results.FilePath, "linux")
+	if err != nil {
+		t.Fatalf("DownloadFile failed: %v", err)
+	}
+	fileData, _ := os.ReadFile(download.LocalPath)
+
+	var queryResults []privilege.QueryResult
+	if err := json.Unmarshal(fileData, &queryResults); err != nil {
+		t.Fatalf("Failed to parse results file: %v", err)
+	}
+	if len(queryResults) != 2 || queryResults[0].QueryKey != "broken" || queryResults[0].Status != "error" {
+		t.Fatalf("Unexpected results: %+v", queryResults)
+	}
+	if queryResults[1].Status != "success" || len(queryResults[1].Result) != 1 || queryResults[1].Result[0][1] != "orders" {
+		t.Errorf("Unexpected TABLE_PRIVILEGES result: %+v", queryResults[1])
+	}
+}
+
+// TestFakeAgent_OSExecuteJob tests a background os_execute job: the command result is reported by checkstatus
+func TestFakeAgent_OSExecuteJob(t *testing.T) {
+	_, executor, _ := setupFakeAgent(t)
+
+	hexJSON, err := utils.CreateAgentOSExecuteJSON(&dto.DBQueryParam{
+		Action:      "os_execute",
+		CommandExec: "mysqldump appdb > /backup/appdb.sql",
+		FileName:    "appdb.sql",
+	})
+	if err != nil {
+		t.Fatalf("Failed to build payload: %v", err)
+	}
+	stdout, err := executor.ExecuteSql("L.0050", "linux", "os_execute", hexJSON, "--background", true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var started struct {
+		JobID    string `json:"job_id"`
+		FileName string `json:"filename"`
+		Success  bool   `json:"success"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &started); err != nil || !started.Success || started.FileName != "appdb.sql" {
+		t.Fatalf("Unexpected job start output: %s", stdout)
+	}
+
+	var status job.StatusResponse
+	deadline := time.Now().Add(5 * time.Second)
+	for status.Status != "completed" && time.Now().Before(deadline) {
+		out, err := executor.ExecuteSimpleCommand("L.0050", "linux", "checkstatus", started.JobID, "", true)
+		if err != nil {
+			t.Fatalf("checkstatus failed: %v", err)
+		}
+		_ = json.Unmarshal([]byte(out), &status)
+		time.Sleep(20 * time.Millisecond)
+	}
+	if status.Status != "completed" || status.Completed != 1 || len(status.Results) != 1 {
+		t.Fatalf("Unexpected final status: %+v", status)
+	}
+	result, _ := status.Results[0].(map[string]interface{})
+	if result["command"] != "mysqldump appdb > /backup/appdb.sql" || result["exit_code"] != float64(0) {
+		t.Errorf("Unexpected OS result: %v", status.Results[0])
+	}
+}
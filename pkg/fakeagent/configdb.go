@@ -0,0 +1,57 @@
+package fakeagent
+
+import (
+	"context"
+	"fmt"
+
+	"dbfartifactapi/services/privilege"
+	"dbfartifactapi/services/privilege/mysql"
+
+	gormmysql "gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// configDBName is the database holding the API tables inside the embedded server
+const configDBName = "dbf"
+
+// ConfigDB is an embedded MySQL server standing in for the API config database, so flows that load
+// their connection and endpoint records (cntmgt, endpoints, ...) can run against the fake agent.
+// It is a separate server from the agent's, whose databases are what the flows discover.
+type ConfigDB struct {
+	DB      *gorm.DB
+	session *privilege.PrivilegeSession
+}
+
+// NewConfigDB starts an empty embedded database and creates the tables of the given models in it.
+// Services read config.DB when they are constructed, so assign DB to it before creating them.
+func NewConfigDB(ctx context.Context, models ...interface{}) (*ConfigDB, error) {
+	session, err := mysql.NewPrivilegeSession(ctx, "fakeagent-configdb")
+	if err != nil {
+		return nil, fmt.Errorf("failed to start embedded config database: %w", err)
+	}
+	if _, _, err := session.QueryInDatabase("CREATE DATABASE "+configDBName, "mysql"); err != nil {
+		session.Close()
+		return nil, fmt.Errorf("failed to create config database: %w", err)
+	}
+
+	dsn := fmt.Sprintf("root@tcp(localhost:%d)/%s?parseTime=true", session.Port, configDBName)
+	db, err := gorm.Open(gormmysql.Open(dsn), &gorm.Config{Logger: gormlogger.Default.LogMode(gormlogger.Silent)})
+	if err != nil {
+		session.Close()
+		return nil, fmt.Errorf("failed to connect to config database: %w", err)
+	}
+	if err := db.AutoMigrate(models...); err != nil {
+		session.Close()
+		return nil, fmt.Errorf("failed to create config tables: %w", err)
+	}
+	return &ConfigDB{DB: db, session: session}, nil
+}
+
+// Close disconnects and stops the embedded config database.
+func (c *ConfigDB) Close() error {
+	if sqlDB, err := c.DB.DB(); err == nil {
+		sqlDB.Close()
+	}
+	return c.session.Close()
+}
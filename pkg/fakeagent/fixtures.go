@@ -0,0 +1,156 @@
+package fakeagent
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Fixture accounts:
+//
+//	root@localhost         all global privileges
+//	dba@%                  SUPER, PROCESS, RELOAD, SHOW DATABASES + dynamic SYSTEM_VARIABLES_ADMIN
+//	app_user@%             SELECT/INSERT/UPDATE/DELETE on appdb.*
+//	report_user@10.0.0.%   SELECT on appdb.orders, granted app_read role
+//	app_read@%             role account with SELECT on hrdb.*
+//
+// Fixture databases appdb (customers, orders) and hrdb (employees) give object discovery something to find.
+
+// userPrivColumns lists mysql.user privilege columns in table order
+var userPrivColumns = []string{
+	"Select_priv", "Insert_priv", "Update_priv", "Delete_priv", "Create_priv", "Drop_priv",
+	"Reload_priv", "Shutdown_priv", "Process_priv", "File_priv", "Grant_priv", "References_priv",
+	"Index_priv", "Alter_priv", "Show_db_priv", "Super_priv", "Create_tmp_table_priv", "Lock_tables_priv",
+	"Execute_priv", "Repl_slave_priv", "Repl_client_priv", "Create_view_priv", "Show_view_priv",
+	"Create_routine_priv", "Alter_routine_priv", "Create_user_priv", "Event_priv", "Trigger_priv",
+	"Create_tablespace_priv",
+}
+
+// dbPrivColumns lists mysql.db privilege columns in table order
+var dbPrivColumns = []string{
+	"Select_priv", "Insert_priv", "Update_priv", "Delete_priv", "Create_priv", "Drop_priv",
+	"Grant_priv", "References_priv", "Index_priv", "Alter_priv", "Create_tmp_table_priv",
+	"Lock_tables_priv", "Create_view_priv", "Show_view_priv", "Create_routine_priv",
+	"Alter_routine_priv", "Execute_priv", "Event_priv", "Trigger_priv",
+}
+
+// privRow builds an INSERT for a privilege table where listed columns are 'Y' and the rest 'N'.
+// Passing "*" grants every column.
+func privRow(table string, keyColumns, keyValues, privColumns, granted []string) string {
+	grantedSet := make(map[string]bool, len(granted))
+	for _, priv := range granted {
+		grantedSet[priv] = true
+	}
+
+	columns := append([]string{}, keyColumns...)
+	values := make([]string, 0, len(keyValues)+len(privColumns))
+	for _, v := range keyValues {
+		values = append(values, "'"+v+"'")
+	}
+	for _, col := range privColumns {
+		columns = append(columns, col)
+		if grantedSet["*"] || grantedSet[col] {
+			values = append(values, "'Y'")
+		} else {
+			values = append(values, "'N'")
+		}
+	}
+
+	return fmt.Sprintf("INSERT INTO mysql.%s (%s) VALUES (%s)",
+		table, strings.Join(columns, ", "), strings.Join(values, ", "))
+}
+
+func userRow(host, user string, granted ...string) string {
+	return privRow("user", []string{"Host", "User"}, []string{host, user}, userPrivColumns, granted)
+}
+
+func dbRow(host, db, user string, granted ...string) string {
+	return privRow("db", []string{"Host", "Db", "User"}, []string{host, db, user}, dbPrivColumns, granted)
+}
+
+// fixtureStatements returns the built-in seed data for the embedded database
+func fixtureStatements() []string {
+	return []string{
+		// Application databases
+		"CREATE DATABASE appdb",
+		"CREATE TABLE appdb.customers (id INT PRIMARY KEY, name VARCHAR(100), email VARCHAR(255))",
+		"CREATE TABLE appdb.orders (id INT PRIMARY KEY, customer_id INT, total DECIMAL(10,2), created_at DATETIME)",
+		"INSERT INTO appdb.customers VALUES (1, 'Alice', 'alice@example.com'), (2, 'Bob', 'bob@example.com')",
+		"INSERT INTO appdb.orders VALUES (1, 1, 120.50, '2024-01-15 10:00:00'), (2, 2, 75.00, '2024-01-16 11:30:00')",
+		"CREATE DATABASE hrdb",
+		"CREATE TABLE hrdb.employees (id INT PRIMARY KEY, name VARCHAR(100), salary DECIMAL(10,2))",
+		"INSERT INTO hrdb.employees VALUES (1, 'Carol', 5000.00)",
+
+		// mysql.user
+		userRow("localhost", "root", "*"),
+		userRow("%", "dba", "Super_priv", "Process_priv", "Reload_priv", "Show_db_priv"),
+		userRow("%", "app_user"),
+		userRow("10.0.0.%", "report_user"),
+		userRow("%", "app_read"),
+
+		// mysql.db
+		dbRow("%", "appdb", "app_user", "Select_priv", "Insert_priv", "Update_priv", "Delete_priv"),
+		dbRow("%", "hrdb", "app_read", "Select_priv"),
+
+		// mysql.tables_priv
+		"INSERT INTO mysql.tables_priv (Host, Db, User, Table_name, Grantor, Timestamp, Table_priv, Column_priv) " +
+			"VALUES ('10.0.0.%', 'appdb', 'report_user', 'orders', 'root@localhost', '2024-01-01 00:00:00', 'Select', '')",
+
+		// mysql.role_edges, mysql.global_grants
+		"INSERT INTO mysql.role_edges (FROM_HOST, FROM_USER, TO_HOST, TO_USER, WITH_ADMIN_OPTION) " +
+			"VALUES ('%', 'app_read', '10.0.0.%', 'report_user', 'N')",
+		"INSERT INTO mysql.global_grants (USER, HOST, PRIV, WITH_GRANT_OPTION) " +
+			"VALUES ('dba', '%', 'SYSTEM_VARIABLES_ADMIN', 'N')",
+
+		// information_schema privilege views (stored as mysql.infoschema_* in the embedded server)
+		"INSERT INTO mysql.infoschema_user_privileges (GRANTEE, TABLE_CATALOG, PRIVILEGE_TYPE, IS_GRANTABLE) VALUES " +
+			"(\"'root'@'localhost'\", 'def', 'SUPER', 'YES'), " +
+			"(\"'dba'@'%'\", 'def', 'SUPER', 'NO'), " +
+			"(\"'dba'@'%'\", 'def', 'PROCESS', 'NO'), " +
+			"(\"'dba'@'%'\", 'def', 'RELOAD', 'NO'), " +
+			"(\"'dba'@'%'\", 'def', 'SHOW DATABASES', 'NO'), " +
+			"(\"'app_user'@'%'\", 'def', 'USAGE', 'NO'), " +
+			"(\"'report_user'@'10.0.0.%'\", 'def', 'USAGE', 'NO'), " +
+			"(\"'app_read'@'%'\", 'def', 'USAGE', 'NO')",
+		"INSERT INTO mysql.infoschema_schema_privileges (GRANTEE, TABLE_CATALOG, TABLE_SCHEMA, PRIVILEGE_TYPE, IS_GRANTABLE) VALUES " +
+			"(\"'app_user'@'%'\", 'def', 'appdb', 'SELECT', 'NO'), " +
+			"(\"'app_user'@'%'\", 'def', 'appdb', 'INSERT', 'NO'), " +
+			"(\"'app_user'@'%'\", 'def', 'appdb', 'UPDATE', 'NO'), " +
+			"(\"'app_user'@'%'\", 'def', 'appdb', 'DELETE', 'NO'), " +
+			"(\"'app_read'@'%'\", 'def', 'hrdb', 'SELECT', 'NO')",
+		"INSERT INTO mysql.infoschema_table_privileges (GRANTEE, TABLE_CATALOG, TABLE_SCHEMA, TABLE_NAME, PRIVILEGE_TYPE, IS_GRANTABLE) VALUES " +
+			"(\"'report_user'@'10.0.0.%'\", 'def', 'appdb', 'orders', 'SELECT', 'NO')",
+	}
+}
+
+// complianceCheck is a policycompliance check evaluated against the embedded database
+type complianceCheck struct {
+	name        string
+	description string
+	query       string
+}
+
+// complianceChecks returns the checks reported by policycompliance jobs
+func complianceChecks() []complianceCheck {
+	return []complianceCheck{
+		{
+			name:        "version",
+			description: "Database server version",
+			query:       "SELECT VERSION()",
+		},
+		{
+			name:        "anonymous_accounts",
+			description: "Number of accounts with empty user name",
+			query:       "SELECT COUNT(*) FROM mysql.user WHERE User = ''",
+		},
+		{
+			name:        "super_privilege_accounts",
+			description: "Number of accounts with SUPER privilege",
+			query:       "SELECT COUNT(*) FROM mysql.user WHERE Super_priv = 'Y'",
+		},
+		{
+			name:        "wildcard_host_accounts",
+			description: "Number of accounts allowed to connect from any host",
+			query:       "SELECT COUNT(*) FROM mysql.user WHERE Host = '%'",
+		},
+	}
+}
@@ -0,0 +1,326 @@
+package fakeagent
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"dbfartifactapi/pkg/logger"
+	"dbfartifactapi/services/job"
+)
+
+// fakeJob is a background job started with --background
+type fakeJob struct {
+	mu sync.Mutex
+
+	id   string
+	kind string
+	pid  int
+
+	status    string // running, completed, failed, cancelled
+	message   string
+	doneMsg   string // overrides default completion message
+	errMsg    string
+	total     int
+	completed int
+	failed    int
+	filePath  string
+	results   []interface{}
+	createdAt time.Time
+	endTime   time.Time
+	updatedAt time.Time
+}
+
+// jobRunner does the job work. Returned content (if not nil) is written as the job results file.
+type jobRunner func(j *fakeJob) (interface{}, error)
+
+// startJob registers a running job and executes it after JobDelay
+func (a *Agent) startJob(kind string, total int, run jobRunner) *fakeJob {
+	now := time.Now()
+
+	a.jobsMu.Lock()
+	a.jobSeq++
+	j := &fakeJob{
+		id:        fmt.Sprintf("job_%s_%04d", now.Format("20060102_150405"), a.jobSeq),
+		kind:      kind,
+		pid:       10000 + a.jobSeq,
+		status:    "running",
+		message:   fmt.Sprintf("%s job running", kind),
+		total:     total,
+		createdAt: now,
+		updatedAt: now,
+	}
+	a.jobs[j.id] = j
+	a.jobsMu.Unlock()
+
+	logger.Infof("Fake agent started %s job %s with %d queries", kind, j.id, total)
+
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+
+		select {
+		case <-a.closed:
+			j.finish("", fmt.Errorf("agent stopped"))
+			return
+		case <-time.After(a.opts.JobDelay):
+		}
+
+		if j.isCancelled() {
+			return
+		}
+
+		content, err := run(j)
+		filePath := ""
+		if err == nil && content != nil {
+			filePath, err = a.writeResultsFile(j.id, content)
+		}
+		j.finish(filePath, err)
+	}()
+
+	return j
+}
+
+// writeResultsFile stores job results where getresults points and the files endpoint serves them
+func (a *Agent) writeResultsFile(jobID string, content interface{}) (string, error) {
+	data, err := json.MarshalIndent(content, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode results: %w", err)
+	}
+
+	filePath := filepath.Join(a.opts.ResultsDir, jobID+"_results.json")
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write results file: %w", err)
+	}
+	return filePath, nil
+}
+
+func (a *Agent) getJob(jobID string) (*fakeJob, error) {
+	a.jobsMu.Lock()
+	defer a.jobsMu.Unlock()
+
+	j, exists := a.jobs[jobID]
+	if !exists {
+		return nil, fmt.Errorf("job not found: %s", jobID)
+	}
+	return j, nil
+}
+
+func (j *fakeJob) recordQuery(success bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if success {
+		j.completed++
+	} else {
+		j.failed++
+	}
+	j.updatedAt = time.Now()
+}
+
+func (j *fakeJob) setResults(results []interface{}) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.results = results
+}
+
+// setDoneMessage sets the message reported once the job completes
+func (j *fakeJob) setDoneMessage(message string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.doneMsg = message
+}
+
+func (j *fakeJob) isCancelled() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.status == "cancelled"
+}
+
+// finish moves job to its final state. Cancelled jobs keep their status.
+func (j *fakeJob) finish(filePath string, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.status == "cancelled" {
+		return
+	}
+
+	now := time.Now()
+	j.endTime = now
+	j.updatedAt = now
+	j.filePath = filePath
+
+	if err != nil {
+		j.status = "failed"
+		j.errMsg = err.Error()
+		j.message = fmt.Sprintf("%s job failed: %v", j.kind, err)
+		logger.Infof("Fake agent job %s failed: %v", j.id, err)
+		return
+	}
+
+	j.status = "completed"
+	j.message = j.doneMsg
+	if j.message == "" {
+		j.message = fmt.Sprintf("%s job completed: %d/%d queries succeeded", j.kind, j.completed, j.total)
+	}
+	logger.Infof("Fake agent job %s completed", j.id)
+}
+
+// statusResponse renders job state as checkstatus output
+func (j *fakeJob) statusResponse() job.StatusResponse {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	progress := 100
+	if j.status == "running" && j.total > 0 {
+		progress = (j.completed + j.failed) * 100 / j.total
+	}
+
+	resp := job.StatusResponse{
+		Completed:    j.completed,
+		CreatedAt:    j.createdAt.Format(time.RFC3339),
+		Failed:       j.failed,
+		JobID:        j.id,
+		Message:      j.message,
+		PID:          j.pid,
+		Platform:     runtime.GOOS,
+		Progress:     progress,
+		StartTime:    j.createdAt.Format(time.RFC3339),
+		Status:       j.status,
+		TotalQueries: j.total,
+		UpdatedAt:    j.updatedAt.Format(time.RFC3339),
+		Error:        j.errMsg,
+		Results:      j.results,
+	}
+	if !j.endTime.IsZero() {
+		resp.EndTime = j.endTime.Format(time.RFC3339)
+	}
+	return resp
+}
+
+func (a *Agent) checkStatus(jobID string) (string, error) {
+	j, err := a.getJob(jobID)
+	if err != nil {
+		return "", err
+	}
+	return marshalOutput(j.statusResponse())
+}
+
+func (a *Agent) getResults(jobID string) (string, error) {
+	j, err := a.getJob(jobID)
+	if err != nil {
+		return "", err
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	resp := map[string]interface{}{
+		"completed":     j.completed,
+		"failed":        j.failed,
+		"file_path":     j.filePath,
+		"total_queries": j.total,
+		"success":       j.status == "completed" && j.filePath != "",
+	}
+	switch {
+	case j.status != "completed":
+		resp["message"] = fmt.Sprintf("job %s is %s, results not available", jobID, j.status)
+	case j.filePath == "":
+		resp["message"] = fmt.Sprintf("job %s has no results file", jobID)
+	default:
+		resp["message"] = fmt.Sprintf("Results exported to %s", j.filePath)
+	}
+	return marshalOutput(resp)
+}
+
+func (a *Agent) cancel(jobID string) (string, error) {
+	j, err := a.getJob(jobID)
+	if err != nil {
+		return "", err
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.status != "running" {
+		return marshalOutput(map[string]interface{}{
+			"success": false,
+			"job_id":  jobID,
+			"message": fmt.Sprintf("job %s is already %s", jobID, j.status),
+		})
+	}
+
+	now := time.Now()
+	j.status = "cancelled"
+	j.message = "Job cancelled"
+	j.endTime = now
+	j.updatedAt = now
+	logger.Infof("Fake agent job %s cancelled", jobID)
+
+	return marshalOutput(map[string]interface{}{
+		"success": true,
+		"job_id":  jobID,
+		"message": "Job cancelled",
+	})
+}
+
+func (a *Agent) listJobs() (string, error) {
+	a.jobsMu.Lock()
+	jobs := make([]*fakeJob, 0, len(a.jobs))
+	for _, j := range a.jobs {
+		jobs = append(jobs, j)
+	}
+	a.jobsMu.Unlock()
+
+	sort.Slice(jobs, func(i, k int) bool { return jobs[i].id < jobs[k].id })
+
+	statuses := make([]job.StatusResponse, 0, len(jobs))
+	for _, j := range jobs {
+		statuses = append(statuses, j.statusResponse())
+	}
+
+	return marshalOutput(map[string]interface{}{
+		"success": true,
+		"jobs":    statuses,
+		"count":   len(statuses),
+	})
+}
+
+// cleanup removes finished jobs and their results files. Value "all" or empty removes every finished job.
+func (a *Agent) cleanup(jobID string) (string, error) {
+	a.jobsMu.Lock()
+	defer a.jobsMu.Unlock()
+
+	removed := 0
+	for id, j := range a.jobs {
+		if jobID != "" && jobID != "all" && id != jobID {
+			continue
+		}
+
+		j.mu.Lock()
+		finished := j.status != "running"
+		filePath := j.filePath
+		j.mu.Unlock()
+		if !finished {
+			continue
+		}
+
+		if filePath != "" {
+			os.Remove(filePath)
+		}
+		delete(a.jobs, id)
+		removed++
+	}
+
+	return marshalOutput(map[string]interface{}{
+		"success": true,
+		"removed": removed,
+		"message": fmt.Sprintf("Removed %d jobs", removed),
+	})
+}
@@ -32,6 +32,22 @@ var levelNames = map[LogLevel]string{
 	FATAL: "FATAL",
 }
 
+// String returns the level name, e.g. "INFO".
+func (l LogLevel) String() string {
+	return levelNames[l]
+}
+
+// LookupLogLevel converts a level name to its LogLevel constant, reporting false for unknown names
+// instead of falling back to INFO like ParseLogLevel.
+func LookupLogLevel(level string) (LogLevel, bool) {
+	switch strings.ToUpper(level) {
+	case "DEBUG", "INFO", "WARN", "WARNING", "ERROR", "FATAL":
+		return ParseLogLevel(level), true
+	default:
+		return INFO, false
+	}
+}
+
 // ParseLogLevel converts a string log level to its LogLevel constant.
 func ParseLogLevel(level string) LogLevel {
 	switch strings.ToUpper(level) {
@@ -0,0 +1,356 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are histogram buckets in seconds suited to HTTP request latency.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// labelSeparator joins label values into a child key; it cannot appear in valid UTF-8 text.
+const labelSeparator = "\xff"
+
+// Collector writes its samples in the Prometheus text exposition format.
+type Collector interface {
+	Name() string
+	Collect(w io.Writer)
+}
+
+// vec holds one child per distinct combination of label values.
+type vec[T any] struct {
+	name       string
+	help       string
+	labelNames []string
+	newChild   func() *T
+
+	mu       sync.RWMutex
+	children map[string]*T
+	values   map[string][]string
+}
+
+func newVec[T any](name, help string, labelNames []string, newChild func() *T) *vec[T] {
+	return &vec[T]{
+		name:       name,
+		help:       help,
+		labelNames: labelNames,
+		newChild:   newChild,
+		children:   make(map[string]*T),
+		values:     make(map[string][]string),
+	}
+}
+
+// with returns the child for labelValues, creating it on first use.
+func (v *vec[T]) with(labelValues []string) *T {
+	if len(labelValues) != len(v.labelNames) {
+		panic(fmt.Sprintf("metric %s: expected %d label values, got %d", v.name, len(v.labelNames), len(labelValues)))
+	}
+	key := strings.Join(labelValues, labelSeparator)
+
+	v.mu.RLock()
+	child, ok := v.children[key]
+	v.mu.RUnlock()
+	if ok {
+		return child
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if child, ok = v.children[key]; !ok {
+		child = v.newChild()
+		v.children[key] = child
+		v.values[key] = append([]string(nil), labelValues...)
+	}
+	return child
+}
+
+// each calls fn for every child in label order.
+func (v *vec[T]) each(fn func(labelValues []string, child *T)) {
+	v.mu.RLock()
+	keys := make([]string, 0, len(v.children))
+	for key := range v.children {
+		keys = append(keys, key)
+	}
+	v.mu.RUnlock()
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		v.mu.RLock()
+		child, values := v.children[key], v.values[key]
+		v.mu.RUnlock()
+		fn(values, child)
+	}
+}
+
+// Name implements Collector.
+func (v *vec[T]) Name() string {
+	return v.name
+}
+
+// Counter is a monotonically increasing value.
+type Counter struct {
+	value floatValue
+}
+
+// Inc adds one.
+func (c *Counter) Inc() {
+	c.value.add(1)
+}
+
+// Add adds delta, which must not be negative.
+func (c *Counter) Add(delta float64) {
+	if delta < 0 {
+		return
+	}
+	c.value.add(delta)
+}
+
+// CounterVec is a counter partitioned by labels.
+type CounterVec struct {
+	*vec[Counter]
+}
+
+// NewCounterVec creates a counter with the given label names. Register it with MustRegister.
+func NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	return &CounterVec{newVec(name, help, labelNames, func() *Counter { return &Counter{} })}
+}
+
+// WithLabelValues returns the counter for labelValues, given in label name order.
+func (v *CounterVec) WithLabelValues(labelValues ...string) *Counter {
+	return v.with(labelValues)
+}
+
+// Collect implements Collector.
+func (v *CounterVec) Collect(w io.Writer) {
+	writeHeader(w, v.name, v.help, "counter")
+	v.each(func(values []string, c *Counter) {
+		writeSample(w, v.name, v.labelNames, values, "", "", c.value.load())
+	})
+}
+
+// Gauge is a value that can go up and down.
+type Gauge struct {
+	value floatValue
+}
+
+// Set replaces the value.
+func (g *Gauge) Set(value float64) {
+	g.value.store(value)
+}
+
+// Inc adds one.
+func (g *Gauge) Inc() {
+	g.value.add(1)
+}
+
+// Dec subtracts one.
+func (g *Gauge) Dec() {
+	g.value.add(-1)
+}
+
+// GaugeVec is a gauge partitioned by labels.
+type GaugeVec struct {
+	*vec[Gauge]
+}
+
+// NewGaugeVec creates a gauge with the given label names. Register it with MustRegister.
+func NewGaugeVec(name, help string, labelNames ...string) *GaugeVec {
+	return &GaugeVec{newVec(name, help, labelNames, func() *Gauge { return &Gauge{} })}
+}
+
+// WithLabelValues returns the gauge for labelValues, given in label name order.
+func (v *GaugeVec) WithLabelValues(labelValues ...string) *Gauge {
+	return v.with(labelValues)
+}
+
+// Collect implements Collector.
+func (v *GaugeVec) Collect(w io.Writer) {
+	writeHeader(w, v.name, v.help, "gauge")
+	v.each(func(values []string, g *Gauge) {
+		writeSample(w, v.name, v.labelNames, values, "", "", g.value.load())
+	})
+}
+
+// Histogram counts observations into cumulative buckets.
+type Histogram struct {
+	mu      sync.Mutex
+	buckets []float64
+	counts  []uint64
+	sum     float64
+	count   uint64
+}
+
+// Observe records one value.
+func (h *Histogram) Observe(value float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i, upper := range h.buckets {
+		if value <= upper {
+			h.counts[i]++
+		}
+	}
+	h.sum += value
+	h.count++
+}
+
+// HistogramVec is a histogram partitioned by labels.
+type HistogramVec struct {
+	*vec[Histogram]
+	buckets []float64
+}
+
+// NewHistogramVec creates a histogram with the given upper bounds and label names.
+// buckets must be sorted ascending; nil uses DefaultBuckets. Register it with MustRegister.
+func NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	return &HistogramVec{
+		vec: newVec(name, help, labelNames, func() *Histogram {
+			return &Histogram{buckets: buckets, counts: make([]uint64, len(buckets))}
+		}),
+		buckets: buckets,
+	}
+}
+
+// WithLabelValues returns the histogram for labelValues, given in label name order.
+func (v *HistogramVec) WithLabelValues(labelValues ...string) *Histogram {
+	return v.with(labelValues)
+}
+
+// Collect implements Collector.
+func (v *HistogramVec) Collect(w io.Writer) {
+	writeHeader(w, v.name, v.help, "histogram")
+	v.each(func(values []string, h *Histogram) {
+		h.mu.Lock()
+		counts := append([]uint64(nil), h.counts...)
+		sum, count := h.sum, h.count
+		h.mu.Unlock()
+
+		for i, upper := range v.buckets {
+			writeSample(w, v.name+"_bucket", v.labelNames, values, "le", formatFloat(upper), float64(counts[i]))
+		}
+		writeSample(w, v.name+"_bucket", v.labelNames, values, "le", "+Inf", float64(count))
+		writeSample(w, v.name+"_sum", v.labelNames, values, "", "", sum)
+		writeSample(w, v.name+"_count", v.labelNames, values, "", "", float64(count))
+	})
+}
+
+// GaugeFunc is a gauge with one label whose values are computed at scrape time.
+type GaugeFunc struct {
+	name      string
+	help      string
+	labelName string
+	fn        func() map[string]float64
+}
+
+// NewGaugeFunc creates a gauge reporting fn's result as one sample per label value.
+// fn runs on every scrape and must be safe for concurrent use. Register it with MustRegister.
+func NewGaugeFunc(name, help, labelName string, fn func() map[string]float64) *GaugeFunc {
+	return &GaugeFunc{name: name, help: help, labelName: labelName, fn: fn}
+}
+
+// Name implements Collector.
+func (g *GaugeFunc) Name() string {
+	return g.name
+}
+
+// Collect implements Collector.
+func (g *GaugeFunc) Collect(w io.Writer) {
+	values := g.fn()
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	writeHeader(w, g.name, g.help, "gauge")
+	for _, key := range keys {
+		writeSample(w, g.name, []string{g.labelName}, []string{key}, "", "", values[key])
+	}
+}
+
+// floatValue is a float64 updated under a mutex; metric updates are far off any hot path.
+type floatValue struct {
+	mu    sync.Mutex
+	value float64
+}
+
+func (f *floatValue) add(delta float64) {
+	f.mu.Lock()
+	f.value += delta
+	f.mu.Unlock()
+}
+
+func (f *floatValue) store(value float64) {
+	f.mu.Lock()
+	f.value = value
+	f.mu.Unlock()
+}
+
+func (f *floatValue) load() float64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.value
+}
+
+func writeHeader(w io.Writer, name, help, typ string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, escapeHelp(help), name, typ)
+}
+
+// writeSample writes one sample line; extraName/extraValue add a label such as le.
+func writeSample(w io.Writer, name string, labelNames, labelValues []string, extraName, extraValue string, value float64) {
+	var b strings.Builder
+	b.WriteString(name)
+	if len(labelNames) > 0 || extraName != "" {
+		b.WriteByte('{')
+		for i, labelName := range labelNames {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(labelName + `="` + escapeLabelValue(labelValues[i]) + `"`)
+		}
+		if extraName != "" {
+			if len(labelNames) > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(extraName + `="` + extraValue + `"`)
+		}
+		b.WriteByte('}')
+	}
+	b.WriteByte(' ')
+	b.WriteString(formatFloat(value))
+	b.WriteByte('\n')
+	io.WriteString(w, b.String())
+}
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
+
+var (
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
+
+func escapeHelp(help string) string {
+	return helpEscaper.Replace(help)
+}
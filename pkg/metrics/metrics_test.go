@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func scrape(t *testing.T, handler http.Handler) string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if ct := w.Header().Get("Content-Type"); ct != contentType {
+		t.Errorf("Content-Type = %q, want %q", ct, contentType)
+	}
+	return w.Body.String()
+}
+
+// TestRegistry_Exposition tests the text format of counters, gauges, histograms and gauge funcs
+func TestRegistry_Exposition(t *testing.T) {
+	registry := NewRegistry()
+	calls := NewCounterVec("test_calls_total", "Agent calls.", "action", "outcome")
+	inflight := NewGaugeVec("test_inflight", "In-flight calls.")
+	latency := NewHistogramVec("test_latency_seconds", "Call latency.", []float64{0.1, 1}, "action")
+	jobs := NewGaugeFunc("test_jobs", "Jobs by status.", "status", func() map[string]float64 {
+		return map[string]float64{"running": 2, "failed": 0}
+	})
+	registry.MustRegister(calls, inflight, latency, jobs)
+
+	calls.WithLabelValues("execute", "success").Inc()
+	calls.WithLabelValues("execute", "success").Add(2)
+	calls.WithLabelValues("download", `bad "quote"`).Inc()
+	inflight.WithLabelValues().Set(3)
+	inflight.WithLabelValues().Dec()
+	latency.WithLabelValues("execute").Observe(0.05)
+	latency.WithLabelValues("execute").Observe(0.5)
+	latency.WithLabelValues("execute").Observe(5)
+
+	got := scrape(t, registry.Handler())
+	want := `# HELP test_calls_total Agent calls.
+# TYPE test_calls_total counter
+test_calls_total{action="download",outcome="bad \"quote\""} 1
+test_calls_total{action="execute",outcome="success"} 3
+# HELP test_inflight In-flight calls.
+# TYPE test_inflight gauge
+test_inflight 2
+# HELP test_jobs Jobs by status.
+# TYPE test_jobs gauge
+test_jobs{status="failed"} 0
+test_jobs{status="running"} 2
+# HELP test_latency_seconds Call latency.
+# TYPE test_latency_seconds histogram
+test_latency_seconds_bucket{action="execute",le="0.1"} 1
+test_latency_seconds_bucket{action="execute",le="1"} 2
+test_latency_seconds_bucket{action="execute",le="+Inf"} 3
+test_latency_seconds_sum{action="execute"} 5.55
+test_latency_seconds_count{action="execute"} 3
+`
+	if got != want {
+		t.Errorf("exposition mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("MustRegister accepted a duplicate metric name")
+		}
+	}()
+	registry.MustRegister(NewCounterVec("test_calls_total", "Duplicate."))
+}
+
+// TestMiddleware_RouteTemplate tests that request latency is labelled by route template, not raw path
+func TestMiddleware_RouteTemplate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Middleware())
+	router.GET("/api/items/:id", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	for _, path := range []string{"/api/items/1", "/api/items/2", "/nowhere"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	got := scrape(t, Handler())
+	for _, want := range []string{
+		`dbfartifactapi_http_request_duration_seconds_count{method="GET",route="/api/items/:id",status="204"} 2`,
+		`dbfartifactapi_http_request_duration_seconds_count{method="GET",route="unmatched",status="404"} 1`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %s in\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// contentType is the Prometheus text exposition format version served by Handler.
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Registry holds the collectors exposed on one metrics endpoint.
+type Registry struct {
+	mu         sync.RWMutex
+	collectors map[string]Collector
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{collectors: make(map[string]Collector)}
+}
+
+// defaultRegistry collects the metrics registered by application packages.
+var defaultRegistry = NewRegistry()
+
+// MustRegister adds collectors to r and panics on a duplicate metric name.
+func (r *Registry) MustRegister(collectors ...Collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, c := range collectors {
+		if _, exists := r.collectors[c.Name()]; exists {
+			panic(fmt.Sprintf("metric %s registered twice", c.Name()))
+		}
+		r.collectors[c.Name()] = c
+	}
+}
+
+// MustRegister adds collectors to the default registry, typically from a package init.
+func MustRegister(collectors ...Collector) {
+	defaultRegistry.MustRegister(collectors...)
+}
+
+// Handler serves every collector of r in name order.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		r.mu.RLock()
+		names := make([]string, 0, len(r.collectors))
+		for name := range r.collectors {
+			names = append(names, name)
+		}
+		collectors := make([]Collector, 0, len(names))
+		sort.Strings(names)
+		for _, name := range names {
+			collectors = append(collectors, r.collectors[name])
+		}
+		r.mu.RUnlock()
+
+		var buf bytes.Buffer
+		for _, c := range collectors {
+			c.Collect(&buf)
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Write(buf.Bytes())
+	})
+}
+
+// Handler serves the default registry.
+func Handler() http.Handler {
+	return defaultRegistry.Handler()
+}
+
+var httpRequestDuration = NewHistogramVec("dbfartifactapi_http_request_duration_seconds",
+	"HTTP request latency by method, route template and status code.", nil, "method", "route", "status")
+
+func init() {
+	MustRegister(httpRequestDuration)
+}
+
+// Middleware records the latency of every request under its route template,
+// so /api/queries/dbactormgt/:id is one series regardless of the ID.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		httpRequestDuration.WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).
+			Observe(time.Since(start).Seconds())
+	}
+}
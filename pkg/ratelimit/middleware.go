@@ -0,0 +1,167 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"dbfartifactapi/pkg/auth"
+	"dbfartifactapi/pkg/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Rate limit response headers
+const (
+	HeaderLimit     = "X-RateLimit-Limit"
+	HeaderRemaining = "X-RateLimit-Remaining"
+	HeaderReset     = "X-RateLimit-Reset"
+)
+
+var rateLimitedRequests = metrics.NewCounterVec("dbfartifactapi_rate_limited_requests_total",
+	"Requests rejected with 429 by route and limit scope (client or route).", "route", "scope")
+
+func init() {
+	metrics.MustRegister(rateLimitedRequests)
+}
+
+// Rule limits one route per client. An empty Method matches every method.
+type Rule struct {
+	Method string
+	Route  string // Gin route pattern, e.g. /api/queries/dbobjectmgt/cntmgt/:id
+	Rate   float64
+	Burst  int
+}
+
+// Options configures Middleware.
+type Options struct {
+	ClientRate  float64 // Requests per second per client across all routes (0 disables the client limit)
+	ClientBurst int
+	Routes      []Rule
+}
+
+// ParseRules parses entries "[METHOD ]/route=rate:burst", e.g. "GET /api/queries/dbobjectmgt/cntmgt/:id=0.5:5".
+func ParseRules(entries []string) ([]Rule, error) {
+	rules := make([]Rule, 0, len(entries))
+	for _, entry := range entries {
+		sep := strings.LastIndex(entry, "=")
+		if sep < 0 {
+			return nil, fmt.Errorf("invalid rate limit rule %q, expected [METHOD ]/route=rate:burst", entry)
+		}
+		target, limits := strings.TrimSpace(entry[:sep]), entry[sep+1:]
+
+		var rule Rule
+		if method, route, found := strings.Cut(target, " "); found {
+			rule.Method = strings.ToUpper(method)
+			rule.Route = strings.TrimSpace(route)
+		} else {
+			rule.Route = target
+		}
+		if !strings.HasPrefix(rule.Route, "/") {
+			return nil, fmt.Errorf("invalid rate limit rule %q, route must start with /", entry)
+		}
+
+		rateStr, burstStr, found := strings.Cut(limits, ":")
+		if !found {
+			return nil, fmt.Errorf("invalid rate limit rule %q, expected rate:burst after =", entry)
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(rateStr), 64)
+		if err != nil || rate <= 0 {
+			return nil, fmt.Errorf("invalid rate in rate limit rule %q, must be a positive number", entry)
+		}
+		burst, err := strconv.Atoi(strings.TrimSpace(burstStr))
+		if err != nil || burst < 1 {
+			return nil, fmt.Errorf("invalid burst in rate limit rule %q, must be a positive integer", entry)
+		}
+		rule.Rate, rule.Burst = rate, burst
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+type routeLimiter struct {
+	rule    Rule
+	limiter *Limiter
+}
+
+// Middleware rejects requests over the client's rate with 429 Too Many Requests.
+// It must run after the auth middleware: clients are identified by their principal,
+// or by IP when authentication is disabled. Each client has one bucket across all routes plus one per
+// matching route rule. Every response carries X-RateLimit-* headers of the most restrictive bucket,
+// and rejections carry Retry-After.
+func Middleware(opts Options) gin.HandlerFunc {
+	var client *Limiter
+	if opts.ClientRate > 0 {
+		client = NewLimiter(opts.ClientRate, opts.ClientBurst)
+	}
+	routes := make([]routeLimiter, 0, len(opts.Routes))
+	for _, rule := range opts.Routes {
+		routes = append(routes, routeLimiter{rule: rule, limiter: NewLimiter(rule.Rate, rule.Burst)})
+	}
+
+	return func(c *gin.Context) {
+		clientKey := "ip:" + c.ClientIP()
+		if principal := auth.PrincipalFrom(c); principal != nil && principal.Method != auth.MethodNone {
+			clientKey = principal.Method + ":" + principal.Subject
+		}
+		route := c.FullPath()
+
+		var decisions []Decision
+		// Route rules are checked first so a rejected expensive call doesn't also use up the client budget
+		for _, r := range routes {
+			if r.rule.Route != route || (r.rule.Method != "" && r.rule.Method != c.Request.Method) {
+				continue
+			}
+			decision := r.limiter.Allow(clientKey + " " + c.Request.Method + " " + route)
+			decisions = append(decisions, decision)
+			if !decision.Allowed {
+				reject(c, route, "route", decisions)
+				return
+			}
+		}
+		if client != nil {
+			decision := client.Allow(clientKey)
+			decisions = append(decisions, decision)
+			if !decision.Allowed {
+				reject(c, route, "client", decisions)
+				return
+			}
+		}
+
+		setHeaders(c, decisions)
+		c.Next()
+	}
+}
+
+func reject(c *gin.Context, route, scope string, decisions []Decision) {
+	rateLimitedRequests.WithLabelValues(route, scope).Inc()
+	setHeaders(c, decisions)
+	retryAfter := decisions[len(decisions)-1].RetryAfter
+	c.Header("Retry-After", strconv.Itoa(ceilSeconds(retryAfter)))
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+		"error": fmt.Sprintf("rate limit exceeded, retry in %d seconds", ceilSeconds(retryAfter)),
+	})
+}
+
+// setHeaders reports the bucket with the fewest remaining tokens.
+func setHeaders(c *gin.Context, decisions []Decision) {
+	if len(decisions) == 0 {
+		return
+	}
+	tightest := decisions[0]
+	for _, d := range decisions[1:] {
+		if d.Remaining < tightest.Remaining {
+			tightest = d
+		}
+	}
+	c.Header(HeaderLimit, strconv.Itoa(tightest.Limit))
+	c.Header(HeaderRemaining, strconv.Itoa(tightest.Remaining))
+	c.Header(HeaderReset, strconv.Itoa(ceilSeconds(tightest.Reset)))
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,163 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"dbfartifactapi/pkg/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestLimiter_RefillsAtRate tests that a bucket allows a burst, rejects, then refills over time
+func TestLimiter_RefillsAtRate(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	l := NewLimiter(2, 3)
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if d := l.Allow("a"); !d.Allowed || d.Remaining != 2-i {
+			t.Fatalf("request %d = %+v, want allowed with %d remaining", i, d, 2-i)
+		}
+	}
+	d := l.Allow("a")
+	if d.Allowed {
+		t.Fatal("expected fourth request in burst to be rejected")
+	}
+	if d.RetryAfter != 500*time.Millisecond || d.Reset != 1500*time.Millisecond {
+		t.Errorf("RetryAfter = %v, Reset = %v, want 500ms and 1.5s", d.RetryAfter, d.Reset)
+	}
+	if other := l.Allow("b"); !other.Allowed {
+		t.Error("expected other key to have its own bucket")
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if d := l.Allow("a"); !d.Allowed {
+		t.Errorf("expected a token after 500ms at 2/s, got %+v", d)
+	}
+
+	now = now.Add(time.Hour)
+	l.Allow("c")
+	if _, exists := l.buckets["a"]; exists {
+		t.Error("expected idle bucket to be swept")
+	}
+}
+
+// TestParseRules tests rule parsing with and without method, and rejects malformed entries
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules([]string{
+		"GET /api/queries/dbobjectmgt/cntmgt/:id=0.5:5",
+		"/api/queries/backup=1:2",
+	})
+	if err != nil {
+		t.Fatalf("ParseRules: %v", err)
+	}
+	want := []Rule{
+		{Method: "GET", Route: "/api/queries/dbobjectmgt/cntmgt/:id", Rate: 0.5, Burst: 5},
+		{Route: "/api/queries/backup", Rate: 1, Burst: 2},
+	}
+	for i := range want {
+		if rules[i] != want[i] {
+			t.Errorf("rule %d = %+v, want %+v", i, rules[i], want[i])
+		}
+	}
+
+	for _, entry := range []string{"/api/x", "api/x=1:1", "/api/x=1", "/api/x=0:1", "/api/x=1:0", "/api/x=a:1"} {
+		if _, err := ParseRules([]string{entry}); err == nil {
+			t.Errorf("expected error for %q", entry)
+		}
+	}
+}
+
+// newTestRouter serves two routes behind authMiddleware and the rate limit middleware
+func newTestRouter(opts Options, authMiddleware gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(authMiddleware, Middleware(opts))
+	router.GET("/api/items/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/api/other", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func get(router *gin.Engine, path string) *httptest.ResponseRecorder {
+	return serve(router, path, "192.0.2.1:1234", "")
+}
+
+func serve(router *gin.Engine, path, remoteAddr, apiKey string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = remoteAddr
+	if apiKey != "" {
+		req.Header.Set(auth.APIKeyHeader, apiKey)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// TestMiddleware_RouteAndClientLimits tests 429 with headers once a route rule or the client budget is used up
+func TestMiddleware_RouteAndClientLimits(t *testing.T) {
+	router := newTestRouter(Options{
+		ClientRate:  1,
+		ClientBurst: 4,
+		Routes:      []Rule{{Method: http.MethodGet, Route: "/api/items/:id", Rate: 0.1, Burst: 2}},
+	}, auth.Anonymous())
+
+	// Different IDs share the route bucket
+	for _, path := range []string{"/api/items/1", "/api/items/2"} {
+		if w := get(router, path); w.Code != http.StatusOK {
+			t.Fatalf("GET %s = %d, want 200", path, w.Code)
+		}
+	}
+	w := get(router, "/api/items/3")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("third route request = %d, want 429", w.Code)
+	}
+	if w.Header().Get("Retry-After") != "10" || w.Header().Get(HeaderRemaining) != "0" || w.Header().Get(HeaderLimit) != "2" {
+		t.Errorf("headers = %v, want Retry-After 10, remaining 0, limit 2", w.Header())
+	}
+
+	// The route rejection did not use the client budget: 2 of 4 client tokens are left
+	for i := 0; i < 2; i++ {
+		w := get(router, "/api/other")
+		if w.Code != http.StatusOK {
+			t.Fatalf("other request %d = %d, want 200", i, w.Code)
+		}
+		if got := w.Header().Get(HeaderRemaining); got != []string{"1", "0"}[i] {
+			t.Errorf("other request %d remaining = %s", i, got)
+		}
+	}
+	if w := get(router, "/api/other"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("request over client budget = %d, want 429", w.Code)
+	}
+}
+
+// TestMiddleware_ClientKeys tests that authenticated callers are limited per principal and anonymous ones per IP
+func TestMiddleware_ClientKeys(t *testing.T) {
+	opts := Options{ClientRate: 1, ClientBurst: 1}
+	keys := []auth.APIKey{{Name: "alice", Role: auth.RoleViewer, Key: "k1"}, {Name: "bob", Role: auth.RoleViewer, Key: "k2"}}
+	router := newTestRouter(opts, auth.Middleware(auth.NewAPIKeyAuthenticator(keys)))
+
+	if w := serve(router, "/api/other", "192.0.2.1:1", "k1"); w.Code != http.StatusOK {
+		t.Fatalf("alice first request = %d, want 200", w.Code)
+	}
+	// Same caller from another address shares the bucket
+	if w := serve(router, "/api/other", "192.0.2.2:1", "k1"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("alice second request = %d, want 429", w.Code)
+	}
+	if w := serve(router, "/api/other", "192.0.2.1:1", "k2"); w.Code != http.StatusOK {
+		t.Errorf("bob request = %d, want 200", w.Code)
+	}
+
+	anonymous := newTestRouter(opts, auth.Anonymous())
+	if w := serve(anonymous, "/api/other", "192.0.2.1:1", ""); w.Code != http.StatusOK {
+		t.Fatalf("anonymous first request = %d, want 200", w.Code)
+	}
+	if w := serve(anonymous, "/api/other", "192.0.2.2:1", ""); w.Code != http.StatusOK {
+		t.Errorf("anonymous request from another IP = %d, want 200", w.Code)
+	}
+	if w := serve(anonymous, "/api/other", "192.0.2.1:1", ""); w.Code != http.StatusTooManyRequests {
+		t.Errorf("anonymous second request from same IP = %d, want 429", w.Code)
+	}
+}
@@ -0,0 +1,248 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// encryptedPrefix marks a value produced by Keyring.Encrypt; anything else is treated as legacy plain text.
+const encryptedPrefix = "enc:v1:"
+
+// keySize is the length of key-encryption and data-encryption keys (AES-256).
+const keySize = 32
+
+// Keyring holds the key-encryption keys (KEKs) used for envelope encryption.
+// Every value gets its own random data-encryption key (DEK) which is stored wrapped by the active KEK,
+// so rotating the KEK only re-wraps the DEK and never touches the payload.
+type Keyring struct {
+	active string
+	keys   map[string][]byte
+}
+
+// NewKeyring creates a keyring; active may be empty when keys holds exactly one key.
+func NewKeyring(active string, keys map[string][]byte) (*Keyring, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("keyring has no keys")
+	}
+	for id, key := range keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("invalid key id %q", id)
+		}
+		if len(key) != keySize {
+			return nil, fmt.Errorf("key %s must be %d bytes, got %d", id, keySize, len(key))
+		}
+	}
+	if active == "" {
+		if len(keys) != 1 {
+			return nil, fmt.Errorf("active key id is required when more than one key is configured")
+		}
+		for id := range keys {
+			active = id
+		}
+	}
+	if _, ok := keys[active]; !ok {
+		return nil, fmt.Errorf("active key %s is not in the keyring", active)
+	}
+	return &Keyring{active: active, keys: keys}, nil
+}
+
+// ParseKeys parses "id:base64key" entries into a keyring.
+func ParseKeys(entries []string, active string) (*Keyring, error) {
+	keys := make(map[string][]byte, len(entries))
+	for _, entry := range entries {
+		id, encoded, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("invalid key entry, expected id:base64key")
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: invalid base64: %w", id, err)
+		}
+		keys[id] = key
+	}
+	return NewKeyring(active, keys)
+}
+
+// LoadKeyFile reads a keyfile of the form {"active": "k2", "keys": {"k1": "<base64>", "k2": "<base64>"}}.
+func LoadKeyFile(path string) (*Keyring, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keyfile: %w", err)
+	}
+	var doc struct {
+		Active string            `json:"active"`
+		Keys   map[string]string `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid keyfile: %w", err)
+	}
+	entries := make([]string, 0, len(doc.Keys))
+	for id, key := range doc.Keys {
+		entries = append(entries, id+":"+key)
+	}
+	return ParseKeys(entries, doc.Active)
+}
+
+// ActiveKeyID returns the ID of the key new values are wrapped with.
+func (k *Keyring) ActiveKeyID() string {
+	return k.active
+}
+
+// IsEncrypted reports whether value was produced by Keyring.Encrypt.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, encryptedPrefix)
+}
+
+// envelope is the parsed form of "enc:v1:<key id>:<wrapped DEK>:<payload>".
+type envelope struct {
+	keyID   string
+	wrapped []byte
+	payload []byte
+}
+
+func parseEnvelope(value string) (*envelope, error) {
+	parts := strings.Split(strings.TrimPrefix(value, encryptedPrefix), ":")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed encrypted value")
+	}
+	wrapped, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("malformed wrapped key: %w", err)
+	}
+	payload, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed payload: %w", err)
+	}
+	return &envelope{keyID: parts[0], wrapped: wrapped, payload: payload}, nil
+}
+
+func (e *envelope) String() string {
+	return encryptedPrefix + e.keyID + ":" +
+		base64.RawStdEncoding.EncodeToString(e.wrapped) + ":" +
+		base64.RawStdEncoding.EncodeToString(e.payload)
+}
+
+// Encrypt seals plaintext under a fresh DEK wrapped by the active key.
+func (k *Keyring) Encrypt(plaintext string) (string, error) {
+	dek := make([]byte, keySize)
+	if _, err := rand.Read(dek); err != nil {
+		return "", fmt.Errorf("failed to generate data key: %w", err)
+	}
+	payload, err := seal(dek, []byte(plaintext), nil)
+	if err != nil {
+		return "", err
+	}
+	wrapped, err := seal(k.keys[k.active], dek, []byte(k.active))
+	if err != nil {
+		return "", err
+	}
+	return (&envelope{keyID: k.active, wrapped: wrapped, payload: payload}).String(), nil
+}
+
+// Decrypt opens a value produced by Encrypt. Values without the encrypted prefix are returned unchanged.
+func (k *Keyring) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	env, err := parseEnvelope(value)
+	if err != nil {
+		return "", err
+	}
+	dek, err := k.unwrap(env)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(dek, env.payload, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// NeedsRewrap reports whether value is plain text or wrapped by a key other than the active one.
+func (k *Keyring) NeedsRewrap(value string) bool {
+	if value == "" {
+		return false
+	}
+	if !IsEncrypted(value) {
+		return true
+	}
+	env, err := parseEnvelope(value)
+	return err == nil && env.keyID != k.active
+}
+
+// Rewrap encrypts plain text and moves encrypted values to the active key.
+// Only the DEK is re-wrapped; the payload ciphertext is kept as is.
+func (k *Keyring) Rewrap(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return k.Encrypt(value)
+	}
+	env, err := parseEnvelope(value)
+	if err != nil {
+		return "", err
+	}
+	if env.keyID == k.active {
+		return value, nil
+	}
+	dek, err := k.unwrap(env)
+	if err != nil {
+		return "", err
+	}
+	wrapped, err := seal(k.keys[k.active], dek, []byte(k.active))
+	if err != nil {
+		return "", err
+	}
+	return (&envelope{keyID: k.active, wrapped: wrapped, payload: env.payload}).String(), nil
+}
+
+func (k *Keyring) unwrap(env *envelope) ([]byte, error) {
+	kek, ok := k.keys[env.keyID]
+	if !ok {
+		return nil, fmt.Errorf("value is encrypted with unknown key %s", env.keyID)
+	}
+	dek, err := open(kek, env.wrapped, []byte(env.keyID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+	return dek, nil
+}
+
+// seal encrypts data with AES-GCM and returns nonce || ciphertext.
+func seal(key, data, additional []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return gcm.Seal(nonce, nonce, data, additional), nil
+}
+
+// open reverses seal.
+func open(key, sealed, additional []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, additional)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,161 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// vaultTimeout bounds a single secret lookup against the Vault API.
+const vaultTimeout = 10 * time.Second
+
+// defaultVaultField is the key read from a Vault secret when the reference names none.
+const defaultVaultField = "password"
+
+// Provider resolves a secret ID to its value.
+type Provider interface {
+	Resolve(ctx context.Context, id string) (string, error)
+}
+
+// Resolver dispatches secret references of the form "scheme:id" to the provider registered for scheme.
+type Resolver struct {
+	providers map[string]Provider
+}
+
+// NewResolver creates a resolver without providers.
+func NewResolver() *Resolver {
+	return &Resolver{providers: make(map[string]Provider)}
+}
+
+// Register makes p handle references with the given scheme.
+func (r *Resolver) Register(scheme string, p Provider) {
+	r.providers[scheme] = p
+}
+
+// Resolve returns the secret value for ref.
+func (r *Resolver) Resolve(ctx context.Context, ref string) (string, error) {
+	scheme, id, ok := strings.Cut(ref, ":")
+	if !ok || id == "" {
+		return "", fmt.Errorf("invalid secret reference %q, expected scheme:id", ref)
+	}
+	p, ok := r.providers[scheme]
+	if !ok {
+		return "", fmt.Errorf("no secret provider configured for scheme %s", scheme)
+	}
+	value, err := p.Resolve(ctx, id)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve secret %s: %w", ref, err)
+	}
+	return value, nil
+}
+
+// fileProvider reads secrets from files below a base directory, e.g. mounted Kubernetes secrets.
+type fileProvider struct {
+	dir string
+}
+
+// NewFileProvider creates a provider for "file:<relative path>" references.
+func NewFileProvider(dir string) Provider {
+	return &fileProvider{dir: dir}
+}
+
+// Resolve implements Provider. Trailing newlines are stripped.
+func (p *fileProvider) Resolve(_ context.Context, id string) (string, error) {
+	name := filepath.Clean(filepath.FromSlash(id))
+	if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("secret path must stay inside the secret directory")
+	}
+	data, err := os.ReadFile(filepath.Join(p.dir, name))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// envProvider reads secrets from environment variables sharing a fixed prefix,
+// so a reference can never expose unrelated process environment.
+type envProvider struct {
+	prefix string
+}
+
+// NewEnvProvider creates a provider for "env:<name>" references, read from variable prefix+name.
+func NewEnvProvider(prefix string) Provider {
+	return &envProvider{prefix: prefix}
+}
+
+// Resolve implements Provider.
+func (p *envProvider) Resolve(_ context.Context, id string) (string, error) {
+	value, ok := os.LookupEnv(p.prefix + id)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s%s is not set", p.prefix, id)
+	}
+	return value, nil
+}
+
+// vaultProvider reads secrets from a Vault-compatible HTTP API (KV version 1 or 2).
+type vaultProvider struct {
+	address string
+	token   string
+	client  *http.Client
+}
+
+// NewVaultProvider creates a provider for "vault:<path>[#field]" references,
+// e.g. "vault:secret/data/db/prod#password" for a KV v2 mount.
+func NewVaultProvider(address, token string) Provider {
+	return &vaultProvider{
+		address: strings.TrimRight(address, "/"),
+		token:   token,
+		client:  &http.Client{Timeout: vaultTimeout},
+	}
+}
+
+// Resolve implements Provider.
+func (p *vaultProvider) Resolve(ctx context.Context, id string) (string, error) {
+	path, field, _ := strings.Cut(id, "#")
+	if field == "" {
+		field = defaultVaultField
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.address+"/v1/"+strings.TrimLeft(path, "/"), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("vault request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+		return "", fmt.Errorf("vault returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("invalid vault response: %w", err)
+	}
+
+	// KV v2 nests the secret under data.data next to data.metadata
+	data := body.Data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, hasMetadata := data["metadata"]; hasMetadata {
+			data = nested
+		}
+	}
+	value, ok := data[field].(string)
+	if !ok {
+		return "", fmt.Errorf("vault secret has no string field %s", field)
+	}
+	return value, nil
+}
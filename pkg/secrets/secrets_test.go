@@ -0,0 +1,185 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, keySize))
+}
+
+// TestKeyring_EncryptRotate tests envelope round trips, key rotation and tamper detection
+func TestKeyring_EncryptRotate(t *testing.T) {
+	old, err := ParseKeys([]string{"k1:" + testKey(1)}, "")
+	if err != nil {
+		t.Fatalf("ParseKeys: %v", err)
+	}
+
+	sealed, err := old.Encrypt("s3cr3t:pa$$")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if !IsEncrypted(sealed) || strings.Contains(sealed, "s3cr3t") {
+		t.Fatalf("Encrypt returned %q, want an opaque envelope", sealed)
+	}
+	if again, _ := old.Encrypt("s3cr3t:pa$$"); again == sealed {
+		t.Error("Encrypt is deterministic, want a fresh data key per value")
+	}
+	if got, err := old.Decrypt(sealed); err != nil || got != "s3cr3t:pa$$" {
+		t.Errorf("Decrypt = %q, %v", got, err)
+	}
+	if got, _ := old.Decrypt("legacy"); got != "legacy" {
+		t.Errorf("Decrypt(plain text) = %q, want it unchanged", got)
+	}
+
+	rotated, err := ParseKeys([]string{"k1:" + testKey(1), "k2:" + testKey(2)}, "k2")
+	if err != nil {
+		t.Fatalf("ParseKeys: %v", err)
+	}
+	if !rotated.NeedsRewrap(sealed) || !rotated.NeedsRewrap("legacy") || rotated.NeedsRewrap("") {
+		t.Error("NeedsRewrap should flag retired keys and plain text only")
+	}
+	rewrapped, err := rotated.Rewrap(sealed)
+	if err != nil {
+		t.Fatalf("Rewrap: %v", err)
+	}
+	if !strings.HasPrefix(rewrapped, encryptedPrefix+"k2:") || rotated.NeedsRewrap(rewrapped) {
+		t.Errorf("Rewrap = %q, want value under k2", rewrapped)
+	}
+	if got, err := rotated.Decrypt(rewrapped); err != nil || got != "s3cr3t:pa$$" {
+		t.Errorf("Decrypt after rewrap = %q, %v", got, err)
+	}
+	if _, err := old.Decrypt(rewrapped); err == nil {
+		t.Error("Decrypt succeeded without the new key")
+	}
+
+	env, _ := parseEnvelope(rewrapped)
+	env.payload[len(env.payload)-1] ^= 0xff
+	if _, err := rotated.Decrypt(env.String()); err == nil {
+		t.Error("Decrypt accepted a tampered payload")
+	}
+	swapped := strings.Replace(sealed, encryptedPrefix+"k1:", encryptedPrefix+"k2:", 1)
+	if _, err := rotated.Decrypt(swapped); err == nil {
+		t.Error("Decrypt accepted a wrapped key under a different key id")
+	}
+
+	if _, err := ParseKeys([]string{"k1:" + testKey(1), "k2:" + testKey(2)}, ""); err == nil {
+		t.Error("ParseKeys accepted several keys without an active key")
+	}
+	if _, err := ParseKeys([]string{"k1:" + base64.StdEncoding.EncodeToString([]byte("short"))}, ""); err == nil {
+		t.Error("ParseKeys accepted a short key")
+	}
+}
+
+// TestLoadKeyFile tests the JSON keyfile format
+func TestLoadKeyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	doc := `{"active": "k2", "keys": {"k1": "` + testKey(1) + `", "k2": "` + testKey(2) + `"}}`
+	if err := os.WriteFile(path, []byte(doc), 0600); err != nil {
+		t.Fatal(err)
+	}
+	keyring, err := LoadKeyFile(path)
+	if err != nil {
+		t.Fatalf("LoadKeyFile: %v", err)
+	}
+	if keyring.ActiveKeyID() != "k2" {
+		t.Errorf("active key = %s, want k2", keyring.ActiveKeyID())
+	}
+}
+
+// TestResolver_Providers tests file, env and Vault secret references
+func TestResolver_Providers(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "db"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "db", "prod"), []byte("from-file\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TEST_SECRET_PROD", "from-env")
+	t.Setenv("UNPREFIXED", "leak")
+
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "vault-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/db/prod":
+			w.Write([]byte(`{"data": {"data": {"password": "from-kv2", "user": "app"}, "metadata": {"version": 3}}}`))
+		case "/v1/kv/db/prod":
+			w.Write([]byte(`{"data": {"pw": "from-kv1"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer vault.Close()
+
+	resolver := NewResolver()
+	resolver.Register("file", NewFileProvider(dir))
+	resolver.Register("env", NewEnvProvider("TEST_SECRET_"))
+	resolver.Register("vault", NewVaultProvider(vault.URL, "vault-token"))
+
+	ctx := context.Background()
+	want := map[string]string{
+		"file:db/prod":                    "from-file",
+		"env:PROD":                        "from-env",
+		"vault:secret/data/db/prod":       "from-kv2",
+		"vault:kv/db/prod#pw":             "from-kv1",
+		"vault:/secret/data/db/prod#user": "app",
+	}
+	for ref, value := range want {
+		if got, err := resolver.Resolve(ctx, ref); err != nil || got != value {
+			t.Errorf("Resolve(%s) = %q, %v, want %q", ref, got, err, value)
+		}
+	}
+
+	for _, ref := range []string{"file:../outside", "file:/etc/passwd", "env:../UNPREFIXED", "vault:missing", "vault:kv/db/prod", "s3:bucket/key", "nope"} {
+		if got, err := resolver.Resolve(ctx, ref); err == nil {
+			t.Errorf("Resolve(%s) = %q, want an error", ref, got)
+		}
+	}
+}
+
+// TestStore_Reveal tests that stored values and references resolve to the plain text password
+func TestStore_Reveal(t *testing.T) {
+	keyring, _ := ParseKeys([]string{"k1:" + testKey(1)}, "")
+	t.Setenv("TEST_SECRET_DB", "referenced")
+	resolver := NewResolver()
+	resolver.Register("env", NewEnvProvider("TEST_SECRET_"))
+	store := NewStore(keyring, resolver)
+
+	sealed, err := store.Seal("stored")
+	if err != nil || !IsEncrypted(sealed) {
+		t.Fatalf("Seal = %q, %v", sealed, err)
+	}
+	ctx := context.Background()
+	if got, err := store.Reveal(ctx, sealed, ""); err != nil || got != "stored" {
+		t.Errorf("Reveal(sealed) = %q, %v", got, err)
+	}
+	if got, err := store.Reveal(ctx, sealed, "env:DB"); err != nil || got != "referenced" {
+		t.Errorf("Reveal(ref) = %q, %v", got, err)
+	}
+	if got, err := store.Reveal(ctx, "legacy", ""); err != nil || got != "legacy" {
+		t.Errorf("Reveal(plain text) = %q, %v", got, err)
+	}
+
+	plain := NewStore(nil, nil)
+	if got, _ := plain.Seal("stored"); got != "stored" {
+		t.Errorf("Seal without keyring = %q, want plain text", got)
+	}
+	if _, err := plain.Reveal(ctx, sealed, ""); err == nil {
+		t.Error("Reveal decrypted without a keyring")
+	}
+	if _, err := plain.Reveal(ctx, "", "env:DB"); err == nil {
+		t.Error("Reveal resolved a reference without a resolver")
+	}
+}
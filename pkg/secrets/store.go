@@ -0,0 +1,88 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Store seals credentials before they are persisted and reveals them only when a command
+// is handed to an agent. Stored values stay encrypted everywhere in between.
+type Store struct {
+	keyring  *Keyring  // nil keeps new values in plain text
+	resolver *Resolver // nil rejects secret references
+}
+
+// NewStore creates a credential store; either argument may be nil.
+func NewStore(keyring *Keyring, resolver *Resolver) *Store {
+	return &Store{keyring: keyring, resolver: resolver}
+}
+
+var (
+	defaultStore   = NewStore(nil, nil)
+	defaultStoreMu sync.RWMutex
+)
+
+// SetDefault replaces the process-wide credential store.
+func SetDefault(store *Store) {
+	defaultStoreMu.Lock()
+	defer defaultStoreMu.Unlock()
+	defaultStore = store
+}
+
+// Default returns the process-wide credential store.
+func Default() *Store {
+	defaultStoreMu.RLock()
+	defer defaultStoreMu.RUnlock()
+	return defaultStore
+}
+
+// EncryptionEnabled reports whether the store has a keyring.
+func (s *Store) EncryptionEnabled() bool {
+	return s.keyring != nil
+}
+
+// Seal returns plaintext in its stored form: encrypted with the active key, or unchanged without a keyring.
+func (s *Store) Seal(plaintext string) (string, error) {
+	if s.keyring == nil || plaintext == "" {
+		return plaintext, nil
+	}
+	return s.keyring.Encrypt(plaintext)
+}
+
+// Open returns the plain text of a stored value. Legacy plain text values are returned unchanged.
+func (s *Store) Open(stored string) (string, error) {
+	if !IsEncrypted(stored) {
+		return stored, nil
+	}
+	if s.keyring == nil {
+		return "", fmt.Errorf("credential is encrypted but no credential key is configured")
+	}
+	return s.keyring.Decrypt(stored)
+}
+
+// Reseal brings a stored value to the active key, encrypting legacy plain text.
+// Returns the value unchanged when it is already current or no keyring is configured.
+func (s *Store) Reseal(stored string) (string, error) {
+	if s.keyring == nil || !s.keyring.NeedsRewrap(stored) {
+		return stored, nil
+	}
+	return s.keyring.Rewrap(stored)
+}
+
+// NeedsReseal reports whether Reseal would change stored.
+func (s *Store) NeedsReseal(stored string) bool {
+	return s.keyring != nil && s.keyring.NeedsRewrap(stored)
+}
+
+// Reveal returns the password for an agent call: the secret behind ref when one is set,
+// otherwise the decrypted stored value.
+func (s *Store) Reveal(ctx context.Context, stored, ref string) (string, error) {
+	if ref == "" {
+		return s.Open(stored)
+	}
+	if s.resolver == nil {
+		return "", fmt.Errorf("secret reference %s cannot be resolved: no secret provider is configured", ref)
+	}
+	return s.resolver.Resolve(ctx, ref)
+}
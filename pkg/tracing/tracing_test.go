@@ -0,0 +1,97 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+const incomingTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+// TestMiddleware_ContinuesIncomingTrace tests that request spans join the caller's trace and reach handlers
+func TestMiddleware_ContinuesIncomingTrace(t *testing.T) {
+	recorder := recordSpans(t)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Middleware())
+
+	var handlerTraceParent string
+	router.GET("/api/items/:id", func(c *gin.Context) {
+		_, span := Start(c.Request.Context(), "service.call")
+		handlerTraceParent = TraceParent(c.Request.Context())
+		span.End()
+		c.Status(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/items/7", nil)
+	req.Header.Set("traceparent", incomingTraceParent)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if got := w.Header().Get("X-Trace-Id"); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("X-Trace-Id = %q, want incoming trace ID", got)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(spans))
+	}
+	service, server := spans[0], spans[1]
+	if server.Name() != "GET /api/items/:id" {
+		t.Errorf("Server span name = %q, want route template", server.Name())
+	}
+	if server.Parent().SpanID().String() != "00f067aa0ba902b7" {
+		t.Errorf("Server span parent = %s, want incoming span", server.Parent().SpanID())
+	}
+	if service.Parent().SpanID() != server.SpanContext().SpanID() {
+		t.Errorf("Service span is not a child of the server span")
+	}
+	if handlerTraceParent == "" || handlerTraceParent == incomingTraceParent {
+		t.Errorf("Handler traceparent = %q, want the server span", handlerTraceParent)
+	}
+}
+
+// TestStartLinked_LinksOriginatingSpan tests that deferred work starts a new trace linked to the stored traceparent
+func TestStartLinked_LinksOriginatingSpan(t *testing.T) {
+	recorder := recordSpans(t)
+
+	_, span := StartLinked(incomingTraceParent, "job.completion")
+	End(span, context.DeadlineExceeded)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(spans))
+	}
+	completion := spans[0]
+	if completion.Parent().IsValid() {
+		t.Errorf("Expected a root span, got parent %s", completion.Parent().SpanID())
+	}
+	if len(completion.Links()) != 1 || completion.Links()[0].SpanContext.SpanID().String() != "00f067aa0ba902b7" {
+		t.Errorf("Expected link to the originating span, got %v", completion.Links())
+	}
+	if completion.Status().Description != context.DeadlineExceeded.Error() {
+		t.Errorf("Expected error status, got %v", completion.Status())
+	}
+
+	// An unknown origin still produces a span, just without a link
+	_, span = StartLinked("", "job.completion")
+	span.End()
+	if links := recorder.Ended()[1].Links(); len(links) != 0 {
+		t.Errorf("Expected no links without a traceparent, got %v", links)
+	}
+}
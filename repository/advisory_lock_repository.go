@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+
+	"dbfartifactapi/config"
+	"dbfartifactapi/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+// AdvisoryLockRepository takes MySQL named locks (GET_LOCK) to coordinate work between API instances
+// sharing the config database.
+type AdvisoryLockRepository interface {
+	TryLock(ctx context.Context, name string) (release func(), acquired bool, err error)
+}
+
+type advisoryLockRepository struct {
+	db *gorm.DB
+}
+
+// NewAdvisoryLockRepository creates a new advisory lock repository instance.
+func NewAdvisoryLockRepository() AdvisoryLockRepository {
+	return &advisoryLockRepository{
+		db: config.DB,
+	}
+}
+
+// TryLock takes the named lock without waiting. The lock belongs to a connection held until release
+// is called, and MySQL frees it by itself if this process dies. Returns acquired false when another
+// session holds the lock.
+func (r *advisoryLockRepository) TryLock(ctx context.Context, name string) (func(), bool, error) {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return nil, false, err
+	}
+	// GET_LOCK and RELEASE_LOCK must run on the same connection, not on whichever the pool hands out
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var result sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, 0)", name).Scan(&result); err != nil {
+		conn.Close()
+		return nil, false, err
+	}
+	if !result.Valid {
+		conn.Close()
+		return nil, false, fmt.Errorf("GET_LOCK(%q) failed", name)
+	}
+	if result.Int64 != 1 {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	release := func() {
+		var released sql.NullInt64
+		err := conn.QueryRowContext(context.Background(), "SELECT RELEASE_LOCK(?)", name).Scan(&released)
+		if err != nil || released.Int64 != 1 {
+			// Close only returns the session to the pool, still holding the lock; discard it so MySQL frees the lock
+			logger.Warnf("RELEASE_LOCK(%q) did not release the lock, discarding its connection: %v", name, err)
+			conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+		conn.Close()
+	}
+	return release, true, nil
+}
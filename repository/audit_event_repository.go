@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"time"
+
+	"dbfartifactapi/config"
+	"dbfartifactapi/models"
+	"dbfartifactapi/pkg/logger"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// auditChainHeadID is the primary key of the single audit_chain_head row.
+const auditChainHeadID = 1
+
+// AuditEventFilter selects audit events; zero values match everything.
+type AuditEventFilter struct {
+	Actor      string
+	EntityType string
+	EntityID   string
+	Action     string
+	Outcome    string
+	From       *time.Time
+	To         *time.Time
+	Offset     int
+	Limit      int
+}
+
+// AuditEventRepository provides append and read access to the audit trail.
+// There are deliberately no update or delete operations.
+type AuditEventRepository interface {
+	Migrate() error
+	LockHead(tx *gorm.DB) (*models.AuditChainHead, error)
+	Append(tx *gorm.DB, head *models.AuditChainHead, event *models.AuditEvent) error
+	List(tx *gorm.DB, filter AuditEventFilter) ([]models.AuditEvent, int64, error)
+	ListAfter(tx *gorm.DB, afterID uint, limit int) ([]models.AuditEvent, error)
+	GetHead(tx *gorm.DB) (*models.AuditChainHead, error)
+}
+
+type auditEventRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditEventRepository creates a new audit event repository instance.
+func NewAuditEventRepository() AuditEventRepository {
+	return &auditEventRepository{
+		db: config.DB,
+	}
+}
+
+// Migrate creates the audit tables and the chain head row.
+// Triggers rejecting UPDATE and DELETE are installed when the database user may create them;
+// without them tampering is still detected by the hash chain.
+func (r *auditEventRepository) Migrate() error {
+	if err := r.db.AutoMigrate(&models.AuditEvent{}, &models.AuditChainHead{}); err != nil {
+		return err
+	}
+	if err := r.db.Where(models.AuditChainHead{ID: auditChainHeadID}).
+		FirstOrCreate(&models.AuditChainHead{ID: auditChainHeadID}).Error; err != nil {
+		return err
+	}
+
+	for _, trigger := range []struct{ name, event string }{
+		{"audit_events_no_update", "UPDATE"},
+		{"audit_events_no_delete", "DELETE"},
+	} {
+		var count int64
+		if err := r.db.Raw("SELECT COUNT(*) FROM information_schema.TRIGGERS WHERE TRIGGER_SCHEMA = DATABASE() AND TRIGGER_NAME = ?",
+			trigger.name).Scan(&count).Error; err != nil || count > 0 {
+			continue
+		}
+		stmt := "CREATE TRIGGER " + trigger.name + " BEFORE " + trigger.event + " ON audit_events FOR EACH ROW " +
+			"SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_events is append-only'"
+		if err := r.db.Exec(stmt).Error; err != nil {
+			logger.Warnf("Could not install %s trigger, relying on hash chain only: %v", trigger.name, err)
+		}
+	}
+	return nil
+}
+
+// LockHead reads the chain head with SELECT ... FOR UPDATE; the lock is held until tx ends.
+func (r *auditEventRepository) LockHead(tx *gorm.DB) (*models.AuditChainHead, error) {
+	var head models.AuditChainHead
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", auditChainHeadID).First(&head).Error; err != nil {
+		return nil, err
+	}
+	return &head, nil
+}
+
+// Append inserts event and moves the locked chain head to it.
+func (r *auditEventRepository) Append(tx *gorm.DB, head *models.AuditChainHead, event *models.AuditEvent) error {
+	if err := tx.Create(event).Error; err != nil {
+		return err
+	}
+	head.LastEventID = event.ID
+	head.LastHash = event.Hash
+	return tx.Model(&models.AuditChainHead{}).Where("id = ?", head.ID).
+		Updates(map[string]interface{}{"last_event_id": head.LastEventID, "last_hash": head.LastHash}).Error
+}
+
+// List returns events matching filter, newest first, and the total number of matches.
+func (r *auditEventRepository) List(tx *gorm.DB, filter AuditEventFilter) ([]models.AuditEvent, int64, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	query := db.Model(&models.AuditEvent{})
+	if filter.Actor != "" {
+		query = query.Where("actor = ?", filter.Actor)
+	}
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.Outcome != "" {
+		query = query.Where("outcome = ?", filter.Outcome)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at <= ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var events []models.AuditEvent
+	if err := query.Order("id DESC").Offset(filter.Offset).Limit(filter.Limit).Find(&events).Error; err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
+
+// ListAfter returns up to limit events with id greater than afterID in chain order.
+func (r *auditEventRepository) ListAfter(tx *gorm.DB, afterID uint, limit int) ([]models.AuditEvent, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	var events []models.AuditEvent
+	if err := db.Where("id > ?", afterID).Order("id ASC").Limit(limit).Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (r *auditEventRepository) GetHead(tx *gorm.DB) (*models.AuditChainHead, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	var head models.AuditChainHead
+	if err := db.Where("id = ?", auditChainHeadID).First(&head).Error; err != nil {
+		return nil, err
+	}
+	return &head, nil
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"dbfartifactapi/config"
+	"dbfartifactapi/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ChangeRequestRepository provides data access operations for change requests and their approvals.
+type ChangeRequestRepository interface {
+	Migrate() error
+	Create(tx *gorm.DB, cr *models.ChangeRequest) error
+	Save(tx *gorm.DB, cr *models.ChangeRequest) error
+	GetByID(tx *gorm.DB, id uint) (*models.ChangeRequest, error)
+	LockByID(tx *gorm.DB, id uint) (*models.ChangeRequest, error)
+	List(tx *gorm.DB, status string, offset, limit int) ([]models.ChangeRequest, int64, error)
+	CreateApproval(tx *gorm.DB, approval *models.ChangeRequestApproval) error
+	ListApprovals(tx *gorm.DB, changeRequestID uint) ([]models.ChangeRequestApproval, error)
+}
+
+type changeRequestRepository struct {
+	db *gorm.DB
+}
+
+// NewChangeRequestRepository creates a new change request repository instance.
+func NewChangeRequestRepository() ChangeRequestRepository {
+	return &changeRequestRepository{
+		db: config.DB,
+	}
+}
+
+// Migrate creates or updates the change_requests and change_request_approvals table schemas.
+func (r *changeRequestRepository) Migrate() error {
+	return r.db.AutoMigrate(&models.ChangeRequest{}, &models.ChangeRequestApproval{})
+}
+
+func (r *changeRequestRepository) Create(tx *gorm.DB, cr *models.ChangeRequest) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	return db.Create(cr).Error
+}
+
+func (r *changeRequestRepository) Save(tx *gorm.DB, cr *models.ChangeRequest) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	return db.Save(cr).Error
+}
+
+func (r *changeRequestRepository) GetByID(tx *gorm.DB, id uint) (*models.ChangeRequest, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	var cr models.ChangeRequest
+	if err := db.First(&cr, id).Error; err != nil {
+		return nil, err
+	}
+	return &cr, nil
+}
+
+// LockByID returns a change request and locks it until tx ends, so two approvers
+// deciding at the same time see each other's decisions.
+func (r *changeRequestRepository) LockByID(tx *gorm.DB, id uint) (*models.ChangeRequest, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	var cr models.ChangeRequest
+	if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&cr, id).Error; err != nil {
+		return nil, err
+	}
+	return &cr, nil
+}
+
+// List returns change requests newest first without their payload, preview and result,
+// together with the total number of matching change requests. An empty status matches all.
+func (r *changeRequestRepository) List(tx *gorm.DB, status string, offset, limit int) ([]models.ChangeRequest, int64, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	query := db.Model(&models.ChangeRequest{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var requests []models.ChangeRequest
+	if err := query.Omit("payload", "preview", "result").Order("id DESC").
+		Offset(offset).Limit(limit).Find(&requests).Error; err != nil {
+		return nil, 0, err
+	}
+	return requests, total, nil
+}
+
+func (r *changeRequestRepository) CreateApproval(tx *gorm.DB, approval *models.ChangeRequestApproval) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	return db.Create(approval).Error
+}
+
+// ListApprovals returns the decisions on a change request in the order they were made.
+func (r *changeRequestRepository) ListApprovals(tx *gorm.DB, changeRequestID uint) ([]models.ChangeRequestApproval, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	var approvals []models.ChangeRequestApproval
+	if err := db.Where("change_request_id = ?", changeRequestID).Order("id").Find(&approvals).Error; err != nil {
+		return nil, err
+	}
+	return approvals, nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"fmt"
+
+	"dbfartifactapi/config"
+	"dbfartifactapi/models"
+
+	"gorm.io/gorm"
+)
+
+// credentialColumnSize is the password column width needed for envelope-encrypted values.
+const credentialColumnSize = 1024
+
+// credentialMigrationScript adds the columns CheckSchema looks for. The tables belong to DBF Web,
+// so the script is applied with its schema changes rather than by this service.
+const credentialMigrationScript = "scripts/migrations/dbfweb_credential_columns.sql"
+
+// StoredPassword is the stored password column of one cntmgt or dbactormgt row.
+type StoredPassword struct {
+	ID       uint
+	Password string
+}
+
+// CredentialRepository gives bulk access to the password columns of cntmgt and dbactormgt
+// for encrypting legacy values and key rotation. model is &models.CntMgt{} or &models.DBActorMgt{}.
+type CredentialRepository interface {
+	CheckSchema(encrypted bool) error
+	ListPasswords(tx *gorm.DB, model interface{}) ([]StoredPassword, error)
+	ReplacePassword(tx *gorm.DB, model interface{}, id uint, current, replacement string) (bool, error)
+}
+
+type credentialRepository struct {
+	db *gorm.DB
+}
+
+// NewCredentialRepository creates a new credential repository instance.
+func NewCredentialRepository() CredentialRepository {
+	return &credentialRepository{
+		db: config.DB,
+	}
+}
+
+// CheckSchema verifies that cntmgt has the secret_ref column and, when encrypted is true, that the
+// password columns are wide enough for encrypted values. It only reads the schema: cntmgt and
+// dbactormgt are DBF Web tables, changed by credentialMigrationScript.
+func (r *credentialRepository) CheckSchema(encrypted bool) error {
+	migrator := r.db.Migrator()
+	if !migrator.HasColumn(&models.CntMgt{}, "SecretRef") {
+		return fmt.Errorf("column cntmgt.secret_ref is missing, apply %s", credentialMigrationScript)
+	}
+	if !encrypted {
+		return nil
+	}
+
+	for _, model := range []interface{}{&models.CntMgt{}, &models.DBActorMgt{}} {
+		columns, err := migrator.ColumnTypes(model)
+		if err != nil {
+			return err
+		}
+		for _, column := range columns {
+			if column.Name() != "password" {
+				continue
+			}
+			// Text columns report no length and are wide enough already
+			if length, ok := column.Length(); ok && length > 0 && length < credentialColumnSize {
+				return fmt.Errorf("password column of %T holds %d characters, encrypted values need %d, apply %s",
+					model, length, credentialColumnSize, credentialMigrationScript)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *credentialRepository) ListPasswords(tx *gorm.DB, model interface{}) ([]StoredPassword, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	var rows []StoredPassword
+	if err := db.Model(model).Select("id, password").Where("password <> ''").Order("id").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// ReplacePassword sets the password of row id to replacement if it still holds current.
+// Returns false when the row changed in the meantime.
+func (r *credentialRepository) ReplacePassword(tx *gorm.DB, model interface{}, id uint, current, replacement string) (bool, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	result := db.Model(model).Where("id = ? AND password = ?", id, current).UpdateColumn("password", replacement)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
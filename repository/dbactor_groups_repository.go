@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"dbfartifactapi/config"
 	"dbfartifactapi/models"
 
@@ -16,9 +18,14 @@ type DBActorGroupsRepository interface {
 	GetByGroupID(tx *gorm.DB, groupID uint) ([]models.DBActorGroups, error)
 	GetActiveGroupsByActorID(tx *gorm.DB, actorID uint) ([]models.DBActorGroups, error)
 	GetActiveActorsByGroupID(tx *gorm.DB, groupID uint) ([]models.DBActorGroups, error)
+	GetActiveByCntMgt(tx *gorm.DB, cntMgtID uint) ([]models.DBActorGroups, error)
 	Update(tx *gorm.DB, actorGroup *models.DBActorGroups) error
 	Delete(tx *gorm.DB, id uint) error
 	DeactivateByActorIDAndGroupID(tx *gorm.DB, actorID, groupID uint) error
+	GetAppliedByGroupID(tx *gorm.DB, groupID uint) ([]models.DBActorGroups, error)
+	GetExpired(tx *gorm.DB, now time.Time) ([]models.DBActorGroups, error)
+	GetDue(tx *gorm.DB, now time.Time) ([]models.DBActorGroups, error)
+	SetActive(tx *gorm.DB, id uint, active bool) error
 }
 
 type dbActorGroupsRepository struct {
@@ -124,6 +131,23 @@ func (r *dbActorGroupsRepository) GetActiveActorsByGroupID(tx *gorm.DB, groupID
 	return actorGroups, nil
 }
 
+// GetActiveByCntMgt returns the memberships within their validity period of all actors of a connection.
+func (r *dbActorGroupsRepository) GetActiveByCntMgt(tx *gorm.DB, cntMgtID uint) ([]models.DBActorGroups, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	var actorGroups []models.DBActorGroups
+	if err := db.Joins("JOIN dbactormgt ON dbactormgt.id = dbactor_groups.actor_id").
+		Where("dbactormgt.cntid = ?", cntMgtID).
+		Where("dbactor_groups.valid_from <= NOW() AND (dbactor_groups.valid_until IS NULL OR dbactor_groups.valid_until >= NOW())").
+		Order("dbactor_groups.actor_id, dbactor_groups.group_id").
+		Find(&actorGroups).Error; err != nil {
+		return nil, err
+	}
+	return actorGroups, nil
+}
+
 func (r *dbActorGroupsRepository) Update(tx *gorm.DB, actorGroup *models.DBActorGroups) error {
 	db := tx
 	if db == nil {
@@ -148,3 +172,53 @@ func (r *dbActorGroupsRepository) DeactivateByActorIDAndGroupID(tx *gorm.DB, act
 	return db.Where("actor_id = ? AND group_id = ?", actorID, groupID).
 		Delete(&models.DBActorGroups{}).Error
 }
+
+// GetAppliedByGroupID returns the memberships of a group whose grants are in place (is_active),
+// regardless of their validity period.
+func (r *dbActorGroupsRepository) GetAppliedByGroupID(tx *gorm.DB, groupID uint) ([]models.DBActorGroups, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	var actorGroups []models.DBActorGroups
+	if err := db.Where("group_id = ? AND is_active = ?", groupID, true).Find(&actorGroups).Error; err != nil {
+		return nil, err
+	}
+	return actorGroups, nil
+}
+
+// GetExpired returns the applied memberships whose valid_until has passed.
+func (r *dbActorGroupsRepository) GetExpired(tx *gorm.DB, now time.Time) ([]models.DBActorGroups, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	var actorGroups []models.DBActorGroups
+	if err := db.Where("is_active = ? AND valid_until IS NOT NULL AND valid_until < ?", true, now).
+		Order("group_id, id").Find(&actorGroups).Error; err != nil {
+		return nil, err
+	}
+	return actorGroups, nil
+}
+
+// GetDue returns the memberships not applied yet whose validity period has started and not ended.
+func (r *dbActorGroupsRepository) GetDue(tx *gorm.DB, now time.Time) ([]models.DBActorGroups, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	var actorGroups []models.DBActorGroups
+	if err := db.Where("is_active = ? AND valid_from <= ? AND (valid_until IS NULL OR valid_until >= ?)", false, now, now).
+		Order("group_id, id").Find(&actorGroups).Error; err != nil {
+		return nil, err
+	}
+	return actorGroups, nil
+}
+
+func (r *dbActorGroupsRepository) SetActive(tx *gorm.DB, id uint, active bool) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	return db.Model(&models.DBActorGroups{}).Where("id = ?", id).Update("is_active", active).Error
+}
@@ -11,6 +11,7 @@ import (
 type DBGroupListPoliciesRepository interface {
 	GetAll(tx *gorm.DB) ([]models.DBGroupListPolicies, error)
 	GetByID(tx *gorm.DB, id uint) (*models.DBGroupListPolicies, error)
+	GetByIDs(tx *gorm.DB, ids []uint) ([]models.DBGroupListPolicies, error)
 	GetByCode(tx *gorm.DB, code string) (*models.DBGroupListPolicies, error)
 	GetActiveByDatabaseType(tx *gorm.DB, databaseTypeID uint) ([]models.DBGroupListPolicies, error)
 }
@@ -73,3 +74,19 @@ func (r *dbGroupListPoliciesRepository) GetActiveByDatabaseType(tx *gorm.DB, dat
 	}
 	return policies, nil
 }
+
+// GetByIDs returns the group policies with the given IDs; missing IDs are left out.
+func (r *dbGroupListPoliciesRepository) GetByIDs(tx *gorm.DB, ids []uint) ([]models.DBGroupListPolicies, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	var policies []models.DBGroupListPolicies
+	if err := db.Where("id IN ?", ids).Find(&policies).Error; err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
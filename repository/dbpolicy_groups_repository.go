@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"dbfartifactapi/config"
 	"dbfartifactapi/models"
 
@@ -15,9 +17,14 @@ type DBPolicyGroupsRepository interface {
 	GetByGroupID(tx *gorm.DB, groupID uint) ([]models.DBPolicyGroups, error)
 	GetByPolicyID(tx *gorm.DB, policyID uint) ([]models.DBPolicyGroups, error)
 	GetActivePoliciesByGroupID(tx *gorm.DB, groupID uint) ([]models.DBPolicyGroups, error)
+	GetActivePoliciesByGroupIDs(tx *gorm.DB, groupIDs []uint) ([]models.DBPolicyGroups, error)
 	Update(tx *gorm.DB, policyGroup *models.DBPolicyGroups) error
 	Delete(tx *gorm.DB, id uint) error
 	DeactivateByGroupIDAndPolicyID(tx *gorm.DB, groupID, policyID uint) error
+	GetAppliedByGroupID(tx *gorm.DB, groupID uint) ([]models.DBPolicyGroups, error)
+	GetExpired(tx *gorm.DB, now time.Time) ([]models.DBPolicyGroups, error)
+	GetDue(tx *gorm.DB, now time.Time) ([]models.DBPolicyGroups, error)
+	SetActive(tx *gorm.DB, id uint, active bool) error
 }
 
 type dbPolicyGroupsRepository struct {
@@ -105,6 +112,24 @@ func (r *dbPolicyGroupsRepository) GetActivePoliciesByGroupID(tx *gorm.DB, group
 	return policyGroups, nil
 }
 
+// GetActivePoliciesByGroupIDs returns the policy mappings within their validity period of several groups.
+func (r *dbPolicyGroupsRepository) GetActivePoliciesByGroupIDs(tx *gorm.DB, groupIDs []uint) ([]models.DBPolicyGroups, error) {
+	if len(groupIDs) == 0 {
+		return nil, nil
+	}
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	var policyGroups []models.DBPolicyGroups
+	if err := db.Where("group_id IN ?", groupIDs).
+		Where("valid_from <= NOW() AND (valid_until IS NULL OR valid_until >= NOW())").
+		Order("group_id, id").Find(&policyGroups).Error; err != nil {
+		return nil, err
+	}
+	return policyGroups, nil
+}
+
 func (r *dbPolicyGroupsRepository) Update(tx *gorm.DB, policyGroup *models.DBPolicyGroups) error {
 	db := tx
 	if db == nil {
@@ -129,3 +154,53 @@ func (r *dbPolicyGroupsRepository) DeactivateByGroupIDAndPolicyID(tx *gorm.DB, g
 	return db.Where("group_id = ? AND dbgroup_listpolicies_id = ?", groupID, policyID).
 		Delete(&models.DBPolicyGroups{}).Error
 }
+
+// GetAppliedByGroupID returns the policy mappings of a group whose grants are in place (is_active),
+// regardless of their validity period.
+func (r *dbPolicyGroupsRepository) GetAppliedByGroupID(tx *gorm.DB, groupID uint) ([]models.DBPolicyGroups, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	var policyGroups []models.DBPolicyGroups
+	if err := db.Where("group_id = ? AND is_active = ?", groupID, true).Find(&policyGroups).Error; err != nil {
+		return nil, err
+	}
+	return policyGroups, nil
+}
+
+// GetExpired returns the applied policy mappings whose valid_until has passed.
+func (r *dbPolicyGroupsRepository) GetExpired(tx *gorm.DB, now time.Time) ([]models.DBPolicyGroups, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	var policyGroups []models.DBPolicyGroups
+	if err := db.Where("is_active = ? AND valid_until IS NOT NULL AND valid_until < ?", true, now).
+		Order("group_id, id").Find(&policyGroups).Error; err != nil {
+		return nil, err
+	}
+	return policyGroups, nil
+}
+
+// GetDue returns the policy mappings not applied yet whose validity period has started and not ended.
+func (r *dbPolicyGroupsRepository) GetDue(tx *gorm.DB, now time.Time) ([]models.DBPolicyGroups, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	var policyGroups []models.DBPolicyGroups
+	if err := db.Where("is_active = ? AND valid_from <= ? AND (valid_until IS NULL OR valid_until >= ?)", false, now, now).
+		Order("group_id, id").Find(&policyGroups).Error; err != nil {
+		return nil, err
+	}
+	return policyGroups, nil
+}
+
+func (r *dbPolicyGroupsRepository) SetActive(tx *gorm.DB, id uint, active bool) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	return db.Model(&models.DBPolicyGroups{}).Where("id = ?", id).Update("is_active", active).Error
+}
@@ -12,7 +12,9 @@ import (
 type DBPolicyRepository interface {
 	GetByDbMgt(tx *gorm.DB, dbmgt uint) ([]models.DBPolicy, error)
 	GetByCntMgt(tx *gorm.DB, cntmgt uint) ([]models.DBPolicy, error)
+	GetAllByCntMgt(tx *gorm.DB, cntMgtID uint) ([]models.DBPolicy, error)
 	GetById(tx *gorm.DB, id uint) (*models.DBPolicy, error)
+	GetByIDs(tx *gorm.DB, ids []uint) ([]models.DBPolicy, error)
 	GetPoliciesByActorAndScope(tx *gorm.DB, cntMgtID, dbMgtID, actorMgtID uint) ([]models.DBPolicy, error)
 	BulkDelete(tx *gorm.DB, policyIDs []uint) error
 	BulkCreate(tx *gorm.DB, policies []models.DBPolicy) error
@@ -58,6 +60,23 @@ func (r *dbPolicyRepository) GetByCntMgt(tx *gorm.DB, cntmgt uint) ([]models.DBP
 	return dbPolicies, nil
 }
 
+// GetAllByCntMgt retrieves every policy of a connection, including policies stored with only
+// their database set and connection-wide policies without one. Ordered by ID for stable revisions.
+func (r *dbPolicyRepository) GetAllByCntMgt(tx *gorm.DB, cntMgtID uint) ([]models.DBPolicy, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	var dbPolicies []models.DBPolicy
+	dbMgtIDs := db.Model(models.DBMgt{}).Select("id").Where("cnt_id = ?", cntMgtID)
+	if err := db.Model(models.DBPolicy{}).
+		Where("cnt_id = ? OR (dbmgt_id > 0 AND dbmgt_id IN (?))", cntMgtID, dbMgtIDs).
+		Order("id").Find(&dbPolicies).Error; err != nil {
+		return nil, err
+	}
+	return dbPolicies, nil
+}
+
 func (r *dbPolicyRepository) GetById(tx *gorm.DB, id uint) (*models.DBPolicy, error) {
 	db := tx
 	if db == nil {
@@ -70,6 +89,21 @@ func (r *dbPolicyRepository) GetById(tx *gorm.DB, id uint) (*models.DBPolicy, er
 	return &dbPolicy, nil
 }
 
+func (r *dbPolicyRepository) GetByIDs(tx *gorm.DB, ids []uint) ([]models.DBPolicy, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	var dbPolicies []models.DBPolicy
+	if len(ids) == 0 {
+		return dbPolicies, nil
+	}
+	if err := db.Model(models.DBPolicy{}).Where("id IN ?", ids).Order("id").Find(&dbPolicies).Error; err != nil {
+		return nil, err
+	}
+	return dbPolicies, nil
+}
+
 // GetPoliciesByActorAndScope retrieves all policies for a specific actor within a database scope.
 // Used for calculating policy diffs during bulk updates.
 func (r *dbPolicyRepository) GetPoliciesByActorAndScope(tx *gorm.DB, cntMgtID, dbMgtID, actorMgtID uint) ([]models.DBPolicy, error) {
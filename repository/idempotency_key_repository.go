@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"time"
+
+	"dbfartifactapi/config"
+	"dbfartifactapi/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IdempotencyKeyRepository provides data access operations for stored idempotency keys.
+type IdempotencyKeyRepository interface {
+	Migrate() error
+	Create(tx *gorm.DB, record *models.IdempotencyKey) (bool, error)
+	GetByKeyHash(tx *gorm.DB, keyHash string) (*models.IdempotencyKey, error)
+	Reclaim(tx *gorm.DB, record *models.IdempotencyKey, now time.Time) (bool, error)
+	Update(tx *gorm.DB, record *models.IdempotencyKey) error
+	Delete(tx *gorm.DB, keyHash string) error
+	DeleteExpired(tx *gorm.DB, now time.Time) (int64, error)
+}
+
+type idempotencyKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewIdempotencyKeyRepository creates a new idempotency key repository instance.
+func NewIdempotencyKeyRepository() IdempotencyKeyRepository {
+	return &idempotencyKeyRepository{
+		db: config.DB,
+	}
+}
+
+// Migrate creates or updates the idempotency_keys table schema.
+func (r *idempotencyKeyRepository) Migrate() error {
+	return r.db.AutoMigrate(&models.IdempotencyKey{})
+}
+
+// Create inserts record unless its key already exists.
+// Returns false without error when another request holds the key, so concurrent duplicates are detected atomically.
+func (r *idempotencyKeyRepository) Create(tx *gorm.DB, record *models.IdempotencyKey) (bool, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *idempotencyKeyRepository) GetByKeyHash(tx *gorm.DB, keyHash string) (*models.IdempotencyKey, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	var record models.IdempotencyKey
+	if err := db.Where("key_hash = ?", keyHash).First(&record).Error; err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Reclaim takes over a key whose stored response expired or whose request was abandoned in progress.
+// Returns false when the key is still live, e.g. another retry reclaimed it first.
+func (r *idempotencyKeyRepository) Reclaim(tx *gorm.DB, record *models.IdempotencyKey, now time.Time) (bool, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	result := db.Model(&models.IdempotencyKey{}).
+		Where("key_hash = ?", record.KeyHash).
+		Where("expires_at < ? OR (status = ? AND locked_until < ?)", now, models.IdempotencyStatusInProgress, now).
+		Updates(map[string]interface{}{
+			"actor":           record.Actor,
+			"method":          record.Method,
+			"path":            record.Path,
+			"fingerprint":     record.Fingerprint,
+			"status":          record.Status,
+			"response_status": 0,
+			"content_type":    "",
+			"response_body":   "",
+			"locked_until":    record.LockedUntil,
+			"expires_at":      record.ExpiresAt,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Update stores the final state and response of a key.
+func (r *idempotencyKeyRepository) Update(tx *gorm.DB, record *models.IdempotencyKey) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	if err := db.Save(record).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *idempotencyKeyRepository) Delete(tx *gorm.DB, keyHash string) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	if err := db.Where("key_hash = ?", keyHash).Delete(&models.IdempotencyKey{}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteExpired removes keys whose stored response is past its TTL.
+func (r *idempotencyKeyRepository) DeleteExpired(tx *gorm.DB, now time.Time) (int64, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	result := db.Where("expires_at < ?", now).Delete(&models.IdempotencyKey{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
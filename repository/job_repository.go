@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"time"
+
+	"dbfartifactapi/config"
+	"dbfartifactapi/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// JobRepository provides data access operations for persisted background job records.
+type JobRepository interface {
+	Migrate() error
+	Save(tx *gorm.DB, job *models.Job) error
+	GetByID(tx *gorm.DB, jobID string) (*models.Job, error)
+	GetByStatuses(tx *gorm.DB, statuses []string) ([]models.Job, error)
+	Delete(tx *gorm.DB, jobID string) error
+	MarkCallbackCommitted(tx *gorm.DB, jobID string) error
+}
+
+type jobRepository struct {
+	db *gorm.DB
+}
+
+// NewJobRepository creates a new job repository instance.
+func NewJobRepository() JobRepository {
+	return &jobRepository{
+		db: config.DB,
+	}
+}
+
+// Migrate creates or updates the jobs table schema.
+// Jobs table is owned by this service, unlike catalog tables managed by DBF Web.
+func (r *jobRepository) Migrate() error {
+	return r.db.AutoMigrate(&models.Job{})
+}
+
+// jobStateColumns are the columns a state save overwrites on an existing record.
+// created_at keeps the first insert time and callback_committed is owned by MarkCallbackCommitted.
+var jobStateColumns = []string{
+	"kind", "dbmgt_id", "client_id", "os_type", "status", "progress", "message",
+	"completed", "failed", "total_queries", "error", "results", "context_data",
+	"processed_via_notification", "trace_parent", "start_time", "end_time", "updated_at",
+}
+
+// Save upserts a job record by job_id.
+// Called on every job state transition, so SQL logging is silenced to reduce log noise.
+// Only jobStateColumns are updated on conflict, so created_at and callback_committed are never overwritten.
+func (r *jobRepository) Save(tx *gorm.DB, job *models.Job) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	if err := saveJob(db, job).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// saveJob runs the job upsert of Save on db.
+func saveJob(db *gorm.DB, job *models.Job) *gorm.DB {
+	return db.Session(&gorm.Session{Logger: db.Logger.LogMode(gormlogger.Silent)}).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "job_id"}},
+			DoUpdates: clause.AssignmentColumns(jobStateColumns),
+		}).Create(job)
+}
+
+func (r *jobRepository) GetByID(tx *gorm.DB, jobID string) (*models.Job, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	var job models.Job
+	if err := db.Model(models.Job{}).Where("job_id = ?", jobID).First(&job).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// GetByStatuses retrieves all jobs whose status is in the given list.
+// Used at startup to rehydrate jobs that were still in flight when the service stopped.
+func (r *jobRepository) GetByStatuses(tx *gorm.DB, statuses []string) ([]models.Job, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	var jobs []models.Job
+	if len(statuses) == 0 {
+		return jobs, nil
+	}
+	if err := db.Model(models.Job{}).Where("status IN ?", statuses).
+		Order("start_time ASC").Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (r *jobRepository) Delete(tx *gorm.DB, jobID string) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	if err := db.Where("job_id = ?", jobID).Delete(&models.Job{}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// MarkCallbackCommitted flags that the completion callback of jobID committed its changes.
+// Meant to run in the callback's own transaction so the flag commits or rolls back with them.
+// Job state is written asynchronously, so a minimal record is created when none exists yet.
+func (r *jobRepository) MarkCallbackCommitted(tx *gorm.DB, jobID string) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	record := &models.Job{JobID: jobID, Status: "processing", CallbackCommitted: true, StartTime: time.Now()}
+	if err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "job_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"callback_committed": true}),
+	}).Create(record).Error; err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"dbfartifactapi/models"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// dryRunDB returns a MySQL session that builds statements without a server
+func dryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(mysql.New(mysql.Config{DSN: "user:pass@tcp(127.0.0.1:1)/dbf", SkipInitializeWithVersion: true}),
+		&gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatalf("Failed to open dry-run session: %v", err)
+	}
+	return db
+}
+
+// TestJobRepository_SaveKeepsCreatedAt tests that state saves never overwrite created_at or callback_committed
+func TestJobRepository_SaveKeepsCreatedAt(t *testing.T) {
+	db := dryRunDB(t)
+
+	sql := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return saveJob(tx, &models.Job{JobID: "job_1", Status: "running", StartTime: time.Now()})
+	})
+
+	_, update, found := strings.Cut(sql, "ON DUPLICATE KEY UPDATE")
+	if !found {
+		t.Fatalf("Save() SQL = %s, want an upsert", sql)
+	}
+	for _, column := range []string{"created_at", "callback_committed"} {
+		if strings.Contains(update, "`"+column+"`") {
+			t.Errorf("Save() updates %s on conflict: %s", column, update)
+		}
+	}
+	if !strings.Contains(update, "`status`=VALUES(`status`)") {
+		t.Errorf("Save() does not update status on conflict: %s", update)
+	}
+	if strings.Contains(sql, "0000-00-00") {
+		t.Errorf("Save() SQL writes a zero date: %s", sql)
+	}
+}
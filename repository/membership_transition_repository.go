@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"dbfartifactapi/config"
+	"dbfartifactapi/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MembershipTransitionRepository provides data access operations for membership transition outcomes.
+type MembershipTransitionRepository interface {
+	Migrate() error
+	Record(tx *gorm.DB, transition *models.MembershipTransition) error
+	List(tx *gorm.DB, status string, offset, limit int) ([]models.MembershipTransition, int64, error)
+}
+
+type membershipTransitionRepository struct {
+	db *gorm.DB
+}
+
+// NewMembershipTransitionRepository creates a new membership transition repository instance.
+func NewMembershipTransitionRepository() MembershipTransitionRepository {
+	return &membershipTransitionRepository{
+		db: config.DB,
+	}
+}
+
+// Migrate creates or updates the membership_transitions table schema.
+func (r *membershipTransitionRepository) Migrate() error {
+	return r.db.AutoMigrate(&models.MembershipTransition{})
+}
+
+// Record stores the outcome of a transition, replacing the outcome of an earlier attempt at
+// the same transition and counting the attempt.
+func (r *membershipTransitionRepository) Record(tx *gorm.DB, transition *models.MembershipTransition) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	transition.Attempts = 1
+	return db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "kind"}, {Name: "membership_id"}, {Name: "action"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"status":     transition.Status,
+			"executions": transition.Executions,
+			"error":      transition.Error,
+			"attempts":   gorm.Expr("attempts + 1"),
+			"updated_at": gorm.Expr("VALUES(updated_at)"),
+		}),
+	}).Create(transition).Error
+}
+
+// List returns transitions most recently attempted first, together with the total number of
+// matching transitions. An empty status matches all.
+func (r *membershipTransitionRepository) List(tx *gorm.DB, status string, offset, limit int) ([]models.MembershipTransition, int64, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	query := db.Model(&models.MembershipTransition{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var transitions []models.MembershipTransition
+	if err := query.Order("updated_at DESC, id DESC").Offset(offset).Limit(limit).Find(&transitions).Error; err != nil {
+		return nil, 0, err
+	}
+	return transitions, total, nil
+}
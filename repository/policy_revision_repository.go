@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"dbfartifactapi/config"
+	"dbfartifactapi/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PolicyRevisionRepository provides data access operations for policy revisions.
+type PolicyRevisionRepository interface {
+	Migrate() error
+	Create(tx *gorm.DB, revision *models.PolicyRevision) error
+	LockLatest(tx *gorm.DB, cntMgtID uint) (*models.PolicyRevision, error)
+	GetByRevision(tx *gorm.DB, cntMgtID uint, revision int) (*models.PolicyRevision, error)
+	GetPrevious(tx *gorm.DB, cntMgtID uint, revision int) (*models.PolicyRevision, error)
+	List(tx *gorm.DB, cntMgtID uint, offset, limit int) ([]models.PolicyRevision, int64, error)
+}
+
+type policyRevisionRepository struct {
+	db *gorm.DB
+}
+
+// NewPolicyRevisionRepository creates a new policy revision repository instance.
+func NewPolicyRevisionRepository() PolicyRevisionRepository {
+	return &policyRevisionRepository{
+		db: config.DB,
+	}
+}
+
+// Migrate creates or updates the policy_revisions table schema.
+// Revisions are owned by this service, unlike the dbpolicy table managed by DBF Web.
+func (r *policyRevisionRepository) Migrate() error {
+	return r.db.AutoMigrate(&models.PolicyRevision{})
+}
+
+func (r *policyRevisionRepository) Create(tx *gorm.DB, revision *models.PolicyRevision) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	return db.Create(revision).Error
+}
+
+// LockLatest returns the newest revision of a connection and locks it until tx ends,
+// so concurrent changes to the same connection get consecutive revision numbers.
+// Returns gorm.ErrRecordNotFound when the connection has no revisions yet.
+func (r *policyRevisionRepository) LockLatest(tx *gorm.DB, cntMgtID uint) (*models.PolicyRevision, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	var revision models.PolicyRevision
+	if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("cnt_id = ?", cntMgtID).Order("revision DESC").First(&revision).Error; err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+func (r *policyRevisionRepository) GetByRevision(tx *gorm.DB, cntMgtID uint, revision int) (*models.PolicyRevision, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	var result models.PolicyRevision
+	if err := db.Where("cnt_id = ? AND revision = ?", cntMgtID, revision).First(&result).Error; err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetPrevious returns the newest revision of a connection older than the given revision.
+func (r *policyRevisionRepository) GetPrevious(tx *gorm.DB, cntMgtID uint, revision int) (*models.PolicyRevision, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	var result models.PolicyRevision
+	if err := db.Where("cnt_id = ? AND revision < ?", cntMgtID, revision).
+		Order("revision DESC").First(&result).Error; err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// List returns revisions of a connection newest first without their changes and policy sets,
+// together with the total number of revisions.
+func (r *policyRevisionRepository) List(tx *gorm.DB, cntMgtID uint, offset, limit int) ([]models.PolicyRevision, int64, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	query := db.Model(&models.PolicyRevision{}).Where("cnt_id = ?", cntMgtID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var revisions []models.PolicyRevision
+	if err := query.Omit("changes", "policies").Order("revision DESC").
+		Offset(offset).Limit(limit).Find(&revisions).Error; err != nil {
+		return nil, 0, err
+	}
+	return revisions, total, nil
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"dbfartifactapi/config"
+	"dbfartifactapi/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PrivilegeSnapshotRepository provides data access operations for privilege discovery snapshots.
+type PrivilegeSnapshotRepository interface {
+	Migrate() error
+	Create(tx *gorm.DB, snapshot *models.PrivilegeSnapshot) (bool, error)
+	GetNextVersion(tx *gorm.DB, cntMgtID uint) (int, error)
+	GetByVersion(tx *gorm.DB, cntMgtID uint, version int) (*models.PrivilegeSnapshot, error)
+	GetLatest(tx *gorm.DB, cntMgtID uint) (*models.PrivilegeSnapshot, error)
+	GetPrevious(tx *gorm.DB, cntMgtID uint, version int) (*models.PrivilegeSnapshot, error)
+}
+
+type privilegeSnapshotRepository struct {
+	db *gorm.DB
+}
+
+// NewPrivilegeSnapshotRepository creates a new privilege snapshot repository instance.
+func NewPrivilegeSnapshotRepository() PrivilegeSnapshotRepository {
+	return &privilegeSnapshotRepository{
+		db: config.DB,
+	}
+}
+
+// Migrate creates or updates the privilege_snapshots table schema.
+// Snapshots are owned by this service, unlike catalog tables managed by DBF Web.
+func (r *privilegeSnapshotRepository) Migrate() error {
+	return r.db.AutoMigrate(&models.PrivilegeSnapshot{})
+}
+
+// Create inserts snapshot unless its connection already has a snapshot with the same version.
+// Returns false without error when a concurrent discovery run took the version first; an insert
+// racing an uncommitted one waits for it to commit or roll back.
+func (r *privilegeSnapshotRepository) Create(tx *gorm.DB, snapshot *models.PrivilegeSnapshot) (bool, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(snapshot)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// GetNextVersion returns the version number for the next snapshot of a connection, starting at 1.
+// Pass a nil tx to see versions committed after the caller's transaction started.
+func (r *privilegeSnapshotRepository) GetNextVersion(tx *gorm.DB, cntMgtID uint) (int, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	var maxVersion int
+	if err := db.Model(models.PrivilegeSnapshot{}).Where("cnt_id = ?", cntMgtID).
+		Select("COALESCE(MAX(version), 0)").Scan(&maxVersion).Error; err != nil {
+		return 0, err
+	}
+	return maxVersion + 1, nil
+}
+
+func (r *privilegeSnapshotRepository) GetByVersion(tx *gorm.DB, cntMgtID uint, version int) (*models.PrivilegeSnapshot, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	var snapshot models.PrivilegeSnapshot
+	if err := db.Where("cnt_id = ? AND version = ?", cntMgtID, version).First(&snapshot).Error; err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+func (r *privilegeSnapshotRepository) GetLatest(tx *gorm.DB, cntMgtID uint) (*models.PrivilegeSnapshot, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	var snapshot models.PrivilegeSnapshot
+	if err := db.Where("cnt_id = ?", cntMgtID).Order("version DESC").First(&snapshot).Error; err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// GetPrevious returns the newest snapshot of a connection older than the given version.
+func (r *privilegeSnapshotRepository) GetPrevious(tx *gorm.DB, cntMgtID uint, version int) (*models.PrivilegeSnapshot, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	var snapshot models.PrivilegeSnapshot
+	if err := db.Where("cnt_id = ? AND version < ?", cntMgtID, version).
+		Order("version DESC").First(&snapshot).Error; err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
@@ -0,0 +1,38 @@
+-- Schema changes the DBF Artifact API needs in the DBF Web database (MySQL).
+-- cntmgt and dbactormgt are owned by DBF Web; the API only checks these changes on startup and
+-- never alters the tables itself. Apply once, together with the DBF Web release that reads
+-- encrypted passwords, before starting an API version with credential encryption support.
+
+-- Secret provider reference (file:, env:, vault:) resolved instead of the password
+ALTER TABLE cntmgt ADD COLUMN secret_ref VARCHAR(512) NULL;
+
+-- Envelope-encrypted passwords (enc:v1:...) need up to 1024 characters. Only required when
+-- CREDENTIAL_KEYS or CREDENTIAL_KEY_FILE is configured. MODIFY COLUMN replaces the whole column
+-- definition, so each statement is built from information_schema to keep the NULL/NOT NULL,
+-- default, character set, collation and comment of the existing column; only the length changes.
+-- Check the result with SHOW CREATE TABLE cntmgt and SHOW CREATE TABLE dbactormgt.
+SET @widen_password = NULL;
+SELECT CONCAT('ALTER TABLE `', TABLE_NAME, '` MODIFY COLUMN `password` VARCHAR(1024)',
+              ' CHARACTER SET ', CHARACTER_SET_NAME, ' COLLATE ', COLLATION_NAME,
+              IF(IS_NULLABLE = 'NO', ' NOT NULL', ' NULL'),
+              IF(COLUMN_DEFAULT IS NULL, '', CONCAT(' DEFAULT ', QUOTE(COLUMN_DEFAULT))),
+              IF(COLUMN_COMMENT = '', '', CONCAT(' COMMENT ', QUOTE(COLUMN_COMMENT))))
+  INTO @widen_password
+  FROM information_schema.COLUMNS
+ WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'cntmgt' AND COLUMN_NAME = 'password';
+PREPARE widen_password FROM @widen_password;
+EXECUTE widen_password;
+DEALLOCATE PREPARE widen_password;
+
+SET @widen_password = NULL;
+SELECT CONCAT('ALTER TABLE `', TABLE_NAME, '` MODIFY COLUMN `password` VARCHAR(1024)',
+              ' CHARACTER SET ', CHARACTER_SET_NAME, ' COLLATE ', COLLATION_NAME,
+              IF(IS_NULLABLE = 'NO', ' NOT NULL', ' NULL'),
+              IF(COLUMN_DEFAULT IS NULL, '', CONCAT(' DEFAULT ', QUOTE(COLUMN_DEFAULT))),
+              IF(COLUMN_COMMENT = '', '', CONCAT(' COMMENT ', QUOTE(COLUMN_COMMENT))))
+  INTO @widen_password
+  FROM information_schema.COLUMNS
+ WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'dbactormgt' AND COLUMN_NAME = 'password';
+PREPARE widen_password FROM @widen_password;
+EXECUTE widen_password;
+DEALLOCATE PREPARE widen_password;
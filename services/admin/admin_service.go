@@ -0,0 +1,56 @@
+package admin
+
+import (
+	"fmt"
+
+	"dbfartifactapi/config"
+	"dbfartifactapi/pkg/logger"
+	"dbfartifactapi/services/dto"
+)
+
+// AdminService changes settings of the running process.
+type AdminService interface {
+	// GetLogLevel returns the current and configured log level.
+	GetLogLevel() *dto.LogLevelResponse
+	// SetLogLevel changes the log level until the next restart or a reload that changes LOG_LEVEL.
+	SetLogLevel(level string) (*dto.LogLevelResponse, error)
+	// ReloadConfig re-reads .env and the environment and applies the reloadable settings.
+	ReloadConfig() (*dto.ConfigReloadResponse, error)
+}
+
+type adminService struct{}
+
+// NewAdminService creates a new admin service instance.
+func NewAdminService() AdminService {
+	return &adminService{}
+}
+
+// GetLogLevel implements AdminService.
+func (s *adminService) GetLogLevel() *dto.LogLevelResponse {
+	return &dto.LogLevelResponse{
+		Level:           logger.GetLevel().String(),
+		ConfiguredLevel: config.Current().LogLevel,
+	}
+}
+
+// SetLogLevel implements AdminService.
+func (s *adminService) SetLogLevel(level string) (*dto.LogLevelResponse, error) {
+	parsed, ok := logger.LookupLogLevel(level)
+	if !ok {
+		return nil, fmt.Errorf("invalid log level %q, must be one of DEBUG, INFO, WARN, ERROR, FATAL", level)
+	}
+	previous := logger.GetLevel()
+	logger.SetLevel(parsed)
+	// Logged at WARN so the change is visible whatever the old and new levels are
+	logger.Warnf("Log level changed from %s to %s", previous, parsed)
+	return s.GetLogLevel(), nil
+}
+
+// ReloadConfig implements AdminService.
+func (s *adminService) ReloadConfig() (*dto.ConfigReloadResponse, error) {
+	result, err := config.Reload()
+	if err != nil {
+		return nil, err
+	}
+	return &dto.ConfigReloadResponse{Applied: result.Applied, RestartRequired: result.RestartRequired}, nil
+}
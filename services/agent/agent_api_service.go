@@ -7,13 +7,17 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"dbfartifactapi/config"
 	"dbfartifactapi/pkg/logger"
+	"dbfartifactapi/pkg/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // AgentDownloadResponse represents response from file download operation via dbfAgentAPI
@@ -36,12 +40,146 @@ type AgentAPIResponse struct {
 	Message     string `json:"message"`      // Additional message (e.g., error details)
 }
 
-// ExecuteSqlAgentAPI executes SQL commands via dbfAgentAPI with retry mechanism.
+// AgentExecutor runs dbfsqlexecute / sqldetector commands on remote agents.
+// Services receive it by injection so the transport can be swapped (dbfAgentAPI binary, HTTP gateway)
+// and stubbed in tests.
+type AgentExecutor interface {
+	// ExecuteSql runs hex-encoded JSON actions: execute, download, policycompliance, os_execute, upload, filedownload.
+	// Option parameter is passed directly to the binary (e.g., "--background" for os_execute).
+	ExecuteSql(agentID, osType, action, hexEncodedJSON, option string, requiredStdout bool) (string, error)
+
+	// ExecuteSimpleCommand runs plain-value actions: checkstatus, getresults, listjobs, cleanup, info, cancel.
+	ExecuteSimpleCommand(agentID, osType, action, value, option string, requiredStdout bool) (string, error)
+
+	// DownloadFile copies a file from agent to local storage and returns its metadata.
+	DownloadFile(agentID, remotePath, osType string) (*AgentDownloadResponse, error)
+
+	// ExecuteConnectionTest runs database connection test via sqldetector on the agent.
+	ExecuteConnectionTest(clientID string, params ConnectionTestAgentParams, osType string) (*AgentAPIResponse, error)
+}
+
+// agentTransport performs a single round trip to an agent. Retries are handled by agentExecutor.
+type agentTransport interface {
+	// runCommand executes command on agent and returns raw dbfAgentAPI JSON response
+	runCommand(ctx context.Context, agentID, command string) ([]byte, error)
+	// getFile copies remotePath on agent to localPath
+	getFile(ctx context.Context, agentID, remotePath, localPath string) error
+}
+
+// agentExecutor implements AgentExecutor on top of a transport:
+// builds dbfsqlexecute command lines, validates responses and retries transient failures.
+type agentExecutor struct {
+	transport agentTransport
+	// ctx carries the caller's span; set only on copies returned by WithContext
+	ctx context.Context
+}
+
+// WithContext returns executor bound to ctx, so its agent calls are traced as children of the span in ctx
+// and the trace context is passed on to the agent. Executors without tracing support are returned unchanged.
+// The interface methods take no context; binding keeps existing callers and test doubles working.
+func WithContext(ctx context.Context, executor AgentExecutor) AgentExecutor {
+	e, ok := executor.(*agentExecutor)
+	if !ok || ctx == nil {
+		return executor
+	}
+	bound := *e
+	bound.ctx = ctx
+	return &bound
+}
+
+// startSpan starts the span of one agent call under the bound context.
+func (e *agentExecutor) startSpan(name, agentID string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	parent := e.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	return tracing.Start(parent, name, append(attrs, attribute.String("agent.id", agentID))...)
+}
+
+// attemptContext bounds one agent round trip by AGENT_EXECUTION_TIMEOUT.
+// The caller's cancellation is not inherited: agent commands are not interrupted when a request ends.
+func attemptContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), config.Current().AgentExecutionTimeout)
+}
+
+var (
+	defaultExecutor   AgentExecutor
+	defaultExecutorMu sync.RWMutex
+)
+
+// NewAgentExecutorFromConfig creates the executor selected by AGENT_TRANSPORT.
+func NewAgentExecutorFromConfig() (AgentExecutor, error) {
+	switch config.Cfg.AgentTransport {
+	case "", "exec":
+		return NewExecAgentExecutor(config.Cfg.AgentAPIPath), nil
+	case "http":
+		if config.Cfg.AgentGatewayURL == "" {
+			return nil, fmt.Errorf("AGENT_GATEWAY_URL is required for http agent transport")
+		}
+		return NewHTTPAgentExecutor(config.Cfg.AgentGatewayURL, config.Cfg.AgentGatewayToken), nil
+	default:
+		return nil, fmt.Errorf("unknown agent transport: %s (must be one of: exec, http)", config.Cfg.AgentTransport)
+	}
+}
+
+// SetDefaultExecutor sets executor returned by DefaultExecutor. Called once at startup.
+func SetDefaultExecutor(executor AgentExecutor) {
+	defaultExecutorMu.Lock()
+	defer defaultExecutorMu.Unlock()
+	defaultExecutor = executor
+}
+
+// DefaultExecutor returns the process-wide agent executor injected into services.
+// Falls back to the dbfAgentAPI binary when none was set.
+func DefaultExecutor() AgentExecutor {
+	defaultExecutorMu.RLock()
+	executor := defaultExecutor
+	defaultExecutorMu.RUnlock()
+
+	if executor != nil {
+		return executor
+	}
+
+	defaultExecutorMu.Lock()
+	defer defaultExecutorMu.Unlock()
+	if defaultExecutor == nil {
+		defaultExecutor = NewExecAgentExecutor(config.Cfg.AgentAPIPath)
+	}
+	return defaultExecutor
+}
+
+// retryDelay returns exponential backoff before the given attempt.
+// Uses lookup table to avoid gosec G115 false positive.
+func retryDelay(attempt int) time.Duration {
+	delays := []time.Duration{2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 32 * time.Second}
+	delayIndex := attempt - 2
+	if delayIndex >= len(delays) {
+		delayIndex = len(delays) - 1
+	}
+	return delays[delayIndex]
+}
+
+// dbfsqlexecutePath returns dbfsqlexecute location on the agent host
+func dbfsqlexecutePath(osType string) (string, error) {
+	switch strings.ToLower(osType) {
+	case "linux":
+		return "/etc/v2/dbf/bin/dbfsqlexecute", nil
+	case "windows":
+		return "C:/PROGRA~1/V2/DBF/bin/dbfsqlexecute", nil
+	default:
+		logger.Errorf("Unknown OS type: %s", osType)
+		return "", fmt.Errorf("unknown os_type: %s", osType)
+	}
+}
+
+// ExecuteSql executes SQL commands via agent with retry mechanism.
 // Replaces executeSqlVeloArtifact for direct agent communication without Velociraptor artifacts.
 // Supports execute, download, policycompliance, and os_execute actions for dbfsqlexecute binary.
-// Option parameter is passed directly to the binary (e.g., "--background" for os_execute).
-func ExecuteSqlAgentAPI(agentID, osType, action, hexEncodedJSON, option string, requiredStdout bool) (string, error) {
-	maxRetries := config.Cfg.AgentMaxRetries
+func (e *agentExecutor) ExecuteSql(agentID, osType, action, hexEncodedJSON, option string, requiredStdout bool) (_ string, err error) {
+	maxRetries := config.Current().AgentMaxRetries
+
+	ctx, span := e.startSpan("agent."+action, agentID, attribute.String("agent.action", action))
+	defer func() { tracing.End(span, err) }()
 
 	logger.Debugf("Starting dbfAgentAPI SQL execution - agentID: %s, osType: %s, action: %s, maxRetries: %d",
 		agentID, osType, action, maxRetries)
@@ -59,16 +197,9 @@ func ExecuteSqlAgentAPI(agentID, osType, action, hexEncodedJSON, option string,
 		return "", fmt.Errorf("invalid SQL action: %s (must be one of: execute, download, policycompliance, os_execute, upload, filedownload)", action)
 	}
 
-	// Build executable path based on OS type
-	var executablePath string
-	switch strings.ToLower(osType) {
-	case "linux":
-		executablePath = "/etc/v2/dbf/bin/dbfsqlexecute"
-	case "windows":
-		executablePath = "C:/PROGRA~1/V2/DBF/bin/dbfsqlexecute"
-	default:
-		logger.Errorf("Unknown OS type: %s", osType)
-		return "", fmt.Errorf("unknown os_type: %s", osType)
+	executablePath, err := dbfsqlexecutePath(osType)
+	if err != nil {
+		return "", err
 	}
 
 	// Build full command string for dbfsqlexecute
@@ -84,27 +215,25 @@ func ExecuteSqlAgentAPI(agentID, osType, action, hexEncodedJSON, option string,
 	// Determine if empty output is acceptable (download, policycompliance, and os_execute with --background may not return output)
 	allowEmptyOutput := (action == "download" || action == "policycompliance" || action == "os_execute")
 
+	start := time.Now()
 	var lastErr error
 	for attempt := 1; attempt <= maxRetries; attempt++ {
 		if attempt > 1 {
-			// Exponential backoff using lookup table to avoid gosec G115 false positive
-			delays := []time.Duration{2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 32 * time.Second}
-			delayIndex := attempt - 2
-			if delayIndex >= len(delays) {
-				delayIndex = len(delays) - 1
-			}
-			delay := delays[delayIndex]
+			delay := retryDelay(attempt)
 			logger.Warnf("dbfAgentAPI attempt %d failed, retrying in %v...", attempt-1, delay)
+			agentCallRetries.WithLabelValues(action, agentID).Inc()
+			span.AddEvent("retry", trace.WithAttributes(attribute.Int("agent.attempt", attempt)))
 			time.Sleep(delay)
 		}
 
 		logger.Debugf("dbfAgentAPI attempt %d/%d - agentID: %s", attempt, maxRetries, agentID)
 
-		result, err := executeAgentAPIAttempt(agentID, command, requiredStdout, allowEmptyOutput)
+		result, err := e.executeAttempt(ctx, agentID, command, requiredStdout, allowEmptyOutput)
 		if err == nil {
 			if attempt > 1 {
 				logger.Infof("dbfAgentAPI succeeded on attempt %d/%d", attempt, maxRetries)
 			}
+			observeAgentCall(action, agentID, start, nil)
 			return result, nil
 		}
 
@@ -113,6 +242,7 @@ func ExecuteSqlAgentAPI(agentID, osType, action, hexEncodedJSON, option string,
 		// Check if this is a retryable error
 		if !isRetryableAgentError(err) {
 			logger.Errorf("Non-retryable error on attempt %d: %v", attempt, err)
+			observeAgentCall(action, agentID, start, err)
 			return "", err
 		}
 
@@ -120,36 +250,19 @@ func ExecuteSqlAgentAPI(agentID, osType, action, hexEncodedJSON, option string,
 	}
 
 	logger.Errorf("dbfAgentAPI failed after %d attempts, last error: %v", maxRetries, lastErr)
+	observeAgentCall(action, agentID, start, lastErr)
 	return "", fmt.Errorf("dbfAgentAPI failed after %d attempts: %w", maxRetries, lastErr)
 }
 
-// executeAgentAPIAttempt performs a single attempt of dbfAgentAPI command execution with timeout.
+// executeAttempt performs a single attempt of agent command execution with timeout.
 // Generic function that can be reused for different command types (SQL, file operations, etc.)
-func executeAgentAPIAttempt(agentID, command string, requiredStdout, allowEmptyOutput bool) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), config.Cfg.AgentExecutionTimeout)
+func (e *agentExecutor) executeAttempt(ctx context.Context, agentID, command string, requiredStdout, allowEmptyOutput bool) (string, error) {
+	ctx, cancel := attemptContext(ctx)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx,
-		"sudo",
-		config.Cfg.AgentAPIPath,
-		"--json",
-		"cmd",
-		agentID,
-		command,
-	)
-
-	logger.Debugf("Executing command with timeout %v: sudo %s --json cmd %s '%s'",
-		config.Cfg.AgentExecutionTimeout, config.Cfg.AgentAPIPath, agentID, command)
-
-	outputBytes, err := cmd.Output()
+	outputBytes, err := e.transport.runCommand(ctx, agentID, command)
 	if err != nil {
-		// Check if error is due to timeout
-		if ctx.Err() == context.DeadlineExceeded {
-			logger.Debugf("dbfAgentAPI execution timed out after %v", config.Cfg.AgentExecutionTimeout)
-			return "", fmt.Errorf("dbfAgentAPI execution timed out after %v", config.Cfg.AgentExecutionTimeout)
-		}
-		logger.Debugf("dbfAgentAPI command execution failed: %v", err)
-		return "", fmt.Errorf("failed to run dbfAgentAPI: %v", err)
+		return "", err
 	}
 
 	logger.Debugf("dbfAgentAPI output: %s", string(outputBytes))
@@ -239,11 +352,14 @@ func isRetryableAgentError(err error) bool {
 	return true
 }
 
-// ExecuteAgentAPISimpleCommand executes simple dbfsqlexecute commands that don't use hex-encoded JSON.
-// Used for operations like checkstatus, getresults, listjobs, cleanup that pass plain values.
+// ExecuteSimpleCommand executes simple dbfsqlexecute commands that don't use hex-encoded JSON.
+// Used for operations like checkstatus, getresults, listjobs, cleanup, cancel that pass plain values.
 // Command format: /etc/v2/dbf/bin/dbfsqlexecute <action> <value> [option]
-func ExecuteAgentAPISimpleCommand(agentID, osType, action, value, option string, requiredStdout bool) (string, error) {
-	maxRetries := config.Cfg.AgentMaxRetries
+func (e *agentExecutor) ExecuteSimpleCommand(agentID, osType, action, value, option string, requiredStdout bool) (_ string, err error) {
+	maxRetries := config.Current().AgentMaxRetries
+
+	ctx, span := e.startSpan("agent."+action, agentID, attribute.String("agent.action", action))
+	defer func() { tracing.End(span, err) }()
 
 	logger.Debugf("Starting dbfAgentAPI simple command - agentID: %s, osType: %s, action: %s, value: %s",
 		agentID, osType, action, value)
@@ -255,21 +371,15 @@ func ExecuteAgentAPISimpleCommand(agentID, osType, action, value, option string,
 		"listjobs":    true,
 		"cleanup":     true,
 		"info":        true,
+		"cancel":      true,
 	}
 	if !validActions[action] {
-		return "", fmt.Errorf("invalid simple action: %s (must be one of: checkstatus, getresults, listjobs, cleanup, info)", action)
+		return "", fmt.Errorf("invalid simple action: %s (must be one of: checkstatus, getresults, listjobs, cleanup, info, cancel)", action)
 	}
 
-	// Build executable path based on OS type
-	var executablePath string
-	switch strings.ToLower(osType) {
-	case "linux":
-		executablePath = "/etc/v2/dbf/bin/dbfsqlexecute"
-	case "windows":
-		executablePath = "C:/PROGRA~1/V2/DBF/bin/dbfsqlexecute"
-	default:
-		logger.Errorf("Unknown OS type: %s", osType)
-		return "", fmt.Errorf("unknown os_type: %s", osType)
+	executablePath, err := dbfsqlexecutePath(osType)
+	if err != nil {
+		return "", err
 	}
 
 	// Build full command string for dbfsqlexecute
@@ -282,26 +392,25 @@ func ExecuteAgentAPISimpleCommand(agentID, osType, action, value, option string,
 
 	logger.Debugf("Constructed simple command: %s", command)
 
+	start := time.Now()
 	var lastErr error
 	for attempt := 1; attempt <= maxRetries; attempt++ {
 		if attempt > 1 {
-			delays := []time.Duration{2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 32 * time.Second}
-			delayIndex := attempt - 2
-			if delayIndex >= len(delays) {
-				delayIndex = len(delays) - 1
-			}
-			delay := delays[delayIndex]
+			delay := retryDelay(attempt)
 			logger.Warnf("dbfAgentAPI simple command attempt %d failed, retrying in %v...", attempt-1, delay)
+			agentCallRetries.WithLabelValues(action, agentID).Inc()
+			span.AddEvent("retry", trace.WithAttributes(attribute.Int("agent.attempt", attempt)))
 			time.Sleep(delay)
 		}
 
 		logger.Debugf("dbfAgentAPI simple command attempt %d/%d - agentID: %s", attempt, maxRetries, agentID)
 
-		result, err := executeAgentAPIAttempt(agentID, command, requiredStdout, false)
+		result, err := e.executeAttempt(ctx, agentID, command, requiredStdout, false)
 		if err == nil {
 			if attempt > 1 {
 				logger.Infof("dbfAgentAPI simple command succeeded on attempt %d/%d", attempt, maxRetries)
 			}
+			observeAgentCall(action, agentID, start, nil)
 			return result, nil
 		}
 
@@ -309,6 +418,7 @@ func ExecuteAgentAPISimpleCommand(agentID, osType, action, value, option string,
 
 		if !isRetryableAgentError(err) {
 			logger.Errorf("Non-retryable error on attempt %d: %v", attempt, err)
+			observeAgentCall(action, agentID, start, err)
 			return "", err
 		}
 
@@ -316,49 +426,18 @@ func ExecuteAgentAPISimpleCommand(agentID, osType, action, value, option string,
 	}
 
 	logger.Errorf("dbfAgentAPI simple command failed after %d attempts, last error: %v", maxRetries, lastErr)
+	observeAgentCall(action, agentID, start, lastErr)
 	return "", fmt.Errorf("dbfAgentAPI simple command failed after %d attempts: %w", maxRetries, lastErr)
 }
 
-// executeAgentAPIGetFile downloads a file from agent to local path.
-// Command format: dbfAgentAPI getfile <agentID> <remote_path> <local_path>
-func executeAgentAPIGetFile(agentID, remotePath, localPath string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), config.Cfg.AgentExecutionTimeout)
-	defer cancel()
-
-	logger.Debugf("Starting dbfAgentAPI getfile - agentID: %s, remotePath: %s, localPath: %s",
-		agentID, remotePath, localPath)
-
-	cmd := exec.CommandContext(ctx,
-		"sudo",
-		config.Cfg.AgentAPIPath,
-		"getfile",
-		agentID,
-		remotePath,
-		localPath,
-	)
-
-	logger.Debugf("Executing getfile command: sudo %s getfile %s %s %s",
-		config.Cfg.AgentAPIPath, agentID, remotePath, localPath)
-
-	outputBytes, err := cmd.CombinedOutput()
-	if err != nil {
-		if ctx.Err() == context.DeadlineExceeded {
-			logger.Errorf("dbfAgentAPI getfile timed out after %v", config.Cfg.AgentExecutionTimeout)
-			return fmt.Errorf("dbfAgentAPI getfile timed out after %v", config.Cfg.AgentExecutionTimeout)
-		}
-		logger.Errorf("dbfAgentAPI getfile failed: %v, output: %s", err, string(outputBytes))
-		return fmt.Errorf("failed to download file from agent: %v, output: %s", err, string(outputBytes))
-	}
-
-	logger.Infof("dbfAgentAPI getfile completed successfully: %s -> %s", remotePath, localPath)
-	return nil
-}
-
-// DownloadFileAgentAPI downloads a file from agent to local storage and returns metadata.
-// Similar to downloadFileVeloArtifact but uses dbfAgentAPI getfile command.
+// DownloadFile downloads a file from agent to local storage and returns metadata.
+// Similar to downloadFileVeloArtifact but uses the agent getfile operation.
 // Files are downloaded to {VeloResultsDir}/{agentID}/ with MD5-based filename for compatibility.
-func DownloadFileAgentAPI(agentID, remotePath, osType string) (*AgentDownloadResponse, error) {
-	maxRetries := config.Cfg.AgentMaxRetries
+func (e *agentExecutor) DownloadFile(agentID, remotePath, osType string) (_ *AgentDownloadResponse, err error) {
+	maxRetries := config.Current().AgentMaxRetries
+
+	spanCtx, span := e.startSpan("agent.getfile", agentID)
+	defer func() { tracing.End(span, err) }()
 
 	// Convert Windows path backslashes to forward slashes
 	if strings.ToLower(osType) == "windows" {
@@ -383,19 +462,16 @@ func DownloadFileAgentAPI(agentID, remotePath, osType string) (*AgentDownloadRes
 	var lastErr error
 	for attempt := 1; attempt <= maxRetries; attempt++ {
 		if attempt > 1 {
-			delays := []time.Duration{2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 32 * time.Second}
-			delayIndex := attempt - 2
-			if delayIndex >= len(delays) {
-				delayIndex = len(delays) - 1
-			}
-			delay := delays[delayIndex]
+			delay := retryDelay(attempt)
 			logger.Warnf("Agent file download attempt %d failed, retrying in %v...", attempt-1, delay)
 			time.Sleep(delay)
 		}
 
 		logger.Debugf("Agent file download attempt %d/%d - agentID: %s, remotePath: %s", attempt, maxRetries, agentID, remotePath)
 
-		err := executeAgentAPIGetFile(agentID, remotePath, tempLocalPath)
+		ctx, cancel := attemptContext(spanCtx)
+		err := e.transport.getFile(ctx, agentID, remotePath, tempLocalPath)
+		cancel()
 		if err == nil {
 			if attempt > 1 {
 				logger.Infof("Agent file download succeeded on attempt %d/%d", attempt, maxRetries)
@@ -458,10 +534,13 @@ type ConnectionTestAgentParams struct {
 	ServiceName string `json:"service_name"`
 }
 
-// ExecuteConnectionTestAgentAPI executes database connection test via dbfAgentAPI.
+// ExecuteConnectionTest executes database connection test via agent.
 // Uses v2dbfsqldetector/sqldetector.exe on the remote agent.
-func ExecuteConnectionTestAgentAPI(clientID string, params ConnectionTestAgentParams, osType string) (*AgentAPIResponse, error) {
-	maxRetries := config.Cfg.AgentMaxRetries
+func (e *agentExecutor) ExecuteConnectionTest(clientID string, params ConnectionTestAgentParams, osType string) (_ *AgentAPIResponse, err error) {
+	maxRetries := config.Current().AgentMaxRetries
+
+	spanCtx, span := e.startSpan("agent.connectiontest", clientID, attribute.String("db.system", params.Type))
+	defer func() { tracing.End(span, err) }()
 
 	logger.Debugf("Starting connection test via agent API - clientID: %s, osType: %s, type: %s, host: %s:%d",
 		clientID, osType, params.Type, params.Host, params.Port)
@@ -493,39 +572,20 @@ func ExecuteConnectionTestAgentAPI(clientID string, params ConnectionTestAgentPa
 	var lastErr error
 	for attempt := 1; attempt <= maxRetries; attempt++ {
 		if attempt > 1 {
-			delays := []time.Duration{2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 32 * time.Second}
-			delayIndex := attempt - 2
-			if delayIndex >= len(delays) {
-				delayIndex = len(delays) - 1
-			}
-			delay := delays[delayIndex]
+			delay := retryDelay(attempt)
 			logger.Warnf("Connection test attempt %d failed, retrying in %v...", attempt-1, delay)
 			time.Sleep(delay)
 		}
 
 		logger.Debugf("Connection test attempt %d/%d - clientID: %s", attempt, maxRetries, clientID)
 
-		// Execute command via agent API
-		ctx, cancel := context.WithTimeout(context.Background(), config.Cfg.AgentExecutionTimeout)
-
-		cmd := exec.CommandContext(ctx,
-			"sudo",
-			config.Cfg.AgentAPIPath,
-			"--json",
-			"cmd",
-			clientID,
-			command,
-		)
-
-		outputBytes, err := cmd.Output()
+		// Execute command via agent transport
+		ctx, cancel := attemptContext(spanCtx)
+		outputBytes, err := e.transport.runCommand(ctx, clientID, command)
 		cancel()
 
 		if err != nil {
-			if ctx.Err() == context.DeadlineExceeded {
-				lastErr = fmt.Errorf("connection test timed out after %v", config.Cfg.AgentExecutionTimeout)
-			} else {
-				lastErr = fmt.Errorf("failed to run dbfAgentAPI: %v", err)
-			}
+			lastErr = err
 
 			if !isRetryableAgentError(lastErr) {
 				logger.Errorf("Non-retryable connection test error on attempt %d: %v", attempt, lastErr)
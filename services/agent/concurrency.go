@@ -0,0 +1,128 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"dbfartifactapi/config"
+	"dbfartifactapi/pkg/logger"
+)
+
+// commandSlots is shared by every executor, so the per-agent limit holds whichever transport runs the command.
+var commandSlots = newConcurrencyLimiter(func() int { return config.Current().AgentMaxConcurrentPerClient })
+
+func init() {
+	config.OnReload(func(prev, next config.AppConfig) {
+		if prev.AgentMaxConcurrentPerClient != next.AgentMaxConcurrentPerClient {
+			logger.Infof("Agent command limit changed from %d to %d per agent",
+				prev.AgentMaxConcurrentPerClient, next.AgentMaxConcurrentPerClient)
+			commandSlots.wakeAll()
+		}
+	})
+}
+
+// concurrencyLimiter caps in-flight commands per agent ID. The limit is read on every acquire,
+// so a reload takes effect without dropping commands already running; 0 means unlimited.
+type concurrencyLimiter struct {
+	limit    func() int
+	mu       sync.Mutex
+	inFlight map[string]int
+	// wake is closed when a slot of the agent frees up or the limit changes
+	wake map[string]chan struct{}
+}
+
+func newConcurrencyLimiter(limit func() int) *concurrencyLimiter {
+	return &concurrencyLimiter{limit: limit, inFlight: make(map[string]int), wake: make(map[string]chan struct{})}
+}
+
+// acquire blocks until agentID has a free slot or ctx is done. The returned func releases the slot.
+func (l *concurrencyLimiter) acquire(ctx context.Context, agentID string) (func(), error) {
+	var start time.Time
+	for {
+		l.mu.Lock()
+		limit := l.limit()
+		if limit <= 0 || l.inFlight[agentID] < limit {
+			l.inFlight[agentID]++
+			l.mu.Unlock()
+			if !start.IsZero() {
+				agentCommandsWaiting.WithLabelValues(agentID).Dec()
+			}
+			agentCommandsInFlight.WithLabelValues(agentID).Inc()
+			return func() { l.release(agentID) }, nil
+		}
+		wake, exists := l.wake[agentID]
+		if !exists {
+			wake = make(chan struct{})
+			l.wake[agentID] = wake
+		}
+		l.mu.Unlock()
+
+		if start.IsZero() {
+			start = time.Now()
+			logger.Debugf("Agent %s has %d commands in flight, waiting for a slot", agentID, limit)
+			agentCommandsWaiting.WithLabelValues(agentID).Inc()
+		}
+		select {
+		case <-wake:
+		case <-ctx.Done():
+			agentCommandsWaiting.WithLabelValues(agentID).Dec()
+			// "timed out after" keeps the error retryable, so the command queues again after backoff
+			return nil, fmt.Errorf("timed out after %v waiting for a command slot on agent %s: %w",
+				time.Since(start).Round(time.Millisecond), agentID, ctx.Err())
+		}
+	}
+}
+
+func (l *concurrencyLimiter) release(agentID string) {
+	agentCommandsInFlight.WithLabelValues(agentID).Dec()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.inFlight[agentID]--; l.inFlight[agentID] <= 0 {
+		delete(l.inFlight, agentID)
+	}
+	if wake, exists := l.wake[agentID]; exists {
+		close(wake)
+		delete(l.wake, agentID)
+	}
+}
+
+// wakeAll lets every waiting command check the limit again.
+func (l *concurrencyLimiter) wakeAll() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for agentID, wake := range l.wake {
+		close(wake)
+		delete(l.wake, agentID)
+	}
+}
+
+// limitedTransport runs at most AGENT_MAX_CONCURRENT_PER_CLIENT commands and file copies per agent at once.
+// Waiting for a slot counts against the attempt timeout.
+type limitedTransport struct {
+	agentTransport
+	limiter *concurrencyLimiter
+}
+
+func withConcurrencyLimit(transport agentTransport) agentTransport {
+	return &limitedTransport{agentTransport: transport, limiter: commandSlots}
+}
+
+func (t *limitedTransport) runCommand(ctx context.Context, agentID, command string) ([]byte, error) {
+	release, err := t.limiter.acquire(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return t.agentTransport.runCommand(ctx, agentID, command)
+}
+
+func (t *limitedTransport) getFile(ctx context.Context, agentID, remotePath, localPath string) error {
+	release, err := t.limiter.acquire(ctx, agentID)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return t.agentTransport.getFile(ctx, agentID, remotePath, localPath)
+}
@@ -0,0 +1,127 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// blockingTransport records the peak number of concurrent commands per agent and blocks until release is closed
+type blockingTransport struct {
+	release chan struct{}
+	mu      sync.Mutex
+	running map[string]int
+	peak    map[string]int
+	started atomic.Int32
+}
+
+func (t *blockingTransport) runCommand(ctx context.Context, agentID, command string) ([]byte, error) {
+	t.mu.Lock()
+	t.running[agentID]++
+	if t.running[agentID] > t.peak[agentID] {
+		t.peak[agentID] = t.running[agentID]
+	}
+	t.mu.Unlock()
+	t.started.Add(1)
+
+	<-t.release
+
+	t.mu.Lock()
+	t.running[agentID]--
+	t.mu.Unlock()
+	return []byte(`{"status":"success","output":"ok"}`), nil
+}
+
+func (t *blockingTransport) getFile(ctx context.Context, agentID, remotePath, localPath string) error {
+	_, err := t.runCommand(ctx, agentID, "")
+	return err
+}
+
+func newBlockingTransport() *blockingTransport {
+	return &blockingTransport{release: make(chan struct{}), running: map[string]int{}, peak: map[string]int{}}
+}
+
+// waitStarted waits until n commands reached the transport, then a little longer so extra ones would show up
+func (t *blockingTransport) waitStarted(tb testing.TB, n int32) {
+	tb.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for t.started.Load() < n && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if got := t.started.Load(); got != n {
+		tb.Errorf("started %d commands, want %d", got, n)
+	}
+}
+
+// TestLimitedTransport_CapsCommandsPerAgent tests that no agent runs more than the limit at once
+// while other agents are not held up
+func TestLimitedTransport_CapsCommandsPerAgent(t *testing.T) {
+	inner := newBlockingTransport()
+	transport := &limitedTransport{agentTransport: inner, limiter: newConcurrencyLimiter(func() int { return 2 })}
+
+	var wg sync.WaitGroup
+	for _, agentID := range []string{"C.1", "C.1", "C.1", "C.1", "C.1", "C.2"} {
+		wg.Add(1)
+		go func(agentID string) {
+			defer wg.Done()
+			if _, err := transport.runCommand(context.Background(), agentID, "info"); err != nil {
+				t.Errorf("runCommand on %s: %v", agentID, err)
+			}
+		}(agentID)
+	}
+
+	// Two commands on C.1 and one on C.2 start, the other three wait
+	inner.waitStarted(t, 3)
+
+	close(inner.release)
+	wg.Wait()
+	if inner.peak["C.1"] != 2 || inner.peak["C.2"] != 1 {
+		t.Errorf("peak concurrency = %v, want C.1: 2, C.2: 1", inner.peak)
+	}
+}
+
+// TestLimitedTransport_RaisedLimitWakesWaiters tests that a reload raising the limit starts queued commands
+// without waiting for running ones to finish
+func TestLimitedTransport_RaisedLimitWakesWaiters(t *testing.T) {
+	var limit atomic.Int32
+	limit.Store(1)
+	limiter := newConcurrencyLimiter(func() int { return int(limit.Load()) })
+	inner := newBlockingTransport()
+	defer close(inner.release)
+	transport := &limitedTransport{agentTransport: inner, limiter: limiter}
+
+	for i := 0; i < 3; i++ {
+		go transport.runCommand(context.Background(), "C.1", "info")
+	}
+	inner.waitStarted(t, 1)
+
+	limit.Store(3)
+	limiter.wakeAll()
+	inner.waitStarted(t, 3)
+}
+
+// TestLimitedTransport_WaitTimesOut tests that waiting for a slot ends with a retryable error at the attempt deadline
+func TestLimitedTransport_WaitTimesOut(t *testing.T) {
+	inner := newBlockingTransport()
+	defer close(inner.release)
+	transport := &limitedTransport{agentTransport: inner, limiter: newConcurrencyLimiter(func() int { return 1 })}
+
+	go transport.runCommand(context.Background(), "C.1", "info")
+	for inner.started.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := transport.getFile(ctx, "C.1", "/tmp/a", "/tmp/b")
+	if err == nil || !strings.Contains(err.Error(), "waiting for a command slot") {
+		t.Fatalf("err = %v, want slot wait timeout", err)
+	}
+	if !isRetryableAgentError(err) {
+		t.Errorf("expected slot wait timeout to be retryable: %v", err)
+	}
+}
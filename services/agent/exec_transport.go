@@ -0,0 +1,93 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"dbfartifactapi/config"
+	"dbfartifactapi/pkg/logger"
+	"dbfartifactapi/pkg/tracing"
+)
+
+// execTransport runs the dbfAgentAPI binary once per call (sudo dbfAgentAPI --json cmd ...)
+type execTransport struct {
+	apiPath string
+}
+
+// NewExecAgentExecutor creates an executor that forks the dbfAgentAPI binary for every agent call.
+func NewExecAgentExecutor(apiPath string) AgentExecutor {
+	return &agentExecutor{
+		transport: withConcurrencyLimit(&execTransport{apiPath: apiPath}),
+	}
+}
+
+func (t *execTransport) runCommand(ctx context.Context, agentID, command string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx,
+		"sudo",
+		t.apiPath,
+		"--json",
+		"cmd",
+		agentID,
+		command,
+	)
+	withTraceEnv(ctx, cmd)
+
+	logger.Debugf("Executing command with timeout %v: sudo %s --json cmd %s '%s'",
+		config.Current().AgentExecutionTimeout, t.apiPath, agentID, command)
+
+	outputBytes, err := cmd.Output()
+	if err != nil {
+		// Check if error is due to timeout
+		if ctx.Err() == context.DeadlineExceeded {
+			logger.Debugf("dbfAgentAPI execution timed out after %v", config.Current().AgentExecutionTimeout)
+			return nil, fmt.Errorf("dbfAgentAPI execution timed out after %v", config.Current().AgentExecutionTimeout)
+		}
+		logger.Debugf("dbfAgentAPI command execution failed: %v", err)
+		return nil, fmt.Errorf("failed to run dbfAgentAPI: %v", err)
+	}
+
+	return outputBytes, nil
+}
+
+// getFile downloads a file from agent to local path.
+// Command format: dbfAgentAPI getfile <agentID> <remote_path> <local_path>
+func (t *execTransport) getFile(ctx context.Context, agentID, remotePath, localPath string) error {
+	logger.Debugf("Starting dbfAgentAPI getfile - agentID: %s, remotePath: %s, localPath: %s",
+		agentID, remotePath, localPath)
+
+	cmd := exec.CommandContext(ctx,
+		"sudo",
+		t.apiPath,
+		"getfile",
+		agentID,
+		remotePath,
+		localPath,
+	)
+	withTraceEnv(ctx, cmd)
+
+	logger.Debugf("Executing getfile command: sudo %s getfile %s %s %s",
+		t.apiPath, agentID, remotePath, localPath)
+
+	outputBytes, err := cmd.CombinedOutput()
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			logger.Errorf("dbfAgentAPI getfile timed out after %v", config.Current().AgentExecutionTimeout)
+			return fmt.Errorf("dbfAgentAPI getfile timed out after %v", config.Current().AgentExecutionTimeout)
+		}
+		logger.Errorf("dbfAgentAPI getfile failed: %v, output: %s", err, string(outputBytes))
+		return fmt.Errorf("failed to download file from agent: %v, output: %s", err, string(outputBytes))
+	}
+
+	logger.Infof("dbfAgentAPI getfile completed successfully: %s -> %s", remotePath, localPath)
+	return nil
+}
+
+// withTraceEnv passes the trace context to dbfAgentAPI in the TRACEPARENT environment variable.
+// sudo drops it unless sudoers keeps it (Defaults env_keep += "TRACEPARENT").
+func withTraceEnv(ctx context.Context, cmd *exec.Cmd) {
+	if traceParent := tracing.TraceParent(ctx); traceParent != "" {
+		cmd.Env = append(os.Environ(), "TRACEPARENT="+traceParent)
+	}
+}
@@ -0,0 +1,164 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"dbfartifactapi/config"
+	"dbfartifactapi/pkg/logger"
+	"dbfartifactapi/pkg/tracing"
+)
+
+// Agent gateway HTTP API (JSON):
+//
+//	POST {base}/api/v1/agents/{agent_id}/commands   body {"command": "..."}  -> AgentAPIResponse
+//	GET  {base}/api/v1/agents/{agent_id}/files?path=<remote_path>           -> raw file content
+//
+// Command responses use the same JSON document dbfAgentAPI prints with --json,
+// so response validation and retries are shared with the exec transport.
+
+// maxGatewayErrorBody limits how much of an error response body is included in error messages
+const maxGatewayErrorBody = 512
+
+// GatewayCommandRequest is the request body for agent gateway command execution
+type GatewayCommandRequest struct {
+	Command string `json:"command"`
+}
+
+// httpTransport talks to an agent gateway over HTTP instead of forking dbfAgentAPI
+type httpTransport struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+// NewHTTPAgentExecutor creates an executor that calls an agent gateway over HTTP/JSON.
+// Token is sent as Bearer authorization when not empty. Per-call timeout comes from AGENT_EXECUTION_TIMEOUT.
+func NewHTTPAgentExecutor(baseURL, token string) AgentExecutor {
+	return &agentExecutor{
+		transport: withConcurrencyLimit(&httpTransport{
+			baseURL: strings.TrimRight(baseURL, "/"),
+			token:   token,
+			client:  &http.Client{},
+		}),
+	}
+}
+
+func (t *httpTransport) runCommand(ctx context.Context, agentID, command string) ([]byte, error) {
+	body, err := json.Marshal(GatewayCommandRequest{Command: command})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode agent gateway request: %v", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/api/v1/agents/%s/commands", t.baseURL, url.PathEscape(agentID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("invalid agent gateway request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	logger.Debugf("Sending command to agent gateway: POST %s", endpoint)
+
+	resp, err := t.do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read agent gateway response: %v", err)
+	}
+
+	if err := gatewayStatusError(resp.StatusCode, respBody); err != nil {
+		return nil, err
+	}
+	return respBody, nil
+}
+
+func (t *httpTransport) getFile(ctx context.Context, agentID, remotePath, localPath string) error {
+	endpoint := fmt.Sprintf("%s/api/v1/agents/%s/files?path=%s",
+		t.baseURL, url.PathEscape(agentID), url.QueryEscape(remotePath))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("invalid agent gateway request: %v", err)
+	}
+
+	logger.Debugf("Downloading file from agent gateway - agentID: %s, remotePath: %s, localPath: %s",
+		agentID, remotePath, localPath)
+
+	resp, err := t.do(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxGatewayErrorBody))
+		return gatewayStatusError(resp.StatusCode, errBody)
+	}
+
+	file, err := os.Create(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to create local file %s: %v", localPath, err)
+	}
+
+	if _, err := io.Copy(file, resp.Body); err != nil {
+		file.Close()
+		os.Remove(localPath)
+		return fmt.Errorf("failed to download file from agent: %v", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write local file %s: %v", localPath, err)
+	}
+
+	logger.Infof("Agent gateway getfile completed successfully: %s -> %s", remotePath, localPath)
+	return nil
+}
+
+// do sends request with authorization and maps context timeout to the agent timeout error
+func (t *httpTransport) do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	if t.token != "" {
+		req.Header.Set("Authorization", "Bearer "+t.token)
+	}
+	tracing.InjectHeader(ctx, req.Header)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("agent gateway request timed out after %v", config.Current().AgentExecutionTimeout)
+		}
+		return nil, fmt.Errorf("agent gateway request failed: %v", err)
+	}
+	return resp, nil
+}
+
+// gatewayStatusError converts non-2xx gateway responses into errors that isRetryableAgentError understands:
+// 401/403 are not retried, other 4xx are reported as invalid requests, 5xx are retried.
+func gatewayStatusError(statusCode int, body []byte) error {
+	if statusCode >= 200 && statusCode < 300 {
+		return nil
+	}
+
+	if len(body) > maxGatewayErrorBody {
+		body = body[:maxGatewayErrorBody]
+	}
+	detail := strings.TrimSpace(string(body))
+
+	switch {
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		return fmt.Errorf("agent gateway unauthorized (status %d): %s", statusCode, detail)
+	case statusCode >= 400 && statusCode < 500:
+		return fmt.Errorf("agent gateway rejected invalid request (status %d): %s", statusCode, detail)
+	default:
+		return fmt.Errorf("agent gateway service unavailable (status %d): %s", statusCode, detail)
+	}
+}
@@ -0,0 +1,146 @@
+package agent
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"dbfartifactapi/config"
+	"dbfartifactapi/pkg/tracing"
+)
+
+func setupAgentTestConfig(t *testing.T) {
+	t.Helper()
+	original := config.Cfg
+	t.Cleanup(func() { config.Cfg = original })
+
+	config.Cfg.AgentMaxRetries = 1
+	config.Cfg.AgentExecutionTimeout = 5 * time.Second
+	config.Cfg.VeloResultsDir = t.TempDir()
+}
+
+// TestHTTPAgentExecutor_ExecuteSql tests command execution through agent gateway
+func TestHTTPAgentExecutor_ExecuteSql(t *testing.T) {
+	setupAgentTestConfig(t)
+
+	var receivedCommand, receivedAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/agents/L.0050/commands" {
+			http.NotFound(w, r)
+			return
+		}
+		var req GatewayCommandRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		receivedCommand = req.Command
+		receivedAuth = r.Header.Get("Authorization")
+
+		_ = json.NewEncoder(w).Encode(AgentAPIResponse{Status: "success", ClientID: "L.0050", Output: `{"job_id":"job-1"}`})
+	}))
+	defer server.Close()
+
+	executor := NewHTTPAgentExecutor(server.URL+"/", "secret")
+	output, err := executor.ExecuteSql("L.0050", "linux", "execute", "abcd", "--background", true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if output != `{"job_id":"job-1"}` {
+		t.Errorf("Unexpected output: %s", output)
+	}
+	if receivedCommand != "/etc/v2/dbf/bin/dbfsqlexecute execute abcd --background" {
+		t.Errorf("Unexpected command sent to gateway: %s", receivedCommand)
+	}
+	if receivedAuth != "Bearer secret" {
+		t.Errorf("Expected bearer token, got %q", receivedAuth)
+	}
+}
+
+// TestHTTPAgentExecutor_Unauthorized tests that auth failures are not retried
+func TestHTTPAgentExecutor_Unauthorized(t *testing.T) {
+	setupAgentTestConfig(t)
+	config.Cfg.AgentMaxRetries = 3
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		http.Error(w, "bad token", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	_, err := NewHTTPAgentExecutor(server.URL, "wrong").ExecuteSimpleCommand("L.0050", "linux", "checkstatus", "job-1", "", true)
+	if err == nil || !strings.Contains(err.Error(), "unauthorized") {
+		t.Fatalf("Expected unauthorized error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected no retries for unauthorized, got %d calls", calls)
+	}
+}
+
+// TestHTTPAgentExecutor_DownloadFile tests file download through agent gateway
+func TestHTTPAgentExecutor_DownloadFile(t *testing.T) {
+	setupAgentTestConfig(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/agents/W.0060/files" || r.URL.Query().Get("path") != "C:/results/out.json" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("file-content"))
+	}))
+	defer server.Close()
+
+	resp, err := NewHTTPAgentExecutor(server.URL, "").DownloadFile("W.0060", `C:\results\out.json`, "windows")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(resp.LocalPath)
+	if err != nil {
+		t.Fatalf("Failed to read downloaded file: %v", err)
+	}
+	if string(data) != "file-content" || resp.Size != int64(len("file-content")) {
+		t.Errorf("Unexpected download result: %+v, content=%s", resp, data)
+	}
+	expectedMd5 := md5.Sum([]byte("file-content"))
+	if resp.Md5 != hex.EncodeToString(expectedMd5[:]) {
+		t.Errorf("Unexpected md5 %s", resp.Md5)
+	}
+}
+
+// TestHTTPAgentExecutor_PropagatesTraceContext tests that a context-bound executor forwards traceparent to the gateway
+func TestHTTPAgentExecutor_PropagatesTraceContext(t *testing.T) {
+	setupAgentTestConfig(t)
+
+	var receivedTraceParent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedTraceParent = r.Header.Get("traceparent")
+		_ = json.NewEncoder(w).Encode(AgentAPIResponse{Status: "success", ClientID: "L.0050", Output: "{}"})
+	}))
+	defer server.Close()
+
+	// Without a tracer provider the caller's span context is passed through unchanged
+	traceParent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	ctx := tracing.ContextWithTraceParent(context.Background(), traceParent)
+	executor := NewHTTPAgentExecutor(server.URL, "")
+
+	if _, err := WithContext(ctx, executor).ExecuteSimpleCommand("L.0050", "linux", "checkstatus", "job-1", "", true); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if receivedTraceParent != traceParent {
+		t.Errorf("Gateway received traceparent %q, want %q", receivedTraceParent, traceParent)
+	}
+
+	if _, err := executor.ExecuteSimpleCommand("L.0050", "linux", "checkstatus", "job-1", "", true); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if receivedTraceParent != "" {
+		t.Errorf("Unbound executor sent traceparent %q", receivedTraceParent)
+	}
+}
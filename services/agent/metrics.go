@@ -0,0 +1,38 @@
+package agent
+
+import (
+	"time"
+
+	"dbfartifactapi/pkg/metrics"
+)
+
+// agentCallBuckets span quick status checks up to long-running SQL executions.
+var agentCallBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300}
+
+var (
+	agentCallsTotal = metrics.NewCounterVec("dbfartifactapi_agent_calls_total",
+		"dbfAgentAPI calls by action, endpoint and outcome (success or failure), counted once per call after retries.",
+		"action", "endpoint", "outcome")
+	agentCallDuration = metrics.NewHistogramVec("dbfartifactapi_agent_call_duration_seconds",
+		"dbfAgentAPI call latency including retries and backoff.", agentCallBuckets, "action", "endpoint")
+	agentCallRetries = metrics.NewCounterVec("dbfartifactapi_agent_call_retries_total",
+		"dbfAgentAPI attempts beyond the first.", "action", "endpoint")
+	agentCommandsInFlight = metrics.NewGaugeVec("dbfartifactapi_agent_commands_in_flight",
+		"Agent commands and file copies currently running per endpoint.", "endpoint")
+	agentCommandsWaiting = metrics.NewGaugeVec("dbfartifactapi_agent_commands_waiting",
+		"Agent commands waiting for a slot under AGENT_MAX_CONCURRENT_PER_CLIENT per endpoint.", "endpoint")
+)
+
+func init() {
+	metrics.MustRegister(agentCallsTotal, agentCallDuration, agentCallRetries, agentCommandsInFlight, agentCommandsWaiting)
+}
+
+// observeAgentCall records the outcome and latency of one agent call started at start.
+func observeAgentCall(action, agentID string, start time.Time, err error) {
+	outcome := "success"
+	if err != nil {
+		outcome = "failure"
+	}
+	agentCallsTotal.WithLabelValues(action, agentID, outcome).Inc()
+	agentCallDuration.WithLabelValues(action, agentID).Observe(time.Since(start).Seconds())
+}
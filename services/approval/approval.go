@@ -0,0 +1,86 @@
+package approval
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"dbfartifactapi/models"
+)
+
+// Policy risk levels, lowest first, as stored in dbgroup_listpolicies.risk_level.
+const (
+	RiskLow      = "LOW"
+	RiskMedium   = "MEDIUM"
+	RiskHigh     = "HIGH"
+	RiskCritical = "CRITICAL"
+)
+
+var riskRank = map[string]int{
+	RiskLow:      1,
+	RiskMedium:   2,
+	RiskHigh:     3,
+	RiskCritical: 4,
+}
+
+// Change describes a group change before it is applied, for deciding whether it needs approval.
+type Change struct {
+	Operation  string                       // Group change, e.g. "assign_actors"
+	GroupID    uint                         // Group the change applies to
+	Request    interface{}                  // Request body, stored so the change can be applied later
+	Preview    interface{}                  // Dry-run result of the change, including the SQL it would run
+	Policies   []models.DBGroupListPolicies // Policies whose grants the change adds or removes
+	ActorCount int                          // Actors added to or removed from the group
+}
+
+// Executor applies an approved change request through the normal service path and returns its result.
+type Executor func(ctx context.Context, cr *models.ChangeRequest) (interface{}, error)
+
+// Thresholds decide which changes need approval and how many approvals they need.
+type Thresholds struct {
+	ActorThreshold   int // More actors than this need Required approvals; 0 disables the rule
+	Required         int // Approvals for HIGH-risk policies and large actor changes
+	RequiredCritical int // Approvals for CRITICAL-risk policies
+}
+
+// assessment is why a change needs approval and how many approvals it needs.
+type assessment struct {
+	required  int
+	riskLevel string
+	reason    string
+}
+
+// assess returns the approvals a change needs, zero when it can be applied right away.
+// The highest risk level among the touched policies and the number of actors changed each
+// set a minimum; the change needs the larger of the two.
+func assess(change Change, thresholds Thresholds) assessment {
+	var result assessment
+	var reasons, risky []string
+	for _, policy := range change.Policies {
+		level := strings.ToUpper(policy.RiskLevel)
+		if riskRank[level] < riskRank[RiskHigh] {
+			continue
+		}
+		risky = append(risky, fmt.Sprintf("%d (%s)", policy.ID, level))
+		if riskRank[level] > riskRank[result.riskLevel] {
+			result.riskLevel = level
+		}
+	}
+	switch result.riskLevel {
+	case RiskCritical:
+		result.required = thresholds.RequiredCritical
+	case RiskHigh:
+		result.required = thresholds.Required
+	}
+	if len(risky) > 0 {
+		reasons = append(reasons, "touches high-risk policies "+strings.Join(risky, ", "))
+	}
+
+	if thresholds.ActorThreshold > 0 && change.ActorCount > thresholds.ActorThreshold {
+		result.required = max(result.required, thresholds.Required)
+		reasons = append(reasons, fmt.Sprintf("changes %d actors, more than %d", change.ActorCount, thresholds.ActorThreshold))
+	}
+
+	result.reason = strings.Join(reasons, "; ")
+	return result
+}
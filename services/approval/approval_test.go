@@ -0,0 +1,62 @@
+package approval
+
+import (
+	"testing"
+
+	"dbfartifactapi/models"
+)
+
+var thresholds = Thresholds{ActorThreshold: 20, Required: 1, RequiredCritical: 2}
+
+func groupPolicy(id uint, risk string) models.DBGroupListPolicies {
+	return models.DBGroupListPolicies{ID: id, RiskLevel: risk}
+}
+
+// TestAssess_LowRiskNeedsNoApproval tests that LOW and MEDIUM policies on few actors apply right away
+func TestAssess_LowRiskNeedsNoApproval(t *testing.T) {
+	result := assess(Change{
+		Policies:   []models.DBGroupListPolicies{groupPolicy(1, RiskLow), groupPolicy(2, RiskMedium)},
+		ActorCount: 20,
+	}, thresholds)
+	if result.required != 0 || result.reason != "" {
+		t.Errorf("assess = %+v, want no approval", result)
+	}
+}
+
+// TestAssess_HighestRiskWins tests that the riskiest policy sets the approval count
+func TestAssess_HighestRiskWins(t *testing.T) {
+	high := assess(Change{Policies: []models.DBGroupListPolicies{groupPolicy(1, RiskLow), groupPolicy(2, RiskHigh)}}, thresholds)
+	if high.required != 1 || high.riskLevel != RiskHigh {
+		t.Errorf("HIGH policy: assess = %+v, want 1 approval at HIGH", high)
+	}
+
+	critical := assess(Change{Policies: []models.DBGroupListPolicies{groupPolicy(2, RiskHigh), groupPolicy(3, "critical")}}, thresholds)
+	if critical.required != 2 || critical.riskLevel != RiskCritical {
+		t.Errorf("CRITICAL policy: assess = %+v, want 2 approvals at CRITICAL", critical)
+	}
+	if critical.reason != "touches high-risk policies 2 (HIGH), 3 (CRITICAL)" {
+		t.Errorf("reason = %q", critical.reason)
+	}
+}
+
+// TestAssess_ActorThreshold tests the actor rule alone, together with a CRITICAL policy and disabled
+func TestAssess_ActorThreshold(t *testing.T) {
+	many := assess(Change{ActorCount: 21}, thresholds)
+	if many.required != 1 || many.riskLevel != "" {
+		t.Errorf("21 actors: assess = %+v, want 1 approval without risk level", many)
+	}
+
+	both := assess(Change{Policies: []models.DBGroupListPolicies{groupPolicy(3, RiskCritical)}, ActorCount: 50}, thresholds)
+	if both.required != 2 {
+		t.Errorf("CRITICAL policy and 50 actors: required = %d, want 2", both.required)
+	}
+	if both.reason != "touches high-risk policies 3 (CRITICAL); changes 50 actors, more than 20" {
+		t.Errorf("reason = %q", both.reason)
+	}
+
+	disabled := thresholds
+	disabled.ActorThreshold = 0
+	if result := assess(Change{ActorCount: 1000}, disabled); result.required != 0 {
+		t.Errorf("disabled actor rule: assess = %+v, want no approval", result)
+	}
+}
@@ -0,0 +1,353 @@
+package approval
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"dbfartifactapi/config"
+	"dbfartifactapi/models"
+	"dbfartifactapi/pkg/logger"
+	"dbfartifactapi/repository"
+	"dbfartifactapi/services/audit"
+	"dbfartifactapi/services/dto"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+	auditEntityType = "change_request"
+)
+
+// ChangeRequestService holds back high-risk group changes until other users approve them.
+type ChangeRequestService interface {
+	// Enabled reports whether changes are checked for approval at all.
+	Enabled() bool
+	// Gate stores change as a pending change request when it needs approval and returns it.
+	// Returns nil without error when the change can be applied right away.
+	Gate(ctx context.Context, change Change) (*dto.ChangeRequestDetailResponse, error)
+	// List returns one page of change requests, newest first.
+	List(ctx context.Context, query dto.ChangeRequestQuery) (*dto.ChangeRequestListResponse, error)
+	// Get returns one change request with its request, preview and decisions.
+	Get(ctx context.Context, id uint) (*dto.ChangeRequestDetailResponse, error)
+	// Approve records the caller's approval and applies the change once it has enough approvals.
+	Approve(ctx context.Context, id uint, comment string) (*dto.ChangeRequestDetailResponse, error)
+	// Reject records the caller's rejection and closes the change request.
+	Reject(ctx context.Context, id uint, comment string) (*dto.ChangeRequestDetailResponse, error)
+}
+
+type changeRequestService struct {
+	baseRepo repository.BaseRepository
+	crRepo   repository.ChangeRequestRepository
+	execute  Executor
+	now      func() time.Time
+}
+
+// NewChangeRequestService creates a change request service that applies approved changes with execute.
+func NewChangeRequestService(execute Executor) ChangeRequestService {
+	return &changeRequestService{
+		baseRepo: repository.NewBaseRepository(),
+		crRepo:   repository.NewChangeRequestRepository(),
+		execute:  execute,
+		now:      time.Now,
+	}
+}
+
+// Enabled implements ChangeRequestService.
+// Thresholds are re-read on each change, but enabling approvals takes a restart so the tables exist.
+func (s *changeRequestService) Enabled() bool {
+	return config.Cfg.ApprovalEnabled
+}
+
+// Gate implements ChangeRequestService.
+func (s *changeRequestService) Gate(ctx context.Context, change Change) (*dto.ChangeRequestDetailResponse, error) {
+	if !s.Enabled() {
+		return nil, nil
+	}
+	cfg := config.Current()
+	result := assess(change, Thresholds{
+		ActorThreshold:   cfg.ApprovalActorThreshold,
+		Required:         cfg.ApprovalsRequired,
+		RequiredCritical: cfg.ApprovalsRequiredCritical,
+	})
+	if result.required == 0 {
+		return nil, nil
+	}
+
+	payload, err := json.Marshal(change.Request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode change request payload: %w", err)
+	}
+	preview, err := json.Marshal(change.Preview)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode change request preview: %w", err)
+	}
+	cr := &models.ChangeRequest{
+		Operation:         change.Operation,
+		GroupID:           change.GroupID,
+		Status:            models.ChangeRequestPending,
+		RiskLevel:         result.riskLevel,
+		Reason:            result.reason,
+		RequiredApprovals: result.required,
+		RequestedBy:       audit.Actor(ctx),
+		Payload:           string(payload),
+		Preview:           string(preview),
+	}
+
+	tx := s.baseRepo.Begin()
+	txCommitted := false
+	defer func() {
+		if !txCommitted {
+			tx.Rollback()
+		}
+	}()
+
+	if err := s.crRepo.Create(tx, cr); err != nil {
+		return nil, fmt.Errorf("failed to store change request: %w", err)
+	}
+	if err := audit.Record(ctx, tx, audit.Entry{
+		EntityType: auditEntityType,
+		EntityID:   strconv.FormatUint(uint64(cr.ID), 10),
+		Action:     "create",
+		After:      cr,
+	}); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit change request: %w", err)
+	}
+	txCommitted = true
+
+	logger.Infof("Change request %d: %s on group %d by %s needs %d approval(s): %s",
+		cr.ID, cr.Operation, cr.GroupID, cr.RequestedBy, cr.RequiredApprovals, cr.Reason)
+	return detail(cr, nil), nil
+}
+
+// List implements ChangeRequestService.
+func (s *changeRequestService) List(ctx context.Context, query dto.ChangeRequestQuery) (*dto.ChangeRequestListResponse, error) {
+	switch query.Status {
+	case "", models.ChangeRequestPending, models.ChangeRequestApproved, models.ChangeRequestRejected,
+		models.ChangeRequestApplied, models.ChangeRequestFailed:
+	default:
+		return nil, fmt.Errorf("invalid status %q: must be pending, approved, rejected, applied or failed", query.Status)
+	}
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.PageSize < 1 {
+		query.PageSize = defaultPageSize
+	}
+	if query.PageSize > maxPageSize {
+		query.PageSize = maxPageSize
+	}
+
+	requests, total, err := s.crRepo.List(nil, query.Status, (query.Page-1)*query.PageSize, query.PageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list change requests: %w", err)
+	}
+
+	totalPages := int(total) / query.PageSize
+	if int(total)%query.PageSize != 0 {
+		totalPages++
+	}
+	return &dto.ChangeRequestListResponse{
+		ChangeRequests: requests,
+		Total:          total,
+		Page:           query.Page,
+		PageSize:       query.PageSize,
+		TotalPages:     totalPages,
+	}, nil
+}
+
+// Get implements ChangeRequestService.
+func (s *changeRequestService) Get(ctx context.Context, id uint) (*dto.ChangeRequestDetailResponse, error) {
+	cr, err := s.crRepo.GetByID(nil, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("change request with id=%d not found", id)
+		}
+		return nil, fmt.Errorf("failed to get change request %d: %w", id, err)
+	}
+	approvals, err := s.crRepo.ListApprovals(nil, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get decisions on change request %d: %w", id, err)
+	}
+	return detail(cr, approvals), nil
+}
+
+// Approve implements ChangeRequestService.
+// The approval that reaches the required count applies the change after its own transaction
+// commits, so the change runs once even when approvers decide at the same time.
+func (s *changeRequestService) Approve(ctx context.Context, id uint, comment string) (*dto.ChangeRequestDetailResponse, error) {
+	cr, err := s.decide(ctx, id, models.DecisionApprove, comment)
+	if err != nil {
+		return nil, err
+	}
+	if cr.Status == models.ChangeRequestApproved {
+		s.apply(ctx, cr)
+	}
+	return s.Get(ctx, id)
+}
+
+// Reject implements ChangeRequestService.
+// The requester may reject their own change request to withdraw it.
+func (s *changeRequestService) Reject(ctx context.Context, id uint, comment string) (*dto.ChangeRequestDetailResponse, error) {
+	if _, err := s.decide(ctx, id, models.DecisionReject, comment); err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, id)
+}
+
+// decide records one decision on a pending change request and moves it to approved or
+// rejected when the decision settles it.
+func (s *changeRequestService) decide(ctx context.Context, id uint, decision, comment string) (*models.ChangeRequest, error) {
+	actor := audit.Actor(ctx)
+
+	tx := s.baseRepo.Begin()
+	txCommitted := false
+	defer func() {
+		if !txCommitted {
+			tx.Rollback()
+		}
+	}()
+
+	cr, err := s.crRepo.LockByID(tx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("change request with id=%d not found", id)
+		}
+		return nil, fmt.Errorf("failed to get change request %d: %w", id, err)
+	}
+	if cr.Status != models.ChangeRequestPending {
+		return nil, fmt.Errorf("change request %d is %s, only pending change requests can be decided", id, cr.Status)
+	}
+	if decision == models.DecisionApprove && actor == cr.RequestedBy {
+		return nil, fmt.Errorf("change request %d must be approved by someone other than its requester %s", id, actor)
+	}
+	approvals, err := s.crRepo.ListApprovals(tx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get decisions on change request %d: %w", id, err)
+	}
+	for _, approval := range approvals {
+		if approval.Approver == actor {
+			return nil, fmt.Errorf("%s already decided on change request %d", actor, id)
+		}
+	}
+
+	before := *cr
+	if err := s.crRepo.CreateApproval(tx, &models.ChangeRequestApproval{
+		ChangeRequestID: id,
+		Approver:        actor,
+		Decision:        decision,
+		Comment:         comment,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to record decision on change request %d: %w", id, err)
+	}
+
+	now := s.now()
+	switch decision {
+	case models.DecisionApprove:
+		cr.ApprovalCount++
+		if cr.ApprovalCount >= cr.RequiredApprovals {
+			cr.Status = models.ChangeRequestApproved
+			cr.DecidedBy = actor
+			cr.DecidedAt = &now
+		}
+	case models.DecisionReject:
+		cr.Status = models.ChangeRequestRejected
+		cr.DecidedBy = actor
+		cr.DecidedAt = &now
+	}
+	if err := s.crRepo.Save(tx, cr); err != nil {
+		return nil, fmt.Errorf("failed to update change request %d: %w", id, err)
+	}
+	if err := audit.Record(ctx, tx, audit.Entry{
+		EntityType: auditEntityType,
+		EntityID:   strconv.FormatUint(uint64(id), 10),
+		Action:     decision,
+		Before:     before,
+		After:      cr,
+	}); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit decision on change request %d: %w", id, err)
+	}
+	txCommitted = true
+
+	logger.Infof("Change request %d: %s by %s, %d/%d approvals, status %s",
+		id, decision, actor, cr.ApprovalCount, cr.RequiredApprovals, cr.Status)
+	return cr, nil
+}
+
+// apply runs an approved change request and stores its outcome as applied or failed.
+func (s *changeRequestService) apply(ctx context.Context, cr *models.ChangeRequest) {
+	before := *cr
+	result, err := s.execute(ctx, cr)
+
+	now := s.now()
+	cr.AppliedAt = &now
+	entry := audit.Entry{
+		EntityType: auditEntityType,
+		EntityID:   strconv.FormatUint(uint64(cr.ID), 10),
+		Action:     "apply",
+		Before:     before,
+	}
+	if err != nil {
+		cr.Status = models.ChangeRequestFailed
+		cr.Error = err.Error()
+		entry.Outcome = audit.OutcomeFailure
+		entry.Error = cr.Error
+		logger.Errorf("Change request %d: failed to apply %s on group %d: %v", cr.ID, cr.Operation, cr.GroupID, err)
+	} else {
+		cr.Status = models.ChangeRequestApplied
+		if encoded, err := json.Marshal(result); err == nil {
+			cr.Result = string(encoded)
+		} else {
+			logger.Warnf("Change request %d: failed to encode result: %v", cr.ID, err)
+		}
+		logger.Infof("Change request %d: applied %s on group %d", cr.ID, cr.Operation, cr.GroupID)
+	}
+	entry.After = cr
+
+	tx := s.baseRepo.Begin()
+	if err := s.crRepo.Save(tx, cr); err != nil {
+		tx.Rollback()
+		logger.Errorf("Change request %d: failed to store outcome %s: %v", cr.ID, cr.Status, err)
+		return
+	}
+	if err := audit.Record(ctx, tx, entry); err != nil {
+		tx.Rollback()
+		logger.Errorf("Change request %d: failed to audit outcome %s: %v", cr.ID, cr.Status, err)
+		return
+	}
+	if err := tx.Commit().Error; err != nil {
+		logger.Errorf("Change request %d: failed to commit outcome %s: %v", cr.ID, cr.Status, err)
+	}
+}
+
+func detail(cr *models.ChangeRequest, approvals []models.ChangeRequestApproval) *dto.ChangeRequestDetailResponse {
+	if approvals == nil {
+		approvals = []models.ChangeRequestApproval{}
+	}
+	return &dto.ChangeRequestDetailResponse{
+		ChangeRequest: *cr,
+		Request:       rawJSON(cr.Payload),
+		Preview:       rawJSON(cr.Preview),
+		Result:        rawJSON(cr.Result),
+		Approvals:     approvals,
+	}
+}
+
+// rawJSON returns stored JSON as is, nil when nothing was stored.
+func rawJSON(s string) json.RawMessage {
+	if s == "" {
+		return nil
+	}
+	return json.RawMessage(s)
+}
@@ -0,0 +1,210 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"dbfartifactapi/config"
+	"dbfartifactapi/models"
+	"dbfartifactapi/repository"
+
+	"gorm.io/gorm"
+)
+
+// Event outcomes.
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// systemActor is recorded for changes made outside an API request, such as job completion handlers.
+const systemActor = "system"
+
+// Entry describes one audited change made by a service.
+type Entry struct {
+	EntityType string      // e.g. "dbpolicy", "group", "dbactormgt"
+	EntityID   string      // Primary key of the changed entity, empty for bulk changes
+	Action     string      // e.g. "create", "update", "delete", "assign_actors"
+	Before     interface{} // State before the change, encoded as JSON with credentials redacted
+	After      interface{} // State after the change, encoded as JSON with credentials redacted
+	Command    string      // Remote SQL or OS command, appended to commands added with AddCommand
+	Outcome    string      // Defaults to OutcomeSuccess
+	Error      string
+}
+
+// trail carries the caller identity of one API request and the agent commands sent for it
+// until they are written with the next audit event.
+type trail struct {
+	actor      string
+	actorRole  string
+	authMethod string
+	clientIP   string
+	method     string
+	route      string
+	path       string
+
+	mu       sync.Mutex
+	commands []string
+	recorded int
+}
+
+type trailKey struct{}
+
+func withTrail(ctx context.Context, t *trail) context.Context {
+	return context.WithValue(ctx, trailKey{}, t)
+}
+
+func trailFrom(ctx context.Context) *trail {
+	if ctx == nil {
+		return nil
+	}
+	t, _ := ctx.Value(trailKey{}).(*trail)
+	return t
+}
+
+// takeCommands returns and clears the commands collected so far.
+func (t *trail) takeCommands() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	commands := t.commands
+	t.commands = nil
+	return commands
+}
+
+// Actor returns the caller of the API request in ctx, or "system" for changes made outside a request.
+func Actor(ctx context.Context) string {
+	if t := trailFrom(ctx); t != nil {
+		return t.actor
+	}
+	return systemActor
+}
+
+// AddCommand remembers a remote SQL or OS command sent through the agent for the request in ctx.
+// Credentials are redacted before the command is kept. The command is written with the next
+// event recorded for the request, or by the audit middleware when the request ends.
+func AddCommand(ctx context.Context, command string) {
+	t := trailFrom(ctx)
+	if t == nil || command == "" {
+		return
+	}
+	t.mu.Lock()
+	t.commands = append(t.commands, RedactCommand(command))
+	t.mu.Unlock()
+}
+
+// Record appends entry to the audit trail inside tx, so the event is committed or rolled back
+// together with the change it describes. Caller identity and route come from ctx.
+// Does nothing when the audit trail is disabled.
+func Record(ctx context.Context, tx *gorm.DB, entry Entry) error {
+	if !config.Cfg.AuditEnabled {
+		return nil
+	}
+
+	before, err := encodeState(entry.Before)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit before state: %w", err)
+	}
+	after, err := encodeState(entry.After)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit after state: %w", err)
+	}
+
+	event := &models.AuditEvent{
+		Actor:      systemActor,
+		EntityType: entry.EntityType,
+		EntityID:   entry.EntityID,
+		Action:     entry.Action,
+		Before:     before,
+		After:      after,
+		Outcome:    entry.Outcome,
+		Error:      entry.Error,
+	}
+	if event.Outcome == "" {
+		event.Outcome = OutcomeSuccess
+	}
+
+	var commands []string
+	t := trailFrom(ctx)
+	if t != nil {
+		t.fill(event)
+		commands = t.takeCommands()
+	}
+	if entry.Command != "" {
+		commands = append(commands, RedactCommand(entry.Command))
+	}
+	event.Command = strings.Join(commands, "\n")
+
+	if err := appendEvent(tx, event); err != nil {
+		if t != nil {
+			// Keep the commands for the failure event written by the middleware
+			t.mu.Lock()
+			t.commands = append(commands, t.commands...)
+			t.mu.Unlock()
+		}
+		return fmt.Errorf("failed to write audit event: %w", err)
+	}
+	if t != nil {
+		t.mu.Lock()
+		t.recorded++
+		t.mu.Unlock()
+	}
+	return nil
+}
+
+// fill copies the caller identity and route of the request onto event.
+func (t *trail) fill(event *models.AuditEvent) {
+	event.Actor = t.actor
+	event.ActorRole = t.actorRole
+	event.AuthMethod = t.authMethod
+	event.ClientIP = t.clientIP
+	event.Method = t.method
+	event.Route = t.route
+	event.Path = t.path
+}
+
+// appendEvent links event to the current chain head and stores it.
+// The head row stays locked until tx ends, so events are chained in commit order.
+func appendEvent(tx *gorm.DB, event *models.AuditEvent) error {
+	repo := repository.NewAuditEventRepository()
+	head, err := repo.LockHead(tx)
+	if err != nil {
+		return fmt.Errorf("failed to lock audit chain head: %w", err)
+	}
+
+	// Millisecond precision matches the DATETIME(3) column, so the stored value hashes the same
+	event.CreatedAt = time.Now().UTC().Truncate(time.Millisecond)
+	event.PrevHash = head.LastHash
+	event.Hash = computeHash(event)
+	return repo.Append(tx, head, event)
+}
+
+// encodeState encodes an entity state as JSON with credential fields redacted.
+func encodeState(state interface{}) (string, error) {
+	if state == nil {
+		return "", nil
+	}
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return "", err
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(string(raw)))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return "", err
+	}
+	if value == nil {
+		return "", nil
+	}
+
+	redacted, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return "", err
+	}
+	return string(redacted), nil
+}
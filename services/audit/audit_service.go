@@ -0,0 +1,126 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+
+	"dbfartifactapi/pkg/logger"
+	"dbfartifactapi/repository"
+	"dbfartifactapi/services/dto"
+)
+
+const (
+	defaultPageSize  = 50
+	maxPageSize      = 500
+	verifyBatchSize  = 1000
+	maxErrorBodySize = 2048
+)
+
+// AuditService provides read access to the audit trail.
+type AuditService interface {
+	// ListEvents returns one page of events matching query, newest first.
+	ListEvents(ctx context.Context, query dto.AuditEventQuery) (*dto.AuditEventListResponse, error)
+	// VerifyChain recomputes every event hash and checks the chain links up to the head.
+	VerifyChain(ctx context.Context) (*dto.AuditVerifyResponse, error)
+}
+
+type auditService struct {
+	auditRepo repository.AuditEventRepository
+}
+
+// NewAuditService creates a new audit service instance.
+func NewAuditService() AuditService {
+	return &auditService{
+		auditRepo: repository.NewAuditEventRepository(),
+	}
+}
+
+// ListEvents implements AuditService.
+func (s *auditService) ListEvents(ctx context.Context, query dto.AuditEventQuery) (*dto.AuditEventListResponse, error) {
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.PageSize < 1 {
+		query.PageSize = defaultPageSize
+	}
+	if query.PageSize > maxPageSize {
+		query.PageSize = maxPageSize
+	}
+	if query.From != nil && query.To != nil && query.From.After(*query.To) {
+		return nil, fmt.Errorf("from must not be after to")
+	}
+
+	events, total, err := s.auditRepo.List(nil, repository.AuditEventFilter{
+		Actor:      query.Actor,
+		EntityType: query.EntityType,
+		EntityID:   query.EntityID,
+		Action:     query.Action,
+		Outcome:    query.Outcome,
+		From:       query.From,
+		To:         query.To,
+		Offset:     (query.Page - 1) * query.PageSize,
+		Limit:      query.PageSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit events: %w", err)
+	}
+
+	totalPages := int(total) / query.PageSize
+	if int(total)%query.PageSize != 0 {
+		totalPages++
+	}
+	return &dto.AuditEventListResponse{
+		Events:     events,
+		Total:      total,
+		Page:       query.Page,
+		PageSize:   query.PageSize,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// VerifyChain implements AuditService.
+// The head is read first, so events appended during verification are not reported as broken.
+func (s *auditService) VerifyChain(ctx context.Context) (*dto.AuditVerifyResponse, error) {
+	head, err := s.auditRepo.GetHead(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit chain head: %w", err)
+	}
+
+	verifier := &chainVerifier{}
+	result := &dto.AuditVerifyResponse{HeadEventID: head.LastEventID}
+scan:
+	for verifier.lastID < head.LastEventID {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		events, err := s.auditRepo.ListAfter(nil, verifier.lastID, verifyBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read audit events after id=%d: %w", verifier.lastID, err)
+		}
+		if len(events) == 0 {
+			break
+		}
+		for i := range events {
+			if events[i].ID > head.LastEventID {
+				break scan
+			}
+			if err := verifier.next(&events[i]); err != nil {
+				result.Checked = verifier.checked
+				result.BrokenAtID = events[i].ID
+				result.Reason = err.Error()
+				logger.Errorf("Audit chain verification failed: %v", err)
+				return result, nil
+			}
+		}
+	}
+
+	result.Checked = verifier.checked
+	if err := verifier.finish(head); err != nil {
+		result.BrokenAtID = head.LastEventID
+		result.Reason = err.Error()
+		logger.Errorf("Audit chain verification failed: %v", err)
+		return result, nil
+	}
+	result.Valid = true
+	return result, nil
+}
@@ -0,0 +1,117 @@
+package audit
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"dbfartifactapi/models"
+)
+
+// TestRedactCommand tests that credentials are masked in SQL and OS commands
+func TestRedactCommand(t *testing.T) {
+	cases := map[string]string{
+		"ALTER USER 'app'@'%' IDENTIFIED BY 's3cr''et'; FLUSH PRIVILEGES": "ALTER USER 'app'@'%' IDENTIFIED BY ***; FLUSH PRIVILEGES",
+		`CREATE USER scott IDENTIFIED BY "tiger"`:                         "CREATE USER scott IDENTIFIED BY ***",
+		"ALTER USER scott IDENTIFIED BY tiger ACCOUNT UNLOCK":             "ALTER USER scott IDENTIFIED BY *** ACCOUNT UNLOCK",
+		"CREATE USER a IDENTIFIED WITH mysql_native_password BY 'pw'":     "CREATE USER a IDENTIFIED WITH mysql_native_password BY ***",
+		"ALTER ROLE app WITH LOGIN PASSWORD 'pw'":                         "ALTER ROLE app WITH LOGIN PASSWORD ***",
+		"CREATE LOGIN app WITH PASSWORD = 'pw', CHECK_POLICY = OFF":       "CREATE LOGIN app WITH PASSWORD = ***, CHECK_POLICY = OFF",
+		"mysqldump -u root -psecret --all-databases":                      "mysqldump -u root -p*** --all-databases",
+		"pg_dump --password=secret db":                                    "pg_dump --password=*** db",
+		"ALTER USER app PASSWORD EXPIRE":                                  "ALTER USER app PASSWORD EXPIRE",
+		"KILL 42":                                                         "KILL 42",
+	}
+	for command, want := range cases {
+		if got := RedactCommand(command); got != want {
+			t.Errorf("RedactCommand(%q) = %q, want %q", command, got, want)
+		}
+	}
+}
+
+// TestEncodeState tests that credential fields are masked in before/after states
+func TestEncodeState(t *testing.T) {
+	state := map[string]interface{}{
+		"id":          7,
+		"dbuser":      "app",
+		"password":    "hunter2",
+		"dbuserpaswd": "hunter3",
+		"nested":      []interface{}{map[string]interface{}{"ApiToken": "abc", "sql": "CREATE USER x IDENTIFIED BY 'y'"}},
+	}
+	got, err := encodeState(state)
+	if err != nil {
+		t.Fatalf("encodeState: %v", err)
+	}
+	want := `{"dbuser":"app","dbuserpaswd":"***","id":7,"nested":[{"ApiToken":"***","sql":"CREATE USER x IDENTIFIED BY ***"}],"password":"***"}`
+	if got != want {
+		t.Errorf("encodeState = %s, want %s", got, want)
+	}
+
+	var missing *models.DBPolicy
+	if got, err := encodeState(missing); err != nil || got != "" {
+		t.Errorf("encodeState(nil pointer) = %q, %v, want empty", got, err)
+	}
+}
+
+// TestChainVerifier tests that edited, removed and truncated events are detected
+func TestChainVerifier(t *testing.T) {
+	newChain := func() ([]models.AuditEvent, *models.AuditChainHead) {
+		var events []models.AuditEvent
+		prev := ""
+		for i := 1; i <= 4; i++ {
+			event := models.AuditEvent{
+				ID:         uint(i),
+				CreatedAt:  time.Date(2026, 1, 2, 3, 4, 5, i*int(time.Millisecond), time.UTC),
+				Actor:      "alice",
+				EntityType: "dbpolicy",
+				EntityID:   "12",
+				Action:     "update",
+				After:      `{"status":"enabled"}`,
+				Outcome:    OutcomeSuccess,
+				PrevHash:   prev,
+			}
+			event.Hash = computeHash(&event)
+			prev = event.Hash
+			events = append(events, event)
+		}
+		return events, &models.AuditChainHead{ID: 1, LastEventID: 4, LastHash: prev}
+	}
+	verify := func(events []models.AuditEvent, head *models.AuditChainHead) error {
+		v := &chainVerifier{}
+		for i := range events {
+			if err := v.next(&events[i]); err != nil {
+				return err
+			}
+		}
+		return v.finish(head)
+	}
+
+	events, head := newChain()
+	if err := verify(events, head); err != nil {
+		t.Fatalf("intact chain rejected: %v", err)
+	}
+
+	// Same instant read back in another time zone must still verify
+	events, head = newChain()
+	events[1].CreatedAt = events[1].CreatedAt.In(time.FixedZone("UTC+7", 7*3600))
+	if err := verify(events, head); err != nil {
+		t.Errorf("time zone change rejected: %v", err)
+	}
+
+	events, head = newChain()
+	events[1].Actor = "mallory"
+	if err := verify(events, head); err == nil || !strings.Contains(err.Error(), "event 2") {
+		t.Errorf("edited event: err = %v, want mismatch at event 2", err)
+	}
+
+	events, head = newChain()
+	events = append(events[:2], events[3:]...)
+	if err := verify(events, head); err == nil || !strings.Contains(err.Error(), "event 4") {
+		t.Errorf("removed event: err = %v, want broken link at event 4", err)
+	}
+
+	events, head = newChain()
+	if err := verify(events[:3], head); err == nil {
+		t.Error("truncated chain accepted")
+	}
+}
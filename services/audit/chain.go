@@ -0,0 +1,87 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"dbfartifactapi/models"
+)
+
+// hashedFields is the canonical form of an audit event covered by its hash.
+// Field order is fixed by the struct; changing it invalidates every stored chain.
+type hashedFields struct {
+	PrevHash   string `json:"prev_hash"`
+	CreatedAt  int64  `json:"created_at"` // Unix milliseconds, independent of the DB session time zone
+	Actor      string `json:"actor"`
+	ActorRole  string `json:"actor_role"`
+	AuthMethod string `json:"auth_method"`
+	ClientIP   string `json:"client_ip"`
+	Method     string `json:"method"`
+	Route      string `json:"route"`
+	Path       string `json:"path"`
+	EntityType string `json:"entity_type"`
+	EntityID   string `json:"entity_id"`
+	Action     string `json:"action"`
+	Before     string `json:"before"`
+	After      string `json:"after"`
+	Command    string `json:"command"`
+	Outcome    string `json:"outcome"`
+	Error      string `json:"error"`
+}
+
+// computeHash returns the hex SHA-256 of the event contents and the hash of its predecessor.
+func computeHash(event *models.AuditEvent) string {
+	data, _ := json.Marshal(hashedFields{
+		PrevHash:   event.PrevHash,
+		CreatedAt:  event.CreatedAt.UnixMilli(),
+		Actor:      event.Actor,
+		ActorRole:  event.ActorRole,
+		AuthMethod: event.AuthMethod,
+		ClientIP:   event.ClientIP,
+		Method:     event.Method,
+		Route:      event.Route,
+		Path:       event.Path,
+		EntityType: event.EntityType,
+		EntityID:   event.EntityID,
+		Action:     event.Action,
+		Before:     event.Before,
+		After:      event.After,
+		Command:    event.Command,
+		Outcome:    event.Outcome,
+		Error:      event.Error,
+	})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// chainVerifier checks audit events fed to it in ID order.
+type chainVerifier struct {
+	prevHash string
+	lastID   uint
+	checked  int
+}
+
+// next verifies one event against its predecessor.
+// Returns a description of the first inconsistency found.
+func (v *chainVerifier) next(event *models.AuditEvent) error {
+	if event.PrevHash != v.prevHash {
+		return fmt.Errorf("event %d does not link to the preceding event %d, an event was removed or rewritten", event.ID, v.lastID)
+	}
+	if computeHash(event) != event.Hash {
+		return fmt.Errorf("event %d content does not match its hash", event.ID)
+	}
+	v.prevHash = event.Hash
+	v.lastID = event.ID
+	v.checked++
+	return nil
+}
+
+// finish checks that the chain ends at the recorded head, which catches removed trailing events.
+func (v *chainVerifier) finish(head *models.AuditChainHead) error {
+	if head.LastEventID != v.lastID || head.LastHash != v.prevHash {
+		return fmt.Errorf("chain ends at event %d but the head records event %d", v.lastID, head.LastEventID)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"dbfartifactapi/config"
+	"dbfartifactapi/models"
+	"dbfartifactapi/pkg/auth"
+	"dbfartifactapi/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// bodyCaptureWriter keeps the start of the response body so failures can be recorded with their error.
+type bodyCaptureWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyCaptureWriter) Write(data []byte) (int, error) {
+	if remaining := maxErrorBodySize - w.body.Len(); remaining > 0 {
+		if len(data) < remaining {
+			remaining = len(data)
+		}
+		w.body.Write(data[:remaining])
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *bodyCaptureWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Middleware audits every mutating request. It must run after the auth middleware.
+// Services record their changes with Record inside their own transaction; when a request fails,
+// or succeeds without any recorded event, the middleware writes a request-level event instead
+// so no mutating call and no agent command goes unrecorded.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+		t := &trail{
+			actor:    "anonymous",
+			clientIP: c.ClientIP(),
+			method:   c.Request.Method,
+			route:    c.FullPath(),
+			path:     c.Request.URL.Path,
+		}
+		if principal := auth.PrincipalFrom(c); principal != nil {
+			t.actor = principal.Subject
+			t.actorRole = string(principal.Role)
+			t.authMethod = principal.Method
+		}
+		c.Request = c.Request.WithContext(withTrail(c.Request.Context(), t))
+
+		// The caller stays available to services through Actor when no events are written
+		if !config.Cfg.AuditEnabled {
+			c.Next()
+			return
+		}
+
+		writer := &bodyCaptureWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		c.Next()
+
+		status := writer.Status()
+		commands := t.takeCommands()
+		t.mu.Lock()
+		recorded := t.recorded
+		t.mu.Unlock()
+		if status < http.StatusBadRequest && recorded > 0 && len(commands) == 0 {
+			return
+		}
+
+		event := &models.AuditEvent{
+			EntityType: routeEntityType(t.route),
+			EntityID:   c.Param("id"),
+			Action:     "request",
+			Command:    strings.Join(commands, "\n"),
+			Outcome:    OutcomeSuccess,
+		}
+		t.fill(event)
+		if status >= http.StatusBadRequest {
+			event.Outcome = OutcomeFailure
+			event.Error = responseError(status, writer.body.Bytes())
+		}
+
+		if err := config.DB.Transaction(func(tx *gorm.DB) error {
+			return appendEvent(tx, event)
+		}); err != nil {
+			logger.Errorf("Failed to write audit event for %s %s by %s: %v", t.method, t.path, t.actor, err)
+		}
+	}
+}
+
+// routeEntityType derives an entity type from a route such as /api/queries/dbpolicy/:id.
+func routeEntityType(route string) string {
+	route = strings.TrimPrefix(route, "/api/")
+	route = strings.TrimPrefix(route, "queries/")
+	entity, _, _ := strings.Cut(route, "/")
+	return entity
+}
+
+// responseError extracts the error message of a failed JSON response.
+func responseError(status int, body []byte) string {
+	var payload struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil {
+		if payload.Error != "" {
+			return RedactCommand(payload.Error)
+		}
+		if payload.Message != "" {
+			return RedactCommand(payload.Message)
+		}
+	}
+	if len(body) == 0 {
+		return http.StatusText(status)
+	}
+	return RedactCommand(string(body))
+}
@@ -0,0 +1,70 @@
+package audit
+
+import (
+	"regexp"
+	"strings"
+)
+
+// redactedValue replaces credentials in stored commands and entity states.
+const redactedValue = "***"
+
+// commandSecretPatterns match credentials in SQL and OS commands. Group 1 is the text kept
+// in front of the secret, the rest of the match is replaced.
+var commandSecretPatterns = []*regexp.Regexp{
+	// MySQL/Oracle: IDENTIFIED BY 'x', IDENTIFIED WITH plugin BY 'x', IDENTIFIED BY x
+	regexp.MustCompile(`(?i)(\bIDENTIFIED\s+(?:WITH\s+\S+\s+)?BY\s+(?:PASSWORD\s+)?)('(?:[^']|'')*'|"(?:[^"]|"")*"|[^\s;]+)`),
+	// MySQL/PostgreSQL/SQL Server: PASSWORD 'x', PASSWORD = 'x', PASSWORD('x')
+	regexp.MustCompile(`(?i)(\bPASSWORD\s*(?:=\s*|\(\s*)?)('(?:[^']|'')*'|"(?:[^"]|"")*")`),
+	// Command line tools: --password=x, --password x
+	regexp.MustCompile(`(--password[=\s]+)(\S+)`),
+	// mysql/mysqldump: -px
+	regexp.MustCompile(`(\s-p)(\S+)`),
+}
+
+// sensitiveKeyParts mark JSON keys whose values are credentials.
+var sensitiveKeyParts = []string{"password", "passwd", "paswd", "pwd", "secret", "token", "apikey", "api_key", "private_key"}
+
+// RedactCommand masks passwords and other credentials in a SQL or OS command.
+func RedactCommand(command string) string {
+	for _, pattern := range commandSecretPatterns {
+		command = pattern.ReplaceAllString(command, "${1}"+redactedValue)
+	}
+	return command
+}
+
+// isSensitiveKey reports whether a JSON key names a credential.
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, part := range sensitiveKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactValue masks credential fields in a decoded JSON value and SQL credentials in its strings.
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if isSensitiveKey(key) {
+				if item != nil && item != "" {
+					v[key] = redactedValue
+				}
+				continue
+			}
+			v[key] = redactValue(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+		return v
+	case string:
+		return RedactCommand(v)
+	default:
+		return v
+	}
+}
@@ -0,0 +1,20 @@
+package compliance
+
+import (
+	"dbfartifactapi/services/agent"
+	"dbfartifactapi/services/job"
+)
+
+// JobKindPolicyCompliance identifies policy compliance check jobs in the job monitor.
+// Persisted with each job so the completion callback can be restored after an API restart.
+const JobKindPolicyCompliance = "policy_compliance"
+
+// RegisterJobKinds registers the completion callbacks of this package with the job monitor.
+// Called at startup once the agent executor is chosen, before persisted jobs are restored.
+func RegisterJobKinds(agentExec agent.AgentExecutor) {
+	job.RegisterJobKind(JobKindPolicyCompliance, CreatePolicyComplianceCompletionHandler(agentExec))
+}
+
+func init() {
+	job.RegisterContextType("policy_compliance_context", &PolicyComplianceJobContext{})
+}
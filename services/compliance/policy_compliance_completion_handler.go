@@ -41,7 +41,7 @@ type PolicyComplianceGetResultsResponse struct {
 }
 
 // CreatePolicyComplianceCompletionHandler creates a callback function for policy compliance job completion
-func CreatePolicyComplianceCompletionHandler() job.JobCompletionCallback {
+func CreatePolicyComplianceCompletionHandler(agentExec agent.AgentExecutor) job.JobCompletionCallback {
 	return func(jobID string, jobInfo *job.JobInfo, statusResp *job.StatusResponse) error {
 		logger.Infof("Processing policy compliance completion for job %s, status: %s", jobID, statusResp.Status)
 
@@ -52,7 +52,7 @@ func CreatePolicyComplianceCompletionHandler() job.JobCompletionCallback {
 		}
 
 		if statusResp.Status == "completed" {
-			return processPolicyComplianceResults(jobID, contextData, statusResp, jobInfo)
+			return processPolicyComplianceResults(agentExec, jobID, contextData, statusResp, jobInfo)
 		} else {
 			logger.Errorf("Policy compliance job %s failed, no results will be processed", jobID)
 			return fmt.Errorf("policy compliance job failed: %s", statusResp.Message)
@@ -62,7 +62,7 @@ func CreatePolicyComplianceCompletionHandler() job.JobCompletionCallback {
 
 // processPolicyComplianceResults processes the results of a completed policy compliance job.
 // Routes to notification-based or VeloArtifact polling flow based on available data.
-func processPolicyComplianceResults(jobID string, contextData interface{}, statusResp *job.StatusResponse, jobInfo *job.JobInfo) error {
+func processPolicyComplianceResults(agentExec agent.AgentExecutor, jobID string, contextData interface{}, statusResp *job.StatusResponse, jobInfo *job.JobInfo) error {
 	logger.Infof("Processing policy compliance results for job %s - completed: %d, failed: %d",
 		jobID, statusResp.Completed, statusResp.Failed)
 
@@ -81,7 +81,7 @@ func processPolicyComplianceResults(jobID string, contextData interface{}, statu
 	}
 
 	// Legacy VeloArtifact polling flow
-	return processPolicyComplianceResultsFromVeloArtifact(jobID, complianceContext, statusResp)
+	return processPolicyComplianceResultsFromVeloArtifact(agentExec, jobID, complianceContext, statusResp)
 }
 
 // processPolicyComplianceResultsFromNotification handles policy compliance processing when triggered by external notification.
@@ -153,7 +153,7 @@ func processPolicyComplianceResultsFromNotification(jobID string, complianceCont
 }
 
 // processPolicyComplianceResultsFromVeloArtifact handles policy compliance processing via traditional VeloArtifact polling.
-func processPolicyComplianceResultsFromVeloArtifact(jobID string, complianceContext *PolicyComplianceJobContext, statusResp *job.StatusResponse) error {
+func processPolicyComplianceResultsFromVeloArtifact(agentExec agent.AgentExecutor, jobID string, complianceContext *PolicyComplianceJobContext, statusResp *job.StatusResponse) error {
 	logger.Infof("Processing policy compliance results from VeloArtifact polling for job %s", jobID)
 
 	jobMonitor := job.GetJobMonitorService()
@@ -166,7 +166,7 @@ func processPolicyComplianceResultsFromVeloArtifact(jobID string, complianceCont
 	}
 
 	// Retrieve and download results file
-	resultsData, err := retrievePolicyComplianceJobResults(jobID, ep)
+	resultsData, err := retrievePolicyComplianceJobResults(agentExec, jobID, ep)
 	if err != nil {
 		jobMonitor.FailJobAfterProcessing(jobID, err.Error())
 		return err
@@ -211,11 +211,11 @@ func getEndpointForComplianceJob(jobID string, endpointID uint) (*models.Endpoin
 }
 
 // retrievePolicyComplianceJobResults retrieves and parses compliance job results from VeloArtifact
-func retrievePolicyComplianceJobResults(jobID string, endpoint *models.Endpoint) ([]PolicyComplianceResult, error) {
+func retrievePolicyComplianceJobResults(agentExec agent.AgentExecutor, jobID string, endpoint *models.Endpoint) ([]PolicyComplianceResult, error) {
 	logger.Debugf("Retrieving policy compliance results for job %s from endpoint %s", jobID, endpoint.ClientID)
 
 	// Get results from agent using getresults command
-	resultsOutput, err := agent.ExecuteAgentAPISimpleCommand(endpoint.ClientID, endpoint.OsType, "getresults", jobID, "", true)
+	resultsOutput, err := agentExec.ExecuteSimpleCommand(endpoint.ClientID, endpoint.OsType, "getresults", jobID, "", true)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get results for job %s: %w", jobID, err)
 	}
@@ -234,7 +234,7 @@ func retrievePolicyComplianceJobResults(jobID string, endpoint *models.Endpoint)
 		jobID, getResultsResp.Completed, getResultsResp.Failed, getResultsResp.FilePath)
 
 	// Download results file from agent
-	downloadInfo, err := agent.DownloadFileAgentAPI(endpoint.ClientID, getResultsResp.FilePath, endpoint.OsType)
+	downloadInfo, err := agentExec.DownloadFile(endpoint.ClientID, getResultsResp.FilePath, endpoint.OsType)
 	if err != nil {
 		return nil, fmt.Errorf("failed to download results file for job %s: %w", jobID, err)
 	}
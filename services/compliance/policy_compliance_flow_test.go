@@ -0,0 +1,193 @@
+package compliance
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"dbfartifactapi/config"
+	"dbfartifactapi/models"
+	"dbfartifactapi/pkg/fakeagent"
+	"dbfartifactapi/services/agent"
+	"dbfartifactapi/services/dto"
+	"dbfartifactapi/services/job"
+	"dbfartifactapi/utils"
+)
+
+// complianceToolDir holds the dbfcheckpolicycompliance stand-in, which keeps the results file and arguments it was called with
+var complianceToolDir string
+
+// TestMain sets the configuration once, before the job monitor starts reading it
+func TestMain(m *testing.M) {
+	tempDir, err := os.MkdirTemp("", "compliance_flow")
+	if err != nil {
+		panic(err)
+	}
+
+	config.Cfg.AgentMaxRetries = 1
+	config.Cfg.AgentExecutionTimeout = 5 * time.Second
+	config.Cfg.VeloResultsDir = filepath.Join(tempDir, "velo")
+	config.Cfg.NotificationFileDir = filepath.Join(tempDir, "notifications")
+	// Completion must come from the notification; the VeloArtifact polling flow needs the endpoint table
+	config.Cfg.JobMonitorInterval = time.Hour
+
+	complianceToolDir = filepath.Join(tempDir, "tool")
+	config.Cfg.DBFCheckPolicyCompliancePath = filepath.Join(complianceToolDir, "dbfcheckpolicycompliance")
+	script := "#!/bin/sh\ncp \"$4\" \"$(dirname \"$0\")/checked.json\"\necho \"$@\" > \"$(dirname \"$0\")/args\"\n"
+	for _, dir := range []string{config.Cfg.VeloResultsDir, config.Cfg.NotificationFileDir, complianceToolDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			panic(err)
+		}
+	}
+	if err := os.WriteFile(config.Cfg.DBFCheckPolicyCompliancePath, []byte(script), 0755); err != nil {
+		panic(err)
+	}
+
+	code := m.Run()
+	os.RemoveAll(tempDir)
+	os.Exit(code)
+}
+
+// TestPolicyComplianceFlow_FakeAgent tests a compliance job started on the fake agent, tracked by the job monitor
+// and finished through the agent notification: the completion handler hands the agent results to dbfcheckpolicycompliance
+func TestPolicyComplianceFlow_FakeAgent(t *testing.T) {
+	os.Remove(filepath.Join(complianceToolDir, "args"))
+	os.Remove(filepath.Join(complianceToolDir, "checked.json"))
+
+	fa, err := fakeagent.New(context.Background(), fakeagent.Options{QueryDir: t.TempDir(), ResultsDir: t.TempDir(), Token: "secret"})
+	if err != nil {
+		t.Fatalf("Failed to start fake agent: %v", err)
+	}
+	t.Cleanup(func() { fa.Close() })
+	server := httptest.NewServer(fa.Handler())
+	t.Cleanup(server.Close)
+	executor := agent.NewHTTPAgentExecutor(server.URL, "secret")
+
+	RegisterJobKinds(executor)
+	jobMonitor := job.GetJobMonitorService()
+	jobMonitor.SetAgentExecutor(executor)
+
+	// Start the job the way StartCheck does once the connection and endpoint are loaded
+	cmt := &models.CntMgt{ID: 7, CntType: "mysql", IP: "127.0.0.1", Port: 3306, Username: "root", Agent: 1}
+	queryParam := dto.NewDBQueryParamBuilder().
+		SetDBType("mysql").
+		SetHost(cmt.IP).
+		SetPort(cmt.Port).
+		SetUser(cmt.Username).
+		SetQuery("").
+		Build()
+	hexJSON, err := utils.CreateAgentCommandJSON(queryParam)
+	if err != nil {
+		t.Fatalf("Failed to build payload: %v", err)
+	}
+	stdout, err := executor.ExecuteSql("L.0050", "linux", "policycompliance", hexJSON, "--background", true)
+	if err != nil {
+		t.Fatalf("Failed to start compliance job: %v", err)
+	}
+	var started struct {
+		JobID   string `json:"job_id"`
+		Success bool   `json:"success"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &started); err != nil || !started.Success {
+		t.Fatalf("Unexpected job start output: %s", stdout)
+	}
+
+	jobMonitor.AddJobWithKind(context.Background(), started.JobID, JobKindPolicyCompliance, cmt.ID, "L.0050", "linux",
+		map[string]interface{}{
+			"policy_compliance_context": &PolicyComplianceJobContext{CntMgtID: cmt.ID, CMT: cmt, EndpointID: 1},
+		})
+	t.Cleanup(func() { jobMonitor.RemoveJob(started.JobID) })
+
+	// The agent uploads its results file to NOTIFICATION_FILE_DIR/<job>/<md5> before notifying
+	var status job.StatusResponse
+	deadline := time.Now().Add(5 * time.Second)
+	for status.Status != "completed" && time.Now().Before(deadline) {
+		out, err := executor.ExecuteSimpleCommand("L.0050", "linux", "checkstatus", started.JobID, "", true)
+		if err != nil {
+			t.Fatalf("checkstatus failed: %v", err)
+		}
+		_ = json.Unmarshal([]byte(out), &status)
+		time.Sleep(20 * time.Millisecond)
+	}
+	if status.Status != "completed" {
+		t.Fatalf("Agent job status = %q, want completed", status.Status)
+	}
+
+	out, err := executor.ExecuteSimpleCommand("L.0050", "linux", "getresults", started.JobID, "", true)
+	if err != nil {
+		t.Fatalf("getresults failed: %v", err)
+	}
+	var results PolicyComplianceGetResultsResponse
+	if err := json.Unmarshal([]byte(out), &results); err != nil || !results.Success {
+		t.Fatalf("Unexpected getresults output: %s", out)
+	}
+	download, err := executor.DownloadFile("L.0050", results.FilePath, "linux")
+	if err != nil {
+		t.Fatalf("DownloadFile failed: %v", err)
+	}
+	fileData, err := os.ReadFile(download.LocalPath)
+	if err != nil {
+		t.Fatalf("Failed to read downloaded results: %v", err)
+	}
+	sum := md5.Sum(fileData)
+	md5Hash := hex.EncodeToString(sum[:])
+	notifyDir := filepath.Join(config.Cfg.NotificationFileDir, started.JobID)
+	if err := os.MkdirAll(notifyDir, 0755); err != nil {
+		t.Fatalf("Failed to create notification dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(notifyDir, md5Hash), fileData, 0644); err != nil {
+		t.Fatalf("Failed to write notification file: %v", err)
+	}
+
+	if err := jobMonitor.ProcessJobNotification(started.JobID, filepath.Base(results.FilePath), md5Hash, true); err != nil {
+		t.Fatalf("ProcessJobNotification() error = %v", err)
+	}
+
+	// The notification marks the job completed at once; the handler then replaces the message with its own result
+	var info *job.JobInfo
+	deadline = time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		info, _ = jobMonitor.GetJob(started.JobID)
+		if info.Status == "failed" || strings.HasPrefix(info.Message, "Policy compliance check completed") {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if info.Status != "completed" || info.Message != "Policy compliance check completed successfully - processed 4 results" {
+		t.Fatalf("Job = %s %q (error %q), want completed after processing 4 results", info.Status, info.Message, info.Error)
+	}
+
+	args, err := os.ReadFile(filepath.Join(complianceToolDir, "args"))
+	if err != nil {
+		t.Fatalf("dbfcheckpolicycompliance was not run: %v", err)
+	}
+	if got := strings.Fields(string(args)); len(got) != 4 || got[0] != "--id" || got[1] != "7" || got[2] != "--path" {
+		t.Errorf("dbfcheckpolicycompliance args = %v, want --id 7 --path <results>", got)
+	}
+
+	checkedData, err := os.ReadFile(filepath.Join(complianceToolDir, "checked.json"))
+	if err != nil {
+		t.Fatalf("Failed to read checked results: %v", err)
+	}
+	var checked []PolicyComplianceResult
+	if err := json.Unmarshal(checkedData, &checked); err != nil {
+		t.Fatalf("Failed to parse checked results: %v", err)
+	}
+	values := make(map[string]string, len(checked))
+	for _, result := range checked {
+		values[result.Name] = result.Value
+	}
+	if got := values["super_privilege_accounts"]; got != "2" {
+		t.Errorf("super_privilege_accounts = %q, want 2", got)
+	}
+	if got := values["anonymous_accounts"]; got != "0" {
+		t.Errorf("anonymous_accounts = %q, want 0", got)
+	}
+}
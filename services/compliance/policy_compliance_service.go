@@ -23,6 +23,7 @@ type policyComplianceService struct {
 	baseRepo     repository.BaseRepository
 	cntMgtRepo   repository.CntMgtRepository
 	endpointRepo repository.EndpointRepository
+	agentExec    agent.AgentExecutor
 }
 
 // NewPolicyComplianceService creates a new policy compliance service instance.
@@ -31,6 +32,7 @@ func NewPolicyComplianceService() PolicyComplianceService {
 		baseRepo:     repository.NewBaseRepository(),
 		cntMgtRepo:   repository.NewCntMgtRepository(),
 		endpointRepo: repository.NewEndpointRepository(),
+		agentExec:    agent.DefaultExecutor(),
 	}
 }
 
@@ -73,7 +75,7 @@ func (s *policyComplianceService) StartCheck(ctx context.Context, cntMgtID uint)
 		SetHost(cmt.IP).
 		SetPort(cmt.Port).
 		SetUser(cmt.Username).
-		SetPassword(cmt.Password).
+		SetCredentials(cmt.Password, cmt.SecretRef).
 		SetDatabase(database).
 		SetQuery("").
 		SetFileConfig(cmt.ConfigFilePath).
@@ -99,7 +101,7 @@ func (s *policyComplianceService) StartCheck(ctx context.Context, cntMgtID uint)
 	}
 
 	// Start background job with --background option
-	stdout, err := agent.ExecuteSqlAgentAPI(ep.ClientID, ep.OsType, "policycompliance", hexJSON, "--background", true)
+	stdout, err := agent.WithContext(ctx, s.agentExec).ExecuteSql(ep.ClientID, ep.OsType, "policycompliance", hexJSON, "--background", true)
 	if err != nil {
 		tx.Rollback()
 		return "", fmt.Errorf("failed to start agent API job: %w", err)
@@ -131,8 +133,7 @@ func (s *policyComplianceService) StartCheck(ctx context.Context, cntMgtID uint)
 
 	// Add job to monitoring system with completion callback
 	jobMonitor := job.GetJobMonitorService()
-	completionCallback := CreatePolicyComplianceCompletionHandler()
-	jobMonitor.AddJobWithCallback(jobResp.JobID, cntMgtID, ep.ClientID, ep.OsType, completionCallback, contextData)
+	jobMonitor.AddJobWithKind(ctx, jobResp.JobID, JobKindPolicyCompliance, cntMgtID, ep.ClientID, ep.OsType, contextData)
 
 	tx.Rollback()
 
@@ -0,0 +1,74 @@
+package credential
+
+import (
+	"fmt"
+
+	"dbfartifactapi/config"
+	"dbfartifactapi/models"
+	"dbfartifactapi/pkg/logger"
+	"dbfartifactapi/pkg/secrets"
+	"dbfartifactapi/repository"
+)
+
+// NewStoreFromConfig creates the credential store from CREDENTIAL_* and secret provider settings.
+// Without a configured key new passwords are stored in plain text, as before encryption was introduced.
+func NewStoreFromConfig() (*secrets.Store, error) {
+	var keyring *secrets.Keyring
+	var err error
+	switch {
+	case config.Cfg.CredentialKeyFile != "":
+		if keyring, err = secrets.LoadKeyFile(config.Cfg.CredentialKeyFile); err != nil {
+			return nil, fmt.Errorf("CREDENTIAL_KEY_FILE: %w", err)
+		}
+	case len(config.Cfg.CredentialKeys) > 0:
+		if keyring, err = secrets.ParseKeys(config.Cfg.CredentialKeys, config.Cfg.CredentialActiveKey); err != nil {
+			return nil, fmt.Errorf("CREDENTIAL_KEYS: %w", err)
+		}
+	}
+
+	resolver := secrets.NewResolver()
+	resolver.Register("env", secrets.NewEnvProvider(config.Cfg.SecretEnvPrefix))
+	if config.Cfg.SecretFileDir != "" {
+		resolver.Register("file", secrets.NewFileProvider(config.Cfg.SecretFileDir))
+	}
+	if config.Cfg.VaultAddr != "" {
+		resolver.Register("vault", secrets.NewVaultProvider(config.Cfg.VaultAddr, config.Cfg.VaultToken))
+	}
+
+	return secrets.NewStore(keyring, resolver), nil
+}
+
+// RewrapStored encrypts plain text passwords and moves passwords wrapped by retired keys to the active key.
+// Rows updated concurrently are skipped; they were written with the active key already.
+// Returns the number of rows rewritten.
+func RewrapStored(repo repository.CredentialRepository, store *secrets.Store) (int, error) {
+	if !store.EncryptionEnabled() {
+		return 0, nil
+	}
+
+	rewrapped := 0
+	for _, model := range []interface{}{&models.CntMgt{}, &models.DBActorMgt{}} {
+		rows, err := repo.ListPasswords(nil, model)
+		if err != nil {
+			return rewrapped, fmt.Errorf("failed to list stored passwords: %w", err)
+		}
+		for _, row := range rows {
+			if !store.NeedsReseal(row.Password) {
+				continue
+			}
+			sealed, err := store.Reseal(row.Password)
+			if err != nil {
+				logger.Errorf("Failed to re-encrypt password of %T id=%d: %v", model, row.ID, err)
+				continue
+			}
+			replaced, err := repo.ReplacePassword(nil, model, row.ID, row.Password, sealed)
+			if err != nil {
+				return rewrapped, fmt.Errorf("failed to store re-encrypted password: %w", err)
+			}
+			if replaced {
+				rewrapped++
+			}
+		}
+	}
+	return rewrapped, nil
+}
@@ -0,0 +1,18 @@
+package dto
+
+// LogLevelRequest sets the minimum level of the application log.
+type LogLevelRequest struct {
+	Level string `json:"level" binding:"required" example:"DEBUG"` // DEBUG, INFO, WARN, ERROR or FATAL
+}
+
+// LogLevelResponse reports the minimum level of the application log.
+type LogLevelResponse struct {
+	Level           string `json:"level" example:"INFO"`
+	ConfiguredLevel string `json:"configured_level" example:"INFO"` // LOG_LEVEL of the loaded config
+}
+
+// ConfigReloadResponse lists the settings, by AppConfig field name, whose value changed in a config reload.
+type ConfigReloadResponse struct {
+	Applied         []string `json:"applied"`          // Now in effect
+	RestartRequired []string `json:"restart_required"` // Changed but only read at startup
+}
@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"time"
+
+	"dbfartifactapi/models"
+)
+
+// AuditEventQuery filters the audit trail; empty fields match everything.
+type AuditEventQuery struct {
+	Actor      string     `form:"actor"`
+	EntityType string     `form:"entity_type"`
+	EntityID   string     `form:"entity_id"`
+	Action     string     `form:"action"`
+	Outcome    string     `form:"outcome"`
+	From       *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To         *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Page       int        `form:"page"`
+	PageSize   int        `form:"page_size"`
+}
+
+// AuditEventListResponse is one page of audit events, newest first.
+type AuditEventListResponse struct {
+	Events     []models.AuditEvent `json:"events"`
+	Total      int64               `json:"total"`
+	Page       int                 `json:"page"`
+	PageSize   int                 `json:"page_size"`
+	TotalPages int                 `json:"total_pages"`
+}
+
+// AuditVerifyResponse reports whether the stored hash chain is intact.
+type AuditVerifyResponse struct {
+	Valid       bool   `json:"valid"`
+	Checked     int    `json:"checked"`
+	HeadEventID uint   `json:"head_event_id"`
+	BrokenAtID  uint   `json:"broken_at_id,omitempty"`
+	Reason      string `json:"reason,omitempty"`
+}
@@ -39,6 +39,8 @@ type BulkPolicyUpdateJobContext struct {
 	Actor           *models.DBActorMgt       `json:"actor"`
 	EndpointID      uint                     `json:"endpoint_id"`
 	CommandMap      map[string]CommandDetail `json:"command_map"`
+	Author          string                   `json:"author"`                // Caller that started the update, recorded on the policy revision
+	RollbackTo      int                      `json:"rollback_to,omitempty"` // Revision restored when the job is part of a rollback
 }
 
 // CommandDetail stores details about a command to be executed
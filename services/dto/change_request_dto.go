@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"encoding/json"
+
+	"dbfartifactapi/models"
+)
+
+// ChangeRequestQuery selects one page of change requests.
+type ChangeRequestQuery struct {
+	Status   string `form:"status"`
+	Page     int    `form:"page"`
+	PageSize int    `form:"page_size"`
+}
+
+// ChangeRequestListResponse is one page of change requests, newest first.
+type ChangeRequestListResponse struct {
+	ChangeRequests []models.ChangeRequest `json:"change_requests"`
+	Total          int64                  `json:"total"`
+	Page           int                    `json:"page"`
+	PageSize       int                    `json:"page_size"`
+	TotalPages     int                    `json:"total_pages"`
+}
+
+// ChangeRequestDetailResponse is one change request with the request it holds back,
+// the dry-run preview of that request and the decisions made on it.
+type ChangeRequestDetailResponse struct {
+	models.ChangeRequest
+	Request   json.RawMessage                `json:"request" swaggertype:"object"`
+	Preview   json.RawMessage                `json:"preview" swaggertype:"object"`
+	Result    json.RawMessage                `json:"result,omitempty" swaggertype:"object"`
+	Approvals []models.ChangeRequestApproval `json:"approvals"`
+}
+
+// ChangeRequestDecisionRequest is the body of an approve or reject call.
+type ChangeRequestDecisionRequest struct {
+	Comment string `json:"comment" validate:"max=1024"`
+}
@@ -7,6 +7,7 @@ type DBQueryParam struct {
 	Port        int    `json:"port,omitempty"`
 	User        string `json:"user,omitempty"`
 	Password    string `json:"password,omitempty"`
+	SecretRef   string `json:"-"`                  // Secret provider reference resolving the password at agent-call time
 	Database string `json:"database,omitempty"` // For Oracle: use service_name here
 	Query    any    `json:"query,omitempty"`
 	Action      string `json:"action,omitempty"`      // Optional: action like "download", "execute", "os_execute"
@@ -60,6 +61,14 @@ func (r *DBQueryParamBuilder) SetPassword(password string) *DBQueryParamBuilder
 	return r
 }
 
+// SetCredentials sets the stored password and secret reference of a connection.
+// Both are resolved to the plain text password only when the agent command is built.
+func (r *DBQueryParamBuilder) SetCredentials(password, secretRef string) *DBQueryParamBuilder {
+	r.dbQueryParam.Password = password
+	r.dbQueryParam.SecretRef = secretRef
+	return r
+}
+
 // SetDatabase sets the database name for the query parameter.
 func (r *DBQueryParamBuilder) SetDatabase(database string) *DBQueryParamBuilder {
 	r.dbQueryParam.Database = database
@@ -0,0 +1,40 @@
+package dto
+
+import "dbfartifactapi/models"
+
+// Dry-run operations: allow runs the sql_updatedata_allow statements of a policy default, deny its
+// sql_updatedata_deny statements.
+const (
+	DryRunOperationAllow = "allow"
+	DryRunOperationDeny  = "deny"
+)
+
+// DryRunCommand is the SQL a change would run on one connection and the agent endpoint it would be sent to.
+type DryRunCommand struct {
+	CntMgtID   uint            `json:"cnt_id"`
+	CntType    string          `json:"cnt_type"`
+	Database   string          `json:"database,omitempty"`
+	Operation  string          `json:"operation"`
+	Endpoint   models.Endpoint `json:"endpoint"`
+	Statements []string        `json:"statements"`
+}
+
+// PolicyDryRunResponse previews a policy create or update.
+// An update appears as the old policy in Removed and the new one in Added.
+type PolicyDryRunResponse struct {
+	DryRun   bool              `json:"dry_run"`
+	Added    []models.DBPolicy `json:"added"`
+	Removed  []models.DBPolicy `json:"removed"`
+	Commands []DryRunCommand   `json:"commands"`
+}
+
+// BulkPolicyDryRunResponse previews a bulk policy update of one actor on one database.
+type BulkPolicyDryRunResponse struct {
+	DryRun           bool                `json:"dry_run"`
+	CntMgtID         uint                `json:"cntmgt_id"`
+	DBMgtID          uint                `json:"dbmgt_id"`
+	DBActorMgtID     uint                `json:"dbactormgt_id"`
+	PoliciesToAdd    []PolicyCombination `json:"policies_to_add"`
+	PoliciesToRemove []PolicyCombination `json:"policies_to_remove"`
+	Commands         []DryRunCommand     `json:"commands"`
+}
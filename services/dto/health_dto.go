@@ -0,0 +1,40 @@
+package dto
+
+import "time"
+
+// Health statuses reported by the readiness and diagnostics endpoints.
+const (
+	HealthStatusOK          = "ok"
+	HealthStatusDegraded    = "degraded"    // Only non-critical checks failed; the service still accepts traffic
+	HealthStatusUnavailable = "unavailable" // A critical check failed; the service is not ready
+	HealthStatusFail        = "fail"        // Status of a single failed check
+)
+
+// DependencyCheck is the result of probing one dependency.
+type DependencyCheck struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	Critical  bool    `json:"critical"`
+	Target    string  `json:"target,omitempty"`
+	Detail    string  `json:"detail,omitempty"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// DiagnosticsResponse lists every dependency check with its latency and error.
+type DiagnosticsResponse struct {
+	Status    string            `json:"status"`
+	CheckedAt time.Time         `json:"checked_at"`
+	Checks    []DependencyCheck `json:"checks"`
+}
+
+// ReadinessResponse is the compact readiness report; Failed names the checks that did not pass.
+type ReadinessResponse struct {
+	Status string   `json:"status"`
+	Failed []string `json:"failed,omitempty"`
+}
+
+// LivenessResponse reports that the process is serving requests.
+type LivenessResponse struct {
+	Status string `json:"status"`
+}
@@ -0,0 +1,27 @@
+package dto
+
+import "dbfartifactapi/models"
+
+// ValidityRunResult counts the membership transitions of one validity enforcement pass.
+// Partial transitions are counted as activated or expired; Failed ones are retried on the next pass.
+type ValidityRunResult struct {
+	Activated int `json:"activated"`
+	Expired   int `json:"expired"`
+	Failed    int `json:"failed"`
+}
+
+// MembershipTransitionQuery selects one page of membership transitions.
+type MembershipTransitionQuery struct {
+	Status   string `form:"status"`
+	Page     int    `form:"page"`
+	PageSize int    `form:"page_size"`
+}
+
+// MembershipTransitionListResponse is one page of membership transitions, most recently attempted first.
+type MembershipTransitionListResponse struct {
+	Transitions []models.MembershipTransition `json:"transitions"`
+	Total       int64                         `json:"total"`
+	Page        int                           `json:"page"`
+	PageSize    int                           `json:"page_size"`
+	TotalPages  int                           `json:"total_pages"`
+}
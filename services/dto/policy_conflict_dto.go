@@ -0,0 +1,44 @@
+package dto
+
+import "dbfartifactapi/models"
+
+// Policy conflict types.
+const (
+	// PolicyConflictContradictory is a disabled policy overlapping a grant of the same policy default.
+	PolicyConflictContradictory = "contradictory"
+	// PolicyConflictShadowed is an enabled policy inside the scope of a wider grant of the same policy default.
+	PolicyConflictShadowed = "shadowed"
+	// PolicyConflictRedundant is a policy or group grant repeating another one exactly.
+	PolicyConflictRedundant = "redundant"
+)
+
+// PolicyConflictReport lists the contradictory, shadowed and redundant policies of a connection per actor.
+type PolicyConflictReport struct {
+	CntMgtID        uint                   `json:"cnt_id"`
+	PolicyCount     int                    `json:"policy_count"`
+	ActorCount      int                    `json:"actor_count"`
+	Contradictory   int                    `json:"contradictory"`
+	Shadowed        int                    `json:"shadowed"`
+	Redundant       int                    `json:"redundant"`
+	RemovablePolicy []uint                 `json:"removable_policy_ids"` // Disabled repeats, whose deletion runs no SQL on the database
+	Actors          []ActorPolicyConflicts `json:"actors"`
+}
+
+// ActorPolicyConflicts lists the findings for one actor.
+type ActorPolicyConflicts struct {
+	DBActorMgtID uint             `json:"dbactormgt_id"`
+	DBUser       string           `json:"dbuser,omitempty"`
+	Conflicts    []PolicyConflict `json:"conflicts"`
+}
+
+// PolicyConflict is one finding. Policy is the row it is about and Against the row it conflicts with;
+// GroupIDs are the groups granting the policy default to the actor when the finding involves them.
+type PolicyConflict struct {
+	Type            string           `json:"type"`
+	DBPolicyDefault uint             `json:"dbpolicydefault"`
+	Policy          *models.DBPolicy `json:"policy,omitempty"`
+	Against         *models.DBPolicy `json:"against,omitempty"`
+	GroupIDs        []uint           `json:"group_ids,omitempty"`
+	Removable       bool             `json:"removable"`
+	Detail          string           `json:"detail"`
+}
@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"time"
+
+	"dbfartifactapi/models"
+)
+
+// PolicyRevisionChanges is the changeset of one policy revision.
+// An update appears as the old policy in Removed and the new one in Added.
+type PolicyRevisionChanges struct {
+	Added   []models.DBPolicy `json:"added"`
+	Removed []models.DBPolicy `json:"removed"`
+}
+
+// PolicyRevisionQuery selects one page of policy revisions.
+type PolicyRevisionQuery struct {
+	Page     int `form:"page"`
+	PageSize int `form:"page_size"`
+}
+
+// PolicyRevisionListResponse is one page of policy revisions of a connection, newest first.
+type PolicyRevisionListResponse struct {
+	CntMgtID   uint                    `json:"cnt_id"`
+	Revisions  []models.PolicyRevision `json:"revisions"`
+	Total      int64                   `json:"total"`
+	Page       int                     `json:"page"`
+	PageSize   int                     `json:"page_size"`
+	TotalPages int                     `json:"total_pages"`
+}
+
+// PolicyRevisionDetailResponse is one policy revision with its changeset and the policy set after it.
+type PolicyRevisionDetailResponse struct {
+	models.PolicyRevision
+	Changes  PolicyRevisionChanges `json:"changes"`
+	Policies []models.DBPolicy     `json:"policies"`
+}
+
+// PolicyRevisionDiffResponse describes how the policy set of a connection differs between two revisions.
+type PolicyRevisionDiffResponse struct {
+	CntMgtID      uint                   `json:"cnt_id"`
+	FromRevision  int                    `json:"from_revision"`
+	ToRevision    int                    `json:"to_revision"`
+	FromCreatedAt time.Time              `json:"from_created_at"`
+	ToCreatedAt   time.Time              `json:"to_created_at"`
+	Added         []models.DBPolicy      `json:"added"`
+	Removed       []models.DBPolicy      `json:"removed"`
+	Changed       []PolicyRevisionChange `json:"changed"`
+}
+
+// PolicyRevisionChange is a policy present in both revisions with a different status or description.
+type PolicyRevisionChange struct {
+	From models.DBPolicy `json:"from"`
+	To   models.DBPolicy `json:"to"`
+}
+
+// PolicyRollbackResponse reports the bulk policy update jobs started to roll a connection back to a revision.
+type PolicyRollbackResponse struct {
+	CntMgtID       uint                `json:"cnt_id"`
+	TargetRevision int                 `json:"target_revision"`
+	Message        string              `json:"message"`
+	Jobs           []PolicyRollbackJob `json:"jobs"`
+	Skipped        []models.DBPolicy   `json:"skipped,omitempty"` // Connection-wide or wildcard-object policies the bulk path cannot apply
+}
+
+// PolicyRollbackJob is the bulk policy update job restoring the policies of one actor on one database.
+type PolicyRollbackJob struct {
+	JobID           string `json:"job_id"`
+	DBMgtID         uint   `json:"dbmgt_id"`
+	DBActorMgtID    uint   `json:"dbactormgt_id"`
+	PoliciesAdded   int    `json:"policies_added"`
+	PoliciesRemoved int    `json:"policies_removed"`
+}
@@ -0,0 +1,39 @@
+package dto
+
+import "time"
+
+// PrivilegeDriftResponse describes what changed between two privilege snapshots of a connection.
+type PrivilegeDriftResponse struct {
+	CntMgtID         uint                   `json:"cnt_id"`
+	FromVersion      int                    `json:"from_version"`
+	ToVersion        int                    `json:"to_version"`
+	FromCreatedAt    time.Time              `json:"from_created_at"`
+	ToCreatedAt      time.Time              `json:"to_created_at"`
+	GrantsAdded      []PrivilegeGrantChange `json:"grants_added"`
+	GrantsRevoked    []PrivilegeGrantChange `json:"grants_revoked"`
+	ActorsAdded      []PrivilegeDriftActor  `json:"actors_added"`
+	ActorsRemoved    []PrivilegeDriftActor  `json:"actors_removed"`
+	PoliciesAllowed  []PrivilegePolicyDrift `json:"policies_allowed"`
+	PoliciesRevoked  []PrivilegePolicyDrift `json:"policies_revoked"`
+	IncompleteTables []string               `json:"incomplete_tables,omitempty"` // Tables not compared because a query failed in either run
+}
+
+// PrivilegeGrantChange is one raw privilege row present in only one of the two snapshots.
+type PrivilegeGrantChange struct {
+	Table string        `json:"table"`
+	Row   []interface{} `json:"row"`
+}
+
+// PrivilegeDriftActor identifies an actor that appeared or disappeared between snapshots.
+type PrivilegeDriftActor struct {
+	ActorID   uint   `json:"actor_id"`
+	DBUser    string `json:"dbuser"`
+	IPAddress string `json:"ip_address,omitempty"`
+}
+
+// PrivilegePolicyDrift is a policy default that became allowed or stopped being allowed for an actor.
+type PrivilegePolicyDrift struct {
+	ActorID         uint   `json:"actor_id"`
+	DBUser          string `json:"dbuser"`
+	PolicyDefaultID uint   `json:"policy_default_id"`
+}
@@ -0,0 +1,42 @@
+package dto
+
+import "time"
+
+// PrivilegeExplainResponse explains why an actor holds each allowed policy default in the latest snapshot.
+type PrivilegeExplainResponse struct {
+	ActorID           uint                    `json:"actor_id"`
+	DBUser            string                  `json:"dbuser"`
+	CntMgtID          uint                    `json:"cnt_id"`
+	DBType            string                  `json:"db_type"`
+	SnapshotVersion   int                     `json:"snapshot_version"`
+	SnapshotCreatedAt time.Time               `json:"snapshot_created_at"`
+	SuperPrivilege    bool                    `json:"super_privilege"` // Pass 1 matched, so action-wide and object-specific passes were skipped
+	Roles             []PrivilegeRolePath     `json:"roles"`
+	Policies          []PrivilegeExplainEntry `json:"policies"`
+}
+
+// PrivilegeRolePath is a role held by the actor, directly or through other roles.
+type PrivilegeRolePath struct {
+	Role string   `json:"role"`
+	Path []string `json:"path"` // Actor first, then each role granted on the way to Role
+}
+
+// PrivilegeExplainEntry is one allowed policy default and the evaluation that allowed it.
+type PrivilegeExplainEntry struct {
+	PolicyDefaultID uint                 `json:"policy_default_id"`
+	ActionID        int                  `json:"action_id"`
+	Pass            string               `json:"pass"` // super, action_wide or object_specific
+	DBMgtID         int                  `json:"dbmgt_id"`
+	ObjectID        int                  `json:"object_id"`
+	FinalSQL        string               `json:"final_sql"`
+	ResultValue     string               `json:"result_value"`
+	Grants          []PrivilegeGrantPath `json:"grants"`
+}
+
+// PrivilegeGrantPath is a privilege row read by FinalSQL that belongs to the actor or one of its roles.
+type PrivilegeGrantPath struct {
+	Table   string                 `json:"table"`
+	Grantee string                 `json:"grantee"`
+	Via     []string               `json:"via"` // Actor first; more than one element means the grant was inherited through roles
+	Row     map[string]interface{} `json:"row"`
+}
@@ -11,8 +11,10 @@ import (
 	"dbfartifactapi/config"
 	"dbfartifactapi/models"
 	"dbfartifactapi/pkg/logger"
+	"dbfartifactapi/pkg/secrets"
 	"dbfartifactapi/repository"
 	"dbfartifactapi/services/agent"
+	"dbfartifactapi/services/audit"
 	"dbfartifactapi/services/dto"
 	"dbfartifactapi/utils"
 
@@ -49,6 +51,7 @@ type dbActorMgtService struct {
 	dbActorMgtRepo repository.DBActorMgtRepository
 	dbMgtRepo      repository.DBMgtRepository
 	actorAll       []models.DBActor
+	agentExec      agent.AgentExecutor
 }
 
 // NewDBActorMgtService creates a new database actor management service instance.
@@ -61,6 +64,7 @@ func NewDBActorMgtService() DBActorMgtService {
 		dbActorMgtRepo: repository.NewDBActorMgtRepository(),
 		dbMgtRepo:      repository.NewDBMgtRepository(),
 		actorAll:       bootstrap.DBActorAll,
+		agentExec:      agent.DefaultExecutor(),
 	}
 }
 
@@ -189,7 +193,7 @@ func (s *dbActorMgtService) Create(ctx context.Context, data models.DBActorMgt)
 		SetHost(cmt.IP).
 		SetPort(cmt.Port).
 		SetUser(cmt.Username).
-		SetPassword(cmt.Password).
+		SetCredentials(cmt.Password, cmt.SecretRef).
 		SetQuery(finalSQL)
 
 	// Oracle requires ServiceName as the database identifier for CDB/PDB routing
@@ -206,13 +210,18 @@ func (s *dbActorMgtService) Create(ctx context.Context, data models.DBActorMgt)
 	}
 	logger.Debugf("Created agent command JSON payload (hex): %s", hexJSON)
 
-	_, err = agent.ExecuteSqlAgentAPI(ep.ClientID, ep.OsType, "execute", hexJSON, "", false)
+	audit.AddCommand(ctx, finalSQL)
+	_, err = agent.WithContext(ctx, s.agentExec).ExecuteSql(ep.ClientID, ep.OsType, "execute", hexJSON, "", false)
 	if err != nil {
 		tx.Rollback()
 		return nil, fmt.Errorf("executeSqlAgentAPI error: %v", err)
 	}
 
 	data.Status = "enabled"
+	if data.Password, err = secrets.Default().Seal(data.Password); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to encrypt database user password: %w", err)
+	}
 	if err := s.dbActorMgtRepo.Create(tx, &data); err != nil {
 		tx.Rollback()
 		return nil, fmt.Errorf("failed to create database actor record: %w", err)
@@ -233,6 +242,16 @@ func (s *dbActorMgtService) Create(ctx context.Context, data models.DBActorMgt)
 		logger.Infof("Created Oracle schema (DBMgt) record: %s (cntid=%d)", data.DBUser, cmt.ID)
 	}
 
+	if err := audit.Record(ctx, tx, audit.Entry{
+		EntityType: "dbactormgt",
+		EntityID:   fmt.Sprint(data.ID),
+		Action:     "create",
+		After:      data,
+	}); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return nil, err
 	}
@@ -255,6 +274,7 @@ func (s *dbActorMgtService) Update(ctx context.Context, id uint, data dto.DBActo
 		tx.Rollback()
 		return nil, fmt.Errorf("dbactormgt id=%d not found: %v", id, err)
 	}
+	before := *existing
 
 	cmt, err := s.cntmgtRepo.GetCntMgtByID(tx, existing.CntID)
 	if err != nil {
@@ -266,13 +286,13 @@ func (s *dbActorMgtService) Update(ctx context.Context, id uint, data dto.DBActo
 	cntTypeLower := strings.ToLower(cmt.CntType)
 
 	if cntTypeLower == "mysql" {
-		err = s.processMySQLUpdate(tx, existing, data, cmt)
+		err = s.processMySQLUpdate(ctx, tx, existing, data, cmt)
 		if err != nil {
 			tx.Rollback()
 			return nil, fmt.Errorf("MySQL update failed: %w", err)
 		}
 	} else if cntTypeLower == "oracle" {
-		err = s.processOracleUpdate(tx, existing, data, cmt)
+		err = s.processOracleUpdate(ctx, tx, existing, data, cmt)
 		if err != nil {
 			tx.Rollback()
 			return nil, fmt.Errorf("Oracle update failed: %w", err)
@@ -282,6 +302,17 @@ func (s *dbActorMgtService) Update(ctx context.Context, id uint, data dto.DBActo
 		return nil, fmt.Errorf("database type %s is not supported yet", cmt.CntType)
 	}
 
+	if err := audit.Record(ctx, tx, audit.Entry{
+		EntityType: "dbactormgt",
+		EntityID:   fmt.Sprint(id),
+		Action:     "update",
+		Before:     before,
+		After:      existing,
+	}); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return nil, fmt.Errorf("transaction commit failed: %w", err)
 	}
@@ -290,7 +321,7 @@ func (s *dbActorMgtService) Update(ctx context.Context, id uint, data dto.DBActo
 }
 
 // processMySQLUpdate handles MySQL database user updates with flexible field changes
-func (s *dbActorMgtService) processMySQLUpdate(tx interface{}, existing *models.DBActorMgt, data dto.DBActorMgtUpdate, cmt interface{}) error {
+func (s *dbActorMgtService) processMySQLUpdate(ctx context.Context, tx interface{}, existing *models.DBActorMgt, data dto.DBActorMgtUpdate, cmt interface{}) error {
 	// Get connection management details
 	cntMgt, ok := cmt.(*models.CntMgt)
 	if !ok {
@@ -315,7 +346,7 @@ func (s *dbActorMgtService) processMySQLUpdate(tx interface{}, existing *models.
 	// Determine update strategy based on changed fields
 	needsRename := (data.DBUser != "" && data.DBUser != existing.DBUser) ||
 		(data.IPAddress != "" && data.IPAddress != existing.IPAddress)
-	needsPasswordChange := data.Password != "" && data.Password != existing.Password
+	needsPasswordChange := passwordChanged(data.Password, existing.Password)
 
 	// Build SQL command based on changes needed
 	var finalSQL string
@@ -336,7 +367,7 @@ func (s *dbActorMgtService) processMySQLUpdate(tx interface{}, existing *models.
 	logger.Debugf("Final SQL for execution: %s", finalSQL)
 
 	// Execute SQL via VeloArtifact
-	err = s.executeMySQLUpdate(cntMgt, finalSQL)
+	err = s.executeMySQLUpdate(ctx, cntMgt, finalSQL)
 	if err != nil {
 		return fmt.Errorf("failed to execute MySQL update: %w", err)
 	}
@@ -454,7 +485,7 @@ func (s *dbActorMgtService) buildMySQLPasswordSQL(rawSQL string, existing *model
 
 // executeMySQLUpdate executes SQL update command via VeloArtifact.
 // Used by Update operation to execute RENAME USER and/or ALTER USER commands.
-func (s *dbActorMgtService) executeMySQLUpdate(cntMgt *models.CntMgt, finalSQL string) error {
+func (s *dbActorMgtService) executeMySQLUpdate(ctx context.Context, cntMgt *models.CntMgt, finalSQL string) error {
 	ep, err := s.endpointRepo.GetByID(nil, utils.MustIntToUint(cntMgt.Agent))
 	if err != nil {
 		return fmt.Errorf("endpoint id=%d not found: %w", cntMgt.Agent, err)
@@ -466,7 +497,7 @@ func (s *dbActorMgtService) executeMySQLUpdate(cntMgt *models.CntMgt, finalSQL s
 		SetHost(cntMgt.IP).
 		SetPort(cntMgt.Port).
 		SetUser(cntMgt.Username).
-		SetPassword(cntMgt.Password).
+		SetCredentials(cntMgt.Password, cntMgt.SecretRef).
 		SetQuery(finalSQL).
 		Build()
 
@@ -476,7 +507,8 @@ func (s *dbActorMgtService) executeMySQLUpdate(cntMgt *models.CntMgt, finalSQL s
 	}
 	logger.Debugf("Created agent command JSON payload (hex): %s", hexJSON)
 
-	_, err = agent.ExecuteSqlAgentAPI(ep.ClientID, ep.OsType, "execute", hexJSON, "", false)
+	audit.AddCommand(ctx, finalSQL)
+	_, err = agent.WithContext(ctx, s.agentExec).ExecuteSql(ep.ClientID, ep.OsType, "execute", hexJSON, "", false)
 	if err != nil {
 		return fmt.Errorf("executeSqlAgentAPI failed: %w", err)
 	}
@@ -486,13 +518,13 @@ func (s *dbActorMgtService) executeMySQLUpdate(cntMgt *models.CntMgt, finalSQL s
 
 // processOracleUpdate handles Oracle database user update (password change only).
 // Executes ALTER USER via VeloArtifact, then updates local record.
-func (s *dbActorMgtService) processOracleUpdate(tx interface{}, existing *models.DBActorMgt, data dto.DBActorMgtUpdate, cmt interface{}) error {
+func (s *dbActorMgtService) processOracleUpdate(ctx context.Context, tx interface{}, existing *models.DBActorMgt, data dto.DBActorMgtUpdate, cmt interface{}) error {
 	cntMgt, ok := cmt.(*models.CntMgt)
 	if !ok {
 		return fmt.Errorf("invalid connection management type")
 	}
 
-	needsPasswordChange := data.Password != "" && data.Password != existing.Password
+	needsPasswordChange := passwordChanged(data.Password, existing.Password)
 	if !needsPasswordChange {
 		return s.updateLocalMetadata(tx, existing, data)
 	}
@@ -512,7 +544,7 @@ func (s *dbActorMgtService) processOracleUpdate(tx interface{}, existing *models
 		SetHost(cntMgt.IP).
 		SetPort(cntMgt.Port).
 		SetUser(cntMgt.Username).
-		SetPassword(cntMgt.Password).
+		SetCredentials(cntMgt.Password, cntMgt.SecretRef).
 		SetDatabase(cntMgt.ServiceName).
 		SetQuery(finalSQL).
 		Build()
@@ -523,7 +555,8 @@ func (s *dbActorMgtService) processOracleUpdate(tx interface{}, existing *models
 	}
 	logger.Debugf("Created agent command JSON payload (hex): %s", hexJSON)
 
-	_, err = agent.ExecuteSqlAgentAPI(ep.ClientID, ep.OsType, "execute", hexJSON, "", false)
+	audit.AddCommand(ctx, finalSQL)
+	_, err = agent.WithContext(ctx, s.agentExec).ExecuteSql(ep.ClientID, ep.OsType, "execute", hexJSON, "", false)
 	if err != nil {
 		return fmt.Errorf("executeSqlAgentAPI failed: %w", err)
 	}
@@ -531,6 +564,16 @@ func (s *dbActorMgtService) processOracleUpdate(tx interface{}, existing *models
 	return s.updateDatabaseRecord(tx, existing, data)
 }
 
+// passwordChanged reports whether requested differs from the stored password.
+// A stored value that cannot be decrypted is treated as changed so the new password is applied.
+func passwordChanged(requested, stored string) bool {
+	if requested == "" {
+		return false
+	}
+	current, err := secrets.Default().Open(stored)
+	return err != nil || requested != current
+}
+
 // updateLocalMetadata updates metadata fields that don't require remote SQL execution.
 // Used when only status, description, or other non-critical fields change.
 func (s *dbActorMgtService) updateLocalMetadata(tx interface{}, existing *models.DBActorMgt, data dto.DBActorMgtUpdate) error {
@@ -572,7 +615,11 @@ func (s *dbActorMgtService) updateDatabaseRecord(tx interface{}, existing *model
 		existing.IPAddress = data.IPAddress
 	}
 	if data.Password != "" {
-		existing.Password = data.Password
+		sealed, err := secrets.Default().Seal(data.Password)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt database user password: %w", err)
+		}
+		existing.Password = sealed
 	}
 
 	// Update metadata fields
@@ -656,7 +703,7 @@ func (s *dbActorMgtService) Delete(ctx context.Context, id uint) error {
 		SetHost(cmt.IP).
 		SetPort(cmt.Port).
 		SetUser(cmt.Username).
-		SetPassword(cmt.Password).
+		SetCredentials(cmt.Password, cmt.SecretRef).
 		SetQuery(finalSQL)
 
 	// Oracle requires ServiceName as the database identifier for CDB/PDB routing
@@ -673,7 +720,8 @@ func (s *dbActorMgtService) Delete(ctx context.Context, id uint) error {
 	}
 	logger.Debugf("Created agent command JSON payload (hex): %s", hexJSON)
 
-	_, err = agent.ExecuteSqlAgentAPI(ep.ClientID, ep.OsType, "execute", hexJSON, "", false)
+	audit.AddCommand(ctx, finalSQL)
+	_, err = agent.WithContext(ctx, s.agentExec).ExecuteSql(ep.ClientID, ep.OsType, "execute", hexJSON, "", false)
 	if err != nil {
 		tx.Rollback()
 		return fmt.Errorf("executeSqlAgentAPI error: %v", err)
@@ -693,6 +741,16 @@ func (s *dbActorMgtService) Delete(ctx context.Context, id uint) error {
 		logger.Infof("Deleted Oracle schema (DBMgt) record: %s (cntid=%d)", existing.DBUser, cmt.ID)
 	}
 
+	if err := audit.Record(ctx, tx, audit.Entry{
+		EntityType: "dbactormgt",
+		EntityID:   fmt.Sprint(id),
+		Action:     "delete",
+		Before:     existing,
+	}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return err
 	}
@@ -728,7 +786,7 @@ func (s *dbActorMgtService) processMySQLCreateAll(tx *gorm.DB, cmt *models.CntMg
 		SetHost(cmt.IP).
 		SetPort(cmt.Port).
 		SetUser(cmt.Username).
-		SetPassword(cmt.Password).
+		SetCredentials(cmt.Password, cmt.SecretRef).
 		SetQuery(finalSQL).
 		Build()
 
@@ -738,7 +796,7 @@ func (s *dbActorMgtService) processMySQLCreateAll(tx *gorm.DB, cmt *models.CntMg
 	}
 	logger.Debugf("Created agent command JSON payload (hex): %s", hexJSON)
 
-	stdout, err := agent.ExecuteSqlAgentAPI(ep.ClientID, ep.OsType, "execute", hexJSON, "", true)
+	stdout, err := s.agentExec.ExecuteSql(ep.ClientID, ep.OsType, "execute", hexJSON, "", true)
 	if err != nil {
 		return 0, 0, fmt.Errorf("executeSqlAgentAPI error: %v", err)
 	}
@@ -965,7 +1023,7 @@ func (s *dbActorMgtService) executeOracleUserQuery(clientID, osType string, cdb
 		SetHost(cdb.IP).
 		SetPort(cdb.Port).
 		SetUser(cdb.Username).
-		SetPassword(cdb.Password).
+		SetCredentials(cdb.Password, cdb.SecretRef).
 		SetDatabase(cdb.ServiceName).
 		SetQuery(sql).
 		Build()
@@ -976,7 +1034,7 @@ func (s *dbActorMgtService) executeOracleUserQuery(clientID, osType string, cdb
 	}
 	logger.Debugf("Oracle user query hex payload: %s", hexJSON)
 
-	stdout, err := agent.ExecuteSqlAgentAPI(clientID, osType, "execute", hexJSON, "", true)
+	stdout, err := s.agentExec.ExecuteSql(clientID, osType, "execute", hexJSON, "", true)
 	if err != nil {
 		return nil, fmt.Errorf("executeSqlAgentAPI error: %w", err)
 	}
@@ -0,0 +1,89 @@
+package entity
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"dbfartifactapi/config"
+	"dbfartifactapi/models"
+	"dbfartifactapi/pkg/fakeagent"
+	"dbfartifactapi/repository"
+	"dbfartifactapi/services/agent"
+)
+
+// TestDBMgtCreateAll_FakeAgent tests MySQL database discovery through the fake agent: the SHOW DATABASES
+// result of the agent's server is synced into dbmgt, adding new databases and removing stale ones
+func TestDBMgtCreateAll_FakeAgent(t *testing.T) {
+	configDB, err := fakeagent.NewConfigDB(context.Background(), &models.CntMgt{}, &models.Endpoint{}, &models.DBMgt{})
+	if err != nil {
+		t.Fatalf("Failed to start config database: %v", err)
+	}
+	t.Cleanup(func() { configDB.Close() })
+	prevDB, prevCfg := config.DB, config.Cfg
+	config.DB = configDB.DB
+	config.Cfg.AgentMaxRetries = 1
+	config.Cfg.AgentExecutionTimeout = 5 * time.Second
+	t.Cleanup(func() { config.DB, config.Cfg = prevDB, prevCfg })
+
+	fa, err := fakeagent.New(context.Background(), fakeagent.Options{
+		QueryDir:   t.TempDir(),
+		ResultsDir: t.TempDir(),
+		Token:      "secret",
+		Fixtures:   []string{"CREATE DATABASE shop", "CREATE DATABASE billing"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to start fake agent: %v", err)
+	}
+	t.Cleanup(func() { fa.Close() })
+	server := httptest.NewServer(fa.Handler())
+	t.Cleanup(server.Close)
+
+	seed := []interface{}{
+		&models.Endpoint{ID: 1, ClientID: "L.0050", OsType: "linux"},
+		&models.CntMgt{ID: 7, CntType: "mysql", IP: "127.0.0.1", Port: 3306, Username: "root", Agent: 1},
+		&models.DBMgt{ID: 20, CntID: 7, DbName: "shop", DbType: "mysql", Status: "enabled"},
+		&models.DBMgt{ID: 21, CntID: 7, DbName: "legacy", DbType: "mysql", Status: "enabled"},
+	}
+	for _, record := range seed {
+		if err := configDB.DB.Create(record).Error; err != nil {
+			t.Fatalf("Failed to seed %T: %v", record, err)
+		}
+	}
+
+	svc := NewDBMgtServiceWithDeps(
+		repository.NewBaseRepository(),
+		repository.NewDBMgtRepository(),
+		repository.NewCntMgtRepository(),
+		repository.NewEndpointRepository(),
+		[]models.DBType{{Name: "mysql", SqlGet: hex.EncodeToString([]byte("SHOW DATABASES"))}},
+		agent.NewHTTPAgentExecutor(server.URL, "secret"),
+	)
+	changes, err := svc.CreateAll(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("CreateAll() error = %v", err)
+	}
+
+	var synced []models.DBMgt
+	if err := configDB.DB.Where("cnt_id = ?", 7).Order("dbname").Find(&synced).Error; err != nil {
+		t.Fatalf("Failed to read dbmgt: %v", err)
+	}
+	var names []string
+	for _, db := range synced {
+		names = append(names, db.DbName)
+		if db.DbName == "shop" && db.ID != 20 {
+			t.Errorf("shop id = %d, want the existing record 20 kept", db.ID)
+		}
+	}
+	// appdb and hrdb come from the built-in fixtures, legacy is gone from the agent's server
+	want := []string{"appdb", "billing", "hrdb", "information_schema", "mysql", "shop"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("dbmgt = %v, want %v", names, want)
+	}
+	if changes != 6 {
+		t.Errorf("CreateAll() = %d changes, want 5 inserted and 1 deleted", changes)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"dbfartifactapi/pkg/logger"
 	"dbfartifactapi/repository"
 	"dbfartifactapi/services/agent"
+	"dbfartifactapi/services/audit"
 	"dbfartifactapi/services/dto"
 	"dbfartifactapi/utils"
 )
@@ -39,6 +40,7 @@ type dbMgtService struct {
 	cntMgtRepo   repository.CntMgtRepository
 	endpointRepo repository.EndpointRepository
 	dbTypeAll    []models.DBType
+	agentExec    agent.AgentExecutor
 }
 
 // NewDBMgtService creates a new database management service instance.
@@ -50,6 +52,7 @@ func NewDBMgtService() DBMgtService {
 		cntMgtRepo:   repository.NewCntMgtRepository(),
 		endpointRepo: repository.NewEndpointRepository(),
 		dbTypeAll:    bootstrap.DBTypeAll,
+		agentExec:    agent.DefaultExecutor(),
 	}
 }
 
@@ -61,6 +64,7 @@ func NewDBMgtServiceWithDeps(
 	cntMgtRepo repository.CntMgtRepository,
 	endpointRepo repository.EndpointRepository,
 	dbTypeAll []models.DBType,
+	agentExec agent.AgentExecutor,
 ) DBMgtService {
 	return &dbMgtService{
 		baseRepo:     baseRepo,
@@ -68,6 +72,7 @@ func NewDBMgtServiceWithDeps(
 		cntMgtRepo:   cntMgtRepo,
 		endpointRepo: endpointRepo,
 		dbTypeAll:    dbTypeAll,
+		agentExec:    agentExec,
 	}
 }
 
@@ -118,7 +123,7 @@ func (s *dbMgtService) CreateAll(ctx context.Context, cntMgtID uint) (int, error
 		SetHost(cmt.IP).
 		SetPort(cmt.Port).
 		SetUser(cmt.Username).
-		SetPassword(cmt.Password).
+		SetCredentials(cmt.Password, cmt.SecretRef).
 		SetQuery(finalSQL).
 		Build()
 
@@ -129,7 +134,7 @@ func (s *dbMgtService) CreateAll(ctx context.Context, cntMgtID uint) (int, error
 	}
 	logger.Debugf("Created agent command JSON payload (hex): %s", hexJSON)
 
-	stdout, err := agent.ExecuteSqlAgentAPI(clientID, ep.OsType, "execute", hexJSON, "", true)
+	stdout, err := agent.WithContext(ctx, s.agentExec).ExecuteSql(clientID, ep.OsType, "execute", hexJSON, "", true)
 	if err != nil {
 		tx.Rollback()
 		return 0, fmt.Errorf("executeSqlAgentAPI error: %v", err)
@@ -299,7 +304,7 @@ func (s *dbMgtService) Create(ctx context.Context, data models.DBMgt) (*models.D
 		SetHost(cmt.IP).
 		SetPort(cmt.Port).
 		SetUser(cmt.Username).
-		SetPassword(cmt.Password).
+		SetCredentials(cmt.Password, cmt.SecretRef).
 		SetQuery(finalSQL).
 		Build()
 
@@ -310,7 +315,8 @@ func (s *dbMgtService) Create(ctx context.Context, data models.DBMgt) (*models.D
 	}
 	logger.Debugf("Created agent command JSON payload (hex): %s", hexJSON)
 
-	_, err = agent.ExecuteSqlAgentAPI(clientID, ep.OsType, "execute", hexJSON, "", false)
+	audit.AddCommand(ctx, finalSQL)
+	_, err = agent.WithContext(ctx, s.agentExec).ExecuteSql(clientID, ep.OsType, "execute", hexJSON, "", false)
 	if err != nil {
 		tx.Rollback()
 		return nil, fmt.Errorf("failed to create database %s on remote server: %w", data.DbName, err)
@@ -323,6 +329,16 @@ func (s *dbMgtService) Create(ctx context.Context, data models.DBMgt) (*models.D
 		return nil, fmt.Errorf("failed to insert database record %s: %w", data.DbName, err)
 	}
 
+	if err := audit.Record(ctx, tx, audit.Entry{
+		EntityType: "dbmgt",
+		EntityID:   fmt.Sprint(data.ID),
+		Action:     "create",
+		After:      data,
+	}); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return nil, fmt.Errorf("failed to commit database creation transaction: %w", err)
 	}
@@ -380,7 +396,7 @@ func (s *dbMgtService) Delete(ctx context.Context, id uint) error {
 		SetHost(cmt.IP).
 		SetPort(cmt.Port).
 		SetUser(cmt.Username).
-		SetPassword(cmt.Password).
+		SetCredentials(cmt.Password, cmt.SecretRef).
 		SetQuery(finalSQL).
 		SetDatabase(existing.DbName).
 		Build()
@@ -392,7 +408,8 @@ func (s *dbMgtService) Delete(ctx context.Context, id uint) error {
 	}
 	logger.Debugf("Created agent command JSON payload (hex): %s", hexJSON)
 
-	_, err = agent.ExecuteSqlAgentAPI(clientID, ep.OsType, "execute", hexJSON, "", false)
+	audit.AddCommand(ctx, finalSQL)
+	_, err = agent.WithContext(ctx, s.agentExec).ExecuteSql(clientID, ep.OsType, "execute", hexJSON, "", false)
 	if err != nil {
 		tx.Rollback()
 		return fmt.Errorf("failed to delete database %s on remote server: %w", existing.DbName, err)
@@ -403,6 +420,16 @@ func (s *dbMgtService) Delete(ctx context.Context, id uint) error {
 		return fmt.Errorf("failed to delete database record with id=%d: %w", id, err)
 	}
 
+	if err := audit.Record(ctx, tx, audit.Entry{
+		EntityType: "dbmgt",
+		EntityID:   fmt.Sprint(id),
+		Action:     "delete",
+		Before:     existing,
+	}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return fmt.Errorf("failed to commit database deletion transaction: %w", err)
 	}
@@ -19,6 +19,7 @@ import (
 	"dbfartifactapi/pkg/logger"
 	"dbfartifactapi/repository"
 	"dbfartifactapi/services/agent"
+	"dbfartifactapi/services/audit"
 	"dbfartifactapi/services/dto"
 	"dbfartifactapi/services/job"
 	"dbfartifactapi/utils"
@@ -59,6 +60,7 @@ type dbObjectMgtService struct {
 	cntMgtRepo      repository.CntMgtRepository
 	endpointRepo    repository.EndpointRepository
 	dbobjectAllMap  map[uint]models.DBObject
+	agentExec       agent.AgentExecutor
 }
 
 // NewDBObjectMgtService creates a new database object management service instance.
@@ -70,6 +72,7 @@ func NewDBObjectMgtService() DBObjectMgtService {
 		cntMgtRepo:      repository.NewCntMgtRepository(),
 		endpointRepo:    repository.NewEndpointRepository(),
 		dbobjectAllMap:  bootstrap.DBObjectAllMap,
+		agentExec:       agent.DefaultExecutor(),
 	}
 }
 
@@ -131,7 +134,7 @@ func (s *dbObjectMgtService) GetByDbMgtId(ctx context.Context, id uint) (string,
 		SetHost(cmt.IP).
 		SetPort(cmt.Port).
 		SetUser(cmt.Username).
-		SetPassword(cmt.Password)
+		SetCredentials(cmt.Password, cmt.SecretRef)
 
 	// Oracle requires ServiceName as the database identifier for CDB/PDB routing
 	if strings.ToLower(cmt.CntType) == "oracle" {
@@ -162,7 +165,7 @@ func (s *dbObjectMgtService) GetByDbMgtId(ctx context.Context, id uint) (string,
 	}
 
 	// Start background job with --background option
-	stdout, err := agent.ExecuteSqlAgentAPI(ep.ClientID, ep.OsType, "download", hexJSON, "--background", true)
+	stdout, err := agent.WithContext(ctx, s.agentExec).ExecuteSql(ep.ClientID, ep.OsType, "download", hexJSON, "--background", true)
 	if err != nil {
 		tx.Rollback()
 		return "", fmt.Errorf("failed to start agent API job: %w", err)
@@ -197,8 +200,7 @@ func (s *dbObjectMgtService) GetByDbMgtId(ctx context.Context, id uint) (string,
 
 	// Add job to monitoring system with completion callback for atomic object creation
 	jobMonitor := job.GetJobMonitorService()
-	completionCallback := CreateObjectCompletionHandler()
-	jobMonitor.AddJobWithCallback(jobResp.JobID, id, ep.ClientID, ep.OsType, completionCallback, contextData)
+	jobMonitor.AddJobWithKind(ctx, jobResp.JobID, JobKindObject, id, ep.ClientID, ep.OsType, contextData)
 
 	// Frontend should use /api/jobs/{job_id}/status for progress tracking
 	logger.Infof("Job %s added to monitoring system with completion callback", jobResp.JobID)
@@ -488,7 +490,7 @@ func (s *dbObjectMgtService) GetByCntMgtIdOptimized(ctx context.Context, id uint
 		SetHost(cmt.IP).
 		SetPort(cmt.Port).
 		SetUser(cmt.Username).
-		SetPassword(cmt.Password)
+		SetCredentials(cmt.Password, cmt.SecretRef)
 
 	// Oracle requires ServiceName as the database identifier for CDB/PDB routing
 	if strings.ToLower(cmt.CntType) == "oracle" {
@@ -517,7 +519,7 @@ func (s *dbObjectMgtService) GetByCntMgtIdOptimized(ctx context.Context, id uint
 	}
 
 	// Start background job with --background option
-	stdout, err := agent.ExecuteSqlAgentAPI(ep.ClientID, ep.OsType, "download", hexJSON, "--background", true)
+	stdout, err := agent.WithContext(ctx, s.agentExec).ExecuteSql(ep.ClientID, ep.OsType, "download", hexJSON, "--background", true)
 	if err != nil {
 		tx.Rollback()
 		return "", fmt.Errorf("failed to start agent API job: %w", err)
@@ -543,8 +545,7 @@ func (s *dbObjectMgtService) GetByCntMgtIdOptimized(ctx context.Context, id uint
 	}
 
 	jobMonitor := job.GetJobMonitorService()
-	completionCallback := CreateCombinedObjectCompletionHandler()
-	jobMonitor.AddJobWithCallback(jobResp.JobID, id, ep.ClientID, ep.OsType, completionCallback, contextData)
+	jobMonitor.AddJobWithKind(ctx, jobResp.JobID, JobKindCombinedObject, id, ep.ClientID, ep.OsType, contextData)
 
 	logger.Infof("Combined job %s added to monitoring system", jobResp.JobID)
 
@@ -692,7 +693,7 @@ func (s *dbObjectMgtService) Create(ctx context.Context, data models.DBObjectMgt
 		SetHost(cmt.IP).
 		SetPort(cmt.Port).
 		SetUser(cmt.Username).
-		SetPassword(cmt.Password).
+		SetCredentials(cmt.Password, cmt.SecretRef).
 		SetQuery(finalSQL)
 
 	// Oracle requires ServiceName as the database identifier for CDB/PDB routing
@@ -711,7 +712,8 @@ func (s *dbObjectMgtService) Create(ctx context.Context, data models.DBObjectMgt
 	}
 	logger.Debugf("Created agent command JSON payload (hex): %s", hexJSON)
 
-	_, err = agent.ExecuteSqlAgentAPI(ep.ClientID, ep.OsType, "execute", hexJSON, "", false)
+	audit.AddCommand(ctx, finalSQL)
+	_, err = agent.WithContext(ctx, s.agentExec).ExecuteSql(ep.ClientID, ep.OsType, "execute", hexJSON, "", false)
 	if err != nil {
 		tx.Rollback()
 		return nil, fmt.Errorf("failed to execute agent API command: %w", err)
@@ -732,6 +734,16 @@ func (s *dbObjectMgtService) Create(ctx context.Context, data models.DBObjectMgt
 		return nil, fmt.Errorf("failed to create dbobjectmgt record: %w", err)
 	}
 
+	if err := audit.Record(ctx, tx, audit.Entry{
+		EntityType: "dbobjectmgt",
+		EntityID:   fmt.Sprint(dbObjectMgt.ID),
+		Action:     "create",
+		After:      dbObjectMgt,
+	}); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return nil, err
 	}
@@ -754,6 +766,7 @@ func (s *dbObjectMgtService) Update(ctx context.Context, id uint, data models.DB
 		tx.Rollback()
 		return nil, fmt.Errorf("failed to get dbobjectmgt with id=%d: %w", id, err)
 	}
+	before := *existing
 
 	// Retrieve database management configuration
 	dbmgt, err := s.dbMgtRepo.GetByID(tx, existing.DBMgt)
@@ -815,7 +828,7 @@ func (s *dbObjectMgtService) Update(ctx context.Context, id uint, data models.DB
 		SetHost(cmt.IP).
 		SetPort(cmt.Port).
 		SetUser(cmt.Username).
-		SetPassword(cmt.Password).
+		SetCredentials(cmt.Password, cmt.SecretRef).
 		SetQuery(finalSQL)
 
 	// Oracle requires ServiceName as the database identifier for CDB/PDB routing
@@ -834,7 +847,8 @@ func (s *dbObjectMgtService) Update(ctx context.Context, id uint, data models.DB
 	}
 	logger.Debugf("Created agent command JSON payload (hex): %s", hexJSON)
 
-	_, err = agent.ExecuteSqlAgentAPI(ep.ClientID, ep.OsType, "execute", hexJSON, "", false)
+	audit.AddCommand(ctx, finalSQL)
+	_, err = agent.WithContext(ctx, s.agentExec).ExecuteSql(ep.ClientID, ep.OsType, "execute", hexJSON, "", false)
 	if err != nil {
 		tx.Rollback()
 		return nil, fmt.Errorf("failed to execute agent API command: %w", err)
@@ -848,6 +862,17 @@ func (s *dbObjectMgtService) Update(ctx context.Context, id uint, data models.DB
 		return nil, fmt.Errorf("failed to update dbobjectmgt id=%d: %w", id, err)
 	}
 
+	if err := audit.Record(ctx, tx, audit.Entry{
+		EntityType: "dbobjectmgt",
+		EntityID:   fmt.Sprint(id),
+		Action:     "update",
+		Before:     before,
+		After:      existing,
+	}); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return nil, err
 	}
@@ -943,7 +968,7 @@ func (s *dbObjectMgtService) Delete(ctx context.Context, id uint) error {
 		SetHost(cmt.IP).
 		SetPort(cmt.Port).
 		SetUser(cmt.Username).
-		SetPassword(cmt.Password).
+		SetCredentials(cmt.Password, cmt.SecretRef).
 		SetQuery(finalSQL)
 
 	// Oracle requires ServiceName as the database identifier for CDB/PDB routing
@@ -961,7 +986,8 @@ func (s *dbObjectMgtService) Delete(ctx context.Context, id uint) error {
 		return fmt.Errorf("failed to create agent command JSON for object deletion: %w", err)
 	}
 
-	_, err = agent.ExecuteSqlAgentAPI(ep.ClientID, ep.OsType, "execute", hexJSON, "", false)
+	audit.AddCommand(ctx, finalSQL)
+	_, err = agent.WithContext(ctx, s.agentExec).ExecuteSql(ep.ClientID, ep.OsType, "execute", hexJSON, "", false)
 	if err != nil {
 		tx.Rollback()
 		return fmt.Errorf("failed to execute agent API command: %w", err)
@@ -973,6 +999,16 @@ func (s *dbObjectMgtService) Delete(ctx context.Context, id uint) error {
 		return fmt.Errorf("failed to delete dbobjectmgt id=%d: %w", id, err)
 	}
 
+	if err := audit.Record(ctx, tx, audit.Entry{
+		EntityType: "dbobjectmgt",
+		EntityID:   fmt.Sprint(id),
+		Action:     "delete",
+		Before:     existing,
+	}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return err
 	}
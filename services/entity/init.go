@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"dbfartifactapi/services/agent"
+	"dbfartifactapi/services/job"
+)
+
+// Job kinds for object discovery jobs. Persisted with each job so the job monitor
+// can restore completion callbacks after an API restart.
+const (
+	JobKindObject         = "object"
+	JobKindCombinedObject = "combined_object"
+)
+
+// RegisterJobKinds registers the completion callbacks of this package with the job monitor.
+// Called at startup once the agent executor is chosen, before persisted jobs are restored.
+func RegisterJobKinds(agentExec agent.AgentExecutor) {
+	job.RegisterJobKind(JobKindObject, CreateObjectCompletionHandler(agentExec))
+	job.RegisterJobKind(JobKindCombinedObject, CreateCombinedObjectCompletionHandler(agentExec))
+}
+
+func init() {
+	job.RegisterContextType("object_context", &ObjectJobContext{})
+	job.RegisterContextType("combined_object_context", &CombinedObjectJobContext{})
+}
@@ -48,7 +48,7 @@ type objectGetResultsResponse struct {
 }
 
 // CreateObjectCompletionHandler creates a callback function for object job completion
-func CreateObjectCompletionHandler() job.JobCompletionCallback {
+func CreateObjectCompletionHandler(agentExec agent.AgentExecutor) job.JobCompletionCallback {
 	return func(jobID string, jobInfo *job.JobInfo, statusResp *job.StatusResponse) error {
 		logger.Infof("Processing object completion for job %s, status: %s", jobID, statusResp.Status)
 
@@ -60,7 +60,7 @@ func CreateObjectCompletionHandler() job.JobCompletionCallback {
 
 		// Process completed jobs regardless of completion method (polling or notification)
 		if statusResp.Status == "completed" {
-			return processObjectResults(jobID, contextData, statusResp, jobInfo)
+			return processObjectResults(agentExec, jobID, contextData, statusResp, jobInfo)
 		} else {
 			logger.Errorf("Object job %s failed, no objects will be created", jobID)
 			return fmt.Errorf("object job failed: %s", statusResp.Message)
@@ -69,7 +69,7 @@ func CreateObjectCompletionHandler() job.JobCompletionCallback {
 }
 
 // processObjectResults processes the results of a completed object job
-func processObjectResults(jobID string, contextData interface{}, statusResp *job.StatusResponse, jobInfo *job.JobInfo) error {
+func processObjectResults(agentExec agent.AgentExecutor, jobID string, contextData interface{}, statusResp *job.StatusResponse, jobInfo *job.JobInfo) error {
 	logger.Infof("Processing object results for job %s - completed: %d, failed: %d",
 		jobID, statusResp.Completed, statusResp.Failed)
 
@@ -85,7 +85,7 @@ func processObjectResults(jobID string, contextData interface{}, statusResp *job
 	}
 
 	// Legacy VeloArtifact polling flow
-	return processObjectResultsFromVeloArtifact(jobID, objectContext, statusResp)
+	return processObjectResultsFromVeloArtifact(agentExec, jobID, objectContext, statusResp)
 }
 
 // getEndpointForObjectJob retrieves endpoint information for job processing
@@ -108,11 +108,11 @@ func getEndpointForObjectJob(jobID string, endpointID uint) (*models.Endpoint, e
 }
 
 // retrieveObjectJobResults retrieves and parses job results from VeloArtifact
-func retrieveObjectJobResults(jobID string, endpoint *models.Endpoint) ([]ObjectResult, error) {
+func retrieveObjectJobResults(agentExec agent.AgentExecutor, jobID string, endpoint *models.Endpoint) ([]ObjectResult, error) {
 	logger.Debugf("Retrieving object results for job %s from endpoint %s", jobID, endpoint.ClientID)
 
 	// Get results from agent using getresults command
-	resultsOutput, err := agent.ExecuteAgentAPISimpleCommand(endpoint.ClientID, endpoint.OsType, "getresults", jobID, "", true)
+	resultsOutput, err := agentExec.ExecuteSimpleCommand(endpoint.ClientID, endpoint.OsType, "getresults", jobID, "", true)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get results for job %s: %w", jobID, err)
 	}
@@ -131,7 +131,7 @@ func retrieveObjectJobResults(jobID string, endpoint *models.Endpoint) ([]Object
 		jobID, getResultsResp.Completed, getResultsResp.Failed, getResultsResp.FilePath)
 
 	// Download results file from agent
-	downloadInfo, err := agent.DownloadFileAgentAPI(endpoint.ClientID, getResultsResp.FilePath, endpoint.OsType)
+	downloadInfo, err := agentExec.DownloadFile(endpoint.ClientID, getResultsResp.FilePath, endpoint.OsType)
 	if err != nil {
 		return nil, fmt.Errorf("failed to download results file for job %s: %w", jobID, err)
 	}
@@ -307,6 +307,12 @@ func createObjectsFromResults(jobID string, objectContext *ObjectJobContext, res
 		}
 	}
 
+	// Committed with the synchronized objects so a restart does not run the sync again
+	if err := job.GetJobMonitorService().MarkCallbackCommitted(tx, jobID); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
 	// Commit transaction atomically
 	if err := tx.Commit().Error; err != nil {
 		return 0, fmt.Errorf("failed to commit object synchronization transaction for job %s: %w", jobID, err)
@@ -439,7 +445,7 @@ func processObjectResultsFromNotification(jobID string, objectContext *ObjectJob
 }
 
 // processObjectResultsFromVeloArtifact handles object processing via traditional VeloArtifact polling
-func processObjectResultsFromVeloArtifact(jobID string, objectContext *ObjectJobContext, statusResp *job.StatusResponse) error {
+func processObjectResultsFromVeloArtifact(agentExec agent.AgentExecutor, jobID string, objectContext *ObjectJobContext, statusResp *job.StatusResponse) error {
 	logger.Infof("Processing object results from VeloArtifact polling for job %s", jobID)
 
 	jobMonitor := job.GetJobMonitorService()
@@ -452,7 +458,7 @@ func processObjectResultsFromVeloArtifact(jobID string, objectContext *ObjectJob
 	}
 
 	// Retrieve and download results file via VeloArtifact
-	resultsData, err := retrieveObjectJobResults(jobID, ep)
+	resultsData, err := retrieveObjectJobResults(agentExec, jobID, ep)
 	if err != nil {
 		jobMonitor.FailJobAfterProcessing(jobID, err.Error())
 		return err
@@ -478,7 +484,7 @@ func processObjectResultsFromVeloArtifact(jobID string, objectContext *ObjectJob
 }
 
 // CreateCombinedObjectCompletionHandler creates a callback function for combined object job completion
-func CreateCombinedObjectCompletionHandler() job.JobCompletionCallback {
+func CreateCombinedObjectCompletionHandler(agentExec agent.AgentExecutor) job.JobCompletionCallback {
 	return func(jobID string, jobInfo *job.JobInfo, statusResp *job.StatusResponse) error {
 		logger.Infof("Processing combined object completion for job %s, status: %s", jobID, statusResp.Status)
 
@@ -489,7 +495,7 @@ func CreateCombinedObjectCompletionHandler() job.JobCompletionCallback {
 		}
 
 		if statusResp.Status == "completed" {
-			return processCombinedObjectResults(jobID, contextData, statusResp, jobInfo)
+			return processCombinedObjectResults(agentExec, jobID, contextData, statusResp, jobInfo)
 		} else {
 			logger.Errorf("Combined object job %s failed, no objects will be created", jobID)
 			return fmt.Errorf("combined object job failed: %s", statusResp.Message)
@@ -498,7 +504,7 @@ func CreateCombinedObjectCompletionHandler() job.JobCompletionCallback {
 }
 
 // processCombinedObjectResults processes the results of a completed combined object job
-func processCombinedObjectResults(jobID string, contextData interface{}, statusResp *job.StatusResponse, jobInfo *job.JobInfo) error {
+func processCombinedObjectResults(agentExec agent.AgentExecutor, jobID string, contextData interface{}, statusResp *job.StatusResponse, jobInfo *job.JobInfo) error {
 	logger.Infof("Processing combined object results for job %s - completed: %d, failed: %d",
 		jobID, statusResp.Completed, statusResp.Failed)
 
@@ -514,7 +520,7 @@ func processCombinedObjectResults(jobID string, contextData interface{}, statusR
 	}
 
 	// Legacy VeloArtifact polling flow
-	return processCombinedObjectResultsFromVeloArtifact(jobID, combinedContext, statusResp)
+	return processCombinedObjectResultsFromVeloArtifact(agentExec, jobID, combinedContext, statusResp)
 }
 
 // createCombinedObjectsFromResults processes combined query results and synchronizes objects atomically across all databases
@@ -672,6 +678,12 @@ func createCombinedObjectsFromResults(jobID string, combinedContext *CombinedObj
 		}
 	}
 
+	// Committed with the synchronized objects so a restart does not run the sync again
+	if err := job.GetJobMonitorService().MarkCallbackCommitted(tx, jobID); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
 	// Commit transaction atomically for all databases
 	if err := tx.Commit().Error; err != nil {
 		return 0, fmt.Errorf("failed to commit combined object synchronization transaction for job %s: %w", jobID, err)
@@ -796,7 +808,7 @@ func processCombinedObjectResultsFromNotification(jobID string, combinedContext
 }
 
 // processCombinedObjectResultsFromVeloArtifact handles combined object processing via traditional VeloArtifact polling
-func processCombinedObjectResultsFromVeloArtifact(jobID string, combinedContext *CombinedObjectJobContext, statusResp *job.StatusResponse) error {
+func processCombinedObjectResultsFromVeloArtifact(agentExec agent.AgentExecutor, jobID string, combinedContext *CombinedObjectJobContext, statusResp *job.StatusResponse) error {
 	logger.Infof("Processing combined object results from VeloArtifact polling for job %s", jobID)
 
 	jobMonitor := job.GetJobMonitorService()
@@ -809,7 +821,7 @@ func processCombinedObjectResultsFromVeloArtifact(jobID string, combinedContext
 	}
 
 	// Retrieve and download results file via VeloArtifact
-	resultsData, err := retrieveObjectJobResults(jobID, ep)
+	resultsData, err := retrieveObjectJobResults(agentExec, jobID, ep)
 	if err != nil {
 		jobMonitor.FailJobAfterProcessing(jobID, err.Error())
 		return err
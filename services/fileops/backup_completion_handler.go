@@ -68,6 +68,12 @@ func CreateBackupSubJobCompletionHandler(masterJobID string) job.JobCompletionCa
 			return fmt.Errorf("failed to process sub-job %s: master job %s not found", subJobID, masterJobID)
 		}
 
+		// Cancelled master job already reported its final state - skip aggregation
+		if masterJob.Status == "cancelling" || masterJob.Status == "cancelled" {
+			logger.Infof("Master backup job %s was cancelled, skipping aggregation for sub-job %s", masterJobID, subJobID)
+			return nil
+		}
+
 		// CRITICAL: OS job IDs required for progress calculation
 		osJobIDs, ok := masterJob.ContextData["os_job_ids"].([]string)
 		if !ok {
@@ -0,0 +1,142 @@
+package fileops
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"dbfartifactapi/config"
+	"dbfartifactapi/models"
+	"dbfartifactapi/pkg/fakeagent"
+	"dbfartifactapi/repository"
+	"dbfartifactapi/services/agent"
+	"dbfartifactapi/services/job"
+)
+
+// TestMain sets the configuration once, before the job monitor starts reading it
+func TestMain(m *testing.M) {
+	config.Cfg.AgentMaxRetries = 1
+	config.Cfg.AgentExecutionTimeout = 5 * time.Second
+	// OS sub-jobs finish through the monitor polling checkstatus on the agent
+	config.Cfg.JobMonitorInterval = 50 * time.Millisecond
+	os.Exit(m.Run())
+}
+
+// TestBackupFlow_FakeAgent tests a dump backup with a SQL and an OS step: the SQL step runs at once, the OS step
+// starts as an os_execute --background job that the job monitor polls with checkstatus until the sub-job
+// callback completes the master job
+func TestBackupFlow_FakeAgent(t *testing.T) {
+	configDB, err := fakeagent.NewConfigDB(context.Background(), &models.CntMgt{}, &models.Endpoint{})
+	if err != nil {
+		t.Fatalf("Failed to start config database: %v", err)
+	}
+	t.Cleanup(func() { configDB.Close() })
+	prevDB := config.DB
+	config.DB = configDB.DB
+	t.Cleanup(func() { config.DB = prevDB })
+
+	var mu sync.Mutex
+	var commands []string
+	fa, err := fakeagent.New(context.Background(), fakeagent.Options{
+		QueryDir:   t.TempDir(),
+		ResultsDir: t.TempDir(),
+		Token:      "secret",
+		OSCommand: func(command string) (string, error) {
+			mu.Lock()
+			commands = append(commands, command)
+			mu.Unlock()
+			return "dump written", nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to start fake agent: %v", err)
+	}
+	t.Cleanup(func() { fa.Close() })
+	server := httptest.NewServer(fa.Handler())
+	t.Cleanup(server.Close)
+	executor := agent.NewHTTPAgentExecutor(server.URL, "secret")
+
+	for _, record := range []interface{}{
+		&models.Endpoint{ID: 1, ClientID: "L.0050", OsType: "linux"},
+		&models.CntMgt{ID: 7, CntType: "mysql", IP: "127.0.0.1", Port: 3306, Username: "root", Agent: 1},
+	} {
+		if err := configDB.DB.Create(record).Error; err != nil {
+			t.Fatalf("Failed to seed %T: %v", record, err)
+		}
+	}
+
+	jobMonitor := job.GetJobMonitorService()
+	jobMonitor.SetAgentExecutor(executor)
+	svc := &backupService{
+		baseRepo:     repository.NewBaseRepository(),
+		cntMgtRepo:   repository.NewCntMgtRepository(),
+		endpointRepo: repository.NewEndpointRepository(),
+		agentExec:    executor,
+	}
+
+	dumpCommand := "mysqldump appdb > /backup/backup_20240101.sql"
+	command, _ := json.Marshal(models.BackupCommand{Steps: []models.BackupStep{
+		{Order: 1, Command: "SELECT 1", Type: "sql"},
+		{Order: 2, Command: dumpCommand, Type: "os"},
+	}})
+	masterJobID, message, err := svc.ExecuteBackup(context.Background(), models.BackupRequest{
+		JobID:    42,
+		CntID:    7,
+		Command:  hex.EncodeToString(command),
+		Type:     "dump",
+		FileName: "backup_20240101.sql",
+	})
+	if err != nil {
+		t.Fatalf("ExecuteBackup() error = %v", err)
+	}
+	t.Cleanup(func() { jobMonitor.RemoveJob(masterJobID) })
+	if masterJobID != "backup_42" || message != "Backup job started successfully with 2 steps (1 SQL completed, 1 OS monitoring)" {
+		t.Fatalf("ExecuteBackup() = %q, %q", masterJobID, message)
+	}
+
+	master, _ := jobMonitor.GetJob(masterJobID)
+	osJobIDs, _ := master.ContextData["os_job_ids"].([]string)
+	if len(osJobIDs) != 1 {
+		t.Fatalf("os_job_ids = %v, want one sub-job", master.ContextData["os_job_ids"])
+	}
+	t.Cleanup(func() { jobMonitor.RemoveJob(osJobIDs[0]) })
+
+	// The master job's own callback runs after it completes and files the SQL results by step
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		master, _ = jobMonitor.GetJob(masterJobID)
+		if _, filed := master.Results.(map[string]interface{}); master.Status == "failed" || filed {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if master.Status != "completed" || master.Message != "Backup completed successfully with 2 steps (1 SQL completed, 1 OS completed)" {
+		t.Fatalf("Master job = %s %q (error %q), want completed with both steps", master.Status, master.Message, master.Error)
+	}
+	if step, _ := master.Results.(map[string]interface{})["step_1"].(map[string]interface{}); step["row_count"] != float64(1) {
+		t.Errorf("Master results = %v, want the SQL step result under step_1", master.Results)
+	}
+
+	// The sub-job keeps the OS result checkstatus reported
+	sub, _ := jobMonitor.GetJob(osJobIDs[0])
+	if sub.Status != "completed" {
+		t.Errorf("Sub-job %s status = %q, want completed", osJobIDs[0], sub.Status)
+	}
+	results, _ := sub.Results.([]interface{})
+	if len(results) != 1 {
+		t.Fatalf("Sub-job results = %#v, want the OS step result", sub.Results)
+	}
+	if result, _ := results[0].(map[string]interface{}); result["command"] != dumpCommand || result["output"] != "dump written" {
+		t.Errorf("OS result = %v, want the dump command and its output", results[0])
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(commands) != 1 || commands[0] != dumpCommand {
+		t.Errorf("Agent ran %q, want only the dump command", commands)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"dbfartifactapi/pkg/logger"
 	"dbfartifactapi/repository"
 	"dbfartifactapi/services/agent"
+	"dbfartifactapi/services/audit"
 	"dbfartifactapi/services/dto"
 	"dbfartifactapi/services/job"
 	"dbfartifactapi/utils"
@@ -34,6 +35,7 @@ type backupService struct {
 	baseRepo     repository.BaseRepository
 	cntMgtRepo   repository.CntMgtRepository
 	endpointRepo repository.EndpointRepository
+	agentExec    agent.AgentExecutor
 }
 
 // NewBackupService creates a new database backup service instance.
@@ -42,6 +44,7 @@ func NewBackupService() BackupService {
 		baseRepo:     repository.NewBaseRepository(),
 		cntMgtRepo:   repository.NewCntMgtRepository(),
 		endpointRepo: repository.NewEndpointRepository(),
+		agentExec:    agent.DefaultExecutor(),
 	}
 }
 
@@ -158,7 +161,8 @@ func (s *backupService) ExecuteBackup(ctx context.Context, req models.BackupRequ
 					tx.Rollback()
 					return "", "", fmt.Errorf("failed to create OS artifact for step %d: %v", step.Order, osErr)
 				}
-				stdout, err = agent.ExecuteSqlAgentAPI(ep.ClientID, ep.OsType, "os_execute", hexJSON, osOption, true)
+				audit.AddCommand(ctx, step.Command)
+				stdout, err = agent.WithContext(ctx, s.agentExec).ExecuteSql(ep.ClientID, ep.OsType, "os_execute", hexJSON, osOption, true)
 			} else if step.Type == "sql" {
 				// SQL command: result available immediately in response
 				hexJSON, sqlErr := s.createSQLExecuteArtifact(step.Command, cmt)
@@ -166,7 +170,8 @@ func (s *backupService) ExecuteBackup(ctx context.Context, req models.BackupRequ
 					tx.Rollback()
 					return "", "", fmt.Errorf("failed to create SQL artifact for step %d: %v", step.Order, sqlErr)
 				}
-				stdout, err = agent.ExecuteSqlAgentAPI(ep.ClientID, ep.OsType, "execute", hexJSON, "", true)
+				audit.AddCommand(ctx, step.Command)
+				stdout, err = agent.WithContext(ctx, s.agentExec).ExecuteSql(ep.ClientID, ep.OsType, "execute", hexJSON, "", true)
 			} else {
 				tx.Rollback()
 				return "", "", fmt.Errorf("unsupported step type: %s", step.Type)
@@ -228,8 +233,7 @@ func (s *backupService) ExecuteBackup(ctx context.Context, req models.BackupRequ
 		// If only SQL steps (no OS jobs), mark job completed immediately
 		if len(osJobIDs) == 0 {
 			// Create master job entry for querying
-			completionCallback := CreateBackupCompletionHandler()
-			jobMonitor.AddJobWithCallback(masterJobID, req.JobID, ep.ClientID, ep.OsType, completionCallback, contextData)
+			jobMonitor.AddJobWithKind(ctx, masterJobID, JobKindBackup, req.JobID, ep.ClientID, ep.OsType, contextData)
 
 			message := fmt.Sprintf("Backup completed with %d SQL steps (all executed immediately)", len(sqlStepResults))
 			if err := jobMonitor.CompleteJobImmediately(masterJobID, message, len(sqlStepResults)); err != nil {
@@ -239,8 +243,7 @@ func (s *backupService) ExecuteBackup(ctx context.Context, req models.BackupRequ
 		} else {
 			// Add master job entry but mark as processed to skip VeloArtifact status check
 			// Master job is just for tracking, real jobs are OS sub-jobs
-			completionCallback := CreateBackupCompletionHandler()
-			jobMonitor.AddJobWithCallback(masterJobID, req.JobID, ep.ClientID, ep.OsType, completionCallback, contextData)
+			jobMonitor.AddJobWithKind(ctx, masterJobID, JobKindBackup, req.JobID, ep.ClientID, ep.OsType, contextData)
 
 			// Mark master job to skip VeloArtifact polling since it doesn't exist on VeloArtifact
 			// We'll monitor OS sub-jobs instead
@@ -249,9 +252,8 @@ func (s *backupService) ExecuteBackup(ctx context.Context, req models.BackupRequ
 			}
 
 			// Add each OS sub-job to monitoring with callback that tracks master job progress
-			subJobCallback := CreateBackupSubJobCompletionHandler(masterJobID)
 			for _, osJobID := range osJobIDs {
-				jobMonitor.AddJobWithCallback(osJobID, req.JobID, ep.ClientID, ep.OsType, subJobCallback, contextData)
+				jobMonitor.AddJobWithKind(ctx, osJobID, JobKindBackupSubJob, req.JobID, ep.ClientID, ep.OsType, contextData)
 				logger.Infof("Added OS sub-job to monitoring: job_id=%s, master=%s", osJobID, masterJobID)
 			}
 
@@ -283,7 +285,8 @@ func (s *backupService) ExecuteBackup(ctx context.Context, req models.BackupRequ
 				return "", "", fmt.Errorf("failed to create OS artifact for step %d: %v", step.Order, osErr)
 			}
 
-			stdout, err := agent.ExecuteSqlAgentAPI(ep.ClientID, ep.OsType, "os_execute", hexJSON, osOption, true)
+			audit.AddCommand(ctx, step.Command)
+			stdout, err := agent.WithContext(ctx, s.agentExec).ExecuteSql(ep.ClientID, ep.OsType, "os_execute", hexJSON, osOption, true)
 			if err != nil {
 				logger.Errorf("executeSqlAgentAPI error for OS step %d: %v", step.Order, err)
 				tx.Rollback()
@@ -317,8 +320,7 @@ func (s *backupService) ExecuteBackup(ctx context.Context, req models.BackupRequ
 		contextData["master_job_id"] = masterJobID
 
 		jobMonitor := job.GetJobMonitorService()
-		completionCallback := CreateBackupCompletionHandler()
-		jobMonitor.AddJobWithCallback(masterJobID, req.JobID, ep.ClientID, ep.OsType, completionCallback, contextData)
+		jobMonitor.AddJobWithKind(ctx, masterJobID, JobKindBackup, req.JobID, ep.ClientID, ep.OsType, contextData)
 
 		// Mark master job to skip VeloArtifact polling - we monitor OS sub-jobs instead
 		if err := jobMonitor.MarkJobAsNoPolling(masterJobID); err != nil {
@@ -326,9 +328,8 @@ func (s *backupService) ExecuteBackup(ctx context.Context, req models.BackupRequ
 		}
 
 		// Add each OS sub-job to monitoring
-		subJobCallback := CreateBackupSubJobCompletionHandler(masterJobID)
 		for _, osJobID := range osJobIDs {
-			jobMonitor.AddJobWithCallback(osJobID, req.JobID, ep.ClientID, ep.OsType, subJobCallback, contextData)
+			jobMonitor.AddJobWithKind(ctx, osJobID, JobKindBackupSubJob, req.JobID, ep.ClientID, ep.OsType, contextData)
 			logger.Infof("Added OS sub-job to monitoring: job_id=%s, master=%s", osJobID, masterJobID)
 		}
 
@@ -352,7 +353,8 @@ func (s *backupService) ExecuteBackup(ctx context.Context, req models.BackupRequ
 			}
 
 			// Execute this step immediately
-			stdout, err := agent.ExecuteSqlAgentAPI(ep.ClientID, ep.OsType, "execute", hexJSON, "", true)
+			audit.AddCommand(ctx, step.Command)
+			stdout, err := agent.WithContext(ctx, s.agentExec).ExecuteSql(ep.ClientID, ep.OsType, "execute", hexJSON, "", true)
 			if err != nil {
 				logger.Errorf("executeSqlAgentAPI error for step %d: %v", step.Order, err)
 				tx.Rollback()
@@ -398,8 +400,7 @@ func (s *backupService) ExecuteBackup(ctx context.Context, req models.BackupRequ
 
 		// Add job to monitoring and mark completed immediately
 		jobMonitor := job.GetJobMonitorService()
-		completionCallback := CreateBackupCompletionHandler()
-		jobMonitor.AddJobWithCallback(masterJobID, req.JobID, ep.ClientID, ep.OsType, completionCallback, contextData)
+		jobMonitor.AddJobWithKind(ctx, masterJobID, JobKindBackup, req.JobID, ep.ClientID, ep.OsType, contextData)
 
 		message := fmt.Sprintf("Backup completed with %d SQL steps (all executed immediately)", len(sqlStepResults))
 		if err := jobMonitor.CompleteJobImmediately(masterJobID, message, len(sqlStepResults)); err != nil {
@@ -447,7 +448,7 @@ func (s *backupService) createSQLExecuteArtifact(command string, cmt *models.Cnt
 		SetHost(cmt.IP).
 		SetPort(cmt.Port).
 		SetUser(cmt.Username).
-		SetPassword(cmt.Password)
+		SetCredentials(cmt.Password, cmt.SecretRef)
 
 	// Oracle requires ServiceName for connection instead of database name
 	if strings.ToLower(cmt.CntType) == "oracle" {
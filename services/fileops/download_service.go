@@ -12,6 +12,7 @@ import (
 	"dbfartifactapi/pkg/logger"
 	"dbfartifactapi/repository"
 	"dbfartifactapi/services/agent"
+	"dbfartifactapi/services/audit"
 	"dbfartifactapi/services/job"
 	"dbfartifactapi/utils"
 )
@@ -25,6 +26,7 @@ type downloadService struct {
 	baseRepo     repository.BaseRepository
 	cntMgtRepo   repository.CntMgtRepository
 	endpointRepo repository.EndpointRepository
+	agentExec    agent.AgentExecutor
 }
 
 // NewDownloadService creates a new file download service instance.
@@ -33,6 +35,7 @@ func NewDownloadService() DownloadService {
 		baseRepo:     repository.NewBaseRepository(),
 		cntMgtRepo:   repository.NewCntMgtRepository(),
 		endpointRepo: repository.NewEndpointRepository(),
+		agentExec:    agent.DefaultExecutor(),
 	}
 }
 
@@ -85,7 +88,8 @@ func (s *downloadService) ExecuteDownload(ctx context.Context, req models.Downlo
 	logger.Infof("Executing download: source=%s, save_path=%s, file_name=%s, compressed=%v",
 		req.SourcePath, req.SavePath, fileName, isCompressed)
 
-	stdout, err := agent.ExecuteSqlAgentAPI(ep.ClientID, ep.OsType, "filedownload", hexJSON, "--background", true)
+	audit.AddCommand(ctx, fmt.Sprintf("filedownload source=%s save_path=%s", req.SourcePath, req.SavePath))
+	stdout, err := agent.WithContext(ctx, s.agentExec).ExecuteSql(ep.ClientID, ep.OsType, "filedownload", hexJSON, "--background", true)
 	if err != nil {
 		logger.Errorf("executeSqlAgentAPI error for filedownload: %v", err)
 		// Clean up archive if command failed
@@ -127,7 +131,6 @@ func (s *downloadService) ExecuteDownload(ctx context.Context, req models.Downlo
 	}
 
 	// Add download job to monitoring with completion handler for cleanup
-	completionCallback := CreateDownloadCompletionHandler()
 	contextData := map[string]interface{}{
 		"download_context": &DownloadJobContext{
 			ArchivePath:  archivePath,
@@ -135,7 +138,7 @@ func (s *downloadService) ExecuteDownload(ctx context.Context, req models.Downlo
 		},
 	}
 	jobMonitor := job.GetJobMonitorService()
-	jobMonitor.AddJobWithCallback(jobResp.JobID, 0, ep.ClientID, ep.OsType, completionCallback, contextData)
+	jobMonitor.AddJobWithKind(ctx, jobResp.JobID, JobKindDownload, 0, ep.ClientID, ep.OsType, contextData)
 
 	logger.Infof("Download job started successfully: job_id=%s", jobResp.JobID)
 
@@ -0,0 +1,36 @@
+package fileops
+
+import (
+	"fmt"
+
+	"dbfartifactapi/services/job"
+)
+
+// Job kinds for file operation jobs. Persisted with each job so the job monitor
+// can restore completion callbacks after an API restart.
+const (
+	JobKindBackup       = "backup"
+	JobKindBackupSubJob = "backup_subjob"
+	JobKindUpload       = "upload"
+	JobKindDownload     = "download"
+)
+
+func init() {
+	job.RegisterJobKind(JobKindBackup, CreateBackupCompletionHandler())
+	job.RegisterJobKind(JobKindBackupSubJob, func(subJobID string, jobInfo *job.JobInfo, statusResp *job.StatusResponse) error {
+		// Sub-jobs share the master job context, which carries master_job_id for both dump and os backups
+		masterJobID, ok := jobInfo.ContextData["master_job_id"].(string)
+		if !ok || masterJobID == "" {
+			return fmt.Errorf("missing master_job_id in context for backup sub-job %s", subJobID)
+		}
+		return CreateBackupSubJobCompletionHandler(masterJobID)(subJobID, jobInfo, statusResp)
+	})
+	job.RegisterJobKind(JobKindUpload, CreateUploadCompletionHandler())
+	job.RegisterJobKind(JobKindDownload, CreateDownloadCompletionHandler())
+
+	job.RegisterContextType("backup_context", &BackupJobContext{})
+	job.RegisterContextType("os_job_ids", []string{})
+	job.RegisterContextType("sql_step_results", []map[string]interface{}{})
+	job.RegisterContextType("upload_context", &UploadJobContext{})
+	job.RegisterContextType("download_context", &DownloadJobContext{})
+}
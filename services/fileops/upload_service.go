@@ -10,6 +10,7 @@ import (
 	"dbfartifactapi/pkg/logger"
 	"dbfartifactapi/repository"
 	"dbfartifactapi/services/agent"
+	"dbfartifactapi/services/audit"
 	"dbfartifactapi/services/job"
 	"dbfartifactapi/utils"
 )
@@ -23,6 +24,7 @@ type uploadService struct {
 	baseRepo     repository.BaseRepository
 	cntMgtRepo   repository.CntMgtRepository
 	endpointRepo repository.EndpointRepository
+	agentExec    agent.AgentExecutor
 }
 
 // NewUploadService creates a new file upload service instance.
@@ -31,6 +33,7 @@ func NewUploadService() UploadService {
 		baseRepo:     repository.NewBaseRepository(),
 		cntMgtRepo:   repository.NewCntMgtRepository(),
 		endpointRepo: repository.NewEndpointRepository(),
+		agentExec:    agent.DefaultExecutor(),
 	}
 }
 
@@ -84,7 +87,8 @@ func (s *uploadService) ExecuteUpload(ctx context.Context, req models.UploadRequ
 
 	logger.Infof("Executing upload for sourceJobId=%s, fileName=%s, filePath=%s", req.SourceJobID, req.FileName, req.FilePath)
 
-	stdout, err := agent.ExecuteSqlAgentAPI(ep.ClientID, ep.OsType, "upload", hexJSON, uploadOption, true)
+	audit.AddCommand(ctx, fmt.Sprintf("upload source_job_id=%s file=%s path=%s", req.SourceJobID, req.FileName, req.FilePath))
+	stdout, err := agent.WithContext(ctx, s.agentExec).ExecuteSql(ep.ClientID, ep.OsType, "upload", hexJSON, uploadOption, true)
 	if err != nil {
 		logger.Errorf("executeSqlAgentAPI error for upload: %v", err)
 		tx.Rollback()
@@ -115,7 +119,6 @@ func (s *uploadService) ExecuteUpload(ctx context.Context, req models.UploadRequ
 	}
 
 	// Add upload job to monitoring with completion handler for storing file metadata
-	completionCallback := CreateUploadCompletionHandler()
 	contextData := map[string]interface{}{
 		"upload_context": &UploadJobContext{
 			FileName:    req.FileName,
@@ -124,7 +127,7 @@ func (s *uploadService) ExecuteUpload(ctx context.Context, req models.UploadRequ
 		},
 	}
 	jobMonitor := job.GetJobMonitorService()
-	jobMonitor.AddJobWithCallback(jobResp.JobID, 0, ep.ClientID, ep.OsType, completionCallback, contextData)
+	jobMonitor.AddJobWithKind(ctx, jobResp.JobID, JobKindUpload, 0, ep.ClientID, ep.OsType, contextData)
 
 	logger.Infof("Upload job started successfully: job_id=%s", jobResp.JobID)
 
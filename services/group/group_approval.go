@@ -0,0 +1,74 @@
+package group
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"dbfartifactapi/models"
+	"dbfartifactapi/services/approval"
+)
+
+// DescribeChange previews a group change and collects what the approval check needs: the
+// policies whose grants it adds or removes and the number of actors it adds or removes.
+// Changing actors grants or revokes every policy of the group, so those count as touched too.
+func (s *groupManagementService) DescribeChange(ctx context.Context, groupID uint, change string, request *GroupAssignmentsUpdateRequest) (*approval.Change, error) {
+	preview, err := s.PreviewGroupChange(ctx, groupID, change, request)
+	if err != nil {
+		return nil, err
+	}
+
+	policyIDs := append(append([]uint{}, preview.PoliciesToAdd...), preview.PoliciesToRemove...)
+	actorCount := len(preview.ActorsToAdd) + len(preview.ActorsToRemove)
+	if actorCount > 0 {
+		currentPolicyIDs, err := s.getCurrentPolicyIDs(groupID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current policy assignments: %v", err)
+		}
+		policyIDs = append(policyIDs, currentPolicyIDs...)
+	}
+
+	var policies []models.DBGroupListPolicies
+	for _, policyID := range removeDuplicateUints(policyIDs) {
+		policy, err := s.groupListPoliciesRepo.GetByID(nil, policyID)
+		if err != nil {
+			return nil, fmt.Errorf("policy with id=%d not found: %v", policyID, err)
+		}
+		policies = append(policies, *policy)
+	}
+
+	return &approval.Change{
+		Operation:  change,
+		GroupID:    groupID,
+		Request:    request,
+		Preview:    preview,
+		Policies:   policies,
+		ActorCount: actorCount,
+	}, nil
+}
+
+// ChangeRequestExecutor applies approved group change requests through the same service
+// methods the group endpoints call.
+func ChangeRequestExecutor(s GroupManagementService) approval.Executor {
+	return func(ctx context.Context, cr *models.ChangeRequest) (interface{}, error) {
+		var request GroupAssignmentsUpdateRequest
+		if err := json.Unmarshal([]byte(cr.Payload), &request); err != nil {
+			return nil, fmt.Errorf("failed to decode change request payload: %v", err)
+		}
+
+		switch cr.Operation {
+		case ChangeUpdateAssignments:
+			return s.UpdateGroupAssignments(ctx, cr.GroupID, &request)
+		case ChangeAssignPolicies:
+			return nil, s.AssignPoliciesToGroup(ctx, cr.GroupID, request.PolicyIDs)
+		case ChangeRemovePolicies:
+			return nil, s.RemovePoliciesFromGroup(ctx, cr.GroupID, request.PolicyIDs)
+		case ChangeAssignActors:
+			return s.AssignActorsToGroup(ctx, cr.GroupID, request.ActorIDs)
+		case ChangeRemoveActors:
+			return s.RemoveActorsFromGroup(ctx, cr.GroupID, request.ActorIDs)
+		default:
+			return nil, fmt.Errorf("unsupported group change %q", cr.Operation)
+		}
+	}
+}
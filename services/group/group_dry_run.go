@@ -0,0 +1,164 @@
+package group
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"dbfartifactapi/services/dto"
+)
+
+// Group changes PreviewGroupChange can preview, named after the audit action of each operation.
+const (
+	ChangeUpdateAssignments = "update_assignments"
+	ChangeAssignPolicies    = "assign_policies"
+	ChangeRemovePolicies    = "remove_policies"
+	ChangeAssignActors      = "assign_actors"
+	ChangeRemoveActors      = "remove_actors"
+)
+
+// GroupDryRunResult previews a group change: the assignments it would add and remove and the
+// batch SQL it would send to each connection.
+type GroupDryRunResult struct {
+	DryRun           bool                `json:"dry_run"`
+	GroupID          uint                `json:"group_id"`
+	Change           string              `json:"change"`
+	PoliciesToAdd    []uint              `json:"policies_to_add"`
+	PoliciesToRemove []uint              `json:"policies_to_remove"`
+	ActorsToAdd      []uint              `json:"actors_to_add"`
+	ActorsToRemove   []uint              `json:"actors_to_remove"`
+	Commands         []dto.DryRunCommand `json:"commands"`
+	TotalExecutions  int                 `json:"total_executions"`
+}
+
+// PreviewGroupChange computes what a group change would do against the current assignments,
+// using the same diff and VeloArtifact execution plan as the change itself, without sending
+// anything to the agents or writing to the database.
+// Only PolicyIDs is read for policy changes and only ActorIDs for actor changes.
+func (s *groupManagementService) PreviewGroupChange(ctx context.Context, groupID uint, change string, request *GroupAssignmentsUpdateRequest) (*GroupDryRunResult, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("context cannot be nil")
+	}
+	if groupID == 0 {
+		return nil, fmt.Errorf("invalid group ID: must be greater than 0")
+	}
+	if request == nil {
+		return nil, fmt.Errorf("request cannot be nil")
+	}
+
+	if _, err := s.groupRepo.GetByID(nil, groupID); err != nil {
+		return nil, fmt.Errorf("group with id=%d not found: %v", groupID, err)
+	}
+
+	currentPolicyIDs, err := s.getCurrentPolicyIDs(groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current policy assignments: %v", err)
+	}
+	currentActorIDs, err := s.getCurrentActorIDs(groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current actor assignments: %v", err)
+	}
+
+	targetPolicyIDs, targetActorIDs := currentPolicyIDs, currentActorIDs
+	sendsCommands := true
+	switch change {
+	case ChangeUpdateAssignments:
+		if len(request.PolicyIDs) == 0 && len(request.ActorIDs) == 0 {
+			return nil, fmt.Errorf("at least one policy or actor ID must be provided")
+		}
+		targetPolicyIDs, targetActorIDs = request.PolicyIDs, request.ActorIDs
+	case ChangeAssignPolicies:
+		if len(request.PolicyIDs) == 0 {
+			return nil, fmt.Errorf("policy IDs list cannot be empty")
+		}
+		for _, policyID := range request.PolicyIDs {
+			if _, err := s.groupListPoliciesRepo.GetByID(nil, policyID); err != nil {
+				return nil, fmt.Errorf("policy with id=%d not found: %v", policyID, err)
+			}
+		}
+		targetPolicyIDs = removeDuplicateUints(append(append([]uint{}, currentPolicyIDs...), request.PolicyIDs...))
+		sendsCommands = false // AssignPoliciesToGroup does not send VeloArtifact jobs yet
+	case ChangeRemovePolicies:
+		if len(request.PolicyIDs) == 0 {
+			return nil, fmt.Errorf("policy IDs list cannot be empty")
+		}
+		targetPolicyIDs = subtractUints(currentPolicyIDs, request.PolicyIDs)
+		sendsCommands = false // RemovePoliciesFromGroup does not send VeloArtifact jobs yet
+	case ChangeAssignActors:
+		if len(request.ActorIDs) == 0 {
+			return nil, fmt.Errorf("actor IDs list cannot be empty")
+		}
+		for _, actorID := range request.ActorIDs {
+			if _, err := s.actorMgtRepo.GetByID(nil, actorID); err != nil {
+				return nil, fmt.Errorf("actor with id=%d not found: %v", actorID, err)
+			}
+		}
+		targetActorIDs = removeDuplicateUints(append(append([]uint{}, currentActorIDs...), request.ActorIDs...))
+	case ChangeRemoveActors:
+		if len(request.ActorIDs) == 0 {
+			return nil, fmt.Errorf("actor IDs list cannot be empty")
+		}
+		targetActorIDs = subtractUints(currentActorIDs, request.ActorIDs)
+	default:
+		return nil, fmt.Errorf("unsupported group change %q", change)
+	}
+
+	diff := s.calculateDiff(currentPolicyIDs, currentActorIDs, targetPolicyIDs, targetActorIDs)
+	result := &GroupDryRunResult{
+		DryRun:           true,
+		GroupID:          groupID,
+		Change:           change,
+		PoliciesToAdd:    nonNilUints(diff.PoliciesToAdd),
+		PoliciesToRemove: nonNilUints(diff.PoliciesToRemove),
+		ActorsToAdd:      nonNilUints(diff.ActorsToAdd),
+		ActorsToRemove:   nonNilUints(diff.ActorsToRemove),
+		Commands:         []dto.DryRunCommand{},
+	}
+	if !sendsCommands {
+		return result, nil
+	}
+
+	// Assigning or removing actors keeps the policies, so the plan allows or denies the current ones
+	executions := s.optimizeVeloExecutions(groupID, &diff, currentPolicyIDs, targetPolicyIDs)
+	for _, execution := range executions {
+		cmt, ep, err := s.connectionEndpoint(execution.ConnectionID)
+		if err != nil {
+			return nil, err
+		}
+		command := dto.DryRunCommand{
+			CntMgtID:   cmt.ID,
+			CntType:    cmt.CntType,
+			Operation:  execution.Operation,
+			Endpoint:   *ep,
+			Statements: execution.BatchSQLCommands,
+		}
+		if strings.ToLower(cmt.CntType) == "oracle" {
+			command.Database = cmt.ServiceName
+		}
+		result.Commands = append(result.Commands, command)
+	}
+	result.TotalExecutions = len(executions)
+	return result, nil
+}
+
+// subtractUints returns the IDs of slice that are not in remove, keeping their order.
+func subtractUints(slice, remove []uint) []uint {
+	removed := make(map[uint]bool, len(remove))
+	for _, id := range remove {
+		removed[id] = true
+	}
+	var result []uint
+	for _, id := range slice {
+		if !removed[id] {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+func nonNilUints(ids []uint) []uint {
+	if ids == nil {
+		return []uint{}
+	}
+	return ids
+}
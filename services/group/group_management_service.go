@@ -13,6 +13,8 @@ import (
 	"dbfartifactapi/pkg/logger"
 	"dbfartifactapi/repository"
 	"dbfartifactapi/services/agent"
+	"dbfartifactapi/services/approval"
+	"dbfartifactapi/services/audit"
 	"dbfartifactapi/services/dto"
 	"dbfartifactapi/services/privilege/oracle"
 	"dbfartifactapi/utils"
@@ -47,6 +49,16 @@ type GroupManagementService interface {
 
 	// Optimized Bulk Update - updates both policies and actors with minimal VeloArtifact executions
 	UpdateGroupAssignments(ctx context.Context, groupID uint, request *GroupAssignmentsUpdateRequest) (*GroupAssignmentsUpdateResult, error)
+
+	// Dry run - computes the diff and agent SQL of a change without running or storing it
+	PreviewGroupChange(ctx context.Context, groupID uint, change string, request *GroupAssignmentsUpdateRequest) (*GroupDryRunResult, error)
+
+	// Change approval - describes a change for the approval check, see ChangeRequestExecutor for applying it
+	DescribeChange(ctx context.Context, groupID uint, change string, request *GroupAssignmentsUpdateRequest) (*approval.Change, error)
+
+	// Time-bound memberships - grants and revokes memberships whose valid_from or valid_until has passed
+	EnforceValidity(ctx context.Context, now time.Time) (*dto.ValidityRunResult, error)
+	ListMembershipTransitions(ctx context.Context, query dto.MembershipTransitionQuery) (*dto.MembershipTransitionListResponse, error)
 }
 
 // GroupInfo contains complete group information with policies and actors
@@ -134,10 +146,12 @@ type groupManagementService struct {
 	groupListPoliciesRepo repository.DBGroupListPoliciesRepository
 	actorMgtRepo          repository.DBActorMgtRepository
 	dbpolicyRepo          repository.DBPolicyRepository
+	transitionRepo        repository.MembershipTransitionRepository
 	// VeloArtifact dependencies
 	dbMgtRepo    repository.DBMgtRepository
 	cntMgtRepo   repository.CntMgtRepository
 	endpointRepo repository.EndpointRepository
+	agentExec    agent.AgentExecutor
 }
 
 // NewGroupManagementService creates a new group management service instance.
@@ -150,10 +164,12 @@ func NewGroupManagementService() GroupManagementService {
 		groupListPoliciesRepo: repository.NewDBGroupListPoliciesRepository(),
 		actorMgtRepo:          repository.NewDBActorMgtRepository(),
 		dbpolicyRepo:          repository.NewDBPolicyRepository(),
+		transitionRepo:        repository.NewMembershipTransitionRepository(),
 		// VeloArtifact dependencies
 		dbMgtRepo:    repository.NewDBMgtRepository(),
 		cntMgtRepo:   repository.NewCntMgtRepository(),
 		endpointRepo: repository.NewEndpointRepository(),
+		agentExec:    agent.DefaultExecutor(),
 	}
 }
 
@@ -198,6 +214,15 @@ func (s *groupManagementService) CreateGroup(ctx context.Context, group *models.
 		return nil, fmt.Errorf("failed to create group: %v", err)
 	}
 
+	if err := audit.Record(ctx, tx, audit.Entry{
+		EntityType: "group",
+		EntityID:   fmt.Sprint(group.ID),
+		Action:     "create",
+		After:      group,
+	}); err != nil {
+		return nil, err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %v", err)
 	}
@@ -228,6 +253,7 @@ func (s *groupManagementService) UpdateGroup(ctx context.Context, id uint, group
 	if err != nil {
 		return nil, fmt.Errorf("group with id=%d not found: %v", id, err)
 	}
+	before := *existingGroup
 
 	// Check if new code conflicts with other groups
 	if group.Code != existingGroup.Code {
@@ -253,6 +279,16 @@ func (s *groupManagementService) UpdateGroup(ctx context.Context, id uint, group
 		return nil, fmt.Errorf("failed to update group: %v", err)
 	}
 
+	if err := audit.Record(ctx, tx, audit.Entry{
+		EntityType: "group",
+		EntityID:   fmt.Sprint(id),
+		Action:     "update",
+		Before:     before,
+		After:      existingGroup,
+	}); err != nil {
+		return nil, err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %v", err)
 	}
@@ -320,6 +356,19 @@ func (s *groupManagementService) DeleteGroup(ctx context.Context, id uint) error
 		return fmt.Errorf("failed to delete group: %v", err)
 	}
 
+	if err := audit.Record(ctx, tx, audit.Entry{
+		EntityType: "group",
+		EntityID:   fmt.Sprint(id),
+		Action:     "delete",
+		Before: map[string]interface{}{
+			"group":    group,
+			"policies": policyGroups,
+			"actors":   actorGroups,
+		},
+	}); err != nil {
+		return err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return fmt.Errorf("failed to commit transaction: %v", err)
 	}
@@ -422,6 +471,15 @@ func (s *groupManagementService) AssignPoliciesToGroup(ctx context.Context, grou
 		}
 	}
 
+	if err := audit.Record(ctx, tx, audit.Entry{
+		EntityType: "group",
+		EntityID:   fmt.Sprint(groupID),
+		Action:     "assign_policies",
+		After:      map[string]interface{}{"policy_ids": policyIDs},
+	}); err != nil {
+		return err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return fmt.Errorf("failed to commit transaction: %v", err)
 	}
@@ -456,6 +514,15 @@ func (s *groupManagementService) RemovePoliciesFromGroup(ctx context.Context, gr
 		}
 	}
 
+	if err := audit.Record(ctx, tx, audit.Entry{
+		EntityType: "group",
+		EntityID:   fmt.Sprint(groupID),
+		Action:     "remove_policies",
+		Before:     map[string]interface{}{"policy_ids": policyIDs},
+	}); err != nil {
+		return err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return fmt.Errorf("failed to commit transaction: %v", err)
 	}
@@ -673,6 +740,20 @@ func (s *groupManagementService) AssignActorsToGroup(ctx context.Context, groupI
 		}
 	}
 
+	if err := audit.Record(ctx, tx, audit.Entry{
+		EntityType: "group",
+		EntityID:   fmt.Sprint(groupID),
+		Action:     "assign_actors",
+		After: map[string]interface{}{
+			"actor_ids":      newActorIDs,
+			"policy_ids":     currentPolicyIDs,
+			"velo_succeeded": veloResult.SuccessfulJobs,
+			"velo_failed":    veloResult.FailedJobs,
+		},
+	}); err != nil {
+		return nil, err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %v", err)
 	}
@@ -846,6 +927,22 @@ func (s *groupManagementService) RemoveActorsFromGroup(ctx context.Context, grou
 		}
 	}
 
+	if err := audit.Record(ctx, tx, audit.Entry{
+		EntityType: "group",
+		EntityID:   fmt.Sprint(groupID),
+		Action:     "remove_actors",
+		Before: map[string]interface{}{
+			"actor_ids":  activeActorIDs,
+			"policy_ids": currentPolicyIDs,
+		},
+		After: map[string]interface{}{
+			"velo_succeeded": veloResult.SuccessfulJobs,
+			"velo_failed":    veloResult.FailedJobs,
+		},
+	}); err != nil {
+		return nil, err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %v", err)
 	}
@@ -1049,6 +1146,19 @@ func (s *groupManagementService) UpdateGroupAssignments(ctx context.Context, gro
 	result.VeloJobsCreated = veloResult.SuccessfulJobs
 	result.TotalExecutions = veloResult.TotalAttempted
 
+	if err := audit.Record(ctx, tx, audit.Entry{
+		EntityType: "group",
+		EntityID:   fmt.Sprint(groupID),
+		Action:     "update_assignments",
+		Before: map[string]interface{}{
+			"policy_ids": currentPolicyIDs,
+			"actor_ids":  currentActorIDs,
+		},
+		After: result,
+	}); err != nil {
+		return nil, err
+	}
+
 	// Commit transaction only after successful VeloArtifact operations
 	if err := tx.Commit().Error; err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %v", err)
@@ -1591,16 +1701,9 @@ func (s *groupManagementService) executeBatchSQLForConnection(ctx context.Contex
 		return fmt.Sprintf("skipped_connection_%d_no_commands_%d", execution.ConnectionID, time.Now().Unix()), nil
 	}
 
-	// Get connection details
-	cmt, err := s.cntMgtRepo.GetCntMgtByID(nil, execution.ConnectionID)
-	if err != nil {
-		return "", fmt.Errorf("connection %d not found: %v", execution.ConnectionID, err)
-	}
-
-	// Get endpoint information
-	ep, err := s.endpointRepo.GetByID(nil, utils.MustIntToUint(cmt.Agent))
+	cmt, ep, err := s.connectionEndpoint(execution.ConnectionID)
 	if err != nil {
-		return "", fmt.Errorf("cannot find endpoint with id=%d for connection %d: %v", cmt.Agent, execution.ConnectionID, err)
+		return "", err
 	}
 
 	// Split batch into smaller chunks to avoid "argument list too long" error
@@ -1637,7 +1740,7 @@ func (s *groupManagementService) executeBatchSQLForConnection(ctx context.Contex
 			SetHost(cmt.IP).
 			SetPort(cmt.Port).
 			SetUser(cmt.Username).
-			SetPassword(cmt.Password)
+			SetCredentials(cmt.Password, cmt.SecretRef)
 
 		// Oracle requires ServiceName for connection
 		if strings.ToLower(cmt.CntType) == "oracle" && cmt.ServiceName != "" {
@@ -1659,7 +1762,8 @@ func (s *groupManagementService) executeBatchSQLForConnection(ctx context.Contex
 		}
 
 		// Execute via agent API with batch SQL
-		result, err := agent.ExecuteSqlAgentAPI(ep.ClientID, ep.OsType, "execute", hexJSON, "", false)
+		audit.AddCommand(ctx, combinedSQL)
+		result, err := agent.WithContext(ctx, s.agentExec).ExecuteSql(ep.ClientID, ep.OsType, "execute", hexJSON, "", false)
 		if err != nil {
 			return "", fmt.Errorf("executeSqlAgentAPI error for connection %d batch %d: %v", execution.ConnectionID, batchNum, err)
 		}
@@ -1683,6 +1787,20 @@ func (s *groupManagementService) executeBatchSQLForConnection(ctx context.Contex
 	return finalJobID, nil
 }
 
+// connectionEndpoint loads a connection and the agent endpoint that runs its commands.
+func (s *groupManagementService) connectionEndpoint(cntID uint) (*models.CntMgt, *models.Endpoint, error) {
+	cmt, err := s.cntMgtRepo.GetCntMgtByID(nil, cntID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("connection %d not found: %v", cntID, err)
+	}
+
+	ep, err := s.endpointRepo.GetByID(nil, utils.MustIntToUint(cmt.Agent))
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot find endpoint with id=%d for connection %d: %v", cmt.Agent, cntID, err)
+	}
+	return cmt, ep, nil
+}
+
 // Helper functions for dbpolicy synchronization
 
 // PolicyOperationType defines the type of policy operation for extraction logic.
@@ -0,0 +1,355 @@
+package group
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"dbfartifactapi/models"
+	"dbfartifactapi/pkg/logger"
+	"dbfartifactapi/services/audit"
+	"dbfartifactapi/services/dto"
+	"dbfartifactapi/utils"
+)
+
+const (
+	defaultTransitionPageSize = 50
+	maxTransitionPageSize     = 500
+)
+
+// membershipTransition is one dbactor_groups or dbpolicy_groups row whose grants must be
+// sent or revoked because its validity period started or ended.
+type membershipTransition struct {
+	kind         string // models.MembershipKindActor or models.MembershipKindPolicy
+	action       string // models.TransitionActivate or models.TransitionExpire
+	membershipID uint
+	groupID      uint
+	subjectID    uint // Actor ID or dbgroup_listpolicies ID
+}
+
+// EnforceValidity applies the group memberships whose validity period changed by now.
+// Memberships past their valid_until are revoked with the deny path of RemoveActorsFromGroup and
+// marked inactive; memberships stored inactive whose valid_from has passed are granted with the
+// allow path of AssignActorsToGroup and marked active. Expirations run first, so an actor joining
+// a group is never granted a policy that expired in the same pass.
+// A transition whose agent commands all fail keeps its is_active value and is retried on the next
+// pass. Every outcome is stored in membership_transitions and applied ones are audited.
+// Cancelling ctx stops the pass after the current transition.
+func (s *groupManagementService) EnforceValidity(ctx context.Context, now time.Time) (*dto.ValidityRunResult, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("context cannot be nil")
+	}
+
+	expiredPolicies, err := s.policyGroupsRepo.GetExpired(nil, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expired policy assignments: %v", err)
+	}
+	expiredActors, err := s.actorGroupsRepo.GetExpired(nil, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expired actor assignments: %v", err)
+	}
+	duePolicies, err := s.policyGroupsRepo.GetDue(nil, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due policy assignments: %v", err)
+	}
+	dueActors, err := s.actorGroupsRepo.GetDue(nil, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due actor assignments: %v", err)
+	}
+
+	var transitions []membershipTransition
+	for _, pg := range expiredPolicies {
+		transitions = append(transitions, membershipTransition{models.MembershipKindPolicy, models.TransitionExpire, pg.ID, pg.GroupID, pg.DBGroupListPoliciesID})
+	}
+	for _, ag := range expiredActors {
+		transitions = append(transitions, membershipTransition{models.MembershipKindActor, models.TransitionExpire, ag.ID, ag.GroupID, ag.ActorID})
+	}
+	for _, pg := range duePolicies {
+		transitions = append(transitions, membershipTransition{models.MembershipKindPolicy, models.TransitionActivate, pg.ID, pg.GroupID, pg.DBGroupListPoliciesID})
+	}
+	for _, ag := range dueActors {
+		transitions = append(transitions, membershipTransition{models.MembershipKindActor, models.TransitionActivate, ag.ID, ag.GroupID, ag.ActorID})
+	}
+
+	result := &dto.ValidityRunResult{}
+	changedGroups := make(map[uint]bool)
+	for _, t := range transitions {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+
+		// A started transition is finished even if ctx is cancelled, so is_active matches the commands sent
+		changed, err := s.applyTransition(context.WithoutCancel(ctx), t, now)
+		if err != nil {
+			logger.Errorf("Failed to %s %s membership %d of group %d: %v", t.action, t.kind, t.membershipID, t.groupID, err)
+			result.Failed++
+			continue
+		}
+		if t.action == models.TransitionExpire {
+			result.Expired++
+		} else {
+			result.Activated++
+		}
+		if changed {
+			changedGroups[t.groupID] = true
+		}
+	}
+
+	// Export DBF policy rules after dbpolicy changes (run in background)
+	if len(changedGroups) > 0 {
+		logger.Infof("Starting background exportDBFPolicy to build rule files after validity changes in %d groups", len(changedGroups))
+		go func() {
+			if err := utils.ExportDBFPolicy(); err != nil {
+				logger.Warnf("Failed to export DBF policy rules after validity changes: %v", err)
+			} else {
+				logger.Infof("Successfully exported DBF policy rules after validity changes")
+			}
+		}()
+	}
+
+	if len(transitions) > 0 {
+		logger.Infof("Validity enforcement: %d activated, %d expired, %d failed", result.Activated, result.Expired, result.Failed)
+	}
+	return result, nil
+}
+
+// applyTransition sends the allow or deny commands of one transition, then flips is_active,
+// syncs the dbpolicy table and records the outcome in one transaction.
+// Reports whether dbpolicy rows may have changed. The failed outcome is recorded before returning an error.
+func (s *groupManagementService) applyTransition(ctx context.Context, t membershipTransition, now time.Time) (bool, error) {
+	policyIDs, actors, err := s.transitionTargets(t, now)
+	if err != nil {
+		return false, s.recordTransitionFailure(t, 0, err)
+	}
+
+	operation, policyOperation := "allow", PolicyOperationAssign
+	if t.action == models.TransitionExpire {
+		operation, policyOperation = "deny", PolicyOperationRemove
+	}
+
+	// CRITICAL: Execute VeloArtifact operations BEFORE database changes
+	var executions []VeloArtifactExecution
+	if len(policyIDs) > 0 {
+		for cntID, connectionActors := range s.groupActorsByConnection(actors) {
+			batchSQLCommands, err := s.buildOptimizedBatchSQL(policyIDs, connectionActors, operation, cntID)
+			if err != nil {
+				logger.Errorf("Failed to build %s commands for group %d on connection %d: %v", operation, t.groupID, cntID, err)
+				continue
+			}
+
+			if len(batchSQLCommands) > 0 {
+				executions = append(executions, VeloArtifactExecution{
+					Operation:        operation,
+					GroupID:          t.groupID,
+					ConnectionID:     cntID,
+					BatchSQLCommands: batchSQLCommands,
+				})
+			}
+		}
+	}
+
+	veloResult := s.executeVeloArtifactOperations(ctx, executions)
+	if veloResult.TotalSucceeded == 0 && veloResult.TotalAttempted > 0 {
+		return false, s.recordTransitionFailure(t, veloResult.TotalAttempted,
+			fmt.Errorf("all VeloArtifact operations failed (%d/%d): %s", veloResult.TotalFailed, veloResult.TotalAttempted, veloFailures(veloResult)))
+	}
+
+	tx := s.baseRepo.Begin()
+	var txCommitted bool
+	defer func() {
+		if !txCommitted {
+			tx.Rollback()
+		}
+	}()
+
+	active := t.action == models.TransitionActivate
+	if t.kind == models.MembershipKindActor {
+		err = s.actorGroupsRepo.SetActive(tx, t.membershipID, active)
+	} else {
+		err = s.policyGroupsRepo.SetActive(tx, t.membershipID, active)
+	}
+	if err != nil {
+		return false, s.recordTransitionFailure(t, veloResult.TotalAttempted, fmt.Errorf("failed to update membership: %v", err))
+	}
+
+	changed := false
+	if len(policyIDs) > 0 && len(actors) > 0 {
+		policyDefaultIDs, err := s.extractPolicyDefaultIDs(policyIDs, policyOperation)
+		if err != nil {
+			return false, s.recordTransitionFailure(t, veloResult.TotalAttempted, fmt.Errorf("failed to extract policy defaults: %v", err))
+		}
+		if len(policyDefaultIDs) > 0 {
+			if active {
+				_, err = s.syncDBPolicyForActors(tx, actors, policyDefaultIDs)
+			} else {
+				err = s.cleanupDBPolicyForActors(tx, actors, policyDefaultIDs)
+			}
+			if err != nil {
+				return false, s.recordTransitionFailure(t, veloResult.TotalAttempted, err)
+			}
+			changed = true
+		}
+	}
+
+	actorIDs := make([]uint, 0, len(actors))
+	for _, actor := range actors {
+		actorIDs = append(actorIDs, actor.ID)
+	}
+	if err := audit.Record(ctx, tx, audit.Entry{
+		EntityType: "group",
+		EntityID:   fmt.Sprint(t.groupID),
+		Action:     t.action + "_" + t.kind,
+		After: map[string]interface{}{
+			"membership_id":  t.membershipID,
+			"actor_ids":      actorIDs,
+			"policy_ids":     policyIDs,
+			"velo_succeeded": veloResult.SuccessfulJobs,
+			"velo_failed":    veloResult.FailedJobs,
+		},
+	}); err != nil {
+		return false, s.recordTransitionFailure(t, veloResult.TotalAttempted, err)
+	}
+
+	transition := t.record(models.TransitionApplied, veloResult.TotalAttempted, "")
+	if veloResult.TotalFailed > 0 {
+		transition.Status = models.TransitionPartial
+		transition.Error = veloFailures(veloResult)
+	}
+	if err := s.transitionRepo.Record(tx, transition); err != nil {
+		return false, fmt.Errorf("failed to record transition: %v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	txCommitted = true
+
+	logger.Infof("Validity enforcement: %s %s membership %d of group %d, VeloArtifact: %d succeeded, %d failed",
+		t.action, t.kind, t.membershipID, t.groupID, veloResult.TotalSucceeded, veloResult.TotalFailed)
+	return changed, nil
+}
+
+// transitionTargets returns the policies and actors whose grants a transition sends or revokes.
+// An actor transition covers the policies applied to its group and a policy transition the actors
+// applied to it. Activations leave out memberships already past their valid_until, which are
+// about to be revoked, and a membership still granted by another applied row of the same group
+// is not revoked.
+func (s *groupManagementService) transitionTargets(t membershipTransition, now time.Time) ([]uint, []models.DBActorMgt, error) {
+	appliedPolicies, err := s.policyGroupsRepo.GetAppliedByGroupID(nil, t.groupID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get applied policies of group %d: %v", t.groupID, err)
+	}
+	appliedActors, err := s.actorGroupsRepo.GetAppliedByGroupID(nil, t.groupID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get applied actors of group %d: %v", t.groupID, err)
+	}
+
+	activate := t.action == models.TransitionActivate
+	var policyIDs, actorIDs []uint
+	if t.kind == models.MembershipKindActor {
+		for _, ag := range appliedActors {
+			if !activate && ag.ID != t.membershipID && ag.ActorID == t.subjectID && !expired(ag.ValidUntil, now) {
+				return nil, nil, nil // Still a member through another row
+			}
+		}
+		for _, pg := range appliedPolicies {
+			if !activate || !expired(pg.ValidUntil, now) {
+				policyIDs = append(policyIDs, pg.DBGroupListPoliciesID)
+			}
+		}
+		actorIDs = []uint{t.subjectID}
+	} else {
+		for _, pg := range appliedPolicies {
+			if !activate && pg.ID != t.membershipID && pg.DBGroupListPoliciesID == t.subjectID && !expired(pg.ValidUntil, now) {
+				return nil, nil, nil // Still assigned through another row
+			}
+		}
+		for _, ag := range appliedActors {
+			if !activate || !expired(ag.ValidUntil, now) {
+				actorIDs = append(actorIDs, ag.ActorID)
+			}
+		}
+		policyIDs = []uint{t.subjectID}
+	}
+
+	actors := make([]models.DBActorMgt, 0, len(actorIDs))
+	for _, actorID := range removeDuplicateUints(actorIDs) {
+		actor, err := s.actorMgtRepo.GetByID(nil, actorID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("actor with id=%d not found: %v", actorID, err)
+		}
+		actors = append(actors, *actor)
+	}
+	return removeDuplicateUints(policyIDs), actors, nil
+}
+
+// recordTransitionFailure stores the failed outcome of a transition and returns cause.
+func (s *groupManagementService) recordTransitionFailure(t membershipTransition, executions int, cause error) error {
+	if err := s.transitionRepo.Record(nil, t.record(models.TransitionFailed, executions, cause.Error())); err != nil {
+		logger.Errorf("Failed to record failed transition of %s membership %d: %v", t.kind, t.membershipID, err)
+	}
+	return cause
+}
+
+// ListMembershipTransitions returns the outcomes of validity enforcement, optionally filtered by status.
+func (s *groupManagementService) ListMembershipTransitions(ctx context.Context, query dto.MembershipTransitionQuery) (*dto.MembershipTransitionListResponse, error) {
+	switch query.Status {
+	case "", models.TransitionApplied, models.TransitionPartial, models.TransitionFailed:
+	default:
+		return nil, fmt.Errorf("invalid status %q: must be applied, partial or failed", query.Status)
+	}
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.PageSize < 1 {
+		query.PageSize = defaultTransitionPageSize
+	}
+	if query.PageSize > maxTransitionPageSize {
+		query.PageSize = maxTransitionPageSize
+	}
+
+	transitions, total, err := s.transitionRepo.List(nil, query.Status, (query.Page-1)*query.PageSize, query.PageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list membership transitions: %w", err)
+	}
+
+	totalPages := int(total) / query.PageSize
+	if int(total)%query.PageSize != 0 {
+		totalPages++
+	}
+	return &dto.MembershipTransitionListResponse{
+		Transitions: transitions,
+		Total:       total,
+		Page:        query.Page,
+		PageSize:    query.PageSize,
+		TotalPages:  totalPages,
+	}, nil
+}
+
+func (t membershipTransition) record(status string, executions int, errMsg string) *models.MembershipTransition {
+	return &models.MembershipTransition{
+		Kind:         t.kind,
+		MembershipID: t.membershipID,
+		Action:       t.action,
+		GroupID:      t.groupID,
+		SubjectID:    t.subjectID,
+		Status:       status,
+		Executions:   executions,
+		Error:        errMsg,
+	}
+}
+
+// expired reports whether a validity period ending at validUntil is over at now.
+func expired(validUntil *time.Time, now time.Time) bool {
+	return validUntil != nil && validUntil.Before(now)
+}
+
+// veloFailures joins the errors of the failed VeloArtifact executions, one per connection.
+func veloFailures(result *VeloExecutionResult) string {
+	failures := make([]string, 0, len(result.FailedJobs))
+	for _, failed := range result.FailedJobs {
+		failures = append(failures, fmt.Sprintf("connection %d: %s", failed.ConnectionID, failed.Error))
+	}
+	return strings.Join(failures, "; ")
+}
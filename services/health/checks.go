@@ -0,0 +1,152 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	"dbfartifactapi/bootstrap"
+	"dbfartifactapi/config"
+	"dbfartifactapi/utils"
+)
+
+// defaultChecks builds the dependency checks from the current config.
+func defaultChecks() []Check {
+	checks := []Check{
+		{
+			Name:     "database",
+			Critical: true,
+			Target:   fmt.Sprintf("%s:%d/%s", config.Cfg.DBHost, config.Cfg.DBPort, config.Cfg.DBName),
+			Probe:    checkDatabase,
+		},
+		{
+			Name:     "bootstrap_cache",
+			Critical: true,
+			Probe:    checkBootstrapCache,
+		},
+	}
+
+	if config.Cfg.AgentTransport == "http" {
+		checks = append(checks, Check{
+			Name:     "agent_gateway",
+			Critical: true,
+			Target:   config.Cfg.AgentGatewayURL,
+			Probe:    func(ctx context.Context) (string, error) { return checkGateway(ctx, config.Cfg.AgentGatewayURL) },
+		})
+	} else {
+		checks = append(checks, Check{
+			Name:     "agent_api",
+			Critical: true,
+			Target:   config.Cfg.AgentAPIPath,
+			Probe:    func(ctx context.Context) (string, error) { return checkExecutable(config.Cfg.AgentAPIPath) },
+		})
+	}
+
+	return append(checks,
+		// Rule files are rebuilt in the background after discovery; requests are still served without the binary
+		Check{
+			Name:   "export_dbf_policy",
+			Target: utils.ExportDBFPolicyPath,
+			Probe:  func(ctx context.Context) (string, error) { return checkExecutable(utils.ExportDBFPolicyPath) },
+		},
+		Check{
+			Name:     "notification_file_dir",
+			Critical: true,
+			Target:   config.Cfg.NotificationFileDir,
+			Probe:    func(ctx context.Context) (string, error) { return checkWritableDir(config.Cfg.NotificationFileDir) },
+		},
+		// Only used for MySQL privilege query logs
+		Check{
+			Name:   "dbfweb_temp_dir",
+			Target: config.Cfg.DBFWebTempDir,
+			Probe:  func(ctx context.Context) (string, error) { return checkWritableDir(config.Cfg.DBFWebTempDir) },
+		},
+	)
+}
+
+// checkDatabase pings the config database over the GORM connection pool.
+func checkDatabase(ctx context.Context) (string, error) {
+	if config.DB == nil {
+		return "", fmt.Errorf("database connection not initialized")
+	}
+	sqlDB, err := config.DB.DB()
+	if err != nil {
+		return "", fmt.Errorf("failed to get connection pool: %w", err)
+	}
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return "", fmt.Errorf("ping failed: %w", err)
+	}
+	stats := sqlDB.Stats()
+	return fmt.Sprintf("open_connections=%d in_use=%d", stats.OpenConnections, stats.InUse), nil
+}
+
+// checkBootstrapCache verifies the lookup data loaded at startup is present.
+// Policy discovery cannot classify privileges without database types and policy templates.
+func checkBootstrapCache(ctx context.Context) (string, error) {
+	detail := fmt.Sprintf("db_types=%d policy_defaults=%d", len(bootstrap.DBTypeAll), len(bootstrap.DBPolicyDefaultsAllMap))
+	if len(bootstrap.DBTypeAll) == 0 {
+		return detail, fmt.Errorf("database types not loaded")
+	}
+	if len(bootstrap.DBPolicyDefaultsAllMap) == 0 {
+		return detail, fmt.Errorf("policy defaults not loaded")
+	}
+	return detail, nil
+}
+
+// checkExecutable verifies path is a regular file with an execute bit set.
+func checkExecutable(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("%s is not a regular file", path)
+	}
+	if info.Mode().Perm()&0111 == 0 {
+		return "", fmt.Errorf("%s is not executable", path)
+	}
+	return fmt.Sprintf("mode=%s", info.Mode().Perm()), nil
+}
+
+// checkWritableDir verifies files can be created in dir by writing and removing a probe file.
+func checkWritableDir(dir string) (string, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", dir)
+	}
+	f, err := os.CreateTemp(dir, ".healthcheck-*")
+	if err != nil {
+		return "", fmt.Errorf("directory not writable: %w", err)
+	}
+	name := f.Name()
+	f.Close()
+	if err := os.Remove(name); err != nil {
+		return "", fmt.Errorf("failed to remove probe file %s: %w", name, err)
+	}
+	return "writable", nil
+}
+
+// checkGateway verifies the agent gateway answers HTTP requests.
+// Any response below 500 counts, since the base URL itself may require authentication or return 404.
+func checkGateway(ctx context.Context, baseURL string) (string, error) {
+	if baseURL == "" {
+		return "", fmt.Errorf("AGENT_GATEWAY_URL is not set")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("invalid gateway URL: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("gateway unreachable: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return "", fmt.Errorf("gateway returned status %d", resp.StatusCode)
+	}
+	return fmt.Sprintf("status=%d", resp.StatusCode), nil
+}
@@ -0,0 +1,136 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"dbfartifactapi/config"
+	"dbfartifactapi/services/dto"
+)
+
+const defaultCheckTimeout = 3 * time.Second
+
+// Check probes one dependency of the service.
+type Check struct {
+	Name     string
+	Critical bool   // A failing critical check makes the service not ready
+	Target   string // Path or address probed, shown in diagnostics
+	// Probe returns a short description of what it found, or an error when the dependency is unusable.
+	Probe func(ctx context.Context) (string, error)
+}
+
+// HealthService runs dependency checks for the readiness and diagnostics endpoints.
+type HealthService interface {
+	// Readiness reports whether every critical dependency is available.
+	Readiness(ctx context.Context) *dto.ReadinessResponse
+	// Diagnostics reports every dependency check with its latency and error.
+	Diagnostics(ctx context.Context) *dto.DiagnosticsResponse
+}
+
+type healthService struct {
+	checks  func() []Check
+	timeout time.Duration
+}
+
+// NewHealthService creates a health service probing the dependencies named in the application config.
+func NewHealthService() HealthService {
+	return &healthService{
+		checks:  defaultChecks,
+		timeout: config.Cfg.HealthCheckTimeout,
+	}
+}
+
+// Readiness implements HealthService.
+func (s *healthService) Readiness(ctx context.Context) *dto.ReadinessResponse {
+	report := s.Diagnostics(ctx)
+	resp := &dto.ReadinessResponse{Status: report.Status}
+	for _, check := range report.Checks {
+		if check.Status == dto.HealthStatusFail {
+			resp.Failed = append(resp.Failed, check.Name)
+		}
+	}
+	return resp
+}
+
+// Diagnostics implements HealthService.
+// Checks run concurrently, each bounded by the configured timeout, so a hung dependency cannot stall a probe.
+func (s *healthService) Diagnostics(ctx context.Context) *dto.DiagnosticsResponse {
+	checks := s.checks()
+	results := make([]dto.DependencyCheck, len(checks))
+
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			results[i] = s.run(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	return &dto.DiagnosticsResponse{
+		Status:    overallStatus(results),
+		CheckedAt: time.Now().UTC(),
+		Checks:    results,
+	}
+}
+
+// run executes one check and records its latency and outcome.
+func (s *healthService) run(ctx context.Context, check Check) dto.DependencyCheck {
+	timeout := s.timeout
+	if timeout <= 0 {
+		timeout = defaultCheckTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type outcome struct {
+		detail string
+		err    error
+	}
+	done := make(chan outcome, 1)
+
+	start := time.Now()
+	go func() {
+		detail, err := check.Probe(ctx)
+		done <- outcome{detail, err}
+	}()
+
+	var out outcome
+	select {
+	case out = <-done:
+	case <-ctx.Done():
+		out.err = fmt.Errorf("check timed out after %v", timeout)
+	}
+
+	result := dto.DependencyCheck{
+		Name:      check.Name,
+		Status:    dto.HealthStatusOK,
+		Critical:  check.Critical,
+		Target:    check.Target,
+		Detail:    out.detail,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if out.err != nil {
+		result.Status = dto.HealthStatusFail
+		result.Error = out.err.Error()
+	}
+	return result
+}
+
+// overallStatus is unavailable when a critical check failed and degraded when only non-critical checks failed.
+func overallStatus(results []dto.DependencyCheck) string {
+	status := dto.HealthStatusOK
+	for _, r := range results {
+		if r.Status != dto.HealthStatusFail {
+			continue
+		}
+		if r.Critical {
+			return dto.HealthStatusUnavailable
+		}
+		status = dto.HealthStatusDegraded
+	}
+	return status
+}
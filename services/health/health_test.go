@@ -0,0 +1,135 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"dbfartifactapi/services/dto"
+)
+
+func probeOK(ctx context.Context) (string, error)   { return "fine", nil }
+func probeFail(ctx context.Context) (string, error) { return "", errors.New("boom") }
+
+func newTestService(timeout time.Duration, checks ...Check) *healthService {
+	return &healthService{
+		checks:  func() []Check { return checks },
+		timeout: timeout,
+	}
+}
+
+// TestDiagnosticsStatus tests that critical failures make the service unavailable and others only degrade it
+func TestDiagnosticsStatus(t *testing.T) {
+	cases := []struct {
+		name   string
+		checks []Check
+		want   string
+	}{
+		{"all ok", []Check{{Name: "a", Critical: true, Probe: probeOK}, {Name: "b", Probe: probeOK}}, dto.HealthStatusOK},
+		{"non-critical failure", []Check{{Name: "a", Critical: true, Probe: probeOK}, {Name: "b", Probe: probeFail}}, dto.HealthStatusDegraded},
+		{"critical failure", []Check{{Name: "a", Critical: true, Probe: probeFail}, {Name: "b", Probe: probeFail}}, dto.HealthStatusUnavailable},
+	}
+	for _, tc := range cases {
+		report := newTestService(time.Second, tc.checks...).Diagnostics(context.Background())
+		if report.Status != tc.want {
+			t.Errorf("%s: status = %s, want %s", tc.name, report.Status, tc.want)
+		}
+		if len(report.Checks) != len(tc.checks) {
+			t.Fatalf("%s: got %d checks, want %d", tc.name, len(report.Checks), len(tc.checks))
+		}
+		for i, check := range report.Checks {
+			if check.Name != tc.checks[i].Name {
+				t.Errorf("%s: check %d = %s, want %s", tc.name, i, check.Name, tc.checks[i].Name)
+			}
+		}
+	}
+}
+
+// TestDiagnosticsRecordsErrorAndTimeout tests that probe errors are reported and hung probes are cut off
+func TestDiagnosticsRecordsErrorAndTimeout(t *testing.T) {
+	hung := func(ctx context.Context) (string, error) {
+		time.Sleep(time.Second)
+		return "", nil
+	}
+	svc := newTestService(50*time.Millisecond,
+		Check{Name: "ok", Critical: true, Target: "/x", Probe: probeOK},
+		Check{Name: "failing", Probe: probeFail},
+		Check{Name: "hung", Critical: true, Probe: hung},
+	)
+
+	start := time.Now()
+	report := svc.Diagnostics(context.Background())
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Diagnostics took %v, want it bounded by the check timeout", elapsed)
+	}
+
+	ok, failing, timedOut := report.Checks[0], report.Checks[1], report.Checks[2]
+	if ok.Status != dto.HealthStatusOK || ok.Detail != "fine" || ok.Target != "/x" || ok.Error != "" {
+		t.Errorf("ok check = %+v", ok)
+	}
+	if failing.Status != dto.HealthStatusFail || failing.Error != "boom" {
+		t.Errorf("failing check = %+v", failing)
+	}
+	if timedOut.Status != dto.HealthStatusFail || timedOut.Error == "" {
+		t.Errorf("hung check = %+v, want timeout failure", timedOut)
+	}
+	if timedOut.LatencyMs < 50 {
+		t.Errorf("hung check latency = %vms, want at least the timeout", timedOut.LatencyMs)
+	}
+
+	ready := svc.Readiness(context.Background())
+	if ready.Status != dto.HealthStatusUnavailable {
+		t.Errorf("readiness status = %s, want %s", ready.Status, dto.HealthStatusUnavailable)
+	}
+	if len(ready.Failed) != 2 || ready.Failed[0] != "failing" || ready.Failed[1] != "hung" {
+		t.Errorf("readiness failed = %v, want [failing hung]", ready.Failed)
+	}
+}
+
+// TestCheckWritableDir tests the probe file is created and removed, and missing directories fail
+func TestCheckWritableDir(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := checkWritableDir(dir); err != nil {
+		t.Fatalf("checkWritableDir(%s): %v", dir, err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("probe file left behind: %v", entries)
+	}
+
+	if _, err := checkWritableDir(filepath.Join(dir, "missing")); err == nil {
+		t.Error("expected error for missing directory")
+	}
+}
+
+// TestCheckExecutable tests that only regular files with an execute bit pass
+func TestCheckExecutable(t *testing.T) {
+	dir := t.TempDir()
+	bin := filepath.Join(dir, "tool")
+	if err := os.WriteFile(bin, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	plain := filepath.Join(dir, "plain")
+	if err := os.WriteFile(plain, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := checkExecutable(bin); err != nil {
+		t.Errorf("checkExecutable(tool): %v", err)
+	}
+	if _, err := checkExecutable(plain); err == nil {
+		t.Error("expected error for file without execute bit")
+	}
+	if _, err := checkExecutable(dir); err == nil {
+		t.Error("expected error for directory")
+	}
+	if _, err := checkExecutable(filepath.Join(dir, "missing")); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
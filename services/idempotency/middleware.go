@@ -0,0 +1,249 @@
+package idempotency
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"dbfartifactapi/config"
+	"dbfartifactapi/models"
+	"dbfartifactapi/pkg/auth"
+	"dbfartifactapi/pkg/logger"
+	"dbfartifactapi/repository"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	// HeaderKey is the request header carrying the client-chosen idempotency key.
+	HeaderKey = "Idempotency-Key"
+	// HeaderReplayed is set on responses replayed from a stored earlier request.
+	HeaderReplayed = "Idempotent-Replayed"
+
+	maxKeyLength    = 255
+	cleanupInterval = time.Hour
+)
+
+// responseRecorder keeps a copy of the response body so a successful response can be replayed.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+type middleware struct {
+	repo        repository.IdempotencyKeyRepository
+	ttl         time.Duration
+	lockTimeout time.Duration
+	now         func() time.Time
+	lastCleanup atomic.Int64
+}
+
+// Middleware makes POST requests carrying an Idempotency-Key header safe to retry.
+// It must run after the auth middleware, since keys are scoped to the caller.
+// The first request with a key runs normally and its 2xx response, including any job ID, is stored for
+// IdempotencyTTL; later requests with the same key and body get that response replayed.
+// A duplicate arriving while the first request is still running gets 409, and reusing a key
+// for a different request gets 422. Failed requests release the key so a retry runs again.
+func Middleware() gin.HandlerFunc {
+	m := &middleware{
+		repo:        repository.NewIdempotencyKeyRepository(),
+		ttl:         config.Cfg.IdempotencyTTL,
+		lockTimeout: config.Cfg.IdempotencyLockTimeout,
+		now:         time.Now,
+	}
+	return m.handle
+}
+
+func (m *middleware) handle(c *gin.Context) {
+	key := c.GetHeader(HeaderKey)
+	if c.Request.Method != http.MethodPost || key == "" || !config.Cfg.IdempotencyEnabled {
+		c.Next()
+		return
+	}
+	if len(key) > maxKeyLength {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("%s must be at most %d characters", HeaderKey, maxKeyLength),
+		})
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to read request body: %v", err)})
+		return
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	actor := "anonymous"
+	if principal := auth.PrincipalFrom(c); principal != nil {
+		actor = principal.Subject
+	}
+
+	now := m.now()
+	m.cleanupExpired(now)
+
+	record := &models.IdempotencyKey{
+		KeyHash:     hashParts(actor, key),
+		Actor:       actor,
+		Method:      c.Request.Method,
+		Path:        c.Request.URL.Path,
+		Fingerprint: hashParts(c.Request.Method, c.Request.URL.Path, c.Request.URL.RawQuery, string(body)),
+		Status:      models.IdempotencyStatusInProgress,
+		LockedUntil: now.Add(m.lockTimeout),
+		ExpiresAt:   now.Add(m.ttl),
+		CreatedAt:   now,
+	}
+
+	existing, err := m.acquire(record, now)
+	if err != nil {
+		logger.Errorf("Idempotency store error for key of %s on %s: %v", actor, record.Path, err)
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "idempotency key store unavailable"})
+		return
+	}
+	if existing != nil {
+		m.respondExisting(c, existing, record)
+		return
+	}
+
+	m.run(c, record)
+}
+
+// acquire claims the key for record. It returns nil when this request owns the key,
+// or the stored record of an earlier live request with the same key.
+func (m *middleware) acquire(record *models.IdempotencyKey, now time.Time) (*models.IdempotencyKey, error) {
+	created, err := m.repo.Create(nil, record)
+	if err != nil || created {
+		return nil, err
+	}
+
+	existing, err := m.repo.GetByKeyHash(nil, record.KeyHash)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Released by a failed request between the insert and the read
+		created, err = m.repo.Create(nil, record)
+		if err != nil || created {
+			return nil, err
+		}
+		existing, err = m.repo.GetByKeyHash(nil, record.KeyHash)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	abandoned := existing.Status == models.IdempotencyStatusInProgress && existing.LockedUntil.Before(now)
+	if !existing.ExpiresAt.Before(now) && !abandoned {
+		return existing, nil
+	}
+
+	reclaimed, err := m.repo.Reclaim(nil, record, now)
+	if err != nil || reclaimed {
+		return nil, err
+	}
+	// Another retry reclaimed the key first
+	return m.repo.GetByKeyHash(nil, record.KeyHash)
+}
+
+// respondExisting answers a duplicate request from the stored record of the first one.
+func (m *middleware) respondExisting(c *gin.Context, existing, record *models.IdempotencyKey) {
+	if existing.Fingerprint != record.Fingerprint {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{
+			"error": fmt.Sprintf("%s was already used for a different request", HeaderKey),
+		})
+		return
+	}
+	if existing.Status != models.IdempotencyStatusCompleted {
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+			"error": fmt.Sprintf("a request with this %s is still in progress", HeaderKey),
+		})
+		return
+	}
+
+	logger.Infof("Replaying stored response for idempotent %s %s by %s", record.Method, record.Path, record.Actor)
+	c.Header(HeaderReplayed, "true")
+	c.Data(existing.ResponseStatus, existing.ContentType, []byte(existing.ResponseBody))
+	c.Abort()
+}
+
+// run executes the request and stores its response, or releases the key when it did not succeed.
+func (m *middleware) run(c *gin.Context, record *models.IdempotencyKey) {
+	recorder := &responseRecorder{ResponseWriter: c.Writer}
+	c.Writer = recorder
+
+	finished := false
+	defer func() {
+		// A panicking handler must not leave the key locked
+		if !finished {
+			m.release(record.KeyHash)
+		}
+	}()
+
+	c.Next()
+	finished = true
+
+	status := recorder.Status()
+	if status < http.StatusOK || status >= http.StatusMultipleChoices {
+		m.release(record.KeyHash)
+		return
+	}
+
+	now := m.now()
+	record.Status = models.IdempotencyStatusCompleted
+	record.ResponseStatus = status
+	record.ContentType = recorder.Header().Get("Content-Type")
+	record.ResponseBody = recorder.body.String()
+	record.LockedUntil = now
+	record.ExpiresAt = now.Add(m.ttl)
+	// On failure the key stays in progress until the lock times out, which blocks retries rather than duplicating work
+	if err := m.repo.Update(nil, record); err != nil {
+		logger.Errorf("Failed to store idempotent response for %s %s by %s: %v", record.Method, record.Path, record.Actor, err)
+	}
+}
+
+func (m *middleware) release(keyHash string) {
+	if err := m.repo.Delete(nil, keyHash); err != nil {
+		logger.Errorf("Failed to release idempotency key: %v", err)
+	}
+}
+
+// cleanupExpired deletes expired keys in the background at most once per cleanupInterval.
+func (m *middleware) cleanupExpired(now time.Time) {
+	last := m.lastCleanup.Load()
+	if now.Sub(time.Unix(0, last)) < cleanupInterval || !m.lastCleanup.CompareAndSwap(last, now.UnixNano()) {
+		return
+	}
+	go func() {
+		count, err := m.repo.DeleteExpired(nil, now)
+		if err != nil {
+			logger.Warnf("Failed to delete expired idempotency keys: %v", err)
+			return
+		}
+		if count > 0 {
+			logger.Debugf("Deleted %d expired idempotency keys", count)
+		}
+	}()
+}
+
+// hashParts returns the hex SHA-256 of parts separated by NUL bytes.
+func hashParts(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,234 @@
+package idempotency
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"dbfartifactapi/config"
+	"dbfartifactapi/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// fakeKeyStore is an in-memory IdempotencyKeyRepository
+type fakeKeyStore struct {
+	mu      sync.Mutex
+	records map[string]models.IdempotencyKey
+}
+
+func newFakeKeyStore() *fakeKeyStore {
+	return &fakeKeyStore{records: make(map[string]models.IdempotencyKey)}
+}
+
+func (f *fakeKeyStore) Migrate() error { return nil }
+
+func (f *fakeKeyStore) Create(tx *gorm.DB, record *models.IdempotencyKey) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, exists := f.records[record.KeyHash]; exists {
+		return false, nil
+	}
+	f.records[record.KeyHash] = *record
+	return true, nil
+}
+
+func (f *fakeKeyStore) GetByKeyHash(tx *gorm.DB, keyHash string) (*models.IdempotencyKey, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	record, exists := f.records[keyHash]
+	if !exists {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &record, nil
+}
+
+func (f *fakeKeyStore) Reclaim(tx *gorm.DB, record *models.IdempotencyKey, now time.Time) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	existing, exists := f.records[record.KeyHash]
+	if !exists {
+		return false, nil
+	}
+	abandoned := existing.Status == models.IdempotencyStatusInProgress && existing.LockedUntil.Before(now)
+	if !existing.ExpiresAt.Before(now) && !abandoned {
+		return false, nil
+	}
+	f.records[record.KeyHash] = *record
+	return true, nil
+}
+
+func (f *fakeKeyStore) Update(tx *gorm.DB, record *models.IdempotencyKey) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.records[record.KeyHash] = *record
+	return nil
+}
+
+func (f *fakeKeyStore) Delete(tx *gorm.DB, keyHash string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.records, keyHash)
+	return nil
+}
+
+func (f *fakeKeyStore) DeleteExpired(tx *gorm.DB, now time.Time) (int64, error) {
+	return 0, nil
+}
+
+type testServer struct {
+	router *gin.Engine
+	store  *fakeKeyStore
+	calls  atomic.Int32
+	now    time.Time
+}
+
+// newTestServer routes POST /jobs through the middleware; the handler returns a new job ID per call
+// and fails with 500 when the body is "fail". The release channel, when set, blocks the handler.
+func newTestServer(t *testing.T, release chan struct{}) *testServer {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	config.Cfg.IdempotencyEnabled = true
+
+	s := &testServer{store: newFakeKeyStore(), now: time.Now()}
+	m := &middleware{repo: s.store, ttl: time.Hour, lockTimeout: time.Minute, now: func() time.Time { return s.now }}
+
+	s.router = gin.New()
+	s.router.POST("/jobs", m.handle, func(c *gin.Context) {
+		n := s.calls.Add(1)
+		if release != nil {
+			<-release
+		}
+		body, _ := io.ReadAll(c.Request.Body)
+		if string(body) == "fail" {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "agent unavailable"})
+			return
+		}
+		c.JSON(http.StatusAccepted, gin.H{"job_id": fmt.Sprintf("job-%d", n), "body": string(body)})
+	})
+	return s
+}
+
+func (s *testServer) post(key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/jobs", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(HeaderKey, key)
+	}
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+// TestMiddleware_ReplaysFirstResponse tests that a retry gets the stored response without running the handler
+func TestMiddleware_ReplaysFirstResponse(t *testing.T) {
+	s := newTestServer(t, nil)
+
+	first := s.post("key-1", "backup")
+	if first.Code != http.StatusAccepted {
+		t.Fatalf("first request status = %d, want %d", first.Code, http.StatusAccepted)
+	}
+	second := s.post("key-1", "backup")
+	if second.Code != http.StatusAccepted || second.Body.String() != first.Body.String() {
+		t.Errorf("replay = %d %s, want %d %s", second.Code, second.Body, first.Code, first.Body)
+	}
+	if second.Header().Get(HeaderReplayed) != "true" {
+		t.Errorf("expected %s header on replay", HeaderReplayed)
+	}
+	if got := s.calls.Load(); got != 1 {
+		t.Errorf("handler ran %d times, want 1", got)
+	}
+
+	// Requests without a key or with another key are not deduplicated
+	s.post("", "backup")
+	s.post("key-2", "backup")
+	if got := s.calls.Load(); got != 3 {
+		t.Errorf("handler ran %d times, want 3", got)
+	}
+}
+
+// TestMiddleware_RejectsKeyReuseWithDifferentBody tests that a key cannot be replayed for another request
+func TestMiddleware_RejectsKeyReuseWithDifferentBody(t *testing.T) {
+	s := newTestServer(t, nil)
+
+	s.post("key-1", "actor 1")
+	if w := s.post("key-1", "actor 2"); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusUnprocessableEntity)
+	}
+}
+
+// TestMiddleware_ConflictWhileInProgress tests that a concurrent duplicate gets 409 while the original runs
+func TestMiddleware_ConflictWhileInProgress(t *testing.T) {
+	release := make(chan struct{})
+	s := newTestServer(t, release)
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- s.post("key-1", "backup") }()
+	for s.calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	if w := s.post("key-1", "backup"); w.Code != http.StatusConflict {
+		t.Errorf("concurrent duplicate status = %d, want %d", w.Code, http.StatusConflict)
+	}
+	close(release)
+	if w := <-done; w.Code != http.StatusAccepted {
+		t.Errorf("original status = %d, want %d", w.Code, http.StatusAccepted)
+	}
+	if got := s.calls.Load(); got != 1 {
+		t.Errorf("handler ran %d times, want 1", got)
+	}
+}
+
+// TestMiddleware_FailureReleasesKey tests that an unsuccessful request can be retried with the same key
+func TestMiddleware_FailureReleasesKey(t *testing.T) {
+	s := newTestServer(t, nil)
+
+	if w := s.post("key-1", "fail"); w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+	if len(s.store.records) != 0 {
+		t.Errorf("expected key to be released, store has %d records", len(s.store.records))
+	}
+	s.post("key-1", "fail")
+	if got := s.calls.Load(); got != 2 {
+		t.Errorf("handler ran %d times, want 2", got)
+	}
+}
+
+// TestMiddleware_ExpiredAndAbandonedKeys tests that keys past their TTL or lock timeout run the request again
+func TestMiddleware_ExpiredAndAbandonedKeys(t *testing.T) {
+	s := newTestServer(t, nil)
+
+	s.post("key-1", "backup")
+	s.now = s.now.Add(2 * time.Hour)
+	if w := s.post("key-1", "backup"); w.Header().Get(HeaderReplayed) != "" {
+		t.Error("expected expired key to run the request again")
+	}
+
+	// A request that never finished, e.g. the process died, blocks the key only until the lock times out
+	abandoned := &models.IdempotencyKey{
+		KeyHash:     hashParts("anonymous", "key-2"),
+		Fingerprint: hashParts(http.MethodPost, "/jobs", "", "backup"),
+		Status:      models.IdempotencyStatusInProgress,
+		LockedUntil: s.now.Add(time.Minute),
+		ExpiresAt:   s.now.Add(time.Hour),
+	}
+	s.store.records[abandoned.KeyHash] = *abandoned
+	if w := s.post("key-2", "backup"); w.Code != http.StatusConflict {
+		t.Errorf("status before lock timeout = %d, want %d", w.Code, http.StatusConflict)
+	}
+	s.now = s.now.Add(2 * time.Minute)
+	if w := s.post("key-2", "backup"); w.Code != http.StatusAccepted {
+		t.Errorf("status after lock timeout = %d, want %d", w.Code, http.StatusAccepted)
+	}
+	if got := s.calls.Load(); got != 3 {
+		t.Errorf("handler ran %d times, want 3", got)
+	}
+}
@@ -0,0 +1,247 @@
+package job
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"dbfartifactapi/pkg/logger"
+)
+
+// Job event types pushed to live progress subscribers (SSE / WebSocket)
+const (
+	EventTypeStatus      = "status"
+	EventTypeResults     = "results"
+	EventTypeProgress    = "progress"
+	EventTypeCompleted   = "completed"
+	EventTypeFailed      = "failed"
+	EventTypeCancelled   = "cancelled"
+	EventTypeInterrupted = "interrupted"
+)
+
+// eventBufferSize bounds per-subscriber backlog; slow subscribers drop events instead of blocking the monitor
+const eventBufferSize = 64
+
+// stepProgressInterval throttles sub-step progress events from tight query loops
+const stepProgressInterval = time.Second
+
+// JobEvent is a single job state transition delivered to live progress subscribers
+type JobEvent struct {
+	Type         string    `json:"type"`
+	JobID        string    `json:"job_id"`
+	Status       string    `json:"status"`
+	Progress     int       `json:"progress"`
+	Message      string    `json:"message"`
+	Completed    int       `json:"completed"`
+	Failed       int       `json:"failed"`
+	TotalQueries int       `json:"total_queries"`
+	Error        string    `json:"error,omitempty"`
+	Timestamp    time.Time `json:"timestamp"`
+}
+
+// IsTerminal reports whether no further events will follow for the job
+func (e JobEvent) IsTerminal() bool {
+	return e.Type != EventTypeResults && e.Type != EventTypeProgress && isTerminalStatus(e.Status)
+}
+
+// isTerminalStatus returns true for job states that end monitoring
+func isTerminalStatus(status string) bool {
+	switch status {
+	case "completed", "failed", "error", "cancelled", "interrupted":
+		return true
+	}
+	return false
+}
+
+// jobEventBroker fans out job events to subscribers keyed by job ID.
+// Zero value is ready to use.
+type jobEventBroker struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan JobEvent]struct{}
+}
+
+func (b *jobEventBroker) subscribe(jobID string) chan JobEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.subscribers == nil {
+		b.subscribers = make(map[string]map[chan JobEvent]struct{})
+	}
+	if b.subscribers[jobID] == nil {
+		b.subscribers[jobID] = make(map[chan JobEvent]struct{})
+	}
+
+	ch := make(chan JobEvent, eventBufferSize)
+	b.subscribers[jobID][ch] = struct{}{}
+	return ch
+}
+
+func (b *jobEventBroker) unsubscribe(jobID string, ch chan JobEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subs, exists := b.subscribers[jobID]
+	if !exists {
+		return
+	}
+	if _, exists := subs[ch]; !exists {
+		return
+	}
+	delete(subs, ch)
+	close(ch)
+	if len(subs) == 0 {
+		delete(b.subscribers, jobID)
+	}
+}
+
+func (b *jobEventBroker) publish(event JobEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers[event.JobID] {
+		select {
+		case ch <- event:
+		default:
+			logger.Debugf("Dropping %s event for job %s - subscriber not keeping up", event.Type, event.JobID)
+		}
+	}
+}
+
+// closeJob closes all subscriber channels for a job that left monitoring
+func (b *jobEventBroker) closeJob(jobID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers[jobID] {
+		close(ch)
+	}
+	delete(b.subscribers, jobID)
+}
+
+// closeAll closes every subscriber channel, ending all live streams
+func (b *jobEventBroker) closeAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, subs := range b.subscribers {
+		for ch := range subs {
+			close(ch)
+		}
+	}
+	b.subscribers = nil
+}
+
+// newJobEvent snapshots job state into an event
+func newJobEvent(eventType string, job *JobInfo) JobEvent {
+	return JobEvent{
+		Type:         eventType,
+		JobID:        job.JobID,
+		Status:       job.Status,
+		Progress:     job.Progress,
+		Message:      job.Message,
+		Completed:    job.Completed,
+		Failed:       job.Failed,
+		TotalQueries: job.TotalQueries,
+		Error:        job.Error,
+		Timestamp:    time.Now(),
+	}
+}
+
+// statusEventType maps job status to the event type published for a status transition
+func statusEventType(status string) string {
+	switch status {
+	case "completed":
+		return EventTypeCompleted
+	case "failed", "error":
+		return EventTypeFailed
+	case "cancelled":
+		return EventTypeCancelled
+	case "interrupted":
+		return EventTypeInterrupted
+	}
+	return EventTypeStatus
+}
+
+// publishEventLocked publishes current job state. Caller must hold jms.mu.
+func (jms *JobMonitorService) publishEventLocked(eventType string, job *JobInfo) {
+	jms.events.publish(newJobEvent(eventType, job))
+}
+
+// SubscribeJobEvents registers a live subscriber for job state transitions.
+// The first event is a snapshot of current state. The returned cancel func must be called
+// when the subscriber goes away; channel is closed on cancel or when the job is removed.
+func (jms *JobMonitorService) SubscribeJobEvents(jobID string) (<-chan JobEvent, func(), error) {
+	jms.mu.RLock()
+	defer jms.mu.RUnlock()
+
+	job, exists := jms.jobs[jobID]
+	if !exists {
+		// Finished jobs restored from the store still get a single snapshot event, read without the lock
+		jms.mu.RUnlock()
+		persisted, found := jms.loadPersistedJob(jobID)
+		jms.mu.RLock()
+		if !found {
+			return nil, nil, fmt.Errorf("job %s: %w", jobID, ErrJobNotFound)
+		}
+		ch := make(chan JobEvent, 1)
+		ch <- newJobEvent(statusEventType(persisted.Status), persisted)
+		close(ch)
+		return ch, func() {}, nil
+	}
+
+	// Subscribing under the read lock guarantees no transition is missed between snapshot and subscribe
+	ch := jms.events.subscribe(jobID)
+	ch <- newJobEvent(statusEventType(job.Status), job)
+
+	return ch, func() { jms.events.unsubscribe(jobID, ch) }, nil
+}
+
+// ReportJobProgress publishes a sub-step progress message for a job being processed server-side,
+// e.g. "Pass 2/3: action-wide queries 1200/5000". Message is visible in job status but not persisted.
+func (jms *JobMonitorService) ReportJobProgress(jobID, message string) {
+	jms.mu.Lock()
+	defer jms.mu.Unlock()
+
+	job, exists := jms.jobs[jobID]
+	if !exists || isCancelledStatus(job.Status) || isTerminalStatus(job.Status) {
+		return
+	}
+
+	job.Message = message
+	jms.publishEventLocked(EventTypeProgress, job)
+}
+
+// StepProgress reports progress of one processing step as "<label> <done>/<total>".
+// Updates are throttled so tight loops can call Update on every item. Nil StepProgress is a no-op.
+type StepProgress struct {
+	jms        *JobMonitorService
+	jobID      string
+	label      string
+	total      int
+	lastReport time.Time
+}
+
+// NewStepProgress creates a progress reporter for a processing step of a job
+func (jms *JobMonitorService) NewStepProgress(jobID, label string, total int) *StepProgress {
+	p := &StepProgress{
+		jms:   jms,
+		jobID: jobID,
+		label: label,
+		total: total,
+	}
+	jms.ReportJobProgress(jobID, fmt.Sprintf("%s 0/%d", label, total))
+	p.lastReport = time.Now()
+	return p
+}
+
+// Update reports that done items of the step are processed
+func (p *StepProgress) Update(done int) {
+	if p == nil {
+		return
+	}
+	if done < p.total && time.Since(p.lastReport) < stepProgressInterval {
+		return
+	}
+	p.lastReport = time.Now()
+	p.jms.ReportJobProgress(p.jobID, fmt.Sprintf("%s %d/%d", p.label, done, p.total))
+}
@@ -0,0 +1,77 @@
+package job
+
+import (
+	"testing"
+	"time"
+)
+
+// TestSubscribeJobEvents_StreamsTransitions tests snapshot, progress and terminal events for a subscriber
+func TestSubscribeJobEvents_StreamsTransitions(t *testing.T) {
+	jms := &JobMonitorService{
+		jobs: make(map[string]*JobInfo),
+	}
+	jms.jobs["job-1"] = &JobInfo{JobID: "job-1", Status: "processing", StartTime: time.Now()}
+
+	events, unsubscribe, err := jms.SubscribeJobEvents("job-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer unsubscribe()
+
+	progress := jms.NewStepProgress("job-1", "Pass 2/3: action-wide queries", 2)
+	progress.Update(2)
+	if err := jms.CompleteJobAfterProcessing("job-1", "done"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []struct {
+		eventType string
+		message   string
+	}{
+		{EventTypeStatus, ""},
+		{EventTypeProgress, "Pass 2/3: action-wide queries 0/2"},
+		{EventTypeProgress, "Pass 2/3: action-wide queries 2/2"},
+		{EventTypeCompleted, "done"},
+	}
+	var last JobEvent
+	for i, want := range expected {
+		last = <-events
+		if last.Type != want.eventType || last.Message != want.message {
+			t.Errorf("Event %d: expected %s %q, got %s %q", i, want.eventType, want.message, last.Type, last.Message)
+		}
+	}
+	if !last.IsTerminal() {
+		t.Errorf("Expected completed event to be terminal")
+	}
+}
+
+// TestSubscribeJobEvents_UnknownJob tests that subscribing to a missing job fails
+func TestSubscribeJobEvents_UnknownJob(t *testing.T) {
+	jms := &JobMonitorService{
+		jobs: make(map[string]*JobInfo),
+	}
+
+	if _, _, err := jms.SubscribeJobEvents("missing"); err == nil {
+		t.Errorf("Expected error for unknown job")
+	}
+}
+
+// TestRemoveJob_ClosesSubscribers tests that removing a job ends its event streams
+func TestRemoveJob_ClosesSubscribers(t *testing.T) {
+	jms := &JobMonitorService{
+		jobs: make(map[string]*JobInfo),
+	}
+	jms.jobs["job-1"] = &JobInfo{JobID: "job-1", Status: "running", StartTime: time.Now()}
+
+	events, unsubscribe, err := jms.SubscribeJobEvents("job-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	<-events // snapshot
+
+	jms.RemoveJob("job-1")
+	if _, ok := <-events; ok {
+		t.Errorf("Expected channel closed after job removal")
+	}
+	unsubscribe() // must be safe after close
+}
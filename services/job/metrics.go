@@ -0,0 +1,44 @@
+package job
+
+import (
+	"time"
+
+	"dbfartifactapi/pkg/metrics"
+)
+
+// callbackBuckets cover quick status updates up to large privilege discovery result processing.
+var callbackBuckets = []float64{0.1, 0.5, 1, 5, 10, 30, 60, 120, 300, 600, 1800}
+
+var (
+	jobsByStatus = metrics.NewGaugeFunc("dbfartifactapi_jobs",
+		"Monitored jobs by status; running, processing and failed are always reported.", "status", countJobsByStatus)
+	callbackDuration = metrics.NewHistogramVec("dbfartifactapi_job_completion_callback_duration_seconds",
+		"Job completion callback duration by job kind and outcome (success, error or panic).",
+		callbackBuckets, "kind", "outcome")
+)
+
+func init() {
+	metrics.MustRegister(jobsByStatus, callbackDuration)
+}
+
+// countJobsByStatus reports the number of monitored jobs per status.
+func countJobsByStatus() map[string]float64 {
+	counts := map[string]float64{"running": 0, "processing": 0, "failed": 0}
+
+	jms := GetJobMonitorService()
+	jms.mu.RLock()
+	defer jms.mu.RUnlock()
+	for _, job := range jms.jobs {
+		counts[job.Status]++
+	}
+	return counts
+}
+
+// observeCallback records how long a completion callback for kind took.
+// Jobs added without a registered kind are reported as "none".
+func observeCallback(kind, outcome string, start time.Time) {
+	if kind == "" {
+		kind = "none"
+	}
+	callbackDuration.WithLabelValues(kind, outcome).Observe(time.Since(start).Seconds())
+}
@@ -1,13 +1,26 @@
 package job
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
 
+	"dbfartifactapi/config"
 	"dbfartifactapi/pkg/logger"
+	"dbfartifactapi/pkg/tracing"
 	"dbfartifactapi/services/agent"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Job cancellation errors returned by CancelJob and checked by completion handlers
+var (
+	ErrJobNotFound       = errors.New("job not found")
+	ErrJobNotCancellable = errors.New("job is not in a cancellable state")
+	ErrJobCancelled      = errors.New("job was cancelled")
 )
 
 // JobCompletionCallback is called when a job completes (success or failure)
@@ -16,6 +29,7 @@ type JobCompletionCallback func(jobID string, jobInfo *JobInfo, statusResp *Stat
 // JobInfo stores information about a running job
 type JobInfo struct {
 	JobID        string      `json:"job_id"`
+	Kind         string      `json:"kind,omitempty"`
 	DBMgtID      uint        `json:"dbmgt_id"`
 	ClientID     string      `json:"client_id"`
 	OsType       string      `json:"os_type"`
@@ -29,6 +43,8 @@ type JobInfo struct {
 	TotalQueries int         `json:"total_queries"`
 	Error        string      `json:"error,omitempty"`
 	Results      interface{} `json:"results,omitempty"`
+	// W3C traceparent of the request that started the job; polling and the completion callback link to it
+	TraceParent string `json:"trace_parent,omitempty"`
 	// Callback function called when job completes
 	CompletionCallback JobCompletionCallback `json:"-"`
 	// Context data for callback processing
@@ -66,6 +82,20 @@ type JobMonitorService struct {
 	stopped bool
 	// Default completion callback for all jobs
 	defaultCallback JobCompletionCallback
+	// Writer of the durable job store, nil when persistence is disabled
+	store *jobWriter
+	// Live progress subscribers (SSE / WebSocket)
+	events jobEventBroker
+	// Agent executor for status checks and cancellation, nil uses agent.DefaultExecutor()
+	agentExec agent.AgentExecutor
+	// Set by Shutdown: no new jobs are monitored and no new completion callbacks start
+	draining bool
+	// In-flight completion callbacks, waited for by Shutdown
+	callbacks sync.WaitGroup
+	// Job IDs with a completion callback still running
+	callbackJobs map[string]int
+	// New polling intervals from config reloads
+	intervalCh chan time.Duration
 }
 
 var (
@@ -77,9 +107,11 @@ var (
 func GetJobMonitorService() *JobMonitorService {
 	jobMonitorOnce.Do(func() {
 		jobMonitorInstance = &JobMonitorService{
-			jobs:   make(map[string]*JobInfo),
-			stopCh: make(chan struct{}),
+			jobs:       make(map[string]*JobInfo),
+			stopCh:     make(chan struct{}),
+			intervalCh: make(chan time.Duration, 1),
 		}
+		config.OnReload(jobMonitorInstance.onConfigReload)
 		// Start the monitoring goroutine
 		go jobMonitorInstance.startMonitoring()
 	})
@@ -91,13 +123,31 @@ func (jms *JobMonitorService) AddJob(jobID string, dbmgtID uint, clientID, osTyp
 	jms.AddJobWithCallback(jobID, dbmgtID, clientID, osType, nil, nil)
 }
 
-// AddJobWithCallback adds a new job to monitoring with completion callback
+// AddJobWithCallback adds a new job to monitoring with completion callback.
+// Jobs added this way cannot resume their callback after a restart; prefer AddJobWithKind.
 func (jms *JobMonitorService) AddJobWithCallback(jobID string, dbmgtID uint, clientID, osType string, callback JobCompletionCallback, contextData map[string]interface{}) {
+	jms.addJob(jobID, "", "", dbmgtID, clientID, osType, callback, contextData)
+}
+
+// AddJobWithKind adds a new job to monitoring with the completion callback registered for kind.
+// The kind is persisted with the job so the callback can be restored after a restart.
+// The trace context of ctx is stored with the job so its polling and completion spans join the request trace.
+func (jms *JobMonitorService) AddJobWithKind(ctx context.Context, jobID, kind string, dbmgtID uint, clientID, osType string, contextData map[string]interface{}) {
+	callback, exists := GetJobKindCallback(kind)
+	if !exists {
+		logger.Warnf("No completion callback registered for job kind %s (job %s)", kind, jobID)
+	}
+	jms.addJob(jobID, kind, tracing.TraceParent(ctx), dbmgtID, clientID, osType, callback, contextData)
+}
+
+// addJob registers job in memory and persists its initial state
+func (jms *JobMonitorService) addJob(jobID, kind, traceParent string, dbmgtID uint, clientID, osType string, callback JobCompletionCallback, contextData map[string]interface{}) {
 	jms.mu.Lock()
-	defer jms.mu.Unlock()
 
 	job := &JobInfo{
 		JobID:              jobID,
+		Kind:               kind,
+		TraceParent:        traceParent,
 		DBMgtID:            dbmgtID,
 		ClientID:           clientID,
 		OsType:             osType,
@@ -109,10 +159,39 @@ func (jms *JobMonitorService) AddJobWithCallback(jobID string, dbmgtID uint, cli
 		ContextData:        contextData,
 	}
 
+	// The agent has already started the job, so it is still recorded for the next start to pick up
+	if jms.draining {
+		logger.Warnf("Job monitor is shutting down, job %s will not be monitored by this instance", jobID)
+		jms.persistJobLocked(job)
+		jms.mu.Unlock()
+		// Shutdown has already flushed the job writer, so the record is written before returning
+		jms.flushPersisted()
+		return
+	}
+
 	jms.jobs[jobID] = job
+	jms.persistJobLocked(job)
+	jms.mu.Unlock()
 	logger.Infof("Added job %s to monitoring for dbmgt_id %d", jobID, dbmgtID)
 }
 
+// SetAgentExecutor sets the executor used for agent status checks and cancellation
+func (jms *JobMonitorService) SetAgentExecutor(executor agent.AgentExecutor) {
+	jms.mu.Lock()
+	defer jms.mu.Unlock()
+	jms.agentExec = executor
+}
+
+// agentExecutor returns the injected agent executor or the process-wide default
+func (jms *JobMonitorService) agentExecutor() agent.AgentExecutor {
+	jms.mu.RLock()
+	defer jms.mu.RUnlock()
+	if jms.agentExec != nil {
+		return jms.agentExec
+	}
+	return agent.DefaultExecutor()
+}
+
 // SetDefaultCallback sets the default completion callback for all jobs
 func (jms *JobMonitorService) SetDefaultCallback(callback JobCompletionCallback) {
 	jms.mu.Lock()
@@ -120,19 +199,20 @@ func (jms *JobMonitorService) SetDefaultCallback(callback JobCompletionCallback)
 	jms.defaultCallback = callback
 }
 
-// GetJob returns job information
+// GetJob returns job information.
+// Falls back to the job store for jobs that finished before the last restart.
 func (jms *JobMonitorService) GetJob(jobID string) (*JobInfo, bool) {
 	jms.mu.RLock()
-	defer jms.mu.RUnlock()
-
 	job, exists := jms.jobs[jobID]
 	if exists {
 		// Return a copy to avoid race conditions
 		jobCopy := *job
+		jms.mu.RUnlock()
 		return &jobCopy, true
 	}
+	jms.mu.RUnlock()
 
-	return nil, false
+	return jms.loadPersistedJob(jobID)
 }
 
 // GetAllJobs returns all jobs information
@@ -227,6 +307,8 @@ func (jms *JobMonitorService) RemoveJob(jobID string) {
 	defer jms.mu.Unlock()
 
 	delete(jms.jobs, jobID)
+	jms.deletePersistedJobLocked(jobID)
+	jms.events.closeJob(jobID)
 	logger.Debugf("Removed job %s from monitoring", jobID)
 }
 
@@ -242,12 +324,34 @@ func (jms *JobMonitorService) Stop() {
 	}
 }
 
+// pollInterval returns JOB_MONITOR_INTERVAL, falling back to 10 seconds when unset
+func pollInterval(cfg config.AppConfig) time.Duration {
+	if cfg.JobMonitorInterval <= 0 {
+		return 10 * time.Second
+	}
+	return cfg.JobMonitorInterval
+}
+
+// onConfigReload hands a changed polling interval to the monitoring loop
+func (jms *JobMonitorService) onConfigReload(prev, next config.AppConfig) {
+	if prev.JobMonitorInterval == next.JobMonitorInterval {
+		return
+	}
+	// Keep only the latest interval if the loop has not picked up the previous one yet
+	select {
+	case <-jms.intervalCh:
+	default:
+	}
+	jms.intervalCh <- pollInterval(next)
+}
+
 // startMonitoring runs the background monitoring loop
 func (jms *JobMonitorService) startMonitoring() {
-	ticker := time.NewTicker(10 * time.Second) // Check every 10 seconds
+	interval := pollInterval(config.Current())
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
-	logger.Infof("Job monitor service started")
+	logger.Infof("Job monitor service started, polling every %v", interval)
 
 	for {
 		select {
@@ -255,6 +359,10 @@ func (jms *JobMonitorService) startMonitoring() {
 			logger.Infof("Job monitoring stopped")
 			return
 
+		case interval = <-jms.intervalCh:
+			ticker.Reset(interval)
+			logger.Infof("Job monitor polling interval changed to %v", interval)
+
 		case <-ticker.C:
 			jms.checkAllJobs()
 		}
@@ -264,6 +372,10 @@ func (jms *JobMonitorService) startMonitoring() {
 // checkAllJobs checks status of all running jobs
 func (jms *JobMonitorService) checkAllJobs() {
 	jms.mu.RLock()
+	if jms.draining {
+		jms.mu.RUnlock()
+		return
+	}
 	runningJobs := make([]*JobInfo, 0)
 	for _, job := range jms.jobs {
 		if job.Status == "running" {
@@ -286,7 +398,11 @@ func (jms *JobMonitorService) checkJobStatus(job *JobInfo) {
 		return
 	}
 
-	statusOutput, err := agent.ExecuteAgentAPISimpleCommand(job.ClientID, job.OsType, "checkstatus", job.JobID, "", true)
+	ctx, span := tracing.Start(tracing.ContextWithTraceParent(context.Background(), job.TraceParent), "job.poll",
+		attribute.String("job.id", job.JobID), attribute.String("job.kind", job.Kind))
+	defer span.End()
+
+	statusOutput, err := agent.WithContext(ctx, jms.agentExecutor()).ExecuteSimpleCommand(job.ClientID, job.OsType, "checkstatus", job.JobID, "", true)
 	if err != nil {
 		logger.Errorf("Failed to check job status for %s: %v", job.JobID, err)
 		jms.updateJobError(job.JobID, fmt.Sprintf("Status check failed: %v", err))
@@ -303,6 +419,12 @@ func (jms *JobMonitorService) checkJobStatus(job *JobInfo) {
 	// Update job status
 	jms.updateJobStatus(job.JobID, &statusResp)
 
+	// Cancellation may race with an in-flight status check - never run callback for cancelled jobs
+	if jms.IsJobCancelled(job.JobID) {
+		logger.Infof("Job %s was cancelled, ignoring agent status %s", job.JobID, statusResp.Status)
+		return
+	}
+
 	logger.Debugf("Job %s status: %s, progress: %d%%", job.JobID, statusResp.Status, statusResp.Progress)
 
 	// Handle completion
@@ -328,6 +450,10 @@ func (jms *JobMonitorService) updateJobStatus(jobID string, statusResp *StatusRe
 		return
 	}
 
+	if isCancelledStatus(job.Status) {
+		return
+	}
+
 	// Skip update if job was already completed via notification to prevent race condition
 	// where polling response arrives after notification and overwrites completed status
 	if job.ProcessedViaNotification {
@@ -362,6 +488,9 @@ func (jms *JobMonitorService) updateJobStatus(jobID string, statusResp *StatusRe
 		now := time.Now()
 		job.EndTime = &now
 	}
+
+	jms.persistJobLocked(job)
+	jms.publishEventLocked(statusEventType(job.Status), job)
 }
 
 // updateJobError updates job with error information
@@ -370,7 +499,7 @@ func (jms *JobMonitorService) updateJobError(jobID, errorMsg string) {
 	defer jms.mu.Unlock()
 
 	job, exists := jms.jobs[jobID]
-	if !exists {
+	if !exists || isCancelledStatus(job.Status) {
 		return
 	}
 
@@ -378,6 +507,9 @@ func (jms *JobMonitorService) updateJobError(jobID, errorMsg string) {
 	job.Error = errorMsg
 	now := time.Now()
 	job.EndTime = &now
+
+	jms.persistJobLocked(job)
+	jms.publishEventLocked(EventTypeFailed, job)
 }
 
 // UpdateJobResults updates job results with proper locking.
@@ -401,6 +533,8 @@ func (jms *JobMonitorService) UpdateJobResults(jobID string, results map[string]
 		existingMap[key] = value
 	}
 	job.Results = existingMap
+	jms.persistJobLocked(job)
+	jms.publishEventLocked(EventTypeResults, job)
 
 	logger.Debugf("Updated results for job %s with %d entries", jobID, len(results))
 }
@@ -417,6 +551,8 @@ func (jms *JobMonitorService) SetJobResults(jobID string, results interface{}) {
 	}
 
 	job.Results = results
+	jms.persistJobLocked(job)
+	jms.publishEventLocked(EventTypeResults, job)
 	logger.Debugf("Set results for job %s", jobID)
 }
 
@@ -433,6 +569,7 @@ func (jms *JobMonitorService) MarkJobAsNoPolling(jobID string) error {
 
 	// Mark as processed to prevent polling loop from checking VeloArtifact status
 	job.ProcessedViaNotification = true
+	jms.persistJobLocked(job)
 	logger.Infof("Marked job %s to skip VeloArtifact polling", jobID)
 
 	return nil
@@ -456,6 +593,11 @@ func (jms *JobMonitorService) CompleteJobWithResults(jobID, status, message stri
 		return fmt.Errorf("job %s not found for immediate completion", jobID)
 	}
 
+	if isCancelledStatus(job.Status) {
+		logger.Infof("Job %s was cancelled, skipping %s completion", jobID, status)
+		return nil
+	}
+
 	logger.Infof("Marking job as %s immediately: job_id=%s, message=%s", status, jobID, message)
 
 	// Mark as processed to prevent monitoring loop from checking status
@@ -473,6 +615,8 @@ func (jms *JobMonitorService) CompleteJobWithResults(jobID, status, message stri
 	job.Completed = totalCompleted
 	job.Failed = failedCount
 	job.EndTime = &now
+	jms.persistJobLocked(job)
+	jms.publishEventLocked(statusEventType(job.Status), job)
 
 	// Create StatusResponse for callback
 	statusResp := &StatusResponse{
@@ -489,7 +633,7 @@ func (jms *JobMonitorService) CompleteJobWithResults(jobID, status, message stri
 	}
 
 	// Execute completion callback asynchronously
-	go jms.executeCompletionCallbackAsync(jobID, job, statusResp)
+	jms.executeCompletionCallbackAsync(jobID, job, statusResp)
 
 	return nil
 }
@@ -504,6 +648,13 @@ func (jms *JobMonitorService) ProcessJobNotification(jobID, fileName, md5Hash st
 		return fmt.Errorf("job %s not found for notification processing", jobID)
 	}
 
+	// Agent may still report results for a job cancelled on the server side. A job applying changes
+	// on the agent that finishes while its cancel is in flight is recorded, CancelJob then gives up.
+	if isCancelledStatus(job.Status) && !(job.Status == "cancelling" && isAgentAppliedKind(job.Kind)) {
+		logger.Infof("Job %s was cancelled, ignoring completion notification for file %s", jobID, fileName)
+		return nil
+	}
+
 	logger.Infof("Processing job notification: job_id=%s, file=%s, md5=%s, success=%v",
 		jobID, fileName, md5Hash, success)
 
@@ -532,6 +683,8 @@ func (jms *JobMonitorService) ProcessJobNotification(jobID, fileName, md5Hash st
 		"md5Hash":  md5Hash,
 		"success":  success,
 	}
+	jms.persistJobLocked(job)
+	jms.publishEventLocked(statusEventType(job.Status), job)
 
 	// Create a StatusResponse for callback compatibility
 	statusResp := &StatusResponse{
@@ -553,17 +706,20 @@ func (jms *JobMonitorService) ProcessJobNotification(jobID, fileName, md5Hash st
 	}
 
 	// Execute completion callback asynchronously
-	go jms.executeCompletionCallbackAsync(jobID, job, statusResp)
+	jms.executeCompletionCallbackAsync(jobID, job, statusResp)
 
 	return nil
 }
 
 // executeCompletionCallback executes the job completion callback
 func (jms *JobMonitorService) executeCompletionCallback(jobID string, job *JobInfo, statusResp *StatusResponse) {
+	jms.mu.Lock()
+	defer jms.mu.Unlock()
 	jms.executeCompletionCallbackAsync(jobID, job, statusResp)
 }
 
-// executeCompletionCallbackAsync executes the job completion callback asynchronously
+// executeCompletionCallbackAsync executes the job completion callback asynchronously.
+// The callback is tracked so Shutdown can wait for it. Caller must hold jms.mu.
 func (jms *JobMonitorService) executeCompletionCallbackAsync(jobID string, job *JobInfo, statusResp *StatusResponse) {
 	// Use job-specific callback if available, otherwise use default
 	callback := job.CompletionCallback
@@ -571,17 +727,44 @@ func (jms *JobMonitorService) executeCompletionCallbackAsync(jobID string, job *
 		callback = jms.defaultCallback
 	}
 
+	if callback != nil && jms.draining {
+		if jms.resumableLocked(job) {
+			logger.Infof("Job monitor is shutting down, completion callback for %s will run after restart", jobID)
+		} else {
+			jms.interruptJobLocked(job, "Server shutting down, completion processing not started")
+		}
+		return
+	}
+
 	if callback != nil {
+		jms.callbacks.Add(1)
+		if jms.callbackJobs == nil {
+			jms.callbackJobs = make(map[string]int)
+		}
+		jms.callbackJobs[jobID]++
+
 		go func() {
+			defer jms.callbackDone(jobID)
+			start := time.Now()
+			// The originating request has usually finished, so the callback starts its own trace linked to it
+			_, span := tracing.StartLinked(job.TraceParent, "job.completion",
+				attribute.String("job.id", jobID), attribute.String("job.kind", job.Kind),
+				attribute.String("job.status", statusResp.Status))
 			defer func() {
 				if r := recover(); r != nil {
+					observeCallback(job.Kind, "panic", start)
+					tracing.End(span, fmt.Errorf("panic: %v", r))
 					logger.Errorf("Job completion callback panic for %s: %v", jobID, r)
 				}
 			}()
 
-			if err := callback(jobID, job, statusResp); err != nil {
+			err := callback(jobID, job, statusResp)
+			tracing.End(span, err)
+			if err != nil {
+				observeCallback(job.Kind, "error", start)
 				logger.Errorf("Job completion callback error for %s: %v", jobID, err)
 			} else {
+				observeCallback(job.Kind, "success", start)
 				logger.Infof("Job completion callback executed successfully for %s", jobID)
 			}
 		}()
@@ -601,6 +784,11 @@ func (jms *JobMonitorService) CompleteJobAfterProcessing(jobID, message string)
 		return fmt.Errorf("job %s not found for completion", jobID)
 	}
 
+	if isCancelledStatus(job.Status) {
+		logger.Infof("Job %s was cancelled, keeping %s status", jobID, job.Status)
+		return nil
+	}
+
 	logger.Infof("Marking job as completed after server processing: job_id=%s, message=%s", jobID, message)
 
 	now := time.Now()
@@ -608,6 +796,8 @@ func (jms *JobMonitorService) CompleteJobAfterProcessing(jobID, message string)
 	job.Progress = 100
 	job.Message = message
 	job.EndTime = &now
+	jms.persistJobLocked(job)
+	jms.publishEventLocked(EventTypeCompleted, job)
 
 	return nil
 }
@@ -623,6 +813,11 @@ func (jms *JobMonitorService) FailJobAfterProcessing(jobID, errorMsg string) err
 		return fmt.Errorf("job %s not found for failure", jobID)
 	}
 
+	if isCancelledStatus(job.Status) {
+		logger.Infof("Job %s was cancelled, keeping %s status (processing error: %s)", jobID, job.Status, errorMsg)
+		return nil
+	}
+
 	logger.Errorf("Marking job as failed after server processing: job_id=%s, error=%s", jobID, errorMsg)
 
 	now := time.Now()
@@ -630,6 +825,129 @@ func (jms *JobMonitorService) FailJobAfterProcessing(jobID, errorMsg string) err
 	job.Message = "Server processing failed"
 	job.Error = errorMsg
 	job.EndTime = &now
+	jms.persistJobLocked(job)
+	jms.publishEventLocked(EventTypeFailed, job)
 
 	return nil
 }
+
+// CancelJob stops a running job: sends cancel to the agent, then marks the job cancelled.
+// Master tracking jobs (no-polling) cancel their OS sub-jobs instead of calling the agent.
+// Completion handlers already in progress observe cancellation via IsJobCancelled and roll back.
+func (jms *JobMonitorService) CancelJob(jobID string) (*JobInfo, error) {
+	jms.mu.Lock()
+	job, exists := jms.jobs[jobID]
+	if !exists {
+		jms.mu.Unlock()
+		return nil, fmt.Errorf("job %s: %w", jobID, ErrJobNotFound)
+	}
+
+	if job.Status != "running" && job.Status != "processing" {
+		status := job.Status
+		jms.mu.Unlock()
+		return nil, fmt.Errorf("job %s has status %s: %w", jobID, status, ErrJobNotCancellable)
+	}
+
+	// A processing job finished on the agent, so changes it made on the database are already applied
+	agentApplied := isAgentAppliedKind(job.Kind)
+	if agentApplied && job.Status == "processing" {
+		jms.mu.Unlock()
+		return nil, fmt.Errorf("job %s already applied its changes on the agent: %w", jobID, ErrJobNotCancellable)
+	}
+
+	previousStatus, previousMessage := job.Status, job.Message
+	job.Status = "cancelling"
+	job.Message = "Cancellation requested"
+	jms.persistJobLocked(job)
+	jms.publishEventLocked(EventTypeStatus, job)
+
+	clientID := job.ClientID
+	osType := job.OsType
+	isTrackingJob := job.ProcessedViaNotification
+	subJobIDs, _ := job.ContextData["os_job_ids"].([]string)
+	jms.mu.Unlock()
+
+	logger.Infof("Cancelling job %s (tracking_job=%v, sub_jobs=%d)", jobID, isTrackingJob, len(subJobIDs))
+
+	// Agent call happens outside the lock since retries can take tens of seconds
+	var cancelWarning string
+	if !isTrackingJob {
+		if _, err := jms.agentExecutor().ExecuteSimpleCommand(clientID, osType, "cancel", jobID, "", false); err != nil {
+			logger.Warnf("Failed to cancel job %s on agent: %v", jobID, err)
+			cancelWarning = fmt.Sprintf("agent cancel failed: %v", err)
+		}
+	}
+
+	// Master jobs (e.g. backup) fan out to agent sub-jobs which are cancelled individually
+	for _, subJobID := range subJobIDs {
+		if _, err := jms.CancelJob(subJobID); err != nil && !errors.Is(err, ErrJobNotCancellable) {
+			logger.Warnf("Failed to cancel sub-job %s of job %s: %v", subJobID, jobID, err)
+		}
+	}
+
+	jms.mu.Lock()
+	defer jms.mu.Unlock()
+
+	job, exists = jms.jobs[jobID]
+	if !exists {
+		return nil, fmt.Errorf("job %s: %w", jobID, ErrJobNotFound)
+	}
+
+	// Without a confirmed cancel the agent may still apply the changes, or already did and
+	// notified meanwhile, so the job goes on and its completion callback records them
+	if agentApplied && (cancelWarning != "" || job.Status != "cancelling") {
+		if job.Status == "cancelling" {
+			job.Status = previousStatus
+			job.Message = previousMessage
+			jms.persistJobLocked(job)
+			jms.publishEventLocked(EventTypeStatus, job)
+		}
+		reason := "agent finished before the cancel"
+		if cancelWarning != "" {
+			reason = cancelWarning
+		}
+		return nil, fmt.Errorf("job %s continues, %s: %w", jobID, reason, ErrJobNotCancellable)
+	}
+
+	now := time.Now()
+	job.Status = "cancelled"
+	job.Message = "Job cancelled"
+	if cancelWarning != "" {
+		job.Message = "Job cancelled on server, " + cancelWarning
+	}
+	job.EndTime = &now
+	jms.persistJobLocked(job)
+	jms.publishEventLocked(EventTypeCancelled, job)
+
+	logger.Infof("Job %s cancelled", jobID)
+
+	jobCopy := *job
+	return &jobCopy, nil
+}
+
+// IsJobCancelled reports whether cancellation was requested for a job.
+// Completion handlers check this before committing so cancelled work is rolled back.
+func (jms *JobMonitorService) IsJobCancelled(jobID string) bool {
+	jms.mu.RLock()
+	defer jms.mu.RUnlock()
+
+	job, exists := jms.jobs[jobID]
+	if !exists {
+		return false
+	}
+	return isCancelledStatus(job.Status)
+}
+
+// CheckCancelled returns ErrJobCancelled (wrapped) when cancellation was requested for a job.
+// Long-running completion handlers call it between processing steps and before committing.
+func (jms *JobMonitorService) CheckCancelled(jobID string) error {
+	if jms.IsJobCancelled(jobID) {
+		return fmt.Errorf("job %s: %w", jobID, ErrJobCancelled)
+	}
+	return nil
+}
+
+// isCancelledStatus returns true for cancelling and cancelled job states
+func isCancelledStatus(status string) bool {
+	return status == "cancelling" || status == "cancelled"
+}
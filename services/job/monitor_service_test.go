@@ -1,8 +1,11 @@
 package job
 
 import (
+	"errors"
 	"testing"
 	"time"
+
+	"dbfartifactapi/services/agent"
 )
 
 // TestGetAllJobsPaginated_EmptyJobs tests pagination with no jobs
@@ -216,3 +219,100 @@ func TestGetAllJobsPaginated_TotalPagesCalculation(t *testing.T) {
 		})
 	}
 }
+
+// TestCancelJob_TrackingJob tests cancellation of a notification-tracked job and that late completion keeps cancelled state
+func TestCancelJob_TrackingJob(t *testing.T) {
+	jms := &JobMonitorService{
+		jobs: make(map[string]*JobInfo),
+	}
+	jms.jobs["tracking-1"] = &JobInfo{
+		JobID:                    "tracking-1",
+		Status:                   "processing",
+		StartTime:                time.Now(),
+		ProcessedViaNotification: true,
+	}
+
+	cancelled, err := jms.CancelJob("tracking-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cancelled.Status != "cancelled" || cancelled.EndTime == nil {
+		t.Errorf("Expected cancelled job with end time, got status=%s end=%v", cancelled.Status, cancelled.EndTime)
+	}
+	if err := jms.CheckCancelled("tracking-1"); !errors.Is(err, ErrJobCancelled) {
+		t.Errorf("Expected ErrJobCancelled, got %v", err)
+	}
+
+	// Handler finishing after cancellation must not overwrite the cancelled state
+	if err := jms.CompleteJobAfterProcessing("tracking-1", "done"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if jms.jobs["tracking-1"].Status != "cancelled" {
+		t.Errorf("Expected status to stay cancelled, got %s", jms.jobs["tracking-1"].Status)
+	}
+}
+
+// TestCancelJob_NotCancellable tests that finished and unknown jobs are rejected
+func TestCancelJob_NotCancellable(t *testing.T) {
+	jms := &JobMonitorService{
+		jobs: make(map[string]*JobInfo),
+	}
+	jms.jobs["done-1"] = &JobInfo{JobID: "done-1", Status: "completed", StartTime: time.Now()}
+
+	if _, err := jms.CancelJob("done-1"); !errors.Is(err, ErrJobNotCancellable) {
+		t.Errorf("Expected ErrJobNotCancellable, got %v", err)
+	}
+	if _, err := jms.CancelJob("missing"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("Expected ErrJobNotFound, got %v", err)
+	}
+}
+
+// cancelFailingExecutor fails every agent call, like an agent that already finished the job
+type cancelFailingExecutor struct{}
+
+func (cancelFailingExecutor) ExecuteSql(agentID, osType, action, hexEncodedJSON, option string, requiredStdout bool) (string, error) {
+	return "", errors.New("agent unavailable")
+}
+
+func (cancelFailingExecutor) ExecuteSimpleCommand(agentID, osType, action, value, option string, requiredStdout bool) (string, error) {
+	return "", errors.New("job already finished")
+}
+
+func (cancelFailingExecutor) DownloadFile(agentID, remotePath, osType string) (*agent.AgentDownloadResponse, error) {
+	return nil, errors.New("agent unavailable")
+}
+
+func (cancelFailingExecutor) ExecuteConnectionTest(clientID string, params agent.ConnectionTestAgentParams, osType string) (*agent.AgentAPIResponse, error) {
+	return nil, errors.New("agent unavailable")
+}
+
+// TestCancelJob_AgentAppliedKind tests that jobs applying changes on the agent are only cancelled while the agent confirms it
+func TestCancelJob_AgentAppliedKind(t *testing.T) {
+	RegisterAgentAppliedKind("test_applied_kind")
+
+	jms := &JobMonitorService{
+		jobs:      make(map[string]*JobInfo),
+		agentExec: cancelFailingExecutor{},
+	}
+	jms.jobs["applied-1"] = &JobInfo{JobID: "applied-1", Kind: "test_applied_kind", Status: "processing", StartTime: time.Now()}
+	jms.jobs["applied-2"] = &JobInfo{JobID: "applied-2", Kind: "test_applied_kind", Status: "running", Message: "Job started", StartTime: time.Now()}
+
+	// Agent finished: the completion callback must record its changes
+	if _, err := jms.CancelJob("applied-1"); !errors.Is(err, ErrJobNotCancellable) {
+		t.Errorf("Expected ErrJobNotCancellable for processing job, got %v", err)
+	}
+	if status := jms.jobs["applied-1"].Status; status != "processing" {
+		t.Errorf("Expected processing job to keep its status, got %s", status)
+	}
+
+	// Agent did not confirm the cancel: the job keeps running
+	if _, err := jms.CancelJob("applied-2"); !errors.Is(err, ErrJobNotCancellable) {
+		t.Errorf("Expected ErrJobNotCancellable when agent cancel fails, got %v", err)
+	}
+	if job := jms.jobs["applied-2"]; job.Status != "running" || job.Message != "Job started" {
+		t.Errorf("Expected job restored to running, got status=%s message=%s", job.Status, job.Message)
+	}
+	if err := jms.CheckCancelled("applied-2"); err != nil {
+		t.Errorf("Expected job not cancelled, got %v", err)
+	}
+}
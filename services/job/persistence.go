@@ -0,0 +1,391 @@
+package job
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"dbfartifactapi/models"
+	"dbfartifactapi/pkg/logger"
+	"dbfartifactapi/repository"
+
+	"gorm.io/gorm"
+)
+
+// resumableStatuses are the job states restored into the monitor on startup.
+// "processing" jobs are reset to "running" so polling re-triggers their completion callback,
+// "cancelling" jobs are finalized as cancelled since the cancel request was already accepted.
+// Jobs whose completion callback committed its changes are interrupted instead of re-running the callback.
+var resumableStatuses = []string{"running", "processing", "cancelling"}
+
+// EnablePersistence attaches a durable job store and rehydrates in-flight jobs from it.
+// Must be called after all job kinds are registered so restored jobs get their callbacks.
+func (jms *JobMonitorService) EnablePersistence(store repository.JobRepository) error {
+	if store == nil {
+		return fmt.Errorf("job store cannot be nil")
+	}
+
+	if err := store.Migrate(); err != nil {
+		return fmt.Errorf("failed to migrate jobs table: %v", err)
+	}
+
+	records, err := store.GetByStatuses(nil, resumableStatuses)
+	if err != nil {
+		return fmt.Errorf("failed to load in-flight jobs: %v", err)
+	}
+
+	writer := newJobWriter(store)
+	defer writer.flush()
+
+	jms.mu.Lock()
+	defer jms.mu.Unlock()
+
+	jms.store = writer
+
+	restored := 0
+	for i := range records {
+		jobInfo, err := jobInfoFromModel(&records[i])
+		if err != nil {
+			logger.Errorf("Failed to restore job %s: %v", records[i].JobID, err)
+			continue
+		}
+
+		if _, exists := jms.jobs[jobInfo.JobID]; exists {
+			continue
+		}
+
+		if jobInfo.Kind != "" {
+			callback, exists := GetJobKindCallback(jobInfo.Kind)
+			if !exists {
+				logger.Warnf("No completion callback registered for job kind %s, restoring job %s without callback",
+					jobInfo.Kind, jobInfo.JobID)
+			}
+			jobInfo.CompletionCallback = callback
+		} else {
+			logger.Warnf("Job %s has no kind, restoring without completion callback", jobInfo.JobID)
+		}
+
+		// Normalize states interrupted by the restart
+		switch {
+		case records[i].CallbackCommitted && jobInfo.Status != "cancelling":
+			now := time.Now()
+			jobInfo.Status = "interrupted"
+			jobInfo.Message = "Results were recorded before restart, steps after the commit may not have run"
+			jobInfo.Error = jobInfo.Message
+			jobInfo.EndTime = &now
+		case jobInfo.Status == "processing":
+			jobInfo.Status = "running"
+			jobInfo.Message = "Resumed after restart, re-checking agent status"
+		case jobInfo.Status == "cancelling":
+			now := time.Now()
+			jobInfo.Status = "cancelled"
+			jobInfo.Message = "Job cancelled (finalized after restart)"
+			jobInfo.EndTime = &now
+		}
+
+		jms.jobs[jobInfo.JobID] = jobInfo
+		jms.persistJobLocked(jobInfo)
+		restored++
+	}
+
+	logger.Infof("Job persistence enabled, restored %d in-flight jobs", restored)
+	return nil
+}
+
+// MarkCallbackCommitted records in tx that the completion callback of jobID committed its changes.
+// Callbacks that are not idempotent call it in the transaction holding their changes, so a job resumed
+// after a restart is interrupted instead of applying them twice. A no-op when persistence is disabled.
+func (jms *JobMonitorService) MarkCallbackCommitted(tx *gorm.DB, jobID string) error {
+	jms.mu.RLock()
+	writer := jms.store
+	jms.mu.RUnlock()
+
+	if writer == nil {
+		return nil
+	}
+	if err := writer.store.MarkCallbackCommitted(tx, jobID); err != nil {
+		return fmt.Errorf("failed to mark completion callback of job %s committed: %v", jobID, err)
+	}
+	return nil
+}
+
+// persistJobLocked queues current job state for the job store.
+// Caller must hold jms.mu. The record is copied here and written by the job writer outside the lock.
+// Failures are logged only - in-memory state stays authoritative.
+func (jms *JobMonitorService) persistJobLocked(job *JobInfo) {
+	if jms.store == nil {
+		return
+	}
+
+	record, err := jobInfoToModel(job)
+	if err != nil {
+		logger.Warnf("Failed to serialize job %s for persistence: %v", job.JobID, err)
+		return
+	}
+	jms.store.enqueue(job.JobID, record)
+}
+
+// deletePersistedJobLocked queues removal of job from the job store. Caller must hold jms.mu.
+func (jms *JobMonitorService) deletePersistedJobLocked(jobID string) {
+	if jms.store == nil {
+		return
+	}
+	jms.store.enqueue(jobID, nil)
+}
+
+// flushPersisted writes queued job records. Caller must not hold jms.mu.
+func (jms *JobMonitorService) flushPersisted() {
+	jms.mu.RLock()
+	writer := jms.store
+	jms.mu.RUnlock()
+
+	if writer != nil {
+		writer.flush()
+	}
+}
+
+// loadPersistedJob reads a job that is no longer in memory (e.g. finished before restart).
+// Caller must not hold jms.mu: the job store is queried.
+func (jms *JobMonitorService) loadPersistedJob(jobID string) (*JobInfo, bool) {
+	jms.mu.RLock()
+	writer := jms.store
+	jms.mu.RUnlock()
+
+	if writer == nil {
+		return nil, false
+	}
+
+	record, err := writer.get(jobID)
+	if err != nil {
+		return nil, false
+	}
+
+	jobInfo, err := jobInfoFromModel(record)
+	if err != nil {
+		logger.Warnf("Failed to restore persisted job %s: %v", jobID, err)
+		return nil, false
+	}
+	return jobInfo, true
+}
+
+// jobWriter writes job records to the job store on its own goroutine, so state transitions made under
+// jms.mu never wait on the database. Only the latest queued record of a job is written.
+type jobWriter struct {
+	store repository.JobRepository
+	wake  chan struct{}
+
+	mu      sync.Mutex
+	pending map[string]*models.Job // nil record deletes the job
+	order   []string
+
+	// Held while writing so writes of one job are never reordered between run and flush
+	writeMu sync.Mutex
+}
+
+func newJobWriter(store repository.JobRepository) *jobWriter {
+	w := &jobWriter{
+		store:   store,
+		wake:    make(chan struct{}, 1),
+		pending: make(map[string]*models.Job),
+	}
+	go w.run()
+	return w
+}
+
+// enqueue replaces any queued record of jobID. A nil record deletes the job.
+func (w *jobWriter) enqueue(jobID string, record *models.Job) {
+	w.mu.Lock()
+	if _, queued := w.pending[jobID]; !queued {
+		w.order = append(w.order, jobID)
+	}
+	w.pending[jobID] = record
+	w.mu.Unlock()
+
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// get returns the queued record of jobID, or reads it from the job store when none is queued.
+func (w *jobWriter) get(jobID string) (*models.Job, error) {
+	w.mu.Lock()
+	record, queued := w.pending[jobID]
+	w.mu.Unlock()
+
+	if queued {
+		if record == nil {
+			return nil, gorm.ErrRecordNotFound
+		}
+		recordCopy := *record
+		return &recordCopy, nil
+	}
+	return w.store.GetByID(nil, jobID)
+}
+
+func (w *jobWriter) run() {
+	for range w.wake {
+		w.flush()
+	}
+}
+
+// flush writes every queued record and returns once they are written.
+func (w *jobWriter) flush() {
+	w.writeMu.Lock()
+	defer w.writeMu.Unlock()
+
+	for {
+		w.mu.Lock()
+		if len(w.order) == 0 {
+			w.mu.Unlock()
+			return
+		}
+		jobID := w.order[0]
+		w.order = w.order[1:]
+		record := w.pending[jobID]
+		delete(w.pending, jobID)
+		w.mu.Unlock()
+
+		if record == nil {
+			if err := w.store.Delete(nil, jobID); err != nil {
+				logger.Warnf("Failed to delete persisted job %s: %v", jobID, err)
+			}
+			continue
+		}
+		if err := w.store.Save(nil, record); err != nil {
+			logger.Warnf("Failed to persist job %s: %v", jobID, err)
+		}
+	}
+}
+
+// jobInfoToModel converts in-memory job to its persisted form.
+func jobInfoToModel(job *JobInfo) (*models.Job, error) {
+	contextJSON, err := encodeContextData(job.ContextData)
+	if err != nil {
+		return nil, err
+	}
+
+	var resultsJSON string
+	if job.Results != nil {
+		data, err := json.Marshal(job.Results)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode results: %v", err)
+		}
+		resultsJSON = string(data)
+	}
+
+	return &models.Job{
+		JobID:                    job.JobID,
+		Kind:                     job.Kind,
+		DBMgtID:                  job.DBMgtID,
+		ClientID:                 job.ClientID,
+		OsType:                   job.OsType,
+		Status:                   job.Status,
+		Progress:                 job.Progress,
+		Message:                  job.Message,
+		Completed:                job.Completed,
+		Failed:                   job.Failed,
+		TotalQueries:             job.TotalQueries,
+		Error:                    job.Error,
+		Results:                  resultsJSON,
+		ContextData:              contextJSON,
+		TraceParent:              job.TraceParent,
+		ProcessedViaNotification: job.ProcessedViaNotification,
+		StartTime:                job.StartTime,
+		EndTime:                  job.EndTime,
+	}, nil
+}
+
+// jobInfoFromModel converts a persisted job back to in-memory form.
+// CompletionCallback is not set here - it is resolved from the kind registry by the caller.
+func jobInfoFromModel(record *models.Job) (*JobInfo, error) {
+	contextData, err := decodeContextData(record.ContextData)
+	if err != nil {
+		return nil, err
+	}
+
+	var results interface{}
+	if record.Results != "" {
+		if err := json.Unmarshal([]byte(record.Results), &results); err != nil {
+			return nil, fmt.Errorf("failed to decode results: %v", err)
+		}
+	}
+
+	return &JobInfo{
+		JobID:                    record.JobID,
+		Kind:                     record.Kind,
+		DBMgtID:                  record.DBMgtID,
+		ClientID:                 record.ClientID,
+		OsType:                   record.OsType,
+		Status:                   record.Status,
+		Progress:                 record.Progress,
+		StartTime:                record.StartTime,
+		EndTime:                  record.EndTime,
+		Message:                  record.Message,
+		Completed:                record.Completed,
+		Failed:                   record.Failed,
+		TotalQueries:             record.TotalQueries,
+		Error:                    record.Error,
+		Results:                  results,
+		ContextData:              contextData,
+		TraceParent:              record.TraceParent,
+		ProcessedViaNotification: record.ProcessedViaNotification,
+	}, nil
+}
+
+// encodeContextData serializes context values key by key.
+// Values that cannot be encoded are skipped so one bad entry doesn't block persistence.
+func encodeContextData(contextData map[string]interface{}) (string, error) {
+	if len(contextData) == 0 {
+		return "", nil
+	}
+
+	encoded := make(map[string]json.RawMessage, len(contextData))
+	for key, value := range contextData {
+		data, err := json.Marshal(value)
+		if err != nil {
+			logger.Warnf("Skipping context key %s during job persistence: %v", key, err)
+			continue
+		}
+		encoded[key] = data
+	}
+
+	data, err := json.Marshal(encoded)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode context data: %v", err)
+	}
+	return string(data), nil
+}
+
+// decodeContextData restores context values using types from the context type registry.
+func decodeContextData(raw string) (map[string]interface{}, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	var encoded map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(raw), &encoded); err != nil {
+		return nil, fmt.Errorf("failed to decode context data: %v", err)
+	}
+
+	contextData := make(map[string]interface{}, len(encoded))
+	for key, data := range encoded {
+		if t, exists := getContextType(key); exists {
+			target := reflect.New(t)
+			if err := json.Unmarshal(data, target.Interface()); err != nil {
+				return nil, fmt.Errorf("failed to decode context key %s: %v", key, err)
+			}
+			contextData[key] = target.Elem().Interface()
+			continue
+		}
+
+		var value interface{}
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, fmt.Errorf("failed to decode context key %s: %v", key, err)
+		}
+		contextData[key] = value
+	}
+	return contextData, nil
+}
@@ -0,0 +1,217 @@
+package job
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"dbfartifactapi/models"
+	"dbfartifactapi/pkg/tracing"
+
+	"gorm.io/gorm"
+)
+
+// fakeJobStore is an in-memory JobRepository for persistence tests
+type fakeJobStore struct {
+	mu      sync.Mutex
+	records map[string]models.Job
+}
+
+func (f *fakeJobStore) Migrate() error { return nil }
+
+func (f *fakeJobStore) Save(tx *gorm.DB, job *models.Job) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	record := *job
+	record.CallbackCommitted = f.records[job.JobID].CallbackCommitted
+	f.records[job.JobID] = record
+	return nil
+}
+
+func (f *fakeJobStore) GetByID(tx *gorm.DB, jobID string) (*models.Job, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	record, exists := f.records[jobID]
+	if !exists {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &record, nil
+}
+
+func (f *fakeJobStore) GetByStatuses(tx *gorm.DB, statuses []string) ([]models.Job, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var result []models.Job
+	for _, record := range f.records {
+		for _, status := range statuses {
+			if record.Status == status {
+				result = append(result, record)
+			}
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeJobStore) Delete(tx *gorm.DB, jobID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.records, jobID)
+	return nil
+}
+
+func (f *fakeJobStore) MarkCallbackCommitted(tx *gorm.DB, jobID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	record := f.records[jobID]
+	record.JobID = jobID
+	record.CallbackCommitted = true
+	f.records[jobID] = record
+	return nil
+}
+
+type testJobContext struct {
+	CntMgtID uint   `json:"cnt_mgt_id"`
+	Name     string `json:"name"`
+}
+
+// TestContextDataRoundTrip_RegisteredTypes tests that registered context keys keep their Go type
+func TestContextDataRoundTrip_RegisteredTypes(t *testing.T) {
+	RegisterContextType("test_context", &testJobContext{})
+	RegisterContextType("test_ids", []string{})
+
+	encoded, err := encodeContextData(map[string]interface{}{
+		"test_context": &testJobContext{CntMgtID: 7, Name: "orcl"},
+		"test_ids":     []string{"a", "b"},
+		"other":        "value",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected encode error: %v", err)
+	}
+
+	decoded, err := decodeContextData(encoded)
+	if err != nil {
+		t.Fatalf("Unexpected decode error: %v", err)
+	}
+
+	ctx, ok := decoded["test_context"].(*testJobContext)
+	if !ok {
+		t.Fatalf("Expected *testJobContext, got %T", decoded["test_context"])
+	}
+	if ctx.CntMgtID != 7 || ctx.Name != "orcl" {
+		t.Errorf("Unexpected context values: %+v", ctx)
+	}
+	if ids, ok := decoded["test_ids"].([]string); !ok || len(ids) != 2 {
+		t.Errorf("Expected []string with 2 items, got %T %v", decoded["test_ids"], decoded["test_ids"])
+	}
+	if decoded["other"] != "value" {
+		t.Errorf("Expected unregistered key restored as generic value, got %v", decoded["other"])
+	}
+}
+
+// TestEnablePersistence_RestoresInFlightJobs tests rehydration of running and processing jobs
+func TestEnablePersistence_RestoresInFlightJobs(t *testing.T) {
+	called := false
+	RegisterJobKind("test_kind", func(jobID string, jobInfo *JobInfo, statusResp *StatusResponse) error {
+		called = true
+		return nil
+	})
+
+	store := &fakeJobStore{records: map[string]models.Job{
+		"running-1":    {JobID: "running-1", Kind: "test_kind", Status: "running", StartTime: time.Now()},
+		"processing-1": {JobID: "processing-1", Kind: "test_kind", Status: "processing", StartTime: time.Now()},
+		"completed-1":  {JobID: "completed-1", Kind: "test_kind", Status: "completed", StartTime: time.Now()},
+	}}
+
+	jms := &JobMonitorService{
+		jobs: make(map[string]*JobInfo),
+	}
+
+	if err := jms.EnablePersistence(store); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(jms.jobs) != 2 {
+		t.Fatalf("Expected 2 restored jobs, got %d", len(jms.jobs))
+	}
+	if jms.jobs["processing-1"].Status != "running" {
+		t.Errorf("Expected processing job reset to running, got %s", jms.jobs["processing-1"].Status)
+	}
+	if jms.jobs["running-1"].CompletionCallback == nil {
+		t.Fatalf("Expected completion callback restored from kind registry")
+	}
+	_ = jms.jobs["running-1"].CompletionCallback("running-1", jms.jobs["running-1"], &StatusResponse{})
+	if !called {
+		t.Errorf("Expected restored callback to be the registered one")
+	}
+
+	// Completed jobs are not restored into memory but remain queryable from the store
+	job, exists := jms.GetJob("completed-1")
+	if !exists || job.Status != "completed" {
+		t.Errorf("Expected completed job to be readable from store, got exists=%v", exists)
+	}
+}
+
+// TestAddJobWithKind_PersistsTraceParent tests that the request trace context is stored with the job
+func TestAddJobWithKind_PersistsTraceParent(t *testing.T) {
+	store := &fakeJobStore{records: map[string]models.Job{}}
+	jms := &JobMonitorService{
+		jobs:  make(map[string]*JobInfo),
+		store: newJobWriter(store),
+	}
+
+	traceParent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	ctx := tracing.ContextWithTraceParent(context.Background(), traceParent)
+	jms.AddJobWithKind(ctx, "traced-1", "test_kind", 1, "L.0001", "linux", nil)
+	jms.flushPersisted()
+
+	if got := store.records["traced-1"].TraceParent; got != traceParent {
+		t.Errorf("Persisted trace_parent = %q, want %q", got, traceParent)
+	}
+	restored, err := jobInfoFromModel(&models.Job{JobID: "traced-1", TraceParent: traceParent})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if restored.TraceParent != traceParent {
+		t.Errorf("Restored trace_parent = %q, want %q", restored.TraceParent, traceParent)
+	}
+}
+
+// TestEnablePersistence_InterruptsCommittedCallbacks tests that a callback whose changes were committed
+// before the restart is not run again
+func TestEnablePersistence_InterruptsCommittedCallbacks(t *testing.T) {
+	RegisterJobKind("test_kind", func(jobID string, jobInfo *JobInfo, statusResp *StatusResponse) error {
+		return nil
+	})
+
+	store := &fakeJobStore{records: map[string]models.Job{
+		"processing-1": {JobID: "processing-1", Kind: "test_kind", Status: "processing", StartTime: time.Now()},
+		"running-1":    {JobID: "running-1", Kind: "test_kind", Status: "running", StartTime: time.Now()},
+	}}
+	jms := &JobMonitorService{
+		jobs:  make(map[string]*JobInfo),
+		store: newJobWriter(store),
+	}
+
+	// The callback commits its changes, then the process stops before the job is finalized
+	if err := jms.MarkCallbackCommitted(nil, "processing-1"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	restarted := &JobMonitorService{
+		jobs: make(map[string]*JobInfo),
+	}
+	if err := restarted.EnablePersistence(store); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got := restarted.jobs["processing-1"].Status; got != "interrupted" {
+		t.Errorf("Committed job status = %q, want %q", got, "interrupted")
+	}
+	if got := restarted.jobs["running-1"].Status; got != "running" {
+		t.Errorf("Uncommitted job status = %q, want %q", got, "running")
+	}
+	if got := store.records["processing-1"]; got.Status != "interrupted" || !got.CallbackCommitted {
+		t.Errorf("Persisted committed job = status %q committed %v, want interrupted and committed", got.Status, got.CallbackCommitted)
+	}
+}
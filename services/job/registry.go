@@ -0,0 +1,82 @@
+package job
+
+import (
+	"reflect"
+	"sync"
+
+	"dbfartifactapi/pkg/logger"
+)
+
+// Job kind registry allows completion callbacks to be resolved by name instead of
+// by closure, so jobs restored from the job store can resume with their handler.
+// Owning packages register context payload types at init time. Kinds whose callbacks call
+// agents are registered at startup, once the agent executor they are built with is chosen.
+var (
+	registryMu       sync.RWMutex
+	kindCallbacks    = make(map[string]JobCompletionCallback)
+	contextDataTypes = make(map[string]reflect.Type)
+	agentAppliedKind = make(map[string]bool)
+)
+
+// RegisterJobKind registers the completion callback used for jobs of the given kind.
+func RegisterJobKind(kind string, callback JobCompletionCallback) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, exists := kindCallbacks[kind]; exists {
+		logger.Warnf("Job kind %s already registered, overriding callback", kind)
+	}
+	kindCallbacks[kind] = callback
+}
+
+// GetJobKindCallback returns the completion callback registered for a job kind.
+func GetJobKindCallback(kind string) (JobCompletionCallback, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	callback, exists := kindCallbacks[kind]
+	return callback, exists
+}
+
+// RegisterAgentAppliedKind marks jobs of kind as changing the target database on the agent
+// (e.g. GRANT/REVOKE). Once the agent has run them their changes exist, so CancelJob refuses
+// to cancel them unless the agent confirms the cancel, and their completion callback records the changes.
+func RegisterAgentAppliedKind(kind string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	agentAppliedKind[kind] = true
+}
+
+// isAgentAppliedKind reports whether kind was registered with RegisterAgentAppliedKind.
+func isAgentAppliedKind(kind string) bool {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	return agentAppliedKind[kind]
+}
+
+// RegisterContextType registers the concrete type stored under a ContextData key.
+// Restored context values keep the same Go type that completion handlers assert on,
+// e.g. RegisterContextType("backup_context", &BackupJobContext{}) restores a *BackupJobContext.
+// Keys without a registered type are restored as generic JSON values.
+func RegisterContextType(key string, sample interface{}) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	contextDataTypes[key] = reflect.TypeOf(sample)
+}
+
+// getContextType returns the registered type for a ContextData key.
+func getContextType(key string) (reflect.Type, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	t, exists := contextDataTypes[key]
+	return t, exists
+}
+
+func init() {
+	// notification_data is written by ProcessJobNotification itself
+	RegisterContextType("notification_data", map[string]interface{}{})
+}
@@ -0,0 +1,97 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"dbfartifactapi/pkg/logger"
+)
+
+// Shutdown drains the job monitor for a graceful stop.
+// Polling stops, new jobs are no longer monitored and no new completion callbacks start; callbacks already
+// running are waited for until ctx expires. Jobs left unfinished are then flushed to the job store so the next
+// start resumes them, or marked interrupted when they cannot be resumed. Live event streams are closed.
+// Returns an error when callbacks were still running at the deadline.
+func (jms *JobMonitorService) Shutdown(ctx context.Context) error {
+	jms.mu.Lock()
+	jms.draining = true
+	if !jms.stopped {
+		close(jms.stopCh)
+		jms.stopped = true
+	}
+	inFlight := len(jms.callbackJobs)
+	jms.mu.Unlock()
+
+	logger.Infof("Job monitor draining, waiting for %d in-flight completion callbacks", inFlight)
+
+	done := make(chan struct{})
+	go func() {
+		jms.callbacks.Wait()
+		close(done)
+	}()
+
+	var err error
+	select {
+	case <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("completion callbacks still running at shutdown deadline: %w", ctx.Err())
+	}
+
+	jms.mu.Lock()
+	flushed, interrupted := 0, 0
+	for jobID, job := range jms.jobs {
+		_, callbackRunning := jms.callbackJobs[jobID]
+		if job.Status != "running" && job.Status != "processing" && !callbackRunning {
+			continue
+		}
+		if jms.resumableLocked(job) {
+			jms.persistJobLocked(job)
+			flushed++
+			continue
+		}
+		jms.interruptJobLocked(job, "Interrupted by server shutdown")
+		interrupted++
+	}
+	jms.events.closeAll()
+	jms.mu.Unlock()
+
+	// Queued records are written outside the lock
+	jms.flushPersisted()
+
+	logger.Infof("Job monitor shut down: %d jobs flushed for resume, %d marked interrupted", flushed, interrupted)
+	return err
+}
+
+// resumableLocked reports whether job is picked up again by the next start.
+// Only polled jobs resume: the agent is asked for their status again, which re-runs the completion callback
+// unless the callback already marked its changes committed (see MarkCallbackCommitted).
+// Caller must hold jms.mu.
+func (jms *JobMonitorService) resumableLocked(job *JobInfo) bool {
+	return jms.store != nil && !job.ProcessedViaNotification &&
+		(job.Status == "running" || job.Status == "processing")
+}
+
+// interruptJobLocked ends job with status interrupted. Caller must hold jms.mu.
+func (jms *JobMonitorService) interruptJobLocked(job *JobInfo, message string) {
+	now := time.Now()
+	job.Status = "interrupted"
+	job.Message = message
+	job.Error = message
+	job.EndTime = &now
+	jms.persistJobLocked(job)
+	jms.publishEventLocked(EventTypeInterrupted, job)
+	logger.Warnf("Job %s interrupted: %s", job.JobID, message)
+}
+
+// callbackDone marks a completion callback for jobID as finished.
+func (jms *JobMonitorService) callbackDone(jobID string) {
+	jms.mu.Lock()
+	if jms.callbackJobs[jobID] <= 1 {
+		delete(jms.callbackJobs, jobID)
+	} else {
+		jms.callbackJobs[jobID]--
+	}
+	jms.mu.Unlock()
+	jms.callbacks.Done()
+}
@@ -0,0 +1,124 @@
+package job
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"dbfartifactapi/models"
+)
+
+func newShutdownTestMonitor() *JobMonitorService {
+	return &JobMonitorService{
+		jobs:   make(map[string]*JobInfo),
+		stopCh: make(chan struct{}),
+	}
+}
+
+// TestShutdown_WaitsForCallbacks tests that Shutdown returns only after in-flight completion callbacks finish
+func TestShutdown_WaitsForCallbacks(t *testing.T) {
+	jms := newShutdownTestMonitor()
+	finished := make(chan struct{})
+	callback := func(jobID string, jobInfo *JobInfo, statusResp *StatusResponse) error {
+		time.Sleep(50 * time.Millisecond)
+		close(finished)
+		return nil
+	}
+	jms.AddJobWithCallback("job-1", 1, "client", "linux", callback, nil)
+	if err := jms.CompleteJobImmediately("job-1", "done", 1); err != nil {
+		t.Fatalf("CompleteJobImmediately: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := jms.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	select {
+	case <-finished:
+	default:
+		t.Fatal("Shutdown returned before the completion callback finished")
+	}
+	if job, _ := jms.GetJob("job-1"); job.Status != "completed" {
+		t.Errorf("Expected completed job to keep its status, got %s", job.Status)
+	}
+}
+
+// TestShutdown_InterruptsUnfinishedJobs tests the deadline path without a job store
+func TestShutdown_InterruptsUnfinishedJobs(t *testing.T) {
+	jms := newShutdownTestMonitor()
+	release := make(chan struct{})
+	defer close(release)
+	blocking := func(jobID string, jobInfo *JobInfo, statusResp *StatusResponse) error {
+		<-release
+		return nil
+	}
+	jms.AddJobWithCallback("slow-callback", 1, "client", "linux", blocking, nil)
+	jms.AddJob("still-running", 1, "client", "linux")
+	if err := jms.CompleteJobImmediately("slow-callback", "done", 1); err != nil {
+		t.Fatalf("CompleteJobImmediately: %v", err)
+	}
+
+	events, unsubscribe, err := jms.SubscribeJobEvents("still-running")
+	if err != nil {
+		t.Fatalf("SubscribeJobEvents: %v", err)
+	}
+	defer unsubscribe()
+	<-events // initial snapshot
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := jms.Shutdown(ctx); err == nil {
+		t.Error("Expected error when callbacks are still running at the deadline")
+	}
+
+	for _, jobID := range []string{"slow-callback", "still-running"} {
+		job, _ := jms.GetJob(jobID)
+		if job.Status != "interrupted" || job.EndTime == nil {
+			t.Errorf("Expected job %s interrupted with end time, got status %s", jobID, job.Status)
+		}
+	}
+
+	event := <-events
+	if event.Type != EventTypeInterrupted || !event.IsTerminal() {
+		t.Errorf("Expected terminal interrupted event, got %+v", event)
+	}
+	if _, ok := <-events; ok {
+		t.Error("Expected event stream to be closed after shutdown")
+	}
+
+	jms.AddJob("late", 1, "client", "linux")
+	if _, exists := jms.GetJob("late"); exists {
+		t.Error("Expected jobs added after shutdown not to be monitored")
+	}
+}
+
+// TestShutdown_FlushesResumableJobs tests that polled jobs are left in the job store for the next start
+func TestShutdown_FlushesResumableJobs(t *testing.T) {
+	store := &fakeJobStore{records: make(map[string]models.Job)}
+	jms := newShutdownTestMonitor()
+	jms.store = newJobWriter(store)
+
+	jms.AddJob("polled", 1, "client", "linux")
+	jms.AddJob("tracking", 1, "client", "linux")
+	if err := jms.MarkJobAsNoPolling("tracking"); err != nil {
+		t.Fatalf("MarkJobAsNoPolling: %v", err)
+	}
+
+	if err := jms.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	if got := store.records["polled"].Status; got != "running" {
+		t.Errorf("Expected polled job persisted as running, got %s", got)
+	}
+	if got := store.records["tracking"].Status; got != "interrupted" {
+		t.Errorf("Expected no-polling job persisted as interrupted, got %s", got)
+	}
+
+	jms.AddJob("late", 1, "client", "linux")
+	if got := store.records["late"].Status; got != "running" {
+		t.Errorf("Expected job added during shutdown persisted as running, got %q", got)
+	}
+}
@@ -9,8 +9,10 @@ import (
 
 	"dbfartifactapi/models"
 	"dbfartifactapi/pkg/logger"
+	"dbfartifactapi/pkg/secrets"
 	"dbfartifactapi/repository"
 	"dbfartifactapi/services/agent"
+	"dbfartifactapi/services/audit"
 	"dbfartifactapi/services/dto"
 	"dbfartifactapi/utils"
 )
@@ -60,6 +62,7 @@ type pdbService struct {
 	baseRepo     repository.BaseRepository
 	cntMgtRepo   repository.CntMgtRepository
 	endpointRepo repository.EndpointRepository
+	agentExec    agent.AgentExecutor
 }
 
 // NewPDBService creates a new PDB management service instance.
@@ -68,6 +71,7 @@ func NewPDBService() PDBService {
 		baseRepo:     repository.NewBaseRepository(),
 		cntMgtRepo:   repository.NewCntMgtRepository(),
 		endpointRepo: repository.NewEndpointRepository(),
+		agentExec:    agent.DefaultExecutor(),
 	}
 }
 
@@ -77,11 +81,13 @@ func NewPDBServiceWithDeps(
 	baseRepo repository.BaseRepository,
 	cntMgtRepo repository.CntMgtRepository,
 	endpointRepo repository.EndpointRepository,
+	agentExec agent.AgentExecutor,
 ) PDBService {
 	return &pdbService{
 		baseRepo:     baseRepo,
 		cntMgtRepo:   cntMgtRepo,
 		endpointRepo: endpointRepo,
+		agentExec:    agentExec,
 	}
 }
 
@@ -125,7 +131,7 @@ func (s *pdbService) GetAll(ctx context.Context, cntMgtID uint) (int, error) {
 		SetHost(cdb.IP).
 		SetPort(cdb.Port).
 		SetUser(cdb.Username).
-		SetPassword(cdb.Password).
+		SetCredentials(cdb.Password, cdb.SecretRef).
 		SetDatabase(cdb.ServiceName).
 		SetQuery(pdbGetAllSQL).
 		Build()
@@ -137,7 +143,7 @@ func (s *pdbService) GetAll(ctx context.Context, cntMgtID uint) (int, error) {
 	}
 	logger.Debugf("Created agent command JSON payload (hex): %s", hexJSON)
 
-	stdout, err := agent.ExecuteSqlAgentAPI(ep.ClientID, ep.OsType, "execute", hexJSON, "", true)
+	stdout, err := agent.WithContext(ctx, s.agentExec).ExecuteSql(ep.ClientID, ep.OsType, "execute", hexJSON, "", true)
 	if err != nil {
 		tx.Rollback()
 		return 0, fmt.Errorf("executeSqlAgentAPI error: %w", err)
@@ -208,6 +214,13 @@ func (s *pdbService) GetAll(ctx context.Context, cntMgtID uint) (int, error) {
 		localPDBs[strings.ToLower(pdb.CntName)] = pdb
 	}
 
+	// Inherited CDB credentials are stored under the active key even if the CDB row predates it
+	pdbPassword, err := secrets.Default().Reseal(cdb.Password)
+	if err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("failed to encrypt credentials for PDBs of CDB %d: %w", cdb.ID, err)
+	}
+
 	insertCount := 0
 	deleteCount := 0
 
@@ -222,7 +235,8 @@ func (s *pdbService) GetAll(ctx context.Context, cntMgtID uint) (int, error) {
 				Port:               cdb.Port,
 				ConfigFilePath:     cdb.ConfigFilePath,
 				Username:           cdb.Username,
-				Password:           cdb.Password,
+				Password:           pdbPassword,
+				SecretRef:          cdb.SecretRef,
 				UserIP:             cdb.UserIP,
 				Agent:              cdb.Agent,
 				Status:             "enabled",
@@ -317,7 +331,7 @@ func (s *pdbService) Create(ctx context.Context, req PDBCreateRequest) (*models.
 		SetHost(cdb.IP).
 		SetPort(cdb.Port).
 		SetUser(cdb.Username).
-		SetPassword(cdb.Password).
+		SetCredentials(cdb.Password, cdb.SecretRef).
 		SetDatabase(cdb.ServiceName).
 		SetQuery(finalSQL).
 		Build()
@@ -329,12 +343,19 @@ func (s *pdbService) Create(ctx context.Context, req PDBCreateRequest) (*models.
 	}
 	logger.Debugf("Created agent command JSON payload (hex): %s", hexJSON)
 
-	_, err = agent.ExecuteSqlAgentAPI(ep.ClientID, ep.OsType, "execute", hexJSON, "", false)
+	audit.AddCommand(ctx, finalSQL)
+	_, err = agent.WithContext(ctx, s.agentExec).ExecuteSql(ep.ClientID, ep.OsType, "execute", hexJSON, "", false)
 	if err != nil {
 		tx.Rollback()
 		return nil, fmt.Errorf("failed to create PDB %s on remote server: %w", req.PDBName, err)
 	}
 
+	pdbPassword, err := secrets.Default().Reseal(cdb.Password)
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to encrypt credentials for PDB %s: %w", req.PDBName, err)
+	}
+
 	// Register PDB in cntmgt with connection details inherited from parent CDB
 	// Oracle service_name defaults to lowercase PDB name
 	parentID := cdb.ID
@@ -345,7 +366,8 @@ func (s *pdbService) Create(ctx context.Context, req PDBCreateRequest) (*models.
 		Port:               cdb.Port,
 		ConfigFilePath:     cdb.ConfigFilePath,
 		Username:           cdb.Username,
-		Password:           cdb.Password,
+		Password:           pdbPassword,
+		SecretRef:          cdb.SecretRef,
 		UserIP:             cdb.UserIP,
 		Agent:              cdb.Agent,
 		Status:             "enabled",
@@ -360,6 +382,16 @@ func (s *pdbService) Create(ctx context.Context, req PDBCreateRequest) (*models.
 		return nil, fmt.Errorf("failed to insert PDB record %s: %w", req.PDBName, err)
 	}
 
+	if err := audit.Record(ctx, tx, audit.Entry{
+		EntityType: "pdb",
+		EntityID:   fmt.Sprint(pdbRecord.ID),
+		Action:     "create",
+		After:      pdbRecord,
+	}); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return nil, fmt.Errorf("failed to commit PDB creation transaction: %w", err)
 	}
@@ -418,7 +450,7 @@ func (s *pdbService) Update(ctx context.Context, id uint, req PDBUpdateRequest)
 		SetHost(cdb.IP).
 		SetPort(cdb.Port).
 		SetUser(cdb.Username).
-		SetPassword(cdb.Password).
+		SetCredentials(cdb.Password, cdb.SecretRef).
 		SetDatabase(cdb.ServiceName).
 		SetQuery(finalSQL).
 		Build()
@@ -430,12 +462,23 @@ func (s *pdbService) Update(ctx context.Context, id uint, req PDBUpdateRequest)
 	}
 	logger.Debugf("Created agent command JSON payload (hex): %s", hexJSON)
 
-	_, err = agent.ExecuteSqlAgentAPI(ep.ClientID, ep.OsType, "execute", hexJSON, "", false)
+	audit.AddCommand(ctx, finalSQL)
+	_, err = agent.WithContext(ctx, s.agentExec).ExecuteSql(ep.ClientID, ep.OsType, "execute", hexJSON, "", false)
 	if err != nil {
 		tx.Rollback()
 		return fmt.Errorf("failed to alter PDB %s on remote server: %w", pdbRec.CntName, err)
 	}
 
+	if err := audit.Record(ctx, tx, audit.Entry{
+		EntityType: "pdb",
+		EntityID:   fmt.Sprint(id),
+		Action:     "alter",
+		Before:     pdbRec,
+	}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return fmt.Errorf("failed to commit PDB alter transaction: %w", err)
 	}
@@ -503,7 +546,7 @@ func (s *pdbService) Delete(ctx context.Context, id uint, sqlParam string) error
 		SetHost(cdb.IP).
 		SetPort(cdb.Port).
 		SetUser(cdb.Username).
-		SetPassword(cdb.Password).
+		SetCredentials(cdb.Password, cdb.SecretRef).
 		SetDatabase(cdb.ServiceName).
 		SetQuery(finalSQL).
 		Build()
@@ -515,7 +558,8 @@ func (s *pdbService) Delete(ctx context.Context, id uint, sqlParam string) error
 	}
 	logger.Debugf("Created agent command JSON payload (hex): %s", hexJSON)
 
-	_, err = agent.ExecuteSqlAgentAPI(ep.ClientID, ep.OsType, "execute", hexJSON, "", false)
+	audit.AddCommand(ctx, finalSQL)
+	_, err = agent.WithContext(ctx, s.agentExec).ExecuteSql(ep.ClientID, ep.OsType, "execute", hexJSON, "", false)
 	if err != nil {
 		tx.Rollback()
 		return fmt.Errorf("failed to drop PDB %s on remote server: %w", pdbRec.CntName, err)
@@ -526,6 +570,16 @@ func (s *pdbService) Delete(ctx context.Context, id uint, sqlParam string) error
 		return fmt.Errorf("failed to delete PDB record with id=%d: %w", id, err)
 	}
 
+	if err := audit.Record(ctx, tx, audit.Entry{
+		EntityType: "pdb",
+		EntityID:   fmt.Sprint(id),
+		Action:     "delete",
+		Before:     pdbRec,
+	}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return fmt.Errorf("failed to commit PDB deletion transaction: %w", err)
 	}
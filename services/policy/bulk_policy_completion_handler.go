@@ -1,22 +1,27 @@
 package policy
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	"dbfartifactapi/config"
 	"dbfartifactapi/models"
 	"dbfartifactapi/pkg/logger"
 	"dbfartifactapi/repository"
+	"dbfartifactapi/services/agent"
+	"dbfartifactapi/services/audit"
 	"dbfartifactapi/services/dto"
 	"dbfartifactapi/services/job"
+	"dbfartifactapi/services/policy/revision"
 	"dbfartifactapi/utils"
 )
 
 // CreateBulkPolicyUpdateCompletionHandler creates a callback function for bulk policy update job completion
 // Processes VeloArtifact results and atomically updates database with policy changes
-func CreateBulkPolicyUpdateCompletionHandler() job.JobCompletionCallback {
+func CreateBulkPolicyUpdateCompletionHandler(agentExec agent.AgentExecutor) job.JobCompletionCallback {
 	return func(jobID string, jobInfo *job.JobInfo, statusResp *job.StatusResponse) error {
 		logger.Infof("Processing bulk policy update completion for job %s, status: %s", jobID, statusResp.Status)
 
@@ -28,7 +33,7 @@ func CreateBulkPolicyUpdateCompletionHandler() job.JobCompletionCallback {
 
 		// Process completed jobs regardless of completion method (polling or notification)
 		if statusResp.Status == "completed" {
-			return processBulkPolicyUpdateResults(jobID, contextData, statusResp, jobInfo)
+			return processBulkPolicyUpdateResults(agentExec, jobID, contextData, statusResp, jobInfo)
 		} else {
 			logger.Errorf("Bulk policy update job %s failed, no database changes will be applied", jobID)
 			return fmt.Errorf("bulk policy update job failed: %s", statusResp.Message)
@@ -37,7 +42,7 @@ func CreateBulkPolicyUpdateCompletionHandler() job.JobCompletionCallback {
 }
 
 // processBulkPolicyUpdateResults processes the results of a completed bulk policy update job
-func processBulkPolicyUpdateResults(jobID string, contextData interface{}, statusResp *job.StatusResponse, jobInfo *job.JobInfo) error {
+func processBulkPolicyUpdateResults(agentExec agent.AgentExecutor, jobID string, contextData interface{}, statusResp *job.StatusResponse, jobInfo *job.JobInfo) error {
 	logger.Infof("Processing bulk policy update results for job %s - completed: %d, failed: %d",
 		jobID, statusResp.Completed, statusResp.Failed)
 
@@ -53,7 +58,7 @@ func processBulkPolicyUpdateResults(jobID string, contextData interface{}, statu
 	}
 
 	// Legacy VeloArtifact polling flow
-	return processBulkPolicyUpdateResultsFromVeloArtifact(jobID, bulkContext, statusResp)
+	return processBulkPolicyUpdateResultsFromVeloArtifact(agentExec, jobID, bulkContext, statusResp)
 }
 
 // processBulkPolicyUpdateResultsFromNotification handles bulk policy update processing when triggered by external notification
@@ -107,7 +112,7 @@ func processBulkPolicyUpdateResultsFromNotification(jobID string, bulkContext *d
 }
 
 // processBulkPolicyUpdateResultsFromVeloArtifact handles bulk policy update processing via traditional VeloArtifact polling
-func processBulkPolicyUpdateResultsFromVeloArtifact(jobID string, bulkContext *dto.BulkPolicyUpdateJobContext, statusResp *job.StatusResponse) error {
+func processBulkPolicyUpdateResultsFromVeloArtifact(agentExec agent.AgentExecutor, jobID string, bulkContext *dto.BulkPolicyUpdateJobContext, statusResp *job.StatusResponse) error {
 	logger.Infof("Processing bulk policy update results from VeloArtifact polling for job %s", jobID)
 
 	// Get endpoint information
@@ -117,7 +122,7 @@ func processBulkPolicyUpdateResultsFromVeloArtifact(jobID string, bulkContext *d
 	}
 
 	// Retrieve and download results file via VeloArtifact
-	resultsData, err := RetrieveJobResults(jobID, ep)
+	resultsData, err := RetrieveJobResults(agentExec, jobID, ep)
 	if err != nil {
 		return err
 	}
@@ -192,6 +197,8 @@ func applyBulkPolicyUpdates(jobID string, bulkContext *dto.BulkPolicyUpdateJobCo
 		return 0, 0, fmt.Errorf("%s - details logged to %s", errMsg, logFilePath)
 	}
 
+	// The agent already applied the GRANT/REVOKE commands, so the job is no longer cancellable
+	// (see job.RegisterAgentAppliedKind) and the policy rows must follow them
 	logger.Infof("All %d commands executed successfully - proceeding with database updates", len(resultsData))
 
 	// Step 2: Create new transaction for atomic database updates
@@ -208,6 +215,7 @@ func applyBulkPolicyUpdates(jobID string, bulkContext *dto.BulkPolicyUpdateJobCo
 
 	// Step 3: Delete policies that were removed (revoke permissions)
 	var removedCount int
+	var removedPolicies, createdPolicies []models.DBPolicy
 	if len(bulkContext.PolicesToRemove) > 0 {
 		policyIDsToDelete := make([]uint, 0, len(bulkContext.PolicesToRemove))
 		for _, combo := range bulkContext.PolicesToRemove {
@@ -217,6 +225,11 @@ func applyBulkPolicyUpdates(jobID string, bulkContext *dto.BulkPolicyUpdateJobCo
 		}
 
 		if len(policyIDsToDelete) > 0 {
+			// Keep the rows for the policy revision before they are gone
+			if removedPolicies, err = dbPolicyRepo.GetByIDs(tx, policyIDsToDelete); err != nil {
+				logger.Errorf("Failed to load policies to delete for job %s: %v", jobID, err)
+				return 0, 0, fmt.Errorf("failed to load policies to delete: %v", err)
+			}
 			if err := dbPolicyRepo.BulkDelete(tx, policyIDsToDelete); err != nil {
 				logger.Errorf("Failed to bulk delete policies for job %s: %v", jobID, err)
 				if auditLogger != nil {
@@ -251,6 +264,9 @@ func applyBulkPolicyUpdates(jobID string, bulkContext *dto.BulkPolicyUpdateJobCo
 				Status:          "enabled",
 				Description:     "Added via bulk policy update",
 			}
+			if bulkContext.RollbackTo > 0 {
+				policy.Description = fmt.Sprintf("Restored by rollback to revision %d", bulkContext.RollbackTo)
+			}
 			policiesToCreate = append(policiesToCreate, policy)
 		}
 
@@ -263,6 +279,7 @@ func applyBulkPolicyUpdates(jobID string, bulkContext *dto.BulkPolicyUpdateJobCo
 				return 0, 0, fmt.Errorf("failed to create policies: %v", err)
 			}
 			addedCount = len(policiesToCreate)
+			createdPolicies = policiesToCreate
 
 			if auditLogger != nil {
 				auditLogger.Printf("CREATED: %d policies", addedCount)
@@ -277,7 +294,51 @@ func applyBulkPolicyUpdates(jobID string, bulkContext *dto.BulkPolicyUpdateJobCo
 		}
 	}
 
-	// Step 5: Commit transaction atomically
+	// Step 5: Record the applied commands with the policy changes they produced
+	queries := make([]string, 0, len(resultsData))
+	for _, result := range resultsData {
+		queries = append(queries, result.Query)
+	}
+	if err := audit.Record(context.Background(), tx, audit.Entry{
+		EntityType: "dbactormgt",
+		EntityID:   fmt.Sprint(bulkContext.DBActorMgtID),
+		Action:     "bulk_policy_update",
+		Before:     bulkContext.PolicesToRemove,
+		After:      bulkContext.PolicesToAdd,
+		Command:    strings.Join(queries, "\n"),
+	}); err != nil {
+		logger.Errorf("Failed to audit bulk policy updates for job %s: %v", jobID, err)
+		return 0, 0, err
+	}
+
+	revisionInput := revision.Input{
+		CntMgtID: bulkContext.CntMgtID,
+		Action:   revision.ActionBulkUpdate,
+		Author:   bulkContext.Author,
+		JobID:    jobID,
+		Added:    createdPolicies,
+		Removed:  removedPolicies,
+	}
+	if bulkContext.RollbackTo > 0 {
+		revisionInput.Action = revision.ActionRollback
+		revisionInput.RollbackTo = bulkContext.RollbackTo
+	}
+	if revisionInput.Author == "" {
+		// Jobs started before revisions were recorded carry no author
+		revisionInput.Author = "system"
+	}
+	if _, err := revision.Record(tx, revisionInput); err != nil {
+		logger.Errorf("Failed to record policy revision for job %s: %v", jobID, err)
+		return 0, 0, err
+	}
+
+	// Committed with the updates so a restart does not apply them again
+	if err := job.GetJobMonitorService().MarkCallbackCommitted(tx, jobID); err != nil {
+		logger.Errorf("Failed to mark bulk policy updates committed for job %s: %v", jobID, err)
+		return 0, 0, err
+	}
+
+	// Step 6: Commit transaction atomically
 	if err := tx.Commit().Error; err != nil {
 		logger.Errorf("Failed to commit bulk policy updates for job %s: %v", jobID, err)
 		if auditLogger != nil {
@@ -295,5 +356,14 @@ func applyBulkPolicyUpdates(jobID string, bulkContext *dto.BulkPolicyUpdateJobCo
 			addedCount, removedCount, bulkContext.DBActorMgtID)
 	}
 
+	// A rollback restores policies the rule files were built without, so rebuild them
+	if bulkContext.RollbackTo > 0 {
+		if err := utils.ExportDBFPolicy(); err != nil {
+			logger.Warnf("Failed to export DBF policy rules after rollback job %s: %v", jobID, err)
+		} else {
+			logger.Infof("Exported DBF policy rules after rollback job %s to revision %d", jobID, bulkContext.RollbackTo)
+		}
+	}
+
 	return addedCount, removedCount, nil
 }
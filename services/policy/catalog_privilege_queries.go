@@ -0,0 +1,53 @@
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"dbfartifactapi/config"
+	"dbfartifactapi/pkg/logger"
+	"dbfartifactapi/services/privilege"
+)
+
+// writeCatalogPrivilegeQueryFile writes PostgreSQL or SQL Server privilege queries to JSON file for dbfAgentAPI execution.
+// File is written to DBFWEB_TEMP_DIR with unique timestamp-based filename.
+// Returns filename (not full path) for agent command construction.
+func (s *dbPolicyService) writeCatalogPrivilegeQueryFile(
+	d *privilege.CatalogDialect,
+	cntMgtID uint,
+	queries map[string][]string,
+) (string, error) {
+	filename := fmt.Sprintf("%s_privileges_%d_%s.json",
+		d.DBType, cntMgtID, time.Now().Format("20060102_150405"))
+	filePath := filepath.Join(config.Cfg.DBFWebTempDir, filename)
+
+	// JSON encoding with readable formatting
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(queries); err != nil {
+		logger.Errorf("Marshal %s privilege queries error: %v", d.Name, err)
+		return "", fmt.Errorf("marshal %s privilege queries error: %w", d.DBType, err)
+	}
+
+	// Ensure directory exists
+	dir := filepath.Dir(filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		logger.Errorf("Create dir error for %s privileges: %v", d.Name, err)
+		return "", fmt.Errorf("create dir error: %w", err)
+	}
+
+	if err := os.WriteFile(filePath, buf.Bytes(), 0644); err != nil {
+		logger.Errorf("Write %s privilege queries to file error: %v", d.Name, err)
+		return "", fmt.Errorf("write %s privilege queries error: %w", d.DBType, err)
+	}
+
+	logger.Infof("%s privilege queries written to %s (%d bytes)", d.Name, filePath, buf.Len())
+	return filename, nil
+}
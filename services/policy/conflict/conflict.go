@@ -0,0 +1,263 @@
+package conflict
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"dbfartifactapi/models"
+	"dbfartifactapi/services/dto"
+)
+
+// wildcard is the dbmgt_id and object_id of a policy covering every database or object.
+const wildcard = -1
+
+// GroupGrant is a policy default an actor inherits from the groups it belongs to. Group policies
+// grant their defaults actor-wide, like the dbpolicy rows synced for them (dbmgt_id and object_id -1).
+type GroupGrant struct {
+	DBActorMgtID    uint
+	DBPolicyDefault uint
+	GroupIDs        []uint
+}
+
+// covers reports whether a grants everything b does: every database and object in b's scope.
+func covers(a, b models.DBPolicy) bool {
+	return (a.DBMgt == wildcard || a.DBMgt == b.DBMgt) &&
+		(a.DBObjectMgt == wildcard || a.DBObjectMgt == b.DBObjectMgt)
+}
+
+func sameScope(a, b models.DBPolicy) bool {
+	return a.DBMgt == b.DBMgt && a.DBObjectMgt == b.DBObjectMgt
+}
+
+func actorWide(p models.DBPolicy) bool {
+	return p.DBMgt == wildcard && p.DBObjectMgt == wildcard
+}
+
+func enabled(p models.DBPolicy) bool {
+	return p.Status == "enabled"
+}
+
+// Analyze reports, per actor, the policies of one connection that contradict, are shadowed by or repeat
+// another policy or a group grant of the same policy default. Policies only interact within one actor
+// and one policy default, since each grants the privileges of its default to its actor.
+//
+// A policy is marked removable only when deleting it sends nothing to the database: a disabled repeat of
+// another disabled row. Deleting an enabled row runs its SqlUpdateDeny, which revokes what the covering
+// policy still grants, so shadowed and enabled redundant rows are reported but not marked; contradictions
+// need a decision and are never marked either.
+func Analyze(cntMgtID uint, policies []models.DBPolicy, grants []GroupGrant) *dto.PolicyConflictReport {
+	type key struct{ actor, policyDefault uint }
+	rowsByKey := make(map[key][]models.DBPolicy)
+	groupsByKey := make(map[key][]uint)
+	actors := make(map[uint]bool)
+	for _, policy := range policies {
+		k := key{policy.DBActorMgt, policy.DBPolicyDefault}
+		rowsByKey[k] = append(rowsByKey[k], policy)
+		actors[policy.DBActorMgt] = true
+	}
+	for _, grant := range grants {
+		k := key{grant.DBActorMgtID, grant.DBPolicyDefault}
+		groupsByKey[k] = append(groupsByKey[k], grant.GroupIDs...)
+		actors[grant.DBActorMgtID] = true
+	}
+
+	var keys []key
+	for k := range rowsByKey {
+		keys = append(keys, k)
+	}
+	for k := range groupsByKey {
+		if _, ok := rowsByKey[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].actor != keys[j].actor {
+			return keys[i].actor < keys[j].actor
+		}
+		return keys[i].policyDefault < keys[j].policyDefault
+	})
+
+	report := &dto.PolicyConflictReport{
+		CntMgtID:        cntMgtID,
+		PolicyCount:     len(policies),
+		ActorCount:      len(actors),
+		RemovablePolicy: []uint{},
+		Actors:          []dto.ActorPolicyConflicts{},
+	}
+	contradicted := make(map[uint]bool)
+	var removable []uint
+	for _, k := range keys {
+		conflicts := analyzeKey(k.policyDefault, rowsByKey[k], uniqueSorted(groupsByKey[k]))
+		if len(conflicts) == 0 {
+			continue
+		}
+		if n := len(report.Actors); n == 0 || report.Actors[n-1].DBActorMgtID != k.actor {
+			report.Actors = append(report.Actors, dto.ActorPolicyConflicts{DBActorMgtID: k.actor})
+		}
+		actor := &report.Actors[len(report.Actors)-1]
+		actor.Conflicts = append(actor.Conflicts, conflicts...)
+
+		for _, c := range conflicts {
+			switch c.Type {
+			case dto.PolicyConflictContradictory:
+				report.Contradictory++
+				contradicted[c.Policy.ID] = true
+				if c.Against != nil {
+					contradicted[c.Against.ID] = true
+				}
+			case dto.PolicyConflictShadowed:
+				report.Shadowed++
+			case dto.PolicyConflictRedundant:
+				report.Redundant++
+			}
+			if c.Removable {
+				removable = append(removable, c.Policy.ID)
+			}
+		}
+	}
+	for _, id := range uniqueSorted(removable) {
+		if !contradicted[id] {
+			report.RemovablePolicy = append(report.RemovablePolicy, id)
+		}
+	}
+	return report
+}
+
+// analyzeKey compares the policies of one actor and policy default, ordered by ID, with each other
+// and with the groups granting that default to the actor.
+func analyzeKey(policyDefault uint, rows []models.DBPolicy, groupIDs []uint) []dto.PolicyConflict {
+	var conflicts []dto.PolicyConflict
+	sort.Slice(rows, func(i, j int) bool { return rows[i].ID < rows[j].ID })
+
+	// Exact repeats: the oldest row of each scope and status stays, later ones are redundant
+	var kept []models.DBPolicy
+	for i := range rows {
+		duplicateOf := -1
+		for j := range kept {
+			if sameScope(rows[i], kept[j]) && enabled(rows[i]) == enabled(kept[j]) {
+				duplicateOf = j
+				break
+			}
+		}
+		if duplicateOf < 0 {
+			kept = append(kept, rows[i])
+			continue
+		}
+		conflicts = append(conflicts, dto.PolicyConflict{
+			Type:            dto.PolicyConflictRedundant,
+			DBPolicyDefault: policyDefault,
+			Policy:          policyRef(rows[i]),
+			Against:         policyRef(kept[duplicateOf]),
+			Removable:       !enabled(rows[i]),
+			Detail:          fmt.Sprintf("repeats policy %d with the same database, object and status", kept[duplicateOf].ID),
+		})
+	}
+
+	for i := range kept {
+		row := kept[i]
+		if !enabled(row) {
+			// A disabled row had its deny statements applied; any grant covering or inside its scope undoes part of it
+			for j := range kept {
+				if i == j || !enabled(kept[j]) || !(covers(kept[j], row) || covers(row, kept[j])) {
+					continue
+				}
+				conflicts = append(conflicts, dto.PolicyConflict{
+					Type:            dto.PolicyConflictContradictory,
+					DBPolicyDefault: policyDefault,
+					Policy:          policyRef(row),
+					Against:         policyRef(kept[j]),
+					Detail:          fmt.Sprintf("disabled, but enabled policy %d grants the same policy default on an overlapping scope", kept[j].ID),
+				})
+			}
+			if len(groupIDs) > 0 {
+				conflicts = append(conflicts, dto.PolicyConflict{
+					Type:            dto.PolicyConflictContradictory,
+					DBPolicyDefault: policyDefault,
+					Policy:          policyRef(row),
+					GroupIDs:        groupIDs,
+					Detail:          fmt.Sprintf("disabled, but groups %s grant the policy default to the actor on every database", joinIDs(groupIDs)),
+				})
+			}
+			continue
+		}
+
+		// The widest enabled row covering this one shadows it; equal scopes were folded above
+		var wider *models.DBPolicy
+		for j := range kept {
+			if i == j || !enabled(kept[j]) || !covers(kept[j], row) {
+				continue
+			}
+			if wider == nil || covers(kept[j], *wider) {
+				wider = &kept[j]
+			}
+		}
+		switch {
+		case wider != nil:
+			conflicts = append(conflicts, dto.PolicyConflict{
+				Type:            dto.PolicyConflictShadowed,
+				DBPolicyDefault: policyDefault,
+				Policy:          policyRef(row),
+				Against:         policyRef(*wider),
+				Detail:          fmt.Sprintf("inside the scope of policy %d, which grants the same policy default", wider.ID),
+			})
+		case len(groupIDs) > 0 && !actorWide(row):
+			conflicts = append(conflicts, dto.PolicyConflict{
+				Type:            dto.PolicyConflictShadowed,
+				DBPolicyDefault: policyDefault,
+				Policy:          policyRef(row),
+				GroupIDs:        groupIDs,
+				Detail: fmt.Sprintf("inside the grant of groups %s; leaving them deletes this policy as well, "+
+					"as group cleanup removes every policy of the actor with this policy default", joinIDs(groupIDs)),
+			})
+		}
+	}
+
+	if len(groupIDs) > 1 {
+		conflicts = append(conflicts, dto.PolicyConflict{
+			Type:            dto.PolicyConflictRedundant,
+			DBPolicyDefault: policyDefault,
+			GroupIDs:        groupIDs,
+			Detail:          fmt.Sprintf("granted by each of groups %s", joinIDs(groupIDs)),
+		})
+	}
+	return conflicts
+}
+
+// ParsePolicyDefaultIDs parses the comma-separated dbpolicydefault_id of a group policy, skipping invalid entries.
+func ParsePolicyDefaultIDs(ids string) []uint {
+	var result []uint
+	for _, part := range strings.Split(ids, ",") {
+		if id, err := strconv.ParseUint(strings.TrimSpace(part), 10, 32); err == nil {
+			result = append(result, uint(id))
+		}
+	}
+	return result
+}
+
+func policyRef(p models.DBPolicy) *models.DBPolicy {
+	return &p
+}
+
+func uniqueSorted(ids []uint) []uint {
+	if len(ids) == 0 {
+		return nil
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	result := ids[:1]
+	for _, id := range ids[1:] {
+		if id != result[len(result)-1] {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+func joinIDs(ids []uint) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.FormatUint(uint64(id), 10)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,139 @@
+package conflict
+
+import (
+	"reflect"
+	"testing"
+
+	"dbfartifactapi/models"
+	"dbfartifactapi/services/dto"
+)
+
+func policy(id uint, dbMgt int, actor, policyDefault uint, object int, status string) models.DBPolicy {
+	return models.DBPolicy{ID: id, CntMgt: 5, DBMgt: dbMgt, DBActorMgt: actor, DBPolicyDefault: policyDefault, DBObjectMgt: object, Status: status}
+}
+
+// conflictsOf returns the findings of one actor, nil when it has none
+func conflictsOf(report *dto.PolicyConflictReport, actor uint) []dto.PolicyConflict {
+	for _, a := range report.Actors {
+		if a.DBActorMgtID == actor {
+			return a.Conflicts
+		}
+	}
+	return nil
+}
+
+// TestAnalyze_NoConflicts tests that policies of different actors, defaults or disjoint scopes do not interact
+func TestAnalyze_NoConflicts(t *testing.T) {
+	report := Analyze(5, []models.DBPolicy{
+		policy(1, 10, 1, 100, 7, "enabled"),
+		policy(2, 10, 1, 100, 8, "disabled"), // Other object
+		policy(3, 10, 1, 101, -1, "enabled"), // Other default
+		policy(4, -1, 2, 100, -1, "enabled"), // Other actor
+	}, []GroupGrant{{DBActorMgtID: 2, DBPolicyDefault: 100, GroupIDs: []uint{9}}})
+
+	if len(report.Actors) != 0 {
+		t.Errorf("actors = %+v, want none", report.Actors)
+	}
+	if report.PolicyCount != 4 || report.ActorCount != 2 {
+		t.Errorf("policy_count = %d, actor_count = %d, want 4 and 2", report.PolicyCount, report.ActorCount)
+	}
+}
+
+// TestAnalyze_RedundantAndShadowed tests repeats and rows inside a wider direct grant. Only disabled repeats
+// are removable, since deleting an enabled row runs its deny SQL on the database
+func TestAnalyze_RedundantAndShadowed(t *testing.T) {
+	report := Analyze(5, []models.DBPolicy{
+		policy(1, -1, 1, 100, -1, "enabled"),
+		policy(2, 10, 1, 100, -1, "enabled"),
+		policy(3, 10, 1, 100, 7, "enabled"),
+		policy(4, 10, 1, 100, 7, "enabled"),  // Repeats 3
+		policy(5, 11, 1, 100, 7, "disabled"), // Contradicted by 1
+		policy(6, 11, 1, 100, 7, "disabled"), // Repeats 5
+	}, nil)
+
+	conflicts := conflictsOf(report, 1)
+	if len(conflicts) != 5 {
+		t.Fatalf("conflicts = %+v, want 5", conflicts)
+	}
+	if c := conflicts[0]; c.Type != dto.PolicyConflictRedundant || c.Policy.ID != 4 || c.Against.ID != 3 || c.Removable {
+		t.Errorf("conflicts[0] = %+v, want policy 4 redundant with 3, not removable", c)
+	}
+	if c := conflicts[1]; c.Type != dto.PolicyConflictRedundant || c.Policy.ID != 6 || c.Against.ID != 5 || !c.Removable {
+		t.Errorf("conflicts[1] = %+v, want policy 6 redundant with 5, removable", c)
+	}
+	// Both narrower rows are shadowed by the actor-wide one, not by each other
+	for i, want := range []uint{2, 3} {
+		c := conflicts[i+2]
+		if c.Type != dto.PolicyConflictShadowed || c.Policy.ID != want || c.Against.ID != 1 || c.Removable {
+			t.Errorf("conflicts[%d] = %+v, want policy %d shadowed by 1, not removable", i+2, c, want)
+		}
+	}
+	if !reflect.DeepEqual(report.RemovablePolicy, []uint{6}) {
+		t.Errorf("removable = %v, want [6]", report.RemovablePolicy)
+	}
+	if report.Redundant != 2 || report.Shadowed != 2 || report.Contradictory != 1 {
+		t.Errorf("counts = %d/%d/%d, want 2 redundant, 2 shadowed, 1 contradictory", report.Redundant, report.Shadowed, report.Contradictory)
+	}
+}
+
+// TestAnalyze_Contradictory tests disabled rows against direct and group grants, and that contradicted rows
+// are never suggested for removal
+func TestAnalyze_Contradictory(t *testing.T) {
+	report := Analyze(5, []models.DBPolicy{
+		policy(1, 10, 1, 100, -1, "enabled"),
+		policy(2, 10, 1, 100, 7, "disabled"),
+		policy(3, 10, 1, 100, 8, "enabled"),  // Shadowed by 1 but also...
+		policy(4, 10, 1, 100, 8, "disabled"), // ...contradicted by this one
+	}, []GroupGrant{{DBActorMgtID: 1, DBPolicyDefault: 100, GroupIDs: []uint{9}}})
+
+	var direct, group int
+	for _, c := range conflictsOf(report, 1) {
+		if c.Type != dto.PolicyConflictContradictory {
+			continue
+		}
+		if c.Removable {
+			t.Errorf("contradiction %+v marked removable", c)
+		}
+		if c.Against != nil {
+			direct++
+		} else if reflect.DeepEqual(c.GroupIDs, []uint{9}) {
+			group++
+		}
+	}
+	// 2 and 4 each overlap 1, 4 also overlaps 3, and group 9 covers both
+	if direct != 3 || group != 2 {
+		t.Errorf("direct contradictions = %d, group contradictions = %d, want 3 and 2", direct, group)
+	}
+	if len(report.RemovablePolicy) != 0 {
+		t.Errorf("removable = %v, want none", report.RemovablePolicy)
+	}
+}
+
+// TestAnalyze_GroupGrants tests rows inside a group grant and defaults granted by several groups
+func TestAnalyze_GroupGrants(t *testing.T) {
+	report := Analyze(5, []models.DBPolicy{
+		policy(1, -1, 1, 100, -1, "enabled"), // Row synced for the group grant itself
+		policy(2, 10, 1, 101, 7, "enabled"),
+	}, []GroupGrant{
+		{DBActorMgtID: 1, DBPolicyDefault: 100, GroupIDs: []uint{9}},
+		{DBActorMgtID: 1, DBPolicyDefault: 100, GroupIDs: []uint{8}},
+		{DBActorMgtID: 1, DBPolicyDefault: 101, GroupIDs: []uint{9}},
+	})
+
+	conflicts := conflictsOf(report, 1)
+	if len(conflicts) != 2 {
+		t.Fatalf("conflicts = %+v, want 2", conflicts)
+	}
+	if c := conflicts[0]; c.Type != dto.PolicyConflictRedundant || c.Policy != nil || !reflect.DeepEqual(c.GroupIDs, []uint{8, 9}) {
+		t.Errorf("conflicts[0] = %+v, want default 100 granted by groups 8 and 9", c)
+	}
+	if c := conflicts[1]; c.Type != dto.PolicyConflictShadowed || c.Policy.ID != 2 || c.Removable {
+		t.Errorf("conflicts[1] = %+v, want policy 2 shadowed by group 9, not removable", c)
+	}
+}
+
+func TestParsePolicyDefaultIDs(t *testing.T) {
+	if got := ParsePolicyDefaultIDs(" 10, 50,x,, 52"); !reflect.DeepEqual(got, []uint{10, 50, 52}) {
+		t.Errorf("ParsePolicyDefaultIDs = %v, want [10 50 52]", got)
+	}
+}
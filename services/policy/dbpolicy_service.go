@@ -15,16 +15,22 @@ import (
 	"dbfartifactapi/config"
 	"dbfartifactapi/models"
 	"dbfartifactapi/pkg/logger"
+	"dbfartifactapi/pkg/tracing"
 	"dbfartifactapi/repository"
 	"dbfartifactapi/services/agent"
+	"dbfartifactapi/services/audit"
 	"dbfartifactapi/services/dto"
 	"dbfartifactapi/services/job"
+	"dbfartifactapi/services/policy/revision"
 	"dbfartifactapi/services/privilege"
+	privmssql "dbfartifactapi/services/privilege/mssql"
 	privmysql "dbfartifactapi/services/privilege/mysql"
 	privoracle "dbfartifactapi/services/privilege/oracle"
+	privpostgres "dbfartifactapi/services/privilege/postgres"
 
 	"dbfartifactapi/utils"
 
+	"go.opentelemetry.io/otel/attribute"
 	"gorm.io/gorm"
 )
 
@@ -63,6 +69,16 @@ type DBPolicyService interface {
 	Delete(ctx context.Context, id uint) error
 	BulkDelete(ctx context.Context, ids []uint) (deletedCount int, failedIDs []uint, errors []string)
 	BulkUpdatePoliciesByActor(ctx context.Context, req dto.BulkPolicyUpdateRequest) (string, error)
+	PreviewCreate(ctx context.Context, data models.DBPolicy) (*dto.PolicyDryRunResponse, error)
+	PreviewUpdate(ctx context.Context, id uint, data models.DBPolicy) (*dto.PolicyDryRunResponse, error)
+	PreviewBulkUpdate(ctx context.Context, req dto.BulkPolicyUpdateRequest) (*dto.BulkPolicyDryRunResponse, error)
+	GetPrivilegeDrift(ctx context.Context, cntMgtID uint, fromVersion, toVersion int) (*dto.PrivilegeDriftResponse, error)
+	ExplainActorPrivileges(ctx context.Context, actorID uint) (*dto.PrivilegeExplainResponse, error)
+	ListRevisions(ctx context.Context, cntMgtID uint, query dto.PolicyRevisionQuery) (*dto.PolicyRevisionListResponse, error)
+	GetRevision(ctx context.Context, cntMgtID uint, rev int) (*dto.PolicyRevisionDetailResponse, error)
+	DiffRevisions(ctx context.Context, cntMgtID uint, fromRev, toRev int) (*dto.PolicyRevisionDiffResponse, error)
+	RollbackToRevision(ctx context.Context, cntMgtID uint, rev int) (*dto.PolicyRollbackResponse, error)
+	AnalyzeConflicts(ctx context.Context, cntMgtID uint) (*dto.PolicyConflictReport, error)
 }
 
 type dbPolicyService struct {
@@ -74,7 +90,13 @@ type dbPolicyService struct {
 	dbActorMgtRepo         repository.DBActorMgtRepository
 	dbObjectMgtRepo        repository.DBObjectMgtRepository
 	endpointRepo           repository.EndpointRepository
+	snapshotRepo           repository.PrivilegeSnapshotRepository
+	revisionRepo           repository.PolicyRevisionRepository
+	actorGroupsRepo        repository.DBActorGroupsRepository
+	policyGroupsRepo       repository.DBPolicyGroupsRepository
+	groupListPoliciesRepo  repository.DBGroupListPoliciesRepository
 	DBPolicyDefaultsAllMap map[uint]models.DBPolicyDefault
+	agentExec              agent.AgentExecutor
 }
 
 // NewDBPolicyService creates a new database policy service instance.
@@ -88,7 +110,13 @@ func NewDBPolicyService() DBPolicyService {
 		dbActorMgtRepo:         repository.NewDBActorMgtRepository(),
 		dbObjectMgtRepo:        repository.NewDBObjectMgtRepository(),
 		endpointRepo:           repository.NewEndpointRepository(),
+		snapshotRepo:           repository.NewPrivilegeSnapshotRepository(),
+		revisionRepo:           repository.NewPolicyRevisionRepository(),
+		actorGroupsRepo:        repository.NewDBActorGroupsRepository(),
+		policyGroupsRepo:       repository.NewDBPolicyGroupsRepository(),
+		groupListPoliciesRepo:  repository.NewDBGroupListPoliciesRepository(),
 		DBPolicyDefaultsAllMap: bootstrap.DBPolicyDefaultsAllMap,
+		agentExec:              agent.DefaultExecutor(),
 	}
 }
 
@@ -96,9 +124,9 @@ func NewDBPolicyService() DBPolicyService {
 // This is the most complex operation that processes hex-encoded SQL templates with variable substitution,
 // executes them via VeloArtifact background jobs, and creates policies based on Allow/Deny rules.
 // GetByCntMgt processes policy generation for all databases under a connection management instance.
-// Routes to MySQL privilege session or Oracle privilege session based on connection type.
+// Routes to MySQL, Oracle, PostgreSQL or MSSQL privilege session based on connection type.
 // Returns job message with background job ID for tracking.
-func (s *dbPolicyService) GetByCntMgt(ctx context.Context, id uint) (string, error) {
+func (s *dbPolicyService) GetByCntMgt(ctx context.Context, id uint) (_ string, err error) {
 	// Input validation at service boundary
 	if id == 0 {
 		return "", fmt.Errorf("invalid connection management ID: must be greater than 0")
@@ -107,6 +135,9 @@ func (s *dbPolicyService) GetByCntMgt(ctx context.Context, id uint) (string, err
 		return "", fmt.Errorf("context cannot be nil")
 	}
 
+	ctx, span := tracing.Start(ctx, "policy.GetByCntMgt", attribute.Int("cntmgt.id", int(id)))
+	defer func() { tracing.End(span, err) }()
+
 	// Get connection management to determine database type
 	cmt, err := s.cntMgtRepo.GetCntMgtByID(nil, id)
 	if err != nil {
@@ -115,11 +146,16 @@ func (s *dbPolicyService) GetByCntMgt(ctx context.Context, id uint) (string, err
 
 	// Route to appropriate handler based on database type
 	cntType := strings.ToLower(cmt.CntType)
+	span.SetAttributes(attribute.String("db.system", cntType))
 	switch cntType {
 	case "oracle":
 		return s.GetByCntMgtWithOraclePrivilegeSession(ctx, id, cmt)
 	case "mysql":
 		return s.GetByCntMgtWithPrivilegeSession(ctx, id)
+	case "postgres", "postgresql":
+		return s.GetByCntMgtWithCatalogPrivilegeSession(ctx, id, cmt, privpostgres.Dialect)
+	case "mssql", "sqlserver":
+		return s.GetByCntMgtWithCatalogPrivilegeSession(ctx, id, cmt, privmssql.Dialect)
 	default:
 		return "", fmt.Errorf("unsupported database type: %s", cmt.CntType)
 	}
@@ -133,14 +169,8 @@ func (s *dbPolicyService) Create(ctx context.Context, data models.DBPolicy) (*mo
 	if ctx == nil {
 		return nil, fmt.Errorf("context cannot be nil")
 	}
-	if data.DBPolicyDefault == 0 {
-		return nil, fmt.Errorf("invalid policy default ID: must be greater than 0")
-	}
-	if data.DBActorMgt == 0 {
-		return nil, fmt.Errorf("invalid actor management ID: must be greater than 0")
-	}
-	if data.DBObjectMgt == 0 || (data.DBObjectMgt < -1) {
-		return nil, fmt.Errorf("invalid object management ID: must be greater than 0 or -1 for wildcard")
+	if err := validatePolicyInput(data); err != nil {
+		return nil, err
 	}
 	if data.CntMgt == 0 && data.DBMgt == 0 {
 		return nil, fmt.Errorf("either connection management ID or database management ID must be provided")
@@ -176,6 +206,18 @@ func (s *dbPolicyService) Create(ctx context.Context, data models.DBPolicy) (*mo
 		return nil, fmt.Errorf("[SERVICE] Insert dbpolicy fail: %v", err)
 	}
 
+	if err := audit.Record(ctx, tx, audit.Entry{
+		EntityType: "dbpolicy",
+		EntityID:   fmt.Sprint(dbpolicy.ID),
+		Action:     "create",
+		After:      dbpolicy,
+	}); err != nil {
+		return nil, err
+	}
+	if err := s.recordRevision(ctx, tx, revision.ActionCreate, []models.DBPolicy{dbpolicy}, nil); err != nil {
+		return nil, err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return nil, err
 	}
@@ -184,6 +226,20 @@ func (s *dbPolicyService) Create(ctx context.Context, data models.DBPolicy) (*mo
 	return &dbpolicy, nil
 }
 
+// validatePolicyInput checks the fields every policy create and update needs.
+func validatePolicyInput(data models.DBPolicy) error {
+	if data.DBPolicyDefault == 0 {
+		return fmt.Errorf("invalid policy default ID: must be greater than 0")
+	}
+	if data.DBActorMgt == 0 {
+		return fmt.Errorf("invalid actor management ID: must be greater than 0")
+	}
+	if data.DBObjectMgt == 0 || (data.DBObjectMgt < -1) {
+		return fmt.Errorf("invalid object management ID: must be greater than 0 or -1 for wildcard")
+	}
+	return nil
+}
+
 // Update revokes old permissions via SqlUpdateDeny, applies new permissions via SqlUpdateAllow,
 // and updates the policy record. Ensures atomic permission changes to prevent security gaps.
 // Returns updated policy with new configuration.
@@ -195,14 +251,8 @@ func (s *dbPolicyService) Update(ctx context.Context, id uint, data models.DBPol
 	if id == 0 {
 		return nil, fmt.Errorf("invalid policy ID: must be greater than 0")
 	}
-	if data.DBPolicyDefault == 0 {
-		return nil, fmt.Errorf("invalid policy default ID: must be greater than 0")
-	}
-	if data.DBActorMgt == 0 {
-		return nil, fmt.Errorf("invalid actor management ID: must be greater than 0")
-	}
-	if data.DBObjectMgt == 0 || (data.DBObjectMgt < -1) {
-		return nil, fmt.Errorf("invalid object management ID: must be greater than 0 or -1 for wildcard")
+	if err := validatePolicyInput(data); err != nil {
+		return nil, err
 	}
 
 	tx := s.baseRepo.Begin()
@@ -219,6 +269,7 @@ func (s *dbPolicyService) Update(ctx context.Context, id uint, data models.DBPol
 		tx.Rollback()
 		return nil, fmt.Errorf("dbpolicy with id=%d not found: %v", id, err)
 	}
+	before := *dbpolicy
 
 	// execute revoke command with old data dbpolicy
 	sqlUpdatedataDeny := s.DBPolicyDefaultsAllMap[dbpolicy.DBPolicyDefault].SqlUpdateDeny
@@ -247,6 +298,19 @@ func (s *dbPolicyService) Update(ctx context.Context, id uint, data models.DBPol
 		return nil, fmt.Errorf("update dbpolicy=%d fail: %v", id, err)
 	}
 
+	if err := audit.Record(ctx, tx, audit.Entry{
+		EntityType: "dbpolicy",
+		EntityID:   fmt.Sprint(id),
+		Action:     "update",
+		Before:     before,
+		After:      dbpolicy,
+	}); err != nil {
+		return nil, err
+	}
+	if err := s.recordRevision(ctx, tx, revision.ActionUpdate, []models.DBPolicy{*dbpolicy}, []models.DBPolicy{before}); err != nil {
+		return nil, err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return nil, err
 	}
@@ -255,22 +319,69 @@ func (s *dbPolicyService) Update(ctx context.Context, id uint, data models.DBPol
 	return dbpolicy, nil
 }
 
-// executePolicyUpdateSql performs hex-decoding of SQL commands, variable substitution,
-// and executes policy changes via VeloArtifact. Critical for real-time permission enforcement.
+// policyCommand is an allow or deny statement rendered for one policy, with the connection it runs on
+// and the agent endpoint that runs it.
+type policyCommand struct {
+	sql        string
+	cmt        *models.CntMgt
+	ep         *models.Endpoint
+	queryParam *dto.DBQueryParam
+}
+
+// dryRun describes the command for a dry-run preview.
+func (c *policyCommand) dryRun(operation string) dto.DryRunCommand {
+	return dto.DryRunCommand{
+		CntMgtID:   c.cmt.ID,
+		CntType:    c.cmt.CntType,
+		Database:   c.queryParam.Database,
+		Operation:  operation,
+		Endpoint:   *c.ep,
+		Statements: []string{c.sql},
+	}
+}
+
+// executePolicyUpdateSql renders a policy SQL command and executes it via VeloArtifact.
+// Critical for real-time permission enforcement.
 // Returns error if VeloArtifact execution fails to maintain security consistency.
-func (s *dbPolicyService) executePolicyUpdateSql(ctx context.Context, sqlcmd string, data models.DBPolicy) error {
+func (s *dbPolicyService) executePolicyUpdateSql(ctx context.Context, sqlcmd string, data models.DBPolicy) (err error) {
 	// Input validation at service boundary
 	if ctx == nil {
 		return fmt.Errorf("context cannot be nil")
 	}
+
+	ctx, span := tracing.Start(ctx, "policy.executePolicyUpdateSql",
+		attribute.Int("cntmgt.id", int(data.CntMgt)), attribute.Int("actor.id", int(data.DBActorMgt)))
+	defer func() { tracing.End(span, err) }()
+
+	cmd, err := s.renderPolicyUpdateSql(sqlcmd, data)
+	if err != nil {
+		return err
+	}
+
+	hexJSON, err := utils.CreateAgentCommandJSON(cmd.queryParam)
+	if err != nil {
+		return fmt.Errorf("failed to create agent command JSON: %v", err)
+	}
+
+	audit.AddCommand(ctx, cmd.sql)
+	_, err = agent.WithContext(ctx, s.agentExec).ExecuteSql(cmd.ep.ClientID, cmd.ep.OsType, "execute", hexJSON, "", false)
+	if err != nil {
+		return fmt.Errorf("executeSqlAgentAPI error: %v", err)
+	}
+	return nil
+}
+
+// renderPolicyUpdateSql performs hex-decoding of a policy SQL command and variable substitution,
+// and resolves the connection and endpoint it runs on. Nothing is sent to the agent.
+func (s *dbPolicyService) renderPolicyUpdateSql(sqlcmd string, data models.DBPolicy) (*policyCommand, error) {
 	if strings.TrimSpace(sqlcmd) == "" {
-		return fmt.Errorf("SQL command cannot be empty")
+		return nil, fmt.Errorf("SQL command cannot be empty")
 	}
 	if data.DBActorMgt == 0 {
-		return fmt.Errorf("invalid actor management ID: must be greater than 0")
+		return nil, fmt.Errorf("invalid actor management ID: must be greater than 0")
 	}
 	if data.DBObjectMgt == 0 || (data.DBObjectMgt < -1) {
-		return fmt.Errorf("invalid object management ID: must be greater than 0 or -1 for wildcard")
+		return nil, fmt.Errorf("invalid object management ID: must be greater than 0 or -1 for wildcard")
 	}
 
 	var dbmgt models.DBMgt
@@ -280,7 +391,7 @@ func (s *dbPolicyService) executePolicyUpdateSql(ctx context.Context, sqlcmd str
 	if data.DBMgt != 0 && data.DBMgt != -1 {
 		dbmgtbyid, err := s.dbMgtRepo.GetByID(nil, utils.MustIntToUint(data.DBMgt))
 		if err != nil {
-			return fmt.Errorf("DbMgt with id=%d not found: %v", data.DBMgt, err)
+			return nil, fmt.Errorf("DbMgt with id=%d not found: %v", data.DBMgt, err)
 		}
 		dbmgt = *dbmgtbyid
 		logger.Infof("Found dbmgt with id=%d", data.DBMgt)
@@ -297,24 +408,24 @@ func (s *dbPolicyService) executePolicyUpdateSql(ctx context.Context, sqlcmd str
 		cmtId = data.CntMgt
 		logger.Infof("Using CntMgt with id=%d", data.CntMgt)
 	} else {
-		return fmt.Errorf("either DBMgt or CntMgt must be provided")
+		return nil, fmt.Errorf("either DBMgt or CntMgt must be provided")
 	}
 
 	cmt, err := s.cntMgtRepo.GetCntMgtByID(nil, cmtId)
 	if err != nil {
-		return fmt.Errorf("cntmgt with id=%d not found: %v", cmtId, err)
+		return nil, fmt.Errorf("cntmgt with id=%d not found: %v", cmtId, err)
 	}
 	logger.Infof("Found cntmgt with id=%d", cmtId)
 
 	ep, err := s.endpointRepo.GetByID(nil, utils.MustIntToUint(cmt.Agent))
 	if err != nil {
-		return fmt.Errorf("cannot find endpoint with id=%d: %v", cmt.Agent, err)
+		return nil, fmt.Errorf("cannot find endpoint with id=%d: %v", cmt.Agent, err)
 	}
 	logger.Infof("Found endpoint id=%d, client_id=%s, os_type=%s", ep.ID, ep.ClientID, ep.OsType)
 
 	actor, err := s.dbActorMgtRepo.GetByID(nil, data.DBActorMgt)
 	if err != nil {
-		return fmt.Errorf("cannot find dbactormgt with id=%d: %v", data.DBActorMgt, err)
+		return nil, fmt.Errorf("cannot find dbactormgt with id=%d: %v", data.DBActorMgt, err)
 	}
 
 	// Handle DBObjectMgt = -1 case
@@ -326,18 +437,18 @@ func (s *dbPolicyService) executePolicyUpdateSql(ctx context.Context, sqlcmd str
 	} else {
 		object, err := s.dbObjectMgtRepo.GetById(nil, utils.MustIntToUint(data.DBObjectMgt))
 		if err != nil {
-			return fmt.Errorf("cannot find dbobjectmgt with id=%d: %v", data.DBObjectMgt, err)
+			return nil, fmt.Errorf("cannot find dbobjectmgt with id=%d: %v", data.DBObjectMgt, err)
 		}
 		objectName = object.ObjectName
 	}
 
 	if sqlcmd == "" {
-		return fmt.Errorf("updatedata command is empty or not exist with policydefaultid=%d", data.DBPolicyDefault)
+		return nil, fmt.Errorf("updatedata command is empty or not exist with policydefaultid=%d", data.DBPolicyDefault)
 	}
 
 	sqlBytes, err := hex.DecodeString(sqlcmd)
 	if err != nil {
-		return fmt.Errorf("unhex error: %v", err)
+		return nil, fmt.Errorf("unhex error: %v", err)
 	}
 
 	rawSQL := string(sqlBytes)
@@ -366,7 +477,7 @@ func (s *dbPolicyService) executePolicyUpdateSql(ctx context.Context, sqlcmd str
 		SetHost(cmt.IP).
 		SetPort(cmt.Port).
 		SetUser(cmt.Username).
-		SetPassword(cmt.Password)
+		SetCredentials(cmt.Password, cmt.SecretRef)
 
 	// Oracle requires ServiceName as the database identifier for CDB/PDB routing
 	if strings.ToLower(cmt.CntType) == "oracle" {
@@ -381,16 +492,7 @@ func (s *dbPolicyService) executePolicyUpdateSql(ctx context.Context, sqlcmd str
 
 	queryParam.Query = executeSql
 
-	hexJSON, err := utils.CreateAgentCommandJSON(queryParam)
-	if err != nil {
-		return fmt.Errorf("failed to create agent command JSON: %v", err)
-	}
-
-	_, err = agent.ExecuteSqlAgentAPI(ep.ClientID, ep.OsType, "execute", hexJSON, "", false)
-	if err != nil {
-		return fmt.Errorf("executeSqlAgentAPI error: %v", err)
-	}
-	return nil
+	return &policyCommand{sql: executeSql, cmt: cmt, ep: ep, queryParam: queryParam}, nil
 }
 
 // Delete revokes database permissions via SqlUpdateDeny and removes policy record.
@@ -445,6 +547,18 @@ func (s *dbPolicyService) Delete(ctx context.Context, id uint) error {
 		return fmt.Errorf("failed to delete policy record with id=%d: %v", id, err)
 	}
 
+	if err := audit.Record(ctx, tx, audit.Entry{
+		EntityType: "dbpolicy",
+		EntityID:   fmt.Sprint(id),
+		Action:     "delete",
+		Before:     dbpolicy,
+	}); err != nil {
+		return err
+	}
+	if err := s.recordRevision(ctx, tx, revision.ActionDelete, nil, []models.DBPolicy{*dbpolicy}); err != nil {
+		return err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return fmt.Errorf("failed to commit delete transaction: %v", err)
 	}
@@ -589,7 +703,7 @@ func (s *dbPolicyService) GetByCntMgtWithPrivilegeSession(ctx context.Context, i
 		SetHost(cmt.IP).
 		SetPort(cmt.Port).
 		SetUser(cmt.Username).
-		SetPassword(cmt.Password).
+		SetCredentials(cmt.Password, cmt.SecretRef).
 		Build()
 	queryParam.Query = filename
 	queryParam.Action = "download"
@@ -611,7 +725,7 @@ func (s *dbPolicyService) GetByCntMgtWithPrivilegeSession(ctx context.Context, i
 	}
 
 	// Start background job
-	stdout, err := agent.ExecuteSqlAgentAPI(ep.ClientID, ep.OsType, "download", hexJSON, "--background", true)
+	stdout, err := agent.WithContext(ctx, s.agentExec).ExecuteSql(ep.ClientID, ep.OsType, "download", hexJSON, "--background", true)
 	if err != nil {
 		return "", fmt.Errorf("failed to start agent API job: %v", err)
 	}
@@ -646,8 +760,7 @@ func (s *dbPolicyService) GetByCntMgtWithPrivilegeSession(ctx context.Context, i
 
 	// Register job with monitoring system
 	jobMonitor := job.GetJobMonitorService()
-	completionCallback := privmysql.CreatePrivilegeSessionCompletionHandler()
-	jobMonitor.AddJobWithCallback(jobResp.JobID, id, ep.ClientID, ep.OsType, completionCallback, contextData)
+	jobMonitor.AddJobWithKind(ctx, jobResp.JobID, privmysql.JobKindPrivilegeSession, id, ep.ClientID, ep.OsType, contextData)
 
 	logger.Infof("Privilege session job added to monitoring: job_id=%s, cntmgt_id=%d, databases=%d", jobResp.JobID, id, len(dbmgts))
 
@@ -726,7 +839,7 @@ func (s *dbPolicyService) GetByCntMgtWithOraclePrivilegeSession(ctx context.Cont
 		SetHost(cmt.IP).
 		SetPort(cmt.Port).
 		SetUser(cmt.Username).
-		SetPassword(cmt.Password).
+		SetCredentials(cmt.Password, cmt.SecretRef).
 		SetDatabase(cmt.ServiceName). // Oracle uses ServiceName for connection
 		Build()
 	queryParam.Query = filename
@@ -749,7 +862,7 @@ func (s *dbPolicyService) GetByCntMgtWithOraclePrivilegeSession(ctx context.Cont
 	}
 
 	// Start background job
-	stdout, err := agent.ExecuteSqlAgentAPI(ep.ClientID, ep.OsType, "download", hexJSON, "--background", true)
+	stdout, err := agent.WithContext(ctx, s.agentExec).ExecuteSql(ep.ClientID, ep.OsType, "download", hexJSON, "--background", true)
 	if err != nil {
 		return "", fmt.Errorf("failed to start oracle agent API job: %v", err)
 	}
@@ -786,8 +899,7 @@ func (s *dbPolicyService) GetByCntMgtWithOraclePrivilegeSession(ctx context.Cont
 
 	// Register job with monitoring system
 	jobMonitor := job.GetJobMonitorService()
-	completionCallback := privoracle.CreateOraclePrivilegeSessionCompletionHandler()
-	jobMonitor.AddJobWithCallback(jobResp.JobID, id, ep.ClientID, ep.OsType, completionCallback, contextData)
+	jobMonitor.AddJobWithKind(ctx, jobResp.JobID, privoracle.JobKindOraclePrivilegeSession, id, ep.ClientID, ep.OsType, contextData)
 
 	logger.Infof("Oracle privilege session job added to monitoring: job_id=%s, cntmgt_id=%d, schemas=%d, conn_type=%s",
 		jobResp.JobID, id, len(dbmgts), connType.String())
@@ -796,6 +908,121 @@ func (s *dbPolicyService) GetByCntMgtWithOraclePrivilegeSession(ctx context.Cont
 		jobResp.JobID, len(dbmgts), connType.String()), nil
 }
 
+// GetByCntMgtWithCatalogPrivilegeSession processes PostgreSQL and SQL Server privilege collection via dbfAgentAPI.
+// The dialect builds the catalog queries (roles, memberships and grants of every actor, following role
+// membership so inherited privileges are evaluated as well); its completion handler evaluates the policies.
+// Returns job message with background job ID for tracking.
+func (s *dbPolicyService) GetByCntMgtWithCatalogPrivilegeSession(ctx context.Context, id uint, cmt *models.CntMgt, d *privilege.CatalogDialect) (string, error) {
+	// Get all databases/schemas under this connection
+	dbmgts, err := s.dbMgtRepo.GetByCntMgtId(nil, id)
+	if err != nil {
+		return "", fmt.Errorf("cannot find dbmgt with cntid=%d: %v", id, err)
+	}
+
+	if len(dbmgts) == 0 {
+		return "", fmt.Errorf("no %s found for %s cntmgt_id=%d", d.DatabaseNoun, d.DBType, id)
+	}
+
+	logger.Infof("Found %d %s %s for cntmgt_id=%d", len(dbmgts), d.Name, d.DatabaseNoun, id)
+
+	// Get endpoint for agent communication
+	ep, err := s.endpointRepo.GetByID(nil, utils.MustIntToUint(cmt.Agent))
+	if err != nil {
+		return "", fmt.Errorf("cannot find endpoint with id=%d: %v", cmt.Agent, err)
+	}
+	logger.Infof("Found endpoint: id=%d, client_id=%s, os_type=%s", ep.ID, ep.ClientID, ep.OsType)
+
+	// Get database actors (roles, logins)
+	dbActorMgts, err := s.dbActorMgtRepo.GetByCntMgt(nil, cmt.ID)
+	if err != nil || len(dbActorMgts) == 0 {
+		return "", fmt.Errorf("list dbactormgts with cntmgtid=%d no data: %v", cmt.ID, err)
+	}
+
+	logger.Infof("Found %d %s actors for cntmgt_id=%d", len(dbActorMgts), d.Name, id)
+
+	privilegeQueries, err := d.BuildQueries(dbActorMgts, dbmgts)
+	if err != nil {
+		return "", fmt.Errorf("failed to build %s privilege queries: %v", d.DBType, err)
+	}
+
+	// Write queries to file for agent execution
+	filename, err := s.writeCatalogPrivilegeQueryFile(d, id, privilegeQueries)
+	if err != nil {
+		return "", fmt.Errorf("failed to write %s privilege query file: %v", d.DBType, err)
+	}
+
+	// Build query parameters for dbfAgentAPI
+	queryParam := dto.NewDBQueryParamBuilder().
+		SetDBType(d.DBType).
+		SetHost(cmt.IP).
+		SetPort(cmt.Port).
+		SetUser(cmt.Username).
+		SetCredentials(cmt.Password, cmt.SecretRef).
+		Build()
+	queryParam.Query = filename
+	queryParam.Action = "download"
+	queryParam.Option = "--background"
+
+	hexJSON, err := utils.CreateAgentCommandJSON(queryParam)
+	if err != nil {
+		return "", fmt.Errorf("failed to create agent command JSON: %v", err)
+	}
+
+	// Job response structure
+	type JobResponse struct {
+		JobID          string `json:"job_id"`
+		Message        string `json:"message"`
+		MonitorCommand string `json:"monitor_command"`
+		PID            int    `json:"pid"`
+		ResultsCommand string `json:"results_command"`
+		Success        bool   `json:"success"`
+	}
+
+	// Start background job
+	stdout, err := agent.WithContext(ctx, s.agentExec).ExecuteSql(ep.ClientID, ep.OsType, "download", hexJSON, "--background", true)
+	if err != nil {
+		return "", fmt.Errorf("failed to start %s agent API job: %v", d.DBType, err)
+	}
+
+	// Parse job response
+	var jobResp JobResponse
+	if err := json.Unmarshal([]byte(stdout), &jobResp); err != nil {
+		return "", fmt.Errorf("failed to parse %s job response: %v", d.DBType, err)
+	}
+
+	if !jobResp.Success {
+		return "", fmt.Errorf("%s job failed to start: %s", d.DBType, jobResp.Message)
+	}
+
+	logger.Infof("%s privilege session job started: job_id=%s, pid=%d", d.Name, jobResp.JobID, jobResp.PID)
+
+	// Prepare context data for job completion callback
+	sessionID := fmt.Sprintf("%s_cntmgt_%d_%d", d.DBType, id, time.Now().UnixNano())
+	sessionContext := &privilege.PrivilegeSessionJobContext{
+		CntMgtID:      id,
+		CMT:           cmt,
+		EndpointID:    ep.ID,
+		DbActorMgts:   dbActorMgts,
+		DbMgts:        dbmgts,
+		SessionID:     sessionID,
+		PrivilegeFile: filename,
+	}
+
+	contextData := map[string]interface{}{
+		d.ContextKey: sessionContext,
+	}
+
+	// Register job with monitoring system
+	jobMonitor := job.GetJobMonitorService()
+	jobMonitor.AddJobWithKind(ctx, jobResp.JobID, d.JobKind, id, ep.ClientID, ep.OsType, contextData)
+
+	logger.Infof("%s privilege session job added to monitoring: job_id=%s, cntmgt_id=%d, %s=%d",
+		d.Name, jobResp.JobID, id, d.DatabaseNoun, len(dbmgts))
+
+	return fmt.Sprintf("%s privilege session background job started: %s. Processing %d %s.",
+		d.Name, jobResp.JobID, len(dbmgts), d.DatabaseNoun), nil
+}
+
 // extractResultValue extracts first value from query result
 // Returns "NULL" if result is empty or first value is nil
 func (s *dbPolicyService) extractResultValue(result []map[string]interface{}) string {
@@ -979,82 +1206,134 @@ func (s *dbPolicyService) writePrivilegeQueryFile(cntMgtID uint, queries map[str
 
 // BulkUpdatePoliciesByActor performs bulk policy update for a specific actor
 // Compares existing policies with new desired state and executes changes via VeloArtifact
-func (s *dbPolicyService) BulkUpdatePoliciesByActor(ctx context.Context, req dto.BulkPolicyUpdateRequest) (string, error) {
+func (s *dbPolicyService) BulkUpdatePoliciesByActor(ctx context.Context, req dto.BulkPolicyUpdateRequest) (_ string, err error) {
 	// Input validation at service boundary
 	if ctx == nil {
 		return "", fmt.Errorf("context cannot be nil")
 	}
+
+	ctx, span := tracing.Start(ctx, "policy.BulkUpdatePoliciesByActor",
+		attribute.Int("cntmgt.id", int(req.CntMgtID)), attribute.Int("actor.id", int(req.DBActorMgtID)))
+	defer func() { tracing.End(span, err) }()
+
+	tx := s.baseRepo.Begin()
+	var txCommitted bool
+	defer func() {
+		if !txCommitted {
+			tx.Rollback()
+		}
+	}()
+
+	bulkContext, ep, err := s.planBulkPolicyUpdate(ctx, tx, req)
+	if err != nil {
+		return "", err
+	}
+	toAdd, toRemove := bulkContext.PolicesToAdd, bulkContext.PolicesToRemove
+
+	// Early return when no changes detected
+	if len(toAdd) == 0 && len(toRemove) == 0 {
+		txCommitted = true
+		tx.Rollback()
+		return "No changes detected - existing policies match desired state", nil
+	}
+
+	jobID, err := s.startBulkPolicyUpdateJob(ctx, bulkContext, ep)
+	if err != nil {
+		return "", err
+	}
+
+	// Rollback preparation transaction - actual DB updates happen in completion handler
+	txCommitted = true
+	tx.Rollback()
+
+	logger.Infof("Bulk policy update job started: job_id=%s, actor_id=%d, add=%d, remove=%d",
+		jobID, req.DBActorMgtID, len(toAdd), len(toRemove))
+	return fmt.Sprintf("Bulk policy update background job started: %s. Adding %d policies, removing %d policies.",
+		jobID, len(toAdd), len(toRemove)), nil
+}
+
+// planBulkPolicyUpdate validates a bulk policy update request, loads the connection, database, actor
+// and endpoint it targets and computes the policies to add and remove. The returned job context has
+// no commands yet.
+func (s *dbPolicyService) planBulkPolicyUpdate(ctx context.Context, tx *gorm.DB, req dto.BulkPolicyUpdateRequest) (*dto.BulkPolicyUpdateJobContext, *models.Endpoint, error) {
 	if req.CntMgtID == 0 {
-		return "", fmt.Errorf("invalid connection management ID: must be greater than 0")
+		return nil, nil, fmt.Errorf("invalid connection management ID: must be greater than 0")
 	}
 	if req.DBMgtID == 0 {
-		return "", fmt.Errorf("invalid database management ID: must be greater than 0")
+		return nil, nil, fmt.Errorf("invalid database management ID: must be greater than 0")
 	}
 	if req.DBActorMgtID == 0 {
-		return "", fmt.Errorf("invalid actor management ID: must be greater than 0")
+		return nil, nil, fmt.Errorf("invalid actor management ID: must be greater than 0")
 	}
 	if len(req.NewPolicyDefaults) == 0 {
-		return "", fmt.Errorf("new policy defaults list cannot be empty")
+		return nil, nil, fmt.Errorf("new policy defaults list cannot be empty")
 	}
 	if len(req.NewObjectMgts) == 0 {
-		return "", fmt.Errorf("new object management list cannot be empty")
+		return nil, nil, fmt.Errorf("new object management list cannot be empty")
 	}
 
-	tx := s.baseRepo.Begin()
-	var txCommitted bool
-	defer func() {
-		if !txCommitted {
-			tx.Rollback()
-		}
-	}()
-
 	dbmgt, err := s.dbMgtRepo.GetByID(tx, req.DBMgtID)
 	if err != nil {
-		return "", fmt.Errorf("dbmgt with id=%d not found: %v", req.DBMgtID, err)
+		return nil, nil, fmt.Errorf("dbmgt with id=%d not found: %v", req.DBMgtID, err)
 	}
 	logger.Infof("Found dbmgt: id=%d, db_name=%s, cnt_id=%d", req.DBMgtID, dbmgt.DbName, dbmgt.CntID)
 
 	cmt, err := s.cntMgtRepo.GetCntMgtByID(tx, req.CntMgtID)
 	if err != nil {
-		return "", fmt.Errorf("cntmgt with id=%d not found: %v", req.CntMgtID, err)
+		return nil, nil, fmt.Errorf("cntmgt with id=%d not found: %v", req.CntMgtID, err)
 	}
 	logger.Infof("Found cmt: id=%d, cnt_type=%s, username=%s, agent=%d", cmt.ID, cmt.CntType, cmt.Username, cmt.Agent)
 
 	actor, err := s.dbActorMgtRepo.GetByID(tx, req.DBActorMgtID)
 	if err != nil {
-		return "", fmt.Errorf("dbactormgt with id=%d not found: %v", req.DBActorMgtID, err)
+		return nil, nil, fmt.Errorf("dbactormgt with id=%d not found: %v", req.DBActorMgtID, err)
 	}
 	logger.Infof("Found actor: id=%d, dbuser=%s, ip=%s", actor.ID, actor.DBUser, actor.IPAddress)
 
 	ep, err := s.endpointRepo.GetByID(tx, utils.MustIntToUint(cmt.Agent))
 	if err != nil {
-		return "", fmt.Errorf("cannot find endpoint with id=%d: %v", cmt.Agent, err)
+		return nil, nil, fmt.Errorf("cannot find endpoint with id=%d: %v", cmt.Agent, err)
 	}
 	logger.Infof("Found endpoint: id=%d, client_id=%s, os_type=%s", ep.ID, ep.ClientID, ep.OsType)
 
 	existingCombinations, err := s.getExistingPolicyCombinations(tx, req.CntMgtID, req.DBMgtID, req.DBActorMgtID)
 	if err != nil {
-		return "", fmt.Errorf("failed to get existing policies: %v", err)
+		return nil, nil, fmt.Errorf("failed to get existing policies: %v", err)
 	}
 	logger.Infof("Found %d existing policy combinations for actor=%d", len(existingCombinations), req.DBActorMgtID)
 
 	toAdd, toRemove := s.calculatePolicyDiff(existingCombinations, req.NewPolicyDefaults, req.NewObjectMgts)
 	logger.Infof("Calculated diff: %d to add, %d to remove", len(toAdd), len(toRemove))
 
-	// Early return when no changes detected
-	if len(toAdd) == 0 && len(toRemove) == 0 {
-		txCommitted = true
-		tx.Rollback()
-		return "No changes detected - existing policies match desired state", nil
-	}
+	return &dto.BulkPolicyUpdateJobContext{
+		CntMgtID:        req.CntMgtID,
+		DBMgtID:         req.DBMgtID,
+		DBActorMgtID:    req.DBActorMgtID,
+		PolicesToAdd:    toAdd,
+		PolicesToRemove: toRemove,
+		DBMgt:           dbmgt,
+		CMT:             cmt,
+		Actor:           actor,
+		EndpointID:      ep.ID,
+		Author:          audit.Actor(ctx),
+	}, ep, nil
+}
+
+// startBulkPolicyUpdateJob builds the allow/deny SQL for the policies to add and remove in bulkContext,
+// starts it as a background agent job and registers the job, so the policy rows are only changed
+// by the completion handler once every command succeeded. Returns the job ID.
+func (s *dbPolicyService) startBulkPolicyUpdateJob(ctx context.Context, bulkContext *dto.BulkPolicyUpdateJobContext, ep *models.Endpoint) (string, error) {
+	dbmgt, actor, cmt := bulkContext.DBMgt, bulkContext.Actor, bulkContext.CMT
+	toAdd, toRemove := bulkContext.PolicesToAdd, bulkContext.PolicesToRemove
 
 	commandMap, err := s.buildBulkPolicyCommands(toAdd, toRemove, dbmgt, actor, cmt)
 	if err != nil {
 		return "", fmt.Errorf("failed to build bulk policy commands: %v", err)
 	}
 	logger.Infof("Built %d SQL commands for bulk policy update", len(commandMap))
+	bulkContext.CommandMap = commandMap
 
-	filename, err := s.writeBulkPolicyUpdateFile(req.CntMgtID, req.DBMgtID, req.DBActorMgtID, commandMap)
+	filename, err := s.writeBulkPolicyUpdateFile(bulkContext.CntMgtID, bulkContext.DBMgtID, bulkContext.DBActorMgtID, commandMap)
 	if err != nil {
 		return "", fmt.Errorf("failed to write bulk policy update file: %v", err)
 	}
@@ -1063,7 +1342,7 @@ func (s *dbPolicyService) BulkUpdatePoliciesByActor(ctx context.Context, req dto
 		SetHost(cmt.IP).
 		SetPort(cmt.Port).
 		SetUser(cmt.Username).
-		SetPassword(cmt.Password)
+		SetCredentials(cmt.Password, cmt.SecretRef)
 
 	// Oracle requires ServiceName as the database identifier for CDB/PDB routing
 	if strings.ToLower(cmt.CntType) == "oracle" {
@@ -1093,7 +1372,7 @@ func (s *dbPolicyService) BulkUpdatePoliciesByActor(ctx context.Context, req dto
 	}
 
 	// Start background job
-	stdout, err := agent.ExecuteSqlAgentAPI(ep.ClientID, ep.OsType, "download", hexJSON, "--background", true)
+	stdout, err := agent.WithContext(ctx, s.agentExec).ExecuteSql(ep.ClientID, ep.OsType, "download", hexJSON, "--background", true)
 	if err != nil {
 		return "", fmt.Errorf("failed to start agent API job: %v", err)
 	}
@@ -1110,39 +1389,17 @@ func (s *dbPolicyService) BulkUpdatePoliciesByActor(ctx context.Context, req dto
 
 	logger.Infof("Bulk policy update VeloArtifact job started: job_id=%s, pid=%d", jobResp.JobID, jobResp.PID)
 
-	bulkContext := &dto.BulkPolicyUpdateJobContext{
-		CntMgtID:        req.CntMgtID,
-		DBMgtID:         req.DBMgtID,
-		DBActorMgtID:    req.DBActorMgtID,
-		PolicesToAdd:    toAdd,
-		PolicesToRemove: toRemove,
-		DBMgt:           dbmgt,
-		CMT:             cmt,
-		Actor:           actor,
-		EndpointID:      ep.ID,
-		CommandMap:      commandMap,
-	}
-
 	contextData := map[string]interface{}{
 		"bulk_policy_context": bulkContext,
 	}
 
 	// Add job to monitoring system with completion callback
 	jobMonitor := job.GetJobMonitorService()
-	completionCallback := CreateBulkPolicyUpdateCompletionHandler()
-	jobMonitor.AddJobWithCallback(jobResp.JobID, req.DBMgtID, ep.ClientID, ep.OsType, completionCallback, contextData)
+	jobMonitor.AddJobWithKind(ctx, jobResp.JobID, JobKindBulkPolicyUpdate, bulkContext.DBMgtID, ep.ClientID, ep.OsType, contextData)
 
 	logger.Infof("Bulk policy update job added to monitoring: job_id=%s, actor_id=%d, add=%d, remove=%d",
-		jobResp.JobID, req.DBActorMgtID, len(toAdd), len(toRemove))
-
-	// Rollback preparation transaction - actual DB updates happen in completion handler
-	txCommitted = true
-	tx.Rollback()
-
-	logger.Infof("Bulk policy update job started: job_id=%s, actor_id=%d, add=%d, remove=%d",
-		jobResp.JobID, req.DBActorMgtID, len(toAdd), len(toRemove))
-	return fmt.Sprintf("Bulk policy update background job started: %s. Adding %d policies, removing %d policies.",
-		jobResp.JobID, len(toAdd), len(toRemove)), nil
+		jobResp.JobID, bulkContext.DBActorMgtID, len(toAdd), len(toRemove))
+	return jobResp.JobID, nil
 }
 
 // getExistingPolicyCombinations retrieves existing policy combinations for an actor
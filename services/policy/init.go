@@ -2,9 +2,28 @@ package policy
 
 import (
 	"dbfartifactapi/models"
+	"dbfartifactapi/services/agent"
+	"dbfartifactapi/services/dto"
+	"dbfartifactapi/services/job"
 	"dbfartifactapi/services/privilege"
 )
 
+// Job kinds for policy jobs. Persisted with each job so the job monitor
+// can restore completion callbacks after an API restart.
+const (
+	JobKindPolicy           = "policy"
+	JobKindCombinedPolicy   = "combined_policy"
+	JobKindBulkPolicyUpdate = "bulk_policy_update"
+)
+
+// RegisterJobKinds registers the completion callbacks of this package with the job monitor.
+// Called at startup once the agent executor is chosen, before persisted jobs are restored.
+func RegisterJobKinds(agentExec agent.AgentExecutor) {
+	job.RegisterJobKind(JobKindPolicy, CreatePolicyCompletionHandler(agentExec))
+	job.RegisterJobKind(JobKindCombinedPolicy, CreateCombinedPolicyCompletionHandler(agentExec))
+	job.RegisterJobKind(JobKindBulkPolicyUpdate, CreateBulkPolicyUpdateCompletionHandler(agentExec))
+}
+
 func init() {
 	// Register policy package dependencies with privilege registry to break circular import.
 	// privilege -> policy would create a cycle, so privilege defines interfaces and
@@ -14,8 +33,8 @@ func init() {
 		return NewDBPolicyService().(*dbPolicyService)
 	})
 
-	privilege.RegisterRetrieveJobResults(func(jobID string, ep *models.Endpoint) ([]privilege.QueryResult, error) {
-		results, err := RetrieveJobResults(jobID, ep)
+	privilege.RegisterRetrieveJobResults(func(agentExec agent.AgentExecutor, jobID string, ep *models.Endpoint) ([]privilege.QueryResult, error) {
+		results, err := RetrieveJobResults(agentExec, jobID, ep)
 		if err != nil {
 			return nil, err
 		}
@@ -35,4 +54,11 @@ func init() {
 	})
 
 	privilege.RegisterGetEndpointForJob(GetEndpointForJob)
+
+	// Bulk updates run GRANT/REVOKE on the agent, cancelling after that would orphan the changes
+	job.RegisterAgentAppliedKind(JobKindBulkPolicyUpdate)
+
+	job.RegisterContextType("policy_context", &PolicyJobContext{})
+	job.RegisterContextType("combined_policy_context", &CombinedPolicyJobContext{})
+	job.RegisterContextType("bulk_policy_context", &dto.BulkPolicyUpdateJobContext{})
 }
@@ -30,7 +30,7 @@ type GetResultsResponse struct {
 }
 
 // CreatePolicyCompletionHandler creates a callback function for policy job completion
-func CreatePolicyCompletionHandler() job.JobCompletionCallback {
+func CreatePolicyCompletionHandler(agentExec agent.AgentExecutor) job.JobCompletionCallback {
 	return func(jobID string, jobInfo *job.JobInfo, statusResp *job.StatusResponse) error {
 		logger.Infof("Processing policy completion for job %s, status: %s", jobID, statusResp.Status)
 
@@ -42,7 +42,7 @@ func CreatePolicyCompletionHandler() job.JobCompletionCallback {
 
 		// Process completed jobs regardless of completion method (polling or notification)
 		if statusResp.Status == "completed" {
-			return processPolicyResults(jobID, contextData, statusResp, jobInfo)
+			return processPolicyResults(agentExec, jobID, contextData, statusResp, jobInfo)
 		} else {
 			logger.Errorf("Policy job %s failed, no policies will be created", jobID)
 			return fmt.Errorf("policy job failed: %s", statusResp.Message)
@@ -51,7 +51,7 @@ func CreatePolicyCompletionHandler() job.JobCompletionCallback {
 }
 
 // processPolicyResults processes the results of a completed policy job
-func processPolicyResults(jobID string, contextData interface{}, statusResp *job.StatusResponse, jobInfo *job.JobInfo) error {
+func processPolicyResults(agentExec agent.AgentExecutor, jobID string, contextData interface{}, statusResp *job.StatusResponse, jobInfo *job.JobInfo) error {
 	logger.Infof("Processing policy results for job %s - completed: %d, failed: %d",
 		jobID, statusResp.Completed, statusResp.Failed)
 
@@ -67,7 +67,7 @@ func processPolicyResults(jobID string, contextData interface{}, statusResp *job
 	}
 
 	// Legacy VeloArtifact polling flow
-	return processPolicyResultsFromVeloArtifact(jobID, policyContext, statusResp)
+	return processPolicyResultsFromVeloArtifact(agentExec, jobID, policyContext, statusResp)
 }
 
 // processPolicyResultsFromNotification handles policy processing when triggered by external notification
@@ -120,7 +120,7 @@ func processPolicyResultsFromNotification(jobID string, policyContext *PolicyJob
 }
 
 // processPolicyResultsFromVeloArtifact handles policy processing via traditional VeloArtifact polling
-func processPolicyResultsFromVeloArtifact(jobID string, policyContext *PolicyJobContext, statusResp *job.StatusResponse) error {
+func processPolicyResultsFromVeloArtifact(agentExec agent.AgentExecutor, jobID string, policyContext *PolicyJobContext, statusResp *job.StatusResponse) error {
 	logger.Infof("Processing policy results from VeloArtifact polling for job %s", jobID)
 
 	// Get endpoint information
@@ -130,7 +130,7 @@ func processPolicyResultsFromVeloArtifact(jobID string, policyContext *PolicyJob
 	}
 
 	// Retrieve and download results file via VeloArtifact
-	resultsData, err := RetrieveJobResults(jobID, ep)
+	resultsData, err := RetrieveJobResults(agentExec, jobID, ep)
 	if err != nil {
 		return err
 	}
@@ -189,9 +189,9 @@ func GetEndpointForJob(jobID string, endpointID uint) (*models.Endpoint, error)
 }
 
 // RetrieveJobResults gets results from VeloArtifact and downloads the results file
-func RetrieveJobResults(jobID string, ep *models.Endpoint) ([]QueryResult, error) {
+func RetrieveJobResults(agentExec agent.AgentExecutor, jobID string, ep *models.Endpoint) ([]QueryResult, error) {
 	// Get results from agent using getresults command
-	resultsOutput, err := agent.ExecuteAgentAPISimpleCommand(ep.ClientID, ep.OsType, "getresults", jobID, "", true)
+	resultsOutput, err := agentExec.ExecuteSimpleCommand(ep.ClientID, ep.OsType, "getresults", jobID, "", true)
 	if err != nil {
 		logger.Errorf("Failed to get results for job %s: %v", jobID, err)
 		return nil, fmt.Errorf("failed to get results: %v", err)
@@ -213,7 +213,7 @@ func RetrieveJobResults(jobID string, ep *models.Endpoint) ([]QueryResult, error
 		jobID, getResultsResp.FilePath, getResultsResp.TotalQueries, getResultsResp.Completed, getResultsResp.Failed)
 
 	// Download the results file from agent
-	downloadResp, err := agent.DownloadFileAgentAPI(ep.ClientID, getResultsResp.FilePath, ep.OsType)
+	downloadResp, err := agentExec.DownloadFile(ep.ClientID, getResultsResp.FilePath, ep.OsType)
 	if err != nil {
 		logger.Errorf("Failed to download results file for job %s: %v", jobID, err)
 		return nil, fmt.Errorf("failed to download results file: %v", err)
@@ -439,6 +439,11 @@ func createPoliciesFromResults(jobID string, policyContext *PolicyJobContext, re
 		}
 	}
 
+	// Committed with the policies so a restart does not insert them again
+	if err := job.GetJobMonitorService().MarkCallbackCommitted(tx, jobID); err != nil {
+		return 0, err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		logger.Errorf("Failed to commit policies for job %s: %v", jobID, err)
 		return 0, fmt.Errorf("failed to commit policies: %v", err)
@@ -665,7 +670,7 @@ func processQueryResult(result [][]interface{}) string {
 }
 
 // CreateCombinedPolicyCompletionHandler creates a callback function for combined policy job completion
-func CreateCombinedPolicyCompletionHandler() job.JobCompletionCallback {
+func CreateCombinedPolicyCompletionHandler(agentExec agent.AgentExecutor) job.JobCompletionCallback {
 	return func(jobID string, jobInfo *job.JobInfo, statusResp *job.StatusResponse) error {
 		logger.Infof("Processing combined policy completion for job %s, status: %s", jobID, statusResp.Status)
 
@@ -676,7 +681,7 @@ func CreateCombinedPolicyCompletionHandler() job.JobCompletionCallback {
 		}
 
 		if statusResp.Status == "completed" {
-			return processCombinedPolicyResults(jobID, contextData, statusResp, jobInfo)
+			return processCombinedPolicyResults(agentExec, jobID, contextData, statusResp, jobInfo)
 		} else {
 			logger.Errorf("Combined policy job %s failed, no policies will be created", jobID)
 			return fmt.Errorf("combined policy job failed: %s", statusResp.Message)
@@ -685,7 +690,7 @@ func CreateCombinedPolicyCompletionHandler() job.JobCompletionCallback {
 }
 
 // processCombinedPolicyResults processes the results of a completed combined policy job
-func processCombinedPolicyResults(jobID string, contextData interface{}, statusResp *job.StatusResponse, jobInfo *job.JobInfo) error {
+func processCombinedPolicyResults(agentExec agent.AgentExecutor, jobID string, contextData interface{}, statusResp *job.StatusResponse, jobInfo *job.JobInfo) error {
 	logger.Infof("Processing combined policy results for job %s - completed: %d, failed: %d",
 		jobID, statusResp.Completed, statusResp.Failed)
 
@@ -701,7 +706,7 @@ func processCombinedPolicyResults(jobID string, contextData interface{}, statusR
 	}
 
 	// Legacy VeloArtifact polling flow
-	return processCombinedPolicyResultsFromVeloArtifact(jobID, combinedContext, statusResp)
+	return processCombinedPolicyResultsFromVeloArtifact(agentExec, jobID, combinedContext, statusResp)
 }
 
 // processCombinedPolicyResultsFromNotification handles combined policy processing when triggered by external notification
@@ -755,7 +760,7 @@ func processCombinedPolicyResultsFromNotification(jobID string, combinedContext
 }
 
 // processCombinedPolicyResultsFromVeloArtifact handles combined policy processing via traditional VeloArtifact polling
-func processCombinedPolicyResultsFromVeloArtifact(jobID string, combinedContext *CombinedPolicyJobContext, statusResp *job.StatusResponse) error {
+func processCombinedPolicyResultsFromVeloArtifact(agentExec agent.AgentExecutor, jobID string, combinedContext *CombinedPolicyJobContext, statusResp *job.StatusResponse) error {
 	logger.Infof("Processing combined policy results from VeloArtifact polling for job %s", jobID)
 
 	// Get endpoint information
@@ -765,7 +770,7 @@ func processCombinedPolicyResultsFromVeloArtifact(jobID string, combinedContext
 	}
 
 	// Retrieve and download results file via VeloArtifact
-	resultsData, err := RetrieveJobResults(jobID, ep)
+	resultsData, err := RetrieveJobResults(agentExec, jobID, ep)
 	if err != nil {
 		return err
 	}
@@ -899,6 +904,11 @@ func createCombinedPoliciesFromResults(jobID string, combinedContext *CombinedPo
 		}
 	}
 
+	// Committed with the combined policies so a restart does not insert them again
+	if err := job.GetJobMonitorService().MarkCallbackCommitted(tx, jobID); err != nil {
+		return 0, err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		logger.Errorf("Failed to commit combined policies for job %s: %v", jobID, err)
 		return 0, fmt.Errorf("failed to commit combined policies: %v", err)
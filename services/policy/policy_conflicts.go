@@ -0,0 +1,125 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+
+	"dbfartifactapi/models"
+	"dbfartifactapi/pkg/logger"
+	"dbfartifactapi/services/dto"
+	"dbfartifactapi/services/policy/conflict"
+)
+
+// AnalyzeConflicts reports the contradictory, shadowed and redundant policies of a connection per actor.
+// Besides the dbpolicy rows it takes the policy defaults each actor of the connection inherits from the
+// groups it currently belongs to, as the group endpoints grant them.
+func (s *dbPolicyService) AnalyzeConflicts(ctx context.Context, cntMgtID uint) (*dto.PolicyConflictReport, error) {
+	if cntMgtID == 0 {
+		return nil, fmt.Errorf("invalid connection management ID: must be greater than 0")
+	}
+	if _, err := s.cntMgtRepo.GetCntMgtByID(nil, cntMgtID); err != nil {
+		return nil, fmt.Errorf("cntmgt with id=%d not found: %v", cntMgtID, err)
+	}
+
+	policies, err := s.dbPolicyRepo.GetAllByCntMgt(nil, cntMgtID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load policies of cnt_id=%d: %v", cntMgtID, err)
+	}
+	actors, err := s.dbActorMgtRepo.GetByCntMgt(nil, cntMgtID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load actors of cnt_id=%d: %v", cntMgtID, err)
+	}
+	grants, err := s.groupGrants(cntMgtID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := conflict.Analyze(cntMgtID, policies, grants)
+	dbUsers := make(map[uint]string, len(actors))
+	for _, actor := range actors {
+		dbUsers[actor.ID] = actor.DBUser
+	}
+	for i := range report.Actors {
+		report.Actors[i].DBUser = dbUsers[report.Actors[i].DBActorMgtID]
+	}
+
+	logger.Infof("Policy conflicts for cnt_id=%d: %d policies, %d contradictory, %d shadowed, %d redundant, %d removable",
+		cntMgtID, report.PolicyCount, report.Contradictory, report.Shadowed, report.Redundant, len(report.RemovablePolicy))
+	return report, nil
+}
+
+// groupGrants returns the policy defaults each actor of a connection inherits from its groups.
+// Memberships and group policies outside their validity period are skipped, like in the group endpoints.
+// Three queries cover all actors: their memberships, the policies of those groups and the policy rows.
+func (s *dbPolicyService) groupGrants(cntMgtID uint) ([]conflict.GroupGrant, error) {
+	memberships, err := s.actorGroupsRepo.GetActiveByCntMgt(nil, cntMgtID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load group memberships of cnt_id=%d: %v", cntMgtID, err)
+	}
+	if len(memberships) == 0 {
+		return nil, nil
+	}
+
+	var groupIDs []uint
+	seenGroups := make(map[uint]bool)
+	for _, membership := range memberships {
+		if !seenGroups[membership.GroupID] {
+			seenGroups[membership.GroupID] = true
+			groupIDs = append(groupIDs, membership.GroupID)
+		}
+	}
+	defaultsByGroup, err := s.groupPolicyDefaults(groupIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var grants []conflict.GroupGrant
+	for _, membership := range memberships {
+		for _, policyDefault := range defaultsByGroup[membership.GroupID] {
+			grants = append(grants, conflict.GroupGrant{
+				DBActorMgtID:    membership.ActorID,
+				DBPolicyDefault: policyDefault,
+				GroupIDs:        []uint{membership.GroupID},
+			})
+		}
+	}
+	return grants, nil
+}
+
+// groupPolicyDefaults returns the policy defaults the active policies of each group grant.
+func (s *dbPolicyService) groupPolicyDefaults(groupIDs []uint) (map[uint][]uint, error) {
+	policyGroups, err := s.policyGroupsRepo.GetActivePoliciesByGroupIDs(nil, groupIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load policies of groups %v: %v", groupIDs, err)
+	}
+
+	var policyIDs []uint
+	seenPolicies := make(map[uint]bool)
+	for _, pg := range policyGroups {
+		if !seenPolicies[pg.DBGroupListPoliciesID] {
+			seenPolicies[pg.DBGroupListPoliciesID] = true
+			policyIDs = append(policyIDs, pg.DBGroupListPoliciesID)
+		}
+	}
+	groupPolicies, err := s.groupListPoliciesRepo.GetByIDs(nil, policyIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load group policies: %v", err)
+	}
+	groupPolicyByID := make(map[uint]models.DBGroupListPolicies, len(groupPolicies))
+	for _, groupPolicy := range groupPolicies {
+		groupPolicyByID[groupPolicy.ID] = groupPolicy
+	}
+
+	defaultsByGroup := make(map[uint][]uint)
+	for _, pg := range policyGroups {
+		groupPolicy, ok := groupPolicyByID[pg.DBGroupListPoliciesID]
+		if !ok {
+			logger.Warnf("Group %d references missing group policy %d", pg.GroupID, pg.DBGroupListPoliciesID)
+			continue
+		}
+		if groupPolicy.DBPolicyDefaultID != nil {
+			defaultsByGroup[pg.GroupID] = append(defaultsByGroup[pg.GroupID], conflict.ParsePolicyDefaultIDs(*groupPolicy.DBPolicyDefaultID)...)
+		}
+	}
+	return defaultsByGroup, nil
+}
@@ -0,0 +1,159 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"dbfartifactapi/models"
+	"dbfartifactapi/services/dto"
+)
+
+// Dry-run previews resolve the same connections, endpoints and SQL as the changes they preview,
+// but never call the agent and never write to the database.
+
+// PreviewCreate returns the policy Create would insert and the allow command it would run.
+func (s *dbPolicyService) PreviewCreate(ctx context.Context, data models.DBPolicy) (*dto.PolicyDryRunResponse, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("context cannot be nil")
+	}
+	if err := validatePolicyInput(data); err != nil {
+		return nil, err
+	}
+	if data.CntMgt == 0 && data.DBMgt == 0 {
+		return nil, fmt.Errorf("either connection management ID or database management ID must be provided")
+	}
+
+	cmd, err := s.renderPolicyUpdateSql(s.DBPolicyDefaultsAllMap[data.DBPolicyDefault].SqlUpdateAllow, data)
+	if err != nil {
+		return nil, err
+	}
+
+	data.ID = 0
+	data.Status = "enabled"
+	return &dto.PolicyDryRunResponse{
+		DryRun:   true,
+		Added:    []models.DBPolicy{data},
+		Removed:  []models.DBPolicy{},
+		Commands: []dto.DryRunCommand{cmd.dryRun(dto.DryRunOperationAllow)},
+	}, nil
+}
+
+// PreviewUpdate returns the policy before and after Update, the deny command for the old policy
+// and, when the new one is enabled, its allow command.
+func (s *dbPolicyService) PreviewUpdate(ctx context.Context, id uint, data models.DBPolicy) (*dto.PolicyDryRunResponse, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("context cannot be nil")
+	}
+	if id == 0 {
+		return nil, fmt.Errorf("invalid policy ID: must be greater than 0")
+	}
+	if err := validatePolicyInput(data); err != nil {
+		return nil, err
+	}
+
+	before, err := s.dbPolicyRepo.GetById(nil, id)
+	if err != nil {
+		return nil, fmt.Errorf("dbpolicy with id=%d not found: %v", id, err)
+	}
+
+	deny, err := s.renderPolicyUpdateSql(s.DBPolicyDefaultsAllMap[before.DBPolicyDefault].SqlUpdateDeny, *before)
+	if err != nil {
+		return nil, err
+	}
+	commands := []dto.DryRunCommand{deny.dryRun(dto.DryRunOperationDeny)}
+	if data.Status == "enabled" {
+		allow, err := s.renderPolicyUpdateSql(s.DBPolicyDefaultsAllMap[data.DBPolicyDefault].SqlUpdateAllow, data)
+		if err != nil {
+			return nil, err
+		}
+		commands = append(commands, allow.dryRun(dto.DryRunOperationAllow))
+	}
+
+	after := *before
+	after.CntMgt = data.CntMgt
+	after.DBMgt = data.DBMgt
+	after.DBActorMgt = data.DBActorMgt
+	after.DBObjectMgt = data.DBObjectMgt
+	after.DBPolicyDefault = data.DBPolicyDefault
+	after.Status = data.Status
+	after.Description = data.Description
+	return &dto.PolicyDryRunResponse{
+		DryRun:   true,
+		Added:    []models.DBPolicy{after},
+		Removed:  []models.DBPolicy{*before},
+		Commands: commands,
+	}, nil
+}
+
+// PreviewBulkUpdate returns the policy combinations BulkUpdatePoliciesByActor would add and remove
+// and the revoke and grant statements its background job would run.
+func (s *dbPolicyService) PreviewBulkUpdate(ctx context.Context, req dto.BulkPolicyUpdateRequest) (*dto.BulkPolicyDryRunResponse, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("context cannot be nil")
+	}
+
+	tx := s.baseRepo.Begin()
+	defer tx.Rollback()
+
+	bulkContext, ep, err := s.planBulkPolicyUpdate(ctx, tx, req)
+	if err != nil {
+		return nil, err
+	}
+	commandMap, err := s.buildBulkPolicyCommands(bulkContext.PolicesToAdd, bulkContext.PolicesToRemove,
+		bulkContext.DBMgt, bulkContext.Actor, bulkContext.CMT)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build bulk policy commands: %v", err)
+	}
+
+	preview := &dto.BulkPolicyDryRunResponse{
+		DryRun:           true,
+		CntMgtID:         req.CntMgtID,
+		DBMgtID:          req.DBMgtID,
+		DBActorMgtID:     req.DBActorMgtID,
+		PoliciesToAdd:    bulkContext.PolicesToAdd,
+		PoliciesToRemove: bulkContext.PolicesToRemove,
+		Commands:         []dto.DryRunCommand{},
+	}
+	if preview.PoliciesToAdd == nil {
+		preview.PoliciesToAdd = []dto.PolicyCombination{}
+	}
+	if preview.PoliciesToRemove == nil {
+		preview.PoliciesToRemove = []dto.PolicyCombination{}
+	}
+
+	// The job runs every command on the database of the update; Oracle routes by service name instead
+	database := bulkContext.DBMgt.DbName
+	if strings.ToLower(bulkContext.CMT.CntType) == "oracle" {
+		database = bulkContext.CMT.ServiceName
+	}
+	for _, op := range []struct{ action, operation string }{
+		{"remove", dto.DryRunOperationDeny},
+		{"add", dto.DryRunOperationAllow},
+	} {
+		var keys []string
+		for key, cmd := range commandMap {
+			if cmd.Action == op.action {
+				keys = append(keys, key)
+			}
+		}
+		if len(keys) == 0 {
+			continue
+		}
+		sort.Strings(keys)
+
+		command := dto.DryRunCommand{
+			CntMgtID:  bulkContext.CMT.ID,
+			CntType:   bulkContext.CMT.CntType,
+			Database:  database,
+			Operation: op.operation,
+			Endpoint:  *ep,
+		}
+		for _, key := range keys {
+			command.Statements = append(command.Statements, commandMap[key].SQL)
+		}
+		preview.Commands = append(preview.Commands, command)
+	}
+	return preview, nil
+}
@@ -0,0 +1,315 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"dbfartifactapi/config"
+	"dbfartifactapi/models"
+	"dbfartifactapi/pkg/logger"
+	"dbfartifactapi/pkg/tracing"
+	"dbfartifactapi/services/audit"
+	"dbfartifactapi/services/dto"
+	"dbfartifactapi/services/policy/revision"
+	"dbfartifactapi/utils"
+
+	"go.opentelemetry.io/otel/attribute"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultRevisionPageSize = 50
+	maxRevisionPageSize     = 500
+)
+
+// recordRevision stores a change to single policies as a revision of every connection it touches,
+// so an update moving a policy to another connection shows up in the history of both.
+func (s *dbPolicyService) recordRevision(ctx context.Context, tx *gorm.DB, action string, added, removed []models.DBPolicy) error {
+	if !config.Cfg.PolicyRevisionsEnabled {
+		return nil
+	}
+
+	inputs := make(map[uint]*revision.Input)
+	var cntMgtIDs []uint
+	inputFor := func(policy models.DBPolicy) (*revision.Input, error) {
+		cntMgtID, err := s.policyCntMgtID(tx, policy)
+		if err != nil {
+			return nil, err
+		}
+		input, exists := inputs[cntMgtID]
+		if !exists {
+			input = &revision.Input{CntMgtID: cntMgtID, Action: action, Author: audit.Actor(ctx)}
+			inputs[cntMgtID] = input
+			cntMgtIDs = append(cntMgtIDs, cntMgtID)
+		}
+		return input, nil
+	}
+	for _, policy := range added {
+		input, err := inputFor(policy)
+		if err != nil {
+			return err
+		}
+		input.Added = append(input.Added, policy)
+	}
+	for _, policy := range removed {
+		input, err := inputFor(policy)
+		if err != nil {
+			return err
+		}
+		input.Removed = append(input.Removed, policy)
+	}
+
+	for _, cntMgtID := range cntMgtIDs {
+		if _, err := revision.Record(tx, *inputs[cntMgtID]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// policyCntMgtID returns the connection of a policy, looked up through its database when only that is set.
+func (s *dbPolicyService) policyCntMgtID(tx *gorm.DB, policy models.DBPolicy) (uint, error) {
+	if policy.CntMgt != 0 {
+		return policy.CntMgt, nil
+	}
+	if policy.DBMgt <= 0 {
+		return 0, fmt.Errorf("policy id=%d belongs to no connection", policy.ID)
+	}
+	dbmgt, err := s.dbMgtRepo.GetByID(tx, utils.MustIntToUint(policy.DBMgt))
+	if err != nil {
+		return 0, fmt.Errorf("dbmgt with id=%d not found: %v", policy.DBMgt, err)
+	}
+	return dbmgt.CntID, nil
+}
+
+// ListRevisions returns one page of the policy revisions of a connection, newest first.
+func (s *dbPolicyService) ListRevisions(ctx context.Context, cntMgtID uint, query dto.PolicyRevisionQuery) (*dto.PolicyRevisionListResponse, error) {
+	if cntMgtID == 0 {
+		return nil, fmt.Errorf("invalid connection management ID: must be greater than 0")
+	}
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.PageSize < 1 {
+		query.PageSize = defaultRevisionPageSize
+	}
+	if query.PageSize > maxRevisionPageSize {
+		query.PageSize = maxRevisionPageSize
+	}
+
+	revisions, total, err := s.revisionRepo.List(nil, cntMgtID, (query.Page-1)*query.PageSize, query.PageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list policy revisions for cntmgt_id=%d: %v", cntMgtID, err)
+	}
+
+	totalPages := int(total) / query.PageSize
+	if int(total)%query.PageSize != 0 {
+		totalPages++
+	}
+	return &dto.PolicyRevisionListResponse{
+		CntMgtID:   cntMgtID,
+		Revisions:  revisions,
+		Total:      total,
+		Page:       query.Page,
+		PageSize:   query.PageSize,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// GetRevision returns one policy revision of a connection with its changeset and policy set.
+func (s *dbPolicyService) GetRevision(ctx context.Context, cntMgtID uint, rev int) (*dto.PolicyRevisionDetailResponse, error) {
+	target, err := s.getRevision(cntMgtID, rev)
+	if err != nil {
+		return nil, err
+	}
+	return revision.Detail(target)
+}
+
+// DiffRevisions compares the policy sets after two revisions of a connection.
+// fromRev defaults to the revision right before toRev (pass 0 to use the default).
+func (s *dbPolicyService) DiffRevisions(ctx context.Context, cntMgtID uint, fromRev, toRev int) (*dto.PolicyRevisionDiffResponse, error) {
+	if fromRev < 0 {
+		return nil, fmt.Errorf("policy revisions must be positive")
+	}
+	if fromRev != 0 && fromRev == toRev {
+		return nil, fmt.Errorf("from revision must differ from revision %d", toRev)
+	}
+	to, err := s.getRevision(cntMgtID, toRev)
+	if err != nil {
+		return nil, err
+	}
+
+	var from *models.PolicyRevision
+	if fromRev == 0 {
+		from, err = s.revisionRepo.GetPrevious(nil, cntMgtID, to.Revision)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("no policy revision older than r%d for cntmgt_id=%d", to.Revision, cntMgtID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load policy revision for cntmgt_id=%d: %v", cntMgtID, err)
+		}
+	} else if from, err = s.getRevision(cntMgtID, fromRev); err != nil {
+		return nil, err
+	}
+
+	diff, err := revision.Diff(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("Policy revision diff cntmgt_id=%d r%d..r%d: +%d/-%d policies, %d changed",
+		cntMgtID, from.Revision, to.Revision, len(diff.Added), len(diff.Removed), len(diff.Changed))
+	return diff, nil
+}
+
+func (s *dbPolicyService) getRevision(cntMgtID uint, rev int) (*models.PolicyRevision, error) {
+	if cntMgtID == 0 {
+		return nil, fmt.Errorf("invalid connection management ID: must be greater than 0")
+	}
+	if rev <= 0 {
+		return nil, fmt.Errorf("invalid policy revision: must be greater than 0")
+	}
+	result, err := s.revisionRepo.GetByRevision(nil, cntMgtID, rev)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("policy revision r%d not found for cntmgt_id=%d", rev, cntMgtID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load policy revision r%d for cntmgt_id=%d: %v", rev, cntMgtID, err)
+	}
+	return result, nil
+}
+
+// RollbackToRevision brings the policies of a connection back to how they were after a revision.
+// The grants differing from the current policies are applied as one bulk policy update job per
+// database and actor, through the same allow/deny SQL as BulkUpdatePoliciesByActor. Each job records
+// a rollback revision and rebuilds the rule files once its commands succeeded.
+func (s *dbPolicyService) RollbackToRevision(ctx context.Context, cntMgtID uint, rev int) (_ *dto.PolicyRollbackResponse, err error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("context cannot be nil")
+	}
+
+	ctx, span := tracing.Start(ctx, "policy.RollbackToRevision",
+		attribute.Int("cntmgt.id", int(cntMgtID)), attribute.Int("policy.revision", rev))
+	defer func() { tracing.End(span, err) }()
+
+	target, err := s.getRevision(cntMgtID, rev)
+	if err != nil {
+		return nil, err
+	}
+	targetPolicies, err := revision.Policies(target)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.dbPolicyRepo.GetAllByCntMgt(nil, cntMgtID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load policies for cntmgt_id=%d: %v", cntMgtID, err)
+	}
+
+	plan := revision.PlanRollback(current, targetPolicies)
+	logger.Infof("Rollback plan cntmgt_id=%d to r%d: grant %d, revoke %d, skipped %d",
+		cntMgtID, rev, len(plan.Grant), len(plan.Revoke), len(plan.Skipped))
+
+	response := &dto.PolicyRollbackResponse{
+		CntMgtID:       cntMgtID,
+		TargetRevision: rev,
+		Jobs:           []dto.PolicyRollbackJob{},
+		Skipped:        plan.Skipped,
+	}
+	if len(plan.Grant) == 0 && len(plan.Revoke) == 0 {
+		response.Message = fmt.Sprintf("No changes detected - policies already match revision r%d", rev)
+		return response, nil
+	}
+
+	cmt, err := s.cntMgtRepo.GetCntMgtByID(nil, cntMgtID)
+	if err != nil {
+		return nil, fmt.Errorf("cntmgt with id=%d not found: %v", cntMgtID, err)
+	}
+	ep, err := s.endpointRepo.GetByID(nil, utils.MustIntToUint(cmt.Agent))
+	if err != nil {
+		return nil, fmt.Errorf("cannot find endpoint with id=%d: %v", cmt.Agent, err)
+	}
+
+	// Group the changes the way bulk policy updates are scoped: one database and one actor
+	type scope struct{ dbMgtID, actorID uint }
+	groups := make(map[scope]*dto.BulkPolicyUpdateJobContext)
+	var scopes []scope
+	contextFor := func(policy models.DBPolicy) *dto.BulkPolicyUpdateJobContext {
+		key := scope{dbMgtID: utils.MustIntToUint(policy.DBMgt), actorID: policy.DBActorMgt}
+		bulkContext, exists := groups[key]
+		if !exists {
+			bulkContext = &dto.BulkPolicyUpdateJobContext{
+				CntMgtID:     cntMgtID,
+				DBMgtID:      key.dbMgtID,
+				DBActorMgtID: key.actorID,
+				CMT:          cmt,
+				EndpointID:   ep.ID,
+				Author:       audit.Actor(ctx),
+				RollbackTo:   rev,
+			}
+			groups[key] = bulkContext
+			scopes = append(scopes, key)
+		}
+		return bulkContext
+	}
+	for _, policy := range plan.Grant {
+		bulkContext := contextFor(policy)
+		bulkContext.PolicesToAdd = append(bulkContext.PolicesToAdd, dto.PolicyCombination{
+			PolicyDefaultID: policy.DBPolicyDefault,
+			ObjectMgtID:     utils.MustIntToUint(policy.DBObjectMgt),
+		})
+	}
+	for _, policy := range plan.Revoke {
+		bulkContext := contextFor(policy)
+		bulkContext.PolicesToRemove = append(bulkContext.PolicesToRemove, dto.PolicyCombination{
+			PolicyDefaultID: policy.DBPolicyDefault,
+			ObjectMgtID:     utils.MustIntToUint(policy.DBObjectMgt),
+			DBPolicyID:      policy.ID,
+		})
+	}
+	sort.Slice(scopes, func(i, j int) bool {
+		if scopes[i].dbMgtID != scopes[j].dbMgtID {
+			return scopes[i].dbMgtID < scopes[j].dbMgtID
+		}
+		return scopes[i].actorID < scopes[j].actorID
+	})
+
+	// Resolve every database and actor before starting any job, so a missing one starts nothing
+	for _, key := range scopes {
+		bulkContext := groups[key]
+		if bulkContext.DBMgt, err = s.dbMgtRepo.GetByID(nil, key.dbMgtID); err != nil {
+			return nil, fmt.Errorf("dbmgt with id=%d not found: %v", key.dbMgtID, err)
+		}
+		if bulkContext.Actor, err = s.dbActorMgtRepo.GetByID(nil, key.actorID); err != nil {
+			return nil, fmt.Errorf("dbactormgt with id=%d not found: %v", key.actorID, err)
+		}
+	}
+
+	var jobIDs []string
+	for _, key := range scopes {
+		bulkContext := groups[key]
+		jobID, err := s.startBulkPolicyUpdateJob(ctx, bulkContext, ep)
+		if err != nil {
+			if len(jobIDs) > 0 {
+				return nil, fmt.Errorf("rollback to r%d stopped after starting jobs %s: dbmgt_id=%d, actor_id=%d: %v",
+					rev, strings.Join(jobIDs, ", "), key.dbMgtID, key.actorID, err)
+			}
+			return nil, err
+		}
+		jobIDs = append(jobIDs, jobID)
+		response.Jobs = append(response.Jobs, dto.PolicyRollbackJob{
+			JobID:           jobID,
+			DBMgtID:         key.dbMgtID,
+			DBActorMgtID:    key.actorID,
+			PoliciesAdded:   len(bulkContext.PolicesToAdd),
+			PoliciesRemoved: len(bulkContext.PolicesToRemove),
+		})
+	}
+
+	response.Message = fmt.Sprintf("Rollback to revision r%d started: %d background jobs granting %d and revoking %d policies.",
+		rev, len(response.Jobs), len(plan.Grant), len(plan.Revoke))
+	logger.Infof("Rollback cntmgt_id=%d to r%d started jobs %s", cntMgtID, rev, strings.Join(jobIDs, ", "))
+	return response, nil
+}
@@ -0,0 +1,72 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"dbfartifactapi/models"
+	"dbfartifactapi/pkg/logger"
+	"dbfartifactapi/services/dto"
+	"dbfartifactapi/services/privilege/snapshot"
+
+	"gorm.io/gorm"
+)
+
+// GetPrivilegeDrift compares two privilege snapshots of a connection.
+// toVersion defaults to the latest snapshot and fromVersion to the one right before toVersion
+// (pass 0 to use the default).
+func (s *dbPolicyService) GetPrivilegeDrift(ctx context.Context, cntMgtID uint, fromVersion, toVersion int) (*dto.PrivilegeDriftResponse, error) {
+	if cntMgtID == 0 {
+		return nil, fmt.Errorf("invalid connection management ID: must be greater than 0")
+	}
+	if fromVersion < 0 || toVersion < 0 {
+		return nil, fmt.Errorf("snapshot versions must be positive")
+	}
+
+	var to *models.PrivilegeSnapshot
+	var err error
+	if toVersion == 0 {
+		to, err = s.snapshotRepo.GetLatest(nil, cntMgtID)
+	} else {
+		to, err = s.snapshotRepo.GetByVersion(nil, cntMgtID, toVersion)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if toVersion == 0 {
+			return nil, fmt.Errorf("no privilege snapshots found for cntmgt_id=%d", cntMgtID)
+		}
+		return nil, fmt.Errorf("privilege snapshot v%d not found for cntmgt_id=%d", toVersion, cntMgtID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load privilege snapshot for cntmgt_id=%d: %v", cntMgtID, err)
+	}
+
+	var from *models.PrivilegeSnapshot
+	if fromVersion == 0 {
+		from, err = s.snapshotRepo.GetPrevious(nil, cntMgtID, to.Version)
+	} else {
+		if fromVersion >= to.Version {
+			return nil, fmt.Errorf("from version %d must be lower than to version %d", fromVersion, to.Version)
+		}
+		from, err = s.snapshotRepo.GetByVersion(nil, cntMgtID, fromVersion)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if fromVersion == 0 {
+			return nil, fmt.Errorf("no privilege snapshot older than v%d for cntmgt_id=%d", to.Version, cntMgtID)
+		}
+		return nil, fmt.Errorf("privilege snapshot v%d not found for cntmgt_id=%d", fromVersion, cntMgtID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load privilege snapshot for cntmgt_id=%d: %v", cntMgtID, err)
+	}
+
+	drift, err := snapshot.Diff(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("Privilege drift cntmgt_id=%d v%d..v%d: grants +%d/-%d, actors +%d/-%d, policies +%d/-%d",
+		cntMgtID, from.Version, to.Version, len(drift.GrantsAdded), len(drift.GrantsRevoked),
+		len(drift.ActorsAdded), len(drift.ActorsRemoved), len(drift.PoliciesAllowed), len(drift.PoliciesRevoked))
+	return drift, nil
+}
@@ -0,0 +1,106 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"dbfartifactapi/pkg/logger"
+	"dbfartifactapi/services/dto"
+	"dbfartifactapi/services/privilege"
+	privmssql "dbfartifactapi/services/privilege/mssql"
+	privmysql "dbfartifactapi/services/privilege/mysql"
+	privoracle "dbfartifactapi/services/privilege/oracle"
+	privpostgres "dbfartifactapi/services/privilege/postgres"
+	"dbfartifactapi/services/privilege/snapshot"
+
+	"gorm.io/gorm"
+)
+
+// ExplainActorPrivileges re-evaluates one actor against the latest privilege snapshot of its connection.
+// Returns every policy default that would be allowed, with the pass that matched, the final SQL,
+// its result value and the grant rows and role chains behind it. No policies are written.
+func (s *dbPolicyService) ExplainActorPrivileges(ctx context.Context, actorID uint) (*dto.PrivilegeExplainResponse, error) {
+	if actorID == 0 {
+		return nil, fmt.Errorf("invalid actor management ID: must be greater than 0")
+	}
+	if ctx == nil {
+		return nil, fmt.Errorf("context cannot be nil")
+	}
+
+	actor, err := s.dbActorMgtRepo.GetByID(nil, actorID)
+	if err != nil {
+		return nil, fmt.Errorf("dbactormgt with id=%d not found: %v", actorID, err)
+	}
+
+	cmt, err := s.cntMgtRepo.GetCntMgtByID(nil, actor.CntID)
+	if err != nil {
+		return nil, fmt.Errorf("cntmgt with id=%d not found: %v", actor.CntID, err)
+	}
+
+	dbmgts, err := s.dbMgtRepo.GetByCntMgtId(nil, cmt.ID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot find dbmgt with cntid=%d: %v", cmt.ID, err)
+	}
+	if len(dbmgts) == 0 {
+		return nil, fmt.Errorf("no databases found for cntmgt_id=%d", cmt.ID)
+	}
+
+	latest, err := s.snapshotRepo.GetLatest(nil, cmt.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("no privilege snapshots found for cntmgt_id=%d, run policy discovery first", cmt.ID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load privilege snapshot for cntmgt_id=%d: %v", cmt.ID, err)
+	}
+
+	grants, err := snapshot.DecodeGrants(latest)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]privilege.QueryResult, 0, len(grants))
+	for _, grant := range grants {
+		results = append(results, privilege.QueryResult{QueryKey: grant.QueryKey, Status: grant.Status, Result: grant.Result})
+	}
+
+	input := privilege.ExplainInput{
+		CMT:    cmt,
+		Actor:  *actor,
+		DbMgts: dbmgts,
+		Grants: results,
+	}
+
+	var explained *privilege.ExplainResult
+	cntType := strings.ToLower(cmt.CntType)
+	switch cntType {
+	case "oracle":
+		explained, err = privoracle.ExplainOracleActorPrivileges(input)
+	case "mysql":
+		explained, err = privmysql.ExplainActorPrivileges(input)
+	case "postgres", "postgresql":
+		explained, err = privpostgres.ExplainPostgresActorPrivileges(input)
+	case "mssql", "sqlserver":
+		explained, err = privmssql.ExplainMSSQLActorPrivileges(input)
+	default:
+		return nil, fmt.Errorf("unsupported database type: %s", cmt.CntType)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to explain privileges of actor id=%d: %v", actorID, err)
+	}
+
+	logger.Infof("Explained privileges of actor id=%d (%s) against snapshot v%d of cntmgt_id=%d: %d allowed policies, %d roles, super=%t",
+		actor.ID, actor.DBUser, latest.Version, cmt.ID, len(explained.Policies), len(explained.Roles), explained.SuperPrivilege)
+
+	return &dto.PrivilegeExplainResponse{
+		ActorID:           actor.ID,
+		DBUser:            actor.DBUser,
+		CntMgtID:          cmt.ID,
+		DBType:            cntType,
+		SnapshotVersion:   latest.Version,
+		SnapshotCreatedAt: latest.CreatedAt,
+		SuperPrivilege:    explained.SuperPrivilege,
+		Roles:             explained.Roles,
+		Policies:          explained.Policies,
+	}, nil
+}
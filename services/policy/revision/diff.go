@@ -0,0 +1,144 @@
+package revision
+
+import (
+	"fmt"
+	"sort"
+
+	"dbfartifactapi/models"
+	"dbfartifactapi/services/dto"
+)
+
+// key identifies what a policy grants: one policy default for one actor on one database object.
+// IDs and descriptions are left out, so a policy deleted and created again matches itself.
+func key(policy models.DBPolicy) string {
+	return fmt.Sprintf("%d_%d_%d_%d", policy.DBMgt, policy.DBActorMgt, policy.DBPolicyDefault, policy.DBObjectMgt)
+}
+
+func byKey(policies []models.DBPolicy) map[string]models.DBPolicy {
+	keyed := make(map[string]models.DBPolicy, len(policies))
+	for _, policy := range policies {
+		keyed[key(policy)] = policy
+	}
+	return keyed
+}
+
+// sortedPolicies returns the policies of a keyed set ordered by key, for stable responses.
+func sortedPolicies(keyed map[string]models.DBPolicy) []models.DBPolicy {
+	keys := make([]string, 0, len(keyed))
+	for k := range keyed {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	policies := make([]models.DBPolicy, 0, len(keys))
+	for _, k := range keys {
+		policies = append(policies, keyed[k])
+	}
+	return policies
+}
+
+// Diff compares the policy sets after two revisions of the same connection.
+func Diff(from, to *models.PolicyRevision) (*dto.PolicyRevisionDiffResponse, error) {
+	if from.CntMgtID != to.CntMgtID {
+		return nil, fmt.Errorf("revisions belong to different connections: cnt_id=%d and cnt_id=%d", from.CntMgtID, to.CntMgtID)
+	}
+	fromPolicies, err := Policies(from)
+	if err != nil {
+		return nil, err
+	}
+	toPolicies, err := Policies(to)
+	if err != nil {
+		return nil, err
+	}
+
+	before, after := byKey(fromPolicies), byKey(toPolicies)
+	added := make(map[string]models.DBPolicy)
+	removed := make(map[string]models.DBPolicy)
+	changed := make(map[string]models.DBPolicy)
+	for k, policy := range after {
+		old, exists := before[k]
+		switch {
+		case !exists:
+			added[k] = policy
+		case old.Status != policy.Status || old.Description != policy.Description:
+			changed[k] = policy
+		}
+	}
+	for k, policy := range before {
+		if _, exists := after[k]; !exists {
+			removed[k] = policy
+		}
+	}
+
+	diff := &dto.PolicyRevisionDiffResponse{
+		CntMgtID:      to.CntMgtID,
+		FromRevision:  from.Revision,
+		ToRevision:    to.Revision,
+		FromCreatedAt: from.CreatedAt,
+		ToCreatedAt:   to.CreatedAt,
+		Added:         sortedPolicies(added),
+		Removed:       sortedPolicies(removed),
+		Changed:       []dto.PolicyRevisionChange{},
+	}
+	for _, policy := range sortedPolicies(changed) {
+		diff.Changed = append(diff.Changed, dto.PolicyRevisionChange{From: before[key(policy)], To: policy})
+	}
+	return diff, nil
+}
+
+// RollbackPlan lists the grants that differ between the current policies and a revision.
+// Only enabled policies grant permissions, so disabled ones are left as they are.
+type RollbackPlan struct {
+	Grant   []models.DBPolicy // Enabled in the revision but not now
+	Revoke  []models.DBPolicy // Enabled now but not in the revision, with their current IDs
+	Skipped []models.DBPolicy // Connection-wide or wildcard-object policies the per-database bulk path cannot apply
+}
+
+// PlanRollback computes the grants and revokes bringing current back to the policies of target.
+func PlanRollback(current, target []models.DBPolicy) RollbackPlan {
+	now, then := byKey(enabled(current)), byKey(enabled(target))
+	grant := make(map[string]models.DBPolicy)
+	revoke := make(map[string]models.DBPolicy)
+	for k, policy := range then {
+		if _, exists := now[k]; !exists {
+			grant[k] = policy
+		}
+	}
+	for k, policy := range now {
+		if _, exists := then[k]; !exists {
+			revoke[k] = policy
+		}
+	}
+
+	var plan RollbackPlan
+	for _, policy := range sortedPolicies(grant) {
+		if bulkApplicable(policy) {
+			plan.Grant = append(plan.Grant, policy)
+		} else {
+			plan.Skipped = append(plan.Skipped, policy)
+		}
+	}
+	for _, policy := range sortedPolicies(revoke) {
+		if bulkApplicable(policy) {
+			plan.Revoke = append(plan.Revoke, policy)
+		} else {
+			plan.Skipped = append(plan.Skipped, policy)
+		}
+	}
+	return plan
+}
+
+func enabled(policies []models.DBPolicy) []models.DBPolicy {
+	result := make([]models.DBPolicy, 0, len(policies))
+	for _, policy := range policies {
+		if policy.Status == "enabled" {
+			result = append(result, policy)
+		}
+	}
+	return result
+}
+
+// bulkApplicable reports whether a policy targets one database and one object,
+// which is what bulk policy updates are built for.
+func bulkApplicable(policy models.DBPolicy) bool {
+	return policy.DBMgt > 0 && policy.DBObjectMgt > 0
+}
@@ -0,0 +1,171 @@
+package revision
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"dbfartifactapi/config"
+	"dbfartifactapi/models"
+	"dbfartifactapi/pkg/logger"
+	"dbfartifactapi/repository"
+	"dbfartifactapi/services/dto"
+
+	"gorm.io/gorm"
+)
+
+// Revision actions.
+const (
+	ActionBaseline   = "baseline"
+	ActionCreate     = "create"
+	ActionUpdate     = "update"
+	ActionDelete     = "delete"
+	ActionBulkUpdate = "bulk_update"
+	ActionRollback   = "rollback"
+)
+
+// baselineAuthor is recorded on the baseline revision, which no caller made.
+const baselineAuthor = "system"
+
+// Input describes one change to the policies of a connection.
+type Input struct {
+	CntMgtID   uint
+	Action     string
+	Author     string
+	JobID      string
+	RollbackTo int // Revision restored by a rollback
+	Added      []models.DBPolicy
+	Removed    []models.DBPolicy
+}
+
+// Record stores a change as the next revision of its connection. It must run inside the
+// caller's transaction after the change was written, so the stored policy set includes it
+// and a rolled back change leaves no revision behind.
+// The first change of a connection is preceded by a baseline revision holding the policy set
+// before it, so that change can be rolled back as well.
+// Returns nil revision without error when revisions are disabled.
+func Record(tx *gorm.DB, input Input) (*models.PolicyRevision, error) {
+	if !config.Cfg.PolicyRevisionsEnabled {
+		return nil, nil
+	}
+	if input.CntMgtID == 0 {
+		return nil, fmt.Errorf("cannot record policy revision without connection management ID")
+	}
+
+	policies, err := repository.NewDBPolicyRepository().GetAllByCntMgt(tx, input.CntMgtID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load policies of cnt_id=%d for revision: %w", input.CntMgtID, err)
+	}
+
+	repo := repository.NewPolicyRevisionRepository()
+	next := 1
+	latest, err := repo.LockLatest(tx, input.CntMgtID)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		base, err := newRevision(input.CntMgtID, next, ActionBaseline, baselineAuthor,
+			dto.PolicyRevisionChanges{}, baseline(policies, input.Added, input.Removed))
+		if err != nil {
+			return nil, err
+		}
+		if err := repo.Create(tx, base); err != nil {
+			return nil, fmt.Errorf("failed to store baseline policy revision for cnt_id=%d: %w", input.CntMgtID, err)
+		}
+		next++
+	case err != nil:
+		return nil, fmt.Errorf("failed to determine policy revision for cnt_id=%d: %w", input.CntMgtID, err)
+	default:
+		next = latest.Revision + 1
+	}
+
+	changes := dto.PolicyRevisionChanges{Added: input.Added, Removed: input.Removed}
+	revision, err := newRevision(input.CntMgtID, next, input.Action, input.Author, changes, policies)
+	if err != nil {
+		return nil, err
+	}
+	revision.JobID = input.JobID
+	revision.RolledBackTo = input.RollbackTo
+	if err := repo.Create(tx, revision); err != nil {
+		return nil, fmt.Errorf("failed to store policy revision for cnt_id=%d: %w", input.CntMgtID, err)
+	}
+
+	logger.Infof("Stored policy revision r%d for cnt_id=%d: %s by %s, +%d/-%d policies, %d total",
+		revision.Revision, input.CntMgtID, input.Action, input.Author, revision.AddedCount, revision.RemovedCount, revision.PolicyCount)
+	return revision, nil
+}
+
+func newRevision(cntMgtID uint, number int, action, author string, changes dto.PolicyRevisionChanges, policies []models.DBPolicy) (*models.PolicyRevision, error) {
+	if changes.Added == nil {
+		changes.Added = []models.DBPolicy{}
+	}
+	if changes.Removed == nil {
+		changes.Removed = []models.DBPolicy{}
+	}
+	if policies == nil {
+		policies = []models.DBPolicy{}
+	}
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode policy revision changes: %w", err)
+	}
+	policiesJSON, err := json.Marshal(policies)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode policy revision policy set: %w", err)
+	}
+	return &models.PolicyRevision{
+		CntMgtID:     cntMgtID,
+		Revision:     number,
+		Action:       action,
+		Author:       author,
+		AddedCount:   len(changes.Added),
+		RemovedCount: len(changes.Removed),
+		PolicyCount:  len(policies),
+		Changes:      string(changesJSON),
+		Policies:     string(policiesJSON),
+	}, nil
+}
+
+// baseline reconstructs the policy set before a change from the set after it.
+// An updated policy keeps its ID, so it is taken out as added and put back as removed.
+func baseline(after, added, removed []models.DBPolicy) []models.DBPolicy {
+	byID := make(map[uint]models.DBPolicy, len(after))
+	for _, policy := range after {
+		byID[policy.ID] = policy
+	}
+	for _, policy := range added {
+		delete(byID, policy.ID)
+	}
+	for _, policy := range removed {
+		byID[policy.ID] = policy
+	}
+
+	policies := make([]models.DBPolicy, 0, len(byID))
+	for _, policy := range byID {
+		policies = append(policies, policy)
+	}
+	sort.Slice(policies, func(i, j int) bool { return policies[i].ID < policies[j].ID })
+	return policies
+}
+
+// Detail decodes the changeset and policy set of a revision.
+func Detail(revision *models.PolicyRevision) (*dto.PolicyRevisionDetailResponse, error) {
+	detail := &dto.PolicyRevisionDetailResponse{PolicyRevision: *revision}
+	if err := json.Unmarshal([]byte(revision.Changes), &detail.Changes); err != nil {
+		return nil, fmt.Errorf("failed to decode changes of policy revision r%d: %w", revision.Revision, err)
+	}
+	policies, err := Policies(revision)
+	if err != nil {
+		return nil, err
+	}
+	detail.Policies = policies
+	return detail, nil
+}
+
+// Policies decodes the policy set of a connection as it was after a revision.
+func Policies(revision *models.PolicyRevision) ([]models.DBPolicy, error) {
+	var policies []models.DBPolicy
+	if err := json.Unmarshal([]byte(revision.Policies), &policies); err != nil {
+		return nil, fmt.Errorf("failed to decode policy set of policy revision r%d: %w", revision.Revision, err)
+	}
+	return policies, nil
+}
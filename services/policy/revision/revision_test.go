@@ -0,0 +1,124 @@
+package revision
+
+import (
+	"testing"
+
+	"dbfartifactapi/models"
+	"dbfartifactapi/services/dto"
+)
+
+// buildRevision encodes a policy set the same way Record does
+func buildRevision(t *testing.T, number int, policies []models.DBPolicy) *models.PolicyRevision {
+	t.Helper()
+	revision, err := newRevision(5, number, ActionUpdate, "alice", dto.PolicyRevisionChanges{}, policies)
+	if err != nil {
+		t.Fatalf("newRevision: %v", err)
+	}
+	return revision
+}
+
+func policy(id uint, dbMgt int, actor, policyDefault uint, object int, status string) models.DBPolicy {
+	return models.DBPolicy{ID: id, CntMgt: 5, DBMgt: dbMgt, DBActorMgt: actor, DBPolicyDefault: policyDefault, DBObjectMgt: object, Status: status}
+}
+
+// TestBaseline_ReconstructsSetBeforeChange tests that created policies are taken out and
+// deleted and updated ones restored to their old state
+func TestBaseline_ReconstructsSetBeforeChange(t *testing.T) {
+	kept := policy(1, 10, 1, 100, 7, "enabled")
+	updatedOld := policy(2, 10, 1, 101, 7, "enabled")
+	updatedNew := policy(2, 10, 1, 101, 7, "disabled")
+	created := policy(4, 10, 2, 100, 7, "enabled")
+	deleted := policy(3, 10, 2, 102, 8, "enabled")
+
+	before := baseline([]models.DBPolicy{kept, updatedNew, created},
+		[]models.DBPolicy{updatedNew, created}, []models.DBPolicy{updatedOld, deleted})
+
+	want := []models.DBPolicy{kept, updatedOld, deleted}
+	if len(before) != len(want) {
+		t.Fatalf("baseline = %+v, want %+v", before, want)
+	}
+	for i := range want {
+		if before[i] != want[i] {
+			t.Errorf("baseline[%d] = %+v, want %+v", i, before[i], want[i])
+		}
+	}
+}
+
+// TestDiff_AddedRemovedChanged tests that policies are matched by what they grant, not by ID
+func TestDiff_AddedRemovedChanged(t *testing.T) {
+	from := buildRevision(t, 1, []models.DBPolicy{
+		policy(1, 10, 1, 100, 7, "enabled"),
+		policy(2, 10, 1, 101, 7, "enabled"),
+		policy(3, 10, 2, 100, 7, "enabled"),
+	})
+	to := buildRevision(t, 2, []models.DBPolicy{
+		policy(1, 10, 1, 100, 7, "enabled"),
+		policy(2, 10, 1, 101, 7, "disabled"),
+		policy(9, 10, 2, 100, 7, "enabled"), // Deleted and created again
+		policy(8, 11, 2, 100, 7, "enabled"),
+	})
+
+	diff, err := Diff(from, to)
+	if err != nil {
+		t.Fatalf("Diff: %v", err)
+	}
+	if diff.FromRevision != 1 || diff.ToRevision != 2 {
+		t.Errorf("revisions = r%d..r%d, want r1..r2", diff.FromRevision, diff.ToRevision)
+	}
+	if len(diff.Added) != 1 || diff.Added[0].ID != 8 {
+		t.Errorf("added = %+v, want policy 8", diff.Added)
+	}
+	if len(diff.Removed) != 0 {
+		t.Errorf("removed = %+v, want none", diff.Removed)
+	}
+	if len(diff.Changed) != 1 || diff.Changed[0].From.Status != "enabled" || diff.Changed[0].To.Status != "disabled" {
+		t.Errorf("changed = %+v, want policy 2 enabled -> disabled", diff.Changed)
+	}
+
+	// Swapping the revisions reverses the diff
+	reverse, err := Diff(to, from)
+	if err != nil {
+		t.Fatalf("Diff: %v", err)
+	}
+	if len(reverse.Added) != 0 || len(reverse.Removed) != 1 || reverse.Removed[0].ID != 8 {
+		t.Errorf("reverse diff added %+v, removed %+v, want policy 8 removed", reverse.Added, reverse.Removed)
+	}
+}
+
+// TestDiff_RejectsOtherConnection tests that revisions of two connections are not compared
+func TestDiff_RejectsOtherConnection(t *testing.T) {
+	from := buildRevision(t, 1, nil)
+	to := buildRevision(t, 2, nil)
+	to.CntMgtID = 6
+	if _, err := Diff(from, to); err == nil {
+		t.Fatal("expected error for revisions of different connections")
+	}
+}
+
+// TestPlanRollback tests that only enabled grants are compared and policies the bulk path
+// cannot apply are skipped
+func TestPlanRollback(t *testing.T) {
+	current := []models.DBPolicy{
+		policy(1, 10, 1, 100, 7, "enabled"),  // In both
+		policy(4, 10, 1, 103, 7, "enabled"),  // Added after the target revision
+		policy(5, 10, 2, 100, 7, "disabled"), // Grants nothing
+		policy(6, -1, 2, 100, 7, "enabled"),  // Connection-wide
+	}
+	target := []models.DBPolicy{
+		policy(1, 10, 1, 100, 7, "enabled"),
+		policy(2, 10, 1, 101, 7, "enabled"),  // Deleted since
+		policy(3, 10, 2, 102, -1, "enabled"), // Wildcard object, deleted since
+		policy(7, 10, 2, 104, 8, "disabled"), // Grants nothing
+	}
+
+	plan := PlanRollback(current, target)
+	if len(plan.Grant) != 1 || plan.Grant[0].ID != 2 {
+		t.Errorf("grant = %+v, want policy 2", plan.Grant)
+	}
+	if len(plan.Revoke) != 1 || plan.Revoke[0].ID != 4 {
+		t.Errorf("revoke = %+v, want policy 4", plan.Revoke)
+	}
+	if len(plan.Skipped) != 2 || plan.Skipped[0].ID != 3 || plan.Skipped[1].ID != 6 {
+		t.Errorf("skipped = %+v, want policies 3 and 6", plan.Skipped)
+	}
+}
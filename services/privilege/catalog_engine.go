@@ -0,0 +1,938 @@
+package privilege
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"dbfartifactapi/config"
+	"dbfartifactapi/models"
+	"dbfartifactapi/pkg/logger"
+	"dbfartifactapi/repository"
+	"dbfartifactapi/services/job"
+	"dbfartifactapi/utils"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// processedCatalogJobs tracks catalog discovery jobs already processed to prevent duplicate execution
+var processedCatalogJobs sync.Map
+
+// CatalogTemplates holds the policy templates of a catalog dialect by execution order.
+type CatalogTemplates struct {
+	Super          []models.DBPolicyDefault
+	ActionWide     []models.DBPolicyDefault
+	ObjectSpecific []models.DBPolicyDefault
+}
+
+// AllowedPolicy is a policy default a discovery pass allowed for an actor.
+// DbMgtID and ObjectID are -1 when the policy covers all databases or all objects.
+type AllowedPolicy struct {
+	Key             string
+	ActorID         uint
+	PolicyDefaultID uint
+	DbMgtID         int
+	ObjectID        int
+}
+
+// CatalogPassResults holds the policies allowed by each discovery pass.
+type CatalogPassResults struct {
+	Super          []AllowedPolicy
+	ActionWide     []AllowedPolicy
+	ObjectSpecific []AllowedPolicy
+}
+
+// grantedActionsCache tracks which actions have been granted to which actors.
+type grantedActionsCache struct {
+	mu      sync.RWMutex
+	granted map[uint]map[int]bool // actorID -> actionID -> granted
+}
+
+// allowedPolicyResults tracks which policies were allowed for each actor.
+type allowedPolicyResults struct {
+	mu            sync.Mutex
+	actorPolicies map[uint]map[uint]bool // actorID -> policyDefaultID -> true
+}
+
+// superPrivilegeActors tracks actors that have been granted super privileges.
+// Actors in actors are super on every database, actors in dbOwners only on the databases listed.
+type superPrivilegeActors struct {
+	mu       sync.RWMutex
+	actors   map[uint]bool
+	dbOwners map[uint]map[int]bool // actorID -> dbmgtID -> super
+}
+
+func newGrantedActionsCache() *grantedActionsCache {
+	return &grantedActionsCache{
+		granted: make(map[uint]map[int]bool),
+	}
+}
+
+func (c *grantedActionsCache) markGranted(actorID uint, actionID int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.granted[actorID] == nil {
+		c.granted[actorID] = make(map[int]bool)
+	}
+	c.granted[actorID][actionID] = true
+}
+
+func (c *grantedActionsCache) isGranted(actorID uint, actionID int) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if actions, ok := c.granted[actorID]; ok {
+		return actions[actionID]
+	}
+	return false
+}
+
+func newAllowedPolicyResults() *allowedPolicyResults {
+	return &allowedPolicyResults{
+		actorPolicies: make(map[uint]map[uint]bool),
+	}
+}
+
+func (a *allowedPolicyResults) recordAllowed(actorID uint, policyDefaultID uint) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.actorPolicies[actorID] == nil {
+		a.actorPolicies[actorID] = make(map[uint]bool)
+	}
+	a.actorPolicies[actorID][policyDefaultID] = true
+}
+
+func newSuperPrivilegeActors() *superPrivilegeActors {
+	return &superPrivilegeActors{
+		actors:   make(map[uint]bool),
+		dbOwners: make(map[uint]map[int]bool),
+	}
+}
+
+func (s *superPrivilegeActors) markSuperPrivilege(actorID uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.actors[actorID] = true
+}
+
+func (s *superPrivilegeActors) markDatabaseSuper(actorID uint, dbmgtID int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.dbOwners[actorID] == nil {
+		s.dbOwners[actorID] = make(map[int]bool)
+	}
+	s.dbOwners[actorID][dbmgtID] = true
+}
+
+func (s *superPrivilegeActors) hasSuperPrivilege(actorID uint) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.actors[actorID]
+}
+
+// hasSuperPrivilegeOn reports whether the actor is super on every database or on dbmgtID.
+// Queries spanning all databases (dbmgtID=-1) are only short-circuited for actors super everywhere.
+func (s *superPrivilegeActors) hasSuperPrivilegeOn(actorID uint, dbmgtID int) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.actors[actorID] {
+		return true
+	}
+	return dbmgtID != -1 && s.dbOwners[actorID][dbmgtID]
+}
+
+// groupListPolicy maps a list policy ID to its set of required policy default IDs.
+type groupListPolicy struct {
+	listPolicyID     uint
+	policyDefaultIDs map[uint]bool
+}
+
+// CreatePolicies creates policies from the catalog rows of a finished discovery job.
+// Loads the rows into an in-memory session and evaluates DBPolicyDefault queries in three passes:
+// Pass 1: Super privileges - object_id=-1, dbmgt_id=-1 (or one database for DatabaseSuperPolicyID)
+// Pass 2: Action-wide privileges (DBGroupListPolicies) - object_id=-1, dbmgt_id=-1
+// Pass 3: Object-specific privileges - normal policies with specific objects/databases
+func (d *CatalogDialect) CreatePolicies(jobID string, sessionContext *PrivilegeSessionJobContext, privilegeData []QueryResult) (int, error) {
+	// Idempotency check: prevent duplicate processing from notification + polling
+	if _, alreadyProcessed := processedCatalogJobs.LoadOrStore(jobID, true); alreadyProcessed {
+		logger.Warnf("%s job %s already processed, skipping duplicate execution", d.Name, jobID)
+		return 0, nil
+	}
+
+	// Skip all processing when job was cancelled before its results arrived
+	jobMonitor := job.GetJobMonitorService()
+	if err := jobMonitor.CheckCancelled(jobID); err != nil {
+		return 0, err
+	}
+
+	logger.Infof("Creating %s policies from privilege data for job %s", d.Name, jobID)
+
+	session, err := d.NewSession(context.Background(), sessionContext.SessionID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create %s privilege session: %w", d.DBType, err)
+	}
+	defer session.Close()
+
+	if err := d.LoadResults(session, privilegeData); err != nil {
+		return 0, fmt.Errorf("failed to load %s privilege data: %w", d.DBType, err)
+	}
+
+	// Create query log file for tracking (only if enabled)
+	var logFile *os.File
+	if config.Current().EnableMySQLPrivilegeQueryLogging {
+		logFileName := fmt.Sprintf("%s_privilege_queries_%s_%s.log", d.DBType, sessionContext.SessionID, time.Now().Format("20060102_150405"))
+		logFilePath := filepath.Join(config.Cfg.DBFWebTempDir, logFileName)
+		logFile, err = os.OpenFile(logFilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			logger.Warnf("Failed to create %s query log file %s: %v", d.DBType, logFilePath, err)
+			logFile = nil
+		} else {
+			defer logFile.Close()
+			logger.Infof("%s query log file created: %s", d.Name, logFilePath)
+			logFile.WriteString(fmt.Sprintf("=== %s Privilege Session Query Log ===\n", d.Name))
+			logFile.WriteString(fmt.Sprintf("Job ID: %s\n", jobID))
+			logFile.WriteString(fmt.Sprintf("Session ID: %s\n", sessionContext.SessionID))
+			logFile.WriteString(fmt.Sprintf("Started: %s\n\n", time.Now().Format("2006-01-02 15:04:05")))
+		}
+	}
+
+	baseRepo := repository.NewBaseRepository()
+	tx := baseRepo.Begin()
+	var txCommitted bool
+	defer func() {
+		if !txCommitted {
+			tx.Rollback()
+		}
+	}()
+
+	databaseTypeID, err := d.ResolveDatabaseTypeID(tx)
+	if err != nil {
+		return 0, err
+	}
+
+	service := NewPolicyEvaluator()
+	templates := d.ClassifyTemplates(tx, service.GetPolicyDefaultsMap(), databaseTypeID)
+	engine := d.newPassEngine(session, service, logFile)
+	totalPolicies := 0
+
+	// PASS 1: Super privileges - grant ALL actions on ALL objects, for every database or one database
+	if len(templates.Super) > 0 {
+		logger.Infof("Processing %s Pass 1: %d super privilege templates", d.Name, len(templates.Super))
+
+		passStart := time.Now()
+		superQueries := d.expandTemplates(templates.Super, sessionContext.DbMgts, sessionContext.DbActorMgts)
+		allowed := engine.superPass(superQueries, jobMonitor.NewStepProgress(jobID, "Pass 1/3: super privilege queries", len(superQueries)))
+		superPolicies := d.createPolicyRecords(tx, sessionContext.CntMgtID, PassSuper, allowed)
+		ObservePass(d.DBType, PassSuper, len(superQueries), superPolicies, passStart)
+		totalPolicies += superPolicies
+		logger.Infof("%s Pass 1 completed: %d super policies created", d.Name, superPolicies)
+	}
+
+	// Uncommitted policies from earlier passes are rolled back by the deferred tx.Rollback
+	if err := jobMonitor.CheckCancelled(jobID); err != nil {
+		return 0, err
+	}
+
+	// PASS 2: Action-wide privileges - grant specific action on ALL objects for ALL databases
+	if len(templates.ActionWide) > 0 {
+		logger.Infof("Processing %s Pass 2: %d action-wide privilege templates", d.Name, len(templates.ActionWide))
+
+		passStart := time.Now()
+		actionWideQueries := d.expandTemplates(templates.ActionWide, sessionContext.DbMgts, sessionContext.DbActorMgts)
+		allowed := engine.actionWidePass(actionWideQueries, jobMonitor.NewStepProgress(jobID, "Pass 2/3: action-wide queries", len(actionWideQueries)))
+		actionPolicies := d.createPolicyRecords(tx, sessionContext.CntMgtID, PassActionWide, allowed)
+		ObservePass(d.DBType, PassActionWide, len(actionWideQueries), actionPolicies, passStart)
+		totalPolicies += actionPolicies
+		logger.Infof("%s Pass 2 completed: %d action-wide policies created", d.Name, actionPolicies)
+	}
+
+	if err := jobMonitor.CheckCancelled(jobID); err != nil {
+		return 0, err
+	}
+
+	// PASS 3: Object-specific privileges - skip if action already granted in Pass 2
+	if len(templates.ObjectSpecific) > 0 {
+		logger.Infof("Processing %s Pass 3: %d object-specific privilege templates", d.Name, len(templates.ObjectSpecific))
+
+		passStart := time.Now()
+		objects := d.loadTemplateObjects(tx, sessionContext.DbMgts, templates.ObjectSpecific)
+		objectQueries := d.expandObjectTemplates(templates.ObjectSpecific, sessionContext.DbMgts, sessionContext.DbActorMgts, objects)
+		allowed := engine.objectSpecificPass(objectQueries, jobMonitor.NewStepProgress(jobID, "Pass 3/3: object-specific queries", len(objectQueries)))
+		objectPolicies := d.createPolicyRecords(tx, sessionContext.CntMgtID, PassObjectSpecific, allowed)
+		ObservePass(d.DBType, PassObjectSpecific, len(objectQueries), objectPolicies, passStart)
+		totalPolicies += objectPolicies
+		logger.Infof("%s Pass 3 completed: %d object-specific policies created from %d queries", d.Name, objectPolicies, len(objectQueries))
+	}
+
+	logger.Infof("Assigning %s actors to groups based on allowed query results from %d policies", d.Name, totalPolicies)
+	if err := d.assignActorsToGroups(tx, sessionContext.CntMgtID, databaseTypeID, engine.allowed, engine.super); err != nil {
+		logger.Warnf("Failed to assign %s actors to groups: %v", d.DBType, err)
+	}
+
+	CapturePrivilegeSnapshot(tx, sessionContext.CntMgtID, jobID, d.DBType, privilegeData, sessionContext.DbActorMgts, engine.allowed.actorPolicies)
+
+	// Last check before commit - cancellation during Pass 3 or group assignment rolls back everything
+	if err := jobMonitor.CheckCancelled(jobID); err != nil {
+		return 0, err
+	}
+
+	// Committed with the policies so a restart does not evaluate and insert them again
+	if err := jobMonitor.MarkCallbackCommitted(tx, jobID); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return 0, fmt.Errorf("failed to commit %s policies: %v", d.DBType, err)
+	}
+	txCommitted = true
+
+	logger.Infof("Successfully created %d %s policies for job %s (super+action-wide+object-specific)", totalPolicies, d.Name, jobID)
+
+	go func(jID string) {
+		if err := utils.ExportDBFPolicy(); err != nil {
+			logger.Warnf("Failed to export DBF policy rules for %s job %s: %v", d.Name, jID, err)
+		} else {
+			logger.Infof("Successfully exported DBF policy rules for %s job %s", d.Name, jID)
+		}
+	}(jobID)
+
+	return totalPolicies, nil
+}
+
+// EvaluatePasses runs the three discovery passes for actors against session without writing policies.
+// objects are the discovered objects SqlGetSpecific templates are expanded with.
+func (d *CatalogDialect) EvaluatePasses(
+	session *PrivilegeSession,
+	service PolicyEvaluator,
+	templates CatalogTemplates,
+	dbMgts []models.DBMgt,
+	actors []models.DBActorMgt,
+	objects []models.DBObjectMgt,
+) CatalogPassResults {
+	engine := d.newPassEngine(session, service, nil)
+	return CatalogPassResults{
+		Super:      engine.superPass(d.expandTemplates(templates.Super, dbMgts, actors), nil),
+		ActionWide: engine.actionWidePass(d.expandTemplates(templates.ActionWide, dbMgts, actors), nil),
+		ObjectSpecific: engine.objectSpecificPass(
+			d.expandObjectTemplates(templates.ObjectSpecific, dbMgts, actors, groupObjects(objects)), nil),
+	}
+}
+
+// ResolveDatabaseTypeID looks up the dbtype row used for the dialect's group list policies and groups.
+func (d *CatalogDialect) ResolveDatabaseTypeID(tx *gorm.DB) (uint, error) {
+	dbTypes, err := repository.NewDBTypeRepository().GetAll(tx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load database types: %w", err)
+	}
+
+	for _, dbType := range dbTypes {
+		for _, name := range d.TypeNames {
+			if strings.EqualFold(dbType.Name, name) {
+				return dbType.ID, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("database type %v not found in dbtype table", d.TypeNames)
+}
+
+// parseGroupListPolicyIDs extracts policy default IDs from dbgroup_listpolicies.dbpolicydefault_id.
+// Accepts a JSON array "[1,2,3]", a single number "123" or a comma-separated list "1,2,3".
+func parseGroupListPolicyIDs(rawValue string) map[uint]bool {
+	policyIDs := make(map[uint]bool)
+
+	var ids []uint
+	if err := json.Unmarshal([]byte(rawValue), &ids); err == nil {
+		for _, id := range ids {
+			policyIDs[id] = true
+		}
+		return policyIDs
+	}
+
+	var singleID uint
+	if err := json.Unmarshal([]byte(rawValue), &singleID); err == nil {
+		policyIDs[singleID] = true
+		return policyIDs
+	}
+
+	for _, part := range strings.Split(rawValue, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		var id uint
+		if _, err := fmt.Sscanf(part, "%d", &id); err == nil {
+			policyIDs[id] = true
+		}
+	}
+	return policyIDs
+}
+
+// loadGroupListPolicies loads active group list policies of a database type with their parsed policy default IDs.
+func loadGroupListPolicies(tx *gorm.DB, databaseTypeID uint) ([]groupListPolicy, error) {
+	groupListPolicyRepo := repository.NewDBGroupListPoliciesRepository()
+	allGroupListPolicies, err := groupListPolicyRepo.GetActiveByDatabaseType(tx, databaseTypeID)
+	if err != nil {
+		return nil, err
+	}
+
+	groupListPolicies := []groupListPolicy{}
+	for _, glp := range allGroupListPolicies {
+		if glp.DBPolicyDefaultID == nil || *glp.DBPolicyDefaultID == "" {
+			continue
+		}
+
+		policyIDs := parseGroupListPolicyIDs(*glp.DBPolicyDefaultID)
+		if len(policyIDs) > 0 {
+			groupListPolicies = append(groupListPolicies, groupListPolicy{
+				listPolicyID:     glp.ID,
+				policyDefaultIDs: policyIDs,
+			})
+		}
+	}
+	return groupListPolicies, nil
+}
+
+// ClassifyTemplates categorizes the dialect's policy templates into the three execution tiers.
+// Only templates that query the dialect's catalog tables are considered.
+func (d *CatalogDialect) ClassifyTemplates(tx *gorm.DB, allPolicies map[uint]models.DBPolicyDefault, databaseTypeID uint) CatalogTemplates {
+	groupListPolicies, err := loadGroupListPolicies(tx, databaseTypeID)
+	if err != nil {
+		logger.Warnf("Failed to load %s DBGroupListPolicies: %v", d.Name, err)
+	}
+
+	actionWidePolicyIDs := make(map[uint]bool)
+	for _, glp := range groupListPolicies {
+		for id := range glp.policyDefaultIDs {
+			actionWidePolicyIDs[id] = true
+		}
+	}
+
+	logger.Infof("Total %s action-wide policy IDs from DBGroupListPolicies: %d", d.Name, len(actionWidePolicyIDs))
+
+	templates := CatalogTemplates{
+		Super:          []models.DBPolicyDefault{},
+		ActionWide:     []models.DBPolicyDefault{},
+		ObjectSpecific: []models.DBPolicyDefault{},
+	}
+	for id, policy := range allPolicies {
+		if !d.isTemplate(policy) {
+			continue
+		}
+
+		switch {
+		case id == d.SuperPolicyID, d.DatabaseSuperPolicyID != 0 && id == d.DatabaseSuperPolicyID:
+			templates.Super = append(templates.Super, policy)
+		case actionWidePolicyIDs[id]:
+			templates.ActionWide = append(templates.ActionWide, policy)
+		default:
+			templates.ObjectSpecific = append(templates.ObjectSpecific, policy)
+		}
+	}
+
+	logger.Debugf("%s policy classification: super=%d, action-wide=%d, object-specific=%d",
+		d.Name, len(templates.Super), len(templates.ActionWide), len(templates.ObjectSpecific))
+
+	return templates
+}
+
+// expandTemplates builds SQL queries from SqlGet templates with actor-centric approach.
+// Templates with ${dbmgt.dbname} are expanded per database, others run once per actor with dbmgt_id=-1.
+func (d *CatalogDialect) expandTemplates(
+	policyDefaults []models.DBPolicyDefault,
+	allDatabases []models.DBMgt,
+	actors []models.DBActorMgt,
+) map[string]PolicyInput {
+	sqlFinalMap := make(map[string]PolicyInput)
+
+	for _, policydf := range policyDefaults {
+		if policydf.SqlGet == "" {
+			continue
+		}
+
+		sqlBytes, err := hex.DecodeString(policydf.SqlGet)
+		if err != nil {
+			logger.Warnf("Failed to decode SqlGet for %s policy_default_id=%d: %v", d.DBType, policydf.ID, err)
+			continue
+		}
+		rawSQL := string(sqlBytes)
+		hasDatabaseVar := strings.Contains(rawSQL, "${dbmgt.dbname}")
+
+		for _, actor := range actors {
+			finalSQL := strings.ReplaceAll(rawSQL, "${dbactormgt.dbuser}", d.Escape(actor.DBUser))
+			finalSQL = strings.ReplaceAll(finalSQL, "${dbactormgt.ip_address}", actor.IPAddress)
+			finalSQL = strings.ReplaceAll(finalSQL, "${dbobjectmgt.objectname}", "*")
+
+			if !hasDatabaseVar {
+				uniqueKey := fmt.Sprintf("Actor:%d_PolicyDf:%d_General", actor.ID, policydf.ID)
+				sqlFinalMap[uniqueKey] = PolicyInput{
+					Policydf: policydf,
+					ActorId:  actor.ID,
+					ObjectId: -1,
+					DbmgtId:  -1,
+					FinalSQL: finalSQL,
+				}
+				continue
+			}
+
+			for _, dbmgt := range allDatabases {
+				uniqueKey := fmt.Sprintf("Actor:%d_PolicyDf:%d_DbMgt:%d_General", actor.ID, policydf.ID, dbmgt.ID)
+				sqlFinalMap[uniqueKey] = PolicyInput{
+					Policydf: policydf,
+					ActorId:  actor.ID,
+					ObjectId: -1,
+					DbmgtId:  utils.MustUintToInt(dbmgt.ID),
+					FinalSQL: strings.ReplaceAll(finalSQL, "${dbmgt.dbname}", d.Escape(dbmgt.DbName)),
+				}
+			}
+		}
+	}
+
+	return sqlFinalMap
+}
+
+// expandSpecificTemplates builds SQL queries from SqlGetSpecific templates.
+// Substitutes ${dbobjectmgt.objectname} with each discovered object of the template's object type.
+func (d *CatalogDialect) expandSpecificTemplates(
+	policyDefaults []models.DBPolicyDefault,
+	allDatabases []models.DBMgt,
+	actors []models.DBActorMgt,
+	objectsByKey map[string][]models.DBObjectMgt,
+) map[string]PolicyInput {
+	sqlFinalMap := make(map[string]PolicyInput)
+
+	for _, policydf := range policyDefaults {
+		if policydf.SqlGetSpecific == "" {
+			continue
+		}
+
+		sqlBytes, err := hex.DecodeString(policydf.SqlGetSpecific)
+		if err != nil {
+			logger.Warnf("Failed to decode SqlGetSpecific for %s policy_default_id=%d: %v", d.DBType, policydf.ID, err)
+			continue
+		}
+		rawSQL := string(sqlBytes)
+
+		if !strings.Contains(rawSQL, "${dbobjectmgt.objectname}") {
+			// Without an object variable the template is equivalent to the general query built from SqlGet
+			continue
+		}
+
+		for _, actor := range actors {
+			actorSQL := strings.ReplaceAll(rawSQL, "${dbactormgt.dbuser}", d.Escape(actor.DBUser))
+			actorSQL = strings.ReplaceAll(actorSQL, "${dbactormgt.ip_address}", actor.IPAddress)
+
+			for _, dbmgt := range allDatabases {
+				dbSQL := strings.ReplaceAll(actorSQL, "${dbmgt.dbname}", d.Escape(dbmgt.DbName))
+
+				key := fmt.Sprintf("%d:%d", policydf.ObjectId, dbmgt.ID)
+				for _, object := range objectsByKey[key] {
+					uniqueKey := fmt.Sprintf("Actor:%d_PolicyDf:%d_DbMgt:%d_Object:%d", actor.ID, policydf.ID, dbmgt.ID, object.ID)
+					sqlFinalMap[uniqueKey] = PolicyInput{
+						Policydf: policydf,
+						ActorId:  actor.ID,
+						ObjectId: utils.MustUintToInt(object.ID),
+						DbmgtId:  utils.MustUintToInt(dbmgt.ID),
+						FinalSQL: strings.ReplaceAll(dbSQL, "${dbobjectmgt.objectname}", d.Escape(object.ObjectName)),
+					}
+				}
+			}
+		}
+	}
+
+	return sqlFinalMap
+}
+
+// expandObjectTemplates builds the Pass 3 queries: general queries from SqlGet plus one query per object from SqlGetSpecific.
+func (d *CatalogDialect) expandObjectTemplates(
+	policyDefaults []models.DBPolicyDefault,
+	allDatabases []models.DBMgt,
+	actors []models.DBActorMgt,
+	objectsByKey map[string][]models.DBObjectMgt,
+) map[string]PolicyInput {
+	queries := d.expandTemplates(policyDefaults, allDatabases, actors)
+	for k, v := range d.expandSpecificTemplates(policyDefaults, allDatabases, actors, objectsByKey) {
+		queries[k] = v
+	}
+	return queries
+}
+
+// loadTemplateObjects loads all objects referenced by object-specific templates in one query.
+// Prevents N+1 queries when building object-specific queries.
+func (d *CatalogDialect) loadTemplateObjects(tx *gorm.DB, dbMgts []models.DBMgt, policies []models.DBPolicyDefault) map[string][]models.DBObjectMgt {
+	objectIDs := make(map[int]bool)
+	for _, policy := range policies {
+		if policy.ObjectId > 0 && policy.SqlGetSpecific != "" {
+			objectIDs[policy.ObjectId] = true
+		}
+	}
+	if len(objectIDs) == 0 || len(dbMgts) == 0 {
+		return map[string][]models.DBObjectMgt{}
+	}
+
+	dbMgtIDs := make([]uint, len(dbMgts))
+	for i, dbMgt := range dbMgts {
+		dbMgtIDs[i] = dbMgt.ID
+	}
+	objectIDList := make([]int, 0, len(objectIDs))
+	for id := range objectIDs {
+		objectIDList = append(objectIDList, id)
+	}
+
+	var allObjects []models.DBObjectMgt
+	if err := tx.Where("dbmgt_id IN ? AND dbobject_id IN ?", dbMgtIDs, objectIDList).Find(&allObjects).Error; err != nil {
+		logger.Warnf("Failed to load %s DBObjectMgt records for cache: %v", d.Name, err)
+		return map[string][]models.DBObjectMgt{}
+	}
+
+	objectsByKey := groupObjects(allObjects)
+	logger.Debugf("%s query cache built: %d object keys from %d objects", d.Name, len(objectsByKey), len(allObjects))
+	return objectsByKey
+}
+
+// groupObjects keys objects by "objectId:dbMgtId".
+func groupObjects(objects []models.DBObjectMgt) map[string][]models.DBObjectMgt {
+	objectsByKey := make(map[string][]models.DBObjectMgt)
+	for _, obj := range objects {
+		key := fmt.Sprintf("%d:%d", obj.ObjectId, obj.DBMgt)
+		objectsByKey[key] = append(objectsByKey[key], obj)
+	}
+	return objectsByKey
+}
+
+// passEngine evaluates the discovery passes of one run, carrying super actors and granted actions between passes.
+type passEngine struct {
+	dialect *CatalogDialect
+	session *PrivilegeSession
+	service PolicyEvaluator
+	logFile *os.File
+	granted *grantedActionsCache
+	allowed *allowedPolicyResults
+	super   *superPrivilegeActors
+}
+
+func (d *CatalogDialect) newPassEngine(session *PrivilegeSession, service PolicyEvaluator, logFile *os.File) *passEngine {
+	return &passEngine{
+		dialect: d,
+		session: session,
+		service: service,
+		logFile: logFile,
+		granted: newGrantedActionsCache(),
+		allowed: newAllowedPolicyResults(),
+		super:   newSuperPrivilegeActors(),
+	}
+}
+
+// passQueryResult is the outcome of one policy template query against the in-memory session.
+type passQueryResult struct {
+	uniqueKey   string
+	policyData  PolicyInput
+	resultValue string
+	err         error
+}
+
+// run executes policy queries concurrently against the in-memory session and returns the allowed ones.
+// Results are collected before logging to avoid I/O contention in goroutines; failed queries are dropped.
+func (e *passEngine) run(queries map[string]PolicyInput, passName string, progress *job.StepProgress) []passQueryResult {
+	maxConcurrent := config.GetPrivilegeQueryConcurrency()
+	logger.Debugf("Using privilege query concurrency: %d for %d %s %s queries", maxConcurrent, len(queries), e.dialect.Name, passName)
+	semaphore := make(chan struct{}, maxConcurrent)
+	resultsChan := make(chan passQueryResult, len(queries))
+
+	for uniqueKey, policyData := range queries {
+		go func(key string, input PolicyInput) {
+			defer func() {
+				if r := recover(); r != nil {
+					logger.Errorf("Panic in %s %s goroutine for key %s: %v", e.dialect.Name, passName, key, r)
+					resultsChan <- passQueryResult{uniqueKey: key, policyData: input, err: fmt.Errorf("panic: %v", r)}
+				}
+			}()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			result, err := e.dialect.Execute(e.session, input.FinalSQL)
+			if err != nil {
+				resultsChan <- passQueryResult{uniqueKey: key, policyData: input, err: err}
+				return
+			}
+			resultsChan <- passQueryResult{uniqueKey: key, policyData: input, resultValue: e.service.ExtractResultValue(result)}
+		}(uniqueKey, policyData)
+	}
+
+	allowedResults := make([]passQueryResult, 0, len(queries))
+	for i := 0; i < len(queries); i++ {
+		result := <-resultsChan
+		progress.Update(i + 1)
+		writeQueryToLogFile(e.logFile, passName, result.uniqueKey, result.policyData.FinalSQL)
+		if result.err != nil {
+			logger.Debugf("%s %s query failed for key=%s: %v", e.dialect.Name, passName, result.uniqueKey, result.err)
+			continue
+		}
+		policydf := result.policyData.Policydf
+		if !e.service.IsPolicyAllowed(result.resultValue, policydf.SqlGetAllow, policydf.SqlGetDeny) {
+			continue
+		}
+		allowedResults = append(allowedResults, result)
+	}
+	return allowedResults
+}
+
+// superPass executes Pass 1 super privilege queries.
+// SuperPolicyID policies are actor-wide; DatabaseSuperPolicyID policies are scoped to the database the query ran for.
+func (e *passEngine) superPass(queries map[string]PolicyInput, progress *job.StepProgress) []AllowedPolicy {
+	allowed := []AllowedPolicy{}
+	for _, result := range e.run(queries, "PASS-1-SUPER", progress) {
+		policydf := result.policyData.Policydf
+		dbmgtID := -1
+		if e.dialect.DatabaseSuperPolicyID != 0 && policydf.ID == e.dialect.DatabaseSuperPolicyID {
+			if result.policyData.DbmgtId == -1 {
+				logger.Warnf("%s database super template %d has no ${dbmgt.dbname} variable - skipping key=%s", e.dialect.Name, policydf.ID, result.uniqueKey)
+				continue
+			}
+			dbmgtID = result.policyData.DbmgtId
+			e.super.markDatabaseSuper(result.policyData.ActorId, dbmgtID)
+		} else {
+			e.super.markSuperPrivilege(result.policyData.ActorId)
+		}
+
+		e.allowed.recordAllowed(result.policyData.ActorId, policydf.ID)
+		allowed = append(allowed, AllowedPolicy{
+			Key:             result.uniqueKey,
+			ActorID:         result.policyData.ActorId,
+			PolicyDefaultID: policydf.ID,
+			DbMgtID:         dbmgtID,
+			ObjectID:        -1,
+		})
+	}
+
+	logger.Infof("%s PASS-1 completed: %d actors super on all databases, %d actors super on single databases",
+		e.dialect.Name, len(e.super.actors), len(e.super.dbOwners))
+	return allowed
+}
+
+// actionWidePass executes Pass 2 action-wide queries, skipping actors super on the query's database.
+func (e *passEngine) actionWidePass(queries map[string]PolicyInput, progress *job.StepProgress) []AllowedPolicy {
+	filteredQueries := make(map[string]PolicyInput)
+	for uniqueKey, input := range queries {
+		if e.super.hasSuperPrivilegeOn(input.ActorId, input.DbmgtId) {
+			continue
+		}
+		filteredQueries[uniqueKey] = input
+	}
+
+	allowed := []AllowedPolicy{}
+	for _, result := range e.run(filteredQueries, "PASS-2-ACTION", progress) {
+		policydf := result.policyData.Policydf
+		e.allowed.recordAllowed(result.policyData.ActorId, policydf.ID)
+		e.granted.markGranted(result.policyData.ActorId, policydf.ActionId)
+		allowed = append(allowed, AllowedPolicy{
+			Key:             result.uniqueKey,
+			ActorID:         result.policyData.ActorId,
+			PolicyDefaultID: policydf.ID,
+			DbMgtID:         -1,
+			ObjectID:        -1,
+		})
+	}
+	return allowed
+}
+
+// objectSpecificPass executes Pass 3 object-specific queries.
+// Skips queries if the actor is super on the query's database or the action was already granted in Pass 2.
+func (e *passEngine) objectSpecificPass(queries map[string]PolicyInput, progress *job.StepProgress) []AllowedPolicy {
+	filteredQueries := make(map[string]PolicyInput)
+	for uniqueKey, input := range queries {
+		if e.super.hasSuperPrivilegeOn(input.ActorId, input.DbmgtId) {
+			continue
+		}
+		if e.granted.isGranted(input.ActorId, input.Policydf.ActionId) {
+			continue
+		}
+		filteredQueries[uniqueKey] = input
+	}
+
+	allowed := []AllowedPolicy{}
+	for _, result := range e.run(filteredQueries, "PASS-3-OBJECT", progress) {
+		policydf := result.policyData.Policydf
+		e.allowed.recordAllowed(result.policyData.ActorId, policydf.ID)
+		allowed = append(allowed, AllowedPolicy{
+			Key:             result.uniqueKey,
+			ActorID:         result.policyData.ActorId,
+			PolicyDefaultID: policydf.ID,
+			DbMgtID:         result.policyData.DbmgtId,
+			ObjectID:        result.policyData.ObjectId,
+		})
+	}
+	return allowed
+}
+
+// createPolicyRecords creates DBPolicy records for the policies allowed by one pass and returns how many were inserted.
+func (d *CatalogDialect) createPolicyRecords(tx *gorm.DB, cntMgtID uint, pass string, allowed []AllowedPolicy) int {
+	policiesCreated := 0
+	for _, policy := range allowed {
+		created, err := createPolicyRecord(tx, cntMgtID, policy)
+		if err != nil {
+			logger.Warnf("Failed to create %s %s policy for key=%s: %v", d.DBType, pass, policy.Key, err)
+			continue
+		}
+		if created {
+			policiesCreated++
+		}
+	}
+	return policiesCreated
+}
+
+// createPolicyRecord creates a DBPolicy record unless an identical one already exists.
+// Returns true when a new record was inserted.
+func createPolicyRecord(tx *gorm.DB, cntMgtID uint, allowed AllowedPolicy) (bool, error) {
+	// Check duplicate (silent mode to reduce log noise)
+	var existing models.DBPolicy
+	err := tx.Session(&gorm.Session{Logger: tx.Logger.LogMode(gormlogger.Silent)}).
+		Where("cnt_id=? AND actor_id=? AND object_id=? AND dbmgt_id=? AND dbpolicydefault_id=?",
+			cntMgtID, allowed.ActorID, allowed.ObjectID, allowed.DbMgtID, allowed.PolicyDefaultID).First(&existing).Error
+	if err == nil {
+		return false, nil
+	}
+
+	policy := models.DBPolicy{
+		CntMgt:          cntMgtID,
+		DBPolicyDefault: allowed.PolicyDefaultID,
+		DBMgt:           allowed.DbMgtID,
+		DBActorMgt:      allowed.ActorID,
+		DBObjectMgt:     allowed.ObjectID,
+		Status:          "enabled",
+		Description:     "Auto-inserted by Group Policy",
+	}
+	if err := tx.Create(&policy).Error; err != nil {
+		return false, fmt.Errorf("failed to create policy: %w", err)
+	}
+	return true, nil
+}
+
+// writeQueryToLogFile appends a pass query to the query log file.
+func writeQueryToLogFile(logFile *os.File, passName, uniqueKey, query string) {
+	if logFile == nil {
+		return
+	}
+
+	timestamp := time.Now().Format("2006-01-02 15:04:05.000")
+	logEntry := fmt.Sprintf("[%s] [%s] [%s]\n%s\n\n", timestamp, passName, uniqueKey, query)
+
+	if _, err := logFile.WriteString(logEntry); err != nil {
+		logger.Warnf("Failed to write privilege query to log file: %v", err)
+	}
+}
+
+// isExactMatch checks if actor's policies are a superset of the group's required policies.
+func isExactMatch(actorPolicies map[uint]bool, groupPolicies map[uint]bool) bool {
+	for requiredID := range groupPolicies {
+		if !actorPolicies[requiredID] {
+			return false
+		}
+	}
+	return true
+}
+
+// assignActorsToGroups assigns actors to groups based on privilege evaluation results.
+// Actors super on every database go to SuperGroupID; other actors join every group whose required
+// dbgroup_listpolicies they fully satisfy.
+func (d *CatalogDialect) assignActorsToGroups(tx *gorm.DB, cntMgtID uint, databaseTypeID uint, allowedResults *allowedPolicyResults, superPrivActors *superPrivilegeActors) error {
+	logger.Infof("Assigning %s actors to groups for cnt_id=%d", d.Name, cntMgtID)
+
+	groupListPolicies, err := loadGroupListPolicies(tx, databaseTypeID)
+	if err != nil {
+		logger.Warnf("Failed to load %s DBGroupListPolicies for database_type_id=%d: %v", d.Name, databaseTypeID, err)
+		return nil
+	}
+
+	// Build group → required listpolicy IDs map from dbpolicy_groups
+	var policyGroupRows []struct {
+		GroupID        uint `gorm:"column:group_id"`
+		ListPoliciesID uint `gorm:"column:dbgroup_listpolicies_id"`
+	}
+	err = tx.Table("dbpolicy_groups pg").
+		Select("pg.group_id, pg.dbgroup_listpolicies_id").
+		Joins("INNER JOIN dbgroupmgt g ON pg.group_id = g.id").
+		Where("pg.is_active = ? AND g.is_active = ? AND g.database_type_id = ?", true, true, databaseTypeID).
+		Order("pg.group_id ASC").
+		Find(&policyGroupRows).Error
+	if err != nil {
+		logger.Warnf("Failed to load %s dbpolicy_groups: %v", d.Name, err)
+		return nil
+	}
+
+	groupRequirements := make(map[uint]map[uint]bool)
+	for _, row := range policyGroupRows {
+		if groupRequirements[row.GroupID] == nil {
+			groupRequirements[row.GroupID] = make(map[uint]bool)
+		}
+		groupRequirements[row.GroupID][row.ListPoliciesID] = true
+	}
+
+	actorGroupsRepo := repository.NewDBActorGroupsRepository()
+	assignedCount := 0
+
+	for actorID, actorPolicyIDs := range allowedResults.actorPolicies {
+		if len(actorPolicyIDs) == 0 {
+			continue
+		}
+
+		var candidateGroupIDs []uint
+		if actorPolicyIDs[d.SuperPolicyID] || superPrivActors.hasSuperPrivilege(actorID) {
+			candidateGroupIDs = []uint{d.SuperGroupID}
+		} else {
+			// Level 1: dbgroup_listpolicies the actor fully satisfies
+			satisfied := make(map[uint]bool)
+			for _, glp := range groupListPolicies {
+				if isExactMatch(actorPolicyIDs, glp.policyDefaultIDs) {
+					satisfied[glp.listPolicyID] = true
+				}
+			}
+			if len(satisfied) == 0 {
+				logger.Debugf("No satisfied listpolicies for %s actor %d - skipping", d.Name, actorID)
+				continue
+			}
+
+			// Level 2: groups where the actor satisfies ALL required listpolicies
+			for groupID, required := range groupRequirements {
+				if isExactMatch(satisfied, required) {
+					candidateGroupIDs = append(candidateGroupIDs, groupID)
+				}
+			}
+		}
+
+		existingGroups, _ := actorGroupsRepo.GetActiveGroupsByActorID(tx, actorID)
+		existingGroupIDs := make(map[uint]bool)
+		for _, ag := range existingGroups {
+			existingGroupIDs[ag.GroupID] = true
+		}
+
+		for _, groupID := range candidateGroupIDs {
+			if existingGroupIDs[groupID] {
+				logger.Debugf("%s actor %d already assigned to group %d", d.Name, actorID, groupID)
+				continue
+			}
+
+			actorGroup := &models.DBActorGroups{
+				ActorID:   actorID,
+				GroupID:   groupID,
+				ValidFrom: time.Now(),
+				IsActive:  true,
+			}
+			if err := actorGroupsRepo.Create(tx, actorGroup); err != nil {
+				logger.Warnf("Failed to assign %s actor %d to group %d: %v", d.Name, actorID, groupID, err)
+				continue
+			}
+			logger.Infof("Assigned %s actor %d to group %d", d.Name, actorID, groupID)
+			assignedCount++
+		}
+	}
+
+	logger.Infof("%s group assignment completed: %d actor-group assignments created", d.Name, assignedCount)
+	return nil
+}
@@ -0,0 +1,217 @@
+package privilege
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"dbfartifactapi/config"
+	"dbfartifactapi/models"
+	"dbfartifactapi/pkg/logger"
+	"dbfartifactapi/repository"
+	"dbfartifactapi/services/agent"
+	"dbfartifactapi/services/job"
+)
+
+// NewCompletionHandler creates the callback for privilege session jobs of d.
+// Handles both notification-based and VeloArtifact polling completion flows.
+func (d *CatalogDialect) NewCompletionHandler(agentExec agent.AgentExecutor) job.JobCompletionCallback {
+	return func(jobID string, jobInfo *job.JobInfo, statusResp *job.StatusResponse) error {
+		logger.Infof("Processing %s privilege session completion for job %s, status: %s", d.Name, jobID, statusResp.Status)
+
+		contextData, ok := jobInfo.ContextData[d.ContextKey]
+		if !ok {
+			return fmt.Errorf("missing %s privilege session context data for job %s", d.DBType, jobID)
+		}
+
+		if statusResp.Status == "completed" {
+			return d.processResults(agentExec, jobID, contextData, statusResp, jobInfo)
+		}
+
+		logger.Errorf("%s privilege session job %s failed", d.Name, jobID)
+		return fmt.Errorf("%s privilege session job failed: %s", d.DBType, statusResp.Message)
+	}
+}
+
+// processResults processes the results of a privilege data loading job.
+// Routes to notification-based or VeloArtifact polling processing based on context.
+func (d *CatalogDialect) processResults(agentExec agent.AgentExecutor, jobID string, contextData interface{}, statusResp *job.StatusResponse, jobInfo *job.JobInfo) error {
+	logger.Infof("Processing %s privilege session results for job %s - completed: %d, failed: %d",
+		d.Name, jobID, statusResp.Completed, statusResp.Failed)
+
+	sessionContext, ok := contextData.(*PrivilegeSessionJobContext)
+	if !ok {
+		return fmt.Errorf("invalid %s privilege session context data for job %s", d.DBType, jobID)
+	}
+
+	if notificationData, exists := jobInfo.ContextData["notification_data"]; exists {
+		return d.processFromNotification(jobID, sessionContext, notificationData)
+	}
+
+	return d.processFromVeloArtifact(agentExec, jobID, sessionContext)
+}
+
+// processFromNotification handles privilege session processing from a job notification.
+func (d *CatalogDialect) processFromNotification(jobID string, sessionContext *PrivilegeSessionJobContext, notificationData interface{}) error {
+	logger.Infof("Processing %s privilege session from notification for job %s", d.Name, jobID)
+
+	jobMonitor := job.GetJobMonitorService()
+
+	notification, ok := notificationData.(map[string]interface{})
+	if !ok {
+		err := fmt.Errorf("invalid notification data format for %s job %s", d.DBType, jobID)
+		jobMonitor.FailJobAfterProcessing(jobID, err.Error())
+		return err
+	}
+
+	fileName, ok := notification["fileName"].(string)
+	if !ok {
+		err := fmt.Errorf("missing fileName in notification data for %s job %s", d.DBType, jobID)
+		jobMonitor.FailJobAfterProcessing(jobID, err.Error())
+		return err
+	}
+
+	md5Hash, ok := notification["md5Hash"].(string)
+	if !ok {
+		err := fmt.Errorf("missing md5Hash in notification data for %s job %s", d.DBType, jobID)
+		jobMonitor.FailJobAfterProcessing(jobID, err.Error())
+		return err
+	}
+
+	success, ok := notification["success"].(bool)
+	if !ok || !success {
+		err := fmt.Errorf("%s job %s was not successful according to notification", d.DBType, jobID)
+		jobMonitor.FailJobAfterProcessing(jobID, err.Error())
+		return err
+	}
+
+	logger.Infof("Processing notification-based %s privilege data: job_id=%s, file=%s, md5=%s", d.Name, jobID, fileName, md5Hash)
+
+	localFilePath := fmt.Sprintf("%s/%s/%s", config.Cfg.NotificationFileDir, jobID, md5Hash)
+	privilegeData, err := d.parseDataFile(localFilePath)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to parse notification %s privilege data for job %s: %v", d.DBType, jobID, err)
+		jobMonitor.FailJobAfterProcessing(jobID, errMsg)
+		return fmt.Errorf("%s", errMsg)
+	}
+
+	logger.Infof("Successfully parsed %d %s privilege table results from notification for job %s", len(privilegeData), d.Name, jobID)
+
+	return d.complete(jobID, sessionContext, privilegeData)
+}
+
+// processFromVeloArtifact handles privilege session processing via VeloArtifact polling.
+func (d *CatalogDialect) processFromVeloArtifact(agentExec agent.AgentExecutor, jobID string, sessionContext *PrivilegeSessionJobContext) error {
+	logger.Infof("Processing %s privilege session from VeloArtifact polling for job %s", d.Name, jobID)
+
+	jobMonitor := job.GetJobMonitorService()
+
+	ep, err := GetEndpointForJob(jobID, sessionContext.EndpointID)
+	if err != nil {
+		jobMonitor.FailJobAfterProcessing(jobID, err.Error())
+		return err
+	}
+
+	privilegeData, err := RetrieveJobResults(agentExec, jobID, ep)
+	if err != nil {
+		jobMonitor.FailJobAfterProcessing(jobID, err.Error())
+		return err
+	}
+
+	logger.Infof("Successfully retrieved %d %s privilege table results via VeloArtifact for job %s", len(privilegeData), d.Name, jobID)
+
+	return d.complete(jobID, sessionContext, privilegeData)
+}
+
+// complete creates policies from privilege data and marks the job as finished.
+func (d *CatalogDialect) complete(jobID string, sessionContext *PrivilegeSessionJobContext, privilegeData []QueryResult) error {
+	jobMonitor := job.GetJobMonitorService()
+
+	totalPolicies, err := d.CreatePolicies(jobID, sessionContext, privilegeData)
+	if err != nil {
+		jobMonitor.FailJobAfterProcessing(jobID, err.Error())
+		return err
+	}
+
+	successMsg := fmt.Sprintf("%s privilege session completed successfully - created %d policies", d.Name, totalPolicies)
+	if err := jobMonitor.CompleteJobAfterProcessing(jobID, successMsg); err != nil {
+		logger.Errorf("Failed to mark %s job as completed: %v", d.DBType, err)
+	}
+
+	logger.Infof("%s privilege session handler executed successfully for job %s - created %d policies", d.Name, jobID, totalPolicies)
+	return nil
+}
+
+// parseDataFile reads and parses a privilege data results file.
+func (d *CatalogDialect) parseDataFile(filePath string) ([]QueryResult, error) {
+	fileData, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s privilege data file %s: %v", d.DBType, filePath, err)
+	}
+
+	var resultsData []QueryResult
+	if err := json.Unmarshal(fileData, &resultsData); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON from %s file %s: %v", d.DBType, filePath, err)
+	}
+
+	return resultsData, nil
+}
+
+// Explain re-runs the three passes of d for one actor against snapshot rows.
+// Nothing is written: policy defaults that would be allowed are returned with their grant/role path.
+func (d *CatalogDialect) Explain(input ExplainInput, trace ExplainTrace) (*ExplainResult, error) {
+	sessionID := fmt.Sprintf("%s_explain_actor_%d_%d", d.DBType, input.Actor.ID, time.Now().Unix())
+	session, err := d.NewSession(context.Background(), sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s privilege session: %w", d.DBType, err)
+	}
+	defer session.Close()
+
+	if err := d.LoadResults(session, input.Grants); err != nil {
+		return nil, fmt.Errorf("failed to load %s privilege data: %w", d.DBType, err)
+	}
+
+	// Query building reads objects and group list policies only; the transaction is never committed
+	tx := repository.NewBaseRepository().Begin()
+	defer tx.Rollback()
+
+	databaseTypeID, err := d.ResolveDatabaseTypeID(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	service := NewPolicyEvaluator()
+	templates := d.ClassifyTemplates(tx, service.GetPolicyDefaultsMap(), databaseTypeID)
+	actors := []models.DBActorMgt{input.Actor}
+
+	objects := d.loadTemplateObjects(tx, input.DbMgts, templates.ObjectSpecific)
+	passes := ExplainPasses{
+		Super:          d.expandTemplates(templates.Super, input.DbMgts, actors),
+		ActionWide:     d.expandTemplates(templates.ActionWide, input.DbMgts, actors),
+		ObjectSpecific: d.expandObjectTemplates(templates.ObjectSpecific, input.DbMgts, actors, objects),
+	}
+
+	evaluator := ExplainEvaluator{
+		Evaluate: func(in PolicyInput) (string, bool, error) {
+			result, err := d.Execute(session, in.FinalSQL)
+			if err != nil {
+				return "", false, err
+			}
+			value := service.ExtractResultValue(result)
+			return value, service.IsPolicyAllowed(value, in.Policydf.SqlGetAllow, in.Policydf.SqlGetDeny), nil
+		},
+	}
+	if d.DatabaseSuperPolicyID != 0 {
+		// Database super policies only cover the database their query ran for
+		evaluator.SuperScope = func(in PolicyInput) (int, bool) {
+			if in.Policydf.ID != d.DatabaseSuperPolicyID {
+				return -1, true
+			}
+			return in.DbmgtId, in.DbmgtId != -1
+		}
+	}
+
+	return evaluator.Explain(input, passes, trace), nil
+}
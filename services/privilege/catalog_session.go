@@ -0,0 +1,300 @@
+package privilege
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	sqle "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/memory"
+	"github.com/dolthub/go-mysql-server/server"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"dbfartifactapi/config"
+	"dbfartifactapi/models"
+	"dbfartifactapi/pkg/logger"
+)
+
+// CatalogDialect describes a database type whose privileges are discovered from catalog views
+// (PostgreSQL, SQL Server). The catalog rows collected by the agent are loaded into an in-memory
+// session and policy templates are evaluated by the shared three-pass engine; a dialect only
+// supplies its catalog queries, table layout and template rewriting.
+type CatalogDialect struct {
+	// DBType is the lower-case type used for metrics, snapshots, file names and agent commands
+	DBType string
+	// Name is the display name used in logs and job messages
+	Name string
+	// TypeNames are the dbtype.name values accepted for the database type
+	TypeNames []string
+	// JobKind and ContextKey identify privilege session jobs and their context in the job monitor
+	JobKind    string
+	ContextKey string
+	// DatabaseNoun is what one DBMgt is for this database type, e.g. "schemas"
+	DatabaseNoun string
+
+	// SuperPolicyID is evaluated in Pass 1 and makes an actor super on every database
+	SuperPolicyID uint
+	// DatabaseSuperPolicyID, when set, is evaluated in Pass 1 per database and makes an actor super on that database only
+	DatabaseSuperPolicyID uint
+	// SuperGroupID is the group super actors are assigned to
+	SuperGroupID uint
+
+	// SessionDatabase is the in-memory database holding the catalog tables
+	SessionDatabase string
+	// Tables maps agent query keys to in-memory tables
+	Tables map[string]string
+	// PrimaryKeys lists key columns per table; tables without keys may hold repeated rows
+	PrimaryKeys map[string][]string
+	// TemplateTables are the catalog tables a policy template must reference to be evaluated
+	TemplateTables []string
+	// Columns returns the columns of an in-memory table, in the order of the agent query
+	Columns func(table string) ([]string, error)
+	// Rewrite adapts a policy template to the in-memory session
+	Rewrite func(query string) string
+	// Escape escapes a string literal
+	Escape func(s string) string
+	// BuildQueries builds the catalog queries run by the agent
+	BuildQueries func(actors []models.DBActorMgt, databases []models.DBMgt) (map[string][]string, error)
+}
+
+// textColumns builds a flexible TEXT schema for an in-memory catalog table.
+// Columns listed in primaryKey are non-nullable key columns.
+func textColumns(tableName string, columns []string, primaryKey ...string) sql.PrimaryKeySchema {
+	keys := make(map[string]bool, len(primaryKey))
+	for _, col := range primaryKey {
+		keys[col] = true
+	}
+
+	schema := make(sql.Schema, 0, len(columns))
+	for _, col := range columns {
+		schema = append(schema, &sql.Column{
+			Name:       col,
+			Type:       types.Text,
+			Source:     tableName,
+			Nullable:   !keys[col],
+			PrimaryKey: keys[col],
+		})
+	}
+	return sql.NewPrimaryKeySchema(schema)
+}
+
+// createTables creates the catalog tables of d with TEXT columns for in-memory privilege analysis.
+func (d *CatalogDialect) createTables(db *memory.Database) error {
+	for _, tableName := range d.Tables {
+		columns, err := d.Columns(tableName)
+		if err != nil {
+			return err
+		}
+
+		schema := textColumns(tableName, columns, d.PrimaryKeys[tableName]...)
+		table := memory.NewTable(db, tableName, schema, db.GetForeignKeyCollection())
+		db.AddTable(tableName, table)
+	}
+
+	logger.Infof("Created all %s privilege tables with flexible TEXT schema", d.Name)
+	return nil
+}
+
+// NewSession creates a temporary in-memory server holding the catalog tables of d.
+// Uses the same go-mysql-server as MySQL discovery, with the dialect's catalog schemas.
+func (d *CatalogDialect) NewSession(ctx context.Context, sessionID string) (*PrivilegeSession, error) {
+	port, err := GetFreePort()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get free port: %w", err)
+	}
+
+	db := memory.NewDatabase(d.SessionDatabase)
+
+	provider := memory.NewDBProvider(db)
+	engine := sqle.NewDefault(provider)
+
+	if err := d.createTables(db); err != nil {
+		return nil, fmt.Errorf("failed to create %s privilege tables: %w", d.DBType, err)
+	}
+
+	serverConfig := server.Config{
+		Protocol: "tcp",
+		Address:  fmt.Sprintf("localhost:%d", port),
+	}
+
+	s, err := server.NewServer(serverConfig, engine, sql.NewContext, memory.NewSessionBuilder(provider), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create server: %w", err)
+	}
+
+	serverCtx, cancel := context.WithCancel(ctx)
+
+	go func() {
+		if err := s.Start(); err != nil {
+			logger.Errorf("%s session server error for session %s: %v", d.Name, sessionID, err)
+		}
+	}()
+
+	go func() {
+		<-serverCtx.Done()
+		if err := s.Close(); err != nil {
+			logger.Warnf("Failed to close %s session server for session %s: %v", d.DBType, sessionID, err)
+		}
+	}()
+
+	readyCtx, readyCancel := context.WithTimeout(ctx, 5*time.Second)
+	defer readyCancel()
+
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-readyCtx.Done():
+			cancel()
+			return nil, fmt.Errorf("%s session server failed to start within timeout for session %s: %w", d.DBType, sessionID, readyCtx.Err())
+		case <-ticker.C:
+			conn, err := net.DialTimeout("tcp", fmt.Sprintf("localhost:%d", port), 100*time.Millisecond)
+			if err == nil {
+				conn.Close()
+				logger.Infof("Started temporary %s privilege server on port %d for session %s", d.Name, port, sessionID)
+				return &PrivilegeSession{
+					Server:    s,
+					Engine:    engine,
+					Provider:  provider,
+					Port:      port,
+					SessionID: sessionID,
+					Cancel:    cancel,
+				}, nil
+			}
+		}
+	}
+}
+
+// Execute runs a policy template against the catalog tables of session.
+func (d *CatalogDialect) Execute(session *PrivilegeSession, sqlTemplate string) ([]map[string]interface{}, error) {
+	return session.ExecuteInDatabase(d.Rewrite(sqlTemplate), d.SessionDatabase, nil)
+}
+
+// LoadResults populates session with the catalog rows collected by the agent, one table per goroutine.
+// Failed or unknown query results are logged and skipped so partial data still produces policies.
+func (d *CatalogDialect) LoadResults(session *PrivilegeSession, results []QueryResult) error {
+	type loadResult struct {
+		tableName string
+		err       error
+		rowCount  int
+	}
+
+	maxConcurrent := config.GetPrivilegeLoadConcurrency()
+	logger.Debugf("Using privilege load concurrency: %d", maxConcurrent)
+	semaphore := make(chan struct{}, maxConcurrent)
+	loadResults := make(chan loadResult, len(results))
+
+	validResults := 0
+	for _, result := range results {
+		// Strip array index from query key (e.g., "sys.database_principals[1]" -> "sys.database_principals")
+		queryKey := result.QueryKey
+		if idx := strings.Index(queryKey, "["); idx != -1 {
+			queryKey = queryKey[:idx]
+		}
+
+		tableName, ok := d.Tables[queryKey]
+		if !ok {
+			logger.Warnf("Unknown %s privilege table key: %s", d.Name, result.QueryKey)
+			continue
+		}
+
+		if result.Status != "success" {
+			logger.Warnf("%s query failed for %s (key=%s): status=%s", d.Name, tableName, result.QueryKey, result.Status)
+			continue
+		}
+
+		validResults++
+		go func(tblName string, rows [][]interface{}) {
+			defer func() {
+				if r := recover(); r != nil {
+					logger.Errorf("Panic in %s privilege load goroutine for table %s: %v", d.Name, tblName, r)
+					loadResults <- loadResult{tableName: tblName, err: fmt.Errorf("panic: %v", r)}
+				}
+			}()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			err := d.insertRows(session, tblName, rows)
+			loadResults <- loadResult{tableName: tblName, err: err, rowCount: len(rows)}
+		}(tableName, result.Result)
+	}
+
+	for i := 0; i < validResults; i++ {
+		result := <-loadResults
+		if result.err != nil {
+			logger.Errorf("Failed to insert into %s: %v", result.tableName, result.err)
+		} else {
+			logger.Debugf("Loaded %d rows into %s", result.rowCount, result.tableName)
+		}
+	}
+
+	logger.Infof("Loaded %s privilege data into temporary server for session %s", d.Name, session.SessionID)
+	return nil
+}
+
+// insertRows converts dbfAgentAPI result rows into INSERT statements for one catalog table.
+func (d *CatalogDialect) insertRows(session *PrivilegeSession, tableName string, rows [][]interface{}) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	columnNames, err := d.Columns(tableName)
+	if err != nil {
+		return err
+	}
+
+	insertedCount := 0
+	for _, row := range rows {
+		if len(row) != len(columnNames) {
+			logger.Warnf("Column count mismatch for %s: expected %d, got %d", tableName, len(columnNames), len(row))
+			continue
+		}
+
+		values := make([]string, len(row))
+		for i, val := range row {
+			if val == nil {
+				values[i] = "NULL"
+			} else {
+				values[i] = fmt.Sprintf("'%s'", d.Escape(fmt.Sprintf("%v", val)))
+			}
+		}
+
+		insertSQL := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+			tableName, strings.Join(columnNames, ", "), strings.Join(values, ", "))
+
+		if _, err := session.ExecuteInDatabase(insertSQL, d.SessionDatabase, nil); err != nil {
+			logger.Warnf("Failed to insert row into %s: %v", tableName, err)
+			continue
+		}
+		insertedCount++
+	}
+
+	logger.Debugf("Inserted %d/%d rows into %s", insertedCount, len(rows), tableName)
+	return nil
+}
+
+// isTemplate checks if a policy template queries the catalog tables of d.
+func (d *CatalogDialect) isTemplate(policy models.DBPolicyDefault) bool {
+	if policy.SqlGet == "" {
+		return false
+	}
+
+	sqlBytes, err := hex.DecodeString(policy.SqlGet)
+	if err != nil {
+		return false
+	}
+
+	sqlLower := strings.ToLower(string(sqlBytes))
+	for _, table := range d.TemplateTables {
+		if strings.Contains(sqlLower, table) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,158 @@
+package privilege
+
+import (
+	"sort"
+
+	"dbfartifactapi/models"
+	"dbfartifactapi/pkg/logger"
+	"dbfartifactapi/services/dto"
+	"dbfartifactapi/services/privilege/snapshot"
+)
+
+// Evaluation passes reported by privilege explain.
+const (
+	ExplainPassSuper          = "super"
+	ExplainPassActionWide     = "action_wide"
+	ExplainPassObjectSpecific = "object_specific"
+)
+
+// ExplainInput holds one actor and the snapshot rows it is re-evaluated against.
+type ExplainInput struct {
+	CMT    *models.CntMgt
+	Actor  models.DBActorMgt
+	DbMgts []models.DBMgt
+	Grants []QueryResult
+}
+
+// ExplainResult is the read-only re-evaluation of one actor.
+type ExplainResult struct {
+	SuperPrivilege bool
+	Roles          []dto.PrivilegeRolePath
+	Policies       []dto.PrivilegeExplainEntry
+}
+
+// ExplainPasses holds the policy queries built for one actor, per pass.
+type ExplainPasses struct {
+	Super          map[string]PolicyInput
+	ActionWide     map[string]PolicyInput
+	ObjectSpecific map[string]PolicyInput
+}
+
+// ExplainTrace tells how to trace allowed policies back to snapshot rows of one database type.
+type ExplainTrace struct {
+	Roots    []string // Principals the actor authenticates as; PUBLIC-style pseudo roles belong here too
+	Roles    []snapshot.RoleSource
+	Sources  []snapshot.GrantSource
+	FoldCase bool
+}
+
+// ExplainEvaluator replays the three discovery passes for a single actor without writing policies.
+// Skip rules match the handlers: super actors skip passes 2 and 3, actions granted in pass 2 skip pass 3.
+type ExplainEvaluator struct {
+	// Evaluate runs a policy query in the in-memory session and returns its result value and whether it allows the policy
+	Evaluate func(input PolicyInput) (string, bool, error)
+	// SuperScope returns the dbmgt ID covered by an allowed super policy (-1 for all databases),
+	// or false when the handler ignores the match. Nil means every super policy covers all databases.
+	SuperScope func(input PolicyInput) (int, bool)
+}
+
+// Explain evaluates passes and attaches the snapshot rows and role chains behind each allowed policy.
+func (e ExplainEvaluator) Explain(input ExplainInput, passes ExplainPasses, trace ExplainTrace) *ExplainResult {
+	grants := make([]snapshot.GrantResult, 0, len(input.Grants))
+	for _, result := range input.Grants {
+		grants = append(grants, snapshot.GrantResult{QueryKey: result.QueryKey, Status: result.Status, Result: result.Result})
+	}
+	resolver := snapshot.NewGrantResolver(grants, trace.Roles, trace.FoldCase)
+	paths := resolver.RolePaths(trace.Roots...)
+
+	result := &ExplainResult{
+		Roles:    snapshot.Roles(paths),
+		Policies: []dto.PrivilegeExplainEntry{},
+	}
+
+	superDbMgts := make(map[int]bool) // -1 covers all databases
+	grantedActions := make(map[int]bool)
+	hasSuper := func(dbmgtID int) bool {
+		return superDbMgts[-1] || superDbMgts[dbmgtID]
+	}
+
+	record := func(pass string, in PolicyInput, resultValue string) {
+		result.Policies = append(result.Policies, dto.PrivilegeExplainEntry{
+			PolicyDefaultID: in.Policydf.ID,
+			ActionID:        in.Policydf.ActionId,
+			Pass:            pass,
+			DBMgtID:         in.DbmgtId,
+			ObjectID:        in.ObjectId,
+			FinalSQL:        in.FinalSQL,
+			ResultValue:     resultValue,
+			Grants:          resolver.Evidence(in.FinalSQL, trace.Sources, paths),
+		})
+	}
+
+	// PASS 1: Super privileges
+	for _, key := range sortedQueryKeys(passes.Super) {
+		in := passes.Super[key]
+		value, allowed := e.evaluate(ExplainPassSuper, key, in)
+		if !allowed {
+			continue
+		}
+		scope := -1
+		if e.SuperScope != nil {
+			var ok bool
+			if scope, ok = e.SuperScope(in); !ok {
+				continue
+			}
+		}
+		superDbMgts[scope] = true
+		result.SuperPrivilege = true
+		record(ExplainPassSuper, in, value)
+	}
+
+	// PASS 2: Action-wide privileges
+	for _, key := range sortedQueryKeys(passes.ActionWide) {
+		in := passes.ActionWide[key]
+		if hasSuper(in.DbmgtId) {
+			continue
+		}
+		value, allowed := e.evaluate(ExplainPassActionWide, key, in)
+		if !allowed {
+			continue
+		}
+		grantedActions[in.Policydf.ActionId] = true
+		record(ExplainPassActionWide, in, value)
+	}
+
+	// PASS 3: Object-specific privileges
+	for _, key := range sortedQueryKeys(passes.ObjectSpecific) {
+		in := passes.ObjectSpecific[key]
+		if hasSuper(in.DbmgtId) || grantedActions[in.Policydf.ActionId] {
+			continue
+		}
+		value, allowed := e.evaluate(ExplainPassObjectSpecific, key, in)
+		if !allowed {
+			continue
+		}
+		record(ExplainPassObjectSpecific, in, value)
+	}
+
+	return result
+}
+
+func (e ExplainEvaluator) evaluate(pass, key string, in PolicyInput) (string, bool) {
+	value, allowed, err := e.Evaluate(in)
+	if err != nil {
+		logger.Debugf("Explain %s query failed for key=%s: %v", pass, key, err)
+		return "", false
+	}
+	return value, allowed
+}
+
+// sortedQueryKeys keeps explain output stable between calls.
+func sortedQueryKeys(queries map[string]PolicyInput) []string {
+	keys := make([]string, 0, len(queries))
+	for key := range queries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,37 @@
+package privilege
+
+import (
+	"time"
+
+	"dbfartifactapi/pkg/metrics"
+)
+
+// passBuckets span a handful of templates up to full discovery over large estates.
+var passBuckets = []float64{1, 5, 10, 30, 60, 120, 300, 600, 1800}
+
+var (
+	passDuration = metrics.NewHistogramVec("dbfartifactapi_privilege_pass_duration_seconds",
+		"Privilege discovery pass duration by database type and pass.", passBuckets, "db_type", "pass")
+	passQueries = metrics.NewCounterVec("dbfartifactapi_privilege_pass_queries_total",
+		"Queries executed by privilege discovery passes.", "db_type", "pass")
+	passPolicies = metrics.NewCounterVec("dbfartifactapi_privilege_pass_policies_total",
+		"Policies created by privilege discovery passes.", "db_type", "pass")
+)
+
+func init() {
+	metrics.MustRegister(passDuration, passQueries, passPolicies)
+}
+
+// Discovery pass names used as the pass label.
+const (
+	PassSuper          = "super"
+	PassActionWide     = "action_wide"
+	PassObjectSpecific = "object_specific"
+)
+
+// ObservePass records one discovery pass that executed queries and created policies since started.
+func ObservePass(dbType, pass string, queries, policies int, started time.Time) {
+	passDuration.WithLabelValues(dbType, pass).Observe(time.Since(started).Seconds())
+	passQueries.WithLabelValues(dbType, pass).Add(float64(queries))
+	passPolicies.WithLabelValues(dbType, pass).Add(float64(policies))
+}
@@ -0,0 +1,80 @@
+package mssql
+
+import (
+	"regexp"
+
+	"dbfartifactapi/models"
+	"dbfartifactapi/services/privilege"
+)
+
+const (
+	// mssqlSuperPrivilegePolicyID is the sysadmin policy default evaluated in Pass 1 (MySQL uses 1, Oracle 1001)
+	mssqlSuperPrivilegePolicyID = uint(3001)
+	// mssqlDBOwnerPolicyID is the db_owner policy default evaluated in Pass 1, once per database
+	mssqlDBOwnerPolicyID = uint(3002)
+	// mssqlSuperPrivGroupID is the group sysadmin actors are assigned to (MySQL uses 1, Oracle 1000)
+	mssqlSuperPrivGroupID = uint(3000)
+)
+
+var (
+	// sysPrefixRegex matches sys. qualifiers, optionally preceded by a database name ([db].sys. or db.sys.)
+	sysPrefixRegex = regexp.MustCompile(`(?i)(\[[^\]]+\]\.|\b\w+\.)?\bsys\.`)
+	// unicodeLiteralRegex matches the N prefix of Unicode string literals (N'sysadmin')
+	unicodeLiteralRegex = regexp.MustCompile(`\bN'`)
+	// bracketIdentifierRegex matches [identifier] quoting, which go-mysql-server expects as backticks
+	bracketIdentifierRegex = regexp.MustCompile(`\[([A-Za-z0-9_$#@ ]+)\]`)
+)
+
+// Dialect evaluates SQL Server privileges with the shared catalog discovery engine.
+// DbMgts hold the databases whose principals and permissions are collected (sys.database_* views).
+// sysadmin members are super on every database, db_owner members only on the databases they own.
+var Dialect = &privilege.CatalogDialect{
+	DBType:                "mssql",
+	Name:                  "MSSQL",
+	TypeNames:             []string{"mssql", "sqlserver"},
+	JobKind:               JobKindMSSQLPrivilegeSession,
+	ContextKey:            "mssql_privilege_session_context",
+	DatabaseNoun:          "databases",
+	SuperPolicyID:         mssqlSuperPrivilegePolicyID,
+	DatabaseSuperPolicyID: mssqlDBOwnerPolicyID,
+	SuperGroupID:          mssqlSuperPrivGroupID,
+	SessionDatabase:       "mssql",
+	Tables:                mssqlPrivilegeTables,
+	// Permission tables have no primary key because GRANT and DENY rows may repeat per grantor
+	PrimaryKeys: map[string][]string{
+		"server_principals":     {"name"},
+		"server_role_members":   {"role_name", "member_name"},
+		"database_principals":   {"database_name", "name"},
+		"database_role_members": {"database_name", "role_name", "member_name"},
+	},
+	TemplateTables: []string{"server_principals", "server_permissions", "server_role_members",
+		"database_principals", "database_permissions", "database_role_members"},
+	Columns:      GetMSSQLPrivilegeColumnNames,
+	Rewrite:      RewriteMSSQLQueryForPrivilegeSession,
+	Escape:       EscapeMSSQL,
+	BuildQueries: buildPrivilegeDataQueries,
+}
+
+// RewriteMSSQLQueryForPrivilegeSession adapts T-SQL policy templates to the in-memory session.
+// Strips sys. and database qualifiers (all views live in one database, keyed by database_name),
+// drops the N prefix of Unicode literals and converts [identifier] quoting to backticks.
+func RewriteMSSQLQueryForPrivilegeSession(query string) string {
+	rewritten := sysPrefixRegex.ReplaceAllString(query, "")
+	rewritten = unicodeLiteralRegex.ReplaceAllString(rewritten, "'")
+	rewritten = bracketIdentifierRegex.ReplaceAllString(rewritten, "`$1`")
+	return rewritten
+}
+
+// buildPrivilegeDataQueries adapts BuildMSSQLPrivilegeDataQueries to the actors and databases of a connection.
+func buildPrivilegeDataQueries(actors []models.DBActorMgt, databases []models.DBMgt) (map[string][]string, error) {
+	actorInfos := make([]ActorInfo, len(actors))
+	for i, actor := range actors {
+		actorInfos[i] = ActorInfo{DBUser: actor.DBUser}
+	}
+
+	dbInfos := make([]DatabaseInfo, len(databases))
+	for i, db := range databases {
+		dbInfos[i] = DatabaseInfo{DbName: db.DbName}
+	}
+	return BuildMSSQLPrivilegeDataQueries(actorInfos, dbInfos)
+}
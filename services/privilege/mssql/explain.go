@@ -0,0 +1,76 @@
+package mssql
+
+import (
+	"fmt"
+	"strings"
+
+	"dbfartifactapi/models"
+	"dbfartifactapi/services/privilege"
+	"dbfartifactapi/services/privilege/snapshot"
+)
+
+// mssqlExplainTrace describes which SQL Server catalog rows name a grantee and how roles are granted.
+// An actor is its login plus every database user mapped to that login; all principals belong to public.
+func mssqlExplainTrace(actor models.DBActorMgt, grants []privilege.QueryResult) privilege.ExplainTrace {
+	source := func(queryKey, grantee string) snapshot.GrantSource {
+		table := mssqlPrivilegeTables[queryKey]
+		columns, _ := GetMSSQLPrivilegeColumnNames(table)
+		return snapshot.GrantSource{QueryKey: queryKey, Table: table, Columns: columns, Grantee: grantee}
+	}
+	roleSource := func(queryKey string) snapshot.RoleSource {
+		columns, _ := GetMSSQLPrivilegeColumnNames(mssqlPrivilegeTables[queryKey])
+		return snapshot.RoleSource{QueryKey: queryKey, Columns: columns, Member: "member_name", Role: "role_name"}
+	}
+
+	return privilege.ExplainTrace{
+		Roots: append([]string{actor.DBUser, "public"}, mappedDatabaseUsers(actor.DBUser, grants)...),
+		Roles: []snapshot.RoleSource{
+			roleSource("sys.server_role_members"),
+			roleSource("sys.database_role_members"),
+		},
+		Sources: []snapshot.GrantSource{
+			source("sys.server_permissions", "grantee"),
+			source("sys.server_role_members", "member_name"),
+			source("sys.database_permissions", "grantee"),
+			source("sys.database_role_members", "member_name"),
+		},
+		FoldCase: true,
+	}
+}
+
+// mappedDatabaseUsers returns the database user names mapped to login in any captured database.
+func mappedDatabaseUsers(login string, grants []privilege.QueryResult) []string {
+	columns, _ := GetMSSQLPrivilegeColumnNames("database_principals")
+	nameIdx, loginIdx := -1, -1
+	for i, column := range columns {
+		switch column {
+		case "name":
+			nameIdx = i
+		case "login_name":
+			loginIdx = i
+		}
+	}
+
+	var users []string
+	for _, result := range grants {
+		if result.Status != "success" || !strings.HasPrefix(result.QueryKey, "sys.database_principals") {
+			continue
+		}
+		for _, row := range result.Result {
+			if len(row) <= nameIdx || len(row) <= loginIdx {
+				continue
+			}
+			if strings.EqualFold(fmt.Sprintf("%v", row[loginIdx]), login) {
+				users = append(users, fmt.Sprintf("%v", row[nameIdx]))
+			}
+		}
+	}
+	return users
+}
+
+// ExplainMSSQLActorPrivileges re-runs the three SQL Server passes for one actor against snapshot rows.
+// Nothing is written: policy defaults that would be allowed are returned with their grant/role path.
+// sysadmin covers every database; db_owner only the database its query ran for.
+func ExplainMSSQLActorPrivileges(input privilege.ExplainInput) (*privilege.ExplainResult, error) {
+	return Dialect.Explain(input, mssqlExplainTrace(input.Actor, input.Grants))
+}
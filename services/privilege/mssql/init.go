@@ -0,0 +1,21 @@
+package mssql
+
+import (
+	"dbfartifactapi/services/agent"
+	"dbfartifactapi/services/job"
+	"dbfartifactapi/services/privilege"
+)
+
+// JobKindMSSQLPrivilegeSession identifies MSSQL privilege session jobs in the job monitor.
+// Persisted with each job so the completion callback can be restored after an API restart.
+const JobKindMSSQLPrivilegeSession = "mssql_privilege_session"
+
+// RegisterJobKinds registers the completion callbacks of this package with the job monitor.
+// Called at startup once the agent executor is chosen, before persisted jobs are restored.
+func RegisterJobKinds(agentExec agent.AgentExecutor) {
+	job.RegisterJobKind(JobKindMSSQLPrivilegeSession, Dialect.NewCompletionHandler(agentExec))
+}
+
+func init() {
+	job.RegisterContextType(Dialect.ContextKey, &privilege.PrivilegeSessionJobContext{})
+}
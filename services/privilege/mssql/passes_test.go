@@ -0,0 +1,153 @@
+package mssql
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"testing"
+
+	"dbfartifactapi/models"
+	"dbfartifactapi/services/privilege"
+
+	"gorm.io/gorm"
+)
+
+// fixtureEvaluator applies the allow/deny rules of the policy service without a database
+type fixtureEvaluator struct{}
+
+func (fixtureEvaluator) IsPolicyAllowed(output, resAllow, resDeny string) bool {
+	if output == resDeny {
+		return false
+	}
+	return output == resAllow || (resAllow == "NOT NULL" && output != "NULL")
+}
+
+func (fixtureEvaluator) ExtractResultValue(result []map[string]interface{}) string {
+	if len(result) == 0 {
+		return "NULL"
+	}
+	for _, value := range result[0] {
+		if value == nil {
+			return "NULL"
+		}
+		return fmt.Sprintf("%v", value)
+	}
+	return "NULL"
+}
+
+func (fixtureEvaluator) GetPolicyDefaultsMap() map[uint]models.DBPolicyDefault { return nil }
+
+func (fixtureEvaluator) GetDBActorMgts(tx *gorm.DB, cntID uint) ([]*models.DBActorMgt, error) {
+	return nil, nil
+}
+
+func (fixtureEvaluator) GetDBObjectsByObjectIdAndDbMgt(tx *gorm.DB, objectID int, dbMgtID uint) ([]models.DBObjectMgt, error) {
+	return nil, nil
+}
+
+func allowedKeys(policies []privilege.AllowedPolicy) []string {
+	keys := make([]string, 0, len(policies))
+	for _, p := range policies {
+		keys = append(keys, p.Key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// TestEvaluatePasses_Fixture tests that sysadmin stops at Pass 1 everywhere, db_owner only on its own database,
+// a database-wide grant stops at Pass 2 and an object grant is found in Pass 3
+func TestEvaluatePasses_Fixture(t *testing.T) {
+	session, err := Dialect.NewSession(context.Background(), "mssql_passes_test")
+	if err != nil {
+		t.Fatalf("NewSession() error = %v", err)
+	}
+	defer session.Close()
+
+	fixture := []privilege.QueryResult{
+		{QueryKey: "sys.server_role_members", Status: "success", Result: [][]interface{}{
+			{"sysadmin", "sa"},
+		}},
+		{QueryKey: "sys.database_role_members[1]", Status: "success", Result: [][]interface{}{
+			{"sales", "db_owner", "owner"},
+		}},
+		{QueryKey: "sys.database_permissions[1]", Status: "success", Result: [][]interface{}{
+			{"sales", "sa", "dbo", "DATABASE", nil, nil, nil, "SELECT", "GRANT"},
+			{"sales", "owner", "dbo", "DATABASE", nil, nil, nil, "SELECT", "GRANT"},
+			{"sales", "owner", "dbo", "OBJECT_OR_COLUMN", "dbo", "orders", nil, "SELECT", "GRANT"},
+			{"sales", "app", "dbo", "OBJECT_OR_COLUMN", "dbo", "orders", nil, "SELECT", "GRANT"},
+		}},
+		{QueryKey: "sys.database_permissions[2]", Status: "success", Result: [][]interface{}{
+			{"hr", "app", "dbo", "DATABASE", nil, nil, nil, "SELECT", "GRANT"},
+			{"hr", "owner", "dbo", "OBJECT_OR_COLUMN", "dbo", "employees", nil, "SELECT", "GRANT"},
+		}},
+	}
+	if err := Dialect.LoadResults(session, fixture); err != nil {
+		t.Fatalf("LoadResults() error = %v", err)
+	}
+
+	hexSQL := func(s string) string { return hex.EncodeToString([]byte(s)) }
+	templates := privilege.CatalogTemplates{
+		Super: []models.DBPolicyDefault{
+			{
+				ID:          mssqlSuperPrivilegePolicyID,
+				SqlGet:      hexSQL("SELECT member_name FROM sys.server_role_members WHERE role_name = N'sysadmin' AND member_name = N'${dbactormgt.dbuser}'"),
+				SqlGetAllow: "NOT NULL",
+			},
+			{
+				ID:          mssqlDBOwnerPolicyID,
+				SqlGet:      hexSQL("SELECT member_name FROM [${dbmgt.dbname}].sys.database_role_members WHERE database_name = N'${dbmgt.dbname}' AND role_name = N'db_owner' AND member_name = N'${dbactormgt.dbuser}'"),
+				SqlGetAllow: "NOT NULL",
+			},
+		},
+		ActionWide: []models.DBPolicyDefault{{
+			ID:          3010,
+			ActionId:    1,
+			SqlGet:      hexSQL("SELECT permission_name FROM sys.database_permissions WHERE database_name = N'${dbmgt.dbname}' AND grantee = N'${dbactormgt.dbuser}' AND class_desc = N'DATABASE' AND permission_name = N'SELECT' AND state_desc = N'GRANT'"),
+			SqlGetAllow: "NOT NULL",
+		}},
+		ObjectSpecific: []models.DBPolicyDefault{{
+			ID:             3020,
+			ActionId:       1,
+			ObjectId:       7,
+			SqlGetSpecific: hexSQL("SELECT permission_name FROM sys.database_permissions WHERE database_name = N'${dbmgt.dbname}' AND grantee = N'${dbactormgt.dbuser}' AND class_desc = N'OBJECT_OR_COLUMN' AND object_name = N'${dbobjectmgt.objectname}' AND permission_name = N'SELECT' AND state_desc = N'GRANT'"),
+			SqlGetAllow:    "NOT NULL",
+		}},
+	}
+	dbMgts := []models.DBMgt{{ID: 10, DbName: "sales"}, {ID: 11, DbName: "hr"}}
+	actors := []models.DBActorMgt{
+		{ID: 1, DBUser: "sa"},
+		{ID: 2, DBUser: "owner"},
+		{ID: 3, DBUser: "app"},
+	}
+	objects := []models.DBObjectMgt{
+		{ID: 100, DBMgt: 10, ObjectName: "orders", ObjectId: 7},
+		{ID: 200, DBMgt: 11, ObjectName: "employees", ObjectId: 7},
+	}
+
+	results := Dialect.EvaluatePasses(session, fixtureEvaluator{}, templates, dbMgts, actors, objects)
+
+	tests := []struct {
+		pass string
+		got  []privilege.AllowedPolicy
+		want []string
+	}{
+		{"pass 1", results.Super, []string{"Actor:1_PolicyDf:3001_General", "Actor:2_PolicyDf:3002_DbMgt:10_General"}},
+		{"pass 2", results.ActionWide, []string{"Actor:3_PolicyDf:3010_DbMgt:11_General"}},
+		{"pass 3", results.ObjectSpecific, []string{"Actor:2_PolicyDf:3020_DbMgt:11_Object:200"}},
+	}
+	for _, tt := range tests {
+		if got := allowedKeys(tt.got); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%s allowed = %v, want %v", tt.pass, got, tt.want)
+		}
+	}
+
+	for _, p := range results.Super {
+		if p.PolicyDefaultID == mssqlDBOwnerPolicyID && p.DbMgtID != 10 {
+			t.Errorf("db_owner scope = dbmgt %d, want 10", p.DbMgtID)
+		}
+		if p.PolicyDefaultID == mssqlSuperPrivilegePolicyID && p.DbMgtID != -1 {
+			t.Errorf("sysadmin scope = dbmgt %d, want -1", p.DbMgtID)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package mssql
+
+import (
+	"fmt"
+	"strings"
+
+	"dbfartifactapi/pkg/logger"
+)
+
+// mssqlPrivilegeTables maps dbfAgentAPI query keys to in-memory session tables.
+// Principal IDs are resolved to names at the source, so tables can be joined on names across databases.
+var mssqlPrivilegeTables = map[string]string{
+	"sys.server_principals":     "server_principals",
+	"sys.server_permissions":    "server_permissions",
+	"sys.server_role_members":   "server_role_members",
+	"sys.database_principals":   "database_principals",
+	"sys.database_permissions":  "database_permissions",
+	"sys.database_role_members": "database_role_members",
+}
+
+// GetMSSQLPrivilegeColumnNames returns column names for SQL Server privilege tables.
+// Column order must match SELECT query order to prevent data corruption during loading.
+func GetMSSQLPrivilegeColumnNames(tableName string) ([]string, error) {
+	columnMap := map[string][]string{
+		"server_principals": {
+			"name", "principal_id", "type", "type_desc", "is_disabled", "default_database_name",
+		},
+		"server_permissions": {
+			"grantee", "grantor", "class_desc", "permission_name", "state_desc",
+		},
+		"server_role_members": {
+			"role_name", "member_name",
+		},
+		"database_principals": {
+			"database_name", "name", "principal_id", "type", "type_desc", "login_name", "default_schema_name",
+		},
+		"database_permissions": {
+			"database_name", "grantee", "grantor", "class_desc", "schema_name", "object_name",
+			"column_name", "permission_name", "state_desc",
+		},
+		"database_role_members": {
+			"database_name", "role_name", "member_name",
+		},
+	}
+
+	columns, ok := columnMap[tableName]
+	if !ok {
+		return nil, fmt.Errorf("unknown MSSQL privilege table: %s", tableName)
+	}
+	return columns, nil
+}
+
+// EscapeMSSQL escapes single quotes in SQL Server string literals.
+// SQL Server escapes a quote inside a literal by doubling it.
+func EscapeMSSQL(s string) string {
+	return strings.ReplaceAll(s, "'", "''")
+}
+
+// quoteMSSQLIdentifier wraps a database name in brackets for three-part names.
+// Closing brackets are doubled: my]db -> [my]]db]
+func quoteMSSQLIdentifier(s string) string {
+	return "[" + strings.ReplaceAll(s, "]", "]]") + "]"
+}
+
+// ActorInfo provides actor data needed for building SQL Server privilege queries.
+// Decouples from models.DBActorMgt to avoid circular imports.
+type ActorInfo struct {
+	DBUser string
+}
+
+// DatabaseInfo provides database data needed for building SQL Server privilege queries.
+// Decouples from models.DBMgt to avoid circular imports.
+type DatabaseInfo struct {
+	DbName string
+}
+
+// BuildMSSQLPrivilegeDataQueries builds queries to fetch privilege data from SQL Server catalog views.
+// Server-level views are queried once; database-level views are queried per database through
+// three-part names, each row tagged with its database_name.
+// Roles are always included so membership in fixed roles (sysadmin, db_owner, ...) can be evaluated.
+// Returns map of query keys to SQL statements for dbfAgentAPI execution.
+func BuildMSSQLPrivilegeDataQueries(actors []ActorInfo, databases []DatabaseInfo) (map[string][]string, error) {
+	if len(actors) == 0 {
+		return nil, fmt.Errorf("no actors provided for MSSQL privilege query building")
+	}
+	if len(databases) == 0 {
+		return nil, fmt.Errorf("no databases provided for MSSQL privilege query building")
+	}
+
+	loginNames := make([]string, 0, len(actors))
+	for _, actor := range actors {
+		loginNames = append(loginNames, fmt.Sprintf("N'%s'", EscapeMSSQL(actor.DBUser)))
+	}
+	loginFilter := strings.Join(loginNames, ", ")
+
+	queries := make(map[string][]string)
+
+	// sys.server_principals - actor logins plus server roles
+	queries["sys.server_principals"] = []string{
+		fmt.Sprintf(`SELECT name, CAST(principal_id AS varchar(20)) AS principal_id, type, type_desc,
+			CASE WHEN is_disabled = 1 THEN 'Y' ELSE 'N' END AS is_disabled,
+			ISNULL(default_database_name, '') AS default_database_name
+			FROM sys.server_principals
+			WHERE name IN (%s) OR type = 'R'`, loginFilter),
+	}
+
+	// sys.server_permissions - server-level GRANT/DENY (CONTROL SERVER, VIEW SERVER STATE, ...)
+	queries["sys.server_permissions"] = []string{
+		fmt.Sprintf(`SELECT gee.name AS grantee, gor.name AS grantor, p.class_desc, p.permission_name, p.state_desc
+			FROM sys.server_permissions p
+			JOIN sys.server_principals gee ON gee.principal_id = p.grantee_principal_id
+			JOIN sys.server_principals gor ON gor.principal_id = p.grantor_principal_id
+			WHERE gee.name IN (%s) OR gee.type = 'R'`, loginFilter),
+	}
+
+	// sys.server_role_members - fixed and user-defined server role membership
+	queries["sys.server_role_members"] = []string{
+		fmt.Sprintf(`SELECT r.name AS role_name, m.name AS member_name
+			FROM sys.server_role_members rm
+			JOIN sys.server_principals r ON r.principal_id = rm.role_principal_id
+			JOIN sys.server_principals m ON m.principal_id = rm.member_principal_id
+			WHERE m.name IN (%s) OR m.type = 'R'`, loginFilter),
+	}
+
+	for _, db := range databases {
+		dbLiteral := EscapeMSSQL(db.DbName)
+		dbIdent := quoteMSSQLIdentifier(db.DbName)
+
+		// Database users mapped to actor logins (via SID) plus database roles
+		principalFilter := fmt.Sprintf(`(sp.name IN (%s) OR dp.type = 'R')`, loginFilter)
+
+		// sys.database_principals - users with their mapped login, and database roles
+		queries["sys.database_principals"] = append(queries["sys.database_principals"],
+			fmt.Sprintf(`SELECT N'%s' AS database_name, dp.name, CAST(dp.principal_id AS varchar(20)) AS principal_id,
+				dp.type, dp.type_desc, ISNULL(sp.name, '') AS login_name, ISNULL(dp.default_schema_name, '') AS default_schema_name
+				FROM %s.sys.database_principals dp
+				LEFT JOIN sys.server_principals sp ON sp.sid = dp.sid
+				WHERE %s`, dbLiteral, dbIdent, principalFilter))
+
+		// sys.database_permissions - database, schema, object and column GRANT/DENY
+		queries["sys.database_permissions"] = append(queries["sys.database_permissions"],
+			fmt.Sprintf(`SELECT N'%s' AS database_name, dp.name AS grantee, gor.name AS grantor, p.class_desc,
+				ISNULL(COALESCE(os.name, s.name), '') AS schema_name,
+				ISNULL(o.name, '') AS object_name,
+				ISNULL(c.name, '') AS column_name,
+				p.permission_name, p.state_desc
+				FROM %s.sys.database_permissions p
+				JOIN %s.sys.database_principals dp ON dp.principal_id = p.grantee_principal_id
+				JOIN %s.sys.database_principals gor ON gor.principal_id = p.grantor_principal_id
+				LEFT JOIN sys.server_principals sp ON sp.sid = dp.sid
+				LEFT JOIN %s.sys.objects o ON p.class = 1 AND o.object_id = p.major_id
+				LEFT JOIN %s.sys.schemas os ON os.schema_id = o.schema_id
+				LEFT JOIN %s.sys.schemas s ON p.class = 3 AND s.schema_id = p.major_id
+				LEFT JOIN %s.sys.columns c ON p.class = 1 AND p.minor_id > 0 AND c.object_id = p.major_id AND c.column_id = p.minor_id
+				WHERE %s`, dbLiteral, dbIdent, dbIdent, dbIdent, dbIdent, dbIdent, dbIdent, dbIdent, principalFilter))
+
+		// sys.database_role_members - fixed and user-defined database role membership
+		queries["sys.database_role_members"] = append(queries["sys.database_role_members"],
+			fmt.Sprintf(`SELECT N'%s' AS database_name, r.name AS role_name, dp.name AS member_name
+				FROM %s.sys.database_role_members rm
+				JOIN %s.sys.database_principals r ON r.principal_id = rm.role_principal_id
+				JOIN %s.sys.database_principals dp ON dp.principal_id = rm.member_principal_id
+				LEFT JOIN sys.server_principals sp ON sp.sid = dp.sid
+				WHERE %s`, dbLiteral, dbIdent, dbIdent, dbIdent, principalFilter))
+	}
+
+	logger.Infof("Built %d MSSQL privilege query groups for %d actors and %d databases",
+		len(queries), len(actors), len(databases))
+
+	return queries, nil
+}
@@ -0,0 +1,119 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"dbfartifactapi/models"
+	"dbfartifactapi/pkg/logger"
+	"dbfartifactapi/repository"
+	"dbfartifactapi/services/privilege"
+	"dbfartifactapi/services/privilege/snapshot"
+)
+
+// mysqlExplainTrace describes which MySQL grant tables name a grantee and how roles are granted.
+// Principals are user@host, the same identity MySQL checks privileges against.
+func mysqlExplainTrace(actor models.DBActorMgt) privilege.ExplainTrace {
+	source := func(table, user, host string) snapshot.GrantSource {
+		columns, _ := getTableColumnNames(table)
+		return snapshot.GrantSource{QueryKey: table, Table: table, Columns: columns, Grantee: user, GranteeHost: host}
+	}
+	roleColumns, _ := getTableColumnNames("mysql.role_edges")
+
+	return privilege.ExplainTrace{
+		Roots: []string{actor.DBUser + "@" + actor.IPAddress},
+		Roles: []snapshot.RoleSource{{
+			QueryKey:   "mysql.role_edges",
+			Columns:    roleColumns,
+			Member:     "TO_USER",
+			MemberHost: "TO_HOST",
+			Role:       "FROM_USER",
+			RoleHost:   "FROM_HOST",
+		}},
+		Sources: []snapshot.GrantSource{
+			source("mysql.user", "User", "Host"),
+			source("mysql.db", "User", "Host"),
+			source("mysql.tables_priv", "User", "Host"),
+			source("mysql.procs_priv", "User", "Host"),
+			source("mysql.global_grants", "USER", "HOST"),
+			source("mysql.proxies_priv", "User", "Host"),
+		},
+	}
+}
+
+// ExplainActorPrivileges re-runs the three MySQL passes for one actor against snapshot rows.
+// Nothing is written: policy defaults that would be allowed are returned with their grant/role path.
+func ExplainActorPrivileges(input privilege.ExplainInput) (*privilege.ExplainResult, error) {
+	if input.CMT == nil {
+		return nil, fmt.Errorf("connection management is required for MySQL privilege explain")
+	}
+	if strings.ToLower(input.CMT.CntType) != "mysql" {
+		return nil, fmt.Errorf("MySQL privilege explain does not support database type: %s", input.CMT.CntType)
+	}
+
+	sessionID := fmt.Sprintf("mysql_explain_actor_%d_%d", input.Actor.ID, time.Now().Unix())
+	session, err := NewPrivilegeSession(context.Background(), sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create privilege session: %w", err)
+	}
+	defer session.Close()
+
+	if err := loadPrivilegeDataFromResults(session, input.Grants); err != nil {
+		return nil, fmt.Errorf("failed to load privilege data: %w", err)
+	}
+
+	// Query building reads objects and actors only; the transaction is never committed
+	tx := repository.NewBaseRepository().Begin()
+	defer tx.Rollback()
+
+	service := privilege.NewPolicyEvaluator()
+	classification := classifyPolicyTemplates(service.GetPolicyDefaultsMap())
+	actors := []models.DBActorMgt{input.Actor}
+
+	var passes privilege.ExplainPasses
+	if passes.Super, err = processGeneralSQLTemplatesForSession(tx, classification.superPrivileges, input.DbMgts, actors, input.CMT, service); err != nil {
+		logger.Warnf("Failed to build super privilege queries for explain: %v", err)
+	}
+	if passes.ActionWide, err = processGeneralSQLTemplatesForSession(tx, classification.actionWidePrivs, input.DbMgts, actors, input.CMT, service); err != nil {
+		logger.Warnf("Failed to build action-wide queries for explain: %v", err)
+	}
+
+	cache, err := buildQueryBuildCache(tx, input.CMT.ID, input.DbMgts, classification.objectSpecificPrivs, service)
+	if err != nil {
+		logger.Warnf("Failed to build query cache for explain: %v", err)
+		cache = &queryBuildCache{
+			allActorsByCntID: make(map[uint][]*models.DBActorMgt),
+			objectsByKey:     make(map[string][]*models.DBObjectMgt),
+		}
+	}
+	generalQueries, err := processGeneralSQLTemplatesForSession(tx, classification.objectSpecificPrivs, input.DbMgts, actors, input.CMT, service)
+	if err != nil {
+		logger.Warnf("Failed to build general object-specific queries for explain: %v", err)
+	}
+	specificQueries, err := processSpecificSQLTemplatesForSession(tx, classification.objectSpecificPrivs, input.DbMgts, actors, input.CMT, service, cache)
+	if err != nil {
+		logger.Warnf("Failed to build specific object-specific queries for explain: %v", err)
+	}
+	passes.ObjectSpecific = make(map[string]privilege.PolicyInput, len(generalQueries)+len(specificQueries))
+	for k, v := range generalQueries {
+		passes.ObjectSpecific[k] = v
+	}
+	for k, v := range specificQueries {
+		passes.ObjectSpecific[k] = v
+	}
+
+	evaluator := privilege.ExplainEvaluator{
+		Evaluate: func(in privilege.PolicyInput) (string, bool, error) {
+			result, err := session.ExecuteTemplate(RewriteQueryForPrivilegeSession(in.FinalSQL), map[string]string{})
+			if err != nil {
+				return "", false, err
+			}
+			value := service.ExtractResultValue(result)
+			return value, service.IsPolicyAllowed(value, in.Policydf.SqlGetAllow, in.Policydf.SqlGetDeny), nil
+		},
+	}
+
+	return evaluator.Explain(input, passes, mysqlExplainTrace(input.Actor)), nil
+}
@@ -16,6 +16,7 @@ import (
 	"dbfartifactapi/models"
 	"dbfartifactapi/pkg/logger"
 	"dbfartifactapi/repository"
+	"dbfartifactapi/services/agent"
 	"dbfartifactapi/services/job"
 	"dbfartifactapi/services/privilege"
 	"dbfartifactapi/utils"
@@ -517,7 +518,7 @@ actorLoop:
 }
 
 // CreatePrivilegeSessionCompletionHandler creates callback for privilege session job completion
-func CreatePrivilegeSessionCompletionHandler() job.JobCompletionCallback {
+func CreatePrivilegeSessionCompletionHandler(agentExec agent.AgentExecutor) job.JobCompletionCallback {
 	return func(jobID string, jobInfo *job.JobInfo, statusResp *job.StatusResponse) error {
 		logger.Infof("Processing privilege session completion for job %s, status: %s", jobID, statusResp.Status)
 
@@ -529,7 +530,7 @@ func CreatePrivilegeSessionCompletionHandler() job.JobCompletionCallback {
 
 		// Process completed jobs
 		if statusResp.Status == "completed" {
-			return processPrivilegeSessionResults(jobID, contextData, statusResp, jobInfo)
+			return processPrivilegeSessionResults(agentExec, jobID, contextData, statusResp, jobInfo)
 		} else {
 			logger.Errorf("Privilege session job %s failed", jobID)
 			return fmt.Errorf("privilege session job failed: %s", statusResp.Message)
@@ -538,7 +539,7 @@ func CreatePrivilegeSessionCompletionHandler() job.JobCompletionCallback {
 }
 
 // processPrivilegeSessionResults processes the results of privilege data loading job
-func processPrivilegeSessionResults(jobID string, contextData interface{}, statusResp *job.StatusResponse, jobInfo *job.JobInfo) error {
+func processPrivilegeSessionResults(agentExec agent.AgentExecutor, jobID string, contextData interface{}, statusResp *job.StatusResponse, jobInfo *job.JobInfo) error {
 	logger.Infof("Processing privilege session results for job %s - completed: %d, failed: %d",
 		jobID, statusResp.Completed, statusResp.Failed)
 
@@ -554,7 +555,7 @@ func processPrivilegeSessionResults(jobID string, contextData interface{}, statu
 	}
 
 	// Legacy VeloArtifact polling flow
-	return processPrivilegeSessionFromVeloArtifact(jobID, sessionContext, statusResp)
+	return processPrivilegeSessionFromVeloArtifact(agentExec, jobID, sessionContext, statusResp)
 }
 
 // processPrivilegeSessionFromNotification handles privilege session processing from notification
@@ -626,7 +627,7 @@ func processPrivilegeSessionFromNotification(jobID string, sessionContext *privi
 }
 
 // processPrivilegeSessionFromVeloArtifact handles privilege session processing via VeloArtifact polling
-func processPrivilegeSessionFromVeloArtifact(jobID string, sessionContext *privilege.PrivilegeSessionJobContext, statusResp *job.StatusResponse) error {
+func processPrivilegeSessionFromVeloArtifact(agentExec agent.AgentExecutor, jobID string, sessionContext *privilege.PrivilegeSessionJobContext, statusResp *job.StatusResponse) error {
 	logger.Infof("Processing privilege session from VeloArtifact polling for job %s", jobID)
 
 	jobMonitor := job.GetJobMonitorService()
@@ -639,7 +640,7 @@ func processPrivilegeSessionFromVeloArtifact(jobID string, sessionContext *privi
 	}
 
 	// Retrieve privilege data results
-	privilegeData, err := retrievePrivilegeDataResults(jobID, ep)
+	privilegeData, err := retrievePrivilegeDataResults(agentExec, jobID, ep)
 	if err != nil {
 		jobMonitor.FailJobAfterProcessing(jobID, err.Error())
 		return err
@@ -685,9 +686,9 @@ func parsePrivilegeDataFile(filePath string) ([]privilege.QueryResult, error) {
 }
 
 // retrievePrivilegeDataResults gets privilege data from VeloArtifact
-func retrievePrivilegeDataResults(jobID string, ep *models.Endpoint) ([]privilege.QueryResult, error) {
+func retrievePrivilegeDataResults(agentExec agent.AgentExecutor, jobID string, ep *models.Endpoint) ([]privilege.QueryResult, error) {
 	// Use same logic as policy results retrieval
-	return privilege.RetrieveJobResults(jobID, ep)
+	return privilege.RetrieveJobResults(agentExec, jobID, ep)
 }
 
 // writeQueryToLogFile appends query to log file for tracking purposes
@@ -721,6 +722,12 @@ func createPoliciesWithPrivilegeData(jobID string, sessionContext *privilege.Pri
 		return 0, fmt.Errorf("privilege session only supports MySQL databases, got: %s", sessionContext.CMT.CntType)
 	}
 
+	// Skip all processing when job was cancelled before its results arrived
+	jobMonitor := job.GetJobMonitorService()
+	if err := jobMonitor.CheckCancelled(jobID); err != nil {
+		return 0, err
+	}
+
 	logger.Infof("Creating in-memory privilege session for MySQL database, job %s", jobID)
 
 	ctx := context.Background()
@@ -738,7 +745,7 @@ func createPoliciesWithPrivilegeData(jobID string, sessionContext *privilege.Pri
 
 	// Create query log file for tracking (only if enabled)
 	var logFile *os.File
-	if config.Cfg.EnableMySQLPrivilegeQueryLogging {
+	if config.Current().EnableMySQLPrivilegeQueryLogging {
 		logFileName := fmt.Sprintf("privilege_queries_%s_%s.log", sessionContext.SessionID, time.Now().Format("20060102_150405"))
 		logFilePath := filepath.Join(config.Cfg.DBFWebTempDir, logFileName)
 		var err error
@@ -783,12 +790,20 @@ func createPoliciesWithPrivilegeData(jobID string, sessionContext *privilege.Pri
 		if err != nil {
 			logger.Errorf("Failed to build super privilege queries: %v", err)
 		} else {
-			superPolicies := executeSuperPrivilegeQueries(tx, session, superQueries, service, sessionContext.CntMgtID, sessionContext.CMT, allowedResults, superPrivActors, logFile)
+			passStart := time.Now()
+			superPolicies := executeSuperPrivilegeQueries(tx, session, superQueries, service, sessionContext.CntMgtID, sessionContext.CMT, allowedResults, superPrivActors, logFile,
+				jobMonitor.NewStepProgress(jobID, "Pass 1/3: super privilege queries", len(superQueries)))
+			privilege.ObservePass("mysql", privilege.PassSuper, len(superQueries), superPolicies, passStart)
 			totalPolicies += superPolicies
 			logger.Infof("Pass 1 completed: %d super policies created", superPolicies)
 		}
 	}
 
+	// Uncommitted policies from earlier passes are rolled back by the deferred tx.Rollback
+	if err := jobMonitor.CheckCancelled(jobID); err != nil {
+		return 0, err
+	}
+
 	// PASS 2: Action-wide privileges - grant specific action on ALL objects for ALL databases
 	if len(classification.actionWidePrivs) > 0 {
 		logger.Infof("Processing Pass 2: %d action-wide privilege templates", len(classification.actionWidePrivs))
@@ -799,12 +814,19 @@ func createPoliciesWithPrivilegeData(jobID string, sessionContext *privilege.Pri
 		if err != nil {
 			logger.Errorf("Failed to build action-wide queries: %v", err)
 		} else {
-			actionPolicies := executeActionWideQueries(tx, session, actionWideQueries, service, sessionContext.CntMgtID, sessionContext.CMT, grantedActions, allowedResults, superPrivActors, logFile)
+			passStart := time.Now()
+			actionPolicies := executeActionWideQueries(tx, session, actionWideQueries, service, sessionContext.CntMgtID, sessionContext.CMT, grantedActions, allowedResults, superPrivActors, logFile,
+				jobMonitor.NewStepProgress(jobID, "Pass 2/3: action-wide queries", len(actionWideQueries)))
+			privilege.ObservePass("mysql", privilege.PassActionWide, len(actionWideQueries), actionPolicies, passStart)
 			totalPolicies += actionPolicies
 			logger.Infof("Pass 2 completed: %d action-wide policies created", actionPolicies)
 		}
 	}
 
+	if err := jobMonitor.CheckCancelled(jobID); err != nil {
+		return 0, err
+	}
+
 	// PASS 3: Object-specific privileges - skip if action already granted in Pass 2
 	if len(classification.objectSpecificPrivs) > 0 {
 		logger.Infof("Processing Pass 3: %d object-specific privilege templates", len(classification.objectSpecificPrivs))
@@ -842,7 +864,10 @@ func createPoliciesWithPrivilegeData(jobID string, sessionContext *privilege.Pri
 			allObjectQueries[k] = v
 		}
 
-		objectPolicies := executeObjectSpecificQueries(tx, session, allObjectQueries, service, sessionContext.CntMgtID, grantedActions, allowedResults, superPrivActors, logFile)
+		passStart := time.Now()
+		objectPolicies := executeObjectSpecificQueries(tx, session, allObjectQueries, service, sessionContext.CntMgtID, grantedActions, allowedResults, superPrivActors, logFile,
+			jobMonitor.NewStepProgress(jobID, "Pass 3/3: object-specific queries", len(allObjectQueries)))
+		privilege.ObservePass("mysql", privilege.PassObjectSpecific, len(allObjectQueries), objectPolicies, passStart)
 		totalPolicies += objectPolicies
 		logger.Infof("Pass 3 completed: %d object-specific policies created", objectPolicies)
 	}
@@ -853,6 +878,18 @@ func createPoliciesWithPrivilegeData(jobID string, sessionContext *privilege.Pri
 		logger.Warnf("Failed to assign actors to groups: %v", err)
 	}
 
+	privilege.CapturePrivilegeSnapshot(tx, sessionContext.CntMgtID, jobID, "mysql", privilegeData, sessionContext.DbActorMgts, allowedResults.actorPolicies)
+
+	// Last check before commit - cancellation during Pass 3 or group assignment rolls back everything
+	if err := jobMonitor.CheckCancelled(jobID); err != nil {
+		return 0, err
+	}
+
+	// Committed with the policies so a restart does not evaluate and insert them again
+	if err := jobMonitor.MarkCallbackCommitted(tx, jobID); err != nil {
+		return 0, err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return 0, fmt.Errorf("failed to commit policies: %v", err)
 	}
@@ -889,6 +926,7 @@ func executeSuperPrivilegeQueries(
 	allowedResults *allowedPolicyResults,
 	superPrivActors *superPrivilegeActors,
 	logFile *os.File,
+	progress *job.StepProgress,
 ) int {
 	type queryResult struct {
 		uniqueKey   string
@@ -917,7 +955,7 @@ func executeSuperPrivilegeQueries(
 			semaphore <- struct{}{}
 			defer func() { <-semaphore }()
 
-			rewrittenSQL := rewriteQueryForPrivilegeSession(input.FinalSQL)
+			rewrittenSQL := RewriteQueryForPrivilegeSession(input.FinalSQL)
 
 			// Log query if enabled
 			writeQueryToLogFile(logFile, "PASS-1-SUPER", key, rewrittenSQL)
@@ -937,6 +975,7 @@ func executeSuperPrivilegeQueries(
 	allResults := make([]queryResult, 0, queryCount)
 	for i := 0; i < queryCount; i++ {
 		result := <-results
+		progress.Update(i + 1)
 		if result.err != nil {
 			logger.Debugf("Super privilege query failed for key=%s: %v", result.uniqueKey, result.err)
 			continue
@@ -1005,6 +1044,7 @@ func executeActionWideQueries(
 	allowedResults *allowedPolicyResults,
 	superPrivActors *superPrivilegeActors,
 	logFile *os.File,
+	progress *job.StepProgress,
 ) int {
 	type queryResult struct {
 		uniqueKey   string
@@ -1055,7 +1095,7 @@ func executeActionWideQueries(
 			semaphore <- struct{}{}
 			defer func() { <-semaphore }()
 
-			rewrittenSQL := rewriteQueryForPrivilegeSession(input.FinalSQL)
+			rewrittenSQL := RewriteQueryForPrivilegeSession(input.FinalSQL)
 
 			// Log query if enabled
 			writeQueryToLogFile(logFile, "PASS-2-ACTION-WIDE", key, rewrittenSQL)
@@ -1075,6 +1115,7 @@ func executeActionWideQueries(
 	allResults := make([]queryResult, 0, queryCount)
 	for i := 0; i < queryCount; i++ {
 		result := <-results
+		progress.Update(i + 1)
 		if result.err != nil {
 			logger.Debugf("Action-wide query failed for key=%s: %v", result.uniqueKey, result.err)
 			continue
@@ -1139,6 +1180,7 @@ func executeObjectSpecificQueries(
 	allowedResults *allowedPolicyResults,
 	superPrivActors *superPrivilegeActors,
 	logFile *os.File,
+	progress *job.StepProgress,
 ) int {
 	type queryResult struct {
 		uniqueKey   string
@@ -1197,7 +1239,7 @@ func executeObjectSpecificQueries(
 			semaphore <- struct{}{}
 			defer func() { <-semaphore }()
 
-			rewrittenSQL := rewriteQueryForPrivilegeSession(input.FinalSQL)
+			rewrittenSQL := RewriteQueryForPrivilegeSession(input.FinalSQL)
 
 			// Log query if enabled
 			writeQueryToLogFile(logFile, "PASS-3-OBJECT-SPECIFIC", key, rewrittenSQL)
@@ -1217,6 +1259,7 @@ func executeObjectSpecificQueries(
 	allResults := make([]queryResult, 0, queryCount)
 	for i := 0; i < queryCount; i++ {
 		result := <-results
+		progress.Update(i + 1)
 		if result.err != nil {
 			logger.Debugf("Object-specific query failed for key=%s: %v", result.uniqueKey, result.err)
 			continue
@@ -1402,9 +1445,9 @@ func insertResultsIntoSession(session *privilege.PrivilegeSession, tableName str
 	return nil
 }
 
-// rewriteQueryForPrivilegeSession rewrites queries to use mysql.infoschema_* tables instead of information_schema.*.
+// RewriteQueryForPrivilegeSession rewrites queries to use mysql.infoschema_* tables instead of information_schema.*.
 // Required because go-mysql-server doesn't allow INSERT into information_schema database.
-func rewriteQueryForPrivilegeSession(sql string) string {
+func RewriteQueryForPrivilegeSession(sql string) string {
 	// Replace information_schema table references with mysql.infoschema_* equivalents
 	replacements := map[string]string{
 		"information_schema.USER_PRIVILEGES":   "mysql.infoschema_user_privileges",
@@ -1457,7 +1500,7 @@ func processQueriesWithSession(tx *gorm.DB, session *privilege.PrivilegeSession,
 			defer func() { <-semaphore }()
 
 			// Rewrite query to use mysql.infoschema_* tables instead of information_schema.*
-			rewrittenSQL := rewriteQueryForPrivilegeSession(input.FinalSQL)
+			rewrittenSQL := RewriteQueryForPrivilegeSession(input.FinalSQL)
 
 			result, err := session.ExecuteTemplate(rewrittenSQL, map[string]string{})
 			if err != nil {
@@ -0,0 +1,21 @@
+package mysql
+
+import (
+	"dbfartifactapi/services/agent"
+	"dbfartifactapi/services/job"
+	"dbfartifactapi/services/privilege"
+)
+
+// JobKindPrivilegeSession identifies MySQL privilege session jobs in the job monitor.
+// Persisted with each job so the completion callback can be restored after an API restart.
+const JobKindPrivilegeSession = "privilege_session"
+
+// RegisterJobKinds registers the completion callbacks of this package with the job monitor.
+// Called at startup once the agent executor is chosen, before persisted jobs are restored.
+func RegisterJobKinds(agentExec agent.AgentExecutor) {
+	job.RegisterJobKind(JobKindPrivilegeSession, CreatePrivilegeSessionCompletionHandler(agentExec))
+}
+
+func init() {
+	job.RegisterContextType("privilege_session_context", &privilege.PrivilegeSessionJobContext{})
+}
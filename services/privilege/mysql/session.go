@@ -105,7 +105,7 @@ func NewPrivilegeSession(ctx context.Context, sessionID string) (*privilege.Priv
 
 // LoadPrivilegeData loads privilege data from real MySQL database into temporary in-memory server.
 // Executes SELECT queries on MySQL system tables to retrieve privilege information for specified actors and databases.
-func LoadPrivilegeData(ps *privilege.PrivilegeSession, cmt *models.CntMgt, actors []models.DBActorMgt, databases []models.DBMgt, endpointRepo repository.EndpointRepository) error {
+func LoadPrivilegeData(agentExec agent.AgentExecutor, ps *privilege.PrivilegeSession, cmt *models.CntMgt, actors []models.DBActorMgt, databases []models.DBMgt, endpointRepo repository.EndpointRepository) error {
 	actorPairs := []string{}
 	for _, actor := range actors {
 		actorPairs = append(actorPairs, fmt.Sprintf("('%s', '%s')",
@@ -159,7 +159,7 @@ func LoadPrivilegeData(ps *privilege.PrivilegeSession, cmt *models.CntMgt, actor
 	}
 
 	for _, tbl := range tables {
-		if err := loadTable(ps, tbl.tableName, tbl.query, cmt, ep); err != nil {
+		if err := loadTable(agentExec, ps, tbl.tableName, tbl.query, cmt, ep); err != nil {
 			logger.Warnf("Failed to load %s: %v", tbl.tableName, err)
 		}
 	}
@@ -169,13 +169,13 @@ func LoadPrivilegeData(ps *privilege.PrivilegeSession, cmt *models.CntMgt, actor
 }
 
 // loadTable fetches data from real MySQL and inserts into temporary server
-func loadTable(ps *privilege.PrivilegeSession, tableName, query string, cmt *models.CntMgt, ep *models.Endpoint) error {
+func loadTable(agentExec agent.AgentExecutor, ps *privilege.PrivilegeSession, tableName, query string, cmt *models.CntMgt, ep *models.Endpoint) error {
 	queryParam := dto.NewDBQueryParamBuilder().
 		SetDBType(strings.ToLower(cmt.CntType)).
 		SetHost(cmt.IP).
 		SetPort(cmt.Port).
 		SetUser(cmt.Username).
-		SetPassword(cmt.Password).
+		SetCredentials(cmt.Password, cmt.SecretRef).
 		Build()
 	queryParam.Query = query
 
@@ -184,7 +184,7 @@ func loadTable(ps *privilege.PrivilegeSession, tableName, query string, cmt *mod
 		return fmt.Errorf("failed to create agent command JSON: %w", err)
 	}
 
-	stdout, err := agent.ExecuteSqlAgentAPI(ep.ClientID, ep.OsType, "execute", hexJSON, "", false)
+	stdout, err := agentExec.ExecuteSql(ep.ClientID, ep.OsType, "execute", hexJSON, "", false)
 	if err != nil {
 		return fmt.Errorf("failed to execute query: %w", err)
 	}
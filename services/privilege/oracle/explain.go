@@ -0,0 +1,130 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"dbfartifactapi/models"
+	"dbfartifactapi/pkg/logger"
+	"dbfartifactapi/repository"
+	"dbfartifactapi/services/privilege"
+	"dbfartifactapi/services/privilege/snapshot"
+)
+
+// oracleExplainTrace describes which Oracle dictionary views name a grantee and how roles are granted.
+// Grants to PUBLIC apply to every user, so PUBLIC is traced as a root next to the actor.
+func oracleExplainTrace(actor models.DBActorMgt) privilege.ExplainTrace {
+	source := func(table, grantee string) snapshot.GrantSource {
+		columns, _ := GetOraclePrivilegeColumnNames(table)
+		return snapshot.GrantSource{QueryKey: table, Table: table, Columns: columns, Grantee: grantee}
+	}
+	roleColumns, _ := GetOraclePrivilegeColumnNames("dba_role_privs")
+
+	return privilege.ExplainTrace{
+		Roots: []string{actor.DBUser, "PUBLIC"},
+		Roles: []snapshot.RoleSource{{
+			QueryKey: "dba_role_privs",
+			Columns:  roleColumns,
+			Member:   "GRANTEE",
+			Role:     "GRANTED_ROLE",
+		}},
+		Sources: []snapshot.GrantSource{
+			source("dba_sys_privs", "GRANTEE"),
+			source("dba_tab_privs", "GRANTEE"),
+			source("dba_role_privs", "GRANTEE"),
+			source("cdb_sys_privs", "GRANTEE"),
+			source("v$pwfile_users", "USERNAME"),
+		},
+		FoldCase: true,
+	}
+}
+
+// ExplainOracleActorPrivileges re-runs the three Oracle passes for one actor against snapshot rows.
+// Nothing is written: policy defaults that would be allowed are returned with their grant/role path.
+func ExplainOracleActorPrivileges(input privilege.ExplainInput) (*privilege.ExplainResult, error) {
+	if input.CMT == nil {
+		return nil, fmt.Errorf("connection management is required for Oracle privilege explain")
+	}
+	connType := GetOracleConnectionType(input.CMT)
+
+	oraclePrivData, err := parseOraclePrivilegeResults(input.Grants, connType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse oracle privilege results: %w", err)
+	}
+	oraclePrivData.ExpandRoleClosure([]string{input.Actor.DBUser})
+
+	sessionID := fmt.Sprintf("oracle_explain_actor_%d_%d", input.Actor.ID, time.Now().Unix())
+	session, err := NewOraclePrivilegeSession(context.Background(), sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create oracle privilege session: %w", err)
+	}
+	defer session.Close()
+
+	if err := session.LoadOraclePrivilegeDataFromResults(oraclePrivData); err != nil {
+		return nil, fmt.Errorf("failed to load oracle privilege data: %w", err)
+	}
+
+	// Query building reads objects and actors only; the transaction is never committed
+	tx := repository.NewBaseRepository().Begin()
+	defer tx.Rollback()
+
+	service := privilege.NewPolicyEvaluator()
+	classification := classifyOraclePolicyTemplates(service.GetPolicyDefaultsMap())
+	actors := []models.DBActorMgt{input.Actor}
+
+	superQueries, err := processOracleSQLTemplatesForSession(tx, classification.superPrivileges, input.DbMgts, actors, input.CMT, service, connType)
+	if err != nil {
+		logger.Warnf("Failed to build oracle super privilege queries for explain: %v", err)
+	}
+	actionWideQueries, err := processOracleSQLTemplatesForSession(tx, classification.actionWidePrivs, input.DbMgts, actors, input.CMT, service, connType)
+	if err != nil {
+		logger.Warnf("Failed to build oracle action-wide queries for explain: %v", err)
+	}
+
+	cache := newOracleQueryBuildCache(tx, input.CMT.ID, input.DbMgts, classification.objectSpecificPrivs)
+	generalQueries, err := processOracleSQLTemplatesForSession(tx, classification.objectSpecificPrivs, input.DbMgts, actors, input.CMT, service, connType)
+	if err != nil {
+		logger.Warnf("Failed to build oracle general object-specific queries for explain: %v", err)
+	}
+	specificQueries, err := processOracleSpecificSQLTemplatesForSession(tx, classification.objectSpecificPrivs, input.DbMgts, actors, input.CMT, service, connType, cache)
+	if err != nil {
+		logger.Warnf("Failed to build oracle specific object-specific queries for explain: %v", err)
+	}
+
+	passes := privilege.ExplainPasses{
+		Super:          toPolicyInputs(superQueries),
+		ActionWide:     toPolicyInputs(actionWideQueries),
+		ObjectSpecific: toPolicyInputs(generalQueries),
+	}
+	for k, v := range toPolicyInputs(specificQueries) {
+		passes.ObjectSpecific[k] = v
+	}
+
+	evaluator := privilege.ExplainEvaluator{
+		Evaluate: func(in privilege.PolicyInput) (string, bool, error) {
+			results, err := session.ExecuteOracleTemplate(in.FinalSQL, nil)
+			if err != nil {
+				return "", false, err
+			}
+			return service.ExtractResultValue(results), isOraclePolicyAllowed(results, in.Policydf), nil
+		},
+	}
+
+	return evaluator.Explain(input, passes, oracleExplainTrace(input.Actor)), nil
+}
+
+// toPolicyInputs converts Oracle query inputs to the shared PolicyInput used by explain.
+func toPolicyInputs(queries map[string]policyInput) map[string]privilege.PolicyInput {
+	inputs := make(map[string]privilege.PolicyInput, len(queries))
+	for key, q := range queries {
+		inputs[key] = privilege.PolicyInput{
+			Policydf: q.policydf,
+			ActorId:  q.actorId,
+			ObjectId: q.objectId,
+			DbmgtId:  q.dbmgtId,
+			FinalSQL: q.finalSQL,
+		}
+	}
+	return inputs
+}
@@ -15,6 +15,7 @@ import (
 	"dbfartifactapi/models"
 	"dbfartifactapi/pkg/logger"
 	"dbfartifactapi/repository"
+	"dbfartifactapi/services/agent"
 	"dbfartifactapi/services/job"
 	"dbfartifactapi/services/privilege"
 	"dbfartifactapi/utils"
@@ -124,7 +125,7 @@ type groupListPolicy struct {
 
 // CreateOraclePrivilegeSessionCompletionHandler creates callback for Oracle privilege session job completion.
 // Handles both notification-based and VeloArtifact polling completion flows.
-func CreateOraclePrivilegeSessionCompletionHandler() job.JobCompletionCallback {
+func CreateOraclePrivilegeSessionCompletionHandler(agentExec agent.AgentExecutor) job.JobCompletionCallback {
 	return func(jobID string, jobInfo *job.JobInfo, statusResp *job.StatusResponse) error {
 		logger.Infof("Processing Oracle privilege session completion for job %s, status: %s", jobID, statusResp.Status)
 
@@ -136,7 +137,7 @@ func CreateOraclePrivilegeSessionCompletionHandler() job.JobCompletionCallback {
 
 		// Process completed jobs
 		if statusResp.Status == "completed" {
-			return processOraclePrivilegeSessionResults(jobID, contextData, statusResp, jobInfo)
+			return processOraclePrivilegeSessionResults(agentExec, jobID, contextData, statusResp, jobInfo)
 		}
 
 		logger.Errorf("Oracle privilege session job %s failed", jobID)
@@ -146,7 +147,7 @@ func CreateOraclePrivilegeSessionCompletionHandler() job.JobCompletionCallback {
 
 // processOraclePrivilegeSessionResults processes the results of Oracle privilege data loading job.
 // Routes to notification-based or VeloArtifact polling processing based on context.
-func processOraclePrivilegeSessionResults(jobID string, contextData interface{}, statusResp *job.StatusResponse, jobInfo *job.JobInfo) error {
+func processOraclePrivilegeSessionResults(agentExec agent.AgentExecutor, jobID string, contextData interface{}, statusResp *job.StatusResponse, jobInfo *job.JobInfo) error {
 	logger.Infof("Processing Oracle privilege session results for job %s - completed: %d, failed: %d",
 		jobID, statusResp.Completed, statusResp.Failed)
 
@@ -162,7 +163,7 @@ func processOraclePrivilegeSessionResults(jobID string, contextData interface{},
 	}
 
 	// Legacy VeloArtifact polling flow
-	return processOraclePrivilegeSessionFromVeloArtifact(jobID, sessionContext, statusResp)
+	return processOraclePrivilegeSessionFromVeloArtifact(agentExec, jobID, sessionContext, statusResp)
 }
 
 // processOraclePrivilegeSessionFromNotification handles Oracle privilege session processing from notification.
@@ -234,7 +235,7 @@ func processOraclePrivilegeSessionFromNotification(jobID string, sessionContext
 }
 
 // processOraclePrivilegeSessionFromVeloArtifact handles Oracle privilege session processing via VeloArtifact polling.
-func processOraclePrivilegeSessionFromVeloArtifact(jobID string, sessionContext *OraclePrivilegeSessionJobContext, statusResp *job.StatusResponse) error {
+func processOraclePrivilegeSessionFromVeloArtifact(agentExec agent.AgentExecutor, jobID string, sessionContext *OraclePrivilegeSessionJobContext, statusResp *job.StatusResponse) error {
 	logger.Infof("Processing Oracle privilege session from VeloArtifact polling for job %s", jobID)
 
 	jobMonitor := job.GetJobMonitorService()
@@ -247,7 +248,7 @@ func processOraclePrivilegeSessionFromVeloArtifact(jobID string, sessionContext
 	}
 
 	// Retrieve privilege data results
-	privilegeData, err := retrieveOraclePrivilegeDataResults(jobID, ep)
+	privilegeData, err := retrieveOraclePrivilegeDataResults(agentExec, jobID, ep)
 	if err != nil {
 		jobMonitor.FailJobAfterProcessing(jobID, err.Error())
 		return err
@@ -293,8 +294,8 @@ func parseOraclePrivilegeDataFile(filePath string) ([]privilege.QueryResult, err
 }
 
 // retrieveOraclePrivilegeDataResults gets Oracle privilege data from VeloArtifact.
-func retrieveOraclePrivilegeDataResults(jobID string, ep *models.Endpoint) ([]privilege.QueryResult, error) {
-	return privilege.RetrieveJobResults(jobID, ep)
+func retrieveOraclePrivilegeDataResults(agentExec agent.AgentExecutor, jobID string, ep *models.Endpoint) ([]privilege.QueryResult, error) {
+	return privilege.RetrieveJobResults(agentExec, jobID, ep)
 }
 
 // createOraclePoliciesWithPrivilegeData creates policies using three-pass execution strategy for Oracle databases.
@@ -309,6 +310,12 @@ func createOraclePoliciesWithPrivilegeData(jobID string, sessionContext *OracleP
 		return 0, nil
 	}
 
+	// Skip all processing when job was cancelled before its results arrived
+	jobMonitor := job.GetJobMonitorService()
+	if err := jobMonitor.CheckCancelled(jobID); err != nil {
+		return 0, err
+	}
+
 	logger.Infof("Creating Oracle policies from privilege data for job %s (conn_type=%s)",
 		jobID, sessionContext.ConnType.String())
 
@@ -318,9 +325,17 @@ func createOraclePoliciesWithPrivilegeData(jobID string, sessionContext *OracleP
 		return 0, fmt.Errorf("failed to parse oracle privilege results: %w", err)
 	}
 
-	logger.Infof("Parsed Oracle privilege data: sys_privs=%d, tab_privs=%d, role_privs=%d, pwfile_users=%d, cdb_sys_privs=%d",
+	logger.Infof("Parsed Oracle privilege data: sys_privs=%d, tab_privs=%d, role_privs=%d, pwfile_users=%d, cdb_sys_privs=%d, roles=%d",
 		len(oraclePrivData.SysPrivs), len(oraclePrivData.TabPrivs), len(oraclePrivData.RolePrivs),
-		len(oraclePrivData.PwFileUsers), len(oraclePrivData.CdbSysPrivs))
+		len(oraclePrivData.PwFileUsers), len(oraclePrivData.CdbSysPrivs), len(oraclePrivData.Roles))
+
+	// Expand PUBLIC and nested role grants so the three passes see effective privileges per actor
+	actorNames := make([]string, 0, len(sessionContext.DbActorMgts))
+	for _, actor := range sessionContext.DbActorMgts {
+		actorNames = append(actorNames, actor.DBUser)
+	}
+	expanded := oraclePrivData.ExpandRoleClosure(actorNames)
+	logger.Infof("Expanded Oracle role closure for %d actors: %d inherited rows added", len(actorNames), expanded)
 
 	// Create in-memory Oracle privilege session (uses go-mysql-server with Oracle tables)
 	ctx := context.Background()
@@ -339,7 +354,7 @@ func createOraclePoliciesWithPrivilegeData(jobID string, sessionContext *OracleP
 
 	// Create query log file for tracking (only if enabled)
 	var logFile *os.File
-	if config.Cfg.EnableMySQLPrivilegeQueryLogging {
+	if config.Current().EnableMySQLPrivilegeQueryLogging {
 		logFileName := fmt.Sprintf("oracle_privilege_queries_%s_%s.log", sessionContext.SessionID, time.Now().Format("20060102_150405"))
 		logFilePath := filepath.Join(config.Cfg.DBFWebTempDir, logFileName)
 		logFile, err = os.OpenFile(logFilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
@@ -385,12 +400,20 @@ func createOraclePoliciesWithPrivilegeData(jobID string, sessionContext *OracleP
 		if err != nil {
 			logger.Errorf("Failed to build oracle super privilege queries: %v", err)
 		} else {
-			superPolicies := executeOracleSuperPrivilegeQueries(tx, session, superQueries, service, sessionContext.CntMgtID, sessionContext.CMT, allowedResults, superPrivActors, logFile)
+			passStart := time.Now()
+			superPolicies := executeOracleSuperPrivilegeQueries(tx, session, superQueries, service, sessionContext.CntMgtID, sessionContext.CMT, allowedResults, superPrivActors, logFile,
+				jobMonitor.NewStepProgress(jobID, "Pass 1/3: super privilege queries", len(superQueries)))
+			privilege.ObservePass("oracle", privilege.PassSuper, len(superQueries), superPolicies, passStart)
 			totalPolicies += superPolicies
 			logger.Infof("Oracle Pass 1 completed: %d super policies created", superPolicies)
 		}
 	}
 
+	// Uncommitted policies from earlier passes are rolled back by the deferred tx.Rollback
+	if err := jobMonitor.CheckCancelled(jobID); err != nil {
+		return 0, err
+	}
+
 	// PASS 2: Action-wide privileges - grant specific action on ALL objects for ALL databases
 	if len(classification.actionWidePrivs) > 0 {
 		logger.Infof("Processing Oracle Pass 2: %d action-wide privilege templates", len(classification.actionWidePrivs))
@@ -401,12 +424,19 @@ func createOraclePoliciesWithPrivilegeData(jobID string, sessionContext *OracleP
 		if err != nil {
 			logger.Errorf("Failed to build oracle action-wide queries: %v", err)
 		} else {
-			actionPolicies := executeOracleActionWideQueries(tx, session, actionWideQueries, service, sessionContext.CntMgtID, sessionContext.CMT, grantedActions, allowedResults, superPrivActors, logFile)
+			passStart := time.Now()
+			actionPolicies := executeOracleActionWideQueries(tx, session, actionWideQueries, service, sessionContext.CntMgtID, sessionContext.CMT, grantedActions, allowedResults, superPrivActors, logFile,
+				jobMonitor.NewStepProgress(jobID, "Pass 2/3: action-wide queries", len(actionWideQueries)))
+			privilege.ObservePass("oracle", privilege.PassActionWide, len(actionWideQueries), actionPolicies, passStart)
 			totalPolicies += actionPolicies
 			logger.Infof("Oracle Pass 2 completed: %d action-wide policies created", actionPolicies)
 		}
 	}
 
+	if err := jobMonitor.CheckCancelled(jobID); err != nil {
+		return 0, err
+	}
+
 	// PASS 3: Object-specific privileges - skip if action already granted in Pass 2
 	if len(classification.objectSpecificPrivs) > 0 {
 		logger.Infof("Processing Oracle Pass 3: %d object-specific privilege templates", len(classification.objectSpecificPrivs))
@@ -439,7 +469,10 @@ func createOraclePoliciesWithPrivilegeData(jobID string, sessionContext *OracleP
 			allObjectQueries[k] = v
 		}
 
-		objectPolicies := executeOracleObjectSpecificQueries(tx, session, allObjectQueries, service, sessionContext.CntMgtID, grantedActions, allowedResults, superPrivActors, logFile)
+		passStart := time.Now()
+		objectPolicies := executeOracleObjectSpecificQueries(tx, session, allObjectQueries, service, sessionContext.CntMgtID, grantedActions, allowedResults, superPrivActors, logFile,
+			jobMonitor.NewStepProgress(jobID, "Pass 3/3: object-specific queries", len(allObjectQueries)))
+		privilege.ObservePass("oracle", privilege.PassObjectSpecific, len(allObjectQueries), objectPolicies, passStart)
 		totalPolicies += objectPolicies
 		logger.Infof("Oracle Pass 3 completed: %d object-specific policies created (general=%d, specific=%d queries)",
 			objectPolicies, len(generalQueries), len(specificQueries))
@@ -452,6 +485,18 @@ func createOraclePoliciesWithPrivilegeData(jobID string, sessionContext *OracleP
 		logger.Warnf("Failed to assign oracle actors to groups: %v", err)
 	}
 
+	privilege.CapturePrivilegeSnapshot(tx, sessionContext.CntMgtID, jobID, "oracle", privilegeData, sessionContext.DbActorMgts, allowedResults.actorPolicies)
+
+	// Last check before commit - cancellation during Pass 3 or group assignment rolls back everything
+	if err := jobMonitor.CheckCancelled(jobID); err != nil {
+		return 0, err
+	}
+
+	// Committed with the policies so a restart does not evaluate and insert them again
+	if err := jobMonitor.MarkCallbackCommitted(tx, jobID); err != nil {
+		return 0, err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return 0, fmt.Errorf("failed to commit oracle policies: %v", err)
 	}
@@ -871,6 +916,7 @@ func executeOracleSuperPrivilegeQueries(
 	allowedResults *allowedPolicyResults,
 	superPrivActors *superPrivilegeActors,
 	logFile *os.File,
+	progress *job.StepProgress,
 ) int {
 	type queryResult struct {
 		uniqueKey  string
@@ -914,6 +960,7 @@ func executeOracleSuperPrivilegeQueries(
 	allResults := make([]queryResult, 0, queryCount)
 	for i := 0; i < queryCount; i++ {
 		result := <-resultsChan
+		progress.Update(i + 1)
 		writeOracleQueryToLogFile(logFile, "PASS-1-SUPER", result.uniqueKey, result.finalSQL)
 		if result.err != nil {
 			logger.Debugf("Oracle super privilege query failed for key=%s: %v", result.uniqueKey, result.err)
@@ -964,6 +1011,7 @@ func executeOracleActionWideQueries(
 	allowedResults *allowedPolicyResults,
 	superPrivActors *superPrivilegeActors,
 	logFile *os.File,
+	progress *job.StepProgress,
 ) int {
 	type queryResult struct {
 		uniqueKey  string
@@ -1021,6 +1069,7 @@ func executeOracleActionWideQueries(
 	allResults := make([]queryResult, 0, queryCount)
 	for i := 0; i < queryCount; i++ {
 		result := <-resultsChan
+		progress.Update(i + 1)
 		writeOracleQueryToLogFile(logFile, "PASS-2-ACTION", result.uniqueKey, result.finalSQL)
 		if result.err != nil {
 			logger.Debugf("Oracle action-wide query failed for key=%s: %v", result.uniqueKey, result.err)
@@ -1065,6 +1114,7 @@ func executeOracleObjectSpecificQueries(
 	allowedResults *allowedPolicyResults,
 	superPrivActors *superPrivilegeActors,
 	logFile *os.File,
+	progress *job.StepProgress,
 ) int {
 	type queryResult struct {
 		uniqueKey  string
@@ -1125,6 +1175,7 @@ func executeOracleObjectSpecificQueries(
 	allResults := make([]queryResult, 0, queryCount)
 	for i := 0; i < queryCount; i++ {
 		result := <-resultsChan
+		progress.Update(i + 1)
 		writeOracleQueryToLogFile(logFile, "PASS-3-OBJECT", result.uniqueKey, result.finalSQL)
 		if result.err != nil {
 			logger.Debugf("Oracle object-specific query failed for key=%s: %v", result.uniqueKey, result.err)
@@ -1237,6 +1288,7 @@ func parseOraclePrivilegeResults(results []privilege.QueryResult, connType Oracl
 		RolePrivs:   []OracleRolePriv{},
 		PwFileUsers: []OraclePwFileUser{},
 		CdbSysPrivs: []OracleCdbSysPriv{},
+		Roles:       []OracleRole{},
 	}
 
 	for _, result := range results {
@@ -1294,6 +1346,14 @@ func parseOraclePrivilegeResults(results []privilege.QueryResult, connType Oracl
 				data.CdbSysPrivs = append(data.CdbSysPrivs, privs...)
 			}
 
+		case "dba_roles":
+			roles, err := parseDbaRoles(result.Result)
+			if err != nil {
+				logger.Warnf("Failed to parse dba_roles: %v", err)
+				continue
+			}
+			data.Roles = append(data.Roles, roles...)
+
 		default:
 			// Object queries (all_tables, all_views, etc.) - log but don't parse yet
 			logger.Debugf("Oracle object query result: %s with %d rows", queryKey, len(result.Result))
@@ -1448,6 +1508,31 @@ func parseCdbSysPrivs(rows [][]interface{}) ([]OracleCdbSysPriv, error) {
 	return privs, nil
 }
 
+// parseDbaRoles parses DBA_ROLES query results into OracleRole structs.
+func parseDbaRoles(rows [][]interface{}) ([]OracleRole, error) {
+	roles := make([]OracleRole, 0, len(rows))
+
+	columns, err := GetOraclePrivilegeColumnNames("dba_roles")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		if len(row) < len(columns) {
+			continue
+		}
+
+		role := OracleRole{
+			Role:               safeString(row[0]),
+			PasswordRequired:   safeString(row[1]),
+			AuthenticationType: safeString(row[2]),
+		}
+		roles = append(roles, role)
+	}
+
+	return roles, nil
+}
+
 // safeString converts interface{} to string safely.
 func safeString(v interface{}) string {
 	if v == nil {
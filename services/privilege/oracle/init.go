@@ -0,0 +1,20 @@
+package oracle
+
+import (
+	"dbfartifactapi/services/agent"
+	"dbfartifactapi/services/job"
+)
+
+// JobKindOraclePrivilegeSession identifies Oracle privilege session jobs in the job monitor.
+// Persisted with each job so the completion callback can be restored after an API restart.
+const JobKindOraclePrivilegeSession = "oracle_privilege_session"
+
+// RegisterJobKinds registers the completion callbacks of this package with the job monitor.
+// Called at startup once the agent executor is chosen, before persisted jobs are restored.
+func RegisterJobKinds(agentExec agent.AgentExecutor) {
+	job.RegisterJobKind(JobKindOraclePrivilegeSession, CreateOraclePrivilegeSessionCompletionHandler(agentExec))
+}
+
+func init() {
+	job.RegisterContextType("oracle_privilege_session_context", &OraclePrivilegeSessionJobContext{})
+}
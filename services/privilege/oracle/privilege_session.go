@@ -100,7 +100,8 @@ func getFreePort() (int, error) {
 
 // createOraclePrivilegeTables creates Oracle privilege tables with flexible schemas for in-memory privilege analysis.
 // Uses TEXT type for all columns to avoid strict type checking and allow any Oracle privilege data.
-// Tables: DBA_SYS_PRIVS, DBA_TAB_PRIVS, DBA_ROLE_PRIVS, V_PWFILE_USERS, CDB_SYS_PRIVS, DBA_TS_QUOTAS.
+// Tables: DBA_SYS_PRIVS, DBA_TAB_PRIVS, DBA_ROLE_PRIVS, V_PWFILE_USERS, CDB_SYS_PRIVS, DBA_ROLES, DBA_TS_QUOTAS.
+// GRANTED_VIA is not an Oracle column: it holds the role path of rows added by ExpandRoleClosure.
 func createOraclePrivilegeTables(oracleDB *memory.Database) error {
 	// DBA_SYS_PRIVS - System privileges
 	dbaSysPrivsSchema := sql.NewPrimaryKeySchema(sql.Schema{
@@ -109,6 +110,7 @@ func createOraclePrivilegeTables(oracleDB *memory.Database) error {
 		{Name: "ADMIN_OPTION", Type: types.Text, Source: "DBA_SYS_PRIVS"},
 		{Name: "COMMON", Type: types.Text, Source: "DBA_SYS_PRIVS"},
 		{Name: "INHERITED", Type: types.Text, Source: "DBA_SYS_PRIVS"},
+		{Name: "GRANTED_VIA", Type: types.Text, Source: "DBA_SYS_PRIVS", Nullable: true},
 	})
 	dbaSysPrivsTable := memory.NewTable(oracleDB, "DBA_SYS_PRIVS", dbaSysPrivsSchema, oracleDB.GetForeignKeyCollection())
 	oracleDB.AddTable("DBA_SYS_PRIVS", dbaSysPrivsTable)
@@ -125,6 +127,7 @@ func createOraclePrivilegeTables(oracleDB *memory.Database) error {
 		{Name: "COMMON", Type: types.Text, Source: "DBA_TAB_PRIVS"},
 		{Name: "TYPE", Type: types.Text, Source: "DBA_TAB_PRIVS"},
 		{Name: "INHERITED", Type: types.Text, Source: "DBA_TAB_PRIVS"},
+		{Name: "GRANTED_VIA", Type: types.Text, Source: "DBA_TAB_PRIVS", Nullable: true},
 	})
 	dbaTabPrivsTable := memory.NewTable(oracleDB, "DBA_TAB_PRIVS", dbaTabPrivsSchema, oracleDB.GetForeignKeyCollection())
 	oracleDB.AddTable("DBA_TAB_PRIVS", dbaTabPrivsTable)
@@ -138,6 +141,7 @@ func createOraclePrivilegeTables(oracleDB *memory.Database) error {
 		{Name: "DEFAULT_ROLE", Type: types.Text, Source: "DBA_ROLE_PRIVS"},
 		{Name: "COMMON", Type: types.Text, Source: "DBA_ROLE_PRIVS"},
 		{Name: "INHERITED", Type: types.Text, Source: "DBA_ROLE_PRIVS"},
+		{Name: "GRANTED_VIA", Type: types.Text, Source: "DBA_ROLE_PRIVS", Nullable: true},
 	})
 	dbaRolePrivsTable := memory.NewTable(oracleDB, "DBA_ROLE_PRIVS", dbaRolePrivsSchema, oracleDB.GetForeignKeyCollection())
 	oracleDB.AddTable("DBA_ROLE_PRIVS", dbaRolePrivsTable)
@@ -163,10 +167,20 @@ func createOraclePrivilegeTables(oracleDB *memory.Database) error {
 		{Name: "COMMON", Type: types.Text, Source: "CDB_SYS_PRIVS"},
 		{Name: "INHERITED", Type: types.Text, Source: "CDB_SYS_PRIVS"},
 		{Name: "CON_ID", Type: types.Text, Source: "CDB_SYS_PRIVS", Nullable: false, PrimaryKey: true},
+		{Name: "GRANTED_VIA", Type: types.Text, Source: "CDB_SYS_PRIVS", Nullable: true},
 	})
 	cdbSysPrivsTable := memory.NewTable(oracleDB, "CDB_SYS_PRIVS", cdbSysPrivsSchema, oracleDB.GetForeignKeyCollection())
 	oracleDB.AddTable("CDB_SYS_PRIVS", cdbSysPrivsTable)
 
+	// DBA_ROLES - Authentication of roles reachable from the actors
+	dbaRolesSchema := sql.NewPrimaryKeySchema(sql.Schema{
+		{Name: "ROLE", Type: types.Text, Source: "DBA_ROLES", Nullable: false, PrimaryKey: true},
+		{Name: "PASSWORD_REQUIRED", Type: types.Text, Source: "DBA_ROLES"},
+		{Name: "AUTHENTICATION_TYPE", Type: types.Text, Source: "DBA_ROLES"},
+	})
+	dbaRolesTable := memory.NewTable(oracleDB, "DBA_ROLES", dbaRolesSchema, oracleDB.GetForeignKeyCollection())
+	oracleDB.AddTable("DBA_ROLES", dbaRolesTable)
+
 	// DBA_TS_QUOTAS - Tablespace quotas for users
 	dbaTsQuotasSchema := sql.NewPrimaryKeySchema(sql.Schema{
 		{Name: "TABLESPACE_NAME", Type: types.Text, Source: "DBA_TS_QUOTAS", Nullable: false, PrimaryKey: true},
@@ -270,9 +284,9 @@ func (s *PrivilegeSession) LoadOraclePrivilegeDataFromResults(privilegeData *Ora
 	// Load DBA_SYS_PRIVS
 	for _, priv := range privilegeData.SysPrivs {
 		insertSQL := fmt.Sprintf(
-			"INSERT INTO DBA_SYS_PRIVS (GRANTEE, PRIVILEGE, ADMIN_OPTION, COMMON, INHERITED) VALUES ('%s', '%s', '%s', '%s', '%s')",
+			"INSERT INTO DBA_SYS_PRIVS (GRANTEE, PRIVILEGE, ADMIN_OPTION, COMMON, INHERITED, GRANTED_VIA) VALUES ('%s', '%s', '%s', '%s', '%s', '%s')",
 			utils.EscapeSQL(priv.Grantee), utils.EscapeSQL(priv.Privilege), utils.EscapeSQL(priv.AdminOption),
-			utils.EscapeSQL(priv.Common), utils.EscapeSQL(priv.Inherited))
+			utils.EscapeSQL(priv.Common), utils.EscapeSQL(priv.Inherited), utils.EscapeSQL(priv.GrantedVia))
 
 		if _, _, _, err := s.engine.Query(ctx, insertSQL); err != nil {
 			logger.Warnf("Failed to insert into DBA_SYS_PRIVS: %v", err)
@@ -283,10 +297,11 @@ func (s *PrivilegeSession) LoadOraclePrivilegeDataFromResults(privilegeData *Ora
 	// Load DBA_TAB_PRIVS
 	for _, priv := range privilegeData.TabPrivs {
 		insertSQL := fmt.Sprintf(
-			"INSERT INTO DBA_TAB_PRIVS (GRANTEE, OWNER, TABLE_NAME, GRANTOR, PRIVILEGE, GRANTABLE, HIERARCHY, COMMON, TYPE, INHERITED) VALUES ('%s', '%s', '%s', '%s', '%s', '%s', '%s', '%s', '%s', '%s')",
+			"INSERT INTO DBA_TAB_PRIVS (GRANTEE, OWNER, TABLE_NAME, GRANTOR, PRIVILEGE, GRANTABLE, HIERARCHY, COMMON, TYPE, INHERITED, GRANTED_VIA) VALUES ('%s', '%s', '%s', '%s', '%s', '%s', '%s', '%s', '%s', '%s', '%s')",
 			utils.EscapeSQL(priv.Grantee), utils.EscapeSQL(priv.Owner), utils.EscapeSQL(priv.TableName),
 			utils.EscapeSQL(priv.Grantor), utils.EscapeSQL(priv.Privilege), utils.EscapeSQL(priv.Grantable),
-			utils.EscapeSQL(priv.Hierarchy), utils.EscapeSQL(priv.Common), utils.EscapeSQL(priv.Type), utils.EscapeSQL(priv.Inherited))
+			utils.EscapeSQL(priv.Hierarchy), utils.EscapeSQL(priv.Common), utils.EscapeSQL(priv.Type), utils.EscapeSQL(priv.Inherited),
+			utils.EscapeSQL(priv.GrantedVia))
 
 		if _, _, _, err := s.engine.Query(ctx, insertSQL); err != nil {
 			logger.Warnf("Failed to insert into DBA_TAB_PRIVS: %v", err)
@@ -297,9 +312,10 @@ func (s *PrivilegeSession) LoadOraclePrivilegeDataFromResults(privilegeData *Ora
 	// Load DBA_ROLE_PRIVS
 	for _, priv := range privilegeData.RolePrivs {
 		insertSQL := fmt.Sprintf(
-			"INSERT INTO DBA_ROLE_PRIVS (GRANTEE, GRANTED_ROLE, ADMIN_OPTION, DELEGATE_OPTION, DEFAULT_ROLE, COMMON, INHERITED) VALUES ('%s', '%s', '%s', '%s', '%s', '%s', '%s')",
+			"INSERT INTO DBA_ROLE_PRIVS (GRANTEE, GRANTED_ROLE, ADMIN_OPTION, DELEGATE_OPTION, DEFAULT_ROLE, COMMON, INHERITED, GRANTED_VIA) VALUES ('%s', '%s', '%s', '%s', '%s', '%s', '%s', '%s')",
 			utils.EscapeSQL(priv.Grantee), utils.EscapeSQL(priv.GrantedRole), utils.EscapeSQL(priv.AdminOption),
-			utils.EscapeSQL(priv.DelegateOpt), utils.EscapeSQL(priv.DefaultRole), utils.EscapeSQL(priv.Common), utils.EscapeSQL(priv.Inherited))
+			utils.EscapeSQL(priv.DelegateOpt), utils.EscapeSQL(priv.DefaultRole), utils.EscapeSQL(priv.Common), utils.EscapeSQL(priv.Inherited),
+			utils.EscapeSQL(priv.GrantedVia))
 
 		if _, _, _, err := s.engine.Query(ctx, insertSQL); err != nil {
 			logger.Warnf("Failed to insert into DBA_ROLE_PRIVS: %v", err)
@@ -323,9 +339,9 @@ func (s *PrivilegeSession) LoadOraclePrivilegeDataFromResults(privilegeData *Ora
 	// Load CDB_SYS_PRIVS
 	for _, priv := range privilegeData.CdbSysPrivs {
 		insertSQL := fmt.Sprintf(
-			"INSERT INTO CDB_SYS_PRIVS (GRANTEE, PRIVILEGE, ADMIN_OPTION, COMMON, INHERITED, CON_ID) VALUES ('%s', '%s', '%s', '%s', '%s', '%d')",
+			"INSERT INTO CDB_SYS_PRIVS (GRANTEE, PRIVILEGE, ADMIN_OPTION, COMMON, INHERITED, CON_ID, GRANTED_VIA) VALUES ('%s', '%s', '%s', '%s', '%s', '%d', '%s')",
 			utils.EscapeSQL(priv.Grantee), utils.EscapeSQL(priv.Privilege), utils.EscapeSQL(priv.AdminOption),
-			utils.EscapeSQL(priv.Common), utils.EscapeSQL(priv.Inherited), priv.ConID)
+			utils.EscapeSQL(priv.Common), utils.EscapeSQL(priv.Inherited), priv.ConID, utils.EscapeSQL(priv.GrantedVia))
 
 		if _, _, _, err := s.engine.Query(ctx, insertSQL); err != nil {
 			logger.Warnf("Failed to insert into CDB_SYS_PRIVS: %v", err)
@@ -333,6 +349,18 @@ func (s *PrivilegeSession) LoadOraclePrivilegeDataFromResults(privilegeData *Ora
 	}
 	logger.Debugf("Loaded %d rows into CDB_SYS_PRIVS", len(privilegeData.CdbSysPrivs))
 
+	// Load DBA_ROLES
+	for _, role := range privilegeData.Roles {
+		insertSQL := fmt.Sprintf(
+			"INSERT INTO DBA_ROLES (ROLE, PASSWORD_REQUIRED, AUTHENTICATION_TYPE) VALUES ('%s', '%s', '%s')",
+			utils.EscapeSQL(role.Role), utils.EscapeSQL(role.PasswordRequired), utils.EscapeSQL(role.AuthenticationType))
+
+		if _, _, _, err := s.engine.Query(ctx, insertSQL); err != nil {
+			logger.Warnf("Failed to insert into DBA_ROLES: %v", err)
+		}
+	}
+	logger.Debugf("Loaded %d rows into DBA_ROLES", len(privilegeData.Roles))
+
 	logger.Infof("Loaded Oracle privilege data into temporary server for session %s", s.sessionID)
 	return nil
 }
@@ -28,6 +28,9 @@ func GetOraclePrivilegeColumnNames(tableName string) ([]string, error) {
 		"cdb_sys_privs": {
 			"GRANTEE", "PRIVILEGE", "ADMIN_OPTION", "COMMON", "INHERITED", "CON_ID",
 		},
+		"dba_roles": {
+			"ROLE", "PASSWORD_REQUIRED", "AUTHENTICATION_TYPE",
+		},
 	}
 
 	columns, ok := columnMap[tableName]
@@ -43,8 +46,18 @@ func EscapeOracleSQL(s string) string {
 	return strings.ReplaceAll(s, "'", "''")
 }
 
+// oracleRoleClosure returns a subquery listing every role reachable from the given grantees through DBA_ROLE_PRIVS.
+// CONNECT BY NOCYCLE walks nested grants (user -> ROLE_A -> ROLE_B) and tolerates circular role grants.
+func oracleRoleClosure(granteeFilter string) string {
+	return fmt.Sprintf(`SELECT GRANTED_ROLE FROM DBA_ROLE_PRIVS
+				START WITH GRANTEE IN (%s)
+				CONNECT BY NOCYCLE PRIOR GRANTED_ROLE = GRANTEE`, granteeFilter)
+}
+
 // BuildOraclePrivilegeDataQueries builds queries to fetch privilege data from Oracle system tables.
 // Queries adapt based on connection type: CDB queries include CDB_SYS_PRIVS, PDB queries use DBA views.
+// Grants are fetched for the actors, PUBLIC and the transitive closure of their roles so nested role
+// privileges can be expanded by ExpandRoleClosure before the policy passes run.
 // Returns map of query keys to SQL statements for dbfAgentAPI execution.
 func BuildOraclePrivilegeDataQueries(
 	actors []ActorInfo,
@@ -61,6 +74,18 @@ func BuildOraclePrivilegeDataQueries(
 	}
 	actorFilter := strings.Join(actorNames, ", ")
 
+	// Roots of the role graph: the actors themselves plus PUBLIC, whose grants apply to every user
+	rootFilter := actorFilter + ", 'PUBLIC'"
+	roleClosure := oracleRoleClosure(rootFilter)
+	granteeFilter := fmt.Sprintf("GRANTEE IN (%s) OR GRANTEE IN (%s)", rootFilter, roleClosure)
+
+	// Object grants reached through PUBLIC or roles are limited to non Oracle-maintained schemas;
+	// PUBLIC and catalog roles hold tens of thousands of grants on SYS objects that policies never target
+	tabPrivFilter := fmt.Sprintf(`GRANTEE IN (%s)
+			OR ((GRANTEE = 'PUBLIC' OR GRANTEE IN (%s))
+				AND OWNER NOT IN (SELECT USERNAME FROM DBA_USERS WHERE ORACLE_MAINTAINED = 'Y'))`,
+		actorFilter, roleClosure)
+
 	queries := make(map[string][]string)
 
 	// DBA_SYS_PRIVS - System privileges granted to users/roles
@@ -69,7 +94,7 @@ func BuildOraclePrivilegeDataQueries(
 			NVL(COMMON, 'NO') AS COMMON,
 			NVL(INHERITED, 'NO') AS INHERITED
 			FROM DBA_SYS_PRIVS
-			WHERE GRANTEE IN (%s)`, actorFilter),
+			WHERE %s`, granteeFilter),
 	}
 
 	// DBA_TAB_PRIVS - Object privileges on tables, views, procedures, etc.
@@ -80,10 +105,10 @@ func BuildOraclePrivilegeDataQueries(
 			NVL(TYPE, 'TABLE') AS TYPE,
 			NVL(INHERITED, 'NO') AS INHERITED
 			FROM DBA_TAB_PRIVS
-			WHERE GRANTEE IN (%s)`, actorFilter),
+			WHERE %s`, tabPrivFilter),
 	}
 
-	// DBA_ROLE_PRIVS - Roles granted to users
+	// DBA_ROLE_PRIVS - Role grants to users, PUBLIC and every reachable role (edges of the role graph)
 	queries["dba_role_privs"] = []string{
 		fmt.Sprintf(`SELECT GRANTEE, GRANTED_ROLE, ADMIN_OPTION,
 			NVL(DELEGATE_OPTION, 'NO') AS DELEGATE_OPTION,
@@ -91,7 +116,17 @@ func BuildOraclePrivilegeDataQueries(
 			NVL(COMMON, 'NO') AS COMMON,
 			NVL(INHERITED, 'NO') AS INHERITED
 			FROM DBA_ROLE_PRIVS
-			WHERE GRANTEE IN (%s)`, actorFilter),
+			WHERE %s`, granteeFilter),
+	}
+
+	// DBA_ROLES - Authentication of reachable roles; password and application roles
+	// cannot be enabled with a plain SET ROLE, so they only count when enabled by default
+	queries["dba_roles"] = []string{
+		fmt.Sprintf(`SELECT ROLE,
+			NVL(PASSWORD_REQUIRED, 'NO') AS PASSWORD_REQUIRED,
+			NVL(AUTHENTICATION_TYPE, 'NONE') AS AUTHENTICATION_TYPE
+			FROM DBA_ROLES
+			WHERE ROLE IN (%s)`, roleClosure),
 	}
 
 	// V$PWFILE_USERS - Password file administrative privileges
@@ -117,7 +152,7 @@ func BuildOraclePrivilegeDataQueries(
 				NVL(INHERITED, 'NO') AS INHERITED,
 				CON_ID
 				FROM CDB_SYS_PRIVS
-				WHERE GRANTEE IN (%s)`, actorFilter),
+				WHERE %s`, granteeFilter),
 		}
 	}
 
@@ -0,0 +1,182 @@
+package oracle
+
+import (
+	"strings"
+)
+
+// oracleRoleVia separates role names in GrantedVia paths (e.g. "APP_READ > REPORTING").
+const oracleRoleVia = " > "
+
+// oracleReachableGrantee is a grantee whose privileges an actor holds, with the role path leading to it.
+type oracleReachableGrantee struct {
+	name  string
+	path  []string
+	edge  OracleRolePriv // last DBA_ROLE_PRIVS edge of the path, zero for PUBLIC
+	first OracleRolePriv // first edge of the path, decides whether the role is enabled at login
+}
+
+// isProtectedRole reports whether a role needs a password, package or external source to be enabled.
+func isProtectedRole(role OracleRole) bool {
+	passwordRequired := strings.ToUpper(strings.TrimSpace(role.PasswordRequired))
+	authType := strings.ToUpper(strings.TrimSpace(role.AuthenticationType))
+	return (passwordRequired != "" && passwordRequired != "NO") || (authType != "" && authType != "NONE")
+}
+
+// reachableGrantees walks the role graph from actor and PUBLIC in breadth-first order.
+// A role granted directly counts when it is a default role or the user can enable it with a plain SET ROLE;
+// non-default password, application, external and global roles are skipped together with everything below them.
+// Roles granted to an enabled role are always enabled with it.
+func reachableGrantees(actor string, edges map[string][]OracleRolePriv, protected map[string]bool) []oracleReachableGrantee {
+	visited := map[string]bool{strings.ToUpper(actor): true}
+	var reachable []oracleReachableGrantee
+	var queue []oracleReachableGrantee
+
+	enqueue := func(parent oracleReachableGrantee, edge OracleRolePriv, firstHop bool) {
+		key := strings.ToUpper(edge.GrantedRole)
+		if visited[key] {
+			return
+		}
+		if firstHop && !strings.EqualFold(edge.DefaultRole, "YES") && protected[key] {
+			return
+		}
+		visited[key] = true
+
+		next := oracleReachableGrantee{
+			name:  edge.GrantedRole,
+			path:  append(append([]string{}, parent.path...), edge.GrantedRole),
+			edge:  edge,
+			first: parent.first,
+		}
+		if firstHop {
+			next.first = edge
+		}
+		reachable = append(reachable, next)
+		queue = append(queue, next)
+	}
+
+	for _, edge := range edges[strings.ToUpper(actor)] {
+		enqueue(oracleReachableGrantee{}, edge, true)
+	}
+
+	// Grants to PUBLIC apply to every user, including the roles PUBLIC holds
+	if !visited["PUBLIC"] {
+		visited["PUBLIC"] = true
+		public := oracleReachableGrantee{name: "PUBLIC", path: []string{"PUBLIC"}}
+		reachable = append(reachable, public)
+		for _, edge := range edges["PUBLIC"] {
+			enqueue(public, edge, true)
+		}
+	}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, edge := range edges[strings.ToUpper(current.name)] {
+			enqueue(current, edge, false)
+		}
+	}
+
+	return reachable
+}
+
+// ExpandRoleClosure adds the privileges each actor holds through PUBLIC and nested roles as rows granted
+// to the actor itself, so policy templates filtering on GRANTEE see effective privileges.
+// Direct grants win over expanded ones; expanded rows carry the role path in GrantedVia.
+// Returns the number of rows added.
+func (d *OraclePrivilegeData) ExpandRoleClosure(actors []string) int {
+	edges := make(map[string][]OracleRolePriv)
+	for _, rp := range d.RolePrivs {
+		key := strings.ToUpper(rp.Grantee)
+		edges[key] = append(edges[key], rp)
+	}
+	protected := make(map[string]bool, len(d.Roles))
+	for _, role := range d.Roles {
+		protected[strings.ToUpper(role.Role)] = isProtectedRole(role)
+	}
+
+	sysByGrantee := make(map[string][]OracleSysPriv)
+	seenSys := make(map[string]bool)
+	for _, p := range d.SysPrivs {
+		key := strings.ToUpper(p.Grantee)
+		sysByGrantee[key] = append(sysByGrantee[key], p)
+		seenSys[key+"|"+strings.ToUpper(p.Privilege)] = true
+	}
+	tabByGrantee := make(map[string][]OracleTabPriv)
+	seenTab := make(map[string]bool)
+	for _, p := range d.TabPrivs {
+		key := strings.ToUpper(p.Grantee)
+		tabByGrantee[key] = append(tabByGrantee[key], p)
+		seenTab[strings.ToUpper(strings.Join([]string{p.Grantee, p.Owner, p.TableName, p.Privilege}, "|"))] = true
+	}
+	cdbByGrantee := make(map[string][]OracleCdbSysPriv)
+	seenCdb := make(map[string]bool)
+	for _, p := range d.CdbSysPrivs {
+		key := strings.ToUpper(p.Grantee)
+		cdbByGrantee[key] = append(cdbByGrantee[key], p)
+		seenCdb[strings.ToUpper(strings.Join([]string{p.Grantee, p.Privilege}, "|"))+"|"+safeString(p.ConID)] = true
+	}
+	seenRole := make(map[string]bool)
+	for _, rp := range d.RolePrivs {
+		seenRole[strings.ToUpper(rp.Grantee+"|"+rp.GrantedRole)] = true
+	}
+
+	added := 0
+	expandedActors := make(map[string]bool, len(actors))
+	for _, actor := range actors {
+		actorKey := strings.ToUpper(actor)
+		if actor == "" || actorKey == "PUBLIC" || expandedActors[actorKey] {
+			continue
+		}
+		expandedActors[actorKey] = true
+
+		for _, g := range reachableGrantees(actor, edges, protected) {
+			via := strings.Join(g.path, oracleRoleVia)
+			granteeKey := strings.ToUpper(g.name)
+
+			if g.edge.GrantedRole != "" && !seenRole[actorKey+"|"+granteeKey] {
+				seenRole[actorKey+"|"+granteeKey] = true
+				rp := g.edge
+				rp.Grantee = actor
+				rp.DefaultRole = g.first.DefaultRole
+				rp.GrantedVia = strings.Join(g.path[:len(g.path)-1], oracleRoleVia)
+				d.RolePrivs = append(d.RolePrivs, rp)
+				added++
+			}
+
+			for _, p := range sysByGrantee[granteeKey] {
+				key := actorKey + "|" + strings.ToUpper(p.Privilege)
+				if seenSys[key] {
+					continue
+				}
+				seenSys[key] = true
+				p.Grantee, p.GrantedVia = actor, via
+				d.SysPrivs = append(d.SysPrivs, p)
+				added++
+			}
+
+			for _, p := range tabByGrantee[granteeKey] {
+				key := strings.ToUpper(strings.Join([]string{actor, p.Owner, p.TableName, p.Privilege}, "|"))
+				if seenTab[key] {
+					continue
+				}
+				seenTab[key] = true
+				p.Grantee, p.GrantedVia = actor, via
+				d.TabPrivs = append(d.TabPrivs, p)
+				added++
+			}
+
+			for _, p := range cdbByGrantee[granteeKey] {
+				key := strings.ToUpper(strings.Join([]string{actor, p.Privilege}, "|")) + "|" + safeString(p.ConID)
+				if seenCdb[key] {
+					continue
+				}
+				seenCdb[key] = true
+				p.Grantee, p.GrantedVia = actor, via
+				d.CdbSysPrivs = append(d.CdbSysPrivs, p)
+				added++
+			}
+		}
+	}
+
+	return added
+}
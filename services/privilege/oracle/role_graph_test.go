@@ -0,0 +1,105 @@
+package oracle
+
+import "testing"
+
+// TestExpandRoleClosure_NestedRoles tests that privileges of nested and PUBLIC roles are granted to the actor
+func TestExpandRoleClosure_NestedRoles(t *testing.T) {
+	data := &OraclePrivilegeData{
+		RolePrivs: []OracleRolePriv{
+			{Grantee: "SCOTT", GrantedRole: "ROLE_A", DefaultRole: "YES"},
+			{Grantee: "ROLE_A", GrantedRole: "ROLE_B", DefaultRole: "YES"},
+			{Grantee: "ROLE_B", GrantedRole: "ROLE_A", DefaultRole: "YES"},
+			{Grantee: "PUBLIC", GrantedRole: "PUB_ROLE", DefaultRole: "YES"},
+		},
+		SysPrivs: []OracleSysPriv{
+			{Grantee: "ROLE_B", Privilege: "SELECT ANY TABLE"},
+			{Grantee: "SCOTT", Privilege: "CREATE SESSION"},
+			{Grantee: "ROLE_A", Privilege: "CREATE SESSION", AdminOption: "YES"},
+			{Grantee: "PUB_ROLE", Privilege: "CREATE VIEW"},
+		},
+		TabPrivs: []OracleTabPriv{
+			{Grantee: "PUBLIC", Owner: "HR", TableName: "COUNTRIES", Privilege: "SELECT"},
+		},
+	}
+
+	data.ExpandRoleClosure([]string{"SCOTT"})
+
+	sys := make(map[string]OracleSysPriv)
+	for _, p := range data.SysPrivs {
+		if p.Grantee == "SCOTT" {
+			sys[p.Privilege] = p
+		}
+	}
+	if got := sys["SELECT ANY TABLE"].GrantedVia; got != "ROLE_A > ROLE_B" {
+		t.Errorf("SELECT ANY TABLE via = %q, want ROLE_A > ROLE_B", got)
+	}
+	if got := sys["CREATE SESSION"]; got.GrantedVia != "" || got.AdminOption != "" {
+		t.Errorf("CREATE SESSION = %+v, want the direct grant kept", got)
+	}
+	if got := sys["CREATE VIEW"].GrantedVia; got != "PUBLIC > PUB_ROLE" {
+		t.Errorf("CREATE VIEW via = %q, want PUBLIC > PUB_ROLE", got)
+	}
+
+	var publicTab bool
+	for _, p := range data.TabPrivs {
+		if p.Grantee == "SCOTT" && p.TableName == "COUNTRIES" && p.GrantedVia == "PUBLIC" {
+			publicTab = true
+		}
+	}
+	if !publicTab {
+		t.Error("object grant to PUBLIC was not expanded to SCOTT")
+	}
+
+	var nestedRole bool
+	for _, rp := range data.RolePrivs {
+		if rp.Grantee == "SCOTT" && rp.GrantedRole == "ROLE_B" {
+			nestedRole = rp.GrantedVia == "ROLE_A" && rp.DefaultRole == "YES"
+		}
+	}
+	if !nestedRole {
+		t.Error("nested role ROLE_B was not granted to SCOTT via ROLE_A")
+	}
+}
+
+// TestExpandRoleClosure_ProtectedRoles tests that non-default password and application roles are not enabled
+func TestExpandRoleClosure_ProtectedRoles(t *testing.T) {
+	data := &OraclePrivilegeData{
+		RolePrivs: []OracleRolePriv{
+			{Grantee: "APP", GrantedRole: "PW_ROLE", DefaultRole: "NO"},
+			{Grantee: "APP", GrantedRole: "SEC_APP_ROLE", DefaultRole: "NO"},
+			{Grantee: "APP", GrantedRole: "PLAIN_ROLE", DefaultRole: "NO"},
+			{Grantee: "APP", GrantedRole: "PW_DEFAULT", DefaultRole: "YES"},
+		},
+		Roles: []OracleRole{
+			{Role: "PW_ROLE", PasswordRequired: "YES", AuthenticationType: "PASSWORD"},
+			{Role: "SEC_APP_ROLE", PasswordRequired: "NO", AuthenticationType: "APPLICATION"},
+			{Role: "PLAIN_ROLE", PasswordRequired: "NO", AuthenticationType: "NONE"},
+			{Role: "PW_DEFAULT", PasswordRequired: "YES", AuthenticationType: "PASSWORD"},
+		},
+		SysPrivs: []OracleSysPriv{
+			{Grantee: "PW_ROLE", Privilege: "DROP ANY TABLE"},
+			{Grantee: "SEC_APP_ROLE", Privilege: "ALTER ANY TABLE"},
+			{Grantee: "PLAIN_ROLE", Privilege: "CREATE TABLE"},
+			{Grantee: "PW_DEFAULT", Privilege: "CREATE PROCEDURE"},
+		},
+	}
+
+	data.ExpandRoleClosure([]string{"APP"})
+
+	got := make(map[string]bool)
+	for _, p := range data.SysPrivs {
+		if p.Grantee == "APP" {
+			got[p.Privilege] = true
+		}
+	}
+	for privilege, want := range map[string]bool{
+		"DROP ANY TABLE":   false,
+		"ALTER ANY TABLE":  false,
+		"CREATE TABLE":     true,
+		"CREATE PROCEDURE": true,
+	} {
+		if got[privilege] != want {
+			t.Errorf("APP has %s = %v, want %v", privilege, got[privilege], want)
+		}
+	}
+}
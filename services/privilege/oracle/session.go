@@ -13,6 +13,7 @@ type OraclePrivilegeData struct {
 	PwFileUsers []OraclePwFileUser // From V$PWFILE_USERS
 	RolePrivs   []OracleRolePriv   // From DBA_ROLE_PRIVS
 	CdbSysPrivs []OracleCdbSysPriv // From CDB_SYS_PRIVS (CDB only)
+	Roles       []OracleRole       // From DBA_ROLES (reachable roles only)
 }
 
 // OracleSysPriv represents a system privilege grant from DBA_SYS_PRIVS.
@@ -23,21 +24,23 @@ type OracleSysPriv struct {
 	AdminOption string // YES if grantee can grant to others
 	Common      string // YES for CDB-level grant, NO for local
 	Inherited   string // YES if inherited from CDB
+	GrantedVia  string // Role path the grant was expanded through, empty for direct grants
 }
 
 // OracleTabPriv represents an object privilege grant from DBA_TAB_PRIVS.
 // Object privileges control access to specific database objects.
 type OracleTabPriv struct {
-	Grantee   string // User or role receiving the privilege
-	Owner     string // Schema owner of the object
-	TableName string // Object name (table, view, procedure, etc.)
-	Grantor   string // User who granted the privilege
-	Privilege string // Object privilege (SELECT, INSERT, UPDATE, DELETE, EXECUTE, etc.)
-	Grantable string // YES if grantee can grant to others
-	Hierarchy string // YES for hierarchy option
-	Common    string // YES for CDB-level grant
-	Type      string // Object type (TABLE, VIEW, SEQUENCE, PROCEDURE, etc.)
-	Inherited string // YES if inherited from CDB
+	Grantee    string // User or role receiving the privilege
+	Owner      string // Schema owner of the object
+	TableName  string // Object name (table, view, procedure, etc.)
+	Grantor    string // User who granted the privilege
+	Privilege  string // Object privilege (SELECT, INSERT, UPDATE, DELETE, EXECUTE, etc.)
+	Grantable  string // YES if grantee can grant to others
+	Hierarchy  string // YES for hierarchy option
+	Common     string // YES for CDB-level grant
+	Type       string // Object type (TABLE, VIEW, SEQUENCE, PROCEDURE, etc.)
+	Inherited  string // YES if inherited from CDB
+	GrantedVia string // Role path the grant was expanded through, empty for direct grants
 }
 
 // OraclePwFileUser represents password file user privileges from V$PWFILE_USERS.
@@ -62,6 +65,7 @@ type OracleRolePriv struct {
 	DefaultRole string // YES if role is enabled by default
 	Common      string // YES for CDB-level grant
 	Inherited   string // YES if inherited from CDB
+	GrantedVia  string // Role path the grant was expanded through, empty for direct grants
 }
 
 // OracleCdbSysPriv represents CDB-wide system privileges from CDB_SYS_PRIVS.
@@ -74,6 +78,15 @@ type OracleCdbSysPriv struct {
 	Common      string // YES for common privilege
 	Inherited   string // YES if inherited
 	ConID       int    // Container ID (1=CDB$ROOT, 3+=PDB)
+	GrantedVia  string // Role path the grant was expanded through, empty for direct grants
+}
+
+// OracleRole represents role authentication from DBA_ROLES.
+// Password, application, external and global roles cannot be enabled with a plain SET ROLE.
+type OracleRole struct {
+	Role               string // Role name
+	PasswordRequired   string // YES, NO, GLOBAL or EXTERNAL
+	AuthenticationType string // NONE, PASSWORD, APPLICATION, EXTERNAL or GLOBAL
 }
 
 // OraclePrivilegeSessionJobContext contains context data for Oracle privilege session job completion.
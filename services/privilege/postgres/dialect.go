@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"regexp"
+
+	"dbfartifactapi/models"
+	"dbfartifactapi/services/privilege"
+)
+
+const (
+	// postgresSuperPrivilegePolicyID is the policy default evaluated in Pass 1 (MySQL uses 1, Oracle 1001)
+	postgresSuperPrivilegePolicyID = uint(2001)
+	// postgresSuperPrivGroupID is the group superuser actors are assigned to (MySQL uses 1, Oracle 1000)
+	postgresSuperPrivGroupID = uint(2000)
+)
+
+var (
+	// catalogPrefixRegex matches schema qualifiers that do not exist in the in-memory session
+	catalogPrefixRegex = regexp.MustCompile(`(?i)\b(pg_catalog|information_schema)\.`)
+	// typeCastRegex matches PostgreSQL ::type casts, which go-mysql-server cannot parse
+	typeCastRegex = regexp.MustCompile(`::[A-Za-z_][A-Za-z0-9_]*(\[\])?`)
+	// pgNamespaceRegex matches pg_namespace, whose ACLs are loaded flattened into pg_namespace_acl
+	pgNamespaceRegex = regexp.MustCompile(`(?i)\bpg_namespace\b`)
+)
+
+// Dialect evaluates PostgreSQL privileges with the shared catalog discovery engine.
+// DbMgts hold the schemas whose grants are collected (matched against table_schema / nspname).
+var Dialect = &privilege.CatalogDialect{
+	DBType:          "postgres",
+	Name:            "PostgreSQL",
+	TypeNames:       []string{"postgres", "postgresql"},
+	JobKind:         JobKindPostgresPrivilegeSession,
+	ContextKey:      "postgres_privilege_session_context",
+	DatabaseNoun:    "schemas",
+	SuperPolicyID:   postgresSuperPrivilegePolicyID,
+	SuperGroupID:    postgresSuperPrivGroupID,
+	SessionDatabase: "postgres",
+	Tables:          postgresPrivilegeTables,
+	// Grant tables have no primary key because PUBLIC and role grants may repeat per grantor
+	PrimaryKeys: map[string][]string{
+		"pg_roles":        {"rolname"},
+		"pg_auth_members": {"rolname", "member"},
+	},
+	TemplateTables: []string{"pg_roles", "pg_auth_members", "role_table_grants", "role_column_grants", "pg_namespace", "pg_default_acl"},
+	Columns:        GetPostgresPrivilegeColumnNames,
+	Rewrite:        RewritePostgresQueryForPrivilegeSession,
+	Escape:         EscapePostgresSQL,
+	BuildQueries:   buildPrivilegeDataQueries,
+}
+
+// RewritePostgresQueryForPrivilegeSession adapts PostgreSQL policy templates to the in-memory session.
+// Strips pg_catalog./information_schema. qualifiers and ::type casts, and maps pg_namespace
+// references to the flattened pg_namespace_acl table.
+func RewritePostgresQueryForPrivilegeSession(query string) string {
+	rewritten := catalogPrefixRegex.ReplaceAllString(query, "")
+	rewritten = typeCastRegex.ReplaceAllString(rewritten, "")
+	rewritten = pgNamespaceRegex.ReplaceAllString(rewritten, "pg_namespace_acl")
+	return rewritten
+}
+
+// buildPrivilegeDataQueries adapts BuildPostgresPrivilegeDataQueries to the actors and schemas of a connection.
+func buildPrivilegeDataQueries(actors []models.DBActorMgt, databases []models.DBMgt) (map[string][]string, error) {
+	actorInfos := make([]ActorInfo, len(actors))
+	for i, actor := range actors {
+		actorInfos[i] = ActorInfo{DBUser: actor.DBUser}
+	}
+
+	dbInfos := make([]DatabaseInfo, len(databases))
+	for i, db := range databases {
+		dbInfos[i] = DatabaseInfo{DbName: db.DbName}
+	}
+	return BuildPostgresPrivilegeDataQueries(actorInfos, dbInfos)
+}
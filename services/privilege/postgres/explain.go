@@ -0,0 +1,42 @@
+package postgres
+
+import (
+	"dbfartifactapi/models"
+	"dbfartifactapi/services/privilege"
+	"dbfartifactapi/services/privilege/snapshot"
+)
+
+// postgresExplainTrace describes which PostgreSQL catalog rows name a grantee and how roles are granted.
+// Grants to PUBLIC apply to every role, so PUBLIC is traced as a root next to the actor.
+func postgresExplainTrace(actor models.DBActorMgt) privilege.ExplainTrace {
+	source := func(queryKey, grantee string) snapshot.GrantSource {
+		table := postgresPrivilegeTables[queryKey]
+		columns, _ := GetPostgresPrivilegeColumnNames(table)
+		return snapshot.GrantSource{QueryKey: queryKey, Table: table, Columns: columns, Grantee: grantee}
+	}
+	roleColumns, _ := GetPostgresPrivilegeColumnNames("pg_auth_members")
+
+	return privilege.ExplainTrace{
+		Roots: []string{actor.DBUser, "PUBLIC"},
+		Roles: []snapshot.RoleSource{{
+			QueryKey: "pg_auth_members",
+			Columns:  roleColumns,
+			Member:   "member",
+			Role:     "rolname",
+		}},
+		Sources: []snapshot.GrantSource{
+			source("pg_roles", "rolname"),
+			source("pg_auth_members", "member"),
+			source("information_schema.role_table_grants", "grantee"),
+			source("information_schema.role_column_grants", "grantee"),
+			source("pg_namespace_acl", "grantee"),
+			source("pg_default_acl", "grantee"),
+		},
+	}
+}
+
+// ExplainPostgresActorPrivileges re-runs the three PostgreSQL passes for one actor against snapshot rows.
+// Nothing is written: policy defaults that would be allowed are returned with their grant/role path.
+func ExplainPostgresActorPrivileges(input privilege.ExplainInput) (*privilege.ExplainResult, error) {
+	return Dialect.Explain(input, postgresExplainTrace(input.Actor))
+}
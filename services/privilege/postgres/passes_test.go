@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"testing"
+
+	"dbfartifactapi/models"
+	"dbfartifactapi/services/privilege"
+
+	"gorm.io/gorm"
+)
+
+// fixtureEvaluator applies the allow/deny rules of the policy service without a database
+type fixtureEvaluator struct{}
+
+func (fixtureEvaluator) IsPolicyAllowed(output, resAllow, resDeny string) bool {
+	if output == resDeny {
+		return false
+	}
+	return output == resAllow || (resAllow == "NOT NULL" && output != "NULL")
+}
+
+func (fixtureEvaluator) ExtractResultValue(result []map[string]interface{}) string {
+	if len(result) == 0 {
+		return "NULL"
+	}
+	for _, value := range result[0] {
+		if value == nil {
+			return "NULL"
+		}
+		return fmt.Sprintf("%v", value)
+	}
+	return "NULL"
+}
+
+func (fixtureEvaluator) GetPolicyDefaultsMap() map[uint]models.DBPolicyDefault { return nil }
+
+func (fixtureEvaluator) GetDBActorMgts(tx *gorm.DB, cntID uint) ([]*models.DBActorMgt, error) {
+	return nil, nil
+}
+
+func (fixtureEvaluator) GetDBObjectsByObjectIdAndDbMgt(tx *gorm.DB, objectID int, dbMgtID uint) ([]models.DBObjectMgt, error) {
+	return nil, nil
+}
+
+func allowedKeys(policies []privilege.AllowedPolicy) []string {
+	keys := make([]string, 0, len(policies))
+	for _, p := range policies {
+		keys = append(keys, p.Key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// TestEvaluatePasses_Fixture tests that a superuser stops at Pass 1, a schema-wide grant stops at Pass 2
+// and a table grant is found in Pass 3
+func TestEvaluatePasses_Fixture(t *testing.T) {
+	session, err := Dialect.NewSession(context.Background(), "postgres_passes_test")
+	if err != nil {
+		t.Fatalf("NewSession() error = %v", err)
+	}
+	defer session.Close()
+
+	fixture := []privilege.QueryResult{
+		{QueryKey: "pg_roles", Status: "success", Result: [][]interface{}{
+			{"postgres", "t", "t", "t", "t", "t", "t", "t", "-1", nil},
+			{"app", "f", "t", "f", "f", "t", "f", "f", "-1", nil},
+			{"viewer", "f", "t", "f", "f", "t", "f", "f", "-1", nil},
+		}},
+		{QueryKey: "pg_default_acl", Status: "success", Result: [][]interface{}{
+			{"postgres", "sales", "r", "postgres", "postgres", "SELECT", "NO"},
+			{"postgres", "sales", "r", "app", "postgres", "SELECT", "NO"},
+		}},
+		{QueryKey: "information_schema.role_table_grants", Status: "success", Result: [][]interface{}{
+			{"postgres", "postgres", "shop", "sales", "orders", "SELECT", "YES", "YES"},
+			{"postgres", "app", "shop", "sales", "orders", "SELECT", "NO", "YES"},
+			{"postgres", "viewer", "shop", "sales", "orders", "SELECT", "NO", "YES"},
+		}},
+	}
+	if err := Dialect.LoadResults(session, fixture); err != nil {
+		t.Fatalf("LoadResults() error = %v", err)
+	}
+
+	hexSQL := func(s string) string { return hex.EncodeToString([]byte(s)) }
+	templates := privilege.CatalogTemplates{
+		Super: []models.DBPolicyDefault{{
+			ID:          postgresSuperPrivilegePolicyID,
+			SqlGet:      hexSQL("SELECT rolsuper FROM pg_catalog.pg_roles WHERE rolname = '${dbactormgt.dbuser}'"),
+			SqlGetAllow: "t",
+			SqlGetDeny:  "f",
+		}},
+		ActionWide: []models.DBPolicyDefault{{
+			ID:          2010,
+			ActionId:    1,
+			SqlGet:      hexSQL("SELECT privilege_type FROM pg_default_acl WHERE nspname = '${dbmgt.dbname}' AND grantee = '${dbactormgt.dbuser}' AND objtype = 'r' AND privilege_type = 'SELECT'"),
+			SqlGetAllow: "NOT NULL",
+		}},
+		ObjectSpecific: []models.DBPolicyDefault{{
+			ID:             2020,
+			ActionId:       1,
+			ObjectId:       7,
+			SqlGetSpecific: hexSQL("SELECT privilege_type FROM information_schema.role_table_grants WHERE grantee = '${dbactormgt.dbuser}' AND table_schema = '${dbmgt.dbname}' AND table_name = '${dbobjectmgt.objectname}' AND privilege_type = 'SELECT'"),
+			SqlGetAllow:    "NOT NULL",
+		}},
+	}
+	dbMgts := []models.DBMgt{{ID: 10, DbName: "sales"}}
+	actors := []models.DBActorMgt{
+		{ID: 1, DBUser: "postgres"},
+		{ID: 2, DBUser: "app"},
+		{ID: 3, DBUser: "viewer"},
+	}
+	objects := []models.DBObjectMgt{
+		{ID: 100, DBMgt: 10, ObjectName: "orders", ObjectId: 7},
+		{ID: 101, DBMgt: 10, ObjectName: "customers", ObjectId: 7},
+	}
+
+	results := Dialect.EvaluatePasses(session, fixtureEvaluator{}, templates, dbMgts, actors, objects)
+
+	tests := []struct {
+		pass string
+		got  []privilege.AllowedPolicy
+		want []string
+	}{
+		{"pass 1", results.Super, []string{"Actor:1_PolicyDf:2001_General"}},
+		{"pass 2", results.ActionWide, []string{"Actor:2_PolicyDf:2010_DbMgt:10_General"}},
+		{"pass 3", results.ObjectSpecific, []string{"Actor:3_PolicyDf:2020_DbMgt:10_Object:100"}},
+	}
+	for _, tt := range tests {
+		if got := allowedKeys(tt.got); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%s allowed = %v, want %v", tt.pass, got, tt.want)
+		}
+	}
+
+	if len(results.ObjectSpecific) == 1 {
+		if got := results.ObjectSpecific[0]; got.DbMgtID != 10 || got.ObjectID != 100 {
+			t.Errorf("pass 3 scope = dbmgt %d object %d, want dbmgt 10 object 100", got.DbMgtID, got.ObjectID)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	"dbfartifactapi/pkg/logger"
+)
+
+// postgresPrivilegeTables maps dbfAgentAPI query keys to in-memory session tables.
+// information_schema views and ACL arrays are flattened at the source, so every table is plain rows.
+var postgresPrivilegeTables = map[string]string{
+	"pg_roles":                              "pg_roles",
+	"pg_auth_members":                       "pg_auth_members",
+	"information_schema.role_table_grants":  "role_table_grants",
+	"information_schema.role_column_grants": "role_column_grants",
+	"pg_namespace_acl":                      "pg_namespace_acl",
+	"pg_default_acl":                        "pg_default_acl",
+}
+
+// GetPostgresPrivilegeColumnNames returns column names for PostgreSQL privilege tables.
+// Column order must match SELECT query order to prevent data corruption during loading.
+func GetPostgresPrivilegeColumnNames(tableName string) ([]string, error) {
+	columnMap := map[string][]string{
+		"pg_roles": {
+			"rolname", "rolsuper", "rolinherit", "rolcreaterole", "rolcreatedb",
+			"rolcanlogin", "rolreplication", "rolbypassrls", "rolconnlimit", "rolvaliduntil",
+		},
+		"pg_auth_members": {
+			"rolname", "member", "grantor", "admin_option",
+		},
+		"role_table_grants": {
+			"grantor", "grantee", "table_catalog", "table_schema", "table_name",
+			"privilege_type", "is_grantable", "with_hierarchy",
+		},
+		"role_column_grants": {
+			"grantor", "grantee", "table_catalog", "table_schema", "table_name",
+			"column_name", "privilege_type", "is_grantable",
+		},
+		"pg_namespace_acl": {
+			"nspname", "nspowner", "grantee", "grantor", "privilege_type", "is_grantable",
+		},
+		"pg_default_acl": {
+			"defaclrole", "nspname", "objtype", "grantee", "grantor", "privilege_type", "is_grantable",
+		},
+	}
+
+	columns, ok := columnMap[tableName]
+	if !ok {
+		return nil, fmt.Errorf("unknown PostgreSQL privilege table: %s", tableName)
+	}
+	return columns, nil
+}
+
+// EscapePostgresSQL escapes single quotes in PostgreSQL string literals.
+// PostgreSQL escapes a quote inside a literal by doubling it.
+func EscapePostgresSQL(s string) string {
+	return strings.ReplaceAll(s, "'", "''")
+}
+
+// ActorInfo provides actor data needed for building PostgreSQL privilege queries.
+// Decouples from models.DBActorMgt to avoid circular imports.
+type ActorInfo struct {
+	DBUser string
+}
+
+// DatabaseInfo provides schema data needed for building PostgreSQL privilege queries.
+// Decouples from models.DBMgt to avoid circular imports.
+type DatabaseInfo struct {
+	DbName string
+}
+
+// BuildPostgresPrivilegeDataQueries builds queries to fetch privilege data from PostgreSQL catalogs.
+// Every query starts from the actors and the roles they are members of (directly or through other roles),
+// so privileges inherited via role membership are available to policy templates.
+// ACL arrays are expanded with aclexplode and booleans rendered as Y/N to keep the in-memory tables flat.
+// Returns map of query keys to SQL statements for dbfAgentAPI execution.
+func BuildPostgresPrivilegeDataQueries(actors []ActorInfo, databases []DatabaseInfo) (map[string][]string, error) {
+	if len(actors) == 0 {
+		return nil, fmt.Errorf("no actors provided for PostgreSQL privilege query building")
+	}
+	if len(databases) == 0 {
+		return nil, fmt.Errorf("no schemas provided for PostgreSQL privilege query building")
+	}
+
+	actorNames := make([]string, 0, len(actors))
+	for _, actor := range actors {
+		actorNames = append(actorNames, fmt.Sprintf("'%s'", EscapePostgresSQL(actor.DBUser)))
+	}
+	actorFilter := strings.Join(actorNames, ", ")
+
+	schemaNames := make([]string, 0, len(databases))
+	for _, db := range databases {
+		schemaNames = append(schemaNames, fmt.Sprintf("'%s'", EscapePostgresSQL(db.DbName)))
+	}
+	schemaFilter := strings.Join(schemaNames, ", ")
+
+	// actor_roles holds the OIDs of the actors plus every role reachable through pg_auth_members
+	actorRoles := fmt.Sprintf(`WITH RECURSIVE actor_roles(oid) AS (
+			SELECT oid FROM pg_catalog.pg_roles WHERE rolname IN (%s)
+			UNION
+			SELECT m.roleid FROM pg_catalog.pg_auth_members m JOIN actor_roles a ON m.member = a.oid
+		)`, actorFilter)
+
+	queries := make(map[string][]string)
+
+	// pg_roles - role attributes (superuser, createrole, bypassrls, ...)
+	queries["pg_roles"] = []string{
+		fmt.Sprintf(`%s
+			SELECT rolname,
+			CASE WHEN rolsuper THEN 'Y' ELSE 'N' END AS rolsuper,
+			CASE WHEN rolinherit THEN 'Y' ELSE 'N' END AS rolinherit,
+			CASE WHEN rolcreaterole THEN 'Y' ELSE 'N' END AS rolcreaterole,
+			CASE WHEN rolcreatedb THEN 'Y' ELSE 'N' END AS rolcreatedb,
+			CASE WHEN rolcanlogin THEN 'Y' ELSE 'N' END AS rolcanlogin,
+			CASE WHEN rolreplication THEN 'Y' ELSE 'N' END AS rolreplication,
+			CASE WHEN rolbypassrls THEN 'Y' ELSE 'N' END AS rolbypassrls,
+			rolconnlimit::text AS rolconnlimit,
+			COALESCE(rolvaliduntil::text, '') AS rolvaliduntil
+			FROM pg_catalog.pg_roles
+			WHERE oid IN (SELECT oid FROM actor_roles)`, actorRoles),
+	}
+
+	// pg_auth_members - role memberships, resolved to role names
+	queries["pg_auth_members"] = []string{
+		fmt.Sprintf(`%s
+			SELECT r.rolname, m.rolname AS member,
+			COALESCE(g.rolname, '') AS grantor,
+			CASE WHEN am.admin_option THEN 'Y' ELSE 'N' END AS admin_option
+			FROM pg_catalog.pg_auth_members am
+			JOIN pg_catalog.pg_roles r ON r.oid = am.roleid
+			JOIN pg_catalog.pg_roles m ON m.oid = am.member
+			LEFT JOIN pg_catalog.pg_roles g ON g.oid = am.grantor
+			WHERE am.member IN (SELECT oid FROM actor_roles)`, actorRoles),
+	}
+
+	// role_table_grants - table and view privileges
+	queries["information_schema.role_table_grants"] = []string{
+		fmt.Sprintf(`%s
+			SELECT grantor, grantee, table_catalog, table_schema, table_name,
+			privilege_type, is_grantable, with_hierarchy
+			FROM information_schema.role_table_grants
+			WHERE table_schema IN (%s)
+			AND (grantee = 'PUBLIC' OR grantee IN (SELECT rolname FROM pg_catalog.pg_roles WHERE oid IN (SELECT oid FROM actor_roles)))`,
+			actorRoles, schemaFilter),
+	}
+
+	// role_column_grants - column-level privileges
+	queries["information_schema.role_column_grants"] = []string{
+		fmt.Sprintf(`%s
+			SELECT grantor, grantee, table_catalog, table_schema, table_name,
+			column_name, privilege_type, is_grantable
+			FROM information_schema.role_column_grants
+			WHERE table_schema IN (%s)
+			AND (grantee = 'PUBLIC' OR grantee IN (SELECT rolname FROM pg_catalog.pg_roles WHERE oid IN (SELECT oid FROM actor_roles)))`,
+			actorRoles, schemaFilter),
+	}
+
+	// pg_namespace ACLs - schema USAGE/CREATE privileges (NULL nspacl means owner defaults)
+	queries["pg_namespace_acl"] = []string{
+		fmt.Sprintf(`%s
+			SELECT n.nspname,
+			pg_catalog.pg_get_userbyid(n.nspowner) AS nspowner,
+			COALESCE(r.rolname, 'PUBLIC') AS grantee,
+			pg_catalog.pg_get_userbyid(a.grantor) AS grantor,
+			a.privilege_type,
+			CASE WHEN a.is_grantable THEN 'YES' ELSE 'NO' END AS is_grantable
+			FROM pg_catalog.pg_namespace n
+			CROSS JOIN LATERAL pg_catalog.aclexplode(COALESCE(n.nspacl, pg_catalog.acldefault('n', n.nspowner))) a
+			LEFT JOIN pg_catalog.pg_roles r ON r.oid = a.grantee
+			WHERE n.nspname IN (%s)
+			AND (a.grantee = 0 OR a.grantee IN (SELECT oid FROM actor_roles))`, actorRoles, schemaFilter),
+	}
+
+	// pg_default_acl - privileges applied to objects created in the future (empty nspname means all schemas)
+	queries["pg_default_acl"] = []string{
+		fmt.Sprintf(`%s
+			SELECT pg_catalog.pg_get_userbyid(d.defaclrole) AS defaclrole,
+			COALESCE(n.nspname, '') AS nspname,
+			CASE d.defaclobjtype WHEN 'r' THEN 'TABLE' WHEN 'S' THEN 'SEQUENCE' WHEN 'f' THEN 'FUNCTION'
+				WHEN 'T' THEN 'TYPE' WHEN 'n' THEN 'SCHEMA' ELSE d.defaclobjtype::text END AS objtype,
+			COALESCE(r.rolname, 'PUBLIC') AS grantee,
+			pg_catalog.pg_get_userbyid(a.grantor) AS grantor,
+			a.privilege_type,
+			CASE WHEN a.is_grantable THEN 'YES' ELSE 'NO' END AS is_grantable
+			FROM pg_catalog.pg_default_acl d
+			LEFT JOIN pg_catalog.pg_namespace n ON n.oid = d.defaclnamespace
+			CROSS JOIN LATERAL pg_catalog.aclexplode(d.defaclacl) a
+			LEFT JOIN pg_catalog.pg_roles r ON r.oid = a.grantee
+			WHERE (n.nspname IS NULL OR n.nspname IN (%s))
+			AND (a.grantee = 0 OR a.grantee IN (SELECT oid FROM actor_roles))`, actorRoles, schemaFilter),
+	}
+
+	logger.Infof("Built %d PostgreSQL privilege queries for %d actors and %d schemas",
+		len(queries), len(actors), len(databases))
+
+	return queries, nil
+}
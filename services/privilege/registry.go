@@ -5,6 +5,7 @@ import (
 	"sync"
 
 	"dbfartifactapi/models"
+	"dbfartifactapi/services/agent"
 )
 
 // registry holds function references registered by services/ to break circular dependency.
@@ -50,14 +51,14 @@ func NewPolicyEvaluator() PolicyEvaluator {
 	return newPolicyEvaluatorFn()
 }
 
-// RetrieveJobResults fetches job results via registered function.
-func RetrieveJobResults(jobID string, ep *models.Endpoint) ([]QueryResult, error) {
+// RetrieveJobResults fetches job results with agentExec via registered function.
+func RetrieveJobResults(agentExec agent.AgentExecutor, jobID string, ep *models.Endpoint) ([]QueryResult, error) {
 	registryMu.RLock()
 	defer registryMu.RUnlock()
 	if retrieveJobResultsFn == nil {
 		return nil, fmt.Errorf("privilege: RetrieveJobResults not registered")
 	}
-	return retrieveJobResultsFn(jobID, ep)
+	return retrieveJobResultsFn(agentExec, jobID, ep)
 }
 
 // GetEndpointForJob retrieves endpoint via registered function.
@@ -51,36 +51,54 @@ func (s *PrivilegeSession) ExecuteInDatabase(sqlTemplate string, database string
 		finalSQL = strings.ReplaceAll(finalSQL, "${"+varName+"}", varValue)
 	}
 
+	columns, rows, err := s.QueryInDatabase(finalSQL, database)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []map[string]interface{}{}
+	for _, row := range rows {
+		rowMap := make(map[string]interface{})
+		for i, col := range columns {
+			rowMap[col] = row[i]
+		}
+		results = append(results, rowMap)
+	}
+
+	return results, nil
+}
+
+// QueryInDatabase executes SQL against temporary server in specified database context.
+// Unlike ExecuteInDatabase, column order is preserved: rows are returned positionally with their column names.
+func (s *PrivilegeSession) QueryInDatabase(query string, database string) ([]string, [][]interface{}, error) {
 	session := memory.NewSession(sql.NewBaseSession(), s.Provider)
 	ctx := sql.NewContext(context.Background(), sql.WithSession(session))
 	ctx.SetCurrentDatabase(database)
 
-	schema, rowIter, _, err := s.Engine.Query(ctx, finalSQL)
+	schema, rowIter, _, err := s.Engine.Query(ctx, query)
 	if err != nil {
-		return nil, fmt.Errorf("query execution failed: %w", err)
+		return nil, nil, fmt.Errorf("query execution failed: %w", err)
 	}
 	defer rowIter.Close(ctx)
 
-	results := []map[string]interface{}{}
+	columns := make([]string, len(schema))
+	for i, col := range schema {
+		columns[i] = col.Name
+	}
 
+	rows := [][]interface{}{}
 	for {
 		row, err := rowIter.Next(ctx)
 		if err == io.EOF {
 			break
 		}
 		if err != nil {
-			return nil, fmt.Errorf("failed to fetch row: %w", err)
-		}
-
-		rowMap := make(map[string]interface{})
-		for i, col := range schema {
-			rowMap[col.Name] = row[i]
+			return nil, nil, fmt.Errorf("failed to fetch row: %w", err)
 		}
-
-		results = append(results, rowMap)
+		rows = append(rows, []interface{}(row))
 	}
 
-	return results, nil
+	return columns, rows, nil
 }
 
 // GetFreePort finds an available TCP port.
@@ -0,0 +1,41 @@
+package privilege
+
+import (
+	"dbfartifactapi/models"
+	"dbfartifactapi/pkg/logger"
+	"dbfartifactapi/services/privilege/snapshot"
+
+	"gorm.io/gorm"
+)
+
+// CapturePrivilegeSnapshot records the raw privilege rows and allowed policies of a discovery run
+// in the handler's transaction. Failures are logged and do not abort policy creation.
+func CapturePrivilegeSnapshot(
+	tx *gorm.DB,
+	cntMgtID uint,
+	jobID string,
+	dbType string,
+	results []QueryResult,
+	actors []models.DBActorMgt,
+	actorPolicies map[uint]map[uint]bool,
+) {
+	grants := make([]snapshot.GrantResult, len(results))
+	for i, result := range results {
+		grants[i] = snapshot.GrantResult{
+			QueryKey: result.QueryKey,
+			Status:   result.Status,
+			Result:   result.Result,
+		}
+	}
+
+	if _, err := snapshot.Capture(tx, snapshot.Input{
+		CntMgtID:      cntMgtID,
+		JobID:         jobID,
+		DBType:        dbType,
+		Grants:        grants,
+		Actors:        actors,
+		ActorPolicies: actorPolicies,
+	}); err != nil {
+		logger.Warnf("Failed to capture %s privilege snapshot for job %s: %v", dbType, jobID, err)
+	}
+}
@@ -0,0 +1,219 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"dbfartifactapi/models"
+	"dbfartifactapi/services/dto"
+)
+
+// tableRows holds the rows of one privilege table keyed by their JSON encoding.
+type tableRows struct {
+	complete bool
+	rows     map[string][]interface{}
+}
+
+// Diff compares two snapshots of the same connection.
+// Grant rows are compared per privilege table; tables whose query failed in either
+// snapshot are listed in IncompleteTables instead of being reported as revoked or added.
+func Diff(from, to *models.PrivilegeSnapshot) (*dto.PrivilegeDriftResponse, error) {
+	if from.CntMgtID != to.CntMgtID {
+		return nil, fmt.Errorf("snapshots belong to different connections: cnt_id=%d and cnt_id=%d", from.CntMgtID, to.CntMgtID)
+	}
+
+	fromGrants, err := decodeGrantRows(from)
+	if err != nil {
+		return nil, err
+	}
+	toGrants, err := decodeGrantRows(to)
+	if err != nil {
+		return nil, err
+	}
+	fromActors, err := decodeActors(from)
+	if err != nil {
+		return nil, err
+	}
+	toActors, err := decodeActors(to)
+	if err != nil {
+		return nil, err
+	}
+	fromPolicies, err := decodeActorPolicies(from)
+	if err != nil {
+		return nil, err
+	}
+	toPolicies, err := decodeActorPolicies(to)
+	if err != nil {
+		return nil, err
+	}
+
+	drift := &dto.PrivilegeDriftResponse{
+		CntMgtID:        to.CntMgtID,
+		FromVersion:     from.Version,
+		ToVersion:       to.Version,
+		FromCreatedAt:   from.CreatedAt,
+		ToCreatedAt:     to.CreatedAt,
+		GrantsAdded:     []dto.PrivilegeGrantChange{},
+		GrantsRevoked:   []dto.PrivilegeGrantChange{},
+		ActorsAdded:     []dto.PrivilegeDriftActor{},
+		ActorsRemoved:   []dto.PrivilegeDriftActor{},
+		PoliciesAllowed: []dto.PrivilegePolicyDrift{},
+		PoliciesRevoked: []dto.PrivilegePolicyDrift{},
+	}
+
+	// Grants
+	tables := make(map[string]bool)
+	for table := range fromGrants {
+		tables[table] = true
+	}
+	for table := range toGrants {
+		tables[table] = true
+	}
+	for _, table := range sortedKeys(tables) {
+		before, inFrom := fromGrants[table]
+		after, inTo := toGrants[table]
+		if (inFrom && !before.complete) || (inTo && !after.complete) {
+			drift.IncompleteTables = append(drift.IncompleteTables, table)
+			continue
+		}
+		for _, key := range sortedKeys(after.rows) {
+			if _, ok := before.rows[key]; !ok {
+				drift.GrantsAdded = append(drift.GrantsAdded, dto.PrivilegeGrantChange{Table: table, Row: after.rows[key]})
+			}
+		}
+		for _, key := range sortedKeys(before.rows) {
+			if _, ok := after.rows[key]; !ok {
+				drift.GrantsRevoked = append(drift.GrantsRevoked, dto.PrivilegeGrantChange{Table: table, Row: before.rows[key]})
+			}
+		}
+	}
+
+	// Actors
+	for _, id := range sortedKeys(toActors) {
+		if _, ok := fromActors[id]; !ok {
+			drift.ActorsAdded = append(drift.ActorsAdded, driftActor(toActors[id]))
+		}
+	}
+	for _, id := range sortedKeys(fromActors) {
+		if _, ok := toActors[id]; !ok {
+			drift.ActorsRemoved = append(drift.ActorsRemoved, driftActor(fromActors[id]))
+		}
+	}
+
+	// Allowed policy defaults
+	actorName := func(id uint) string {
+		if actor, ok := toActors[id]; ok {
+			return actor.DBUser
+		}
+		return fromActors[id].DBUser
+	}
+	drift.PoliciesAllowed = policyChanges(toPolicies, fromPolicies, actorName)
+	drift.PoliciesRevoked = policyChanges(fromPolicies, toPolicies, actorName)
+
+	return drift, nil
+}
+
+// decodeGrantRows groups snapshot rows by privilege table.
+// Query keys carry an array index per statement (e.g. "mysql.user[0]"), which is stripped.
+func decodeGrantRows(snapshot *models.PrivilegeSnapshot) (map[string]*tableRows, error) {
+	var results []GrantResult
+	if snapshot.GrantRows != "" {
+		if err := json.Unmarshal([]byte(snapshot.GrantRows), &results); err != nil {
+			return nil, fmt.Errorf("failed to decode grant rows of snapshot v%d: %w", snapshot.Version, err)
+		}
+	}
+
+	tables := make(map[string]*tableRows)
+	for _, result := range results {
+		table := result.QueryKey
+		if idx := strings.Index(table, "["); idx != -1 {
+			table = table[:idx]
+		}
+
+		rows, ok := tables[table]
+		if !ok {
+			rows = &tableRows{complete: true, rows: make(map[string][]interface{})}
+			tables[table] = rows
+		}
+		if result.Status != "success" {
+			rows.complete = false
+			continue
+		}
+		for _, row := range result.Result {
+			key, err := json.Marshal(row)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode grant row of snapshot v%d: %w", snapshot.Version, err)
+			}
+			rows.rows[string(key)] = row
+		}
+	}
+	return tables, nil
+}
+
+func decodeActors(snapshot *models.PrivilegeSnapshot) (map[uint]Actor, error) {
+	var actors []Actor
+	if snapshot.Actors != "" {
+		if err := json.Unmarshal([]byte(snapshot.Actors), &actors); err != nil {
+			return nil, fmt.Errorf("failed to decode actors of snapshot v%d: %w", snapshot.Version, err)
+		}
+	}
+
+	byID := make(map[uint]Actor, len(actors))
+	for _, actor := range actors {
+		byID[actor.ID] = actor
+	}
+	return byID, nil
+}
+
+func decodeActorPolicies(snapshot *models.PrivilegeSnapshot) (map[uint]map[uint]bool, error) {
+	var actorPolicies map[uint][]uint
+	if snapshot.ActorPolicies != "" {
+		if err := json.Unmarshal([]byte(snapshot.ActorPolicies), &actorPolicies); err != nil {
+			return nil, fmt.Errorf("failed to decode actor policies of snapshot v%d: %w", snapshot.Version, err)
+		}
+	}
+
+	sets := make(map[uint]map[uint]bool, len(actorPolicies))
+	for actorID, ids := range actorPolicies {
+		set := make(map[uint]bool, len(ids))
+		for _, id := range ids {
+			set[id] = true
+		}
+		sets[actorID] = set
+	}
+	return sets, nil
+}
+
+// policyChanges returns actor/policy pairs present in current but not in baseline.
+func policyChanges(current, baseline map[uint]map[uint]bool, actorName func(uint) string) []dto.PrivilegePolicyDrift {
+	changes := []dto.PrivilegePolicyDrift{}
+	for _, actorID := range sortedKeys(current) {
+		for _, policyID := range sortedKeys(current[actorID]) {
+			if baseline[actorID][policyID] {
+				continue
+			}
+			changes = append(changes, dto.PrivilegePolicyDrift{
+				ActorID:         actorID,
+				DBUser:          actorName(actorID),
+				PolicyDefaultID: policyID,
+			})
+		}
+	}
+	return changes
+}
+
+func driftActor(actor Actor) dto.PrivilegeDriftActor {
+	return dto.PrivilegeDriftActor{ActorID: actor.ID, DBUser: actor.DBUser, IPAddress: actor.IPAddress}
+}
+
+// sortedKeys returns map keys in ascending order so drift output is deterministic.
+func sortedKeys[K uint | string, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
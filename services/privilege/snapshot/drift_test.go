@@ -0,0 +1,138 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"dbfartifactapi/models"
+)
+
+// buildSnapshot encodes test data the same way Capture does
+func buildSnapshot(t *testing.T, version int, grants []GrantResult, actors []Actor, actorPolicies map[uint][]uint) *models.PrivilegeSnapshot {
+	t.Helper()
+
+	grantRows, err := json.Marshal(grants)
+	if err != nil {
+		t.Fatalf("marshal grants: %v", err)
+	}
+	actorsJSON, err := json.Marshal(actors)
+	if err != nil {
+		t.Fatalf("marshal actors: %v", err)
+	}
+	policiesJSON, err := json.Marshal(actorPolicies)
+	if err != nil {
+		t.Fatalf("marshal actor policies: %v", err)
+	}
+
+	return &models.PrivilegeSnapshot{
+		CntMgtID:      5,
+		Version:       version,
+		GrantRows:     string(grantRows),
+		Actors:        string(actorsJSON),
+		ActorPolicies: string(policiesJSON),
+		CreatedAt:     time.Now(),
+	}
+}
+
+// TestDiff_GrantsActorsAndPolicies tests that added/revoked grants, actors and policies are reported
+func TestDiff_GrantsActorsAndPolicies(t *testing.T) {
+	from := buildSnapshot(t, 1,
+		[]GrantResult{
+			{QueryKey: "mysql.db[0]", Status: "success", Result: [][]interface{}{
+				{"%", "app", "alice", "Y"},
+				{"%", "app", "bob", "Y"},
+			}},
+		},
+		[]Actor{{ID: 1, DBUser: "alice"}, {ID: 2, DBUser: "bob"}},
+		map[uint][]uint{1: {10, 11}, 2: {10}},
+	)
+	to := buildSnapshot(t, 2,
+		[]GrantResult{
+			{QueryKey: "mysql.db[0]", Status: "success", Result: [][]interface{}{
+				{"%", "app", "alice", "Y"},
+				{"%", "app", "carol", "Y"},
+			}},
+		},
+		[]Actor{{ID: 1, DBUser: "alice"}, {ID: 3, DBUser: "carol"}},
+		map[uint][]uint{1: {10, 12}, 3: {10}},
+	)
+
+	drift, err := Diff(from, to)
+	if err != nil {
+		t.Fatalf("Diff returned error: %v", err)
+	}
+
+	if drift.FromVersion != 1 || drift.ToVersion != 2 {
+		t.Errorf("versions = %d..%d, want 1..2", drift.FromVersion, drift.ToVersion)
+	}
+	if len(drift.GrantsAdded) != 1 || drift.GrantsAdded[0].Table != "mysql.db" || drift.GrantsAdded[0].Row[2] != "carol" {
+		t.Errorf("GrantsAdded = %+v, want carol row in mysql.db", drift.GrantsAdded)
+	}
+	if len(drift.GrantsRevoked) != 1 || drift.GrantsRevoked[0].Row[2] != "bob" {
+		t.Errorf("GrantsRevoked = %+v, want bob row", drift.GrantsRevoked)
+	}
+	if len(drift.ActorsAdded) != 1 || drift.ActorsAdded[0].ActorID != 3 {
+		t.Errorf("ActorsAdded = %+v, want actor 3", drift.ActorsAdded)
+	}
+	if len(drift.ActorsRemoved) != 1 || drift.ActorsRemoved[0].DBUser != "bob" {
+		t.Errorf("ActorsRemoved = %+v, want bob", drift.ActorsRemoved)
+	}
+
+	allowed := map[[2]uint]bool{}
+	for _, p := range drift.PoliciesAllowed {
+		allowed[[2]uint{p.ActorID, p.PolicyDefaultID}] = true
+	}
+	if len(allowed) != 2 || !allowed[[2]uint{1, 12}] || !allowed[[2]uint{3, 10}] {
+		t.Errorf("PoliciesAllowed = %+v, want alice/12 and carol/10", drift.PoliciesAllowed)
+	}
+
+	revoked := map[[2]uint]bool{}
+	for _, p := range drift.PoliciesRevoked {
+		revoked[[2]uint{p.ActorID, p.PolicyDefaultID}] = true
+	}
+	if len(revoked) != 2 || !revoked[[2]uint{1, 11}] || !revoked[[2]uint{2, 10}] {
+		t.Errorf("PoliciesRevoked = %+v, want alice/11 and bob/10", drift.PoliciesRevoked)
+	}
+}
+
+// TestDiff_FailedQueryMarksTableIncomplete tests that a failed table query is not reported as revoked grants
+func TestDiff_FailedQueryMarksTableIncomplete(t *testing.T) {
+	from := buildSnapshot(t, 3,
+		[]GrantResult{
+			{QueryKey: "DBA_SYS_PRIVS[0]", Status: "success", Result: [][]interface{}{{"SCOTT", "CREATE SESSION", "NO"}}},
+			{QueryKey: "DBA_ROLE_PRIVS[0]", Status: "success", Result: [][]interface{}{{"SCOTT", "CONNECT", "NO", "YES"}}},
+		},
+		nil, nil,
+	)
+	to := buildSnapshot(t, 4,
+		[]GrantResult{
+			{QueryKey: "DBA_SYS_PRIVS[0]", Status: "failed"},
+			{QueryKey: "DBA_ROLE_PRIVS[0]", Status: "success", Result: [][]interface{}{{"SCOTT", "CONNECT", "NO", "YES"}}},
+		},
+		nil, nil,
+	)
+
+	drift, err := Diff(from, to)
+	if err != nil {
+		t.Fatalf("Diff returned error: %v", err)
+	}
+
+	if len(drift.GrantsRevoked) != 0 || len(drift.GrantsAdded) != 0 {
+		t.Errorf("expected no grant changes, got added=%+v revoked=%+v", drift.GrantsAdded, drift.GrantsRevoked)
+	}
+	if len(drift.IncompleteTables) != 1 || drift.IncompleteTables[0] != "DBA_SYS_PRIVS" {
+		t.Errorf("IncompleteTables = %v, want [DBA_SYS_PRIVS]", drift.IncompleteTables)
+	}
+}
+
+// TestDiff_DifferentConnections tests that snapshots of different connections are rejected
+func TestDiff_DifferentConnections(t *testing.T) {
+	from := buildSnapshot(t, 1, nil, nil, nil)
+	to := buildSnapshot(t, 2, nil, nil, nil)
+	to.CntMgtID = 6
+
+	if _, err := Diff(from, to); err == nil {
+		t.Fatal("expected error for snapshots of different connections")
+	}
+}
@@ -0,0 +1,199 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"dbfartifactapi/models"
+	"dbfartifactapi/services/dto"
+)
+
+// GrantSource describes a privilege table whose rows can explain an allowed policy.
+type GrantSource struct {
+	QueryKey    string   // Snapshot query key without statement index, e.g. "mysql.tables_priv"
+	Table       string   // Table reference looked up in policy SQL
+	Columns     []string // Column order of the snapshot rows
+	Grantee     string   // Column naming the user or role holding the grant
+	GranteeHost string   // Optional host column; grantee becomes user@host (MySQL)
+}
+
+// RoleSource describes a role membership table.
+type RoleSource struct {
+	QueryKey   string
+	Columns    []string
+	Member     string // Column naming the user or role the role is granted to
+	MemberHost string // Optional host column of the member (MySQL)
+	Role       string // Column naming the granted role
+	RoleHost   string // Optional host column of the role (MySQL)
+}
+
+// GrantResolver traces allowed policies back to privilege rows and role grants of a snapshot.
+type GrantResolver struct {
+	rows     map[string][][]interface{} // query key -> rows of successful statements
+	memberOf map[string][]string        // principal -> roles granted to it
+	foldCase bool
+}
+
+// DecodeGrants returns the privilege query results stored in a snapshot.
+func DecodeGrants(snapshot *models.PrivilegeSnapshot) ([]GrantResult, error) {
+	var results []GrantResult
+	if snapshot.GrantRows == "" {
+		return results, nil
+	}
+	if err := json.Unmarshal([]byte(snapshot.GrantRows), &results); err != nil {
+		return nil, fmt.Errorf("failed to decode grant rows of snapshot v%d: %w", snapshot.Version, err)
+	}
+	return results, nil
+}
+
+// NewGrantResolver indexes snapshot rows and builds the role graph from roles.
+// foldCase compares principal names case-insensitively (Oracle, SQL Server).
+func NewGrantResolver(grants []GrantResult, roles []RoleSource, foldCase bool) *GrantResolver {
+	r := &GrantResolver{
+		rows:     make(map[string][][]interface{}),
+		memberOf: make(map[string][]string),
+		foldCase: foldCase,
+	}
+
+	for _, result := range grants {
+		if result.Status != "success" {
+			continue
+		}
+		key := result.QueryKey
+		if idx := strings.Index(key, "["); idx != -1 {
+			key = key[:idx]
+		}
+		r.rows[key] = append(r.rows[key], result.Result...)
+	}
+
+	for _, source := range roles {
+		for _, row := range r.rows[source.QueryKey] {
+			values := rowValues(source.Columns, row)
+			member := principal(values, source.Member, source.MemberHost)
+			role := principal(values, source.Role, source.RoleHost)
+			if member == "" || role == "" {
+				continue
+			}
+			key := r.normalize(member)
+			r.memberOf[key] = append(r.memberOf[key], role)
+		}
+	}
+	return r
+}
+
+// RolePaths returns every principal reachable from roots through role grants,
+// keyed by normalized name, with the shortest chain of principals leading to it.
+// Each root maps to a path holding only itself.
+func (r *GrantResolver) RolePaths(roots ...string) map[string][]string {
+	paths := make(map[string][]string)
+	queue := make([]string, 0, len(roots))
+	for _, root := range roots {
+		if root == "" {
+			continue
+		}
+		key := r.normalize(root)
+		if _, ok := paths[key]; ok {
+			continue
+		}
+		paths[key] = []string{root}
+		queue = append(queue, key)
+	}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, role := range r.memberOf[current] {
+			key := r.normalize(role)
+			if _, seen := paths[key]; seen {
+				continue
+			}
+			path := make([]string, len(paths[current]), len(paths[current])+1)
+			copy(path, paths[current])
+			paths[key] = append(path, role)
+			queue = append(queue, key)
+		}
+	}
+	return paths
+}
+
+// Roles lists the roles in paths (every entry reached through at least one role grant), sorted by name.
+func Roles(paths map[string][]string) []dto.PrivilegeRolePath {
+	roles := []dto.PrivilegeRolePath{}
+	for _, key := range sortedKeys(paths) {
+		path := paths[key]
+		if len(path) < 2 {
+			continue
+		}
+		roles = append(roles, dto.PrivilegeRolePath{Role: path[len(path)-1], Path: path})
+	}
+	return roles
+}
+
+// Evidence returns rows of the sources referenced by finalSQL whose grantee is one of the principals in paths.
+func (r *GrantResolver) Evidence(finalSQL string, sources []GrantSource, paths map[string][]string) []dto.PrivilegeGrantPath {
+	evidence := []dto.PrivilegeGrantPath{}
+	for _, source := range sources {
+		if !referencesTable(finalSQL, source.Table) {
+			continue
+		}
+		for _, row := range r.rows[source.QueryKey] {
+			values := rowValues(source.Columns, row)
+			grantee := principal(values, source.Grantee, source.GranteeHost)
+			path, ok := paths[r.normalize(grantee)]
+			if grantee == "" || !ok {
+				continue
+			}
+			evidence = append(evidence, dto.PrivilegeGrantPath{
+				Table:   source.QueryKey,
+				Grantee: grantee,
+				Via:     path,
+				Row:     values,
+			})
+		}
+	}
+	return evidence
+}
+
+func (r *GrantResolver) normalize(name string) string {
+	if r.foldCase {
+		return strings.ToUpper(name)
+	}
+	return name
+}
+
+// rowValues maps a snapshot row onto its column names; extra values without a column are dropped.
+func rowValues(columns []string, row []interface{}) map[string]interface{} {
+	values := make(map[string]interface{}, len(columns))
+	for i, column := range columns {
+		if i >= len(row) {
+			break
+		}
+		values[column] = row[i]
+	}
+	return values
+}
+
+// principal builds the principal name of a row, as user@host when hostColumn is set.
+func principal(values map[string]interface{}, nameColumn, hostColumn string) string {
+	name, ok := values[nameColumn]
+	if !ok || name == nil {
+		return ""
+	}
+	if hostColumn == "" {
+		return fmt.Sprintf("%v", name)
+	}
+	host := values[hostColumn]
+	if host == nil {
+		host = ""
+	}
+	return fmt.Sprintf("%v@%v", name, host)
+}
+
+// referencesTable reports whether sql names table as a whole identifier, ignoring case.
+func referencesTable(sql, table string) bool {
+	pattern := `(?i)(^|[^a-z0-9_$])` + regexp.QuoteMeta(table) + `($|[^a-z0-9_$])`
+	matched, err := regexp.MatchString(pattern, sql)
+	return err == nil && matched
+}
@@ -0,0 +1,81 @@
+package snapshot
+
+import "testing"
+
+// TestGrantResolver_RoleChainEvidence tests that grants inherited through nested roles are traced back to the actor
+func TestGrantResolver_RoleChainEvidence(t *testing.T) {
+	grants := []GrantResult{
+		{QueryKey: "dba_role_privs[0]", Status: "success", Result: [][]interface{}{
+			{"SCOTT", "APP_READ"},
+			{"APP_READ", "REPORTING"},
+			{"OTHER", "DBA"},
+		}},
+		{QueryKey: "dba_tab_privs[0]", Status: "success", Result: [][]interface{}{
+			{"REPORTING", "HR", "EMPLOYEES", "SELECT"},
+			{"OTHER", "HR", "EMPLOYEES", "DELETE"},
+		}},
+		{QueryKey: "dba_sys_privs[0]", Status: "success", Result: [][]interface{}{
+			{"scott", "CREATE SESSION"},
+		}},
+	}
+	roles := []RoleSource{{QueryKey: "dba_role_privs", Columns: []string{"GRANTEE", "GRANTED_ROLE"}, Member: "GRANTEE", Role: "GRANTED_ROLE"}}
+	sources := []GrantSource{
+		{QueryKey: "dba_tab_privs", Table: "dba_tab_privs", Columns: []string{"GRANTEE", "OWNER", "TABLE_NAME", "PRIVILEGE"}, Grantee: "GRANTEE"},
+		{QueryKey: "dba_sys_privs", Table: "dba_sys_privs", Columns: []string{"GRANTEE", "PRIVILEGE"}, Grantee: "GRANTEE"},
+	}
+
+	resolver := NewGrantResolver(grants, roles, true)
+	paths := resolver.RolePaths("Scott")
+
+	if got := paths["REPORTING"]; len(got) != 3 || got[0] != "Scott" || got[1] != "APP_READ" || got[2] != "REPORTING" {
+		t.Fatalf("path to REPORTING = %v, want [Scott APP_READ REPORTING]", got)
+	}
+	if _, ok := paths["DBA"]; ok {
+		t.Error("DBA granted to another user must not be reachable")
+	}
+	if roleList := Roles(paths); len(roleList) != 2 {
+		t.Errorf("Roles = %+v, want APP_READ and REPORTING", roleList)
+	}
+
+	evidence := resolver.Evidence("SELECT 'Y' FROM DBA_TAB_PRIVS WHERE PRIVILEGE = 'SELECT'", sources, paths)
+	if len(evidence) != 1 {
+		t.Fatalf("evidence = %+v, want only the REPORTING row of dba_tab_privs", evidence)
+	}
+	if evidence[0].Grantee != "REPORTING" || len(evidence[0].Via) != 3 || evidence[0].Row["TABLE_NAME"] != "EMPLOYEES" {
+		t.Errorf("evidence[0] = %+v, want REPORTING grant on EMPLOYEES via two roles", evidence[0])
+	}
+}
+
+// TestGrantResolver_HostQualifiedPrincipals tests that user@host principals only match the same host
+func TestGrantResolver_HostQualifiedPrincipals(t *testing.T) {
+	grants := []GrantResult{
+		{QueryKey: "mysql.role_edges[0]", Status: "success", Result: [][]interface{}{
+			{"%", "r_admin", "10.0.0.5", "app", "N"},
+		}},
+		{QueryKey: "mysql.global_grants[0]", Status: "success", Result: [][]interface{}{
+			{"r_admin", "%", "SYSTEM_VARIABLES_ADMIN", "N"},
+			{"app", "%", "BACKUP_ADMIN", "N"},
+		}},
+		{QueryKey: "mysql.db[0]", Status: "failed"},
+	}
+	roles := []RoleSource{{
+		QueryKey: "mysql.role_edges", Columns: []string{"FROM_HOST", "FROM_USER", "TO_HOST", "TO_USER", "WITH_ADMIN_OPTION"},
+		Member: "TO_USER", MemberHost: "TO_HOST", Role: "FROM_USER", RoleHost: "FROM_HOST",
+	}}
+	sources := []GrantSource{{
+		QueryKey: "mysql.global_grants", Table: "mysql.global_grants", Columns: []string{"USER", "HOST", "PRIV", "WITH_GRANT_OPTION"},
+		Grantee: "USER", GranteeHost: "HOST",
+	}}
+
+	resolver := NewGrantResolver(grants, roles, false)
+	paths := resolver.RolePaths("app@10.0.0.5")
+
+	evidence := resolver.Evidence("select 'Y' from mysql.global_grants where PRIV='SYSTEM_VARIABLES_ADMIN'", sources, paths)
+	if len(evidence) != 1 || evidence[0].Grantee != "r_admin@%" || evidence[0].Row["PRIV"] != "SYSTEM_VARIABLES_ADMIN" {
+		t.Fatalf("evidence = %+v, want only the r_admin@%% dynamic privilege", evidence)
+	}
+
+	if got := resolver.Evidence("select 'Y' from mysql.global_grants_backup", sources, paths); len(got) != 0 {
+		t.Errorf("evidence for unrelated table = %+v, want none", got)
+	}
+}
@@ -0,0 +1,116 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"dbfartifactapi/config"
+	"dbfartifactapi/models"
+	"dbfartifactapi/pkg/logger"
+	"dbfartifactapi/repository"
+
+	"gorm.io/gorm"
+)
+
+// maxVersionAttempts bounds how often Capture picks a new version after losing it to a concurrent run.
+const maxVersionAttempts = 5
+
+// GrantResult is one privilege query result from dbfAgentAPI as stored in a snapshot.
+// Mirrors privilege.QueryResult without the query text and timings.
+type GrantResult struct {
+	QueryKey string          `json:"query_key"`
+	Status   string          `json:"status"`
+	Result   [][]interface{} `json:"result"`
+}
+
+// Actor is the actor identity recorded in a snapshot.
+type Actor struct {
+	ID        uint   `json:"id"`
+	DBUser    string `json:"dbuser"`
+	IPAddress string `json:"ip_address,omitempty"`
+}
+
+// Input contains everything produced by one privilege discovery run.
+type Input struct {
+	CntMgtID      uint
+	JobID         string
+	DBType        string
+	Grants        []GrantResult
+	Actors        []models.DBActorMgt
+	ActorPolicies map[uint]map[uint]bool // actorID -> policyDefaultID -> allowed
+}
+
+// Capture stores a privilege discovery run as the next snapshot version of its connection.
+// Runs inside the caller's transaction so a rolled back run leaves no snapshot behind.
+// Returns nil snapshot without error when snapshots are disabled.
+func Capture(tx *gorm.DB, input Input) (*models.PrivilegeSnapshot, error) {
+	if !config.Cfg.PrivilegeSnapshotEnabled {
+		return nil, nil
+	}
+
+	grantRows, err := json.Marshal(input.Grants)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode snapshot grant rows: %w", err)
+	}
+
+	actors := make([]Actor, 0, len(input.Actors))
+	for _, actor := range input.Actors {
+		actors = append(actors, Actor{ID: actor.ID, DBUser: actor.DBUser, IPAddress: actor.IPAddress})
+	}
+	actorsJSON, err := json.Marshal(actors)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode snapshot actors: %w", err)
+	}
+
+	// Sorted ID lists keep the stored JSON stable across runs with the same result
+	actorPolicies := make(map[uint][]uint, len(input.ActorPolicies))
+	for actorID, policyIDs := range input.ActorPolicies {
+		ids := make([]uint, 0, len(policyIDs))
+		for id, allowed := range policyIDs {
+			if allowed {
+				ids = append(ids, id)
+			}
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		actorPolicies[actorID] = ids
+	}
+	actorPoliciesJSON, err := json.Marshal(actorPolicies)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode snapshot actor policies: %w", err)
+	}
+
+	snapshot := &models.PrivilegeSnapshot{
+		CntMgtID:      input.CntMgtID,
+		JobID:         input.JobID,
+		DBType:        strings.ToLower(input.DBType),
+		GrantRows:     string(grantRows),
+		Actors:        string(actorsJSON),
+		ActorPolicies: string(actorPoliciesJSON),
+	}
+
+	// Concurrent runs on the same connection may read the same next version; the loser of the
+	// insert reads the version again, outside tx so the winner's snapshot is visible once committed
+	repo := repository.NewPrivilegeSnapshotRepository()
+	for attempt := 1; attempt <= maxVersionAttempts; attempt++ {
+		version, err := repo.GetNextVersion(nil, input.CntMgtID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine snapshot version for cnt_id=%d: %w", input.CntMgtID, err)
+		}
+		snapshot.ID = 0
+		snapshot.Version = version
+		created, err := repo.Create(tx, snapshot)
+		if err != nil {
+			return nil, fmt.Errorf("failed to store privilege snapshot for cnt_id=%d: %w", input.CntMgtID, err)
+		}
+		if created {
+			logger.Infof("Stored privilege snapshot v%d for cnt_id=%d (job %s): %d result sets, %d actors",
+				version, input.CntMgtID, input.JobID, len(input.Grants), len(actors))
+			return snapshot, nil
+		}
+		logger.Debugf("Snapshot v%d for cnt_id=%d taken by a concurrent run, retrying", version, input.CntMgtID)
+	}
+	return nil, fmt.Errorf("failed to store privilege snapshot for cnt_id=%d: version still taken after %d attempts",
+		input.CntMgtID, maxVersionAttempts)
+}
@@ -2,6 +2,7 @@ package privilege
 
 import (
 	"dbfartifactapi/models"
+	"dbfartifactapi/services/agent"
 
 	"gorm.io/gorm"
 )
@@ -48,13 +49,13 @@ type NewPolicyEvaluatorFunc func() PolicyEvaluator
 
 // RetrieveJobResultsFunc fetches job results from VeloArtifact via agent API.
 // Registered by services/ at init time to break circular dependency.
-type RetrieveJobResultsFunc func(jobID string, ep *models.Endpoint) ([]QueryResult, error)
+type RetrieveJobResultsFunc func(agentExec agent.AgentExecutor, jobID string, ep *models.Endpoint) ([]QueryResult, error)
 
 // GetEndpointForJobFunc retrieves endpoint by ID for job processing.
 // Registered by services/ at init time to break circular dependency.
 type GetEndpointForJobFunc func(jobID string, endpointID uint) (*models.Endpoint, error)
 
-// PrivilegeSessionJobContext contains context data for MySQL privilege session job completion.
+// PrivilegeSessionJobContext contains context data for MySQL, PostgreSQL and SQL Server privilege session job completion.
 // Passed to completion handler when dbfAgentAPI background job finishes.
 type PrivilegeSessionJobContext struct {
 	CntMgtID      uint                `json:"cnt_mgt_id"`
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"dbfartifactapi/pkg/logger"
+	"dbfartifactapi/pkg/secrets"
 	"dbfartifactapi/repository"
 	"dbfartifactapi/services/agent"
 	"dbfartifactapi/utils"
@@ -27,6 +28,7 @@ type ConnectionTestService interface {
 type connectionTestService struct {
 	cntMgtRepo   repository.CntMgtRepository
 	endpointRepo repository.EndpointRepository
+	agentExec    agent.AgentExecutor
 }
 
 // NewConnectionTestService creates a new connection test service instance
@@ -34,6 +36,7 @@ func NewConnectionTestService() ConnectionTestService {
 	return &connectionTestService{
 		cntMgtRepo:   repository.NewCntMgtRepository(),
 		endpointRepo: repository.NewEndpointRepository(),
+		agentExec:    agent.DefaultExecutor(),
 	}
 }
 
@@ -65,6 +68,11 @@ func (s *connectionTestService) TestConnection(ctx context.Context, id uint) (st
 
 	logger.Infof("Found endpoint: client_id=%s, os_type=%s", endpoint.ClientID, endpoint.OsType)
 
+	password, err := secrets.Default().Reveal(ctx, cntMgt.Password, cntMgt.SecretRef)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve credentials for connection id=%d: %v", id, err)
+	}
+
 	// Prepare connection test parameters
 	testParams := agent.ConnectionTestAgentParams{
 		Action:      "test_connection",
@@ -72,12 +80,12 @@ func (s *connectionTestService) TestConnection(ctx context.Context, id uint) (st
 		Host:        cntMgt.IP,
 		Port:        cntMgt.Port,
 		Username:    cntMgt.Username,
-		Password:    cntMgt.Password,
+		Password:    password,
 		ServiceName: cntMgt.ServiceName,
 	}
 
 	// Execute connection test via agent API
-	agentResponse, err := agent.ExecuteConnectionTestAgentAPI(endpoint.ClientID, testParams, endpoint.OsType)
+	agentResponse, err := s.agentExec.ExecuteConnectionTest(endpoint.ClientID, testParams, endpoint.OsType)
 	if err != nil {
 		logger.Errorf("Connection test execution failed: %v", err)
 		return "", fmt.Errorf("failed to execute connection test: %v", err)
@@ -8,6 +8,7 @@ import (
 	"dbfartifactapi/pkg/logger"
 	"dbfartifactapi/repository"
 	"dbfartifactapi/services/agent"
+	"dbfartifactapi/services/audit"
 	"dbfartifactapi/services/dto"
 	"dbfartifactapi/utils"
 )
@@ -21,6 +22,7 @@ type sessionService struct {
 	baseRepo     repository.BaseRepository
 	cntmgtRepo   repository.CntMgtRepository
 	endpointRepo repository.EndpointRepository
+	agentExec    agent.AgentExecutor
 }
 
 // NewSessionService creates a new session service instance
@@ -29,6 +31,7 @@ func NewSessionService() SessionService {
 		baseRepo:     repository.NewBaseRepository(),
 		cntmgtRepo:   repository.NewCntMgtRepository(),
 		endpointRepo: repository.NewEndpointRepository(),
+		agentExec:    agent.DefaultExecutor(),
 	}
 }
 
@@ -66,7 +69,7 @@ func (s *sessionService) KillSession(ctx context.Context, cntmgtID uint, session
 		SetHost(cntMgt.IP).
 		SetPort(cntMgt.Port).
 		SetUser(cntMgt.Username).
-		SetPassword(cntMgt.Password).
+		SetCredentials(cntMgt.Password, cntMgt.SecretRef).
 		SetDatabase(""). // Empty for kill session command
 		SetQuery(killQuery).
 		SetAction("execute").
@@ -82,7 +85,8 @@ func (s *sessionService) KillSession(ctx context.Context, cntmgtID uint, session
 
 	// Execute agent API
 	osType := strings.ToLower(endpoint.OsType)
-	stdout, err := agent.ExecuteSqlAgentAPI(endpoint.ClientID, osType, "execute", hexJSON, "", false)
+	audit.AddCommand(ctx, killQuery)
+	stdout, err := agent.WithContext(ctx, s.agentExec).ExecuteSql(endpoint.ClientID, osType, "execute", hexJSON, "", false)
 	if err != nil {
 		logger.Errorf("Agent API execution failed: %v", err)
 		return "", fmt.Errorf("agent API execution failed: %w", err)
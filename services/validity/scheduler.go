@@ -0,0 +1,137 @@
+// Package validity runs the periodic enforcement of group membership validity periods.
+package validity
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"dbfartifactapi/pkg/logger"
+	"dbfartifactapi/services/dto"
+)
+
+// Enforcer applies the memberships whose validity period started or ended by now.
+// The group management service implements it.
+type Enforcer interface {
+	EnforceValidity(ctx context.Context, now time.Time) (*dto.ValidityRunResult, error)
+}
+
+// LockName is the advisory lock a pass holds, so one API instance at a time enforces validity.
+const LockName = "dbfartifactapi.validity_enforcement"
+
+// Locker takes a lock shared by all API instances without waiting.
+// repository.AdvisoryLockRepository implements it with MySQL GET_LOCK.
+type Locker interface {
+	TryLock(ctx context.Context, name string) (release func(), acquired bool, err error)
+}
+
+// Scheduler calls an Enforcer once at start and then at a fixed interval.
+// Passes never overlap: a pass that outlasts the interval delays the next one, and with a Locker
+// an instance skips its pass while another instance runs one.
+type Scheduler struct {
+	enforcer Enforcer
+	locker   Locker
+	interval time.Duration
+	now      func() time.Time
+
+	ctx    context.Context // Cancelled by Shutdown, aborts the running pass between transitions
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+}
+
+// NewScheduler creates a scheduler running enforcer every interval under locker. A nil locker runs
+// every pass, for a single instance. Call Start to begin.
+func NewScheduler(enforcer Enforcer, locker Locker, interval time.Duration) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		enforcer: enforcer,
+		locker:   locker,
+		interval: interval,
+		now:      time.Now,
+		ctx:      ctx,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+}
+
+// Start runs the first pass and the scheduling loop in the background.
+func (s *Scheduler) Start() {
+	s.once.Do(func() {
+		go s.run()
+	})
+}
+
+// Shutdown stops scheduling and waits until ctx expires for the running pass, which stops after
+// its current transition. A nil or never started scheduler shuts down immediately.
+func (s *Scheduler) Shutdown(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	s.cancel()
+	started := true
+	s.once.Do(func() {
+		started = false
+	})
+	if !started {
+		return nil
+	}
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("validity enforcement pass still running at shutdown deadline: %w", ctx.Err())
+	}
+}
+
+func (s *Scheduler) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	logger.Infof("Validity enforcement scheduler started, running every %v", s.interval)
+	for {
+		s.enforce()
+
+		select {
+		case <-s.ctx.Done():
+			logger.Infof("Validity enforcement scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// enforce runs one pass; errors are logged and the pass is retried on the next tick.
+func (s *Scheduler) enforce() {
+	if s.ctx.Err() != nil {
+		return
+	}
+	if s.locker != nil {
+		release, acquired, err := s.locker.TryLock(s.ctx, LockName)
+		if err != nil {
+			logger.Errorf("Validity enforcement lock unavailable, skipping pass: %v", err)
+			return
+		}
+		if !acquired {
+			logger.Debugf("Validity enforcement pass running on another instance, skipping")
+			return
+		}
+		defer release()
+	}
+
+	result, err := s.enforcer.EnforceValidity(s.ctx, s.now())
+	if err != nil {
+		if s.ctx.Err() != nil {
+			return // Stopped by Shutdown
+		}
+		logger.Errorf("Validity enforcement pass failed: %v", err)
+		return
+	}
+	if result.Failed > 0 {
+		logger.Warnf("Validity enforcement: %d membership transitions failed and will be retried", result.Failed)
+	}
+}
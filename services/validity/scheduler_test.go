@@ -0,0 +1,162 @@
+package validity
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"dbfartifactapi/services/dto"
+)
+
+// fakeEnforcer counts passes and blocks each pass until release is closed, when set
+type fakeEnforcer struct {
+	mu      sync.Mutex
+	passes  int
+	started chan struct{}
+	release chan struct{}
+	err     error
+}
+
+func (f *fakeEnforcer) EnforceValidity(ctx context.Context, now time.Time) (*dto.ValidityRunResult, error) {
+	f.mu.Lock()
+	f.passes++
+	f.mu.Unlock()
+	if f.started != nil {
+		select {
+		case f.started <- struct{}{}:
+		default:
+		}
+	}
+	if f.release != nil {
+		<-f.release
+	}
+	return &dto.ValidityRunResult{}, f.err
+}
+
+func (f *fakeEnforcer) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.passes
+}
+
+// TestScheduler_RunsAtStartAndEachInterval tests that the first pass does not wait for the interval
+func TestScheduler_RunsAtStartAndEachInterval(t *testing.T) {
+	enforcer := &fakeEnforcer{err: errors.New("database unavailable")}
+	scheduler := NewScheduler(enforcer, nil, 20*time.Millisecond)
+	scheduler.Start()
+	scheduler.Start() // Starting twice runs one loop
+
+	deadline := time.Now().Add(2 * time.Second)
+	for enforcer.count() < 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if enforcer.count() < 3 {
+		t.Fatalf("Expected at least 3 passes despite errors, got %d", enforcer.count())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := scheduler.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	passes := enforcer.count()
+	time.Sleep(50 * time.Millisecond)
+	if enforcer.count() != passes {
+		t.Errorf("Expected no passes after Shutdown, got %d more", enforcer.count()-passes)
+	}
+}
+
+// TestScheduler_ShutdownWaitsForRunningPass tests that Shutdown waits for the pass in progress
+// and gives up at the deadline
+func TestScheduler_ShutdownWaitsForRunningPass(t *testing.T) {
+	enforcer := &fakeEnforcer{started: make(chan struct{}, 1), release: make(chan struct{})}
+	scheduler := NewScheduler(enforcer, nil, time.Hour)
+	scheduler.Start()
+	<-enforcer.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := scheduler.Shutdown(ctx); err == nil {
+		t.Fatal("Expected error while the pass is still running")
+	}
+
+	close(enforcer.release)
+	ctx2, cancel2 := context.WithTimeout(context.Background(), time.Second)
+	defer cancel2()
+	if err := scheduler.Shutdown(ctx2); err != nil {
+		t.Fatalf("Shutdown after the pass finished: %v", err)
+	}
+	if enforcer.count() != 1 {
+		t.Errorf("Expected 1 pass, got %d", enforcer.count())
+	}
+}
+
+// TestScheduler_ShutdownWithoutStart tests that an unstarted or nil scheduler shuts down immediately
+func TestScheduler_ShutdownWithoutStart(t *testing.T) {
+	enforcer := &fakeEnforcer{}
+	scheduler := NewScheduler(enforcer, nil, time.Millisecond)
+	if err := scheduler.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	scheduler.Start() // No pass after Shutdown
+	time.Sleep(20 * time.Millisecond)
+	if enforcer.count() != 0 {
+		t.Errorf("Expected no passes, got %d", enforcer.count())
+	}
+
+	var none *Scheduler
+	if err := none.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown of nil scheduler: %v", err)
+	}
+}
+
+// fakeLocker is a lock shared by the schedulers of two simulated instances
+type fakeLocker struct {
+	mu   sync.Mutex
+	held bool
+}
+
+func (l *fakeLocker) TryLock(ctx context.Context, name string) (func(), bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.held {
+		return nil, false, nil
+	}
+	l.held = true
+	return func() {
+		l.mu.Lock()
+		l.held = false
+		l.mu.Unlock()
+	}, true, nil
+}
+
+// TestScheduler_SkipsPassWhileLockHeld tests that a second instance does not run a pass while the
+// first one holds the lock, and runs again once it is released
+func TestScheduler_SkipsPassWhileLockHeld(t *testing.T) {
+	locker := &fakeLocker{}
+	first := &fakeEnforcer{started: make(chan struct{}, 1), release: make(chan struct{})}
+	second := &fakeEnforcer{}
+
+	s1 := NewScheduler(first, locker, time.Hour)
+	s1.Start()
+	<-first.started
+
+	s2 := NewScheduler(second, locker, time.Hour)
+	s2.enforce()
+	if second.count() != 0 {
+		t.Fatalf("Expected the second instance to skip its pass, got %d passes", second.count())
+	}
+
+	close(first.release)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := s1.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	s2.enforce()
+	if second.count() != 1 {
+		t.Errorf("Expected the second instance to run once the lock was released, got %d passes", second.count())
+	}
+}
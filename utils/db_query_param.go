@@ -2,15 +2,37 @@ package utils
 
 import (
 	"bytes"
+	"context"
+	"dbfartifactapi/pkg/secrets"
 	"dbfartifactapi/services/dto"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 )
 
+// withPlainPassword returns a copy of data carrying the plain text password for the agent.
+// Stored credentials stay encrypted in data itself, so callers never hold the plain text.
+func withPlainPassword(data *dto.DBQueryParam) (*dto.DBQueryParam, error) {
+	if data.Password == "" && data.SecretRef == "" {
+		return data, nil
+	}
+	password, err := secrets.Default().Reveal(context.Background(), data.Password, data.SecretRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve connection credentials: %w", err)
+	}
+	dataCopy := *data
+	dataCopy.Password = password
+	return &dataCopy, nil
+}
+
 // CreateArtifactJSON converts DBQueryParam to hex-encoded JSON artifact for VeloArtifact execution.
 // DEPRECATED: Use CreateAgentCommandJSON for dbfAgentAPI integration.
 func CreateArtifactJSON(data *dto.DBQueryParam) (string, error) {
+	data, err := withPlainPassword(data)
+	if err != nil {
+		return "", err
+	}
+
 	// chuyển thành json
 	paramBytes, err := json.Marshal(data)
 	if err != nil {
@@ -43,6 +65,11 @@ func CreateArtifactJSON(data *dto.DBQueryParam) (string, error) {
 // CreateAgentCommandJSON converts DBQueryParam to hex-encoded JSON for dbfAgentAPI execution.
 // Returns hex-encoded JSON string ready to be passed to dbfsqlexecute binary.
 func CreateAgentCommandJSON(data *dto.DBQueryParam) (string, error) {
+	data, err := withPlainPassword(data)
+	if err != nil {
+		return "", err
+	}
+
 	paramBytes, err := json.Marshal(data)
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal DBQueryParam to JSON: %w", err)
@@ -63,8 +90,12 @@ func CreateAgentOSExecuteJSON(data *dto.DBQueryParam) (string, error) {
 	// Step 1: Hex-encode the command string to prevent injection
 	hexCommand := hex.EncodeToString([]byte(data.CommandExec))
 
-	// Step 2: Create a copy with hex-encoded commandExec
-	dataCopy := *data
+	// Step 2: Create a copy with hex-encoded commandExec and the plain text password
+	resolved, err := withPlainPassword(data)
+	if err != nil {
+		return "", err
+	}
+	dataCopy := *resolved
 	dataCopy.CommandExec = hexCommand
 
 	// Step 3: Marshal to JSON without HTML escaping
@@ -146,8 +177,12 @@ func CreateOSExecuteArtifactJSON(data *dto.DBQueryParam) (string, error) {
 	// Step 1: Hex-encode the command string
 	hexCommand := hex.EncodeToString([]byte(data.CommandExec))
 
-	// Step 2: Create a copy with hex-encoded commandExec
-	dataCopy := *data
+	// Step 2: Create a copy with hex-encoded commandExec and the plain text password
+	resolved, err := withPlainPassword(data)
+	if err != nil {
+		return "", err
+	}
+	dataCopy := *resolved
 	dataCopy.CommandExec = hexCommand
 
 	// Step 3: Marshal to JSON without HTML escaping
@@ -7,10 +7,13 @@ import (
 	"dbfartifactapi/pkg/logger"
 )
 
+// ExportDBFPolicyPath is the binary that builds rule files from the stored policies.
+const ExportDBFPolicyPath = "/usr/local/bin/exportDBFPolicy"
+
 // ExportDBFPolicy calls /usr/local/bin/exportDBFPolicy to build rule files after policy insertion.
 // Returns error if command execution fails, allowing caller to log or handle appropriately.
 func ExportDBFPolicy() error {
-	exportCmd := "/usr/local/bin/exportDBFPolicy"
+	exportCmd := ExportDBFPolicyPath
 
 	logger.Infof("Executing policy export command: %s", exportCmd)
 